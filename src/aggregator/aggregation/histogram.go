@@ -0,0 +1,170 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"errors"
+	"math"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+)
+
+var (
+	errHistogramCountsMismatch = errors.New("histogram must have one more count than bounds")
+	errHistogramBoundsUnsorted = errors.New("histogram bounds must be sorted in ascending order")
+)
+
+// HistogramBucket is a cumulative histogram bucket containing the number of
+// observations no larger than the upper bound of the bucket.
+type HistogramBucket struct {
+	UpperBound float64
+	Count      int64
+}
+
+// Histogram aggregates histogram values. Histograms are merged on the union
+// of their bucket upper bounds, with the observations in each incoming bucket
+// attributed to the bucket with the same upper bound.
+type Histogram struct {
+	Options
+
+	bounds  []float64 // upper bounds of the finite buckets in ascending order
+	counts  []int64   // non-cumulative bucket counts, the last being the +Inf bucket
+	sum     float64
+	count   int64
+	buckets []HistogramBucket
+}
+
+// NewHistogram creates a new histogram.
+func NewHistogram(opts Options) Histogram {
+	return Histogram{
+		Options: opts,
+		counts:  make([]int64, 1),
+	}
+}
+
+// Update merges a histogram with the given bucket upper bounds, bucket counts
+// and sum of observations. The bounds must be sorted in ascending order and
+// there must be one more count than there are bounds, with the last count
+// being the number of observations larger than all bounds. Histograms that
+// do not meet these requirements are rejected without being merged.
+func (h *Histogram) Update(bounds []float64, counts []int64, sum float64) error {
+	if len(counts) != len(bounds)+1 {
+		return errHistogramCountsMismatch
+	}
+	for i := 1; i < len(bounds); i++ {
+		if !(bounds[i-1] < bounds[i]) {
+			return errHistogramBoundsUnsorted
+		}
+	}
+	idx := 0
+	for i, bound := range bounds {
+		if math.IsInf(bound, 1) {
+			// Observations in an explicit +Inf bucket belong to the overflow bucket.
+			h.counts[len(h.counts)-1] += counts[i]
+			h.count += counts[i]
+			continue
+		}
+		idx = h.bucketIndex(idx, bound)
+		if idx == len(h.bounds) || h.bounds[idx] != bound {
+			h.insertBucket(idx, bound)
+		}
+		h.counts[idx] += counts[i]
+		h.count += counts[i]
+	}
+	overflow := counts[len(counts)-1]
+	h.counts[len(h.counts)-1] += overflow
+	h.count += overflow
+	h.sum += sum
+	return nil
+}
+
+// Add records a single observation.
+func (h *Histogram) Add(value float64) {
+	idx := h.bucketIndex(0, value)
+	h.counts[idx]++
+	h.count++
+	h.sum += value
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() int64 { return h.count }
+
+// Sum returns the sum of observations.
+func (h *Histogram) Sum() float64 { return h.sum }
+
+// Mean returns the mean of observations.
+func (h *Histogram) Mean() float64 {
+	if h.count == 0 {
+		return 0.0
+	}
+	return h.sum / float64(h.count)
+}
+
+// Buckets returns the cumulative buckets of the histogram in ascending order
+// of upper bounds, the last of which has an upper bound of +Inf. The returned
+// buckets are only valid until the next call to Buckets.
+func (h *Histogram) Buckets() []HistogramBucket {
+	h.buckets = h.buckets[:0]
+	var cumulative int64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		h.buckets = append(h.buckets, HistogramBucket{UpperBound: bound, Count: cumulative})
+	}
+	cumulative += h.counts[len(h.counts)-1]
+	return append(h.buckets, HistogramBucket{UpperBound: math.Inf(1), Count: cumulative})
+}
+
+// ValueOf returns the value for the aggregation type.
+func (h *Histogram) ValueOf(aggType aggregation.Type) float64 {
+	switch aggType {
+	case aggregation.Count:
+		return float64(h.Count())
+	case aggregation.Sum:
+		return h.Sum()
+	case aggregation.Mean:
+		return h.Mean()
+	default:
+		return 0
+	}
+}
+
+// Close closes the histogram.
+func (h *Histogram) Close() {}
+
+// bucketIndex returns the index of the first bucket starting from the given
+// index whose upper bound is no smaller than the given value.
+func (h *Histogram) bucketIndex(start int, value float64) int {
+	idx := start
+	for idx < len(h.bounds) && h.bounds[idx] < value {
+		idx++
+	}
+	return idx
+}
+
+func (h *Histogram) insertBucket(idx int, bound float64) {
+	h.bounds = append(h.bounds, 0)
+	copy(h.bounds[idx+1:], h.bounds[idx:])
+	h.bounds[idx] = bound
+
+	h.counts = append(h.counts, 0)
+	copy(h.counts[idx+1:], h.counts[idx:])
+	h.counts[idx] = 0
+}
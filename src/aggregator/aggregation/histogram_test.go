@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramUpdate(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	h.Update([]float64{1, 5, 10}, []int64{2, 3, 0, 1}, 24.5)
+	h.Update([]float64{1, 5, 10}, []int64{1, 0, 4, 0}, 30)
+
+	require.Equal(t, int64(11), h.Count())
+	require.Equal(t, 54.5, h.Sum())
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: 1, Count: 3},
+		{UpperBound: 5, Count: 6},
+		{UpperBound: 10, Count: 10},
+		{UpperBound: math.Inf(1), Count: 11},
+	}, h.Buckets())
+}
+
+func TestHistogramUpdateDifferentBounds(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	h.Update([]float64{1, 10}, []int64{2, 3, 1}, 20)
+	h.Update([]float64{5, 10, 100}, []int64{4, 1, 2, 0}, 90)
+
+	require.Equal(t, int64(13), h.Count())
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 5, Count: 6},
+		{UpperBound: 10, Count: 10},
+		{UpperBound: 100, Count: 12},
+		{UpperBound: math.Inf(1), Count: 13},
+	}, h.Buckets())
+}
+
+func TestHistogramUpdateExplicitInfBound(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	h.Update([]float64{1, math.Inf(1)}, []int64{2, 3, 0}, 20)
+
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: math.Inf(1), Count: 5},
+	}, h.Buckets())
+}
+
+func TestHistogramUpdateMismatchedCounts(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	require.Equal(t, errHistogramCountsMismatch, h.Update([]float64{1, 10}, []int64{2, 3}, 20))
+
+	require.Equal(t, int64(0), h.Count())
+	require.Equal(t, 0.0, h.Sum())
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: math.Inf(1), Count: 0},
+	}, h.Buckets())
+}
+
+func TestHistogramUpdateUnsortedBounds(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	require.Equal(t, errHistogramBoundsUnsorted, h.Update([]float64{10, 1}, []int64{2, 3, 4}, 20))
+
+	require.Equal(t, int64(0), h.Count())
+	require.Equal(t, 0.0, h.Sum())
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: math.Inf(1), Count: 0},
+	}, h.Buckets())
+}
+
+func TestHistogramAdd(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	h.Update([]float64{1, 10}, []int64{0, 0, 0}, 0)
+	for _, v := range []float64{0.5, 1, 2, 20} {
+		h.Add(v)
+	}
+
+	require.Equal(t, []HistogramBucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 10, Count: 3},
+		{UpperBound: math.Inf(1), Count: 4},
+	}, h.Buckets())
+}
+
+func TestHistogramValueOf(t *testing.T) {
+	h := NewHistogram(NewOptions())
+	h.Update([]float64{1, 10}, []int64{2, 1, 1}, 30)
+
+	for aggType := range aggregation.ValidTypes {
+		v := h.ValueOf(aggType)
+		switch aggType {
+		case aggregation.Count:
+			require.Equal(t, 4.0, v)
+		case aggregation.Sum:
+			require.Equal(t, 30.0, v)
+		case aggregation.Mean:
+			require.Equal(t, 7.5, v)
+		default:
+			require.Equal(t, 0.0, v)
+			require.False(t, aggType.IsValidForHistogram())
+		}
+	}
+}
@@ -34,23 +34,50 @@ func newCounterAggregation(c aggregation.Counter) counterAggregation {
 	return counterAggregation{Counter: c}
 }
 
-func (c *counterAggregation) Add(value float64)                    { c.Counter.Update(int64(value)) }
-func (c *counterAggregation) AddUnion(mu unaggregated.MetricUnion) { c.Counter.Update(mu.CounterVal) }
+func (c *counterAggregation) Add(value float64)                      { c.Counter.Update(int64(value)) }
+func (c *counterAggregation) Buckets() []aggregation.HistogramBucket { return nil }
+func (c *counterAggregation) AddUnion(mu unaggregated.MetricUnion) error {
+	c.Counter.Update(mu.CounterVal)
+	return nil
+}
 
 // timerAggregation is a timer aggregation.
 type timerAggregation struct {
 	aggregation.Timer
 }
 
-func newTimerAggregation(t aggregation.Timer) timerAggregation   { return timerAggregation{Timer: t} }
-func (t *timerAggregation) Add(value float64)                    { t.Timer.Add(value) }
-func (t *timerAggregation) AddUnion(mu unaggregated.MetricUnion) { t.Timer.AddBatch(mu.BatchTimerVal) }
+func newTimerAggregation(t aggregation.Timer) timerAggregation     { return timerAggregation{Timer: t} }
+func (t *timerAggregation) Add(value float64)                      { t.Timer.Add(value) }
+func (t *timerAggregation) Buckets() []aggregation.HistogramBucket { return nil }
+func (t *timerAggregation) AddUnion(mu unaggregated.MetricUnion) error {
+	t.Timer.AddBatch(mu.BatchTimerVal)
+	return nil
+}
 
 // gaugeAggregation is a gauge aggregation.
 type gaugeAggregation struct {
 	aggregation.Gauge
 }
 
-func newGaugeAggregation(g aggregation.Gauge) gaugeAggregation   { return gaugeAggregation{Gauge: g} }
-func (g *gaugeAggregation) Add(value float64)                    { g.Gauge.Update(value) }
-func (g *gaugeAggregation) AddUnion(mu unaggregated.MetricUnion) { g.Gauge.Update(mu.GaugeVal) }
+func newGaugeAggregation(g aggregation.Gauge) gaugeAggregation     { return gaugeAggregation{Gauge: g} }
+func (g *gaugeAggregation) Add(value float64)                      { g.Gauge.Update(value) }
+func (g *gaugeAggregation) Buckets() []aggregation.HistogramBucket { return nil }
+func (g *gaugeAggregation) AddUnion(mu unaggregated.MetricUnion) error {
+	g.Gauge.Update(mu.GaugeVal)
+	return nil
+}
+
+// histogramAggregation is a histogram aggregation.
+type histogramAggregation struct {
+	aggregation.Histogram
+}
+
+func newHistogramAggregation(h aggregation.Histogram) histogramAggregation {
+	return histogramAggregation{Histogram: h}
+}
+
+func (h *histogramAggregation) Add(value float64) { h.Histogram.Add(value) }
+func (h *histogramAggregation) AddUnion(mu unaggregated.MetricUnion) error {
+	return h.Histogram.Update(mu.HistogramBounds, mu.HistogramCounts, mu.HistogramSum)
+}
+func (h *histogramAggregation) Buckets() []aggregation.HistogramBucket { return h.Histogram.Buckets() }
@@ -56,6 +56,7 @@ var (
 	errAggregatorNotOpenOrClosed     = errors.New("aggregator is not open or closed")
 	errAggregatorAlreadyOpenOrClosed = errors.New("aggregator is already open or closed")
 	errInvalidMetricType             = errors.New("invalid metric type")
+	errInvalidHistogram              = errors.New("invalid histogram: number of counts must be one more than number of bounds")
 	errActivePlacementChanged        = errors.New("active placement has changed")
 	errShardNotOwned                 = errors.New("aggregator shard is not owned")
 )
@@ -515,6 +516,12 @@ func (agg *aggregator) checkMetricType(mu unaggregated.MetricUnion) error {
 	case metric.GaugeType:
 		agg.metrics.gauges.Inc(1)
 		return nil
+	case metric.HistogramType:
+		if len(mu.HistogramCounts) != len(mu.HistogramBounds)+1 {
+			return errInvalidHistogram
+		}
+		agg.metrics.histograms.Inc(1)
+		return nil
 	default:
 		return errInvalidMetricType
 	}
@@ -681,6 +688,7 @@ type aggregatorAddUntimedMetrics struct {
 	aggregatorAddMetricMetrics
 
 	invalidMetricTypes tally.Counter
+	invalidHistograms  tally.Counter
 }
 
 func newAggregatorAddUntimedMetrics(
@@ -692,13 +700,20 @@ func newAggregatorAddUntimedMetrics(
 		invalidMetricTypes: scope.Tagged(map[string]string{
 			"reason": "invalid-metric-types",
 		}).Counter("errors"),
+		invalidHistograms: scope.Tagged(map[string]string{
+			"reason": "invalid-histograms",
+		}).Counter("errors"),
 	}
 }
 
 func (m *aggregatorAddUntimedMetrics) ReportError(err error) {
-	if err == errInvalidMetricType {
+	switch err {
+	case errInvalidMetricType:
 		m.invalidMetricTypes.Inc(1)
 		return
+	case errInvalidHistogram:
+		m.invalidHistograms.Inc(1)
+		return
 	}
 	m.aggregatorAddMetricMetrics.ReportError(err)
 }
@@ -912,6 +927,7 @@ type aggregatorMetrics struct {
 	timers       tally.Counter
 	timerBatches tally.Counter
 	gauges       tally.Counter
+	histograms   tally.Counter
 	forwarded    tally.Counter
 	timed        tally.Counter
 	addUntimed   aggregatorAddUntimedMetrics
@@ -940,6 +956,7 @@ func newAggregatorMetrics(
 		timers:       scope.Counter("timers"),
 		timerBatches: scope.Counter("timer-batches"),
 		gauges:       scope.Counter("gauges"),
+		histograms:   scope.Counter("histograms"),
 		forwarded:    scope.Counter("forwarded"),
 		timed:        scope.Counter("timed"),
 		addUntimed:   newAggregatorAddUntimedMetrics(addUntimedScope, samplingRate),
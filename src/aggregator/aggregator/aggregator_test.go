@@ -314,6 +314,22 @@ func TestAggregatorAddUntimedInvalidMetricType(t *testing.T) {
 	require.Equal(t, errInvalidMetricType, err)
 }
 
+func TestAggregatorAddUntimedInvalidHistogram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	require.NoError(t, agg.Open())
+	invalid := unaggregated.MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              []byte("testInvalidHistogram"),
+		HistogramBounds: []float64{1, 2},
+		HistogramCounts: []int64{1, 2},
+	}
+	err := agg.AddUntimed(invalid, testStagedMetadatas)
+	require.Equal(t, errInvalidHistogram, err)
+}
+
 func TestAggregatorAddUntimedNotOpen(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -922,6 +938,7 @@ func TestAggregatorAddMetricMetrics(t *testing.T) {
 	m := newAggregatorAddUntimedMetrics(s, 1.0)
 	m.ReportSuccess(time.Second)
 	m.ReportError(errInvalidMetricType)
+	m.ReportError(errInvalidHistogram)
 	m.ReportError(errShardNotOwned)
 	m.ReportError(errAggregatorShardNotWriteable)
 	m.ReportError(errWriteNewMetricRateLimitExceeded)
@@ -932,10 +949,11 @@ func TestAggregatorAddMetricMetrics(t *testing.T) {
 	counters, timers, gauges := snapshot.Counters(), snapshot.Timers(), snapshot.Gauges()
 
 	// Validate we count successes and errors correctly.
-	require.Equal(t, 7, len(counters))
+	require.Equal(t, 8, len(counters))
 	for _, id := range []string{
 		"testScope.success+",
 		"testScope.errors+reason=invalid-metric-types",
+		"testScope.errors+reason=invalid-histograms",
 		"testScope.errors+reason=shard-not-owned",
 		"testScope.errors+reason=shard-not-writeable",
 		"testScope.errors+reason=value-rate-limit-exceeded",
@@ -55,6 +55,13 @@ var (
 		ID:       id.RawID("testCounter"),
 		GaugeVal: 123.456,
 	}
+	testHistogram = unaggregated.MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              id.RawID("testHistogram"),
+		HistogramBounds: []float64{0.5, 1},
+		HistogramCounts: []int64{3, 2, 1},
+		HistogramSum:    4.5,
+	}
 	testTimed = aggregated.Metric{
 		Type:      metric.CounterType,
 		ID:        []byte("testForwarded"),
@@ -102,7 +109,7 @@ func TestAggregator(t *testing.T) {
 
 	// Add valid untimed metrics with policies.
 	var expected SnapshotResult
-	for _, mu := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge} {
+	for _, mu := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge, testHistogram} {
 		switch mu.Type {
 		case metric.CounterType:
 			expected.CountersWithMetadatas = append(
@@ -125,6 +132,13 @@ func TestAggregator(t *testing.T) {
 					Gauge:           mu.Gauge(),
 					StagedMetadatas: metadatas,
 				})
+		case metric.HistogramType:
+			expected.HistogramsWithMetadatas = append(
+				expected.HistogramsWithMetadatas,
+				unaggregated.HistogramWithMetadatas{
+					Histogram:       mu.Histogram(),
+					StagedMetadatas: metadatas,
+				})
 		default:
 			require.Fail(t, fmt.Sprintf("unknown metric type %v", mu.Type))
 		}
@@ -141,7 +155,7 @@ func TestAggregator(t *testing.T) {
 	)
 	require.NoError(t, agg.AddTimed(testTimed, testTimedMetadata))
 
-	require.Equal(t, 4, agg.NumMetricsAdded())
+	require.Equal(t, 5, agg.NumMetricsAdded())
 
 	// Add valid forwarded metrics with metadata.
 	expected.ForwardedMetricsWithMetadata = append(
@@ -153,7 +167,7 @@ func TestAggregator(t *testing.T) {
 	)
 	require.NoError(t, agg.AddForwarded(testForwarded, testForwardMetadata))
 
-	require.Equal(t, 5, agg.NumMetricsAdded())
+	require.Equal(t, 6, agg.NumMetricsAdded())
 
 	res := agg.Snapshot()
 	require.Equal(t, expected, res)
@@ -37,6 +37,7 @@ import (
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/pool"
 
+	"github.com/uber-go/tally"
 	"github.com/willf/bitset"
 )
 
@@ -164,6 +165,7 @@ type elemBase struct {
 	idPrefixSuffixType              IDPrefixSuffixType
 	writeForwardedMetricFn          writeForwardedMetricFn
 	onForwardedAggregationWrittenFn onForwardedAggregationDoneFn
+	histogramBucketIDErrors         tally.Counter
 
 	// Mutable states.
 	tombstoned           bool
@@ -177,6 +179,8 @@ func newElemBase(opts Options) elemBase {
 		opts:         opts,
 		aggTypesOpts: opts.AggregationTypesOptions(),
 		aggOpts:      raggregation.NewOptions(),
+		histogramBucketIDErrors: opts.InstrumentOptions().MetricsScope().
+			Counter("histogram-bucket-id-errors"),
 	}
 }
 
@@ -373,6 +377,39 @@ func (e *gaugeElemBase) ResetSetData(
 
 func (e *gaugeElemBase) Close() {}
 
+type histogramElemBase struct{}
+
+func (e histogramElemBase) Type() metric.Type { return metric.HistogramType }
+
+func (e histogramElemBase) FullPrefix(opts Options) []byte { return opts.FullHistogramPrefix() }
+
+func (e histogramElemBase) DefaultAggregationTypes(aggTypesOpts maggregation.TypesOptions) maggregation.Types {
+	return aggTypesOpts.DefaultHistogramAggregationTypes()
+}
+
+func (e histogramElemBase) TypeStringFor(aggTypesOpts maggregation.TypesOptions, aggType maggregation.Type) []byte {
+	return aggTypesOpts.TypeStringForHistogram(aggType)
+}
+
+func (e histogramElemBase) ElemPool(opts Options) HistogramElemPool { return opts.HistogramElemPool() }
+
+func (e histogramElemBase) NewAggregation(_ Options, aggOpts raggregation.Options) histogramAggregation {
+	return newHistogramAggregation(raggregation.NewHistogram(aggOpts))
+}
+
+func (e *histogramElemBase) ResetSetData(
+	_ maggregation.TypesOptions,
+	aggTypes maggregation.Types,
+	_ bool,
+) error {
+	if !aggTypes.IsValidForHistogram() {
+		return fmt.Errorf("invalid aggregation types %s for histogram", aggTypes.String())
+	}
+	return nil
+}
+
+func (e *histogramElemBase) Close() {}
+
 // nolint: maligned
 type parsedPipeline struct {
 	// Whether the source pipeline contains derivative transformations at its head.
@@ -46,6 +46,7 @@ var (
 	testCounterID                 = id.RawID("testCounter")
 	testBatchTimerID              = id.RawID("testBatchTimer")
 	testGaugeID                   = id.RawID("testGauge")
+	testHistogramID               = id.RawID("testHistogram")
 	testStoragePolicy             = policy.NewStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour)
 	testAggregationTypes          = maggregation.Types{maggregation.Mean, maggregation.Sum}
 	testAggregationTypesExpensive = maggregation.Types{maggregation.SumSq}
@@ -65,6 +66,13 @@ var (
 		ID:       testGaugeID,
 		GaugeVal: 123.456,
 	}
+	testHistogram = unaggregated.MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              testHistogramID,
+		HistogramBounds: []float64{0.1, 1},
+		HistogramCounts: []int64{2, 3, 1},
+		HistogramSum:    4.5,
+	}
 	testPipeline = applied.NewPipeline([]applied.OpUnion{
 		{
 			Type:           pipeline.TransformationOpType,
@@ -100,6 +108,7 @@ var (
 	testCounterVals    = []int64{testCounter.CounterVal, testCounter.CounterVal}
 	testBatchTimerVals = [][]float64{testBatchTimer.BatchTimerVal, testBatchTimer.BatchTimerVal}
 	testGaugeVals      = []float64{testGauge.GaugeVal, testGauge.GaugeVal}
+	testHistogramVals  = []unaggregated.MetricUnion{testHistogram, testHistogram}
 )
 
 func TestCounterResetSetData(t *testing.T) {
@@ -1695,6 +1704,106 @@ func TestGaugeFindOrCreateWithSourceSet(t *testing.T) {
 	require.Equal(t, 0, len(e.cachedSourceSets))
 }
 
+func TestHistogramElemAddUnion(t *testing.T) {
+	e, err := NewHistogramElem(testHistogramID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, err)
+
+	// Add a histogram metric.
+	require.NoError(t, e.AddUnion(testTimestamps[0], testHistogram))
+	require.Equal(t, 1, len(e.values))
+	require.Equal(t, testAlignedStarts[0], e.values[0].startAtNanos)
+	require.Equal(t, int64(6), e.values[0].lockedAgg.aggregation.Count())
+	require.Equal(t, testHistogram.HistogramSum, e.values[0].lockedAgg.aggregation.Sum())
+
+	// Add the histogram metric at slightly different time
+	// but still within the same aggregation interval.
+	require.NoError(t, e.AddUnion(testTimestamps[1], testHistogram))
+	require.Equal(t, 1, len(e.values))
+	require.Equal(t, int64(12), e.values[0].lockedAgg.aggregation.Count())
+	require.Equal(t, 2*testHistogram.HistogramSum, e.values[0].lockedAgg.aggregation.Sum())
+	require.Equal(t, []raggregation.HistogramBucket{
+		{UpperBound: 0.1, Count: 4},
+		{UpperBound: 1, Count: 10},
+		{UpperBound: math.Inf(1), Count: 12},
+	}, e.values[0].lockedAgg.aggregation.Buckets())
+
+	// Add the histogram metric in the next aggregation interval.
+	require.NoError(t, e.AddUnion(testTimestamps[2], testHistogram))
+	require.Equal(t, 2, len(e.values))
+	for i := 0; i < len(e.values); i++ {
+		require.Equal(t, testAlignedStarts[i], e.values[i].startAtNanos)
+	}
+	require.Equal(t, int64(6), e.values[1].lockedAgg.aggregation.Count())
+
+	// Adding the histogram metric to a closed element results in an error.
+	e.closed = true
+	require.Equal(t, errElemClosed, e.AddUnion(testTimestamps[2], testHistogram))
+}
+
+func TestHistogramElemAddUnionMismatchedCounts(t *testing.T) {
+	e, err := NewHistogramElem(testHistogramID, testStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, NewOptions())
+	require.NoError(t, err)
+
+	mu := testHistogram
+	mu.HistogramCounts = mu.HistogramCounts[:len(mu.HistogramCounts)-1]
+	require.Error(t, e.AddUnion(testTimestamps[0], mu))
+	require.Equal(t, int64(0), e.values[0].lockedAgg.aggregation.Count())
+}
+
+func TestHistogramElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
+	isEarlierThanFn := isStandardMetricEarlierThan
+	timestampNanosFn := standardMetricTimestampNanos
+	opts := NewOptions()
+	e := testHistogramElem(testAlignedStarts[:len(testAlignedStarts)-1], testHistogramVals, maggregation.DefaultTypes, applied.DefaultPipeline, opts)
+
+	// Consume one value.
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, expectedLocalMetricsForHistogram(testAlignedStarts[1], testStoragePolicy, maggregation.DefaultTypes), *localRes)
+	require.Equal(t, 0, len(*forwardRes))
+	require.Equal(t, 0, len(*onForwardedFlushedRes))
+	require.Equal(t, 1, len(e.values))
+
+	// Consume all values.
+	localFn, localRes = testFlushLocalMetricFn()
+	forwardFn, forwardRes = testFlushForwardedMetricFn()
+	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, expectedLocalMetricsForHistogram(testAlignedStarts[2], testStoragePolicy, maggregation.DefaultTypes), *localRes)
+	require.Equal(t, 0, len(*forwardRes))
+	require.Equal(t, 0, len(*onForwardedFlushedRes))
+	require.Equal(t, 0, len(e.values))
+}
+
+func TestHistogramElemConsumeCustomAggregationDefaultPipeline(t *testing.T) {
+	isEarlierThanFn := isStandardMetricEarlierThan
+	timestampNanosFn := standardMetricTimestampNanos
+	opts := NewOptions()
+	aggTypes := maggregation.Types{maggregation.Mean}
+	e := testHistogramElem(testAlignedStarts[:len(testAlignedStarts)-1], testHistogramVals, aggTypes, applied.DefaultPipeline, opts)
+
+	// Consume all values.
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, onForwardedFlushedRes := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	expectedLocalMetrics := expectedLocalMetricsForHistogram(testAlignedStarts[1], testStoragePolicy, aggTypes)
+	expectedLocalMetrics = append(expectedLocalMetrics, expectedLocalMetricsForHistogram(testAlignedStarts[2], testStoragePolicy, aggTypes)...)
+	require.Equal(t, expectedLocalMetrics, *localRes)
+	require.Equal(t, 0, len(*forwardRes))
+	require.Equal(t, 0, len(*onForwardedFlushedRes))
+	require.Equal(t, 0, len(e.values))
+}
+
+func TestHistogramResetSetDataInvalidAggregationType(t *testing.T) {
+	opts := NewOptions()
+	he := MustNewHistogramElem(nil, policy.EmptyStoragePolicy, maggregation.DefaultTypes, applied.DefaultPipeline, testNumForwardedTimes, NoPrefixNoSuffix, opts)
+	err := he.ResetSetData(testHistogramID, testStoragePolicy, maggregation.Types{maggregation.P99}, applied.DefaultPipeline, 0, NoPrefixNoSuffix)
+	require.Error(t, err)
+}
+
 type testIndexData struct {
 	index int
 	data  []int64
@@ -1852,6 +1961,25 @@ func testGaugeElem(
 	return e
 }
 
+func testHistogramElem(
+	alignedstartAtNanos []int64,
+	histogramVals []unaggregated.MetricUnion,
+	aggTypes maggregation.Types,
+	pipeline applied.Pipeline,
+	opts Options,
+) *HistogramElem {
+	e := MustNewHistogramElem(testHistogramID, testStoragePolicy, aggTypes, pipeline, testNumForwardedTimes, WithPrefixWithSuffix, opts)
+	for i, aligned := range alignedstartAtNanos {
+		histogram := &lockedHistogramAggregation{aggregation: newHistogramAggregation(raggregation.NewHistogram(e.aggOpts))}
+		histogram.aggregation.AddUnion(histogramVals[i])
+		e.values = append(e.values, timedHistogram{
+			startAtNanos: aligned,
+			lockedAgg:    histogram,
+		})
+	}
+	return e
+}
+
 func expectCounterSuffix(aggType maggregation.Type) []byte {
 	return testOpts.AggregationTypesOptions().TypeStringForCounter(aggType)
 }
@@ -1977,6 +2105,56 @@ func expectedLocalMetricsForGauge(
 	}
 }
 
+func expectHistogramSuffix(aggType maggregation.Type) []byte {
+	return testOpts.AggregationTypesOptions().TypeStringForHistogram(aggType)
+}
+
+func expectedLocalMetricsForHistogram(
+	timeNanos int64,
+	sp policy.StoragePolicy,
+	aggTypes maggregation.Types,
+) []testLocalMetricWithMetadata {
+	if aggTypes.IsDefault() {
+		aggTypes = testOpts.AggregationTypesOptions().DefaultHistogramAggregationTypes()
+	}
+	var (
+		res   []testLocalMetricWithMetadata
+		count int64
+	)
+	for _, aggType := range aggTypes {
+		var value float64
+		switch aggType {
+		case maggregation.Sum:
+			value = testHistogram.HistogramSum
+		case maggregation.Count:
+			value = 6
+		case maggregation.Mean:
+			value = testHistogram.HistogramSum / 6
+		}
+		res = append(res, testLocalMetricWithMetadata{
+			idPrefix:  []byte("stats.histograms."),
+			id:        testHistogramID,
+			idSuffix:  expectHistogramSuffix(aggType),
+			timeNanos: timeNanos,
+			value:     value,
+			sp:        sp,
+		})
+	}
+	bounds := append(append([]float64(nil), testHistogram.HistogramBounds...), math.Inf(1))
+	for i, bound := range bounds {
+		count += testHistogram.HistogramCounts[i]
+		res = append(res, testLocalMetricWithMetadata{
+			idPrefix:  []byte("stats.histograms."),
+			id:        testOpts.HistogramBucketIDFn()(testHistogramID, bound),
+			idSuffix:  nil,
+			timeNanos: timeNanos,
+			value:     float64(count),
+			sp:        sp,
+		})
+	}
+	return res
+}
+
 func verifyForwardedMetrics(t *testing.T, expected, actual []testForwardedMetricWithMetadata) {
 	require.Equal(t, len(expected), len(actual))
 	for i := 0; i < len(expected); i++ {
@@ -536,6 +536,8 @@ func (e *Entry) addNewAggregationKeyWithLock(
 		newElem = e.opts.TimerElemPool().Get()
 	case metric.GaugeType:
 		newElem = e.opts.GaugeElemPool().Get()
+	case metric.HistogramType:
+		newElem = e.opts.HistogramElemPool().Get()
 	default:
 		return nil, errInvalidMetricType
 	}
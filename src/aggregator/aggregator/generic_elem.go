@@ -46,11 +46,15 @@ type typeSpecificAggregation interface {
 	Add(value float64)
 
 	// AddUnion adds a new metric value union.
-	AddUnion(mu unaggregated.MetricUnion)
+	AddUnion(mu unaggregated.MetricUnion) error
 
 	// ValueOf returns the value for the given aggregation type.
 	ValueOf(aggType maggregation.Type) float64
 
+	// Buckets returns the cumulative buckets of a histogram aggregation,
+	// or nil for aggregations that are not bucketed.
+	Buckets() []raggregation.HistogramBucket
+
 	// Close closes the aggregation object.
 	Close()
 }
@@ -207,9 +211,9 @@ func (e *GenericElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion)
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
-	lockedAgg.aggregation.AddUnion(mu)
+	err = lockedAgg.aggregation.AddUnion(mu)
 	lockedAgg.Unlock()
-	return nil
+	return err
 }
 
 // AddValue adds a metric value at a given timestamp.
@@ -500,5 +504,22 @@ func (e *GenericElem) processValueWithAggregationLock(
 			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey, timeNanos, value)
 		}
 	}
+	// NB: histogram buckets are flushed as separate series so that they can be
+	// queried with histogram_quantile, and are never forwarded for rollups.
+	if !e.parsedPipeline.HasRollup {
+		for _, bucket := range lockedAgg.aggregation.Buckets() {
+			bucketID, err := e.opts.HistogramBucketIDFn()(e.id, bucket.UpperBound)
+			if err != nil {
+				e.histogramBucketIDErrors.Inc(1)
+				continue
+			}
+			switch e.idPrefixSuffixType {
+			case NoPrefixNoSuffix:
+				flushLocalFn(nil, bucketID, nil, timeNanos, float64(bucket.Count), e.sp)
+			case WithPrefixWithSuffix:
+				flushLocalFn(e.FullPrefix(e.opts), bucketID, nil, timeNanos, float64(bucket.Count), e.sp)
+			}
+		}
+	}
 	e.lastConsumedAtNanos = timeNanos
 }
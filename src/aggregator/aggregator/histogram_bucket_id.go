@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
+)
+
+var (
+	errHistogramBucketIDNoData = errors.New("no encoded data for histogram bucket id")
+)
+
+// NewTagsHistogramBucketIDFn creates a histogram bucket id function for
+// histograms whose ids are serialized tags. The id of a bucket is the id of
+// the histogram with an additional tag carrying the upper bound of the bucket,
+// e.g. the "le" tag used by Prometheus. Any existing tag with the same name is
+// replaced.
+func NewTagsHistogramBucketIDFn(
+	bucketTagName []byte,
+	tagEncoderPool serialize.TagEncoderPool,
+	metricTagsIteratorPool serialize.MetricTagsIteratorPool,
+) HistogramBucketIDFn {
+	return func(histogramID id.RawID, upperBound float64) (id.RawID, error) {
+		iter := metricTagsIteratorPool.Get()
+		iter.Reset(histogramID)
+		defer iter.Close()
+
+		var (
+			bucketTag = ident.Tag{
+				Name:  ident.BytesID(bucketTagName),
+				Value: ident.BytesID(strconv.AppendFloat(nil, upperBound, 'f', -1, 64)),
+			}
+			tags  = make([]ident.Tag, 0, iter.NumTags()+1)
+			added bool
+		)
+		for iter.Next() {
+			name, value := iter.Current()
+			cmp := bytes.Compare(name, bucketTagName)
+			if cmp == 0 {
+				continue
+			}
+			if cmp > 0 && !added {
+				tags = append(tags, bucketTag)
+				added = true
+			}
+			tags = append(tags, ident.Tag{Name: ident.BytesID(name), Value: ident.BytesID(value)})
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		if !added {
+			tags = append(tags, bucketTag)
+		}
+
+		encoder := tagEncoderPool.Get()
+		defer encoder.Finalize()
+
+		encoder.Reset()
+		if err := encoder.Encode(ident.NewTagsIterator(ident.NewTags(tags...))); err != nil {
+			return nil, err
+		}
+		data, ok := encoder.Data()
+		if !ok {
+			return nil, errHistogramBucketIDNoData
+		}
+		// NB: the encoded bytes are owned by the encoder and need to be copied.
+		return append(id.RawID(nil), data.Bytes()...), nil
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagsHistogramBucketIDFn(t *testing.T) {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(), nil)
+	encoderPool.Init()
+	decoderPool := serialize.NewTagDecoderPool(serialize.NewTagDecoderOptions(), nil)
+	decoderPool.Init()
+	iterPool := serialize.NewMetricTagsIteratorPool(decoderPool, nil)
+	iterPool.Init()
+
+	encode := func(tags ...string) id.RawID {
+		encoder := encoderPool.Get()
+		defer encoder.Finalize()
+		require.NoError(t, encoder.Encode(ident.MustNewTagStringsIterator(tags...)))
+		data, ok := encoder.Data()
+		require.True(t, ok)
+		return append(id.RawID(nil), data.Bytes()...)
+	}
+
+	fn := NewTagsHistogramBucketIDFn([]byte("le"), encoderPool, iterPool)
+	inputs := []struct {
+		histogramID id.RawID
+		upperBound  float64
+		expected    id.RawID
+	}{
+		{
+			histogramID: encode("__name__", "latency", "service", "foo"),
+			upperBound:  0.5,
+			expected:    encode("__name__", "latency", "le", "0.5", "service", "foo"),
+		},
+		{
+			histogramID: encode("__name__", "latency"),
+			upperBound:  math.Inf(1),
+			expected:    encode("__name__", "latency", "le", "+Inf"),
+		},
+		{
+			histogramID: encode("__name__", "latency", "le", "1", "service", "foo"),
+			upperBound:  10,
+			expected:    encode("__name__", "latency", "le", "10", "service", "foo"),
+		},
+	}
+	for _, input := range inputs {
+		bucketID, err := fn(input.histogramID, input.upperBound)
+		require.NoError(t, err)
+		require.Equal(t, input.expected, bucketID)
+	}
+
+	// Ids that are not serialized tags cannot be identified.
+	_, err := fn(id.RawID("foo.bar"), 0.5)
+	require.Error(t, err)
+}
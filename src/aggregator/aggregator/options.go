@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/aggregator/sharding"
 	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/pipeline/applied"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
@@ -42,6 +44,8 @@ var (
 	defaultCounterPrefix              = []byte("counts.")
 	defaultTimerPrefix                = []byte("timers.")
 	defaultGaugePrefix                = []byte("gauges.")
+	defaultHistogramPrefix            = []byte("histograms.")
+	defaultHistogramBucketSeparator   = []byte(".le_")
 	defaultEntryTTL                   = time.Hour
 	defaultEntryCheckInterval         = time.Hour
 	defaultEntryCheckBatchPercent     = 0.01
@@ -78,6 +82,11 @@ var (
 // destination server, and ingestion delay at the destination server.
 type MaxAllowedForwardingDelayFn func(resolution time.Duration, numForwardedTimes int) time.Duration
 
+// HistogramBucketIDFn returns the id of the series a histogram bucket with the
+// given upper bound is flushed to, given the id of the histogram. Buckets whose
+// id cannot be generated are not flushed.
+type HistogramBucketIDFn func(id id.RawID, upperBound float64) (id.RawID, error)
+
 // BufferForPastTimedMetricFn returns the buffer duration for past timed metrics.
 type BufferForPastTimedMetricFn func(resolution time.Duration) time.Duration
 
@@ -109,6 +118,12 @@ type Options interface {
 	// GaugePrefix returns the prefix for gauges.
 	GaugePrefix() []byte
 
+	// SetHistogramPrefix sets the prefix for histograms.
+	SetHistogramPrefix(value []byte) Options
+
+	// HistogramPrefix returns the prefix for histograms.
+	HistogramPrefix() []byte
+
 	// SetTimeLock sets the time lock.
 	SetTimeLock(value *sync.RWMutex) Options
 
@@ -267,6 +282,12 @@ type Options interface {
 	// DiscardNaNAggregatedValues determines whether NaN aggregated values are discarded.
 	DiscardNaNAggregatedValues() bool
 
+	// SetHistogramBucketIDFn sets the function that generates the ids of histogram buckets.
+	SetHistogramBucketIDFn(value HistogramBucketIDFn) Options
+
+	// HistogramBucketIDFn returns the function that generates the ids of histogram buckets.
+	HistogramBucketIDFn() HistogramBucketIDFn
+
 	// SetEntryPool sets the entry pool.
 	SetEntryPool(value EntryPool) Options
 
@@ -291,6 +312,12 @@ type Options interface {
 	// GaugeElemPool returns the gauge element pool.
 	GaugeElemPool() GaugeElemPool
 
+	// SetHistogramElemPool sets the histogram element pool.
+	SetHistogramElemPool(value HistogramElemPool) Options
+
+	// HistogramElemPool returns the histogram element pool.
+	HistogramElemPool() HistogramElemPool
+
 	/// Read-only derived options.
 
 	// FullCounterPrefix returns the full prefix for counters.
@@ -302,6 +329,9 @@ type Options interface {
 	// FullGaugePrefix returns the full prefix for gauges.
 	FullGaugePrefix() []byte
 
+	// FullHistogramPrefix returns the full prefix for histograms.
+	FullHistogramPrefix() []byte
+
 	// SetVerboseErrors returns whether to return verbose errors or not.
 	SetVerboseErrors(value bool) Options
 
@@ -316,6 +346,7 @@ type options struct {
 	counterPrefix                    []byte
 	timerPrefix                      []byte
 	gaugePrefix                      []byte
+	histogramPrefix                  []byte
 	timeLock                         *sync.RWMutex
 	clockOpts                        clock.Options
 	instrumentOpts                   instrument.Options
@@ -341,17 +372,20 @@ type options struct {
 	bufferForFutureTimedMetric       time.Duration
 	maxNumCachedSourceSets           int
 	discardNaNAggregatedValues       bool
+	histogramBucketIDFn              HistogramBucketIDFn
 	entryPool                        EntryPool
 	counterElemPool                  CounterElemPool
 	timerElemPool                    TimerElemPool
 	gaugeElemPool                    GaugeElemPool
+	histogramElemPool                HistogramElemPool
 	verboseErrors                    bool
 
 	// Derived options.
-	fullCounterPrefix []byte
-	fullTimerPrefix   []byte
-	fullGaugePrefix   []byte
-	timerQuantiles    []float64
+	fullCounterPrefix   []byte
+	fullTimerPrefix     []byte
+	fullGaugePrefix     []byte
+	fullHistogramPrefix []byte
+	timerQuantiles      []float64
 }
 
 // NewOptions create a new set of options.
@@ -359,13 +393,15 @@ func NewOptions() Options {
 	aggTypesOptions := aggregation.NewTypesOptions().
 		SetCounterTypeStringTransformFn(aggregation.EmptyTransform).
 		SetTimerTypeStringTransformFn(aggregation.SuffixTransform).
-		SetGaugeTypeStringTransformFn(aggregation.EmptyTransform)
+		SetGaugeTypeStringTransformFn(aggregation.EmptyTransform).
+		SetHistogramTypeStringTransformFn(aggregation.SuffixTransform)
 	o := &options{
 		aggTypesOptions:                  aggTypesOptions,
 		metricPrefix:                     defaultMetricPrefix,
 		counterPrefix:                    defaultCounterPrefix,
 		timerPrefix:                      defaultTimerPrefix,
 		gaugePrefix:                      defaultGaugePrefix,
+		histogramPrefix:                  defaultHistogramPrefix,
 		timeLock:                         &sync.RWMutex{},
 		clockOpts:                        clock.NewOptions(),
 		instrumentOpts:                   instrument.NewOptions(),
@@ -385,6 +421,7 @@ func NewOptions() Options {
 		bufferForFutureTimedMetric:       defaultTimedMetricBuffer,
 		maxNumCachedSourceSets:           defaultMaxNumCachedSourceSets,
 		discardNaNAggregatedValues:       defaultDiscardNaNAggregatedValues,
+		histogramBucketIDFn:              defaultHistogramBucketIDFn,
 		verboseErrors:                    defaultVerboseErrors,
 	}
 
@@ -441,6 +478,17 @@ func (o *options) GaugePrefix() []byte {
 	return o.gaugePrefix
 }
 
+func (o *options) SetHistogramPrefix(value []byte) Options {
+	opts := *o
+	opts.histogramPrefix = value
+	opts.computeFullHistogramPrefix()
+	return &opts
+}
+
+func (o *options) HistogramPrefix() []byte {
+	return o.histogramPrefix
+}
+
 func (o *options) SetTimeLock(value *sync.RWMutex) Options {
 	opts := *o
 	opts.timeLock = value
@@ -701,6 +749,16 @@ func (o *options) DiscardNaNAggregatedValues() bool {
 	return o.discardNaNAggregatedValues
 }
 
+func (o *options) SetHistogramBucketIDFn(value HistogramBucketIDFn) Options {
+	opts := *o
+	opts.histogramBucketIDFn = value
+	return &opts
+}
+
+func (o *options) HistogramBucketIDFn() HistogramBucketIDFn {
+	return o.histogramBucketIDFn
+}
+
 func (o *options) SetEntryPool(value EntryPool) Options {
 	opts := *o
 	opts.entryPool = value
@@ -741,6 +799,16 @@ func (o *options) GaugeElemPool() GaugeElemPool {
 	return o.gaugeElemPool
 }
 
+func (o *options) SetHistogramElemPool(value HistogramElemPool) Options {
+	opts := *o
+	opts.histogramElemPool = value
+	return &opts
+}
+
+func (o *options) HistogramElemPool() HistogramElemPool {
+	return o.histogramElemPool
+}
+
 func (o *options) SetVerboseErrors(value bool) Options {
 	opts := *o
 	opts.verboseErrors = value
@@ -763,6 +831,10 @@ func (o *options) FullGaugePrefix() []byte {
 	return o.fullGaugePrefix
 }
 
+func (o *options) FullHistogramPrefix() []byte {
+	return o.fullHistogramPrefix
+}
+
 func (o *options) TimerQuantiles() []float64 {
 	return o.timerQuantiles
 }
@@ -788,6 +860,11 @@ func (o *options) initPools() {
 	o.gaugeElemPool.Init(func() *GaugeElem {
 		return MustNewGaugeElem(nil, policy.EmptyStoragePolicy, aggregation.DefaultTypes, applied.DefaultPipeline, 0, WithPrefixWithSuffix, o)
 	})
+
+	o.histogramElemPool = NewHistogramElemPool(nil)
+	o.histogramElemPool.Init(func() *HistogramElem {
+		return MustNewHistogramElem(nil, policy.EmptyStoragePolicy, aggregation.DefaultTypes, applied.DefaultPipeline, 0, WithPrefixWithSuffix, o)
+	})
 }
 
 func (o *options) computeAllDerived() {
@@ -798,6 +875,7 @@ func (o *options) computeFullPrefixes() {
 	o.computeFullCounterPrefix()
 	o.computeFullTimerPrefix()
 	o.computeFullGaugePrefix()
+	o.computeFullHistogramPrefix()
 }
 
 func (o *options) computeFullCounterPrefix() {
@@ -821,6 +899,24 @@ func (o *options) computeFullGaugePrefix() {
 	o.fullGaugePrefix = fullGaugePrefix
 }
 
+func (o *options) computeFullHistogramPrefix() {
+	fullHistogramPrefix := make([]byte, len(o.metricPrefix)+len(o.histogramPrefix))
+	n := copy(fullHistogramPrefix, o.metricPrefix)
+	copy(fullHistogramPrefix[n:], o.histogramPrefix)
+	o.fullHistogramPrefix = fullHistogramPrefix
+}
+
+// defaultHistogramBucketIDFn appends the bucket upper bound to the histogram id,
+// e.g. "foo.bar" with an upper bound of 0.5 becomes "foo.bar.le_0.5".
+func defaultHistogramBucketIDFn(histogramID id.RawID, upperBound float64) (id.RawID, error) {
+	bound := strconv.FormatFloat(upperBound, 'f', -1, 64)
+	bucketID := make([]byte, 0, len(histogramID)+len(defaultHistogramBucketSeparator)+len(bound))
+	bucketID = append(bucketID, histogramID...)
+	bucketID = append(bucketID, defaultHistogramBucketSeparator...)
+	bucketID = append(bucketID, bound...)
+	return bucketID, nil
+}
+
 func defaultMaxAllowedForwardingDelayFn(
 	resolution time.Duration,
 	numForwardedTimes int,
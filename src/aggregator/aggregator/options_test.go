@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/aggregator/runtime"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
 
@@ -56,6 +58,7 @@ func TestOptionsValidateDefault(t *testing.T) {
 	require.Equal(t, defaultCounterPrefix, o.CounterPrefix())
 	require.Equal(t, defaultTimerPrefix, o.TimerPrefix())
 	require.Equal(t, defaultGaugePrefix, o.GaugePrefix())
+	require.Equal(t, defaultHistogramPrefix, o.HistogramPrefix())
 	require.Equal(t, defaultEntryTTL, o.EntryTTL())
 	require.Equal(t, defaultEntryCheckInterval, o.EntryCheckInterval())
 	require.Equal(t, defaultEntryCheckBatchPercent, o.EntryCheckBatchPercent())
@@ -67,11 +70,14 @@ func TestOptionsValidateDefault(t *testing.T) {
 	require.NotNil(t, o.CounterElemPool())
 	require.NotNil(t, o.TimerElemPool())
 	require.NotNil(t, o.GaugeElemPool())
+	require.NotNil(t, o.HistogramElemPool())
+	require.NotNil(t, o.HistogramBucketIDFn())
 
 	// Validate derived options.
 	validateDerivedPrefix(t, o.FullCounterPrefix(), o.MetricPrefix(), o.CounterPrefix())
 	validateDerivedPrefix(t, o.FullTimerPrefix(), o.MetricPrefix(), o.TimerPrefix())
 	validateDerivedPrefix(t, o.FullGaugePrefix(), o.MetricPrefix(), o.GaugePrefix())
+	validateDerivedPrefix(t, o.FullHistogramPrefix(), o.MetricPrefix(), o.HistogramPrefix())
 }
 
 func TestOptionsSetMetricPrefix(t *testing.T) {
@@ -81,6 +87,7 @@ func TestOptionsSetMetricPrefix(t *testing.T) {
 	validateDerivedPrefix(t, o.FullCounterPrefix(), o.MetricPrefix(), o.CounterPrefix())
 	validateDerivedPrefix(t, o.FullTimerPrefix(), o.MetricPrefix(), o.TimerPrefix())
 	validateDerivedPrefix(t, o.FullGaugePrefix(), o.MetricPrefix(), o.GaugePrefix())
+	validateDerivedPrefix(t, o.FullHistogramPrefix(), o.MetricPrefix(), o.HistogramPrefix())
 }
 
 func TestOptionsSetCounterPrefix(t *testing.T) {
@@ -104,6 +111,13 @@ func TestOptionsSetGaugePrefix(t *testing.T) {
 	validateDerivedPrefix(t, o.FullGaugePrefix(), o.MetricPrefix(), o.GaugePrefix())
 }
 
+func TestOptionsSetHistogramPrefix(t *testing.T) {
+	newPrefix := []byte("testHistogramPrefix")
+	o := NewOptions().SetHistogramPrefix(newPrefix)
+	require.Equal(t, newPrefix, o.HistogramPrefix())
+	validateDerivedPrefix(t, o.FullHistogramPrefix(), o.MetricPrefix(), o.HistogramPrefix())
+}
+
 func TestSetClockOptions(t *testing.T) {
 	value := clock.NewOptions()
 	o := NewOptions().SetClockOptions(value)
@@ -225,3 +239,35 @@ func TestSetGaugeElemPool(t *testing.T) {
 	o := NewOptions().SetGaugeElemPool(value)
 	require.Equal(t, value, o.GaugeElemPool())
 }
+
+func TestSetHistogramElemPool(t *testing.T) {
+	value := NewHistogramElemPool(nil)
+	o := NewOptions().SetHistogramElemPool(value)
+	require.Equal(t, value, o.HistogramElemPool())
+}
+
+func TestSetHistogramBucketIDFn(t *testing.T) {
+	value := func(histogramID id.RawID, upperBound float64) (id.RawID, error) {
+		return histogramID, nil
+	}
+	o := NewOptions().SetHistogramBucketIDFn(value)
+	bucketID, err := o.HistogramBucketIDFn()(id.RawID("foo"), 1.5)
+	require.NoError(t, err)
+	require.Equal(t, id.RawID("foo"), bucketID)
+}
+
+func TestDefaultHistogramBucketIDFn(t *testing.T) {
+	fn := NewOptions().HistogramBucketIDFn()
+	for _, input := range []struct {
+		upperBound float64
+		expected   id.RawID
+	}{
+		{upperBound: 0.25, expected: id.RawID("foo.le_0.25")},
+		{upperBound: 10, expected: id.RawID("foo.le_10")},
+		{upperBound: math.Inf(1), expected: id.RawID("foo.le_+Inf")},
+	} {
+		bucketID, err := fn(id.RawID("foo"), input.upperBound)
+		require.NoError(t, err)
+		require.Equal(t, input.expected, bucketID)
+	}
+}
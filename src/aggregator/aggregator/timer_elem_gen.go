@@ -153,9 +153,9 @@ func (e *TimerElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) e
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
-	lockedAgg.aggregation.AddUnion(mu)
+	err = lockedAgg.aggregation.AddUnion(mu)
 	lockedAgg.Unlock()
-	return nil
+	return err
 }
 
 // AddValue adds a metric value at a given timestamp.
@@ -446,5 +446,22 @@ func (e *TimerElem) processValueWithAggregationLock(
 			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey, timeNanos, value)
 		}
 	}
+	// NB: histogram buckets are flushed as separate series so that they can be
+	// queried with histogram_quantile, and are never forwarded for rollups.
+	if !e.parsedPipeline.HasRollup {
+		for _, bucket := range lockedAgg.aggregation.Buckets() {
+			bucketID, err := e.opts.HistogramBucketIDFn()(e.id, bucket.UpperBound)
+			if err != nil {
+				e.histogramBucketIDErrors.Inc(1)
+				continue
+			}
+			switch e.idPrefixSuffixType {
+			case NoPrefixNoSuffix:
+				flushLocalFn(nil, bucketID, nil, timeNanos, float64(bucket.Count), e.sp)
+			case WithPrefixWithSuffix:
+				flushLocalFn(e.FullPrefix(e.opts), bucketID, nil, timeNanos, float64(bucket.Count), e.sp)
+			}
+		}
+	}
 	e.lastConsumedAtNanos = timeNanos
 }
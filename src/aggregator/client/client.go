@@ -69,6 +69,12 @@ type Client interface {
 		metadatas metadata.StagedMetadatas,
 	) error
 
+	// WriteUntimedHistogram writes untimed histogram metrics.
+	WriteUntimedHistogram(
+		histogram unaggregated.Histogram,
+		metadatas metadata.StagedMetadatas,
+	) error
+
 	// WriteTimed writes timed metrics.
 	WriteTimed(
 		metric aggregated.Metric,
@@ -107,6 +113,7 @@ type clientMetrics struct {
 	writeUntimedCounter    instrument.MethodMetrics
 	writeUntimedBatchTimer instrument.MethodMetrics
 	writeUntimedGauge      instrument.MethodMetrics
+	writeUntimedHistogram  instrument.MethodMetrics
 	writeForwarded         instrument.MethodMetrics
 	flush                  instrument.MethodMetrics
 	shardNotOwned          tally.Counter
@@ -118,6 +125,7 @@ func newClientMetrics(scope tally.Scope, sampleRate float64) clientMetrics {
 		writeUntimedCounter:    instrument.NewMethodMetrics(scope, "writeUntimedCounter", sampleRate),
 		writeUntimedBatchTimer: instrument.NewMethodMetrics(scope, "writeUntimedBatchTimer", sampleRate),
 		writeUntimedGauge:      instrument.NewMethodMetrics(scope, "writeUntimedGauge", sampleRate),
+		writeUntimedHistogram:  instrument.NewMethodMetrics(scope, "writeUntimedHistogram", sampleRate),
 		writeForwarded:         instrument.NewMethodMetrics(scope, "writeForwarded", sampleRate),
 		flush:                  instrument.NewMethodMetrics(scope, "flush", sampleRate),
 		shardNotOwned:          scope.Counter("shard-not-owned"),
@@ -239,6 +247,23 @@ func (c *client) WriteUntimedGauge(
 	return err
 }
 
+func (c *client) WriteUntimedHistogram(
+	histogram unaggregated.Histogram,
+	metadatas metadata.StagedMetadatas,
+) error {
+	callStart := c.nowFn()
+	payload := payloadUnion{
+		payloadType: untimedType,
+		untimed: untimedPayload{
+			metric:    histogram.ToUnion(),
+			metadatas: metadatas,
+		},
+	}
+	err := c.write(histogram.ID, c.nowNanos(), payload)
+	c.metrics.writeUntimedHistogram.ReportSuccessOrError(err, c.nowFn().Sub(callStart))
+	return err
+}
+
 func (c *client) WriteTimed(
 	metric aggregated.Metric,
 	metadata metadata.TimedMetadata,
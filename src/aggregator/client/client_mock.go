@@ -155,6 +155,20 @@ func (mr *MockClientMockRecorder) WriteUntimedGauge(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteUntimedGauge", reflect.TypeOf((*MockClient)(nil).WriteUntimedGauge), arg0, arg1)
 }
 
+// WriteUntimedHistogram mocks base method
+func (m *MockClient) WriteUntimedHistogram(arg0 unaggregated.Histogram, arg1 metadata.StagedMetadatas) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteUntimedHistogram", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteUntimedHistogram indicates an expected call of WriteUntimedHistogram
+func (mr *MockClientMockRecorder) WriteUntimedHistogram(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteUntimedHistogram", reflect.TypeOf((*MockClient)(nil).WriteUntimedHistogram), arg0, arg1)
+}
+
 // MockAdminClient is a mock of AdminClient interface
 type MockAdminClient struct {
 	ctrl     *gomock.Controller
@@ -289,3 +303,17 @@ func (mr *MockAdminClientMockRecorder) WriteUntimedGauge(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteUntimedGauge", reflect.TypeOf((*MockAdminClient)(nil).WriteUntimedGauge), arg0, arg1)
 }
+
+// WriteUntimedHistogram mocks base method
+func (m *MockAdminClient) WriteUntimedHistogram(arg0 unaggregated.Histogram, arg1 metadata.StagedMetadatas) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteUntimedHistogram", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteUntimedHistogram indicates an expected call of WriteUntimedHistogram
+func (mr *MockAdminClientMockRecorder) WriteUntimedHistogram(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteUntimedHistogram", reflect.TypeOf((*MockAdminClient)(nil).WriteUntimedHistogram), arg0, arg1)
+}
@@ -64,6 +64,13 @@ var (
 		ID:       []byte("foo"),
 		GaugeVal: 123.456,
 	}
+	testHistogram = unaggregated.MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              []byte("foo"),
+		HistogramBounds: []float64{0.5, 1},
+		HistogramCounts: []int64{3, 2, 1},
+		HistogramSum:    4.5,
+	}
 	testTimed = aggregated.Metric{
 		Type:      metric.CounterType,
 		ID:        []byte("testForwarded"),
@@ -225,7 +232,7 @@ func TestClientInitSuccess(t *testing.T) {
 func TestClientWriteUntimedMetricClosed(t *testing.T) {
 	c := NewClient(testOptions()).(*client)
 	c.state = clientUninitialized
-	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge} {
+	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge, testHistogram} {
 		var err error
 		switch input.Type {
 		case metric.CounterType:
@@ -234,6 +241,8 @@ func TestClientWriteUntimedMetricClosed(t *testing.T) {
 			err = c.WriteUntimedBatchTimer(input.BatchTimer(), testStagedMetadatas)
 		case metric.GaugeType:
 			err = c.WriteUntimedGauge(input.Gauge(), testStagedMetadatas)
+		case metric.HistogramType:
+			err = c.WriteUntimedHistogram(input.Histogram(), testStagedMetadatas)
 		}
 		require.Equal(t, errClientIsUninitializedOrClosed, err)
 	}
@@ -250,7 +259,7 @@ func TestClientWriteUntimedMetricActiveStagedPlacementError(t *testing.T) {
 	c.state = clientInitialized
 	c.placementWatcher = watcher
 
-	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge} {
+	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge, testHistogram} {
 		var err error
 		switch input.Type {
 		case metric.CounterType:
@@ -259,6 +268,8 @@ func TestClientWriteUntimedMetricActiveStagedPlacementError(t *testing.T) {
 			err = c.WriteUntimedBatchTimer(input.BatchTimer(), testStagedMetadatas)
 		case metric.GaugeType:
 			err = c.WriteUntimedGauge(input.Gauge(), testStagedMetadatas)
+		case metric.HistogramType:
+			err = c.WriteUntimedHistogram(input.Histogram(), testStagedMetadatas)
 		}
 		require.Equal(t, errActiveStagedPlacementError, err)
 	}
@@ -277,7 +288,7 @@ func TestClientWriteUntimedMetricActivePlacementError(t *testing.T) {
 	c.state = clientInitialized
 	c.placementWatcher = watcher
 
-	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge} {
+	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge, testHistogram} {
 		var err error
 		switch input.Type {
 		case metric.CounterType:
@@ -286,6 +297,8 @@ func TestClientWriteUntimedMetricActivePlacementError(t *testing.T) {
 			err = c.WriteUntimedBatchTimer(input.BatchTimer(), testStagedMetadatas)
 		case metric.GaugeType:
 			err = c.WriteUntimedGauge(input.Gauge(), testStagedMetadatas)
+		case metric.HistogramType:
+			err = c.WriteUntimedHistogram(input.Histogram(), testStagedMetadatas)
 		}
 		require.Equal(t, errActivePlacementError, err)
 	}
@@ -328,7 +341,7 @@ func TestClientWriteUntimedMetricSuccess(t *testing.T) {
 		testPlacementInstances[0],
 		testPlacementInstances[2],
 	}
-	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge} {
+	for _, input := range []unaggregated.MetricUnion{testCounter, testBatchTimer, testGauge, testHistogram} {
 		// Reset states in each iteration.
 		instancesRes = instancesRes[:0]
 		shardRes = 0
@@ -342,6 +355,8 @@ func TestClientWriteUntimedMetricSuccess(t *testing.T) {
 			err = c.WriteUntimedBatchTimer(input.BatchTimer(), testStagedMetadatas)
 		case metric.GaugeType:
 			err = c.WriteUntimedGauge(input.Gauge(), testStagedMetadatas)
+		case metric.HistogramType:
+			err = c.WriteUntimedHistogram(input.Histogram(), testStagedMetadatas)
 		}
 
 		require.NoError(t, err)
@@ -262,6 +262,14 @@ func (w *writer) encodeUntimedWithLock(
 				StagedMetadatas: metadatas,
 			}}
 		encodeErr = encoder.EncodeMessage(msg)
+	case metric.HistogramType:
+		msg := encoding.UnaggregatedMessageUnion{
+			Type: encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: unaggregated.HistogramWithMetadatas{
+				Histogram:       metricUnion.Histogram(),
+				StagedMetadatas: metadatas,
+			}}
+		encodeErr = encoder.EncodeMessage(msg)
 	default:
 		encodeErr = errUnrecognizedMetricType
 	}
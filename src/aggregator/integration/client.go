@@ -153,6 +153,13 @@ func (c *client) writeUntimedMetricWithMetadatas(
 				Gauge:           mu.Gauge(),
 				StagedMetadatas: sm,
 			}}
+	case metric.HistogramType:
+		msg = encoding.UnaggregatedMessageUnion{
+			Type: encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: unaggregated.HistogramWithMetadatas{
+				Histogram:       mu.Histogram(),
+				StagedMetadatas: sm,
+			}}
 	default:
 		return fmt.Errorf("unrecognized metric type %v", mu.Type)
 	}
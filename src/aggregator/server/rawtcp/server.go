@@ -147,6 +147,10 @@ func (s *handler) Handle(conn net.Conn) {
 			untimedMetric = current.GaugeWithMetadatas.Gauge.ToUnion()
 			stagedMetadatas = current.GaugeWithMetadatas.StagedMetadatas
 			err = toAddUntimedError(s.aggregator.AddUntimed(untimedMetric, stagedMetadatas))
+		case encoding.HistogramWithMetadatasType:
+			untimedMetric = current.HistogramWithMetadatas.Histogram.ToUnion()
+			stagedMetadatas = current.HistogramWithMetadatas.StagedMetadatas
+			err = toAddUntimedError(s.aggregator.AddUntimed(untimedMetric, stagedMetadatas))
 		case encoding.ForwardedMetricWithMetadataType:
 			forwardedMetric = current.ForwardedMetricWithMetadata.ForwardedMetric
 			forwardMetadata = current.ForwardedMetricWithMetadata.ForwardMetadata
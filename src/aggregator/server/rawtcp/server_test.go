@@ -159,6 +159,15 @@ var (
 		Gauge:           testGauge.Gauge(),
 		StagedMetadatas: testDefaultMetadatas,
 	}
+	testHistogramWithMetadatas = unaggregated.HistogramWithMetadatas{
+		Histogram: unaggregated.Histogram{
+			ID:     []byte("testHistogram"),
+			Bounds: []float64{0.5, 1},
+			Counts: []int64{3, 2, 1},
+			Sum:    4.5,
+		},
+		StagedMetadatas: testDefaultMetadatas,
+	}
 	testTimedMetricWithMetadata = aggregated.TimedMetricWithMetadata{
 		Metric:        testTimed,
 		TimedMetadata: testTimedMetadata,
@@ -225,7 +234,8 @@ func testRawTCPServerHandleUnaggregated(
 		if protocol == protobufEncoding {
 			expectedResult.TimedMetricWithMetadata = append(expectedResult.TimedMetricWithMetadata, testTimedMetricWithMetadata)
 			expectedResult.ForwardedMetricsWithMetadata = append(expectedResult.ForwardedMetricsWithMetadata, testForwardedMetricWithMetadata)
-			expectedTotalMetrics += 5
+			expectedResult.HistogramsWithMetadatas = append(expectedResult.HistogramsWithMetadatas, testHistogramWithMetadatas)
+			expectedTotalMetrics += 6
 		} else {
 			expectedTotalMetrics += 3
 		}
@@ -258,6 +268,10 @@ func testRawTCPServerHandleUnaggregated(
 					Type:               encoding.GaugeWithMetadatasType,
 					GaugeWithMetadatas: testGaugeWithMetadatas,
 				}))
+				require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+					Type:                   encoding.HistogramWithMetadatasType,
+					HistogramWithMetadatas: testHistogramWithMetadatas,
+				}))
 				require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
 					Type: encoding.TimedMetricWithMetadataType,
 					TimedMetricWithMetadata: testTimedMetricWithMetadata,
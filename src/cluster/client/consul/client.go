@@ -0,0 +1,308 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package consul provides a config service client backed by consul.
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/client"
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/kv"
+	consulkv "github.com/m3db/m3/src/cluster/kv/consul"
+	"github.com/m3db/m3/src/cluster/services"
+	consulheartbeat "github.com/m3db/m3/src/cluster/services/heartbeat/consul"
+	"github.com/m3db/m3/src/cluster/services/leader"
+	consulleader "github.com/m3db/m3/src/cluster/services/leader/consul"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	hierarchySeparator = "/"
+	internalPrefix     = "_"
+	// kvPrefix matches the default namespace of the etcd backed client.
+	kvPrefix = "_kv"
+)
+
+var errInvalidNamespace = errors.New("invalid namespace")
+
+type newClientFn func(cluster Cluster) (consulapi.Client, error)
+
+// NewConfigServiceClient returns a ConfigServiceClient.
+func NewConfigServiceClient(opts Options) (client.Client, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	scope := opts.InstrumentOptions().
+		MetricsScope().
+		Tagged(map[string]string{"service": opts.Service()})
+
+	return &csclient{
+		opts:    opts,
+		sdOpts:  opts.ServicesOptions(),
+		kvScope: scope.Tagged(map[string]string{"config_service": "kv"}),
+		sdScope: scope.Tagged(map[string]string{"config_service": "sd"}),
+		hbScope: scope.Tagged(map[string]string{"config_service": "hb"}),
+		clis:    make(map[string]consulapi.Client),
+		logger:  opts.InstrumentOptions().Logger(),
+		newFn:   newClient,
+		stores:  make(map[string]kv.TxnStore),
+	}, nil
+}
+
+type csclient struct {
+	sync.RWMutex
+	clis map[string]consulapi.Client
+
+	opts    Options
+	sdOpts  services.Options
+	kvScope tally.Scope
+	sdScope tally.Scope
+	hbScope tally.Scope
+	logger  *zap.Logger
+	newFn   newClientFn
+
+	storeLock sync.Mutex
+	stores    map[string]kv.TxnStore
+}
+
+func (c *csclient) Services(opts services.OverrideOptions) (services.Services, error) {
+	if opts == nil {
+		opts = services.NewOverrideOptions()
+	}
+	return c.createServices(opts)
+}
+
+func (c *csclient) KV() (kv.Store, error) {
+	return c.Txn()
+}
+
+func (c *csclient) Txn() (kv.TxnStore, error) {
+	return c.TxnStore(kv.NewOverrideOptions())
+}
+
+func (c *csclient) Store(opts kv.OverrideOptions) (kv.Store, error) {
+	return c.TxnStore(opts)
+}
+
+func (c *csclient) TxnStore(opts kv.OverrideOptions) (kv.TxnStore, error) {
+	opts, err := c.sanitizeOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.createTxnStore(opts)
+}
+
+func (c *csclient) createServices(opts services.OverrideOptions) (services.Services, error) {
+	return services.NewServices(c.sdOpts.
+		SetHeartbeatGen(c.heartbeatGen()).
+		SetKVGen(c.kvGen()).
+		SetLeaderGen(c.leaderGen()).
+		SetNamespaceOptions(opts.NamespaceOptions()).
+		SetInstrumentsOptions(instrument.NewOptions().
+			SetLogger(c.logger).
+			SetMetricsScope(c.sdScope),
+		),
+	)
+}
+
+func (c *csclient) createTxnStore(opts kv.OverrideOptions) (kv.TxnStore, error) {
+	// validate the override options because they are user supplied.
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return c.txnGen(opts)
+}
+
+func (c *csclient) kvGen() services.KVGen {
+	return services.KVGen(func(zone string) (kv.Store, error) {
+		// we don't validate or sanitize the options here because we're using
+		// them as a container for zone.
+		opts := kv.NewOverrideOptions().SetZone(zone)
+		return c.txnGen(opts)
+	})
+}
+
+func (c *csclient) newkvOptions(opts kv.OverrideOptions) consulkv.Options {
+	kvOpts := consulkv.NewOptions().
+		SetInstrumentsOptions(instrument.NewOptions().
+			SetLogger(c.logger).
+			SetMetricsScope(c.kvScope))
+
+	if ns := opts.Namespace(); ns != "" {
+		kvOpts = kvOpts.SetPrefix(kvOpts.ApplyPrefix(ns))
+	}
+
+	if env := opts.Environment(); env != "" {
+		kvOpts = kvOpts.SetPrefix(kvOpts.ApplyPrefix(env))
+	}
+
+	return kvOpts
+}
+
+// txnGen assumes the caller has validated the options passed if they are
+// user-supplied (as opposed to constructed ourselves).
+func (c *csclient) txnGen(opts kv.OverrideOptions) (kv.TxnStore, error) {
+	cli, err := c.consulClientGen(opts.Zone())
+	if err != nil {
+		return nil, err
+	}
+
+	c.storeLock.Lock()
+	defer c.storeLock.Unlock()
+
+	key := kvStoreCacheKey(opts.Zone(), opts.Namespace(), opts.Environment())
+	store, ok := c.stores[key]
+	if ok {
+		return store, nil
+	}
+	if store, err = consulkv.NewStore(cli, c.newkvOptions(opts)); err != nil {
+		return nil, err
+	}
+
+	c.stores[key] = store
+	return store, nil
+}
+
+func (c *csclient) heartbeatGen() services.HeartbeatGen {
+	return services.HeartbeatGen(
+		func(sid services.ServiceID) (services.HeartbeatService, error) {
+			cli, err := c.consulClientGen(sid.Zone())
+			if err != nil {
+				return nil, err
+			}
+
+			opts := consulheartbeat.NewOptions().
+				SetInstrumentsOptions(instrument.NewOptions().
+					SetLogger(c.logger).
+					SetMetricsScope(c.hbScope)).
+				SetServiceID(sid)
+			return consulheartbeat.NewStore(cli, opts)
+		},
+	)
+}
+
+func (c *csclient) leaderGen() services.LeaderGen {
+	return services.LeaderGen(
+		func(sid services.ServiceID, eo services.ElectionOptions) (services.LeaderService, error) {
+			cli, err := c.consulClientGen(sid.Zone())
+			if err != nil {
+				return nil, err
+			}
+
+			opts := leader.NewOptions().
+				SetServiceID(sid).
+				SetElectionOpts(eo)
+
+			return consulleader.NewService(cli, opts)
+		},
+	)
+}
+
+func (c *csclient) consulClientGen(zone string) (consulapi.Client, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	cli, ok := c.clis[zone]
+	if ok {
+		return cli, nil
+	}
+
+	cluster, ok := c.opts.ClusterForZone(zone)
+	if !ok {
+		return nil, fmt.Errorf("no consul cluster found for zone: %s", zone)
+	}
+
+	cli, err := c.newFn(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	c.clis[zone] = cli
+	return cli, nil
+}
+
+func newClient(cluster Cluster) (consulapi.Client, error) {
+	opts := consulapi.NewOptions().
+		SetDatacenter(cluster.Datacenter()).
+		SetToken(cluster.Token())
+
+	// NB: fall back to the local agent if no address is configured.
+	if address := cluster.Address(); address != "" {
+		opts = opts.SetAddress(address)
+	}
+
+	return consulapi.NewClient(opts)
+}
+
+func validateTopLevelNamespace(namespace string) error {
+	if namespace == "" || namespace == hierarchySeparator {
+		return errInvalidNamespace
+	}
+	if strings.HasPrefix(namespace, internalPrefix) {
+		// start with _
+		return errInvalidNamespace
+	}
+	if strings.HasPrefix(namespace, hierarchySeparator+internalPrefix) {
+		return errInvalidNamespace
+	}
+	return nil
+}
+
+func (c *csclient) sanitizeOptions(opts kv.OverrideOptions) (kv.OverrideOptions, error) {
+	if opts.Zone() == "" {
+		opts = opts.SetZone(c.opts.Zone())
+	}
+
+	if opts.Environment() == "" {
+		opts = opts.SetEnvironment(c.opts.Env())
+	}
+
+	namespace := opts.Namespace()
+	if namespace == "" {
+		return opts.SetNamespace(kvPrefix), nil
+	}
+
+	if err := validateTopLevelNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+func kvStoreCacheKey(zone string, namespaces ...string) string {
+	parts := make([]string, 0, 1+len(namespaces))
+	parts = append(parts, zone)
+	for _, ns := range namespaces {
+		if ns != "" {
+			parts = append(parts, ns)
+		}
+	}
+	return strings.Join(parts, hierarchySeparator)
+}
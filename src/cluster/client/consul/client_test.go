@@ -0,0 +1,294 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"testing"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/consul/consultest"
+	"github.com/m3db/m3/src/cluster/generated/proto/kvtest"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/services"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulClientGen(t *testing.T) {
+	cs, err := NewConfigServiceClient(testOptions())
+	require.NoError(t, err)
+
+	c := cs.(*csclient)
+	// a zone that does not exist
+	_, err = c.consulClientGen("not_exist")
+	require.Error(t, err)
+	require.Equal(t, 0, len(c.clis))
+
+	c1, err := c.consulClientGen("zone1")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.clis))
+
+	c2, err := c.consulClientGen("zone2")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(c.clis))
+	require.False(t, c1 == c2)
+
+	// the local agent is used if no address is configured
+	_, err = c.consulClientGen("zone3")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(c.clis))
+
+	c1Again, err := c.consulClientGen("zone1")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(c.clis))
+	require.True(t, c1 == c1Again)
+}
+
+func TestKVAndHeartbeatServiceSharingConsulClient(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1")
+
+	cs, err := NewConfigServiceClient(testOptions().SetZone("zone1").SetEnv("env"))
+	require.NoError(t, err)
+
+	c := cs.(*csclient)
+
+	_, err = c.KV()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.clis))
+
+	_, err = c.heartbeatGen()(sid.SetZone("zone1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.clis))
+
+	_, err = c.heartbeatGen()(sid.SetZone("zone2"))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(c.clis))
+
+	_, err = c.heartbeatGen()(sid.SetZone("not_exist"))
+	require.Error(t, err)
+	require.Equal(t, 2, len(c.clis))
+}
+
+func TestClient(t *testing.T) {
+	_, err := NewConfigServiceClient(NewOptions())
+	require.Error(t, err)
+
+	cs, err := NewConfigServiceClient(testOptions())
+	require.NoError(t, err)
+	c := cs.(*csclient)
+
+	fn, closer := testNewConsulFn(t)
+	defer closer()
+	c.newFn = fn
+
+	txn, err := c.Txn()
+	require.NoError(t, err)
+
+	kv1, err := c.KV()
+	require.NoError(t, err)
+	require.Equal(t, kv1, txn)
+
+	kv2, err := c.Store(kv.NewOverrideOptions().SetNamespace("ns").SetEnvironment("test_env1"))
+	require.NoError(t, err)
+	require.NotEqual(t, kv1, kv2)
+
+	_, err = kv1.Set("foo", &kvtest.Foo{Msg: "bar"})
+	require.NoError(t, err)
+
+	_, err = kv2.Get("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	v, err := kv1.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, 1, v.Version())
+
+	kv3, err := c.Store(kv.NewOverrideOptions().SetZone("zone2").SetNamespace("ns"))
+	require.NoError(t, err)
+	require.NotEqual(t, kv2, kv3)
+
+	require.Equal(t, 2, len(c.clis))
+	_, ok := c.clis["zone2"]
+	require.True(t, ok)
+
+	sd1, err := c.Services(nil)
+	require.NoError(t, err)
+
+	err = sd1.SetMetadata(
+		services.NewServiceID().SetName("service").SetZone("zone3"),
+		services.NewMetadata(),
+	)
+	require.NoError(t, err)
+	// consul cli for zone3 will be created since the request is going to zone3
+	require.Equal(t, 3, len(c.clis))
+	_, ok = c.clis["zone3"]
+	require.True(t, ok)
+}
+
+func TestServicesWithNamespace(t *testing.T) {
+	cs, err := NewConfigServiceClient(testOptions())
+	require.NoError(t, err)
+	c := cs.(*csclient)
+
+	fn, closer := testNewConsulFn(t)
+	defer closer()
+	c.newFn = fn
+
+	sd1, err := c.Services(services.NewOverrideOptions())
+	require.NoError(t, err)
+
+	nOpts := services.NewNamespaceOptions().SetPlacementNamespace("p").SetMetadataNamespace("m")
+	sd2, err := c.Services(services.NewOverrideOptions().SetNamespaceOptions(nOpts))
+	require.NoError(t, err)
+
+	require.NotEqual(t, sd1, sd2)
+
+	sid := services.NewServiceID().SetName("service").SetZone("zone2")
+	err = sd1.SetMetadata(sid, services.NewMetadata())
+	require.NoError(t, err)
+
+	_, err = sd1.Metadata(sid)
+	require.NoError(t, err)
+
+	_, err = sd2.Metadata(sid)
+	require.Error(t, err)
+
+	sid2 := services.NewServiceID().SetName("service").SetZone("zone2").SetEnvironment("test")
+	err = sd2.SetMetadata(sid2, services.NewMetadata())
+	require.NoError(t, err)
+
+	_, err = sd1.Metadata(sid2)
+	require.Error(t, err)
+}
+
+func TestSanitizeKVOverrideOptions(t *testing.T) {
+	opts := testOptions()
+	cs, err := NewConfigServiceClient(opts)
+	require.NoError(t, err)
+
+	client := cs.(*csclient)
+	opts1, err := client.sanitizeOptions(kv.NewOverrideOptions())
+	require.NoError(t, err)
+	require.Equal(t, opts.Env(), opts1.Environment())
+	require.Equal(t, opts.Zone(), opts1.Zone())
+	require.Equal(t, kvPrefix, opts1.Namespace())
+
+	_, err = client.sanitizeOptions(kv.NewOverrideOptions().SetNamespace("_ns"))
+	require.Equal(t, errInvalidNamespace, err)
+}
+
+func TestKVOptionsPrefix(t *testing.T) {
+	cs, err := NewConfigServiceClient(testOptions())
+	require.NoError(t, err)
+	client := cs.(*csclient)
+
+	kvOpts := client.newkvOptions(kv.NewOverrideOptions().
+		SetNamespace("ns").
+		SetEnvironment("env"))
+	require.Equal(t, "ns/env", kvOpts.Prefix())
+
+	kvOpts = client.newkvOptions(kv.NewOverrideOptions())
+	require.Equal(t, "", kvOpts.Prefix())
+}
+
+func TestReuseKVStore(t *testing.T) {
+	opts := testOptions()
+	cs, err := NewConfigServiceClient(opts)
+	require.NoError(t, err)
+
+	store1, err := cs.Txn()
+	require.NoError(t, err)
+
+	store2, err := cs.KV()
+	require.NoError(t, err)
+	require.Equal(t, store1, store2)
+
+	store3, err := cs.Store(kv.NewOverrideOptions())
+	require.NoError(t, err)
+	require.Equal(t, store1, store3)
+
+	store4, err := cs.TxnStore(kv.NewOverrideOptions())
+	require.NoError(t, err)
+	require.Equal(t, store1, store4)
+
+	store5, err := cs.Store(kv.NewOverrideOptions().SetNamespace("foo"))
+	require.NoError(t, err)
+	require.NotEqual(t, store1, store5)
+
+	store6, err := cs.TxnStore(kv.NewOverrideOptions().SetNamespace("foo"))
+	require.NoError(t, err)
+	require.Equal(t, store5, store6)
+
+	client := cs.(*csclient)
+
+	client.storeLock.Lock()
+	require.Equal(t, 2, len(client.stores))
+	client.storeLock.Unlock()
+}
+
+func TestValidateNamespace(t *testing.T) {
+	inputs := []struct {
+		ns        string
+		expectErr bool
+	}{
+		{ns: "ns", expectErr: false},
+		{ns: "/ns", expectErr: false},
+		{ns: "/ns/ab", expectErr: false},
+		{ns: "ns/ab", expectErr: false},
+		{ns: "_ns", expectErr: true},
+		{ns: "/_ns", expectErr: true},
+		{ns: "", expectErr: true},
+		{ns: "/", expectErr: true},
+	}
+
+	for _, input := range inputs {
+		err := validateTopLevelNamespace(input.ns)
+		if input.expectErr {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+}
+
+func testOptions() Options {
+	clusters := []Cluster{
+		NewCluster().SetZone("zone1").SetAddress("i1:8500"),
+		NewCluster().SetZone("zone2").SetAddress("i2:8500").SetDatacenter("dc2"),
+		NewCluster().SetZone("zone3"),
+	}
+	return NewOptions().
+		SetClusters(clusters).
+		SetService("test_app").
+		SetZone("zone1").
+		SetEnv("env")
+}
+
+func testNewConsulFn(t *testing.T) (newClientFn, func()) {
+	server := consultest.NewServer()
+	cli, err := consulapi.NewClient(consulapi.NewOptions().SetAddress(server.Address()))
+	require.NoError(t, err)
+
+	newFn := func(Cluster) (consulapi.Client, error) {
+		return cli, nil
+	}
+
+	return newFn, server.Close
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// ClusterConfig is the config for a zoned consul cluster.
+type ClusterConfig struct {
+	Zone       string `yaml:"zone"`
+	Address    string `yaml:"address"`
+	Datacenter string `yaml:"datacenter"`
+	Token      string `yaml:"token"`
+}
+
+// NewCluster creates a new Cluster.
+func (c ClusterConfig) NewCluster() Cluster {
+	return NewCluster().
+		SetZone(c.Zone).
+		SetAddress(c.Address).
+		SetDatacenter(c.Datacenter).
+		SetToken(c.Token)
+}
+
+// Configuration is for consul backed config service client.
+type Configuration struct {
+	Zone           string                 `yaml:"zone"`
+	Env            string                 `yaml:"env"`
+	Service        string                 `yaml:"service" validate:"nonzero"`
+	ConsulClusters []ClusterConfig        `yaml:"consulClusters"`
+	SDConfig       services.Configuration `yaml:"m3sd"`
+}
+
+// NewClient creates a new config service client.
+func (cfg Configuration) NewClient(iopts instrument.Options) (client.Client, error) {
+	return NewConfigServiceClient(cfg.NewOptions().SetInstrumentOptions(iopts))
+}
+
+// NewOptions returns a new Options.
+func (cfg Configuration) NewOptions() Options {
+	return NewOptions().
+		SetZone(cfg.Zone).
+		SetEnv(cfg.Env).
+		SetService(cfg.Service).
+		SetClusters(NewClusters(cfg.ConsulClusters)).
+		SetServicesOptions(cfg.SDConfig.NewOptions())
+}
+
+// NewClusters creates the Clusters described by a list of cluster configs.
+func NewClusters(cfgs []ClusterConfig) []Cluster {
+	res := make([]Cluster, len(cfgs))
+	for i, c := range cfgs {
+		res[i] = c.NewCluster()
+	}
+
+	return res
+}
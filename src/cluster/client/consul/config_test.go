@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestConfig(t *testing.T) {
+	const testConfig = `
+env: env1
+zone: z1
+service: service1
+consulClusters:
+  - zone: z1
+    address: consul1:8500
+  - zone: z2
+    address: https://consul2:8501
+    datacenter: dc2
+    token: secret
+m3sd:
+  initTimeout: 10s
+`
+
+	var cfg Configuration
+	require.NoError(t, yaml.Unmarshal([]byte(testConfig), &cfg))
+
+	require.Equal(t, "env1", cfg.Env)
+	require.Equal(t, "z1", cfg.Zone)
+	require.Equal(t, "service1", cfg.Service)
+	require.Equal(t, []ClusterConfig{
+		{
+			Zone:    "z1",
+			Address: "consul1:8500",
+		},
+		{
+			Zone:       "z2",
+			Address:    "https://consul2:8501",
+			Datacenter: "dc2",
+			Token:      "secret",
+		},
+	}, cfg.ConsulClusters)
+	require.Equal(t, 10*time.Second, *cfg.SDConfig.InitTimeout)
+
+	opts := cfg.NewOptions()
+	require.Equal(t, "env1", opts.Env())
+	require.Equal(t, "z1", opts.Zone())
+	require.Equal(t, "service1", opts.Service())
+	require.Equal(t, 10*time.Second, opts.ServicesOptions().InitTimeout())
+
+	cluster1, exists := opts.ClusterForZone("z1")
+	require.True(t, exists)
+	require.Equal(t, "consul1:8500", cluster1.Address())
+	require.Equal(t, "", cluster1.Datacenter())
+	require.Equal(t, "", cluster1.Token())
+
+	cluster2, exists := opts.ClusterForZone("z2")
+	require.True(t, exists)
+	require.Equal(t, "https://consul2:8501", cluster2.Address())
+	require.Equal(t, "dc2", cluster2.Datacenter())
+	require.Equal(t, "secret", cluster2.Token())
+
+	_, exists = opts.ClusterForZone("z3")
+	require.False(t, exists)
+}
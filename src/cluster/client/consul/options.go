@@ -0,0 +1,175 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// NewOptions creates a set of Options.
+func NewOptions() Options {
+	return options{
+		sdOpts: services.NewOptions(),
+		iopts:  instrument.NewOptions(),
+	}
+}
+
+type options struct {
+	env      string
+	zone     string
+	service  string
+	sdOpts   services.Options
+	clusters map[string]Cluster
+	iopts    instrument.Options
+}
+
+func (o options) Validate() error {
+	if o.service == "" {
+		return errors.New("invalid options, no service name set")
+	}
+
+	if len(o.clusters) == 0 {
+		return errors.New("invalid options, no consul clusters set")
+	}
+
+	if o.iopts == nil {
+		return errors.New("invalid options, no instrument options set")
+	}
+
+	return nil
+}
+
+func (o options) Env() string {
+	return o.env
+}
+
+func (o options) SetEnv(e string) Options {
+	o.env = e
+	return o
+}
+
+func (o options) Zone() string {
+	return o.zone
+}
+
+func (o options) SetZone(z string) Options {
+	o.zone = z
+	return o
+}
+
+func (o options) Service() string {
+	return o.service
+}
+
+func (o options) SetService(id string) Options {
+	o.service = id
+	return o
+}
+
+func (o options) ServicesOptions() services.Options {
+	return o.sdOpts
+}
+
+func (o options) SetServicesOptions(cfg services.Options) Options {
+	o.sdOpts = cfg
+	return o
+}
+
+func (o options) Clusters() []Cluster {
+	res := make([]Cluster, 0, len(o.clusters))
+	for _, c := range o.clusters {
+		res = append(res, c)
+	}
+	return res
+}
+
+func (o options) SetClusters(clusters []Cluster) Options {
+	o.clusters = make(map[string]Cluster, len(clusters))
+	for _, c := range clusters {
+		o.clusters[c.Zone()] = c
+	}
+	return o
+}
+
+func (o options) ClusterForZone(z string) (Cluster, bool) {
+	c, ok := o.clusters[z]
+	return c, ok
+}
+
+func (o options) InstrumentOptions() instrument.Options {
+	return o.iopts
+}
+
+func (o options) SetInstrumentOptions(iopts instrument.Options) Options {
+	o.iopts = iopts
+	return o
+}
+
+// NewCluster creates a Cluster.
+func NewCluster() Cluster {
+	return cluster{}
+}
+
+type cluster struct {
+	zone       string
+	address    string
+	datacenter string
+	token      string
+}
+
+func (c cluster) Zone() string {
+	return c.zone
+}
+
+func (c cluster) SetZone(z string) Cluster {
+	c.zone = z
+	return c
+}
+
+func (c cluster) Address() string {
+	return c.address
+}
+
+func (c cluster) SetAddress(address string) Cluster {
+	c.address = address
+	return c
+}
+
+func (c cluster) Datacenter() string {
+	return c.datacenter
+}
+
+func (c cluster) SetDatacenter(dc string) Cluster {
+	c.datacenter = dc
+	return c
+}
+
+func (c cluster) Token() string {
+	return c.token
+}
+
+func (c cluster) SetToken(token string) Cluster {
+	c.token = token
+	return c
+}
@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/kv"
 	etcdkv "github.com/m3db/m3/src/cluster/kv/etcd"
 	"github.com/m3db/m3/src/cluster/services"
@@ -59,12 +60,17 @@ type newClientFn func(cluster Cluster) (*clientv3.Client, error)
 
 type cacheFileForZoneFn func(zone string) etcdkv.CacheFileFn
 
-// NewConfigServiceClient returns a ConfigServiceClient.
+// NewConfigServiceClient returns a ConfigServiceClient, backed by consul
+// rather than etcd if the consul backend is selected.
 func NewConfigServiceClient(opts Options) (client.Client, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
 
+	if opts.Backend() == ConsulBackend {
+		return consulclient.NewConfigServiceClient(consulOptions(opts))
+	}
+
 	scope := opts.InstrumentOptions().
 		MetricsScope().
 		Tagged(map[string]string{"service": opts.Service()})
@@ -276,6 +282,16 @@ func (c *csclient) etcdClientGen(zone string) (*clientv3.Client, error) {
 	return cli, nil
 }
 
+func consulOptions(opts Options) consulclient.Options {
+	return consulclient.NewOptions().
+		SetEnv(opts.Env()).
+		SetZone(opts.Zone()).
+		SetService(opts.Service()).
+		SetServicesOptions(opts.ServicesOptions()).
+		SetClusters(opts.ConsulClusters()).
+		SetInstrumentOptions(opts.InstrumentOptions())
+}
+
 func newClient(cluster Cluster) (*clientv3.Client, error) {
 	tls, err := cluster.TLSOptions().Config()
 	if err != nil {
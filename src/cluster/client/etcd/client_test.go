@@ -24,6 +24,7 @@ import (
 	"os"
 	"testing"
 
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cluster/services"
 
@@ -80,6 +81,21 @@ func TestETCDClientGen(t *testing.T) {
 	})
 }
 
+func TestConsulBackend(t *testing.T) {
+	opts := testOptions().SetBackend(ConsulBackend)
+	_, err := NewConfigServiceClient(opts)
+	require.Error(t, err)
+
+	opts = opts.SetConsulClusters([]consulclient.Cluster{
+		consulclient.NewCluster().SetZone("zone1"),
+	})
+	cs, err := NewConfigServiceClient(opts)
+	require.NoError(t, err)
+
+	_, ok := cs.(*csclient)
+	require.False(t, ok)
+}
+
 func TestKVAndHeartbeatServiceSharingETCDClient(t *testing.T) {
 	sid := services.NewServiceID().SetName("s1")
 
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/x/instrument"
 )
@@ -90,14 +91,16 @@ func (c *KeepAliveConfig) NewOptions() KeepAliveOptions {
 
 // Configuration is for config service client.
 type Configuration struct {
-	Zone              string                 `yaml:"zone"`
-	Env               string                 `yaml:"env"`
-	Service           string                 `yaml:"service" validate:"nonzero"`
-	CacheDir          string                 `yaml:"cacheDir"`
-	ETCDClusters      []ClusterConfig        `yaml:"etcdClusters"`
-	SDConfig          services.Configuration `yaml:"m3sd"`
-	WatchWithRevision int64                  `yaml:"watchWithRevision"`
-	NewDirectoryMode  *os.FileMode           `yaml:"newDirectoryMode"`
+	Backend           Backend                      `yaml:"backend"`
+	Zone              string                       `yaml:"zone"`
+	Env               string                       `yaml:"env"`
+	Service           string                       `yaml:"service" validate:"nonzero"`
+	CacheDir          string                       `yaml:"cacheDir"`
+	ETCDClusters      []ClusterConfig              `yaml:"etcdClusters"`
+	ConsulClusters    []consulclient.ClusterConfig `yaml:"consulClusters"`
+	SDConfig          services.Configuration       `yaml:"m3sd"`
+	WatchWithRevision int64                        `yaml:"watchWithRevision"`
+	NewDirectoryMode  *os.FileMode                 `yaml:"newDirectoryMode"`
 }
 
 // NewClient creates a new config service client.
@@ -113,9 +116,14 @@ func (cfg Configuration) NewOptions() Options {
 		SetService(cfg.Service).
 		SetCacheDir(cfg.CacheDir).
 		SetClusters(cfg.etcdClusters()).
+		SetConsulClusters(consulclient.NewClusters(cfg.ConsulClusters)).
 		SetServicesOptions(cfg.SDConfig.NewOptions()).
 		SetWatchWithRevision(cfg.WatchWithRevision)
 
+	if cfg.Backend != "" {
+		opts = opts.SetBackend(cfg.Backend)
+	}
+
 	if v := cfg.NewDirectoryMode; v != nil {
 		opts = opts.SetNewDirectoryMode(*v)
 	} else {
@@ -25,6 +25,8 @@ import (
 	"testing"
 	"time"
 
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
+
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -142,6 +144,8 @@ m3sd:
 	require.Equal(t, 10*time.Second, keepAliveOpts.KeepAlivePeriodMaxJitter())
 	require.Equal(t, 10*time.Second, keepAliveOpts.KeepAliveTimeout())
 
+	require.Equal(t, EtcdBackend, opts.Backend())
+
 	t.Run("TestOptionsNewDirectoryMode", func(t *testing.T) {
 		opts := cfg.NewOptions()
 		require.Equal(t, defaultDirectoryMode, opts.NewDirectoryMode())
@@ -172,3 +176,37 @@ m3sd:
 		require.Equal(t, os.FileMode(0744), *cfg2.NewDirectoryMode)
 	})
 }
+
+func TestConsulBackendConfig(t *testing.T) {
+	const testConfig = `
+backend: consul
+env: env1
+zone: z1
+service: service1
+consulClusters:
+  - zone: z1
+    address: consul1:8500
+    datacenter: dc1
+`
+
+	var cfg Configuration
+	require.NoError(t, yaml.Unmarshal([]byte(testConfig), &cfg))
+	require.Equal(t, ConsulBackend, cfg.Backend)
+	require.Equal(t, []consulclient.ClusterConfig{
+		{
+			Zone:       "z1",
+			Address:    "consul1:8500",
+			Datacenter: "dc1",
+		},
+	}, cfg.ConsulClusters)
+
+	opts := cfg.NewOptions()
+	require.Equal(t, ConsulBackend, opts.Backend())
+	require.NoError(t, opts.Validate())
+
+	clusters := opts.ConsulClusters()
+	require.Equal(t, 1, len(clusters))
+	require.Equal(t, "z1", clusters[0].Zone())
+	require.Equal(t, "consul1:8500", clusters[0].Address())
+	require.Equal(t, "dc1", clusters[0].Datacenter())
+}
@@ -30,6 +30,7 @@ import (
 	"os"
 	"time"
 
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/retry"
@@ -171,8 +172,9 @@ func (o tlsOptions) Config() (*tls.Config, error) {
 // NewOptions creates a set of Options.
 func NewOptions() Options {
 	return options{
-		sdOpts: services.NewOptions(),
-		iopts:  instrument.NewOptions(),
+		backend: EtcdBackend,
+		sdOpts:  services.NewOptions(),
+		iopts:   instrument.NewOptions(),
 		// NB(r): Set some default retry options so changes to retry
 		// option defaults don't change behavior of this client's retry options
 		retryOpts: retry.NewOptions().
@@ -186,6 +188,7 @@ func NewOptions() Options {
 }
 
 type options struct {
+	backend           Backend
 	env               string
 	zone              string
 	service           string
@@ -193,6 +196,7 @@ type options struct {
 	watchWithRevision int64
 	sdOpts            services.Options
 	clusters          map[string]Cluster
+	consulClusters    []consulclient.Cluster
 	iopts             instrument.Options
 	retryOpts         retry.Options
 	newDirectoryMode  os.FileMode
@@ -203,8 +207,17 @@ func (o options) Validate() error {
 		return errors.New("invalid options, no service name set")
 	}
 
-	if len(o.clusters) == 0 {
-		return errors.New("invalid options, no etcd clusters set")
+	switch o.backend {
+	case EtcdBackend:
+		if len(o.clusters) == 0 {
+			return errors.New("invalid options, no etcd clusters set")
+		}
+	case ConsulBackend:
+		if len(o.consulClusters) == 0 {
+			return errors.New("invalid options, no consul clusters set")
+		}
+	default:
+		return fmt.Errorf("invalid options, unknown backend: %s", o.backend)
 	}
 
 	if o.iopts == nil {
@@ -214,6 +227,15 @@ func (o options) Validate() error {
 	return nil
 }
 
+func (o options) Backend() Backend {
+	return o.backend
+}
+
+func (o options) SetBackend(b Backend) Options {
+	o.backend = b
+	return o
+}
+
 func (o options) Env() string {
 	return o.env
 }
@@ -280,6 +302,15 @@ func (o options) ClusterForZone(z string) (Cluster, bool) {
 	return c, ok
 }
 
+func (o options) ConsulClusters() []consulclient.Cluster {
+	return o.consulClusters
+}
+
+func (o options) SetConsulClusters(clusters []consulclient.Cluster) Options {
+	o.consulClusters = clusters
+	return o
+}
+
 func (o options) InstrumentOptions() instrument.Options {
 	return o.iopts
 }
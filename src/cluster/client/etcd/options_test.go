@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/x/instrument"
 
@@ -156,3 +157,23 @@ func TestValidate(t *testing.T) {
 	opts = opts.SetInstrumentOptions(nil)
 	assert.Error(t, opts.Validate())
 }
+
+func TestValidateBackend(t *testing.T) {
+	opts := NewOptions().SetService("app")
+	assert.Equal(t, EtcdBackend, opts.Backend())
+
+	opts = opts.SetBackend(ConsulBackend)
+	assert.Equal(t, ConsulBackend, opts.Backend())
+
+	// etcd clusters are not used by the consul backend
+	opts = opts.SetClusters([]Cluster{NewCluster().SetZone("z1")})
+	assert.Error(t, opts.Validate())
+
+	c1 := consulclient.NewCluster().SetZone("z1")
+	opts = opts.SetConsulClusters([]consulclient.Cluster{c1})
+	assert.Equal(t, []consulclient.Cluster{c1}, opts.ConsulClusters())
+	assert.NoError(t, opts.Validate())
+
+	opts = opts.SetBackend(Backend("zookeeper"))
+	assert.Error(t, opts.Validate())
+}
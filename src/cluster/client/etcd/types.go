@@ -25,13 +25,29 @@ import (
 	"os"
 	"time"
 
+	consulclient "github.com/m3db/m3/src/cluster/client/consul"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/retry"
 )
 
+// Backend is the store backing a config service client.
+type Backend string
+
+const (
+	// EtcdBackend backs the config service client with etcd clusters, it is
+	// the default backend.
+	EtcdBackend Backend = "etcd"
+
+	// ConsulBackend backs the config service client with consul clusters.
+	ConsulBackend Backend = "consul"
+)
+
 // Options is the Options to create a config service client.
 type Options interface {
+	Backend() Backend
+	SetBackend(b Backend) Options
+
 	Env() string
 	SetEnv(e string) Options
 
@@ -51,6 +67,9 @@ type Options interface {
 	SetClusters(clusters []Cluster) Options
 	ClusterForZone(z string) (Cluster, bool)
 
+	ConsulClusters() []consulclient.Cluster
+	SetConsulClusters(clusters []consulclient.Cluster) Options
+
 	InstrumentOptions() instrument.Options
 	SetInstrumentOptions(iopts instrument.Options) Options
 
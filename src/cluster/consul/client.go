@@ -0,0 +1,320 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	indexHeader = "X-Consul-Index"
+	tokenHeader = "X-Consul-Token"
+
+	kvPathPrefix       = "/v1/kv/"
+	txnPath            = "/v1/txn"
+	sessionCreatePath  = "/v1/session/create"
+	sessionRenewPath   = "/v1/session/renew/"
+	sessionDestroyPath = "/v1/session/destroy/"
+)
+
+type client struct {
+	base       *url.URL
+	datacenter string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Consul client.
+func NewClient(opts Options) (Client, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	address := opts.Address()
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	base, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consul address %s: %v", opts.Address(), err)
+	}
+
+	return &client{
+		base:       base,
+		datacenter: opts.Datacenter(),
+		token:      opts.Token(),
+		httpClient: opts.HTTPClient(),
+	}, nil
+}
+
+func (c *client) Get(ctx context.Context, key string, q *QueryOptions) (*KVPair, QueryMeta, error) {
+	var pairs []*KVPair
+	found, meta, err := c.query(ctx, kvPathPrefix+key, url.Values{}, q, &pairs)
+	if err != nil || !found || len(pairs) == 0 {
+		return nil, meta, err
+	}
+	return pairs[0], meta, nil
+}
+
+func (c *client) List(ctx context.Context, prefix string, q *QueryOptions) ([]*KVPair, QueryMeta, error) {
+	var pairs []*KVPair
+	_, meta, err := c.query(ctx, kvPathPrefix+prefix, url.Values{"recurse": []string{""}}, q, &pairs)
+	return pairs, meta, err
+}
+
+func (c *client) Keys(ctx context.Context, prefix string, q *QueryOptions) ([]string, QueryMeta, error) {
+	var keys []string
+	_, meta, err := c.query(ctx, kvPathPrefix+prefix, url.Values{"keys": []string{""}}, q, &keys)
+	return keys, meta, err
+}
+
+func (c *client) Put(ctx context.Context, p *KVPair) error {
+	_, err := c.writeKV(ctx, http.MethodPut, p, url.Values{})
+	return err
+}
+
+func (c *client) CAS(ctx context.Context, p *KVPair) (bool, error) {
+	params := url.Values{}
+	params.Set("cas", strconv.FormatUint(p.ModifyIndex, 10))
+	return c.writeKV(ctx, http.MethodPut, p, params)
+}
+
+func (c *client) Acquire(ctx context.Context, p *KVPair) (bool, error) {
+	params := url.Values{}
+	params.Set("acquire", p.Session)
+	return c.writeKV(ctx, http.MethodPut, p, params)
+}
+
+func (c *client) Release(ctx context.Context, p *KVPair) (bool, error) {
+	params := url.Values{}
+	params.Set("release", p.Session)
+	return c.writeKV(ctx, http.MethodPut, p, params)
+}
+
+func (c *client) Delete(ctx context.Context, key string) error {
+	_, err := c.writeKV(ctx, http.MethodDelete, &KVPair{Key: key}, url.Values{})
+	return err
+}
+
+func (c *client) DeleteCAS(ctx context.Context, p *KVPair) (bool, error) {
+	params := url.Values{}
+	params.Set("cas", strconv.FormatUint(p.ModifyIndex, 10))
+	return c.writeKV(ctx, http.MethodDelete, p, params)
+}
+
+func (c *client) Txn(ctx context.Context, ops []TxnOp) (bool, *TxnResponse, error) {
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return false, nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, txnPath, url.Values{}, body)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return false, nil, responseError(resp)
+	}
+
+	var txnResp TxnResponse
+	if err := json.NewDecoder(resp.Body).Decode(&txnResp); err != nil {
+		return false, nil, err
+	}
+	return resp.StatusCode == http.StatusOK, &txnResp, nil
+}
+
+func (c *client) CreateSession(ctx context.Context, se *SessionEntry) (string, error) {
+	body, err := json.Marshal(se)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, sessionCreatePath, url.Values{}, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError(resp)
+	}
+
+	var created SessionEntry
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (c *client) RenewSession(ctx context.Context, id string) error {
+	resp, err := c.do(ctx, http.MethodPut, sessionRenewPath+id, url.Values{}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrSessionNotFound
+	default:
+		return responseError(resp)
+	}
+}
+
+func (c *client) DestroySession(ctx context.Context, id string) error {
+	resp, err := c.do(ctx, http.MethodPut, sessionDestroyPath+id, url.Values{}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// query issues a read request and decodes the response into out, the returned
+// bool is false if the requested keys do not exist.
+func (c *client) query(
+	ctx context.Context,
+	path string,
+	params url.Values,
+	q *QueryOptions,
+	out interface{},
+) (bool, QueryMeta, error) {
+	if q != nil {
+		if q.WaitIndex > 0 {
+			params.Set("index", strconv.FormatUint(q.WaitIndex, 10))
+		}
+		if q.WaitTime > 0 {
+			params.Set("wait", fmt.Sprintf("%dms", q.WaitTime/time.Millisecond))
+		}
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, path, params, nil)
+	if err != nil {
+		return false, QueryMeta{}, err
+	}
+	defer resp.Body.Close()
+
+	var meta QueryMeta
+	if idx := resp.Header.Get(indexHeader); idx != "" {
+		if meta.LastIndex, err = strconv.ParseUint(idx, 10, 64); err != nil {
+			return false, meta, fmt.Errorf("invalid consul index %s: %v", idx, err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, meta, json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNotFound:
+		return false, meta, nil
+	default:
+		return false, meta, responseError(resp)
+	}
+}
+
+func (c *client) writeKV(
+	ctx context.Context,
+	method string,
+	p *KVPair,
+	params url.Values,
+) (bool, error) {
+	if p.Flags != 0 {
+		params.Set("flags", strconv.FormatUint(p.Flags, 10))
+	}
+
+	var body []byte
+	if method == http.MethodPut {
+		// NB: a nil body would be sent without a content length, always send
+		// an explicit, possibly empty, value.
+		body = p.Value
+		if body == nil {
+			body = []byte{}
+		}
+	}
+
+	resp, err := c.do(ctx, method, kvPathPrefix+p.Key, params, body)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, responseError(resp)
+	}
+
+	var ok bool
+	if err := json.NewDecoder(resp.Body).Decode(&ok); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+func (c *client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	params url.Values,
+	body []byte,
+) (*http.Response, error) {
+	u := *c.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	if c.datacenter != "" {
+		params.Set("dc", c.datacenter)
+	}
+	u.RawQuery = params.Encode()
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set(tokenHeader, c.token)
+	}
+
+	return c.httpClient.Do(req.WithContext(ctx))
+}
+
+func responseError(resp *http.Response) error {
+	b, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Errorf("unexpected response code %d from consul: %s",
+		resp.StatusCode, strings.TrimSpace(string(b)))
+}
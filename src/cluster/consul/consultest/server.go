@@ -0,0 +1,566 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package consultest provides an in-process stand-in for a Consul agent to
+// test the Consul backed cluster packages against.
+package consultest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/consul"
+)
+
+const (
+	defaultWaitTime = 5 * time.Minute
+
+	kvPathPrefix       = "/v1/kv/"
+	txnPath            = "/v1/txn"
+	sessionCreatePath  = "/v1/session/create"
+	sessionRenewPath   = "/v1/session/renew/"
+	sessionDestroyPath = "/v1/session/destroy/"
+)
+
+// Server implements the subset of the Consul HTTP API used by the cluster
+// packages: the kv endpoints including blocking queries, cas and session
+// locks, transactions and TTL sessions.
+type Server struct {
+	sync.Mutex
+
+	srv         *httptest.Server
+	index       uint64
+	kvs         map[string]*consul.KVPair
+	sessions    map[string]*session
+	nextSession int
+	changed     chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+type session struct {
+	behavior consul.SessionBehavior
+	ttl      time.Duration
+	timer    *time.Timer
+}
+
+// NewServer creates and starts a new Server.
+func NewServer() *Server {
+	s := &Server{
+		// NB: Consul never returns a zero index, start at one so clients can
+		// always issue blocking queries with the returned index.
+		index:    1,
+		kvs:      make(map[string]*consul.KVPair),
+		sessions: make(map[string]*session),
+		changed:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(kvPathPrefix, s.handleKV)
+	mux.HandleFunc(txnPath, s.handleTxn)
+	mux.HandleFunc(sessionCreatePath, s.handleSessionCreate)
+	mux.HandleFunc(sessionRenewPath, s.handleSessionRenew)
+	mux.HandleFunc(sessionDestroyPath, s.handleSessionDestroy)
+	s.srv = httptest.NewServer(mux)
+	return s
+}
+
+// Address returns the address of the server.
+func (s *Server) Address() string {
+	return s.srv.URL
+}
+
+// Close stops the server, unblocking any outstanding blocking queries.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.Lock()
+		for _, se := range s.sessions {
+			se.timer.Stop()
+		}
+		s.Unlock()
+		s.srv.Close()
+	})
+}
+
+// ExpireSession invalidates a session as if its TTL had elapsed.
+func (s *Server) ExpireSession(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.invalidateWithLock(id)
+}
+
+func (s *Server) handleKV(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, kvPathPrefix)
+	params := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		s.handleKVGet(w, r, key)
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		flags, err := uintParam(params.Get("flags"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var cas *uint64
+		if _, ok := params["cas"]; ok {
+			idx, err := uintParam(params.Get("cas"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			cas = &idx
+		}
+
+		s.Lock()
+		ok, err := s.putWithLock(key, body, flags, cas, params.Get("acquire"), params.Get("release"))
+		s.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, ok)
+	case http.MethodDelete:
+		s.Lock()
+		defer s.Unlock()
+
+		if _, ok := params["recurse"]; ok {
+			for k := range s.kvs {
+				if strings.HasPrefix(k, key) {
+					delete(s.kvs, k)
+				}
+			}
+			s.bumpWithLock()
+			writeJSON(w, http.StatusOK, true)
+			return
+		}
+
+		existing, exists := s.kvs[key]
+		if _, ok := params["cas"]; ok {
+			idx, err := uintParam(params.Get("cas"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if !exists || existing.ModifyIndex != idx {
+				writeJSON(w, http.StatusOK, false)
+				return
+			}
+		}
+		if exists {
+			delete(s.kvs, key)
+			s.bumpWithLock()
+		}
+		writeJSON(w, http.StatusOK, true)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleKVGet(w http.ResponseWriter, r *http.Request, key string) {
+	params := r.URL.Query()
+	waitIndex, err := uintParam(params.Get("index"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	waitTime := defaultWaitTime
+	if v := params.Get("wait"); v != "" {
+		if waitTime, err = time.ParseDuration(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	s.waitForChange(r, waitIndex, waitTime)
+
+	s.Lock()
+	defer s.Unlock()
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(s.index, 10))
+
+	_, recurse := params["recurse"]
+	_, keysOnly := params["keys"]
+	if !recurse && !keysOnly {
+		pair, ok := s.kvs[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, []*consul.KVPair{pair})
+		return
+	}
+
+	var matched []string
+	for k := range s.kvs {
+		if strings.HasPrefix(k, key) {
+			matched = append(matched, k)
+		}
+	}
+	if len(matched) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	sort.Strings(matched)
+
+	if keysOnly {
+		writeJSON(w, http.StatusOK, matched)
+		return
+	}
+	pairs := make([]*consul.KVPair, 0, len(matched))
+	for _, k := range matched {
+		pairs = append(pairs, s.kvs[k])
+	}
+	writeJSON(w, http.StatusOK, pairs)
+}
+
+// waitForChange blocks until the index of the server exceeds waitIndex, the
+// wait time elapses, the request is cancelled or the server is closed. Like
+// Consul, any change wakes up all blocking queries and clients are expected to
+// handle spurious wake ups.
+func (s *Server) waitForChange(r *http.Request, waitIndex uint64, waitTime time.Duration) {
+	if waitIndex == 0 {
+		return
+	}
+
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
+	for {
+		s.Lock()
+		index, changed := s.index, s.changed
+		s.Unlock()
+		if index > waitIndex {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Server) putWithLock(
+	key string,
+	value []byte,
+	flags uint64,
+	cas *uint64,
+	acquire string,
+	release string,
+) (bool, error) {
+	existing, exists := s.kvs[key]
+	if cas != nil {
+		if *cas == 0 && exists {
+			return false, nil
+		}
+		if *cas != 0 && (!exists || existing.ModifyIndex != *cas) {
+			return false, nil
+		}
+	}
+
+	next := &consul.KVPair{Key: key}
+	if exists {
+		*next = *existing
+	}
+
+	switch {
+	case acquire != "":
+		if _, ok := s.sessions[acquire]; !ok {
+			return false, fmt.Errorf("invalid session %s", acquire)
+		}
+		if next.Session != "" && next.Session != acquire {
+			return false, nil
+		}
+		if next.Session == "" {
+			next.LockIndex++
+		}
+		next.Session = acquire
+	case release != "":
+		if next.Session != release {
+			return false, nil
+		}
+		next.Session = ""
+	}
+
+	s.bumpWithLock()
+	if !exists {
+		next.CreateIndex = s.index
+	}
+	next.ModifyIndex = s.index
+	next.Flags = flags
+	next.Value = value
+	s.kvs[key] = next
+	return true, nil
+}
+
+func (s *Server) handleTxn(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var ops []consul.TxnOp
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	var (
+		index   = s.index + 1
+		updated = make(map[string]*consul.KVPair, len(ops))
+		deleted = make(map[string]bool, len(ops))
+		results = make([]consul.TxnResult, 0, len(ops))
+		errs    []consul.TxnError
+	)
+	current := func(key string) (*consul.KVPair, bool) {
+		if deleted[key] {
+			return nil, false
+		}
+		if p, ok := updated[key]; ok {
+			return p, true
+		}
+		p, ok := s.kvs[key]
+		return p, ok
+	}
+
+	for i, op := range ops {
+		if op.KV == nil {
+			errs = append(errs, consul.TxnError{OpIndex: i, What: "unsupported operation"})
+			continue
+		}
+
+		kvOp := op.KV
+		existing, exists := current(kvOp.Key)
+		fail := func(what string) {
+			errs = append(errs, consul.TxnError{OpIndex: i, What: what})
+		}
+
+		switch kvOp.Verb {
+		case consul.TxnSet, consul.TxnCAS:
+			if kvOp.Verb == consul.TxnCAS {
+				if kvOp.Index == 0 && exists {
+					fail("failed to set key, key already exists")
+					continue
+				}
+				if kvOp.Index != 0 && (!exists || existing.ModifyIndex != kvOp.Index) {
+					fail("failed to set key, index is stale")
+					continue
+				}
+			}
+			next := &consul.KVPair{Key: kvOp.Key, CreateIndex: index}
+			if exists {
+				*next = *existing
+			}
+			next.ModifyIndex = index
+			next.Flags = kvOp.Flags
+			next.Value = kvOp.Value
+			updated[kvOp.Key] = next
+			delete(deleted, kvOp.Key)
+			results = append(results, consul.TxnResult{KV: withoutValue(next)})
+		case consul.TxnDelete, consul.TxnDeleteCAS:
+			if kvOp.Verb == consul.TxnDeleteCAS && (!exists || existing.ModifyIndex != kvOp.Index) {
+				fail("failed to delete key, index is stale")
+				continue
+			}
+			delete(updated, kvOp.Key)
+			deleted[kvOp.Key] = true
+		case consul.TxnDeleteTree:
+			for k := range s.kvs {
+				if strings.HasPrefix(k, kvOp.Key) {
+					delete(updated, k)
+					deleted[k] = true
+				}
+			}
+			for k := range updated {
+				if strings.HasPrefix(k, kvOp.Key) {
+					delete(updated, k)
+					deleted[k] = true
+				}
+			}
+		case consul.TxnCheckIndex:
+			if !exists || existing.ModifyIndex != kvOp.Index {
+				fail("current modify index does not match")
+				continue
+			}
+			results = append(results, consul.TxnResult{KV: withoutValue(existing)})
+		case consul.TxnCheckNotExists:
+			if exists {
+				fail("key already exists")
+			}
+		default:
+			fail(fmt.Sprintf("unknown kv verb %q", kvOp.Verb))
+		}
+	}
+
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusConflict, consul.TxnResponse{Errors: errs})
+		return
+	}
+
+	if len(updated) > 0 || len(deleted) > 0 {
+		for k := range deleted {
+			delete(s.kvs, k)
+		}
+		for k, p := range updated {
+			s.kvs[k] = p
+		}
+		s.bumpWithLock()
+	}
+	writeJSON(w, http.StatusOK, consul.TxnResponse{Results: results})
+}
+
+func (s *Server) handleSessionCreate(w http.ResponseWriter, r *http.Request) {
+	var entry consul.SessionEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	se := &session{behavior: entry.Behavior}
+	if se.behavior == "" {
+		se.behavior = consul.SessionBehaviorRelease
+	}
+	if entry.TTL != "" {
+		ttl, err := time.ParseDuration(entry.TTL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		se.ttl = ttl
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.nextSession++
+	id := fmt.Sprintf("session-%d", s.nextSession)
+	if se.ttl > 0 {
+		se.timer = time.AfterFunc(se.ttl, func() { s.ExpireSession(id) })
+	}
+	s.sessions[id] = se
+	writeJSON(w, http.StatusOK, consul.SessionEntry{ID: id})
+}
+
+func (s *Server) handleSessionRenew(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, sessionRenewPath)
+
+	s.Lock()
+	defer s.Unlock()
+
+	se, ok := s.sessions[id]
+	if !ok {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if se.timer != nil {
+		se.timer.Reset(se.ttl)
+	}
+	writeJSON(w, http.StatusOK, []consul.SessionEntry{{
+		ID:       id,
+		Behavior: se.behavior,
+		TTL:      se.ttl.String(),
+	}})
+}
+
+func (s *Server) handleSessionDestroy(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, sessionDestroyPath)
+
+	s.Lock()
+	s.invalidateWithLock(id)
+	s.Unlock()
+
+	writeJSON(w, http.StatusOK, true)
+}
+
+func (s *Server) invalidateWithLock(id string) bool {
+	se, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	if se.timer != nil {
+		se.timer.Stop()
+	}
+	delete(s.sessions, id)
+
+	for k, p := range s.kvs {
+		if p.Session != id {
+			continue
+		}
+		if se.behavior == consul.SessionBehaviorDelete {
+			delete(s.kvs, k)
+			continue
+		}
+		next := *p
+		next.Session = ""
+		next.ModifyIndex = s.index + 1
+		s.kvs[k] = &next
+	}
+	s.bumpWithLock()
+	return true
+}
+
+// bumpWithLock increments the index and wakes up all blocking queries.
+func (s *Server) bumpWithLock() {
+	s.index++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func withoutValue(p *consul.KVPair) *consul.KVPair {
+	res := *p
+	res.Value = nil
+	return &res
+}
+
+func uintParam(v string) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
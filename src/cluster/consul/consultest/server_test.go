@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consultest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/consul"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKV(t *testing.T) {
+	srv, cli := testServer(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	pair, meta, err := cli.Get(ctx, "foo", nil)
+	require.NoError(t, err)
+	require.Nil(t, pair)
+	require.True(t, meta.LastIndex > 0)
+
+	require.NoError(t, cli.Put(ctx, &consul.KVPair{Key: "foo", Value: []byte("bar"), Flags: 3}))
+	pair, _, err = cli.Get(ctx, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), pair.Value)
+	require.Equal(t, uint64(3), pair.Flags)
+
+	ok, err := cli.CAS(ctx, &consul.KVPair{Key: "foo", Value: []byte("baz")})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = cli.CAS(ctx, &consul.KVPair{Key: "foo", Value: []byte("baz"), ModifyIndex: pair.ModifyIndex})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, cli.Put(ctx, &consul.KVPair{Key: "foo/a"}))
+	keys, _, err := cli.Keys(ctx, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "foo/a"}, keys)
+
+	pairs, _, err := cli.List(ctx, "foo/", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pairs))
+	require.Equal(t, "foo/a", pairs[0].Key)
+
+	require.NoError(t, cli.Delete(ctx, "foo"))
+	pair, _, err = cli.Get(ctx, "foo", nil)
+	require.NoError(t, err)
+	require.Nil(t, pair)
+}
+
+func TestBlockingQuery(t *testing.T) {
+	srv, cli := testServer(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	_, meta, err := cli.Get(ctx, "foo", nil)
+	require.NoError(t, err)
+
+	// times out without any change
+	start := time.Now()
+	_, meta2, err := cli.Get(ctx, "foo", &consul.QueryOptions{
+		WaitIndex: meta.LastIndex,
+		WaitTime:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Equal(t, meta.LastIndex, meta2.LastIndex)
+	require.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cli.Put(ctx, &consul.KVPair{Key: "foo", Value: []byte("bar")})
+	}()
+
+	pair, meta2, err := cli.Get(ctx, "foo", &consul.QueryOptions{
+		WaitIndex: meta.LastIndex,
+		WaitTime:  time.Minute,
+	})
+	require.NoError(t, err)
+	require.True(t, meta2.LastIndex > meta.LastIndex)
+	require.Equal(t, []byte("bar"), pair.Value)
+}
+
+func TestTxn(t *testing.T) {
+	srv, cli := testServer(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	ok, resp, err := cli.Txn(ctx, []consul.TxnOp{
+		{KV: &consul.TxnKVOp{Verb: consul.TxnCheckNotExists, Key: "a"}},
+		{KV: &consul.TxnKVOp{Verb: consul.TxnCAS, Key: "a", Value: []byte("1")}},
+		{KV: &consul.TxnKVOp{Verb: consul.TxnSet, Key: "b/1", Value: []byte("2")}},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, len(resp.Results))
+
+	a, _, err := cli.Get(ctx, "a", nil)
+	require.NoError(t, err)
+
+	// a failed check rolls back the whole transaction
+	ok, resp, err = cli.Txn(ctx, []consul.TxnOp{
+		{KV: &consul.TxnKVOp{Verb: consul.TxnDeleteTree, Key: "b/"}},
+		{KV: &consul.TxnKVOp{Verb: consul.TxnCheckIndex, Key: "a", Index: a.ModifyIndex + 1}},
+	})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 1, len(resp.Errors))
+	require.Equal(t, 1, resp.Errors[0].OpIndex)
+
+	b, _, err := cli.Get(ctx, "b/1", nil)
+	require.NoError(t, err)
+	require.NotNil(t, b)
+
+	ok, _, err = cli.Txn(ctx, []consul.TxnOp{
+		{KV: &consul.TxnKVOp{Verb: consul.TxnDeleteTree, Key: "b/"}},
+		{KV: &consul.TxnKVOp{Verb: consul.TxnDeleteCAS, Key: "a", Index: a.ModifyIndex}},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	keys, _, err := cli.Keys(ctx, "", nil)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestSessionLocks(t *testing.T) {
+	srv, cli := testServer(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	s1, err := cli.CreateSession(ctx, &consul.SessionEntry{
+		Behavior: consul.SessionBehaviorRelease,
+		TTL:      "1m",
+	})
+	require.NoError(t, err)
+	s2, err := cli.CreateSession(ctx, &consul.SessionEntry{
+		Behavior: consul.SessionBehaviorDelete,
+		TTL:      "1m",
+	})
+	require.NoError(t, err)
+
+	ok, err := cli.Acquire(ctx, &consul.KVPair{Key: "lock", Value: []byte("s1"), Session: s1})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = cli.Acquire(ctx, &consul.KVPair{Key: "lock", Value: []byte("s2"), Session: s2})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = cli.Acquire(ctx, &consul.KVPair{Key: "other", Session: s2})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// invalidating a release session unlocks its keys
+	require.True(t, srv.ExpireSession(s1))
+	require.Equal(t, consul.ErrSessionNotFound, cli.RenewSession(ctx, s1))
+	pair, _, err := cli.Get(ctx, "lock", nil)
+	require.NoError(t, err)
+	require.Equal(t, "", pair.Session)
+
+	// destroying a delete session removes its keys
+	require.NoError(t, cli.RenewSession(ctx, s2))
+	require.NoError(t, cli.DestroySession(ctx, s2))
+	pair, _, err = cli.Get(ctx, "other", nil)
+	require.NoError(t, err)
+	require.Nil(t, pair)
+}
+
+func TestSessionTTL(t *testing.T) {
+	srv, cli := testServer(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	id, err := cli.CreateSession(ctx, &consul.SessionEntry{TTL: "100ms"})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, cli.RenewSession(ctx, id))
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, consul.ErrSessionNotFound, cli.RenewSession(ctx, id))
+}
+
+func testServer(t *testing.T) (*Server, consul.Client) {
+	srv := NewServer()
+	cli, err := consul.NewClient(consul.NewOptions().SetAddress(srv.Address()))
+	require.NoError(t, err)
+	return srv, cli
+}
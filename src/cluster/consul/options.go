@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultAddress = "http://127.0.0.1:8500"
+
+	// NB: blocking queries are held open by the server for up to the wait
+	// time so the client timeout must exceed the longest wait used.
+	defaultHTTPClientTimeout = 15 * time.Minute
+)
+
+var (
+	errNoAddress    = errors.New("no consul address set")
+	errNoHTTPClient = errors.New("no http client set")
+)
+
+type options struct {
+	address    string
+	datacenter string
+	token      string
+	httpClient *http.Client
+}
+
+// NewOptions creates a set of default Options.
+func NewOptions() Options {
+	return options{
+		address:    defaultAddress,
+		httpClient: &http.Client{Timeout: defaultHTTPClientTimeout},
+	}
+}
+
+func (o options) Validate() error {
+	if o.address == "" {
+		return errNoAddress
+	}
+	if o.httpClient == nil {
+		return errNoHTTPClient
+	}
+	return nil
+}
+
+func (o options) Address() string {
+	return o.address
+}
+
+func (o options) SetAddress(value string) Options {
+	o.address = value
+	return o
+}
+
+func (o options) Datacenter() string {
+	return o.datacenter
+}
+
+func (o options) SetDatacenter(value string) Options {
+	o.datacenter = value
+	return o
+}
+
+func (o options) Token() string {
+	return o.token
+}
+
+func (o options) SetToken(value string) Options {
+	o.token = value
+	return o
+}
+
+func (o options) HTTPClient() *http.Client {
+	return o.httpClient
+}
+
+func (o options) SetHTTPClient(value *http.Client) Options {
+	o.httpClient = value
+	return o
+}
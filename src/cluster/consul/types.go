@@ -0,0 +1,224 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package consul provides a minimal client for the subset of the Consul HTTP
+// API used by the Consul backed kv store, heartbeat and leader election
+// implementations.
+package consul
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrSessionNotFound is returned when renewing a session that has expired
+	// or has been destroyed.
+	ErrSessionNotFound = errors.New("consul: session not found")
+)
+
+// SessionBehavior controls what happens to the locks held by a session when it
+// is invalidated.
+type SessionBehavior string
+
+// List of supported session behaviors.
+const (
+	// SessionBehaviorRelease releases the locks held by the session.
+	SessionBehaviorRelease SessionBehavior = "release"
+
+	// SessionBehaviorDelete deletes the keys locked by the session.
+	SessionBehaviorDelete SessionBehavior = "delete"
+)
+
+// KVPair is a key value pair stored in Consul.
+type KVPair struct {
+	Key         string
+	CreateIndex uint64
+	ModifyIndex uint64
+	LockIndex   uint64
+	Flags       uint64
+	Value       []byte
+	Session     string `json:",omitempty"`
+}
+
+// QueryOptions are the options for read requests.
+type QueryOptions struct {
+	// WaitIndex turns the request into a blocking query which returns once the
+	// index of the result exceeds WaitIndex or WaitTime has elapsed.
+	WaitIndex uint64
+
+	// WaitTime is the maximum duration of a blocking query.
+	WaitTime time.Duration
+}
+
+// QueryMeta is the metadata returned by read requests.
+type QueryMeta struct {
+	// LastIndex is the index to pass as the WaitIndex of the next blocking
+	// query.
+	LastIndex uint64
+}
+
+// TxnVerb is the verb of a kv transaction operation.
+type TxnVerb string
+
+// List of supported transaction verbs.
+const (
+	// TxnSet sets the key.
+	TxnSet TxnVerb = "set"
+
+	// TxnCAS sets the key if its modify index matches the operation index.
+	TxnCAS TxnVerb = "cas"
+
+	// TxnDelete deletes the key.
+	TxnDelete TxnVerb = "delete"
+
+	// TxnDeleteTree deletes all keys starting with the operation key.
+	TxnDeleteTree TxnVerb = "delete-tree"
+
+	// TxnDeleteCAS deletes the key if its modify index matches the operation
+	// index.
+	TxnDeleteCAS TxnVerb = "delete-cas"
+
+	// TxnCheckIndex fails the transaction unless the modify index of the key
+	// matches the operation index.
+	TxnCheckIndex TxnVerb = "check-index"
+
+	// TxnCheckNotExists fails the transaction if the key exists.
+	TxnCheckNotExists TxnVerb = "check-not-exists"
+)
+
+// TxnKVOp is a kv operation in a transaction.
+type TxnKVOp struct {
+	Verb    TxnVerb
+	Key     string
+	Value   []byte `json:",omitempty"`
+	Flags   uint64 `json:",omitempty"`
+	Index   uint64 `json:",omitempty"`
+	Session string `json:",omitempty"`
+}
+
+// TxnOp is a single operation in a transaction.
+type TxnOp struct {
+	KV *TxnKVOp
+}
+
+// TxnResult is the result of a single operation of a successful transaction.
+type TxnResult struct {
+	KV *KVPair
+}
+
+// TxnError describes why a transaction was rolled back.
+type TxnError struct {
+	OpIndex int
+	What    string
+}
+
+// TxnResponse is the response of a transaction.
+type TxnResponse struct {
+	Results []TxnResult
+	Errors  []TxnError
+}
+
+// SessionEntry describes a session.
+type SessionEntry struct {
+	ID        string          `json:",omitempty"`
+	Name      string          `json:",omitempty"`
+	Behavior  SessionBehavior `json:",omitempty"`
+	TTL       string          `json:",omitempty"`
+	LockDelay string          `json:",omitempty"`
+}
+
+// Client is a client for the Consul HTTP API.
+type Client interface {
+	// Get returns the pair stored under the key, or nil if the key does not
+	// exist.
+	Get(ctx context.Context, key string, q *QueryOptions) (*KVPair, QueryMeta, error)
+
+	// List returns all pairs whose key starts with the prefix.
+	List(ctx context.Context, prefix string, q *QueryOptions) ([]*KVPair, QueryMeta, error)
+
+	// Keys returns all keys that start with the prefix.
+	Keys(ctx context.Context, prefix string, q *QueryOptions) ([]string, QueryMeta, error)
+
+	// Put stores the pair unconditionally.
+	Put(ctx context.Context, p *KVPair) error
+
+	// CAS stores the pair if the modify index of the existing key matches
+	// p.ModifyIndex, a zero index only succeeds when the key does not exist.
+	CAS(ctx context.Context, p *KVPair) (bool, error)
+
+	// Acquire stores the pair and locks it with p.Session if no other
+	// session holds the lock.
+	Acquire(ctx context.Context, p *KVPair) (bool, error)
+
+	// Release stores the pair and releases the lock held by p.Session.
+	Release(ctx context.Context, p *KVPair) (bool, error)
+
+	// Delete deletes the key.
+	Delete(ctx context.Context, key string) error
+
+	// DeleteCAS deletes the key if its modify index matches p.ModifyIndex.
+	DeleteCAS(ctx context.Context, p *KVPair) (bool, error)
+
+	// Txn atomically applies the operations. The returned bool is false if the
+	// transaction was rolled back, in which case the response lists the
+	// operations that failed.
+	Txn(ctx context.Context, ops []TxnOp) (bool, *TxnResponse, error)
+
+	// CreateSession creates a session and returns its ID.
+	CreateSession(ctx context.Context, se *SessionEntry) (string, error)
+
+	// RenewSession renews the TTL of a session, ErrSessionNotFound is returned
+	// if the session has been invalidated.
+	RenewSession(ctx context.Context, id string) error
+
+	// DestroySession destroys a session, releasing or deleting its locks
+	// according to the session behavior.
+	DestroySession(ctx context.Context, id string) error
+}
+
+// Options are the options for the Consul client.
+type Options interface {
+	// Address is the address of the Consul agent, e.g. http://127.0.0.1:8500.
+	Address() string
+	// SetAddress sets the Address.
+	SetAddress(value string) Options
+
+	// Datacenter is the datacenter requests are issued against, the agent's
+	// datacenter is used if empty.
+	Datacenter() string
+	// SetDatacenter sets the Datacenter.
+	SetDatacenter(value string) Options
+
+	// Token is the ACL token sent with each request.
+	Token() string
+	// SetToken sets the Token.
+	SetToken(value string) Options
+
+	// HTTPClient is the HTTP client used to issue requests.
+	HTTPClient() *http.Client
+	// SetHTTPClient sets the HTTPClient.
+	SetHTTPClient(value *http.Client) Options
+
+	// Validate validates the Options.
+	Validate() error
+}
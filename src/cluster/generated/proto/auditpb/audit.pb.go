@@ -0,0 +1,486 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: github.com/m3db/m3/src/cluster/generated/proto/auditpb/audit.proto
+
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+/*
+Package auditpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	github.com/m3db/m3/src/cluster/generated/proto/auditpb/audit.proto
+
+It has these top-level messages:
+
+	Envelope
+*/
+package auditpb
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type Envelope struct {
+	Author          string `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Reason          string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	TimestampNanos  int64  `protobuf:"varint,3,opt,name=timestamp_nanos,json=timestampNanos,proto3" json:"timestamp_nanos,omitempty"`
+	RollbackVersion int32  `protobuf:"varint,4,opt,name=rollback_version,json=rollbackVersion,proto3" json:"rollback_version,omitempty"`
+	DeletedVersion  int32  `protobuf:"varint,5,opt,name=deleted_version,json=deletedVersion,proto3" json:"deleted_version,omitempty"`
+}
+
+func (m *Envelope) Reset()                    { *m = Envelope{} }
+func (m *Envelope) String() string            { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()               {}
+func (*Envelope) Descriptor() ([]byte, []int) { return fileDescriptorAudit, []int{0} }
+
+func (m *Envelope) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *Envelope) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Envelope) GetTimestampNanos() int64 {
+	if m != nil {
+		return m.TimestampNanos
+	}
+	return 0
+}
+
+func (m *Envelope) GetRollbackVersion() int32 {
+	if m != nil {
+		return m.RollbackVersion
+	}
+	return 0
+}
+
+func (m *Envelope) GetDeletedVersion() int32 {
+	if m != nil {
+		return m.DeletedVersion
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Envelope)(nil), "auditpb.Envelope")
+}
+func (m *Envelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Envelope) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Author) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.Author)))
+		i += copy(dAtA[i:], m.Author)
+	}
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	if m.TimestampNanos != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintAudit(dAtA, i, uint64(m.TimestampNanos))
+	}
+	if m.RollbackVersion != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintAudit(dAtA, i, uint64(m.RollbackVersion))
+	}
+	if m.DeletedVersion != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintAudit(dAtA, i, uint64(m.DeletedVersion))
+	}
+	return i, nil
+}
+
+func encodeVarintAudit(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Envelope) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Author)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	if m.TimestampNanos != 0 {
+		n += 1 + sovAudit(uint64(m.TimestampNanos))
+	}
+	if m.RollbackVersion != 0 {
+		n += 1 + sovAudit(uint64(m.RollbackVersion))
+	}
+	if m.DeletedVersion != 0 {
+		n += 1 + sovAudit(uint64(m.DeletedVersion))
+	}
+	return n
+}
+
+func sovAudit(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozAudit(x uint64) (n int) {
+	return sovAudit(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Envelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAudit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Envelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Envelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Author = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampNanos", wireType)
+			}
+			m.TimestampNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollbackVersion", wireType)
+			}
+			m.RollbackVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RollbackVersion |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeletedVersion", wireType)
+			}
+			m.DeletedVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeletedVersion |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAudit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipAudit(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowAudit
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthAudit
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowAudit
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipAudit(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthAudit = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowAudit   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/cluster/generated/proto/auditpb/audit.proto", fileDescriptorAudit)
+}
+
+var fileDescriptorAudit = []byte{
+	// 226 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3d, 0x8f, 0x3b, 0x0e, 0xc2, 0x30,
+	0x0c, 0x86, 0x29, 0x6f, 0x32, 0x50, 0xd4, 0x01, 0x75, 0xaa, 0x10, 0x0b, 0xb0, 0x90, 0xa1, 0x37,
+	0x40, 0x62, 0x65, 0xe8, 0xc0, 0x8a, 0x92, 0xd4, 0x6a, 0x2b, 0xd2, 0xa4, 0x4a, 0x52, 0xce, 0xc1,
+	0x6d, 0xb8, 0x02, 0x23, 0x47, 0x40, 0x70, 0x11, 0xd2, 0x07, 0x1d, 0x6c, 0xeb, 0xff, 0xf4, 0xc9,
+	0xb2, 0xd1, 0x21, 0xc9, 0x4c, 0x5a, 0xd2, 0x3d, 0x93, 0x39, 0xce, 0xc3, 0x98, 0xda, 0x86, 0xb5,
+	0x62, 0x98, 0xf1, 0x52, 0x1b, 0x50, 0x38, 0x01, 0x01, 0x8a, 0x18, 0x88, 0x71, 0xa1, 0xa4, 0x91,
+	0x98, 0x94, 0x71, 0x66, 0x0a, 0xda, 0xcc, 0x7d, 0xcd, 0xbc, 0x49, 0x0b, 0xd7, 0x0f, 0x07, 0x4d,
+	0x8f, 0xe2, 0x06, 0x5c, 0x16, 0xe0, 0x2d, 0xd1, 0x98, 0x94, 0x26, 0x95, 0xca, 0x77, 0x56, 0xce,
+	0x76, 0x16, 0xb5, 0xa9, 0xe2, 0x0a, 0x88, 0x96, 0xc2, 0xef, 0x37, 0xbc, 0x49, 0xde, 0x06, 0xb9,
+	0x26, 0xcb, 0x41, 0x1b, 0x92, 0x17, 0x17, 0x41, 0x84, 0xd4, 0xfe, 0xc0, 0x0a, 0x83, 0x68, 0xde,
+	0xe1, 0x53, 0x45, 0xbd, 0x1d, 0x5a, 0x28, 0xc9, 0x39, 0x25, 0xec, 0x7a, 0xb9, 0x81, 0xd2, 0x99,
+	0x5d, 0x35, 0xb4, 0xe6, 0x28, 0x72, 0xff, 0xfc, 0xdc, 0xe0, 0x6a, 0x67, 0x0c, 0x1c, 0xec, 0xf9,
+	0x9d, 0x39, 0xaa, 0xcd, 0x79, 0x8b, 0x5b, 0xf1, 0xb0, 0x78, 0x7e, 0x02, 0xe7, 0x65, 0xeb, 0x6d,
+	0xeb, 0xfe, 0x0d, 0x7a, 0x74, 0x5c, 0xff, 0x16, 0xfe, 0x00, 0xb4, 0x2d, 0xf1, 0x91, 0x21, 0x01,
+	0x00, 0x00,
+}
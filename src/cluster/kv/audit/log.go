@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/kv"
+)
+
+var (
+	errNilEnvelope = errors.New("nil audit envelope")
+)
+
+type log struct {
+	store kv.Store
+	opts  Options
+}
+
+// NewLog returns an audit log that stores envelopes in the given store.
+func NewLog(store kv.Store, opts Options) (Log, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &log{
+		store: store,
+		opts:  opts,
+	}, nil
+}
+
+func (l *log) Record(key string, version int, e *auditpb.Envelope) error {
+	if e == nil {
+		return errNilEnvelope
+	}
+	// NB: versions restart from one when a key is deleted and recreated, so
+	// the envelope overwrites any recorded for the version before the delete.
+	_, err := l.store.Set(l.envelopeKey(key, version), l.withTimestamp(e))
+	return err
+}
+
+func (l *log) RecordDelete(key string, version int, e *auditpb.Envelope) error {
+	if e == nil {
+		return errNilEnvelope
+	}
+	e = l.withTimestamp(e)
+	if e.DeletedVersion != int32(version) {
+		cloned := *e
+		cloned.DeletedVersion = int32(version)
+		e = &cloned
+	}
+	_, err := l.store.Set(l.deleteKey(key), e)
+	return err
+}
+
+func (l *log) Envelope(key string, version int) (*auditpb.Envelope, error) {
+	return l.get(l.envelopeKey(key, version))
+}
+
+func (l *log) DeleteEnvelope(key string) (*auditpb.Envelope, error) {
+	return l.get(l.deleteKey(key))
+}
+
+func (l *log) get(key string) (*auditpb.Envelope, error) {
+	v, err := l.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var e auditpb.Envelope
+	if err := v.Unmarshal(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (l *log) withTimestamp(e *auditpb.Envelope) *auditpb.Envelope {
+	if e.TimestampNanos != 0 {
+		return e
+	}
+	cloned := *e
+	cloned.TimestampNanos = l.opts.NowFn()().UnixNano()
+	return &cloned
+}
+
+func (l *log) envelopeKey(key string, version int) string {
+	return fmt.Sprintf("%s/%s/%d", l.opts.KeyPrefix(), key, version)
+}
+
+// deleteKey is the key of the envelope of the last delete of a key, earlier
+// deletes remain in its kv history.
+func (l *log) deleteKey(key string) string {
+	return fmt.Sprintf("%s/%s/deleted", l.opts.KeyPrefix(), key)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	opts := NewOptions()
+	require.Equal(t, defaultKeyPrefix, opts.KeyPrefix())
+	require.NotNil(t, opts.NowFn())
+	require.NoError(t, opts.Validate())
+
+	require.Error(t, opts.SetKeyPrefix("").Validate())
+	require.Error(t, opts.SetNowFn(nil).Validate())
+	require.Error(t, opts.SetInstrumentOptions(nil).Validate())
+}
+
+func TestLogRecordAndEnvelope(t *testing.T) {
+	now := time.Unix(1234, 0)
+	store := mem.NewStore()
+	l, err := NewLog(store, NewOptions().SetNowFn(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	_, err = l.Envelope("foo", 1)
+	require.Equal(t, kv.ErrNotFound, err)
+
+	require.NoError(t, l.Record("foo", 1, &auditpb.Envelope{Author: "a", Reason: "r"}))
+	e, err := l.Envelope("foo", 1)
+	require.NoError(t, err)
+	require.Equal(t, "a", e.Author)
+	require.Equal(t, "r", e.Reason)
+	require.Equal(t, now.UnixNano(), e.TimestampNanos)
+
+	// Explicit timestamps are kept.
+	require.NoError(t, l.Record("foo", 2, &auditpb.Envelope{Author: "b", TimestampNanos: 42}))
+	e, err = l.Envelope("foo", 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), e.TimestampNanos)
+
+	// Recording a version again, as happens once a key is deleted and
+	// recreated, replaces its envelope.
+	require.NoError(t, l.Record("foo", 1, &auditpb.Envelope{Author: "c"}))
+	e, err = l.Envelope("foo", 1)
+	require.NoError(t, err)
+	require.Equal(t, "c", e.Author)
+	require.Equal(t, errNilEnvelope, l.Record("foo", 3, nil))
+
+	_, err = store.Get("_audit/foo/1")
+	require.NoError(t, err)
+}
+
+func TestLogRecordDelete(t *testing.T) {
+	now := time.Unix(1234, 0)
+	store := mem.NewStore()
+	l, err := NewLog(store, NewOptions().SetNowFn(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	_, err = l.DeleteEnvelope("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	e := &auditpb.Envelope{Author: "a", Reason: "r"}
+	require.NoError(t, l.RecordDelete("foo", 3, e))
+	deleted, err := l.DeleteEnvelope("foo")
+	require.NoError(t, err)
+	require.Equal(t, "a", deleted.Author)
+	require.Equal(t, int32(3), deleted.DeletedVersion)
+	require.Equal(t, now.UnixNano(), deleted.TimestampNanos)
+
+	// The recorded envelope is not modified.
+	require.Equal(t, int32(0), e.DeletedVersion)
+
+	// Later deletes replace the last delete, earlier ones stay in history.
+	require.NoError(t, l.RecordDelete("foo", 1, e))
+	deleted, err = l.DeleteEnvelope("foo")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), deleted.DeletedVersion)
+
+	history, err := store.History("_audit/foo/deleted", 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(history))
+	require.Equal(t, errNilEnvelope, l.RecordDelete("foo", 1, nil))
+}
+
+func TestRollback(t *testing.T) {
+	store := mem.NewStore()
+	for _, s := range []string{"a", "b", "c"} {
+		_, err := store.Set("foo", &commonpb.StringProto{Value: s})
+		require.NoError(t, err)
+	}
+
+	version, err := Rollback(store, "foo", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, version)
+
+	v, err := store.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, 4, v.Version())
+	var msg commonpb.StringProto
+	require.NoError(t, v.Unmarshal(&msg))
+	require.Equal(t, "a", msg.Value)
+
+	_, err = Rollback(store, "foo", 4, nil)
+	require.Equal(t, ErrInvalidRollbackVersion, err)
+	_, err = Rollback(store, "foo", 0, nil)
+	require.Equal(t, ErrInvalidRollbackVersion, err)
+	_, err = Rollback(store, "bar", 1, nil)
+	require.Equal(t, kv.ErrNotFound, err)
+}
+
+func TestRollbackValidates(t *testing.T) {
+	store := mem.NewStore()
+	for _, s := range []string{"", "b"} {
+		_, err := store.Set("foo", &commonpb.StringProto{Value: s})
+		require.NoError(t, err)
+	}
+
+	validateFn := func(v kv.Value) error {
+		var msg commonpb.StringProto
+		if err := v.Unmarshal(&msg); err != nil {
+			return err
+		}
+		if msg.Value == "" {
+			return errors.New("empty value")
+		}
+		return nil
+	}
+	_, err := Rollback(store, "foo", 1, validateFn)
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	// Invalid values are not written back.
+	v, err := store.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, 2, v.Version())
+}
+
+func TestValueAt(t *testing.T) {
+	store := mem.NewStore()
+	_, err := store.Set("foo", &commonpb.StringProto{Value: "a"})
+	require.NoError(t, err)
+
+	raw, err := ValueAt(store, "foo", 1)
+	require.NoError(t, err)
+
+	var msg commonpb.StringProto
+	require.NoError(t, msg.Unmarshal(raw.Bytes()))
+	require.Equal(t, "a", msg.Value)
+
+	_, err = ValueAt(store, "foo", 2)
+	require.Error(t, err)
+}
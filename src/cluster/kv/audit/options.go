@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultKeyPrefix = "_audit"
+)
+
+var (
+	errNoKeyPrefix = errors.New("no audit key prefix set")
+	errNoNowFn     = errors.New("no now fn set")
+	errNoIOpts     = errors.New("no instrument options set")
+)
+
+// Options is a set of options for the audit log.
+type Options interface {
+	// SetKeyPrefix sets the prefix under which audit envelopes are stored.
+	SetKeyPrefix(value string) Options
+
+	// KeyPrefix returns the prefix under which audit envelopes are stored.
+	KeyPrefix() string
+
+	// SetNowFn sets the function used to timestamp envelopes.
+	SetNowFn(value func() time.Time) Options
+
+	// NowFn returns the function used to timestamp envelopes.
+	NowFn() func() time.Time
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// Validate validates the options.
+	Validate() error
+}
+
+type options struct {
+	keyPrefix string
+	nowFn     func() time.Time
+	iOpts     instrument.Options
+}
+
+// NewOptions returns a default set of audit log options.
+func NewOptions() Options {
+	return options{
+		keyPrefix: defaultKeyPrefix,
+		nowFn:     time.Now,
+		iOpts:     instrument.NewOptions(),
+	}
+}
+
+func (o options) SetKeyPrefix(value string) Options {
+	o.keyPrefix = value
+	return o
+}
+
+func (o options) KeyPrefix() string {
+	return o.keyPrefix
+}
+
+func (o options) SetNowFn(value func() time.Time) Options {
+	o.nowFn = value
+	return o
+}
+
+func (o options) NowFn() func() time.Time {
+	return o.nowFn
+}
+
+func (o options) SetInstrumentOptions(value instrument.Options) Options {
+	o.iOpts = value
+	return o
+}
+
+func (o options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
+
+func (o options) Validate() error {
+	if o.keyPrefix == "" {
+		return errNoKeyPrefix
+	}
+	if o.nowFn == nil {
+		return errNoNowFn
+	}
+	if o.iOpts == nil {
+		return errNoIOpts
+	}
+	return nil
+}
+
+// Log records who changed a versioned kv key and why. Envelopes are stored
+// alongside the audited key rather than inside its value so that the value
+// format of existing keys is left untouched.
+type Log interface {
+	// Record stores the envelope for the given version of a key, replacing
+	// any envelope recorded for the same version before the key was deleted
+	// and recreated.
+	Record(key string, version int, e *auditpb.Envelope) error
+
+	// RecordDelete stores the envelope of a delete of a key, which held the
+	// given version when it was deleted.
+	RecordDelete(key string, version int, e *auditpb.Envelope) error
+
+	// Envelope returns the envelope recorded for the given version of a key,
+	// or kv.ErrNotFound if the version was not recorded.
+	Envelope(key string, version int) (*auditpb.Envelope, error)
+
+	// DeleteEnvelope returns the envelope of the last delete of a key, or
+	// kv.ErrNotFound if no delete was recorded.
+	DeleteEnvelope(key string) (*auditpb.Envelope, error)
+}
+
+// IDFn maps a kv key to the id its changes are recorded under, or to the
+// empty string if changes to the key are not recorded.
+type IDFn func(key string) string
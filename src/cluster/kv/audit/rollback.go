@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+var (
+	// ErrInvalidRollbackVersion is returned when rolling back to a version that is
+	// not older than the current version.
+	ErrInvalidRollbackVersion = errors.New("rollback version must be older than the current version")
+)
+
+// ValidateFn validates a value before it is rolled back to.
+type ValidateFn func(v kv.Value) error
+
+// Rollback restores the value a key held at the given version by writing it
+// back as a new version. The old value is checked with validateFn, if set,
+// before it is written, and an invalid value fails the rollback with an
+// invalid params error. The write is a check and set against the current
+// version, so a concurrent update fails the rollback with
+// kv.ErrVersionMismatch. It returns the newly written version.
+func Rollback(
+	store kv.Store,
+	key string,
+	version int,
+	validateFn ValidateFn,
+) (int, error) {
+	current, err := store.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if version < 1 || version >= current.Version() {
+		return 0, ErrInvalidRollbackVersion
+	}
+
+	v, err := valueAt(store, key, version)
+	if err != nil {
+		return 0, err
+	}
+	if validateFn != nil {
+		if err := validateFn(v); err != nil {
+			return 0, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"invalid value at version %d of key %s: %v", version, key, err))
+		}
+	}
+	var old RawValue
+	if err := v.Unmarshal(&old); err != nil {
+		return 0, err
+	}
+	return store.CheckAndSet(key, current.Version(), &old)
+}
+
+// ValueAt returns the raw encoded value a key held at the given version.
+func ValueAt(store kv.Store, key string, version int) (*RawValue, error) {
+	v, err := valueAt(store, key, version)
+	if err != nil {
+		return nil, err
+	}
+	var raw RawValue
+	if err := v.Unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	return &raw, nil
+}
+
+func valueAt(store kv.Store, key string, version int) (kv.Value, error) {
+	values, err := store.History(key, version, version+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("version %d of key %s not found", version, key)
+	}
+	return values[0], nil
+}
+
+// RawValue is a proto message that carries already encoded bytes, used to
+// copy a value between versions without knowing its concrete type.
+type RawValue struct {
+	data []byte
+}
+
+// Bytes returns the encoded value.
+func (v *RawValue) Bytes() []byte { return v.data }
+
+// Reset resets the value.
+func (v *RawValue) Reset() { v.data = nil }
+
+// String returns a description of the value.
+func (v *RawValue) String() string { return fmt.Sprintf("RawValue(%d bytes)", len(v.data)) }
+
+// ProtoMessage marks the value as a proto message.
+func (v *RawValue) ProtoMessage() {}
+
+// Marshal returns the encoded value.
+func (v *RawValue) Marshal() ([]byte, error) { return append([]byte(nil), v.data...), nil }
+
+// Unmarshal stores a copy of the encoded value.
+func (v *RawValue) Unmarshal(data []byte) error {
+	v.data = append([]byte(nil), data...)
+	return nil
+}
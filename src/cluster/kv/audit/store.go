@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/kv"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+// store records an envelope for every version written and every delete made
+// through it.
+type store struct {
+	kv.Store
+
+	log    Log
+	idFn   IDFn
+	e      *auditpb.Envelope
+	logger *zap.Logger
+}
+
+// NewStore returns a store that records the envelope in the log for every
+// version it writes to, and every delete of, a key idFn maps to an id. Because the envelope is
+// recorded against the version the write returned, concurrent writes by
+// other clients are never attributed to it. Failing to record a change does
+// not fail the write, which has already been applied.
+func NewStore(
+	s kv.Store,
+	log Log,
+	idFn IDFn,
+	e *auditpb.Envelope,
+	opts Options,
+) kv.Store {
+	return &store{
+		Store:  s,
+		log:    log,
+		idFn:   idFn,
+		e:      e,
+		logger: opts.InstrumentOptions().Logger(),
+	}
+}
+
+func (s *store) Set(key string, v proto.Message) (int, error) {
+	version, err := s.Store.Set(key, v)
+	if err == nil {
+		s.record(key, version)
+	}
+	return version, err
+}
+
+func (s *store) SetIfNotExists(key string, v proto.Message) (int, error) {
+	version, err := s.Store.SetIfNotExists(key, v)
+	if err == nil {
+		s.record(key, version)
+	}
+	return version, err
+}
+
+func (s *store) CheckAndSet(key string, version int, v proto.Message) (int, error) {
+	newVersion, err := s.Store.CheckAndSet(key, version, v)
+	if err == nil {
+		s.record(key, newVersion)
+	}
+	return newVersion, err
+}
+
+func (s *store) Delete(key string) (kv.Value, error) {
+	v, err := s.Store.Delete(key)
+	if err == nil {
+		s.recordDelete(key, v.Version())
+	}
+	return v, err
+}
+
+func (s *store) record(key string, version int) {
+	id := s.idFn(key)
+	if id == "" {
+		return
+	}
+	if err := s.log.Record(id, version, s.e); err != nil {
+		s.logger.Warn("unable to record change",
+			zap.String("id", id), zap.Int("version", version), zap.Error(err))
+	}
+}
+
+func (s *store) recordDelete(key string, version int) {
+	id := s.idFn(key)
+	if id == "" {
+		return
+	}
+	if err := s.log.RecordDelete(id, version, s.e); err != nil {
+		s.logger.Warn("unable to record delete",
+			zap.String("id", id), zap.Int("version", version), zap.Error(err))
+	}
+}
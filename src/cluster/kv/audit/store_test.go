@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordsWrites(t *testing.T) {
+	var (
+		opts    = NewOptions()
+		backing = mem.NewStore()
+		idFn    = func(key string) string {
+			if key != "foo" {
+				return ""
+			}
+			return "kind/foo"
+		}
+	)
+	l, err := NewLog(backing, opts)
+	require.NoError(t, err)
+
+	// A write by another client is not recorded.
+	_, err = backing.Set("foo", &commonpb.StringProto{Value: "a"})
+	require.NoError(t, err)
+
+	s := NewStore(backing, l, idFn, &auditpb.Envelope{Author: "a", Reason: "r"}, opts)
+	_, err = s.Set("foo", &commonpb.StringProto{Value: "b"})
+	require.NoError(t, err)
+	_, err = s.CheckAndSet("foo", 2, &commonpb.StringProto{Value: "c"})
+	require.NoError(t, err)
+	_, err = s.SetIfNotExists("bar", &commonpb.StringProto{Value: "d"})
+	require.NoError(t, err)
+
+	_, err = l.Envelope("kind/foo", 1)
+	require.Equal(t, kv.ErrNotFound, err)
+	for _, version := range []int{2, 3} {
+		e, err := l.Envelope("kind/foo", version)
+		require.NoError(t, err)
+		require.Equal(t, "a", e.Author)
+		require.Equal(t, "r", e.Reason)
+	}
+
+	// Failed writes are not recorded.
+	_, err = s.CheckAndSet("foo", 2, &commonpb.StringProto{Value: "e"})
+	require.Equal(t, kv.ErrVersionMismatch, err)
+	_, err = l.Envelope("kind/foo", 4)
+	require.Equal(t, kv.ErrNotFound, err)
+}
+
+func TestStoreRecordsDeletes(t *testing.T) {
+	var (
+		opts    = NewOptions()
+		backing = mem.NewStore()
+		idFn    = func(key string) string { return "kind/" + key }
+	)
+	l, err := NewLog(backing, opts)
+	require.NoError(t, err)
+
+	first := NewStore(backing, l, idFn, &auditpb.Envelope{Author: "a"}, opts)
+	for _, v := range []string{"a", "b"} {
+		_, err = first.Set("foo", &commonpb.StringProto{Value: v})
+		require.NoError(t, err)
+	}
+	_, err = first.Delete("foo")
+	require.NoError(t, err)
+
+	deleted, err := l.DeleteEnvelope("kind/foo")
+	require.NoError(t, err)
+	require.Equal(t, "a", deleted.Author)
+	require.Equal(t, int32(2), deleted.DeletedVersion)
+
+	// Versions restart once the key is recreated, which must not be
+	// attributed to the writes before the delete.
+	second := NewStore(backing, l, idFn, &auditpb.Envelope{Author: "b"}, opts)
+	version, err := second.SetIfNotExists("foo", &commonpb.StringProto{Value: "c"})
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	e, err := l.Envelope("kind/foo", version)
+	require.NoError(t, err)
+	require.Equal(t, "b", e.Author)
+
+	// Failed deletes are not recorded.
+	_, err = second.Delete("bar")
+	require.Equal(t, kv.ErrNotFound, err)
+	_, err = l.DeleteEnvelope("kind/bar")
+	require.Equal(t, kv.ErrNotFound, err)
+}
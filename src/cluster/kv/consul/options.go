@@ -0,0 +1,214 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	defaultRequestTimeout     = 10 * time.Second
+	defaultWatchWaitTime      = time.Minute
+	defaultWatchCheckInterval = 10 * time.Second
+	defaultWatchRetryInterval = 10 * time.Second
+	defaultHistoryPrefix      = "_history"
+)
+
+// Options are options for the client of the kv store
+type Options interface {
+	// RequestTimeout is the timeout for consul requests
+	RequestTimeout() time.Duration
+	// SetRequestTimeout sets the RequestTimeout
+	SetRequestTimeout(t time.Duration) Options
+
+	// InstrumentsOptions is the instrument options
+	InstrumentsOptions() instrument.Options
+	// SetInstrumentsOptions sets the InstrumentsOptions
+	SetInstrumentsOptions(iopts instrument.Options) Options
+
+	// WatchWaitTime is the maximum duration of the blocking queries used to
+	// watch for updates
+	WatchWaitTime() time.Duration
+	// SetWatchWaitTime sets the WatchWaitTime
+	SetWatchWaitTime(t time.Duration) Options
+
+	// WatchCheckInterval will be used to periodically check if a watch is no
+	// longer being subscribed and should be stopped
+	WatchCheckInterval() time.Duration
+	// SetWatchCheckInterval sets the WatchCheckInterval
+	SetWatchCheckInterval(t time.Duration) Options
+
+	// WatchRetryInterval is the delay before retrying a failed blocking query
+	WatchRetryInterval() time.Duration
+	// SetWatchRetryInterval sets the WatchRetryInterval
+	SetWatchRetryInterval(t time.Duration) Options
+
+	// Prefix is the prefix for each key
+	Prefix() string
+	// SetPrefix sets the prefix
+	SetPrefix(s string) Options
+	// ApplyPrefix applies the prefix to the key
+	ApplyPrefix(key string) string
+
+	// HistoryPrefix is the prefix under which previous versions of each key
+	// are kept to serve History requests
+	HistoryPrefix() string
+	// SetHistoryPrefix sets the HistoryPrefix
+	SetHistoryPrefix(s string) Options
+
+	// HistoryRetention is the number of versions kept for each key, zero
+	// keeps every version until the key is deleted
+	HistoryRetention() int
+	// SetHistoryRetention sets the HistoryRetention
+	SetHistoryRetention(n int) Options
+
+	// Validate validates the Options
+	Validate() error
+}
+
+type options struct {
+	requestTimeout     time.Duration
+	prefix             string
+	iopts              instrument.Options
+	watchWaitTime      time.Duration
+	watchCheckInterval time.Duration
+	watchRetryInterval time.Duration
+	historyPrefix      string
+	historyRetention   int
+}
+
+// NewOptions creates a sane default Option
+func NewOptions() Options {
+	o := options{}
+	return o.SetRequestTimeout(defaultRequestTimeout).
+		SetInstrumentsOptions(instrument.NewOptions()).
+		SetWatchWaitTime(defaultWatchWaitTime).
+		SetWatchCheckInterval(defaultWatchCheckInterval).
+		SetWatchRetryInterval(defaultWatchRetryInterval).
+		SetHistoryPrefix(defaultHistoryPrefix)
+}
+
+func (o options) Validate() error {
+	if o.iopts == nil {
+		return errors.New("no instrument options")
+	}
+
+	if o.watchWaitTime <= 0 {
+		return errors.New("invalid watch wait time")
+	}
+
+	if o.watchCheckInterval <= 0 {
+		return errors.New("invalid watch check interval")
+	}
+
+	if o.historyPrefix == "" {
+		return errors.New("no history prefix")
+	}
+
+	if o.historyRetention < 0 {
+		return errors.New("invalid history retention")
+	}
+
+	return nil
+}
+
+func (o options) RequestTimeout() time.Duration {
+	return o.requestTimeout
+}
+
+func (o options) SetRequestTimeout(t time.Duration) Options {
+	o.requestTimeout = t
+	return o
+}
+
+func (o options) InstrumentsOptions() instrument.Options {
+	return o.iopts
+}
+
+func (o options) SetInstrumentsOptions(iopts instrument.Options) Options {
+	o.iopts = iopts
+	return o
+}
+
+func (o options) WatchWaitTime() time.Duration {
+	return o.watchWaitTime
+}
+
+func (o options) SetWatchWaitTime(t time.Duration) Options {
+	o.watchWaitTime = t
+	return o
+}
+
+func (o options) WatchCheckInterval() time.Duration {
+	return o.watchCheckInterval
+}
+
+func (o options) SetWatchCheckInterval(t time.Duration) Options {
+	o.watchCheckInterval = t
+	return o
+}
+
+func (o options) WatchRetryInterval() time.Duration {
+	return o.watchRetryInterval
+}
+
+func (o options) SetWatchRetryInterval(t time.Duration) Options {
+	o.watchRetryInterval = t
+	return o
+}
+
+func (o options) Prefix() string {
+	return o.prefix
+}
+
+func (o options) SetPrefix(prefix string) Options {
+	o.prefix = prefix
+	return o
+}
+
+func (o options) ApplyPrefix(key string) string {
+	if o.prefix == "" {
+		return key
+	}
+	return fmt.Sprintf("%s/%s", o.prefix, key)
+}
+
+func (o options) HistoryPrefix() string {
+	return o.historyPrefix
+}
+
+func (o options) SetHistoryPrefix(prefix string) Options {
+	o.historyPrefix = prefix
+	return o
+}
+
+func (o options) HistoryRetention() int {
+	return o.historyRetention
+}
+
+func (o options) SetHistoryRetention(n int) Options {
+	o.historyRetention = n
+	return o
+}
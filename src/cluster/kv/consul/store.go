@@ -0,0 +1,629 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/kv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	consulVersionZero = 0
+
+	// maxConflictRetries bounds the number of times a write is retried after
+	// losing a race against a concurrent write to the same key.
+	maxConflictRetries = 10
+)
+
+var (
+	noopCancel               = func() {}
+	errInvalidHistoryVersion = errors.New("invalid version range")
+	errTooManyConflicts      = errors.New("too many concurrent modifications")
+)
+
+// NewStore creates a kv store based on consul. Consul does not track per key
+// versions or keep previous values, so the version of each key is stored in
+// the flags of the consul pair and every version is additionally written under
+// the history prefix in the same transaction.
+func NewStore(c consulapi.Client, opts Options) (kv.TxnStore, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	scope := opts.InstrumentsOptions().MetricsScope()
+
+	return &client{
+		opts:       opts,
+		consul:     c,
+		watchables: map[string]kv.ValueWatchable{},
+		logger:     opts.InstrumentsOptions().Logger(),
+		m: clientMetrics{
+			consulGetError:   scope.Counter("consul-get-error"),
+			consulPutError:   scope.Counter("consul-put-error"),
+			consulTxnError:   scope.Counter("consul-txn-error"),
+			consulWatchError: scope.Counter("consul-watch-error"),
+		},
+	}, nil
+}
+
+type client struct {
+	sync.RWMutex
+
+	opts       Options
+	consul     consulapi.Client
+	watchables map[string]kv.ValueWatchable
+	logger     *zap.Logger
+	m          clientMetrics
+}
+
+type clientMetrics struct {
+	consulGetError   tally.Counter
+	consulPutError   tally.Counter
+	consulTxnError   tally.Counter
+	consulWatchError tally.Counter
+}
+
+func (c *client) Get(key string) (kv.Value, error) {
+	pair, err := c.getPair(c.opts.ApplyPrefix(key))
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, kv.ErrNotFound
+	}
+	return newValueFromPair(pair), nil
+}
+
+func (c *client) getPair(key string) (*consulapi.KVPair, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	pair, _, err := c.consul.Get(ctx, key, nil)
+	if err != nil {
+		c.m.consulGetError.Inc(1)
+		return nil, err
+	}
+	return pair, nil
+}
+
+func (c *client) History(key string, from, to int) ([]kv.Value, error) {
+	if from > to || from < 0 || to < 0 {
+		return nil, errInvalidHistoryVersion
+	}
+
+	if from == to {
+		return nil, nil
+	}
+
+	// versions start at one
+	if from == consulVersionZero {
+		return nil, errInvalidHistoryVersion
+	}
+
+	newKey := c.opts.ApplyPrefix(key)
+	latest, err := c.getPair(newKey)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, kv.ErrNotFound
+	}
+
+	version := int(latest.Flags)
+	if version < from {
+		// no value available in the requested version range
+		return nil, nil
+	}
+
+	last := to - 1
+	if version < last {
+		last = version
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	prefix := c.historyPrefix(newKey)
+	pairs, _, err := c.consul.List(ctx, prefix, nil)
+	if err != nil {
+		c.m.consulGetError.Inc(1)
+		return nil, err
+	}
+
+	versions := make(map[int]*consulapi.KVPair, len(pairs))
+	for _, p := range pairs {
+		v, err := strconv.Atoi(strings.TrimPrefix(p.Key, prefix))
+		if err != nil {
+			continue
+		}
+		versions[v] = p
+	}
+	versions[version] = latest
+
+	res := make([]kv.Value, 0, last-from+1)
+	for v := from; v <= last; v++ {
+		p, ok := versions[v]
+		if !ok {
+			return nil, fmt.Errorf("could not find version %d for key %s", v, key)
+		}
+		res = append(res, newValue(p.Value, int64(v), int64(p.ModifyIndex)))
+	}
+
+	return res, nil
+}
+
+func (c *client) Set(key string, v proto.Message) (int, error) {
+	return c.set(key, nil, v)
+}
+
+func (c *client) SetIfNotExists(key string, v proto.Message) (int, error) {
+	version, err := c.CheckAndSet(key, consulVersionZero, v)
+	if err == kv.ErrVersionMismatch {
+		err = kv.ErrAlreadyExists
+	}
+	return version, err
+}
+
+func (c *client) CheckAndSet(key string, version int, v proto.Message) (int, error) {
+	return c.set(key, &version, v)
+}
+
+// set writes the value if the current version of the key matches the expected
+// version, or unconditionally if no version is expected.
+func (c *client) set(key string, expected *int, v proto.Message) (int, error) {
+	value, err := proto.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	key = c.opts.ApplyPrefix(key)
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		cur, err := c.getPair(key)
+		if err != nil {
+			return 0, err
+		}
+
+		version := versionOf(cur)
+		if expected != nil && *expected != version {
+			return 0, kv.ErrVersionMismatch
+		}
+
+		ok, err := c.txn(c.setOps(key, cur, value))
+		if err != nil {
+			c.m.consulPutError.Inc(1)
+			return 0, err
+		}
+		if ok {
+			return version + 1, nil
+		}
+		if expected != nil {
+			// the key was modified since it was read so its version has moved on
+			return 0, kv.ErrVersionMismatch
+		}
+	}
+
+	return 0, errTooManyConflicts
+}
+
+// setOps returns the transaction operations to replace cur with value, the
+// write only succeeds if the key has not been modified since cur was read.
+func (c *client) setOps(key string, cur *consulapi.KVPair, value []byte) []consulapi.TxnOp {
+	var (
+		index   uint64
+		version = versionOf(cur) + 1
+	)
+	if cur != nil {
+		index = cur.ModifyIndex
+	}
+
+	ops := []consulapi.TxnOp{
+		{KV: &consulapi.TxnKVOp{
+			Verb:  consulapi.TxnCAS,
+			Key:   key,
+			Value: value,
+			Flags: uint64(version),
+			Index: index,
+		}},
+		{KV: &consulapi.TxnKVOp{
+			Verb:  consulapi.TxnSet,
+			Key:   c.historyKey(key, version),
+			Value: value,
+			Flags: uint64(version),
+		}},
+	}
+
+	if n := c.opts.HistoryRetention(); n > 0 && version > n {
+		ops = append(ops, consulapi.TxnOp{KV: &consulapi.TxnKVOp{
+			Verb: consulapi.TxnDelete,
+			Key:  c.historyKey(key, version-n),
+		}})
+	}
+
+	return ops
+}
+
+func (c *client) processCondition(condition kv.Condition) (consulapi.TxnOp, error) {
+	switch condition.TargetType() {
+	case kv.TargetVersion:
+	default:
+		return consulapi.TxnOp{}, kv.ErrUnknownTargetType
+	}
+
+	switch condition.CompareType() {
+	case kv.CompareEqual:
+	default:
+		return consulapi.TxnOp{}, kv.ErrUnknownCompareType
+	}
+
+	expected, ok := toVersion(condition.Value())
+	if !ok {
+		return consulapi.TxnOp{}, fmt.Errorf("invalid version %v for key %s", condition.Value(), condition.Key())
+	}
+
+	key := c.opts.ApplyPrefix(condition.Key())
+	cur, err := c.getPair(key)
+	if err != nil {
+		return consulapi.TxnOp{}, err
+	}
+	if versionOf(cur) != expected {
+		return consulapi.TxnOp{}, kv.ErrConditionCheckFailed
+	}
+
+	// NB: the version is only checked on read, the transaction guards against
+	// the key changing in between by checking its modify index.
+	if cur == nil {
+		return consulapi.TxnOp{KV: &consulapi.TxnKVOp{
+			Verb: consulapi.TxnCheckNotExists,
+			Key:  key,
+		}}, nil
+	}
+	return consulapi.TxnOp{KV: &consulapi.TxnKVOp{
+		Verb:  consulapi.TxnCheckIndex,
+		Key:   key,
+		Index: cur.ModifyIndex,
+	}}, nil
+}
+
+func (c *client) processOp(op kv.Op) ([]consulapi.TxnOp, int, error) {
+	switch op.Type() {
+	case kv.OpSet:
+		opSet := op.(kv.SetOp)
+
+		value, err := proto.Marshal(opSet.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		key := c.opts.ApplyPrefix(opSet.Key())
+		cur, err := c.getPair(key)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return c.setOps(key, cur, value), versionOf(cur) + 1, nil
+	default:
+		return nil, 0, kv.ErrUnknownOpType
+	}
+}
+
+func (c *client) Commit(conditions []kv.Condition, ops []kv.Op) (kv.Response, error) {
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		txnOps := make([]consulapi.TxnOp, 0, len(conditions)+2*len(ops))
+		for _, condition := range conditions {
+			txnOp, err := c.processCondition(condition)
+			if err != nil {
+				return nil, err
+			}
+			txnOps = append(txnOps, txnOp)
+		}
+
+		opResponses := make([]kv.OpResponse, len(ops))
+		for i, op := range ops {
+			setOps, version, err := c.processOp(op)
+			if err != nil {
+				return nil, err
+			}
+			txnOps = append(txnOps, setOps...)
+			opResponses[i] = kv.NewOpResponse(op).SetValue(version)
+		}
+
+		ok, err := c.txn(txnOps)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return kv.NewResponse().SetResponses(opResponses), nil
+		}
+
+		// a condition key or an op key was modified concurrently, reevaluate
+		// the conditions against the latest versions
+	}
+
+	return nil, errTooManyConflicts
+}
+
+func (c *client) txn(ops []consulapi.TxnOp) (bool, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	ok, _, err := c.consul.Txn(ctx, ops)
+	if err != nil {
+		c.m.consulTxnError.Inc(1)
+		return false, err
+	}
+	return ok, nil
+}
+
+func (c *client) Delete(key string) (kv.Value, error) {
+	key = c.opts.ApplyPrefix(key)
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		cur, err := c.getPair(key)
+		if err != nil {
+			return nil, err
+		}
+		if cur == nil {
+			return nil, kv.ErrNotFound
+		}
+
+		// The history of the key is kept as etcd keeps the previous
+		// revisions of a deleted key, once the key is set again its versions
+		// restart at one and overwrite the history.
+		ok, err := c.txn([]consulapi.TxnOp{
+			{KV: &consulapi.TxnKVOp{
+				Verb:  consulapi.TxnDeleteCAS,
+				Key:   key,
+				Index: cur.ModifyIndex,
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return newValueFromPair(cur), nil
+		}
+	}
+
+	return nil, errTooManyConflicts
+}
+
+func (c *client) Watch(key string) (kv.ValueWatch, error) {
+	newKey := c.opts.ApplyPrefix(key)
+	c.Lock()
+	watchable, ok := c.watchables[newKey]
+	if !ok {
+		watchable = kv.NewValueWatchable()
+		c.watchables[newKey] = watchable
+
+		go c.watch(newKey)
+	}
+	c.Unlock()
+	_, w, err := watchable.Watch()
+	return w, err
+}
+
+// watch issues blocking queries for the key until the watchable for the key
+// no longer has any watches.
+func (c *client) watch(key string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(c.opts.WatchCheckInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if c.tickAndStop(key) {
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var index uint64
+	for {
+		pair, meta, err := c.consul.Get(ctx, key, &consulapi.QueryOptions{
+			WaitIndex: index,
+			WaitTime:  c.opts.WatchWaitTime(),
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.m.consulWatchError.Inc(1)
+			c.logger.Warn("consul watch failed", zap.String("key", key), zap.Error(err))
+
+			select {
+			case <-time.After(c.opts.WatchRetryInterval()):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		// NB: consul resets the index if its state is restored from a
+		// snapshot, start over rather than blocking until it catches up.
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		if err := c.update(key, pair); err != nil {
+			c.logger.Warn("failed to update watch", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (c *client) update(key string, pair *consulapi.KVPair) error {
+	c.RLock()
+	w, ok := c.watchables[key]
+	c.RUnlock()
+	if !ok {
+		return fmt.Errorf("unexpected: no watchable found for key: %s", key)
+	}
+
+	curValue := w.Get()
+
+	// Both current and new are nil.
+	if curValue == nil && pair == nil {
+		return nil
+	}
+
+	if pair == nil {
+		// At deletion, just update the watch to nil.
+		return w.Update(nil)
+	}
+
+	nv := newValueFromPair(pair)
+	if curValue == nil || nv.IsNewer(curValue) {
+		return w.Update(nv)
+	}
+
+	return nil
+}
+
+func (c *client) tickAndStop(key string) bool {
+	// fast path
+	c.RLock()
+	watchable, ok := c.watchables[key]
+	c.RUnlock()
+	if !ok {
+		c.logger.Warn("unexpected: key is already cleaned up", zap.String("key", key))
+		return true
+	}
+
+	if watchable.NumWatches() != 0 {
+		return false
+	}
+
+	// slow path
+	c.Lock()
+	defer c.Unlock()
+	watchable, ok = c.watchables[key]
+	if !ok {
+		// not expect this to happen
+		c.logger.Warn("unexpected: key is already cleaned up", zap.String("key", key))
+		return true
+	}
+
+	if watchable.NumWatches() != 0 {
+		// a new watch has subscribed to the watchable, do not clean up
+		return false
+	}
+
+	watchable.Close()
+	delete(c.watchables, key)
+	return true
+}
+
+// historyPrefix returns the prefix of the history keys of a key. The key is
+// escaped so the history of a key never shares a prefix with the history of
+// another key nested under it.
+func (c *client) historyPrefix(key string) string {
+	return fmt.Sprintf("%s/%s/", c.opts.HistoryPrefix(), url.PathEscape(key))
+}
+
+func (c *client) historyKey(key string, version int) string {
+	return c.historyPrefix(key) + strconv.Itoa(version)
+}
+
+func (c *client) context() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	cancel := noopCancel
+	if c.opts.RequestTimeout() > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.opts.RequestTimeout())
+	}
+
+	return ctx, cancel
+}
+
+func versionOf(pair *consulapi.KVPair) int {
+	if pair == nil {
+		return consulVersionZero
+	}
+	return int(pair.Flags)
+}
+
+func toVersion(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+type value struct {
+	Val []byte
+	Ver int64
+	Rev int64
+}
+
+func newValue(val []byte, ver, rev int64) *value {
+	return &value{
+		Val: val,
+		Ver: ver,
+		Rev: rev,
+	}
+}
+
+func newValueFromPair(pair *consulapi.KVPair) *value {
+	return newValue(pair.Value, int64(pair.Flags), int64(pair.ModifyIndex))
+}
+
+func (c *value) IsNewer(other kv.Value) bool {
+	othervalue, ok := other.(*value)
+	if ok {
+		return c.Rev > othervalue.Rev
+	}
+
+	return c.Version() > other.Version()
+}
+
+func (c *value) Unmarshal(v proto.Message) error {
+	return proto.Unmarshal(c.Val, v)
+}
+
+func (c *value) Version() int {
+	return int(c.Ver)
+}
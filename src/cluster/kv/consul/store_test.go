@@ -0,0 +1,708 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/consul/consultest"
+	"github.com/m3db/m3/src/cluster/generated/proto/kvtest"
+	"github.com/m3db/m3/src/cluster/kv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	v1 := newValue(nil, 2, 100)
+	require.Equal(t, 2, v1.Version())
+
+	v2 := newValue(nil, 1, 200)
+	require.Equal(t, 1, v2.Version())
+
+	require.True(t, v2.IsNewer(v1))
+	require.False(t, v1.IsNewer(v1))
+	require.False(t, v1.IsNewer(v2))
+}
+
+func TestGetAndSet(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	value, err := store.Get("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+	require.Nil(t, value)
+
+	version, err := store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	value, err = store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar1", 1)
+
+	version, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	value, err = store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar2", 2)
+}
+
+func TestSetIfNotExist(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	version, err := store.SetIfNotExists("foo", genProto("bar"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	_, err = store.SetIfNotExists("foo", genProto("bar"))
+	require.Equal(t, kv.ErrAlreadyExists, err)
+
+	value, err := store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar", 1)
+}
+
+func TestCheckAndSet(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.CheckAndSet("foo", 1, genProto("bar"))
+	require.Equal(t, kv.ErrVersionMismatch, err)
+
+	version, err := store.CheckAndSet("foo", 0, genProto("bar"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	version, err = store.CheckAndSet("foo", 1, genProto("bar"))
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	_, err = store.CheckAndSet("foo", 1, genProto("bar"))
+	require.Equal(t, kv.ErrVersionMismatch, err)
+
+	value, err := store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar", 2)
+}
+
+func TestCheckAndSetConcurrent(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar"))
+	require.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		succeeded int32
+		mismatch  int32
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.CheckAndSet("foo", 1, genProto(fmt.Sprintf("bar%d", i)))
+			switch err {
+			case nil:
+				atomic.AddInt32(&succeeded, 1)
+			case kv.ErrVersionMismatch:
+				atomic.AddInt32(&mismatch, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), succeeded)
+	require.Equal(t, int32(9), mismatch)
+	value, err := store.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, 2, value.Version())
+}
+
+func TestWatchClose(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+	w1, err := store.Watch("foo")
+	require.NoError(t, err)
+	<-w1.C()
+	verifyValue(t, w1.Get(), "bar1", 1)
+
+	c := store.(*client)
+	_, ok := c.watchables["test/foo"]
+	require.True(t, ok)
+
+	// closing w1 will close the go routine for the watch updates
+	w1.Close()
+
+	// waits until the original watchable is cleaned up
+	for {
+		c.RLock()
+		_, ok = c.watchables["test/foo"]
+		c.RUnlock()
+		if !ok {
+			break
+		}
+	}
+
+	// getting a new watch will create a new watchale and thread to watch for updates
+	w2, err := store.Watch("foo")
+	require.NoError(t, err)
+	<-w2.C()
+	verifyValue(t, w2.Get(), "bar1", 1)
+
+	// verify that w1 will no longer be updated because the original watchable is closed
+	_, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+	<-w2.C()
+	verifyValue(t, w2.Get(), "bar2", 2)
+	verifyValue(t, w1.Get(), "bar1", 1)
+
+	w1.Close()
+	w2.Close()
+}
+
+func TestWatchLastVersion(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	w, err := store.Watch("foo")
+	require.NoError(t, err)
+	require.Nil(t, w.Get())
+
+	var errs int32
+	lastVersion := 100
+	go func() {
+		for i := 1; i <= lastVersion; i++ {
+			_, err := store.Set("foo", genProto(fmt.Sprintf("bar%d", i)))
+			if err != nil {
+				atomic.AddInt32(&errs, 1)
+			}
+		}
+	}()
+
+	for {
+		<-w.C()
+		value := w.Get()
+		if value.Version() == lastVersion-int(atomic.LoadInt32(&errs)) {
+			break
+		}
+	}
+	verifyValue(t, w.Get(), fmt.Sprintf("bar%d", lastVersion), lastVersion)
+
+	w.Close()
+}
+
+func TestWatchFromExist(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+	value, err := store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar1", 1)
+
+	w, err := store.Watch("foo")
+	require.NoError(t, err)
+
+	<-w.C()
+	require.Equal(t, 0, len(w.C()))
+	verifyValue(t, w.Get(), "bar1", 1)
+
+	_, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+
+	<-w.C()
+	require.Equal(t, 0, len(w.C()))
+	verifyValue(t, w.Get(), "bar2", 2)
+
+	_, err = store.Set("foo", genProto("bar3"))
+	require.NoError(t, err)
+
+	<-w.C()
+	require.Equal(t, 0, len(w.C()))
+	verifyValue(t, w.Get(), "bar3", 3)
+
+	w.Close()
+}
+
+func TestWatchFromNotExist(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	w, err := store.Watch("foo")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(w.C()))
+	require.Nil(t, w.Get())
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+
+	<-w.C()
+	require.Equal(t, 0, len(w.C()))
+	verifyValue(t, w.Get(), "bar1", 1)
+
+	_, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+
+	<-w.C()
+	require.Equal(t, 0, len(w.C()))
+	verifyValue(t, w.Get(), "bar2", 2)
+
+	w.Close()
+}
+
+func TestMultipleWatchesFromExist(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+
+	w1, err := store.Watch("foo")
+	require.NoError(t, err)
+
+	w2, err := store.Watch("foo")
+	require.NoError(t, err)
+
+	<-w1.C()
+	require.Equal(t, 0, len(w1.C()))
+	verifyValue(t, w1.Get(), "bar1", 1)
+
+	<-w2.C()
+	require.Equal(t, 0, len(w2.C()))
+	verifyValue(t, w2.Get(), "bar1", 1)
+
+	_, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+
+	<-w1.C()
+	require.Equal(t, 0, len(w1.C()))
+	verifyValue(t, w1.Get(), "bar2", 2)
+
+	<-w2.C()
+	require.Equal(t, 0, len(w2.C()))
+	verifyValue(t, w2.Get(), "bar2", 2)
+
+	w1.Close()
+	w2.Close()
+}
+
+func TestWatchNonBlocking(t *testing.T) {
+	srv := consultest.NewServer()
+	cc, err := consulapi.NewClient(consulapi.NewOptions().SetAddress(srv.Address()))
+	require.NoError(t, err)
+
+	store, err := NewStore(cc, testOptions().SetWatchRetryInterval(50*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+
+	// an unreachable server does not block the watch and is retried until
+	// the value can be read
+	srv.Close()
+
+	before := time.Now()
+	w1, err := store.Watch("foo")
+	require.WithinDuration(t, time.Now(), before, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(w1.C()))
+	require.Nil(t, w1.Get())
+
+	w1.Close()
+}
+
+func TestHistory(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.History("k1", 10, 5)
+	require.Error(t, err)
+
+	_, err = store.History("k1", 0, 5)
+	require.Error(t, err)
+
+	_, err = store.History("k1", -5, 0)
+	require.Error(t, err)
+
+	totalVersion := 10
+	for i := 1; i <= totalVersion; i++ {
+		store.Set("k1", genProto(fmt.Sprintf("bar%d", i)))
+		store.Set("k1/k2", genProto(fmt.Sprintf("baz%d", i)))
+	}
+
+	res, err := store.History("k1", 5, 5)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+
+	res, err = store.History("k1", 15, 20)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+
+	res, err = store.History("k1", 6, 10)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(res))
+	for i := 0; i < len(res); i++ {
+		version := i + 6
+		value := res[i]
+		verifyValue(t, value, fmt.Sprintf("bar%d", version), version)
+	}
+
+	res, err = store.History("k1", 3, 7)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(res))
+	for i := 0; i < len(res); i++ {
+		version := i + 3
+		value := res[i]
+		verifyValue(t, value, fmt.Sprintf("bar%d", version), version)
+	}
+
+	res, err = store.History("k1", 5, 15)
+	require.NoError(t, err)
+	require.Equal(t, totalVersion-5+1, len(res))
+	for i := 0; i < len(res); i++ {
+		version := i + 5
+		value := res[i]
+		verifyValue(t, value, fmt.Sprintf("bar%d", version), version)
+	}
+
+	// deleting a key keeps its history like etcd, once the key is set again
+	// its versions restart and only the versions up to the current one are
+	// returned
+	_, err = store.Delete("k1")
+	require.NoError(t, err)
+	_, err = store.History("k1", 1, 5)
+	require.Equal(t, kv.ErrNotFound, err)
+	_, err = store.Set("k1", genProto("new1"))
+	require.NoError(t, err)
+
+	res, err = store.History("k1", 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	verifyValue(t, res[0], "new1", 1)
+
+	res, err = store.History("k1/k2", 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+	verifyValue(t, res[0], "baz1", 1)
+	verifyValue(t, res[1], "baz2", 2)
+}
+
+func TestHistoryRetention(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts.SetHistoryRetention(3))
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, err = store.Set("k1", genProto(fmt.Sprintf("bar%d", i)))
+		require.NoError(t, err)
+	}
+
+	res, err := store.History("k1", 8, 11)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(res))
+	for i := 0; i < len(res); i++ {
+		version := i + 8
+		verifyValue(t, res[i], fmt.Sprintf("bar%d", version), version)
+	}
+
+	_, err = store.History("k1", 7, 11)
+	require.Error(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Delete("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	version, err := store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	version, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	v, err := store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, v, "bar2", 2)
+
+	v, err = store.Delete("foo")
+	require.NoError(t, err)
+	verifyValue(t, v, "bar2", 2)
+
+	_, err = store.Delete("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	_, err = store.Get("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	version, err = store.SetIfNotExists("foo", genProto("bar1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+}
+
+func TestDelete_TriggerWatch(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	vw, err := store.Watch("foo")
+	require.NoError(t, err)
+
+	_, err = store.Set("foo", genProto("bar1"))
+	require.NoError(t, err)
+
+	<-vw.C()
+	verifyValue(t, vw.Get(), "bar1", 1)
+
+	_, err = store.Set("foo", genProto("bar2"))
+	require.NoError(t, err)
+
+	<-vw.C()
+	verifyValue(t, vw.Get(), "bar2", 2)
+
+	_, err = store.Delete("foo")
+	require.NoError(t, err)
+
+	<-vw.C()
+	require.Nil(t, vw.Get())
+
+	_, err = store.Set("foo", genProto("bar3"))
+	require.NoError(t, err)
+
+	<-vw.C()
+	verifyValue(t, vw.Get(), "bar3", 1)
+}
+
+func TestTxn(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	r, err := store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("foo").
+				SetValue(0),
+		},
+		[]kv.Op{kv.NewSetOp("foo", genProto("bar1"))},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(r.Responses()))
+	require.Equal(t, "foo", r.Responses()[0].Key())
+	require.Equal(t, kv.OpSet, r.Responses()[0].Type())
+	require.Equal(t, 1, r.Responses()[0].Value())
+
+	v, err := store.Set("key", genProto("bar1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	r, err = store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("key").
+				SetValue(1),
+		},
+		[]kv.Op{kv.NewSetOp("foo", genProto("bar1"))},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(r.Responses()))
+	require.Equal(t, "foo", r.Responses()[0].Key())
+	require.Equal(t, kv.OpSet, r.Responses()[0].Type())
+	require.Equal(t, 2, r.Responses()[0].Value())
+
+	r, err = store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("foo").
+				SetValue(2),
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("key").
+				SetValue(1),
+		},
+		[]kv.Op{
+			kv.NewSetOp("key", genProto("bar1")),
+			kv.NewSetOp("foo", genProto("bar2")),
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(r.Responses()))
+	require.Equal(t, "key", r.Responses()[0].Key())
+	require.Equal(t, kv.OpSet, r.Responses()[0].Type())
+	require.Equal(t, 2, r.Responses()[0].Value())
+	require.Equal(t, "foo", r.Responses()[1].Key())
+	require.Equal(t, kv.OpSet, r.Responses()[1].Type())
+	require.Equal(t, 3, r.Responses()[1].Value())
+
+	value, err := store.Get("foo")
+	require.NoError(t, err)
+	verifyValue(t, value, "bar2", 3)
+}
+
+func TestTxn_ConditionFail(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("foo").
+				SetValue(1),
+		},
+		[]kv.Op{kv.NewSetOp("foo", genProto("bar1"))},
+	)
+	require.Equal(t, kv.ErrConditionCheckFailed, err)
+
+	store.Set("key1", genProto("v1"))
+	store.Set("key2", genProto("v2"))
+	_, err = store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("key1").
+				SetValue(1),
+			kv.NewCondition().
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion).
+				SetKey("key2").
+				SetValue(2),
+		},
+		[]kv.Op{kv.NewSetOp("foo", genProto("bar1"))},
+	)
+	require.Equal(t, kv.ErrConditionCheckFailed, err)
+
+	_, err = store.Get("foo")
+	require.Equal(t, kv.ErrNotFound, err)
+}
+
+func TestTxn_UnknownType(t *testing.T) {
+	cc, opts, closeFn := testStore(t)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	_, err = store.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetTargetType(kv.TargetVersion).
+				SetKey("foo").
+				SetValue(1),
+		},
+		[]kv.Op{kv.NewSetOp("foo", genProto("bar1"))},
+	)
+	require.Equal(t, kv.ErrUnknownCompareType, err)
+}
+
+func verifyValue(t *testing.T, v kv.Value, value string, version int) {
+	var testMsg kvtest.Foo
+	err := v.Unmarshal(&testMsg)
+	require.NoError(t, err)
+	require.Equal(t, value, testMsg.Msg)
+	require.Equal(t, version, v.Version())
+}
+
+func genProto(msg string) proto.Message {
+	return &kvtest.Foo{Msg: msg}
+}
+
+func testOptions() Options {
+	return NewOptions().
+		SetWatchCheckInterval(10 * time.Millisecond).
+		SetPrefix("test")
+}
+
+func testStore(t *testing.T) (consulapi.Client, Options, func()) {
+	srv := consultest.NewServer()
+	cc, err := consulapi.NewClient(consulapi.NewOptions().SetAddress(srv.Address()))
+	require.NoError(t, err)
+
+	return cc, testOptions(), srv.Close
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package algo
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+)
+
+// loadBalanceTolerance is how far above the mean load per weight the most
+// loaded instance may be before shards are moved off of it.
+const loadBalanceTolerance = 0.05
+
+var errInvalidMaxMoves = errors.New("max shard moves must be positive")
+
+// BalanceShardLoad moves up to maxMoves shards of a sharded placement
+// between instances to minimize the maximum load per weight of the
+// instances given the observed load of each shard. Shards are only moved
+// where the replica and isolation group constraints of the sharded algorithm
+// allow it, shards still initializing are preferred over available ones to
+// limit streaming. Since every move of an available shard streams it to
+// another instance this is only run on request, never as part of adding,
+// removing or replacing instances.
+func BalanceShardLoad(
+	p placement.Placement,
+	loads map[uint32]placement.ShardLoad,
+	maxMoves int,
+	opts placement.Options,
+) (placement.Placement, error) {
+	if !p.IsSharded() {
+		return nil, errIncompatibleWithShardedAlgo
+	}
+	if maxMoves <= 0 {
+		return nil, errInvalidMaxMoves
+	}
+
+	ph := newHelper(p.Clone(), p.ReplicaFactor(), opts)
+	ph.balanceLoad(newShardCosts(p.Shards(), loads), maxMoves)
+	return tryCleanupShardState(ph.generatePlacement(), opts)
+}
+
+// shardCosts is the relative load of each shard.
+type shardCosts map[uint32]float64
+
+// newShardCosts combines the shard loads into a single cost per shard, the
+// share of each load dimension held by the shard averaged over dimensions.
+// Shards without a load are estimated as the mean cost, and all shards cost
+// the same when no load is known.
+func newShardCosts(shardIDs []uint32, loads map[uint32]placement.ShardLoad) shardCosts {
+	var (
+		totalSeries int64
+		totalRate   float64
+		totalBytes  int64
+	)
+	for _, id := range shardIDs {
+		load, ok := loads[id]
+		if !ok {
+			continue
+		}
+		totalSeries += load.SeriesCount
+		totalRate += load.WriteRate
+		totalBytes += load.DiskBytes
+	}
+
+	var (
+		costs = make(shardCosts, len(shardIDs))
+		sum   float64
+		known int
+	)
+	for _, id := range shardIDs {
+		load, ok := loads[id]
+		if !ok {
+			continue
+		}
+		var (
+			cost float64
+			dims int
+		)
+		if totalSeries > 0 {
+			cost += float64(load.SeriesCount) / float64(totalSeries)
+			dims++
+		}
+		if totalRate > 0 {
+			cost += load.WriteRate / totalRate
+			dims++
+		}
+		if totalBytes > 0 {
+			cost += float64(load.DiskBytes) / float64(totalBytes)
+			dims++
+		}
+		if dims > 0 {
+			cost /= float64(dims)
+		}
+		costs[id] = cost
+		sum += cost
+		known++
+	}
+
+	if sum == 0 {
+		for _, id := range shardIDs {
+			costs[id] = 1
+		}
+		return costs
+	}
+
+	mean := sum / float64(known)
+	for _, id := range shardIDs {
+		if _, ok := costs[id]; !ok {
+			costs[id] = mean
+		}
+	}
+	return costs
+}
+
+func (ph *helper) balanceLoad(costs shardCosts, maxMoves int) {
+	instances := nonLeavingInstances(ph.Instances())
+	if len(instances) < 2 {
+		return
+	}
+
+	var (
+		loads       = make(map[string]float64, len(instances))
+		totalLoad   float64
+		totalWeight float64
+	)
+	for _, instance := range instances {
+		load := instanceCost(instance, costs)
+		loads[instance.ID()] = load
+		totalLoad += load
+		totalWeight += loadWeight(instance)
+	}
+	limit := totalLoad / totalWeight * (1 + loadBalanceTolerance)
+	ratio := func(instance placement.Instance) float64 {
+		return loads[instance.ID()] / loadWeight(instance)
+	}
+
+	// Every move lowers the load of the most loaded instance, the number of
+	// moves is also bound in case loads only shift between instances.
+	if bound := ph.getShardLen() * ph.rf; maxMoves > bound {
+		maxMoves = bound
+	}
+	for i := 0; i < maxMoves; i++ {
+		sort.Slice(instances, func(i, j int) bool {
+			ri, rj := ratio(instances[i]), ratio(instances[j])
+			if ri != rj {
+				return ri > rj
+			}
+			return instances[i].ID() < instances[j].ID()
+		})
+		hottest := instances[0]
+		if ratio(hottest) <= limit {
+			return
+		}
+		if !ph.moveLoad(hottest, instances[1:], costs, loads) {
+			return
+		}
+	}
+}
+
+// moveLoad moves a single shard off the instance onto one of the targets,
+// which are sorted by descending load, so that the target ends up less
+// loaded than the instance was. The least loaded targets are tried first.
+func (ph *helper) moveLoad(
+	from placement.Instance,
+	targets []placement.Instance,
+	costs shardCosts,
+	loads map[string]float64,
+) bool {
+	fromRatio := loads[from.ID()] / loadWeight(from)
+
+	var shards []shard.Shard
+	for _, s := range from.Shards().All() {
+		if s.State() != shard.Leaving {
+			shards = append(shards, s)
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		si, sj := shards[i], shards[j]
+		if si.State() != sj.State() {
+			return shardMoveRank(si.State()) < shardMoveRank(sj.State())
+		}
+		if costs[si.ID()] != costs[sj.ID()] {
+			return costs[si.ID()] > costs[sj.ID()]
+		}
+		return si.ID() < sj.ID()
+	})
+
+	for _, s := range shards {
+		cost := costs[s.ID()]
+		for i := len(targets) - 1; i >= 0; i-- {
+			to := targets[i]
+			if (loads[to.ID()]+cost)/loadWeight(to) >= fromRatio {
+				continue
+			}
+			if ph.moveShard(s, from, to) {
+				loads[from.ID()] -= cost
+				loads[to.ID()] += cost
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shardMoveRank orders shards by how cheap they are to move, shards that
+// are not yet streamed to the instance cost nothing to move.
+func shardMoveRank(state shard.State) int {
+	switch state {
+	case shard.Unknown:
+		return 0
+	case shard.Initializing:
+		return 1
+	}
+	return 2
+}
+
+func instanceCost(instance placement.Instance, costs shardCosts) float64 {
+	var cost float64
+	for _, s := range instance.Shards().All() {
+		if s.State() == shard.Leaving {
+			continue
+		}
+		cost += costs[s.ID()]
+	}
+	return cost
+}
+
+func loadWeight(instance placement.Instance) float64 {
+	if w := instance.Weight(); w > 0 {
+		return float64(w)
+	}
+	return 1
+}
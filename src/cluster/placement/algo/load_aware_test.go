@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package algo
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShardCosts(t *testing.T) {
+	costs := newShardCosts([]uint32{0, 1, 2}, map[uint32]placement.ShardLoad{
+		0: {SeriesCount: 300, DiskBytes: 100},
+		1: {SeriesCount: 100, DiskBytes: 300},
+	})
+	assert.InDelta(t, 0.5, costs[0], 1e-9)
+	assert.InDelta(t, 0.5, costs[1], 1e-9)
+	// Shards without a load cost the mean.
+	assert.InDelta(t, 0.5, costs[2], 1e-9)
+
+	costs = newShardCosts([]uint32{0, 1}, map[uint32]placement.ShardLoad{
+		0: {WriteRate: 3},
+		1: {WriteRate: 1},
+	})
+	assert.InDelta(t, 0.75, costs[0], 1e-9)
+	assert.InDelta(t, 0.25, costs[1], 1e-9)
+
+	costs = newShardCosts([]uint32{0, 1}, nil)
+	assert.Equal(t, shardCosts{0: 1, 1: 1}, costs)
+}
+
+func newAvailableInstance(id, group string, shardIDs ...uint32) placement.Instance {
+	instance := placement.NewEmptyInstance(id, group, "z1", "endpoint", 1)
+	for _, shardID := range shardIDs {
+		instance.Shards().Add(shard.NewShard(shardID).SetState(shard.Available))
+	}
+	return instance
+}
+
+func maxLoadRatio(p placement.Placement, costs shardCosts) float64 {
+	var max float64
+	for _, instance := range p.Instances() {
+		if r := instanceCost(instance, costs) / loadWeight(instance); r > max {
+			max = r
+		}
+	}
+	return max
+}
+
+func validateIsolationGroups(t *testing.T, p placement.Placement) {
+	groups := make(map[uint32]map[string]struct{})
+	for _, instance := range p.Instances() {
+		for _, s := range instance.Shards().All() {
+			if s.State() == shard.Leaving {
+				continue
+			}
+			if _, ok := groups[s.ID()]; !ok {
+				groups[s.ID()] = make(map[string]struct{})
+			}
+			_, exists := groups[s.ID()][instance.IsolationGroup()]
+			require.False(t, exists, "shard %d has two replicas in %s", s.ID(), instance.IsolationGroup())
+			groups[s.ID()][instance.IsolationGroup()] = struct{}{}
+		}
+	}
+	for _, id := range p.Shards() {
+		require.Len(t, groups[id], p.ReplicaFactor(), "shard %d", id)
+	}
+}
+
+func TestBalanceShardLoad(t *testing.T) {
+	p := placement.NewPlacement().
+		SetInstances([]placement.Instance{
+			newAvailableInstance("i1", "r1", 0, 1, 2),
+			newAvailableInstance("i2", "r2", 3, 4, 5),
+			newAvailableInstance("i3", "r3", 6, 7, 8),
+		}).
+		SetShards([]uint32{0, 1, 2, 3, 4, 5, 6, 7, 8}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+
+	// Shard 0 is as hot as the other shards combined.
+	loads := map[uint32]placement.ShardLoad{0: {SeriesCount: 800}}
+	for id := uint32(1); id < 9; id++ {
+		loads[id] = placement.ShardLoad{SeriesCount: 100}
+	}
+	costs := newShardCosts(p.Shards(), loads)
+
+	result, err := BalanceShardLoad(p, loads, 10, placement.NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(result))
+	validateIsolationGroups(t, result)
+	assert.True(t, maxLoadRatio(result, costs) < maxLoadRatio(p, costs))
+
+	// The hot shard stays put and the cold shards move off of its instance.
+	i1, ok := result.Instance("i1")
+	require.True(t, ok)
+	assert.Equal(t, 1, i1.Shards().NumShardsForState(shard.Available))
+	available, _ := i1.Shards().Shard(0)
+	assert.Equal(t, shard.Available, available.State())
+	assert.Equal(t, 2, i1.Shards().NumShardsForState(shard.Leaving))
+	for _, s := range i1.Shards().ShardsForState(shard.Leaving) {
+		assert.NotEqual(t, uint32(0), s.ID())
+	}
+}
+
+func TestBalanceShardLoadWithinIsolationGroups(t *testing.T) {
+	p := placement.NewPlacement().
+		SetInstances([]placement.Instance{
+			newAvailableInstance("i1", "r1", 0, 1),
+			newAvailableInstance("i2", "r1", 2, 3),
+			newAvailableInstance("i3", "r2", 0, 2),
+			newAvailableInstance("i4", "r2", 1, 3),
+		}).
+		SetShards([]uint32{0, 1, 2, 3}).
+		SetReplicaFactor(2).
+		SetIsSharded(true)
+
+	loads := map[uint32]placement.ShardLoad{
+		0: {WriteRate: 100},
+		1: {WriteRate: 100},
+		2: {WriteRate: 10},
+		3: {WriteRate: 10},
+	}
+	costs := newShardCosts(p.Shards(), loads)
+
+	result, err := BalanceShardLoad(p, loads, 10, placement.NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(result))
+	validateIsolationGroups(t, result)
+	assert.True(t, maxLoadRatio(result, costs) < maxLoadRatio(p, costs))
+}
+
+func TestBalanceShardLoadMaxMoves(t *testing.T) {
+	p := placement.NewPlacement().
+		SetInstances([]placement.Instance{
+			newAvailableInstance("i1", "r1", 0, 1, 2, 3, 4, 5),
+			newAvailableInstance("i2", "r2", 6),
+			newAvailableInstance("i3", "r3", 7),
+		}).
+		SetShards([]uint32{0, 1, 2, 3, 4, 5, 6, 7}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+
+	_, err := BalanceShardLoad(p, nil, 0, placement.NewOptions())
+	require.Error(t, err)
+
+	// All shards cost the same without loads, balancing them takes three
+	// moves of which only two are made.
+	result, err := BalanceShardLoad(p, nil, 2, placement.NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(result))
+	i1, ok := result.Instance("i1")
+	require.True(t, ok)
+	assert.Equal(t, 4, i1.Shards().NumShardsForState(shard.Available))
+	assert.Equal(t, 2, i1.Shards().NumShardsForState(shard.Leaving))
+}
@@ -82,6 +82,10 @@ type placementHelper interface {
 	// returnInitializingShards returns all the initializing shards on the given instance
 	// by returning them back to the original owners.
 	returnInitializingShards(instance placement.Instance)
+
+	// balanceLoad moves up to maxMoves shards to minimize the maximum load
+	// per weight of the instances given the cost of each shard.
+	balanceLoad(costs shardCosts, maxMoves int)
 }
 
 // PlacementHelper helps the algorithm to place shards.
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package operator
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Configuration is the configuration for placement operators.
+type Configuration struct {
+	// Key is the KV key operations are stored at.
+	Key string `yaml:"key"`
+
+	// PollInterval is how often an operation checks on its batch.
+	PollInterval time.Duration `yaml:"pollInterval"`
+
+	// BatchTimeout is how long a batch may take before the operation fails.
+	BatchTimeout time.Duration `yaml:"batchTimeout"`
+
+	// HealthCheckPort is the port of the dbnode HTTP node endpoints used to
+	// check that instances are bootstrapped, defaults to 9002.
+	HealthCheckPort int `yaml:"healthCheckPort"`
+
+	// HealthCheckTimeout is the timeout of a single health check.
+	HealthCheckTimeout time.Duration `yaml:"healthCheckTimeout"`
+
+	// ElectionTTL is the TTL of the leadership of the operator elected to
+	// run operations, defaults to the etcd default of 60s.
+	ElectionTTL time.Duration `yaml:"electionTTL"`
+}
+
+// NewElectionOptions creates the options of the election among operators
+// from the configuration.
+func (c Configuration) NewElectionOptions() services.ElectionOptions {
+	opts := services.NewElectionOptions()
+	if c.ElectionTTL > 0 {
+		opts = opts.SetTTLSecs(int(c.ElectionTTL / time.Second))
+	}
+	return opts
+}
+
+// NewOptions creates operator options from the configuration.
+func (c Configuration) NewOptions(instrumentOpts instrument.Options) Options {
+	opts := NewOptions().SetInstrumentOptions(instrumentOpts)
+	if c.Key != "" {
+		opts = opts.SetKey(c.Key)
+	}
+	if c.PollInterval > 0 {
+		opts = opts.SetPollInterval(c.PollInterval)
+	}
+	if c.BatchTimeout > 0 {
+		opts = opts.SetBatchTimeout(c.BatchTimeout)
+	}
+
+	port := DefaultHealthCheckPort
+	if c.HealthCheckPort > 0 {
+		port = c.HealthCheckPort
+	}
+	timeout := defaultHealthCheckTimeout
+	if c.HealthCheckTimeout > 0 {
+		timeout = c.HealthCheckTimeout
+	}
+	return opts.SetHealthChecker(NewHTTPHealthChecker(port, timeout))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package operator
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+)
+
+const (
+	// DefaultHealthCheckPort is the default port of the dbnode HTTP node
+	// endpoints.
+	DefaultHealthCheckPort = 9002
+
+	defaultHealthCheckTimeout = 5 * time.Second
+
+	bootstrappedPath = "/bootstrappedinplacementornoplacement"
+)
+
+type httpHealthChecker struct {
+	client *http.Client
+	port   int
+}
+
+// NewHTTPHealthChecker returns a health checker that calls the
+// BootstrappedInPlacementOrNoPlacement HTTP endpoint of dbnode instances on
+// the given port.
+func NewHTTPHealthChecker(port int, timeout time.Duration) HealthChecker {
+	return &httpHealthChecker{
+		client: &http.Client{Timeout: timeout},
+		port:   port,
+	}
+}
+
+func (c *httpHealthChecker) Bootstrapped(instance placement.Instance) (bool, error) {
+	host := instance.Hostname()
+	if host == "" {
+		h, _, err := net.SplitHostPort(instance.Endpoint())
+		if err != nil {
+			return false, fmt.Errorf("unable to get host of instance %s: %v",
+				instance.ID(), err)
+		}
+		host = h
+	}
+
+	url := "http://" + net.JoinHostPort(host, strconv.Itoa(c.port)) + bootstrappedPath
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return false, err
+	}
+	// Drain the body so the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	// The endpoint returns an error until the instance is bootstrapped.
+	return resp.StatusCode == http.StatusOK, nil
+}
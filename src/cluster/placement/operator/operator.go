@@ -0,0 +1,704 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errInvalidOperation    = errors.New("invalid placement operation type")
+	errNoInstances         = errors.New("placement operation requires instances")
+	errNoInstanceIDs       = errors.New("placement operation requires instance IDs")
+	errUnexpectedInstances = errors.New("scale in does not take instances")
+	errUnexpectedIDs       = errors.New("scale out does not take instance IDs")
+	errOperatorClosed      = errors.New("placement operator is closed")
+)
+
+type operatorMetrics struct {
+	batchesApplied   tally.Counter
+	batchesCompleted tally.Counter
+	completed        tally.Counter
+	failed           tally.Counter
+	stepErrors       tally.Counter
+	campaignErrors   tally.Counter
+}
+
+func newOperatorMetrics(scope tally.Scope) operatorMetrics {
+	return operatorMetrics{
+		batchesApplied:   scope.Counter("batches-applied"),
+		batchesCompleted: scope.Counter("batches-completed"),
+		completed:        scope.Counter("operations-completed"),
+		failed:           scope.Counter("operations-failed"),
+		stepErrors:       scope.Counter("step-errors"),
+		campaignErrors:   scope.Counter("campaign-errors"),
+	}
+}
+
+type operator struct {
+	sync.Mutex
+
+	service       placement.Service
+	store         kv.Store
+	opts          Options
+	health        HealthChecker
+	leaderService services.LeaderService
+	nowFn         clock.NowFn
+	logger        *zap.Logger
+	metrics       operatorMetrics
+
+	leader   bool
+	running  bool
+	rerun    bool
+	closed   bool
+	closedCh chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewOperator creates a new operator that runs operations against the
+// placement service, storing their progress in the KV store.
+func NewOperator(
+	service placement.Service,
+	store kv.Store,
+	opts Options,
+) (Operator, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &operator{
+		service:       service,
+		store:         store,
+		opts:          opts,
+		health:        opts.HealthChecker(),
+		leaderService: opts.LeaderService(),
+		nowFn:         opts.ClockOptions().NowFn(),
+		logger:        opts.InstrumentOptions().Logger(),
+		metrics:       newOperatorMetrics(opts.InstrumentOptions().MetricsScope()),
+		closedCh:      make(chan struct{}),
+	}, nil
+}
+
+func (o *operator) Start(req OperationRequest) (Operation, error) {
+	op, err := o.newOperation(req)
+	if err != nil {
+		return Operation{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	current, version, err := o.load()
+	if err == nil && current.State == Running {
+		return Operation{}, ErrOperationInProgress
+	}
+	if err != nil && err != ErrNoOperation {
+		return Operation{}, err
+	}
+	if err := o.persist(op, version); err != nil {
+		if err == kv.ErrVersionMismatch {
+			// Another operation was started concurrently.
+			return Operation{}, ErrOperationInProgress
+		}
+		return Operation{}, err
+	}
+
+	o.logger.Info("started placement operation",
+		zap.String("id", op.ID),
+		zap.String("type", string(op.Type)),
+		zap.Int("batches", len(op.Batches)))
+	if err := o.run(); err != nil {
+		return Operation{}, err
+	}
+	return op, nil
+}
+
+func (o *operator) Operation() (Operation, error) {
+	op, _, err := o.load()
+	return op, err
+}
+
+func (o *operator) Cancel() (Operation, error) {
+	op, version, err := o.load()
+	if err != nil {
+		return Operation{}, err
+	}
+	if op.State != Running {
+		return Operation{}, ErrOperationNotRunning
+	}
+
+	op.State = Cancelled
+	op.UpdatedAt = o.nowFn()
+	if err := o.persist(op, version); err != nil {
+		return Operation{}, err
+	}
+	o.logger.Info("cancelled placement operation", zap.String("id", op.ID))
+	return op, nil
+}
+
+func (o *operator) Resume() error {
+	op, _, err := o.load()
+	if err != nil && err != ErrNoOperation {
+		return err
+	}
+	if err == nil && op.State == Running {
+		o.logger.Info("resuming placement operation",
+			zap.String("id", op.ID),
+			zap.Int("batch", op.CurrentBatch))
+	} else if o.leaderService == nil {
+		return nil
+	}
+
+	// With a leader service the operator campaigns even without a running
+	// operation so that it runs the operations started by other operators
+	// on the same key once it is elected.
+	return o.run()
+}
+
+func (o *operator) Close() error {
+	o.Lock()
+	if o.closed {
+		o.Unlock()
+		return errOperatorClosed
+	}
+	o.closed = true
+	close(o.closedCh)
+	o.Unlock()
+
+	o.wg.Wait()
+	return nil
+}
+
+// run starts running the stored operation in the background unless it is
+// already running.
+func (o *operator) run() error {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.closed {
+		return errOperatorClosed
+	}
+	if o.running {
+		// Make sure the running loop picks up the new operation even if it
+		// is about to exit.
+		o.rerun = true
+		return nil
+	}
+
+	o.running = true
+	o.wg.Add(1)
+	go o.runLoop()
+	if o.leaderService != nil {
+		o.wg.Add(1)
+		go o.campaignLoop()
+	}
+	return nil
+}
+
+func (o *operator) runLoop() {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.opts.PollInterval())
+	defer ticker.Stop()
+
+	for {
+		if o.leading() {
+			done, err := o.tick()
+			if err != nil {
+				o.metrics.stepErrors.Inc()
+				o.logger.Warn("placement operation step failed", zap.Error(err))
+			}
+			if done {
+				exit, rerun := o.exitLoop()
+				if exit {
+					return
+				}
+				if rerun {
+					continue
+				}
+			}
+		}
+
+		select {
+		case <-o.closedCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// exitLoop returns whether the running loop can exit once the stored
+// operation is done and whether it should run again right away. It cannot
+// exit if an operation was started since the loop found the stored one done,
+// or if the operator campaigns since it keeps polling for operations started
+// by other operators.
+func (o *operator) exitLoop() (bool, bool) {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.rerun {
+		o.rerun = false
+		return false, true
+	}
+	if o.leaderService != nil {
+		return false, false
+	}
+	o.running = false
+	return true, false
+}
+
+// leading returns whether the operator may step operations, which is always
+// the case without a leader service.
+func (o *operator) leading() bool {
+	if o.leaderService == nil {
+		return true
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	return o.leader
+}
+
+func (o *operator) setLeader(value bool) {
+	o.Lock()
+	o.leader = value
+	o.Unlock()
+}
+
+// campaignLoop campaigns for leadership of the operation key until the
+// operator is closed, campaigning again whenever a campaign ends.
+func (o *operator) campaignLoop() {
+	defer o.wg.Done()
+
+	electionID := o.opts.Key()
+	for {
+		if o.campaign(electionID) {
+			return
+		}
+
+		select {
+		case <-o.closedCh:
+			return
+		case <-time.After(o.opts.PollInterval()):
+		}
+	}
+}
+
+// campaign runs a single campaign until it ends, returning whether it ended
+// because the operator was closed.
+func (o *operator) campaign(electionID string) bool {
+	defer o.setLeader(false)
+
+	statusCh, err := o.leaderService.Campaign(electionID, o.opts.CampaignOptions())
+	if err != nil {
+		o.metrics.campaignErrors.Inc()
+		o.logger.Warn("unable to campaign for placement operation leadership",
+			zap.String("election", electionID), zap.Error(err))
+		return false
+	}
+
+	for {
+		select {
+		case <-o.closedCh:
+			if o.leading() {
+				if err := o.leaderService.Resign(electionID); err != nil {
+					o.logger.Warn("unable to resign placement operation leadership",
+						zap.String("election", electionID), zap.Error(err))
+				}
+			}
+			return true
+		case status, ok := <-statusCh:
+			if !ok {
+				return false
+			}
+			if status.State == campaign.Error {
+				o.metrics.campaignErrors.Inc()
+				o.logger.Warn("placement operation campaign failed",
+					zap.String("election", electionID), zap.Error(status.Err))
+			}
+			leader := status.State == campaign.Leader
+			if leader != o.leading() {
+				o.logger.Info("placement operation leadership changed",
+					zap.String("election", electionID), zap.Bool("leader", leader))
+			}
+			o.setLeader(leader)
+		}
+	}
+}
+
+// tick advances the stored operation by a step, returning whether the
+// operation is done.
+func (o *operator) tick() (bool, error) {
+	op, version, err := o.load()
+	if err == ErrNoOperation {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if op.State != Running {
+		return true, nil
+	}
+
+	next, changed, err := o.step(op)
+	if err != nil {
+		return false, err
+	}
+	if !changed {
+		return false, nil
+	}
+
+	next.UpdatedAt = o.nowFn()
+	// If the operation was cancelled or advanced concurrently the version
+	// no longer matches and the step is retried on the next tick, applying
+	// a batch is idempotent so this is safe.
+	if err := o.persist(next, version); err != nil {
+		return false, err
+	}
+	return next.State != Running, nil
+}
+
+// step advances the operation, returning whether it changed.
+func (o *operator) step(op Operation) (Operation, bool, error) {
+	now := o.nowFn()
+	if op.CurrentBatch >= len(op.Batches) {
+		return o.complete(op), true, nil
+	}
+	if timeout := o.opts.BatchTimeout(); now.Sub(op.BatchStartedAt) > timeout {
+		err := fmt.Errorf("batch %d did not complete within %v", op.CurrentBatch, timeout)
+		return o.fail(op, err), true, nil
+	}
+
+	p, err := o.service.Placement()
+	if err != nil {
+		return op, false, err
+	}
+	// Both before and after applying a batch all shards must be available,
+	// this gates each batch on the previous one and on changes made outside
+	// of the operation.
+	if !allAvailable(p) {
+		return op, false, nil
+	}
+
+	if !op.BatchApplied {
+		if err := o.apply(op, p); err != nil {
+			if isRetryable(err) {
+				// The batch is applied again on the next tick until the batch
+				// timeout, only invalid placement changes fail the operation.
+				return op, false, fmt.Errorf("unable to apply batch %d: %v",
+					op.CurrentBatch, err)
+			}
+			return o.fail(op, err), true, nil
+		}
+		op.BatchApplied = true
+		o.metrics.batchesApplied.Inc()
+		o.logger.Info("applied placement operation batch",
+			zap.String("id", op.ID),
+			zap.Int("batch", op.CurrentBatch),
+			zap.Strings("instances", op.Batches[op.CurrentBatch]))
+		return op, true, nil
+	}
+
+	for _, instance := range p.Instances() {
+		bootstrapped, err := o.health.Bootstrapped(instance)
+		if err != nil {
+			return op, false, fmt.Errorf("unable to check health of instance %s: %v",
+				instance.ID(), err)
+		}
+		if !bootstrapped {
+			return op, false, nil
+		}
+	}
+
+	o.metrics.batchesCompleted.Inc()
+	o.logger.Info("completed placement operation batch",
+		zap.String("id", op.ID),
+		zap.Int("batch", op.CurrentBatch))
+	op.CurrentBatch++
+	op.BatchApplied = false
+	op.BatchStartedAt = now
+	if op.CurrentBatch >= len(op.Batches) {
+		return o.complete(op), true, nil
+	}
+	return op, true, nil
+}
+
+// apply applies the current batch of the operation to the placement. It
+// skips the instances of the batch that are already applied so that a batch
+// interrupted between changing the placement and storing the operation can
+// be applied again.
+func (o *operator) apply(op Operation, p placement.Placement) error {
+	batch := op.Batches[op.CurrentBatch]
+	switch op.Type {
+	case ScaleOut:
+		var add []placement.Instance
+		for _, id := range batch {
+			if _, ok := p.Instance(id); ok {
+				continue
+			}
+			instance, err := operationInstance(op, id)
+			if err != nil {
+				return err
+			}
+			add = append(add, instance)
+		}
+		if len(add) == 0 {
+			return nil
+		}
+		_, _, err := o.service.AddInstances(add)
+		return err
+
+	case ScaleIn:
+		remove := notLeaving(p, batch)
+		if len(remove) == 0 {
+			return nil
+		}
+		_, err := o.service.RemoveInstances(remove)
+		return err
+
+	case Replace:
+		leaving := notLeaving(p, batch)
+		if len(leaving) == 0 {
+			return nil
+		}
+		var candidates []placement.Instance
+		for _, pb := range op.Instances {
+			if _, ok := p.Instance(pb.Id); ok {
+				continue
+			}
+			candidate, err := placement.NewInstanceFromProto(pb)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, candidate)
+		}
+		_, _, err := o.service.ReplaceInstances(leaving, candidates)
+		return err
+	}
+
+	return errInvalidOperation
+}
+
+func (o *operator) complete(op Operation) Operation {
+	op.State = Completed
+	o.metrics.completed.Inc()
+	o.logger.Info("completed placement operation", zap.String("id", op.ID))
+	return op
+}
+
+func (o *operator) fail(op Operation, err error) Operation {
+	op.State = Failed
+	op.Error = err.Error()
+	o.metrics.failed.Inc()
+	o.logger.Error("placement operation failed",
+		zap.String("id", op.ID),
+		zap.Int("batch", op.CurrentBatch),
+		zap.Error(err))
+	return op
+}
+
+func (o *operator) newOperation(req OperationRequest) (Operation, error) {
+	var ids []string
+	switch req.Type {
+	case ScaleOut:
+		if len(req.Instances) == 0 {
+			return Operation{}, errNoInstances
+		}
+		if len(req.InstanceIDs) > 0 {
+			return Operation{}, errUnexpectedIDs
+		}
+		for _, instance := range req.Instances {
+			ids = append(ids, instance.ID())
+		}
+	case ScaleIn:
+		if len(req.InstanceIDs) == 0 {
+			return Operation{}, errNoInstanceIDs
+		}
+		if len(req.Instances) > 0 {
+			return Operation{}, errUnexpectedInstances
+		}
+		ids = req.InstanceIDs
+	case Replace:
+		if len(req.InstanceIDs) == 0 {
+			return Operation{}, errNoInstanceIDs
+		}
+		if len(req.Instances) == 0 {
+			return Operation{}, errNoInstances
+		}
+		ids = req.InstanceIDs
+	default:
+		return Operation{}, errInvalidOperation
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return Operation{}, fmt.Errorf("duplicate instance %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	instances := make([]*placementpb.Instance, 0, len(req.Instances))
+	for _, instance := range req.Instances {
+		pb, err := instance.Proto()
+		if err != nil {
+			return Operation{}, err
+		}
+		instances = append(instances, pb)
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	var batches [][]string
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := make([]string, end-start)
+		copy(batch, ids[start:end])
+		batches = append(batches, batch)
+	}
+
+	now := o.nowFn()
+	return Operation{
+		ID:             strconv.FormatInt(now.UnixNano(), 10),
+		Type:           req.Type,
+		State:          Running,
+		Batches:        batches,
+		Instances:      instances,
+		BatchStartedAt: now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// load returns the stored operation and its version.
+func (o *operator) load() (Operation, int, error) {
+	value, err := o.store.Get(o.opts.Key())
+	if err == kv.ErrNotFound {
+		return Operation{}, 0, ErrNoOperation
+	}
+	if err != nil {
+		return Operation{}, 0, err
+	}
+
+	var str commonpb.StringProto
+	if err := value.Unmarshal(&str); err != nil {
+		return Operation{}, 0, err
+	}
+	var op Operation
+	if err := json.Unmarshal([]byte(str.Value), &op); err != nil {
+		return Operation{}, 0, err
+	}
+	return op, value.Version(), nil
+}
+
+// persist stores the operation if the stored one is still at the version.
+func (o *operator) persist(op Operation, version int) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	_, err = o.store.CheckAndSet(o.opts.Key(), version, &commonpb.StringProto{
+		Value: string(data),
+	})
+	return err
+}
+
+func operationInstance(op Operation, id string) (placement.Instance, error) {
+	for _, pb := range op.Instances {
+		if pb.Id == id {
+			return placement.NewInstanceFromProto(pb)
+		}
+	}
+	return nil, fmt.Errorf("instance %s not found in operation", id)
+}
+
+// notLeaving returns the instances that are in the placement and not yet
+// leaving it.
+func notLeaving(p placement.Placement, ids []string) []string {
+	var result []string
+	for _, id := range ids {
+		instance, ok := p.Instance(id)
+		if !ok || instance.IsLeaving() {
+			continue
+		}
+		result = append(result, id)
+	}
+	return result
+}
+
+// isRetryable returns whether applying a batch failed on a transient error,
+// such as a concurrent placement update or an unavailable KV store, rather
+// than on a placement change that cannot be made.
+func isRetryable(err error) bool {
+	switch err {
+	case kv.ErrVersionMismatch, kv.ErrConditionCheckFailed,
+		context.DeadlineExceeded, context.Canceled:
+		return true
+	}
+	if xerrors.IsRetryableError(err) {
+		return true
+	}
+
+	var code codes.Code
+	if coded, ok := err.(interface{ Code() codes.Code }); ok {
+		code = coded.Code()
+	} else if s, ok := status.FromError(err); ok {
+		code = s.Code()
+	}
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted,
+		codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+func allAvailable(p placement.Placement) bool {
+	for _, instance := range p.Instances() {
+		if instance.Shards().NumShards() != instance.Shards().NumShardsForState(shard.Available) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package operator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/service"
+	"github.com/m3db/m3/src/cluster/placement/storage"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testHealthChecker struct {
+	sync.Mutex
+
+	bootstrapped map[string]bool
+}
+
+func (c *testHealthChecker) Bootstrapped(instance placement.Instance) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	return c.bootstrapped[instance.ID()], nil
+}
+
+func (c *testHealthChecker) set(id string, bootstrapped bool) {
+	c.Lock()
+	c.bootstrapped[id] = bootstrapped
+	c.Unlock()
+}
+
+// failingPlacementService fails adding instances with the queued errors
+// before adding them.
+type failingPlacementService struct {
+	placement.Service
+
+	errs []error
+}
+
+func (s *failingPlacementService) AddInstances(
+	candidates []placement.Instance,
+) (placement.Placement, []placement.Instance, error) {
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, nil, err
+	}
+	return s.Service.AddInstances(candidates)
+}
+
+// testLeaderService hands out a campaign whose status is pushed by the test.
+type testLeaderService struct {
+	services.LeaderService
+	sync.Mutex
+
+	statusCh chan campaign.Status
+	resigned bool
+}
+
+func newTestLeaderService() *testLeaderService {
+	return &testLeaderService{statusCh: make(chan campaign.Status)}
+}
+
+func (l *testLeaderService) Campaign(
+	electionID string,
+	opts services.CampaignOptions,
+) (<-chan campaign.Status, error) {
+	return l.statusCh, nil
+}
+
+func (l *testLeaderService) Resign(electionID string) error {
+	l.Lock()
+	l.resigned = true
+	l.Unlock()
+	return nil
+}
+
+func (l *testLeaderService) isResigned() bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.resigned
+}
+
+type operatorTestSetup struct {
+	store  kv.Store
+	ps     placement.Service
+	health *testHealthChecker
+	now    time.Time
+	opts   Options
+}
+
+func newOperatorTestSetup(t *testing.T) *operatorTestSetup {
+	store := mem.NewStore()
+	pOpts := placement.NewOptions().SetValidZone("z1")
+	ps := service.NewPlacementService(storage.NewPlacementStorage(store, "placement", pOpts), pOpts)
+
+	_, err := ps.BuildInitialPlacement([]placement.Instance{
+		placement.NewEmptyInstance("i1", "r1", "z1", "i1:9000", 1),
+		placement.NewEmptyInstance("i2", "r2", "z1", "i2:9000", 1),
+		placement.NewEmptyInstance("i3", "r3", "z1", "i3:9000", 1),
+	}, 12, 3)
+	require.NoError(t, err)
+	_, err = ps.MarkAllShardsAvailable()
+	require.NoError(t, err)
+
+	s := &operatorTestSetup{
+		store: store,
+		ps:    ps,
+		health: &testHealthChecker{bootstrapped: map[string]bool{
+			"i1": true, "i2": true, "i3": true,
+		}},
+		now: time.Unix(1000, 0),
+	}
+	s.opts = NewOptions().
+		SetHealthChecker(s.health).
+		SetBatchTimeout(time.Hour).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return s.now
+		}))
+	return s
+}
+
+// newOperator returns an operator that is stepped by the test rather than by
+// its background loop.
+func (s *operatorTestSetup) newOperator(t *testing.T) *operator {
+	o, err := NewOperator(s.ps, s.store, s.opts)
+	require.NoError(t, err)
+	op := o.(*operator)
+	op.running = true
+	return op
+}
+
+func (s *operatorTestSetup) markAvailable(t *testing.T, ids ...string) {
+	_, err := s.ps.MarkAllShardsAvailable()
+	require.NoError(t, err)
+	for _, id := range ids {
+		s.health.set(id, true)
+	}
+}
+
+// waitForCompleted waits on the operator running the operation in the
+// background, marking the shards of applied batches available.
+func (s *operatorTestSetup) waitForCompleted(t *testing.T, o Operator) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		require.True(t, time.Now().Before(deadline), "operation did not complete")
+		op, err := o.Operation()
+		require.NoError(t, err)
+		if op.State == Completed {
+			return
+		}
+		if op.BatchApplied {
+			// Can race with the operator updating the placement, in which
+			// case it is retried on the next iteration.
+			s.ps.MarkAllShardsAvailable()
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func requireTick(t *testing.T, o *operator, expectDone bool) Operation {
+	done, err := o.tick()
+	require.NoError(t, err)
+	require.Equal(t, expectDone, done)
+	op, err := o.Operation()
+	require.NoError(t, err)
+	return op
+}
+
+func TestOperatorScaleOut(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	o := s.newOperator(t)
+
+	_, err := o.Operation()
+	require.Equal(t, ErrNoOperation, err)
+
+	op, err := o.Start(OperationRequest{
+		Type: ScaleOut,
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+			placement.NewEmptyInstance("i5", "r2", "z1", "i5:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Running, op.State)
+	assert.Equal(t, [][]string{{"i4"}, {"i5"}}, op.Batches)
+
+	_, err = o.Start(OperationRequest{
+		Type:        ScaleIn,
+		InstanceIDs: []string{"i1"},
+	})
+	require.Equal(t, ErrOperationInProgress, err)
+
+	// The first batch adds i4 only.
+	op = requireTick(t, o, false)
+	assert.True(t, op.BatchApplied)
+	p, err := s.ps.Placement()
+	require.NoError(t, err)
+	_, ok := p.Instance("i4")
+	assert.True(t, ok)
+	_, ok = p.Instance("i5")
+	assert.False(t, ok)
+
+	// The batch waits on shards becoming available and on i4 being
+	// bootstrapped.
+	requireTick(t, o, false)
+	s.markAvailable(t)
+	op = requireTick(t, o, false)
+	assert.Equal(t, 0, op.CurrentBatch)
+	s.health.set("i4", true)
+	op = requireTick(t, o, false)
+	assert.Equal(t, 1, op.CurrentBatch)
+	assert.False(t, op.BatchApplied)
+
+	// The second batch adds i5.
+	op = requireTick(t, o, false)
+	assert.True(t, op.BatchApplied)
+	s.markAvailable(t, "i5")
+	op = requireTick(t, o, true)
+	assert.Equal(t, Completed, op.State)
+
+	p, err = s.ps.Placement()
+	require.NoError(t, err)
+	assert.Equal(t, 5, p.NumInstances())
+}
+
+func TestOperatorResumeReappliesBatch(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	o := s.newOperator(t)
+
+	_, err := o.Start(OperationRequest{
+		Type:        Replace,
+		InstanceIDs: []string{"i1"},
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+	requireTick(t, o, false)
+
+	// Store the operation as if the coordinator restarted after changing
+	// the placement but before storing the batch as applied.
+	op, version, err := o.load()
+	require.NoError(t, err)
+	op.BatchApplied = false
+	require.NoError(t, o.persist(op, version))
+	s.markAvailable(t, "i4")
+
+	resumed := s.newOperator(t)
+	op = requireTick(t, resumed, false)
+	assert.True(t, op.BatchApplied)
+	assert.Empty(t, op.Error)
+	op = requireTick(t, resumed, true)
+	assert.Equal(t, Completed, op.State)
+
+	p, err := s.ps.Placement()
+	require.NoError(t, err)
+	_, ok := p.Instance("i1")
+	assert.False(t, ok)
+	_, ok = p.Instance("i4")
+	assert.True(t, ok)
+}
+
+func TestOperatorCancel(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	o := s.newOperator(t)
+
+	_, err := o.Cancel()
+	require.Equal(t, ErrNoOperation, err)
+
+	_, err = o.Start(OperationRequest{
+		Type:        ScaleIn,
+		InstanceIDs: []string{"i1"},
+	})
+	require.NoError(t, err)
+
+	op, err := o.Cancel()
+	require.NoError(t, err)
+	assert.Equal(t, Cancelled, op.State)
+
+	requireTick(t, o, true)
+	p, err := s.ps.Placement()
+	require.NoError(t, err)
+	instance, ok := p.Instance("i1")
+	require.True(t, ok)
+	assert.True(t, instance.IsAvailable())
+
+	_, err = o.Cancel()
+	require.Equal(t, ErrOperationNotRunning, err)
+}
+
+func TestOperatorBatchTimeout(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	o := s.newOperator(t)
+
+	_, err := o.Start(OperationRequest{
+		Type: ScaleOut,
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+	requireTick(t, o, false)
+
+	s.now = s.now.Add(2 * time.Hour)
+	op := requireTick(t, o, true)
+	assert.Equal(t, Failed, op.State)
+	assert.Contains(t, op.Error, "batch 0 did not complete")
+}
+
+func TestOperatorApplyErrors(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	s.ps = &failingPlacementService{
+		Service: s.ps,
+		errs:    []error{kv.ErrVersionMismatch, errors.New("not enough capacity")},
+	}
+	o := s.newOperator(t)
+
+	_, err := o.Start(OperationRequest{
+		Type: ScaleOut,
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+
+	// A concurrent placement update is retried on the next tick.
+	done, err := o.tick()
+	require.Error(t, err)
+	require.False(t, done)
+	op, err := o.Operation()
+	require.NoError(t, err)
+	assert.Equal(t, Running, op.State)
+	assert.False(t, op.BatchApplied)
+
+	// Any other error fails the operation.
+	op = requireTick(t, o, true)
+	assert.Equal(t, Failed, op.State)
+	assert.Equal(t, "not enough capacity", op.Error)
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		err       error
+		retryable bool
+	}{
+		{err: kv.ErrVersionMismatch, retryable: true},
+		{err: context.DeadlineExceeded, retryable: true},
+		{err: xerrors.NewRetryableError(errors.New("retry")), retryable: true},
+		{err: status.Error(codes.Unavailable, "unavailable"), retryable: true},
+		{err: status.Error(codes.InvalidArgument, "invalid"), retryable: false},
+		{err: kv.ErrNotFound, retryable: false},
+		{err: errors.New("instance not found"), retryable: false},
+	} {
+		assert.Equal(t, test.retryable, isRetryable(test.err), "%v", test.err)
+	}
+}
+
+func TestOperatorInvalidRequest(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	o := s.newOperator(t)
+
+	for _, req := range []OperationRequest{
+		{Type: ScaleOut},
+		{Type: ScaleIn},
+		{Type: Replace, InstanceIDs: []string{"i1"}},
+		{Type: ScaleIn, InstanceIDs: []string{"i1", "i1"}},
+		{Type: "unknown", InstanceIDs: []string{"i1"}},
+	} {
+		_, err := o.Start(req)
+		assert.True(t, xerrors.IsInvalidParams(err), "%v", req)
+	}
+}
+
+func TestOperatorRunLoop(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	s.opts = s.opts.SetPollInterval(time.Millisecond)
+	o, err := NewOperator(s.ps, s.store, s.opts)
+	require.NoError(t, err)
+
+	s.health.set("i4", true)
+	_, err = o.Start(OperationRequest{
+		Type: ScaleOut,
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+
+	s.waitForCompleted(t, o)
+	require.NoError(t, o.Close())
+}
+
+func TestOperatorRunsOnlyWhenLeader(t *testing.T) {
+	s := newOperatorTestSetup(t)
+	leaderService := newTestLeaderService()
+	campaignOpts, err := services.NewCampaignOptions()
+	require.NoError(t, err)
+	s.opts = s.opts.
+		SetPollInterval(time.Millisecond).
+		SetLeaderService(leaderService).
+		SetCampaignOptions(campaignOpts)
+	o, err := NewOperator(s.ps, s.store, s.opts)
+	require.NoError(t, err)
+
+	// The operator campaigns without an operation to resume.
+	require.NoError(t, o.Resume())
+	leaderService.statusCh <- campaign.NewStatus(campaign.Follower)
+
+	s.health.set("i4", true)
+	_, err = o.Start(OperationRequest{
+		Type: ScaleOut,
+		Instances: []placement.Instance{
+			placement.NewEmptyInstance("i4", "r1", "z1", "i4:9000", 1),
+		},
+	})
+	require.NoError(t, err)
+
+	// A follower leaves the operation to the leader.
+	time.Sleep(20 * time.Millisecond)
+	op, err := o.Operation()
+	require.NoError(t, err)
+	assert.False(t, op.BatchApplied)
+
+	leaderService.statusCh <- campaign.NewStatus(campaign.Leader)
+	s.waitForCompleted(t, o)
+
+	require.NoError(t, o.Close())
+	assert.True(t, leaderService.isResigned())
+}
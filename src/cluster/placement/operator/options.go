@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package operator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultKey          = "_placement_operation"
+	defaultPollInterval = 10 * time.Second
+	defaultBatchTimeout = 12 * time.Hour
+)
+
+var (
+	errNoHealthChecker     = errors.New("no health checker set")
+	errNoKey               = errors.New("no key set")
+	errInvalidPollInterval = errors.New("poll interval must be positive")
+	errInvalidBatchTimeout = errors.New("batch timeout must be positive")
+	errNoCampaignOptions   = errors.New("no campaign options set with leader service")
+)
+
+type options struct {
+	clockOpts      clock.Options
+	instrumentOpts instrument.Options
+	healthChecker  HealthChecker
+	leaderService  services.LeaderService
+	campaignOpts   services.CampaignOptions
+	key            string
+	pollInterval   time.Duration
+	batchTimeout   time.Duration
+}
+
+// NewOptions creates a new set of operator options.
+func NewOptions() Options {
+	return &options{
+		clockOpts:      clock.NewOptions(),
+		instrumentOpts: instrument.NewOptions(),
+		healthChecker:  NewHTTPHealthChecker(DefaultHealthCheckPort, defaultHealthCheckTimeout),
+		key:            defaultKey,
+		pollInterval:   defaultPollInterval,
+		batchTimeout:   defaultBatchTimeout,
+	}
+}
+
+func (o *options) Validate() error {
+	if o.healthChecker == nil {
+		return errNoHealthChecker
+	}
+	if o.key == "" {
+		return errNoKey
+	}
+	if o.pollInterval <= 0 {
+		return errInvalidPollInterval
+	}
+	if o.batchTimeout <= 0 {
+		return errInvalidBatchTimeout
+	}
+	if o.leaderService != nil && o.campaignOpts == nil {
+		return errNoCampaignOptions
+	}
+	return nil
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *options) SetHealthChecker(value HealthChecker) Options {
+	opts := *o
+	opts.healthChecker = value
+	return &opts
+}
+
+func (o *options) HealthChecker() HealthChecker {
+	return o.healthChecker
+}
+
+func (o *options) SetLeaderService(value services.LeaderService) Options {
+	opts := *o
+	opts.leaderService = value
+	return &opts
+}
+
+func (o *options) LeaderService() services.LeaderService {
+	return o.leaderService
+}
+
+func (o *options) SetCampaignOptions(value services.CampaignOptions) Options {
+	opts := *o
+	opts.campaignOpts = value
+	return &opts
+}
+
+func (o *options) CampaignOptions() services.CampaignOptions {
+	return o.campaignOpts
+}
+
+func (o *options) SetKey(value string) Options {
+	opts := *o
+	opts.key = value
+	return &opts
+}
+
+func (o *options) Key() string {
+	return o.key
+}
+
+func (o *options) SetPollInterval(value time.Duration) Options {
+	opts := *o
+	opts.pollInterval = value
+	return &opts
+}
+
+func (o *options) PollInterval() time.Duration {
+	return o.pollInterval
+}
+
+func (o *options) SetBatchTimeout(value time.Duration) Options {
+	opts := *o
+	opts.batchTimeout = value
+	return &opts
+}
+
+func (o *options) BatchTimeout() time.Duration {
+	return o.batchTimeout
+}
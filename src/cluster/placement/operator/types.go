@@ -0,0 +1,227 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package operator runs multi-step placement changes one batch at a time,
+// waiting for each batch to be bootstrapped before moving on to the next.
+package operator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	// ErrNoOperation is returned when there is no operation stored.
+	ErrNoOperation = errors.New("no placement operation")
+
+	// ErrOperationInProgress is returned when starting an operation while
+	// another one is still running.
+	ErrOperationInProgress = errors.New("placement operation already in progress")
+
+	// ErrOperationNotRunning is returned when cancelling an operation that
+	// is not running.
+	ErrOperationNotRunning = errors.New("placement operation is not running")
+)
+
+// OperationType is the type of a placement operation.
+type OperationType string
+
+const (
+	// ScaleOut adds instances to the placement.
+	ScaleOut OperationType = "scale_out"
+
+	// ScaleIn removes instances from the placement.
+	ScaleIn OperationType = "scale_in"
+
+	// Replace replaces instances of the placement with candidates, for
+	// example to replace a rack.
+	Replace OperationType = "replace"
+)
+
+// OperationState is the state of a placement operation.
+type OperationState string
+
+const (
+	// Running means the operation is still applying or waiting on batches.
+	Running OperationState = "running"
+
+	// Completed means all batches of the operation are done.
+	Completed OperationState = "completed"
+
+	// Failed means the operation stopped on an error.
+	Failed OperationState = "failed"
+
+	// Cancelled means the operation was cancelled before it completed.
+	Cancelled OperationState = "cancelled"
+)
+
+// OperationRequest is a request to start a placement operation.
+type OperationRequest struct {
+	// Type is the type of the operation.
+	Type OperationType
+
+	// Instances are the instances to add for a scale out or the candidates
+	// to replace instances with for a replace.
+	Instances []placement.Instance
+
+	// InstanceIDs are the instances to remove for a scale in or the
+	// instances to replace for a replace.
+	InstanceIDs []string
+
+	// BatchSize is the number of instances added, removed or replaced at
+	// a time, defaults to one.
+	BatchSize int
+}
+
+// Operation is the progress of a placement operation, it is stored in KV so
+// that the operation resumes where it left off.
+type Operation struct {
+	// ID uniquely identifies the operation.
+	ID string `json:"id"`
+
+	// Type is the type of the operation.
+	Type OperationType `json:"type"`
+
+	// State is the state of the operation.
+	State OperationState `json:"state"`
+
+	// Batches are the instance IDs added, removed or replaced by each batch.
+	Batches [][]string `json:"batches"`
+
+	// Instances are the instances added by a scale out or the candidates of
+	// a replace.
+	Instances []*placementpb.Instance `json:"instances,omitempty"`
+
+	// CurrentBatch is the index of the batch in progress.
+	CurrentBatch int `json:"currentBatch"`
+
+	// BatchApplied is whether the current batch has been applied to the
+	// placement and is being waited on.
+	BatchApplied bool `json:"batchApplied"`
+
+	// BatchStartedAt is when the current batch started.
+	BatchStartedAt time.Time `json:"batchStartedAt"`
+
+	// CreatedAt is when the operation was started.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the operation was last updated.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Error is why the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Operator runs placement operations.
+type Operator interface {
+	// Start starts a new operation, it fails with ErrOperationInProgress if
+	// another operation is still running.
+	Start(req OperationRequest) (Operation, error)
+
+	// Operation returns the most recent operation, or ErrNoOperation if
+	// there is none.
+	Operation() (Operation, error)
+
+	// Cancel cancels the running operation. Batches already applied to the
+	// placement are not reverted.
+	Cancel() (Operation, error)
+
+	// Resume continues running a stored operation that is still running,
+	// for example after a restart. With a leader service it also starts
+	// campaigning so that only the elected operator runs operations.
+	Resume() error
+
+	// Close stops running operations and gives up leadership, they are
+	// resumed by the next operator on the same key.
+	Close() error
+}
+
+// HealthChecker checks the health of placement instances.
+type HealthChecker interface {
+	// Bootstrapped returns whether the instance is bootstrapped with the
+	// shards it owns in the placement.
+	Bootstrapped(instance placement.Instance) (bool, error)
+}
+
+// Options are the operator options.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetHealthChecker sets the health checker used to wait on batches.
+	SetHealthChecker(value HealthChecker) Options
+
+	// HealthChecker returns the health checker used to wait on batches.
+	HealthChecker() HealthChecker
+
+	// SetLeaderService sets the leader service used to elect the single
+	// operator that runs operations among the operators on the same key,
+	// without one every operator runs the operations it starts or resumes.
+	SetLeaderService(value services.LeaderService) Options
+
+	// LeaderService returns the leader service used to elect the single
+	// operator that runs operations among the operators on the same key.
+	LeaderService() services.LeaderService
+
+	// SetCampaignOptions sets the options used to campaign for leadership.
+	SetCampaignOptions(value services.CampaignOptions) Options
+
+	// CampaignOptions returns the options used to campaign for leadership.
+	CampaignOptions() services.CampaignOptions
+
+	// SetKey sets the KV key the operation is stored at, it is also the
+	// election ID when campaigning.
+	SetKey(value string) Options
+
+	// Key returns the KV key the operation is stored at.
+	Key() string
+
+	// SetPollInterval sets how often the operation checks on its batch.
+	SetPollInterval(value time.Duration) Options
+
+	// PollInterval returns how often the operation checks on its batch.
+	PollInterval() time.Duration
+
+	// SetBatchTimeout sets how long a batch may take before the operation
+	// fails.
+	SetBatchTimeout(value time.Duration) Options
+
+	// BatchTimeout returns how long a batch may take before the operation
+	// fails.
+	BatchTimeout() time.Duration
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package planner
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+)
+
+var errNilPlacement = errors.New("current and target placements must not be nil")
+
+type changePlanner struct {
+	opts ChangePlannerOptions
+}
+
+// NewChangePlanner returns a new change planner.
+func NewChangePlanner(opts ChangePlannerOptions) ChangePlanner {
+	return changePlanner{opts: opts}
+}
+
+func (cp changePlanner) Plan(current, target placement.Placement) (ChangePlan, error) {
+	if current == nil || target == nil {
+		return ChangePlan{}, errNilPlacement
+	}
+
+	var (
+		before  = heldShards(current)
+		after   = heldShards(target)
+		sizer   = newShardSizer(cp.opts.ShardSizes())
+		changes = make(map[string]*InstanceChange)
+		moved   = make(map[uint32]struct{})
+		plan    ChangePlan
+	)
+	instanceChange := func(id string) *InstanceChange {
+		if c, ok := changes[id]; ok {
+			return c
+		}
+		c := &InstanceChange{ID: id}
+		if instance, ok := target.Instance(id); ok {
+			c.IsolationGroup = instance.IsolationGroup()
+		} else if instance, ok := current.Instance(id); ok {
+			c.IsolationGroup = instance.IsolationGroup()
+		}
+		if _, ok := current.Instance(id); !ok {
+			c.Added = true
+		}
+		changes[id] = c
+		return c
+	}
+
+	for _, instance := range target.Instances() {
+		id := instance.ID()
+		if _, ok := current.Instance(id); !ok {
+			instanceChange(id)
+		}
+		for shardID := range after[id] {
+			if _, ok := before[id][shardID]; ok {
+				continue
+			}
+
+			var from string
+			if s, ok := instance.Shards().Shard(shardID); ok {
+				from = s.SourceID()
+			}
+			bytes := sizer.size(shardID)
+			plan.Moves = append(plan.Moves, ShardMove{
+				Shard: shardID,
+				From:  from,
+				To:    id,
+				Bytes: bytes,
+			})
+			plan.TotalBytes += bytes
+			moved[shardID] = struct{}{}
+
+			c := instanceChange(id)
+			c.ShardsIn = append(c.ShardsIn, shardID)
+			c.BytesIn += bytes
+			if from != "" {
+				instanceChange(from).BytesOut += bytes
+			}
+		}
+	}
+
+	for _, instance := range current.Instances() {
+		id := instance.ID()
+		_, inTarget := target.Instance(id)
+		for shardID := range before[id] {
+			if _, ok := after[id][shardID]; ok {
+				continue
+			}
+			c := instanceChange(id)
+			c.ShardsOut = append(c.ShardsOut, shardID)
+		}
+		if !inTarget || (len(before[id]) > 0 && len(after[id]) == 0) {
+			instanceChange(id).Removed = true
+		}
+	}
+
+	rate := cp.opts.StreamBytesPerSecond()
+	plan.Instances = make([]InstanceChange, 0, len(changes))
+	for _, c := range changes {
+		sortShardIDs(c.ShardsIn)
+		sortShardIDs(c.ShardsOut)
+		streamed := c.BytesIn
+		if c.BytesOut > streamed {
+			streamed = c.BytesOut
+		}
+		c.EstimatedDuration = streamDuration(streamed, rate)
+		if c.EstimatedDuration > plan.EstimatedDuration {
+			plan.EstimatedDuration = c.EstimatedDuration
+		}
+		plan.Instances = append(plan.Instances, *c)
+	}
+	sort.Slice(plan.Instances, func(i, j int) bool {
+		return plan.Instances[i].ID < plan.Instances[j].ID
+	})
+	sort.Slice(plan.Moves, func(i, j int) bool {
+		if plan.Moves[i].Shard != plan.Moves[j].Shard {
+			return plan.Moves[i].Shard < plan.Moves[j].Shard
+		}
+		return plan.Moves[i].To < plan.Moves[j].To
+	})
+
+	plan.UnderReplicated = underReplicatedShards(target)
+	plan.ShardsMoved = len(moved)
+	plan.UnsizedShards = shardIDs(sizer.unsized)
+	return plan, nil
+}
+
+// heldShards returns the shards each instance of the placement holds or is
+// receiving, leaving shards are excluded.
+func heldShards(p placement.Placement) map[string]map[uint32]struct{} {
+	held := make(map[string]map[uint32]struct{}, p.NumInstances())
+	for _, instance := range p.Instances() {
+		shards := make(map[uint32]struct{}, instance.Shards().NumShards())
+		for _, s := range instance.Shards().All() {
+			if s.State() == shard.Leaving {
+				continue
+			}
+			shards[s.ID()] = struct{}{}
+		}
+		held[instance.ID()] = shards
+	}
+	return held
+}
+
+// underReplicatedShards returns the shards of the placement with fewer
+// available replicas than its replica factor, sorted by ID.
+func underReplicatedShards(p placement.Placement) []UnderReplicatedShard {
+	availableReplicas := make(map[uint32]int, p.NumShards())
+	for _, instance := range p.Instances() {
+		for _, s := range instance.Shards().ShardsForState(shard.Available) {
+			availableReplicas[s.ID()]++
+		}
+	}
+
+	var under []UnderReplicatedShard
+	for _, shardID := range p.Shards() {
+		if n := availableReplicas[shardID]; n < p.ReplicaFactor() {
+			under = append(under, UnderReplicatedShard{
+				Shard:             shardID,
+				AvailableReplicas: n,
+			})
+		}
+	}
+	sort.Slice(under, func(i, j int) bool {
+		return under[i].Shard < under[j].Shard
+	})
+	return under
+}
+
+// shardSizer estimates the size of shards from size reports, shards without
+// a report are estimated as the mean of the reported sizes.
+type shardSizer struct {
+	sizes   map[uint32]int64
+	mean    int64
+	unsized map[uint32]struct{}
+}
+
+func newShardSizer(sizes map[uint32]int64) *shardSizer {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	var mean int64
+	if len(sizes) > 0 {
+		mean = total / int64(len(sizes))
+	}
+	return &shardSizer{
+		sizes:   sizes,
+		mean:    mean,
+		unsized: make(map[uint32]struct{}),
+	}
+}
+
+func (s *shardSizer) size(shardID uint32) int64 {
+	if size, ok := s.sizes[shardID]; ok {
+		return size
+	}
+	s.unsized[shardID] = struct{}{}
+	return s.mean
+}
+
+func streamDuration(bytes, bytesPerSecond int64) time.Duration {
+	if bytes <= 0 || bytesPerSecond <= 0 {
+		return 0
+	}
+	return time.Duration(float64(bytes) / float64(bytesPerSecond) * float64(time.Second))
+}
+
+func shardIDs(shards map[uint32]struct{}) []uint32 {
+	if len(shards) == 0 {
+		return nil
+	}
+	ids := make([]uint32, 0, len(shards))
+	for id := range shards {
+		ids = append(ids, id)
+	}
+	sortShardIDs(ids)
+	return ids
+}
+
+func sortShardIDs(ids []uint32) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInstance(id, group string, shards ...shard.Shard) placement.Instance {
+	instance := placement.NewEmptyInstance(id, group, "z1", id+":9000", 1)
+	for _, s := range shards {
+		instance.Shards().Add(s)
+	}
+	return instance
+}
+
+func available(id uint32) shard.Shard {
+	return shard.NewShard(id).SetState(shard.Available)
+}
+
+func leaving(id uint32) shard.Shard {
+	return shard.NewShard(id).SetState(shard.Leaving)
+}
+
+func initializing(id uint32, source string) shard.Shard {
+	return shard.NewShard(id).SetState(shard.Initializing).SetSourceID(source)
+}
+
+func newTestPlacement(instances ...placement.Instance) placement.Placement {
+	return placement.NewPlacement().
+		SetInstances(instances).
+		SetShards([]uint32{1, 2, 3, 4}).
+		SetReplicaFactor(2).
+		SetIsSharded(true)
+}
+
+func TestChangePlannerPlan(t *testing.T) {
+	current := newTestPlacement(
+		newTestInstance("i1", "r1", available(1), available(2)),
+		newTestInstance("i2", "r1", available(3), available(4)),
+		newTestInstance("i3", "r2", available(1), available(2), available(3), available(4)),
+	)
+	// i4 is added in a new isolation group and takes over all of the shards
+	// of i2 and shard 2 from i1.
+	target := newTestPlacement(
+		newTestInstance("i1", "r1", available(1), leaving(2)),
+		newTestInstance("i2", "r1", leaving(3), leaving(4)),
+		newTestInstance("i3", "r2", available(1), available(2), available(3), available(4)),
+		newTestInstance("i4", "r3", initializing(2, "i1"), initializing(3, "i2"), initializing(4, "i2")),
+	)
+
+	opts := NewChangePlannerOptions().
+		SetShardSizes(map[uint32]int64{1: 50, 2: 100, 3: 150}).
+		SetStreamBytesPerSecond(100)
+	plan, err := NewChangePlanner(opts).Plan(current, target)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ShardMove{
+		{Shard: 2, From: "i1", To: "i4", Bytes: 100},
+		{Shard: 3, From: "i2", To: "i4", Bytes: 150},
+		// Shard 4 has no size report so it is estimated as the mean.
+		{Shard: 4, From: "i2", To: "i4", Bytes: 100},
+	}, plan.Moves)
+	assert.Equal(t, []InstanceChange{
+		{
+			ID:                "i1",
+			IsolationGroup:    "r1",
+			ShardsOut:         []uint32{2},
+			BytesOut:          100,
+			EstimatedDuration: time.Second,
+		},
+		{
+			ID:                "i2",
+			IsolationGroup:    "r1",
+			Removed:           true,
+			ShardsOut:         []uint32{3, 4},
+			BytesOut:          250,
+			EstimatedDuration: 2500 * time.Millisecond,
+		},
+		{
+			ID:                "i4",
+			IsolationGroup:    "r3",
+			Added:             true,
+			ShardsIn:          []uint32{2, 3, 4},
+			BytesIn:           350,
+			EstimatedDuration: 3500 * time.Millisecond,
+		},
+	}, plan.Instances)
+	// Shards 2, 3 and 4 only have their replica on i3 available until i4
+	// has streamed them.
+	assert.Equal(t, []UnderReplicatedShard{
+		{Shard: 2, AvailableReplicas: 1},
+		{Shard: 3, AvailableReplicas: 1},
+		{Shard: 4, AvailableReplicas: 1},
+	}, plan.UnderReplicated)
+	assert.Equal(t, 3, plan.ShardsMoved)
+	assert.Equal(t, int64(350), plan.TotalBytes)
+	assert.Equal(t, 3500*time.Millisecond, plan.EstimatedDuration)
+	assert.Equal(t, []uint32{4}, plan.UnsizedShards)
+}
+
+func TestChangePlannerPlanWithinIsolationGroup(t *testing.T) {
+	current := newTestPlacement(
+		newTestInstance("i1", "r1", available(1), available(2)),
+		newTestInstance("i2", "r2", available(1), available(2)),
+	)
+	// Replacing an instance within its isolation group leaves the shards
+	// with one available replica until the new one is available, the
+	// leaving replica is not counted.
+	target := newTestPlacement(
+		newTestInstance("i1", "r1", leaving(1), leaving(2)),
+		newTestInstance("i2", "r2", available(1), available(2)),
+		newTestInstance("i3", "r1", initializing(1, "i1"), initializing(2, "i1")),
+	)
+
+	plan, err := NewChangePlanner(NewChangePlannerOptions().
+		SetStreamBytesPerSecond(0)).Plan(current, target)
+	require.NoError(t, err)
+	assert.Len(t, plan.Moves, 2)
+	assert.Equal(t, []UnderReplicatedShard{
+		{Shard: 1, AvailableReplicas: 1},
+		{Shard: 2, AvailableReplicas: 1},
+	}, plan.UnderReplicated)
+	assert.Equal(t, time.Duration(0), plan.EstimatedDuration)
+	assert.Equal(t, []uint32{1, 2}, plan.UnsizedShards)
+}
+
+func TestChangePlannerPlanNoChange(t *testing.T) {
+	current := newTestPlacement(
+		newTestInstance("i1", "r1", available(1), available(2)),
+		newTestInstance("i2", "r2", available(1), available(2)),
+	)
+	plan, err := NewChangePlanner(NewChangePlannerOptions()).Plan(current, current.Clone())
+	require.NoError(t, err)
+	assert.Equal(t, ChangePlan{Instances: []InstanceChange{}}, plan)
+
+	_, err = NewChangePlanner(NewChangePlannerOptions()).Plan(current, nil)
+	require.Error(t, err)
+}
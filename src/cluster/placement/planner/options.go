@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package planner
+
+const (
+	// defaultStreamBytesPerSecond is a conservative estimate of the rate at
+	// which a peer bootstrapping instance streams shard data.
+	defaultStreamBytesPerSecond = 64 << 20
+)
+
+type changePlannerOptions struct {
+	shardSizes           map[uint32]int64
+	streamBytesPerSecond int64
+}
+
+// NewChangePlannerOptions returns a new set of change planner options.
+func NewChangePlannerOptions() ChangePlannerOptions {
+	return changePlannerOptions{
+		streamBytesPerSecond: defaultStreamBytesPerSecond,
+	}
+}
+
+func (o changePlannerOptions) ShardSizes() map[uint32]int64 {
+	return o.shardSizes
+}
+
+func (o changePlannerOptions) SetShardSizes(value map[uint32]int64) ChangePlannerOptions {
+	o.shardSizes = value
+	return o
+}
+
+func (o changePlannerOptions) StreamBytesPerSecond() int64 {
+	return o.streamBytesPerSecond
+}
+
+func (o changePlannerOptions) SetStreamBytesPerSecond(value int64) ChangePlannerOptions {
+	o.streamBytesPerSecond = value
+	return o
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package planner
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+)
+
+// ChangePlanner previews the impact of moving from one placement to another.
+type ChangePlanner interface {
+	// Plan diffs the current placement with the target placement, typically
+	// the result of a dry run placement change, and estimates the cost of
+	// the shard movements between them.
+	Plan(current, target placement.Placement) (ChangePlan, error)
+}
+
+// ChangePlannerOptions provides options for a ChangePlanner.
+type ChangePlannerOptions interface {
+	// ShardSizes returns the reported size in bytes of a replica of each shard.
+	ShardSizes() map[uint32]int64
+
+	// SetShardSizes sets the reported size in bytes of a replica of each shard.
+	SetShardSizes(value map[uint32]int64) ChangePlannerOptions
+
+	// StreamBytesPerSecond returns the rate at which an instance is expected
+	// to stream shard data in or out, zero disables duration estimates.
+	StreamBytesPerSecond() int64
+
+	// SetStreamBytesPerSecond sets the rate at which an instance is expected
+	// to stream shard data in or out, zero disables duration estimates.
+	SetStreamBytesPerSecond(value int64) ChangePlannerOptions
+}
+
+// ChangePlan describes the shard movements of a placement change.
+type ChangePlan struct {
+	// Moves are the shard replicas that instances will receive, sorted by
+	// shard and then receiving instance.
+	Moves []ShardMove
+
+	// Instances are the instances that are added, removed, receive or give
+	// up shards, sorted by instance ID.
+	Instances []InstanceChange
+
+	// UnderReplicated are the shards with fewer available replicas than the
+	// replica factor in the target placement until the receiving instances
+	// have streamed them, sorted by shard.
+	UnderReplicated []UnderReplicatedShard
+
+	// ShardsMoved is the number of distinct shards with moving replicas.
+	ShardsMoved int
+
+	// TotalBytes is the estimated number of bytes streamed by all moves.
+	TotalBytes int64
+
+	// EstimatedDuration is the estimated time for all instances to finish
+	// streaming, instances stream in parallel.
+	EstimatedDuration time.Duration
+
+	// UnsizedShards are the moving shards without a size report, their size
+	// is estimated as the mean of the reported shard sizes.
+	UnsizedShards []uint32
+}
+
+// ShardMove is a shard replica received by an instance.
+type ShardMove struct {
+	// Shard is the ID of the shard.
+	Shard uint32
+
+	// From is the instance the shard is streamed from, empty if the target
+	// placement does not name a source.
+	From string
+
+	// To is the instance that receives the shard.
+	To string
+
+	// Bytes is the estimated size of the shard replica.
+	Bytes int64
+}
+
+// InstanceChange is the shard movement of a single instance.
+type InstanceChange struct {
+	// ID is the ID of the instance.
+	ID string
+
+	// IsolationGroup is the isolation group of the instance.
+	IsolationGroup string
+
+	// Added is true if the instance is not in the current placement.
+	Added bool
+
+	// Removed is true if the instance holds no shards in the target
+	// placement.
+	Removed bool
+
+	// ShardsIn are the shards the instance receives, sorted by ID.
+	ShardsIn []uint32
+
+	// ShardsOut are the shards the instance gives up, sorted by ID.
+	ShardsOut []uint32
+
+	// BytesIn is the estimated number of bytes the instance receives.
+	BytesIn int64
+
+	// BytesOut is the estimated number of bytes streamed from the instance.
+	BytesOut int64
+
+	// EstimatedDuration is the estimated time for the instance to finish
+	// streaming data in and out.
+	EstimatedDuration time.Duration
+}
+
+// UnderReplicatedShard is a shard with fewer available replicas than the
+// replica factor while the change is in progress.
+type UnderReplicatedShard struct {
+	// Shard is the ID of the shard.
+	Shard uint32
+
+	// AvailableReplicas is the number of available replicas of the shard in
+	// the target placement.
+	AvailableReplicas int
+}
@@ -453,6 +453,19 @@ type Options interface {
 	SetNowFn(fn clock.NowFn) Options
 }
 
+// ShardLoad is the observed load of a shard, used to balance the shards of
+// a placement on load rather than shard count.
+type ShardLoad struct {
+	// SeriesCount is the number of series in the shard.
+	SeriesCount int64
+
+	// WriteRate is the rate of datapoints written to the shard per second.
+	WriteRate float64
+
+	// DiskBytes is the size of the shard on disk.
+	DiskBytes int64
+}
+
 // ShardStateMode describes the way to manage shard state in the placement.
 type ShardStateMode int
 
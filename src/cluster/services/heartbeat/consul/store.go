@@ -0,0 +1,475 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/watch"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	heartbeatKeyPrefix = "_hb"
+	keySeparator       = "/"
+	keyFormat          = "%s/%s"
+
+	// NB: consul holds back locks released by an invalidated session for the
+	// lock delay, disable it so an instance can heartbeat again right away.
+	sessionLockDelay = "0s"
+)
+
+var (
+	noopCancel     = func() {}
+	errNoServiceID = errors.New("ServiceID cannot be empty")
+)
+
+// NewStore creates a heartbeat store based on consul. Each heartbeat key is
+// locked by a consul session with the heartbeat ttl that deletes the key when
+// it expires.
+func NewStore(c consulapi.Client, opts Options) (services.HeartbeatService, error) {
+	if opts.ServiceID() == nil {
+		return nil, errNoServiceID
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	scope := opts.InstrumentsOptions().MetricsScope()
+
+	return &client{
+		cache:      newSessionCache(),
+		watchables: make(map[string]watch.Watchable),
+		opts:       opts,
+		sid:        opts.ServiceID(),
+		logger:     opts.InstrumentsOptions().Logger(),
+		consul:     c,
+		m: clientMetrics{
+			consulGetError:     scope.Counter("consul-get-error"),
+			consulPutError:     scope.Counter("consul-put-error"),
+			consulSessionError: scope.Counter("consul-session-error"),
+			consulWatchError:   scope.Counter("consul-watch-error"),
+		},
+	}, nil
+}
+
+type client struct {
+	sync.RWMutex
+
+	cache      *sessionCache
+	watchables map[string]watch.Watchable
+	opts       Options
+	sid        services.ServiceID
+	logger     *zap.Logger
+	consul     consulapi.Client
+	m          clientMetrics
+}
+
+type clientMetrics struct {
+	consulGetError     tally.Counter
+	consulPutError     tally.Counter
+	consulSessionError tally.Counter
+	consulWatchError   tally.Counter
+}
+
+func (c *client) Heartbeat(instance placement.Instance, ttl time.Duration) error {
+	key := heartbeatKey(c.sid, instance.ID())
+	sessionID, ok := c.cache.get(key, ttl)
+	if ok {
+		ctx, cancel := c.context()
+		defer cancel()
+
+		err := c.consul.RenewSession(ctx, sessionID)
+		// if err != nil, it could because the old session has already timedout
+		// on the server side, we need to try a new session.
+		if err == nil {
+			return nil
+		}
+	}
+
+	instanceProto, err := instance.Proto()
+	if err != nil {
+		return err
+	}
+
+	instanceBytes, err := proto.Marshal(instanceProto)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	sessionID, err = c.consul.CreateSession(ctx, &consulapi.SessionEntry{
+		Name:      key,
+		Behavior:  consulapi.SessionBehaviorDelete,
+		TTL:       ttl.String(),
+		LockDelay: sessionLockDelay,
+	})
+	if err != nil {
+		c.m.consulSessionError.Inc(1)
+		return err
+	}
+
+	if err := c.acquire(key, sessionID, instanceBytes); err != nil {
+		c.destroySession(sessionID)
+		return err
+	}
+
+	c.cache.put(key, ttl, sessionID)
+
+	return nil
+}
+
+// acquire writes the heartbeat key locked by the session. The key is only
+// ever heartbeated by the instance it belongs to, so a lock held by another
+// session is left over from a previous heartbeat of the instance and is
+// taken over.
+func (c *client) acquire(key, sessionID string, value []byte) error {
+	pair := &consulapi.KVPair{Key: key, Value: value, Session: sessionID}
+	for attempt := 0; attempt < 2; attempt++ {
+		ctx, cancel := c.context()
+		ok, err := c.consul.Acquire(ctx, pair)
+		cancel()
+		if err != nil {
+			c.m.consulPutError.Inc(1)
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		ctx, cancel = c.context()
+		existing, _, err := c.consul.Get(ctx, key, nil)
+		cancel()
+		if err != nil {
+			c.m.consulGetError.Inc(1)
+			return err
+		}
+		if existing != nil && existing.Session != "" {
+			c.destroySession(existing.Session)
+		}
+	}
+
+	return fmt.Errorf("could not acquire heartbeat key %s", key)
+}
+
+func (c *client) destroySession(sessionID string) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if err := c.consul.DestroySession(ctx, sessionID); err != nil {
+		c.m.consulSessionError.Inc(1)
+		c.logger.Warn("could not destroy session", zap.String("session", sessionID), zap.Error(err))
+	}
+}
+
+func (c *client) Get() ([]string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	key := servicePrefix(c.sid)
+	keys, _, err := c.consul.Keys(ctx, key+keySeparator, nil)
+	if err != nil {
+		c.m.consulGetError.Inc(1)
+		return nil, err
+	}
+
+	return instancesFromKeys(keys, key), nil
+}
+
+func (c *client) GetInstances() ([]placement.Instance, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	pairs, _, err := c.consul.List(ctx, servicePrefix(c.sid)+keySeparator, nil)
+	if err != nil {
+		c.m.consulGetError.Inc(1)
+		return nil, err
+	}
+
+	r := make([]placement.Instance, len(pairs))
+	for i, pair := range pairs {
+		var p placementpb.Instance
+		if err := proto.Unmarshal(pair.Value, &p); err != nil {
+			return nil, err
+		}
+
+		pi, err := placement.NewInstanceFromProto(&p)
+		if err != nil {
+			return nil, err
+		}
+
+		r[i] = pi
+	}
+	return r, nil
+}
+
+func (c *client) Delete(instance string) error {
+	key := heartbeatKey(c.sid, instance)
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	existing, _, err := c.consul.Get(ctx, key, nil)
+	if err != nil {
+		c.m.consulGetError.Inc(1)
+		return err
+	}
+
+	if existing == nil {
+		return fmt.Errorf("could not find heartbeat for service: %s, env: %s, instance: %s", c.sid.Name(), c.sid.Environment(), instance)
+	}
+
+	ok, err := c.consul.DeleteCAS(ctx, existing)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("heartbeat for service: %s, env: %s, instance: %s was modified concurrently", c.sid.Name(), c.sid.Environment(), instance)
+	}
+
+	// NB: clean up the cached session, if not the next heartbeat would renew
+	// the session which no longer holds the deleted key.
+	if sessionID, ok := c.cache.delete(key); ok {
+		c.destroySession(sessionID)
+	}
+	return nil
+}
+
+func (c *client) Watch() (watch.Watch, error) {
+	serviceKey := servicePrefix(c.sid)
+
+	c.Lock()
+	watchable, ok := c.watchables[serviceKey]
+	if !ok {
+		watchable = watch.NewWatchable()
+		c.watchables[serviceKey] = watchable
+
+		go c.watch(serviceKey)
+	}
+	c.Unlock()
+
+	_, w, err := watchable.Watch()
+	return w, err
+}
+
+// watch issues blocking queries for the heartbeats of the service until the
+// watchable no longer has any watches.
+func (c *client) watch(key string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(c.opts.WatchCheckInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if c.tickAndStop(key) {
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var index uint64
+	for {
+		keys, meta, err := c.consul.Keys(ctx, key+keySeparator, &consulapi.QueryOptions{
+			WaitIndex: index,
+			WaitTime:  c.opts.WatchWaitTime(),
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.m.consulWatchError.Inc(1)
+			c.logger.Warn("consul watch failed", zap.String("key", key), zap.Error(err))
+
+			select {
+			case <-time.After(c.opts.WatchRetryInterval()):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		if index != 0 && meta.LastIndex == index {
+			// the blocking query timed out without any change
+			continue
+		}
+
+		// NB: consul resets the index if its state is restored from a
+		// snapshot, start over rather than blocking until it catches up.
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		c.RLock()
+		w, ok := c.watchables[key]
+		c.RUnlock()
+		if !ok {
+			c.logger.Warn("unexpected: no watchable found for key", zap.String("key", key))
+			continue
+		}
+		w.Update(instancesFromKeys(keys, key))
+	}
+}
+
+func (c *client) tickAndStop(key string) bool {
+	// fast path
+	c.RLock()
+	watchable, ok := c.watchables[key]
+	c.RUnlock()
+	if !ok {
+		c.logger.Warn("unexpected: key is already cleaned up", zap.String("key", key))
+		return true
+	}
+
+	if watchable.NumWatches() != 0 {
+		return false
+	}
+
+	// slow path
+	c.Lock()
+	defer c.Unlock()
+	watchable, ok = c.watchables[key]
+	if !ok {
+		// not expect this to happen
+		c.logger.Warn("unexpected: key is already cleaned up", zap.String("key", key))
+		return true
+	}
+
+	if watchable.NumWatches() != 0 {
+		// a new watch has subscribed to the watchable, do not clean up
+		return false
+	}
+
+	watchable.Close()
+	delete(c.watchables, key)
+	return true
+}
+
+func (c *client) context() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	cancel := noopCancel
+	if c.opts.RequestTimeout() > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.opts.RequestTimeout())
+	}
+
+	return ctx, cancel
+}
+
+func heartbeatKey(sid services.ServiceID, instance string) string {
+	return fmt.Sprintf(keyFormat, servicePrefix(sid), instance)
+}
+
+func instanceFromKey(key, servicePrefix string) string {
+	return strings.TrimPrefix(
+		strings.TrimPrefix(key, servicePrefix),
+		keySeparator,
+	)
+}
+
+func instancesFromKeys(keys []string, servicePrefix string) []string {
+	r := make([]string, len(keys))
+	for i, key := range keys {
+		r[i] = instanceFromKey(key, servicePrefix)
+	}
+	return r
+}
+
+// heartbeats for a service "svc" in env "test" should be stored under
+// "_hb/test/svc". A service "svc" with no environment will be stored under
+// "_hb/svc".
+func servicePrefix(sid services.ServiceID) string {
+	env := sid.Environment()
+	if env == "" {
+		return fmt.Sprintf(keyFormat, heartbeatKeyPrefix, sid.Name())
+	}
+
+	return fmt.Sprintf(
+		keyFormat,
+		heartbeatKeyPrefix,
+		fmt.Sprintf(keyFormat, env, sid.Name()))
+}
+
+func newSessionCache() *sessionCache {
+	return &sessionCache{
+		sessions: make(map[string]cachedSession),
+	}
+}
+
+type cachedSession struct {
+	id  string
+	ttl time.Duration
+}
+
+type sessionCache struct {
+	sync.RWMutex
+
+	sessions map[string]cachedSession
+}
+
+func (c *sessionCache) get(key string, ttl time.Duration) (string, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	s, ok := c.sessions[key]
+	if !ok || s.ttl != ttl {
+		return "", false
+	}
+	return s.id, true
+}
+
+func (c *sessionCache) put(key string, ttl time.Duration, id string) {
+	c.Lock()
+	c.sessions[key] = cachedSession{id: id, ttl: ttl}
+	c.Unlock()
+}
+
+func (c *sessionCache) delete(key string) (string, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	s, ok := c.sessions[key]
+	delete(c.sessions, key)
+	return s.id, ok
+}
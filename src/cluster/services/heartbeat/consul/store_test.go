@@ -0,0 +1,336 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package consul
+
+import (
+	"testing"
+	"time"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/consul/consultest"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	sid := services.NewServiceID().SetName("service")
+	id := "instance"
+
+	require.Equal(t, "_hb/service", servicePrefix(sid))
+	require.Equal(t, "_hb/service/instance", heartbeatKey(sid, id))
+
+	sid = sid.SetEnvironment("test")
+	require.Equal(t, "_hb/test/service/instance", heartbeatKey(sid, id))
+	require.Equal(t, "_hb/test/service", servicePrefix(sid))
+	require.Equal(t, "instance", instanceFromKey(heartbeatKey(sid, id), servicePrefix(sid)))
+}
+
+func TestReuseSession(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1").SetEnvironment("e1")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	i1 := placement.NewInstance().SetID("i1")
+
+	c, err := NewStore(cc, opts)
+	require.NoError(t, err)
+	store := c.(*client)
+
+	err = store.Heartbeat(i1, time.Minute)
+	require.NoError(t, err)
+
+	sessionID, ok := store.cache.get(heartbeatKey(sid, "i1"), time.Minute)
+	require.True(t, ok)
+
+	err = store.Heartbeat(i1, time.Minute)
+	require.NoError(t, err)
+
+	reused, ok := store.cache.get(heartbeatKey(sid, "i1"), time.Minute)
+	require.True(t, ok)
+	require.Equal(t, sessionID, reused)
+
+	// a different ttl requires a new session which takes over the key
+	err = store.Heartbeat(i1, time.Hour)
+	require.NoError(t, err)
+
+	_, ok = store.cache.get(heartbeatKey(sid, "i1"), time.Minute)
+	require.False(t, ok)
+
+	ids, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, []string{"i1"}, ids)
+}
+
+func TestHeartbeat(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1").SetEnvironment("e1")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	i1 := placement.NewInstance().SetID("i1")
+	i2 := placement.NewInstance().SetID("i2")
+
+	c, err := NewStore(cc, opts)
+	require.NoError(t, err)
+	store := c.(*client)
+
+	ids, err := store.Get()
+	require.Equal(t, 0, len(ids))
+	require.NoError(t, err)
+
+	err = store.Heartbeat(i1, 200*time.Millisecond)
+	require.NoError(t, err)
+	err = store.Heartbeat(i2, time.Minute)
+	require.NoError(t, err)
+
+	ids, err = store.Get()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ids))
+	require.Contains(t, ids, "i1")
+	require.Contains(t, ids, "i2")
+
+	// ensure that both Get and GetInstances return the same instances
+	// with their respective serialization methods
+	instances, err := store.GetInstances()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(instances))
+	require.Contains(t, instances, i1)
+	require.Contains(t, instances, i2)
+
+	for {
+		ids, err = store.Get()
+		require.NoError(t, err)
+		instances, err2 := store.GetInstances()
+		require.NoError(t, err2)
+		if len(ids) == 1 && len(instances) == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, 1, len(ids))
+	require.NotContains(t, ids, "i1")
+	require.Contains(t, ids, "i2")
+
+	// the expired session is replaced on the next heartbeat
+	err = store.Heartbeat(i1, 200*time.Millisecond)
+	require.NoError(t, err)
+
+	ids, err = store.Get()
+	require.NoError(t, err)
+	require.Equal(t, []string{"i1", "i2"}, ids)
+
+	err = store.Delete(i2.ID())
+	require.NoError(t, err)
+
+	ids, err = store.Get()
+	require.NoError(t, err)
+	require.NotContains(t, ids, "i2")
+}
+
+func TestHeartbeatTakesOverPreviousSession(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1").SetEnvironment("e1")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	i1 := placement.NewInstance().SetID("i1").SetEndpoint("e1")
+
+	previous, err := NewStore(cc, opts)
+	require.NoError(t, err)
+	require.NoError(t, previous.Heartbeat(i1, time.Hour))
+
+	// a restarted instance heartbeats with a new store while the session of
+	// its previous heartbeats is still alive
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+	require.NoError(t, store.Heartbeat(i1.SetEndpoint("e2"), time.Hour))
+
+	instances, err := store.GetInstances()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(instances))
+	require.Equal(t, "e2", instances[0].Endpoint())
+}
+
+func TestDelete(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1").SetEnvironment("e1")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	i1 := placement.NewInstance().SetID("i1")
+	i2 := placement.NewInstance().SetID("i2")
+
+	c, err := NewStore(cc, opts)
+	require.NoError(t, err)
+	store := c.(*client)
+
+	err = store.Heartbeat(i1, time.Hour)
+	require.NoError(t, err)
+
+	err = store.Heartbeat(i2, time.Hour)
+	require.NoError(t, err)
+
+	ids, err := store.Get()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ids))
+	require.Contains(t, ids, "i1")
+	require.Contains(t, ids, "i2")
+
+	err = store.Delete(i1.ID())
+	require.NoError(t, err)
+
+	err = store.Delete(i1.ID())
+	require.Error(t, err)
+
+	ids, err = store.Get()
+	require.NoError(t, err)
+	require.Equal(t, []string{"i2"}, ids)
+
+	instances, err := store.GetInstances()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(instances))
+	require.Contains(t, instances, i2)
+
+	err = store.Heartbeat(i1, time.Hour)
+	require.NoError(t, err)
+
+	ids, err = store.Get()
+	require.NoError(t, err)
+	require.Equal(t, []string{"i1", "i2"}, ids)
+}
+
+func TestWatch(t *testing.T) {
+	sid := services.NewServiceID().SetName("s2").SetEnvironment("e2")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	i1 := placement.NewInstance().SetID("i1")
+	i2 := placement.NewInstance().SetID("i2")
+
+	w1, err := store.Watch()
+	require.NoError(t, err)
+	<-w1.C()
+	require.Empty(t, w1.Get())
+
+	err = store.Heartbeat(i1, 200*time.Millisecond)
+	require.NoError(t, err)
+
+	for range w1.C() {
+		if len(w1.Get().([]string)) == 1 {
+			break
+		}
+	}
+	require.Equal(t, []string{"i1"}, w1.Get())
+
+	err = store.Heartbeat(i2, 200*time.Millisecond)
+	require.NoError(t, err)
+
+	for range w1.C() {
+		if len(w1.Get().([]string)) == 2 {
+			break
+		}
+	}
+	require.Equal(t, []string{"i1", "i2"}, w1.Get())
+
+	for range w1.C() {
+		if len(w1.Get().([]string)) == 0 {
+			break
+		}
+	}
+
+	err = store.Heartbeat(i2, time.Second)
+	require.NoError(t, err)
+
+	for range w1.C() {
+		val := w1.Get().([]string)
+		if len(val) == 1 && val[0] == "i2" {
+			break
+		}
+	}
+
+	w1.Close()
+}
+
+func TestWatchClose(t *testing.T) {
+	sid := services.NewServiceID().SetName("s1").SetEnvironment("e1")
+	cc, opts, closeFn := testStore(t, sid)
+	defer closeFn()
+
+	store, err := NewStore(cc, opts)
+	require.NoError(t, err)
+
+	i1 := placement.NewInstance().SetID("i1")
+	i2 := placement.NewInstance().SetID("i2")
+
+	err = store.Heartbeat(i1, 100*time.Second)
+	require.NoError(t, err)
+
+	w1, err := store.Watch()
+	require.NoError(t, err)
+	<-w1.C()
+	require.Equal(t, []string{"i1"}, w1.Get())
+
+	c := store.(*client)
+	_, ok := c.watchables["_hb/e1/s1"]
+	require.True(t, ok)
+
+	// closing w1 will close the go routine for the watch updates
+	w1.Close()
+
+	// waits until the original watchable is cleaned up
+	for {
+		c.RLock()
+		_, ok = c.watchables["_hb/e1/s1"]
+		c.RUnlock()
+		if !ok {
+			break
+		}
+	}
+
+	// getting a new watch will create a new watchale and thread to watch for updates
+	w2, err := store.Watch()
+	require.NoError(t, err)
+	<-w2.C()
+	require.Equal(t, []string{"i1"}, w2.Get())
+
+	// verify that w1 will no longer be updated because the original watchable is closed
+	err = store.Heartbeat(i2, 100*time.Second)
+	require.NoError(t, err)
+	<-w2.C()
+	require.Equal(t, []string{"i1", "i2"}, w2.Get())
+	require.Equal(t, []string{"i1"}, w1.Get())
+
+	w1.Close()
+	w2.Close()
+}
+
+func testStore(t *testing.T, sid services.ServiceID) (consulapi.Client, Options, func()) {
+	srv := consultest.NewServer()
+	cc, err := consulapi.NewClient(consulapi.NewOptions().SetAddress(srv.Address()))
+	require.NoError(t, err)
+
+	opts := NewOptions().
+		SetServiceID(sid).
+		SetWatchCheckInterval(10 * time.Millisecond)
+	return cc, opts, srv.Close
+}
@@ -0,0 +1,491 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package consul provides leader elections backed by consul sessions. The
+// elections follow the states described in the services/leader package: a
+// campaign acquires the election key with a session whose TTL is refreshed
+// in the background, and leadership is lost once the session is invalidated.
+package consul
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consulapi "github.com/m3db/m3/src/cluster/consul"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/cluster/services/leader/election"
+)
+
+const (
+	// Appended to elections with an empty string for electionID to make it
+	// easier for user to debug consul keys.
+	defaultElectionID = "default"
+
+	// defaultTTL matches the default TTL of etcd backed elections.
+	defaultTTL = 60 * time.Second
+
+	// NB: consul holds back keys locked by an invalidated session for the lock
+	// delay so that a leader whose session expired has time to notice before
+	// another campaign acquires the key, this is the consul default. Resigning
+	// releases the key before destroying the session so that leadership is
+	// still handed off right away.
+	sessionLockDelay = "15s"
+)
+
+type client struct {
+	sync.RWMutex
+
+	consul           consulapi.Client
+	key              string
+	ttl              time.Duration
+	opts             services.ElectionOptions
+	session          *session
+	campaignCancelFn context.CancelFunc
+	observeCancelFn  context.CancelFunc
+	observeCtx       context.Context
+	resignCh         chan struct{}
+	campaigning      bool
+	closed           bool
+}
+
+// session is the consul session of a campaign.
+type session struct {
+	id       string
+	cancelFn context.CancelFunc
+	doneCh   chan struct{}
+	doneOnce sync.Once
+}
+
+// expire marks the session as no longer valid.
+func (s *session) expire() {
+	s.doneOnce.Do(func() { close(s.doneCh) })
+}
+
+// newClient returns an instance of an client bound to a single election.
+func newClient(cli consulapi.Client, opts leader.Options, electionID string) (*client, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(opts.ElectionOpts().TTLSecs()) * time.Second
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	// Allow multiple observe calls with the same parent context, to be cancelled
+	// when the client is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &client{
+		consul:          cli,
+		key:             electionKey(opts.ServiceID(), electionID),
+		ttl:             ttl,
+		opts:            opts.ElectionOpts(),
+		resignCh:        make(chan struct{}),
+		observeCtx:      ctx,
+		observeCancelFn: cancel,
+	}, nil
+}
+
+func (c *client) campaign(opts services.CampaignOptions) (<-chan campaign.Status, error) {
+	if c.isClosed() {
+		return nil, errClientClosed
+	}
+
+	if !c.startCampaign() {
+		return nil, leader.ErrCampaignInProgress
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Lock()
+	c.campaignCancelFn = cancel
+	c.Unlock()
+
+	// buffer 1 to not block initial follower update
+	sc := make(chan campaign.Status, 1)
+
+	sc <- campaign.NewStatus(campaign.Follower)
+
+	go func() {
+		defer func() {
+			close(sc)
+			cancel()
+			c.stopCampaign()
+		}()
+
+		// elect blocks until elected. Once we are elected, we get a channel
+		// that's closed if our session dies.
+		ch, err := c.elect(ctx, opts.LeaderValue())
+		if err != nil {
+			sc <- campaign.NewErrorStatus(err)
+			return
+		}
+
+		sc <- campaign.NewStatus(campaign.Leader)
+		select {
+		case <-ch:
+			sc <- campaign.NewErrorStatus(election.ErrSessionExpired)
+		case <-c.resignCh:
+			sc <- campaign.NewStatus(campaign.Follower)
+		}
+	}()
+
+	return sc, nil
+}
+
+// elect creates a session and blocks until it acquires the election key.
+func (c *client) elect(ctx context.Context, value string) (<-chan struct{}, error) {
+	s, err := c.newSession(ctx)
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		if s != nil {
+			c.endSession(s)
+		}
+		return nil, err
+	}
+
+	pair := &consulapi.KVPair{Key: c.key, Value: []byte(value), Session: s.id}
+	for {
+		acquired, err := c.consul.Acquire(ctx, pair)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			c.endSession(s)
+			return nil, err
+		}
+		if acquired {
+			return s.doneCh, nil
+		}
+
+		if err := c.waitForVacancy(ctx, s); err != nil {
+			c.endSession(s)
+			return nil, err
+		}
+	}
+}
+
+// waitForVacancy blocks until the election key is no longer held by a
+// session.
+func (c *client) waitForVacancy(ctx context.Context, s *session) error {
+	var index uint64
+	for {
+		pair, meta, err := c.consul.Get(ctx, c.key, &consulapi.QueryOptions{
+			WaitIndex: index,
+			WaitTime:  c.ttl,
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		if pair == nil || pair.Session == "" {
+			return nil
+		}
+
+		select {
+		case <-s.doneCh:
+			return election.ErrSessionExpired
+		default:
+		}
+
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+	}
+}
+
+func (c *client) newSession(ctx context.Context) (*session, error) {
+	id, err := c.consul.CreateSession(ctx, &consulapi.SessionEntry{
+		Name:      c.key,
+		Behavior:  consulapi.SessionBehaviorDelete,
+		TTL:       c.ttl.String(),
+		LockDelay: sessionLockDelay,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	s := &session{
+		id:       id,
+		cancelFn: cancel,
+		doneCh:   make(chan struct{}),
+	}
+
+	c.Lock()
+	c.session = s
+	c.Unlock()
+
+	go c.keepAlive(sessionCtx, s)
+	return s, nil
+}
+
+// keepAlive renews the session until the context is cancelled, the session
+// is expired if it can not be renewed within its TTL.
+func (c *client) keepAlive(ctx context.Context, s *session) {
+	ticker := time.NewTicker(c.ttl / 3)
+	defer ticker.Stop()
+
+	lastRenewed := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		renewCtx, cancel := context.WithTimeout(ctx, c.ttl)
+		err := c.consul.RenewSession(renewCtx, s.id)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			lastRenewed = time.Now()
+			continue
+		}
+		if err == consulapi.ErrSessionNotFound || time.Since(lastRenewed) >= c.ttl {
+			s.expire()
+			return
+		}
+	}
+}
+
+// endSession stops renewing and destroys the session, releasing the election
+// key first if it is held by the session so that the key is not held back
+// for the lock delay.
+func (c *client) endSession(s *session) error {
+	c.Lock()
+	if c.session == s {
+		c.session = nil
+	}
+	c.Unlock()
+
+	s.cancelFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.ResignTimeout())
+	defer cancel()
+	// The key is still released by destroying the session if releasing it
+	// fails, only then subject to the lock delay.
+	c.consul.Release(ctx, &consulapi.KVPair{Key: c.key, Session: s.id})
+	return c.consul.DestroySession(ctx, s.id)
+}
+
+func (c *client) resign() error {
+	if c.isClosed() {
+		return errClientClosed
+	}
+
+	// if there's an active blocking call to Campaign() stop it
+	c.Lock()
+	if c.campaignCancelFn != nil {
+		c.campaignCancelFn()
+		c.campaignCancelFn = nil
+	}
+	s := c.session
+	c.Unlock()
+
+	if s != nil {
+		if err := c.endSession(s); err != nil {
+			return err
+		}
+	}
+
+	// if successfully resigned and there was a campaign in Leader state cancel
+	// it
+	select {
+	case c.resignCh <- struct{}{}:
+	default:
+	}
+
+	c.stopCampaign()
+
+	return nil
+}
+
+func (c *client) leader() (string, error) {
+	if c.isClosed() {
+		return "", errClientClosed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.LeaderTimeout())
+	defer cancel()
+
+	pair, _, err := c.consul.Get(ctx, c.key, nil)
+	if err != nil {
+		return "", err
+	}
+	if pair == nil || pair.Session == "" {
+		return "", leader.ErrNoLeader
+	}
+	return string(pair.Value), nil
+}
+
+func (c *client) observe() (<-chan string, error) {
+	if c.isClosed() {
+		return nil, errClientClosed
+	}
+
+	c.RLock()
+	pCtx := c.observeCtx
+	c.RUnlock()
+
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+
+		var (
+			index   uint64
+			last    string
+			elected bool
+		)
+		for {
+			pair, meta, err := c.consul.Get(pCtx, c.key, &consulapi.QueryOptions{
+				WaitIndex: index,
+				WaitTime:  c.ttl,
+			})
+			if pCtx.Err() != nil {
+				return
+			}
+			if err != nil {
+				// back off before retrying a failed blocking query
+				select {
+				case <-time.After(c.ttl / 3):
+				case <-pCtx.Done():
+					return
+				}
+				continue
+			}
+
+			if meta.LastIndex < index {
+				index = 0
+			} else {
+				index = meta.LastIndex
+			}
+
+			if pair == nil || pair.Session == "" {
+				elected = false
+				continue
+			}
+
+			value := string(pair.Value)
+			if elected && value == last {
+				continue
+			}
+			elected, last = true, value
+
+			select {
+			case ch <- value:
+			case <-pCtx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func (c *client) startCampaign() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.campaigning {
+		return false
+	}
+
+	c.campaigning = true
+	return true
+}
+
+func (c *client) stopCampaign() {
+	c.Lock()
+	c.campaigning = false
+	c.Unlock()
+}
+
+// Close closes the election service client entirely. No more campaigns can be
+// started and any outstanding campaigns are closed.
+func (c *client) close() error {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return nil
+	}
+	c.observeCancelFn()
+	if c.campaignCancelFn != nil {
+		c.campaignCancelFn()
+		c.campaignCancelFn = nil
+	}
+	s := c.session
+	c.closed = true
+	c.Unlock()
+
+	if s == nil {
+		return nil
+	}
+
+	// NB: expire the session so a campaign in Leader state observes the loss
+	// of its session as it would for etcd backed elections.
+	defer s.expire()
+	return c.endSession(s)
+}
+
+func (c *client) isClosed() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.closed
+}
+
+// elections for a service "svc" in env "test" should be stored under
+// "_ld/test/svc". A service "svc" with no environment will be stored under
+// "_ld/svc".
+func servicePrefix(sid services.ServiceID) string {
+	env := sid.Environment()
+	if env == "" {
+		return fmt.Sprintf(keyFormat, leaderKeyPrefix, sid.Name())
+	}
+
+	return fmt.Sprintf(
+		keyFormat,
+		leaderKeyPrefix,
+		fmt.Sprintf(keyFormat, env, sid.Name()))
+}
+
+func electionKey(sid services.ServiceID, electionID string) string {
+	eid := electionID
+	if eid == "" {
+		eid = defaultElectionID
+	}
+
+	return fmt.Sprintf(
+		keyFormat,
+		servicePrefix(sid),
+		eid)
+}
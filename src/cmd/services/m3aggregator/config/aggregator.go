@@ -49,6 +49,7 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/retry"
+	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3/src/x/sync"
 )
 
@@ -80,6 +81,14 @@ type AggregatorConfiguration struct {
 	// Gauge metric prefix.
 	GaugePrefix *string `yaml:"gaugePrefix"`
 
+	// Histogram metric prefix.
+	HistogramPrefix *string `yaml:"histogramPrefix"`
+
+	// Name of the tag carrying the upper bound of histogram buckets. If set,
+	// histogram ids are expected to be serialized tags and the ids of histogram
+	// buckets are generated by adding this tag to them.
+	HistogramBucketTagName *string `yaml:"histogramBucketTagName"`
+
 	// Stream configuration for computing quantiles.
 	Stream streamConfiguration `yaml:"stream"`
 
@@ -152,6 +161,9 @@ type AggregatorConfiguration struct {
 	// Pool of gauge elements.
 	GaugeElemPool pool.ObjectPoolConfiguration `yaml:"gaugeElemPool"`
 
+	// Pool of histogram elements.
+	HistogramElemPool pool.ObjectPoolConfiguration `yaml:"histogramElemPool"`
+
 	// Pool of entries.
 	EntryPool pool.ObjectPoolConfiguration `yaml:"entryPool"`
 }
@@ -251,6 +263,20 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	opts = setMetricPrefix(opts, c.CounterPrefix, opts.SetCounterPrefix)
 	opts = setMetricPrefix(opts, c.TimerPrefix, opts.SetTimerPrefix)
 	opts = setMetricPrefix(opts, c.GaugePrefix, opts.SetGaugePrefix)
+	opts = setMetricPrefix(opts, c.HistogramPrefix, opts.SetHistogramPrefix)
+
+	// Set the histogram bucket id function.
+	if c.HistogramBucketTagName != nil {
+		tagEncoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(), nil)
+		tagEncoderPool.Init()
+		tagDecoderPool := serialize.NewTagDecoderPool(serialize.NewTagDecoderOptions(), nil)
+		tagDecoderPool.Init()
+		metricTagsIteratorPool := serialize.NewMetricTagsIteratorPool(tagDecoderPool, nil)
+		metricTagsIteratorPool.Init()
+		bucketIDFn := aggregator.NewTagsHistogramBucketIDFn(
+			[]byte(*c.HistogramBucketTagName), tagEncoderPool, metricTagsIteratorPool)
+		opts = opts.SetHistogramBucketIDFn(bucketIDFn)
+	}
 
 	// Set stream options.
 	scope := instrumentOpts.MetricsScope()
@@ -425,6 +451,15 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		return aggregator.MustNewGaugeElem(nil, policy.EmptyStoragePolicy, aggregation.DefaultTypes, applied.DefaultPipeline, 0, aggregator.NoPrefixNoSuffix, opts)
 	})
 
+	// Set histogram elem pool.
+	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("histogram-elem-pool"))
+	histogramElemPoolOpts := c.HistogramElemPool.NewObjectPoolOptions(iOpts)
+	histogramElemPool := aggregator.NewHistogramElemPool(histogramElemPoolOpts)
+	opts = opts.SetHistogramElemPool(histogramElemPool)
+	histogramElemPool.Init(func() *aggregator.HistogramElem {
+		return aggregator.MustNewHistogramElem(nil, policy.EmptyStoragePolicy, aggregation.DefaultTypes, applied.DefaultPipeline, 0, aggregator.NoPrefixNoSuffix, opts)
+	})
+
 	// Set entry pool.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("entry-pool"))
 	entryPoolOpts := c.EntryPool.NewObjectPoolOptions(iOpts)
@@ -206,6 +206,9 @@ type Configuration struct {
 	// Pool of gauge elements.
 	GaugeElemPool pool.ObjectPoolConfiguration `yaml:"gaugeElemPool"`
 
+	// Pool of histogram elements.
+	HistogramElemPool pool.ObjectPoolConfiguration `yaml:"histogramElemPool"`
+
 	// BufferPastLimits specifies the buffer past limits.
 	BufferPastLimits []BufferPastLimitConfiguration `yaml:"bufferPastLimits"`
 
@@ -727,6 +730,9 @@ func (cfg Configuration) newAggregator(o DownsamplerOptions) (agg, error) {
 		SetCounterPrefix(nil).
 		SetGaugePrefix(nil).
 		SetTimerPrefix(nil).
+		SetHistogramPrefix(nil).
+		SetHistogramBucketIDFn(aggregator.NewTagsHistogramBucketIDFn(
+			o.TagOptions.BucketName(), pools.tagEncoderPool, pools.metricTagsIteratorPool)).
 		SetPlacementManager(placementManager).
 		SetFlushTimesManager(flushTimesManager).
 		SetElectionManager(electionManager).
@@ -802,6 +808,24 @@ func (cfg Configuration) newAggregator(o DownsamplerOptions) (agg, error) {
 		)
 	})
 
+	// Set histogram elem pool.
+	histogramElemPoolOpts := cfg.HistogramElemPool.NewObjectPoolOptions(
+		instrumentOpts.SetMetricsScope(scope.SubScope("histogram-elem-pool")),
+	)
+	histogramElemPool := aggregator.NewHistogramElemPool(histogramElemPoolOpts)
+	aggregatorOpts = aggregatorOpts.SetHistogramElemPool(histogramElemPool)
+	histogramElemPool.Init(func() *aggregator.HistogramElem {
+		return aggregator.MustNewHistogramElem(
+			nil,
+			policy.EmptyStoragePolicy,
+			aggregation.DefaultTypes,
+			applied.DefaultPipeline,
+			0,
+			aggregator.WithPrefixWithSuffix,
+			aggregatorOpts,
+		)
+	})
+
 	adminAggClient := newAggregatorLocalAdminClient()
 	aggregatorOpts = aggregatorOpts.SetAdminClient(adminAggClient)
 
@@ -1053,6 +1077,14 @@ func (c *aggregatorLocalAdminClient) WriteUntimedGauge(
 	return c.agg.AddUntimed(gauge.ToUnion(), metadatas)
 }
 
+// WriteUntimedHistogram writes untimed histogram metrics.
+func (c *aggregatorLocalAdminClient) WriteUntimedHistogram(
+	histogram unaggregated.Histogram,
+	metadatas metadata.StagedMetadatas,
+) error {
+	return c.agg.AddUntimed(histogram.ToUnion(), metadatas)
+}
+
 // WriteTimed writes timed metrics.
 func (c *aggregatorLocalAdminClient) WriteTimed(
 	metric aggregated.Metric,
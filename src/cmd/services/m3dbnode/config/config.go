@@ -32,6 +32,7 @@ import (
 	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/x/config/hostid"
 	"github.com/m3db/m3/src/x/instrument"
@@ -136,6 +137,9 @@ type DBConfiguration struct {
 	// The replication policy for replicating data between clusters.
 	Replication *ReplicationPolicy `yaml:"replication"`
 
+	// The backup policy for backing up filesets to an object store.
+	Backup *BackupPolicy `yaml:"backup"`
+
 	// The pooling policy.
 	PoolingPolicy PoolingPolicy `yaml:"pooling"`
 
@@ -286,6 +290,12 @@ type CommitLogPolicy struct {
 	// enough for almost all workloads assuming a reasonable batch size is used.
 	QueueChannel *CommitLogQueuePolicy `yaml:"queueChannel"`
 
+	// The compression of the chunks written to new commit log files. Commit
+	// logs are always read with the compression recorded in their header, but
+	// nodes running a version without chunk compression cannot read compressed
+	// commit logs, so only enable it once every node has been upgraded.
+	ChunkCompression *compression.Type `yaml:"chunkCompression"`
+
 	// Deprecated. Left in struct to keep old YAMLs parseable.
 	// TODO(V1): remove
 	DeprecatedBlockSize *time.Duration `yaml:"blockSize"`
@@ -329,6 +339,20 @@ type RepairPolicy struct {
 	DebugShadowComparisonsPercentage float64 `yaml:"debugShadowComparisonsPercentage"`
 }
 
+// BackupPolicy is the backup policy.
+type BackupPolicy struct {
+	// Enabled or disabled.
+	Enabled bool `yaml:"enabled"`
+
+	// The directory filesets are backed up to, typically a mount of a remote
+	// object store.
+	Directory string `yaml:"directory" validate:"nonzero"`
+
+	// The prefix of the keys of backed up files, defaults to the host ID
+	// since the filesets of each replica differ.
+	KeyPrefix string `yaml:"keyPrefix"`
+}
+
 // ReplicationPolicy is the replication policy.
 type ReplicationPolicy struct {
 	Clusters []ReplicatedCluster `yaml:"clusters"`
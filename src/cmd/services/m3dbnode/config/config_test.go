@@ -448,6 +448,7 @@ func TestConfiguration(t *testing.T) {
       calculationType: fixed
       size: 2097152
     queueChannel: null
+    chunkCompression: null
     blockSize: null
   repair:
     enabled: false
@@ -456,6 +457,7 @@ func TestConfiguration(t *testing.T) {
     debugShadowComparisonsEnabled: false
     debugShadowComparisonsPercentage: 0
   replication: null
+  backup: null
   pooling:
     blockAllocSize: 16
     thriftBytesPoolAllocSize: 2048
@@ -26,15 +26,19 @@ import (
 	"time"
 
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	"github.com/m3db/m3/src/cluster/placement/operator"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	ingestm3msg "github.com/m3db/m3/src/cmd/services/m3coordinator/ingest/m3msg"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/tenant"
 	xconfig "github.com/m3db/m3/src/x/config"
 	"github.com/m3db/m3/src/x/config/listenaddress"
 	"github.com/m3db/m3/src/x/cost"
@@ -136,6 +140,16 @@ type Configuration struct {
 	// Experimental is the configuration for the experimental API group.
 	Experimental ExperimentalAPIConfiguration `yaml:"experimental"`
 
+	// Rules is the configuration for evaluating recording and alerting rules.
+	Rules *rules.Configuration `yaml:"rules"`
+
+	// ResultsCache is the configuration for caching range query results.
+	ResultsCache *cache.Configuration `yaml:"resultsCache"`
+
+	// QuerySharding is the configuration for splitting long range queries
+	// into time shards that are evaluated concurrently.
+	QuerySharding *QueryShardingConfiguration `yaml:"querySharding"`
+
 	// Cache configurations.
 	//
 	// Deprecated: cache configurations are no longer supported. Remove from file
@@ -183,6 +197,17 @@ type DeprecatedQueryConversionCacheConfiguration struct {
 	Size *int `yaml:"size"`
 }
 
+// QueryShardingConfiguration is the configuration for splitting long range
+// queries into time shards that are evaluated concurrently.
+type QueryShardingConfiguration struct {
+	// Interval is the maximum time range of each shard.
+	Interval time.Duration `yaml:"interval" validate:"nonzero"`
+
+	// Concurrency is the number of shards of a query that are evaluated
+	// concurrently.
+	Concurrency int `yaml:"concurrency"`
+}
+
 // ResultOptions are the result options for query.
 type ResultOptions struct {
 	// KeepNans keeps NaNs before returning query results.
@@ -202,6 +227,10 @@ type LimitsConfiguration struct {
 
 	// PerQuery configures limits which apply to each query individually.
 	PerQuery PerQueryLimitsConfiguration `yaml:"perQuery"`
+
+	// PerTenant configures limits which apply to each tenant individually,
+	// which can be changed at runtime in KV.
+	PerTenant *tenant.Configuration `yaml:"perTenant"`
 }
 
 // MaxComputedDatapoints is a getter providing backwards compatibility between
@@ -422,6 +451,10 @@ type LocalConfiguration struct {
 type ClusterManagementConfiguration struct {
 	// Etcd is the client configuration for etcd.
 	Etcd etcdclient.Configuration `yaml:"etcd"`
+
+	// PlacementOperator is the configuration for running placement
+	// operations in batches.
+	PlacementOperator operator.Configuration `yaml:"placementOperator"`
 }
 
 // RemoteConfigurations is a set of remote host configurations.
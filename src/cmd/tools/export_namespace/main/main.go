@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// export_namespace is a tool for exporting the data of a namespace from its
+// filesets as an Arrow IPC stream.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/export"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/models"
+	xpromql "github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/prometheus/prometheus/promql"
+	"go.uber.org/zap"
+)
+
+var (
+	optPathPrefix        = flag.String("path-prefix", "/var/lib/m3db", "Path prefix of the filesets")
+	optTieredPathPrefix  = flag.String("tiered-path-prefix", "", "Path prefix filesets are tiered to, if tiering is enabled for the namespace")
+	optNamespace         = flag.String("namespace", "", "Namespace to export")
+	optNamespaceRegistry = flag.String("namespace-registry", "", "File with the namespace registry JSON returned by the coordinator namespace API, defaults to default namespace options")
+	optStart             = flag.Int64("start", 0, "Start of the time range to export [in nsec]")
+	optEnd               = flag.Int64("end", 0, "End of the time range to export, exclusive [in nsec]")
+	optQuery             = flag.String("query", "", "Prometheus series selector the exported series must match, such as {__name__=\"up\"}")
+	optMetricName        = flag.String("metric-name", "__name__", "Tag name the metric name of the query is matched against")
+	optOutput            = flag.String("output", "", "File to write the Arrow IPC stream to")
+	optBatchSize         = flag.Int("batch-size", export.DefaultArrowBatchSize, "Number of rows of each record batch")
+)
+
+func main() {
+	flag.Parse()
+	if *optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optOutput == "" ||
+		*optStart < 0 ||
+		*optEnd <= *optStart {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	nsMetadata, err := newNamespaceMetadata(ident.StringID(*optNamespace))
+	if err != nil {
+		logger.Fatalf("unable to read namespace: %v", err)
+	}
+
+	query := export.Query{
+		Namespace: nsMetadata,
+		Start:     xtime.FromNanoseconds(*optStart),
+		End:       xtime.FromNanoseconds(*optEnd),
+	}
+	if *optQuery != "" {
+		indexQuery, err := parseQuery(*optQuery)
+		if err != nil {
+			logger.Fatalf("unable to parse query: %v", err)
+		}
+		query.Query = &indexQuery
+	}
+
+	iOpts := instrument.NewOptions().SetLogger(rawLogger)
+	opts := export.NewOptions().
+		SetFilesystemOptions(fs.NewOptions().SetFilePathPrefix(*optPathPrefix)).
+		SetTieredFilePathPrefix(*optTieredPathPrefix).
+		SetInstrumentOptions(iOpts)
+	exporter, err := export.NewExporter(opts)
+	if err != nil {
+		logger.Fatalf("unable to create exporter: %v", err)
+	}
+
+	file, err := os.Create(*optOutput)
+	if err != nil {
+		logger.Fatalf("unable to create output: %v", err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	writer := export.NewArrowWriter(buffered, *optBatchSize)
+	result, err := exporter.Export(query, writer)
+	if err != nil {
+		logger.Fatalf("unable to export: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		logger.Fatalf("unable to close writer: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		logger.Fatalf("unable to flush output: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		logger.Fatalf("unable to close output: %v", err)
+	}
+
+	logger.Infof("exported %d series (%d datapoints) from %d filesets",
+		result.Series, result.Datapoints, result.FileSets)
+}
+
+func newNamespaceMetadata(nsID ident.ID) (namespace.Metadata, error) {
+	if *optNamespaceRegistry == "" {
+		return namespace.NewMetadata(nsID, namespace.NewOptions())
+	}
+
+	file, err := os.Open(*optNamespaceRegistry)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var resp admin.NamespaceGetResponse
+	if err := jsonpb.Unmarshal(file, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Registry == nil {
+		return nil, fmt.Errorf("no namespace registry in %s", *optNamespaceRegistry)
+	}
+
+	nsMap, err := namespace.FromProto(*resp.Registry)
+	if err != nil {
+		return nil, err
+	}
+	return nsMap.Get(nsID)
+}
+
+func parseQuery(selector string) (index.Query, error) {
+	promMatchers, err := promql.ParseMetricSelector(selector)
+	if err != nil {
+		return index.Query{}, err
+	}
+
+	tagOpts := models.NewTagOptions().SetMetricName([]byte(*optMetricName))
+	matchers, err := xpromql.LabelMatchersToModelMatcher(promMatchers, tagOpts)
+	if err != nil {
+		return index.Query{}, err
+	}
+
+	return storage.FetchQueryToM3Query(&storage.FetchQuery{
+		TagMatchers: matchers,
+	}, nil)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type fileCheckpoint struct {
+	file     *os.File
+	imported map[xtime.UnixNano]struct{}
+}
+
+// NewFileCheckpoint returns a checkpoint that records the start of every
+// imported block as a line in the file at the given path, creating the file
+// if it does not exist.
+func NewFileCheckpoint(path string) (Checkpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := readCheckpoint(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to read checkpoint %s: %v", path, err)
+	}
+
+	return &fileCheckpoint{
+		file:     file,
+		imported: imported,
+	}, nil
+}
+
+func readCheckpoint(file *os.File) (map[xtime.UnixNano]struct{}, error) {
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// NB: only complete lines are recorded blocks, a trailing partial line
+	// is left by an interrupted write and is truncated so that it is not
+	// joined with the next line written.
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	if complete < len(data) {
+		if err := file.Truncate(int64(complete)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := file.Seek(int64(complete), io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	imported := make(map[xtime.UnixNano]struct{})
+	for _, line := range bytes.Split(data[:complete], []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		nanos, err := strconv.ParseInt(string(line), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block start %q: %v", line, err)
+		}
+		imported[xtime.UnixNano(nanos)] = struct{}{}
+	}
+	return imported, nil
+}
+
+func (c *fileCheckpoint) Imported(blockStart time.Time) bool {
+	_, ok := c.imported[xtime.ToUnixNano(blockStart)]
+	return ok
+}
+
+func (c *fileCheckpoint) MarkImported(blockStart time.Time) error {
+	nanos := xtime.ToUnixNano(blockStart)
+	if _, ok := c.imported[nanos]; ok {
+		return nil
+	}
+
+	line := strconv.AppendInt(nil, int64(nanos), 10)
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := c.file.Sync(); err != nil {
+		return err
+	}
+
+	c.imported[nanos] = struct{}{}
+	return nil
+}
+
+func (c *fileCheckpoint) Close() error {
+	return c.file.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCheckpointTruncatesPartialLine(t *testing.T) {
+	checkpoint, path, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+
+	var (
+		first  = time.Unix(0, 0).Add(2 * time.Hour)
+		second = first.Add(2 * time.Hour)
+	)
+	require.NoError(t, checkpoint.MarkImported(first))
+	require.NoError(t, checkpoint.Close())
+
+	// Simulate a write interrupted halfway through a line.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0666)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("1234"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	checkpoint, err = NewFileCheckpoint(path)
+	require.NoError(t, err)
+	require.True(t, checkpoint.Imported(first))
+	require.False(t, checkpoint.Imported(second))
+
+	require.NoError(t, checkpoint.MarkImported(second))
+	require.NoError(t, checkpoint.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "7200000000000\n14400000000000\n", string(data))
+}
+
+func TestFileCheckpointInvalidLine(t *testing.T) {
+	_, path, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not-a-time\n"), 0666))
+
+	_, err := NewFileCheckpoint(path)
+	require.Error(t, err)
+}
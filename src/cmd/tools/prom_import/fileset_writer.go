@@ -0,0 +1,430 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	idxconvert "github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+type fileSetWriter struct {
+	filePathPrefix string
+	nsMetadata     namespace.Metadata
+	nsCtx          namespace.Context
+	shardSet       sharding.ShardSet
+	encodingOpts   encoding.Options
+	persistManager persist.Manager
+	merger         fs.Merger
+	indexBuilder   segment.DocumentsBuilder
+	indexShards    map[uint32]struct{}
+	logger         *zap.Logger
+}
+
+// NewFileSetWriter returns a writer that writes the series of each block into
+// filesets of the namespace for the shards of the shard set the series belong
+// to, series of other shards are dropped. Each shard of a block is read and
+// written separately. A shard without a fileset for the block gets a new
+// fileset, otherwise the series are merged with the latest fileset volume into
+// the next volume, existing filesets are never overwritten. Once every shard
+// of a block has been written an index fileset volume with the written series
+// is added to the index block of the block.
+func NewFileSetWriter(
+	fsOpts fs.Options,
+	nsMetadata namespace.Metadata,
+	shardSet sharding.ShardSet,
+	instrumentOpts instrument.Options,
+) (Writer, error) {
+	if !nsMetadata.Options().IndexOptions().Enabled() {
+		return nil, fmt.Errorf("namespace %s does not have indexing enabled",
+			nsMetadata.ID().String())
+	}
+
+	persistManager, err := fs.NewPersistManager(fsOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := fs.NewReader(nil, fsOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	indexBuilder, err := builder.NewBuilderFromDocuments(builder.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		encodingOpts = encoding.NewOptions()
+		poolOpts     = pool.NewObjectPoolOptions().SetSize(1)
+	)
+	segmentReaderPool := xio.NewSegmentReaderPool(poolOpts)
+	segmentReaderPool.Init()
+	multiIterPool := encoding.NewMultiReaderIteratorPool(poolOpts)
+	multiIterPool.Init(func(r io.Reader, _ namespace.SchemaDescr) encoding.ReaderIterator {
+		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+	})
+	bytesPool := pool.NewCheckedBytesPool(nil, poolOpts, func(s []pool.Bucket) pool.BytesPool {
+		return pool.NewBytesPool(s, poolOpts)
+	})
+	bytesPool.Init()
+	encoderPool := encoding.NewEncoderPool(poolOpts)
+	encoderPool.Init(func() encoding.Encoder {
+		return m3tsz.NewEncoder(time.Time{}, nil, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+	})
+	contextPool := context.NewPool(context.NewOptions().
+		SetContextPoolOptions(poolOpts).
+		SetFinalizerPoolOptions(poolOpts))
+	merger := fs.NewMerger(reader, 0, segmentReaderPool, multiIterPool,
+		ident.NewPool(bytesPool, ident.PoolOptions{}), encoderPool, contextPool,
+		nsMetadata.Options(), fsOpts)
+
+	return &fileSetWriter{
+		filePathPrefix: fsOpts.FilePathPrefix(),
+		nsMetadata:     nsMetadata,
+		nsCtx:          namespace.NewContextFrom(nsMetadata),
+		shardSet:       shardSet,
+		encodingOpts:   encodingOpts,
+		persistManager: persistManager,
+		merger:         merger,
+		indexBuilder:   indexBuilder,
+		indexShards:    make(map[uint32]struct{}),
+		logger:         instrumentOpts.Logger(),
+	}, nil
+}
+
+func (w *fileSetWriter) Partitions() []SeriesFilter {
+	shards := w.shardSet.AllIDs()
+	partitions := make([]SeriesFilter, 0, len(shards))
+	for _, shard := range shards {
+		shard := shard
+		partitions = append(partitions, func(id ident.ID) bool {
+			return w.shardSet.Lookup(id) == shard
+		})
+	}
+	return partitions
+}
+
+func (w *fileSetWriter) Write(blockStart time.Time, series []Series) error {
+	byShard := make(map[uint32][]Series)
+	for _, s := range series {
+		shard := w.shardSet.Lookup(s.ID)
+		if _, err := w.shardSet.LookupStateByID(shard); err != nil {
+			// Not a shard of the shard set.
+			continue
+		}
+		byShard[shard] = append(byShard[shard], s)
+	}
+
+	for _, shard := range w.shardSet.AllIDs() {
+		shardSeries, ok := byShard[shard]
+		if !ok {
+			continue
+		}
+
+		if err := w.writeShard(shard, blockStart, shardSeries); err != nil {
+			return fmt.Errorf("unable to write fileset for shard %d: %v", shard, err)
+		}
+		if err := w.indexSeries(shardSeries); err != nil {
+			return fmt.Errorf("unable to index series of shard %d: %v", shard, err)
+		}
+		w.indexShards[shard] = struct{}{}
+	}
+	return nil
+}
+
+func (w *fileSetWriter) writeShard(
+	shard uint32,
+	blockStart time.Time,
+	series []Series,
+) (err error) {
+	files, err := fs.DataFiles(w.filePathPrefix, w.nsMetadata.ID(), shard)
+	if err != nil {
+		return err
+	}
+
+	flushPreparer, err := w.persistManager.StartFlushPersist()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if doneErr := flushPreparer.DoneFlush(); err == nil {
+			err = doneErr
+		}
+	}()
+
+	latest, ok := files.LatestVolumeForBlock(blockStart)
+	if !ok {
+		return w.persistShard(shard, blockStart, series, flushPreparer)
+	}
+
+	var (
+		fileID = fs.FileSetFileIdentifier{
+			Namespace:   w.nsMetadata.ID(),
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: latest.ID.VolumeIndex,
+		}
+		nextVolume = latest.ID.VolumeIndex + 1
+		mergeWith  = newSeriesMergeWith(series, w.nsMetadata.Options().
+				RetentionOptions().BlockSize(), w.encode)
+	)
+	w.logger.Info("merging shard with existing fileset",
+		zap.Uint32("shard", shard),
+		zap.Time("blockStart", blockStart),
+		zap.Int("volume", nextVolume))
+	return w.merger.Merge(fileID, mergeWith, nextVolume, flushPreparer, w.nsCtx)
+}
+
+func (w *fileSetWriter) persistShard(
+	shard uint32,
+	blockStart time.Time,
+	series []Series,
+	flushPreparer persist.FlushPreparer,
+) error {
+	prepared, err := flushPreparer.PrepareData(persist.DataPrepareOptions{
+		NamespaceMetadata: w.nsMetadata,
+		BlockStart:        blockStart,
+		Shard:             shard,
+		FileSetType:       persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	// NB: the prepared fileset is only closed on success since closing it
+	// writes the checkpoint file marking the fileset complete.
+	for _, s := range series {
+		encoded, err := w.encode(blockStart, s.Datapoints)
+		if err != nil {
+			return err
+		}
+
+		checksum := digest.SegmentChecksum(encoded)
+		if err := prepared.Persist(s.ID, tagsToIdentTags(s.Tags), encoded, checksum); err != nil {
+			return err
+		}
+	}
+	return prepared.Close()
+}
+
+func (w *fileSetWriter) indexSeries(series []Series) error {
+	for _, s := range series {
+		d, err := idxconvert.FromMetric(s.ID, tagsToIdentTags(s.Tags))
+		if err != nil {
+			return err
+		}
+		// NB: a series written more than once for a block is indexed once.
+		if _, err := w.indexBuilder.Insert(d); err != nil && err != index.ErrDuplicateID {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *fileSetWriter) encode(
+	blockStart time.Time,
+	datapoints []ts.Datapoint,
+) (ts.Segment, error) {
+	encoder := m3tsz.NewEncoder(blockStart, nil,
+		m3tsz.DefaultIntOptimizationEnabled, w.encodingOpts)
+	for _, dp := range datapoints {
+		if err := encoder.Encode(dp, xtime.Millisecond, nil); err != nil {
+			return ts.Segment{}, err
+		}
+	}
+	return encoder.Discard(), nil
+}
+
+func (w *fileSetWriter) Flush(blockStart time.Time) (err error) {
+	if len(w.indexShards) == 0 {
+		return nil
+	}
+
+	indexFlush, err := w.persistManager.StartIndexPersist()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if doneErr := indexFlush.DoneIndex(); err == nil {
+			err = doneErr
+		}
+	}()
+
+	indexBlockSize := w.nsMetadata.Options().IndexOptions().BlockSize()
+	prepared, err := indexFlush.PrepareIndex(persist.IndexPrepareOptions{
+		NamespaceMetadata: w.nsMetadata,
+		BlockStart:        blockStart.Truncate(indexBlockSize),
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            w.indexShards,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := prepared.Persist(w.indexBuilder); err != nil {
+		return err
+	}
+
+	segments, err := prepared.Close()
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if err := seg.Close(); err != nil {
+			return err
+		}
+	}
+
+	w.indexBuilder.Reset(0)
+	w.indexShards = make(map[uint32]struct{})
+	return nil
+}
+
+func (w *fileSetWriter) Close() error {
+	return nil
+}
+
+// seriesMergeWith merges the imported series of a shard with the series of
+// an existing fileset.
+type seriesMergeWith struct {
+	series    []Series
+	indexByID map[string]int
+	merged    []bool
+	blockSize time.Duration
+	encode    func(blockStart time.Time, datapoints []ts.Datapoint) (ts.Segment, error)
+}
+
+func newSeriesMergeWith(
+	series []Series,
+	blockSize time.Duration,
+	encode func(blockStart time.Time, datapoints []ts.Datapoint) (ts.Segment, error),
+) *seriesMergeWith {
+	indexByID := make(map[string]int, len(series))
+	for i, s := range series {
+		indexByID[s.ID.String()] = i
+	}
+	return &seriesMergeWith{
+		series:    series,
+		indexByID: indexByID,
+		merged:    make([]bool, len(series)),
+		blockSize: blockSize,
+		encode:    encode,
+	}
+}
+
+func (m *seriesMergeWith) Read(
+	_ context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	_ namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	i, ok := m.indexByID[seriesID.String()]
+	if !ok {
+		return nil, false, nil
+	}
+
+	m.merged[i] = true
+	readers, err := m.blockReaders(blockStart.ToTime(), m.series[i])
+	if err != nil {
+		return nil, false, err
+	}
+	return readers, true, nil
+}
+
+func (m *seriesMergeWith) ForEachRemaining(
+	_ context.Context,
+	blockStart xtime.UnixNano,
+	fn fs.ForEachRemainingFn,
+	_ namespace.Context,
+) error {
+	for i, s := range m.series {
+		if m.merged[i] {
+			continue
+		}
+
+		readers, err := m.blockReaders(blockStart.ToTime(), s)
+		if err != nil {
+			return err
+		}
+		if err := fn(s.ID, tagsToIdentTags(s.Tags), readers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *seriesMergeWith) DeletedRanges(
+	_ ident.ID,
+	_ ident.Tags,
+	_ xtime.UnixNano,
+) xtime.Ranges {
+	return xtime.NewRanges()
+}
+
+func (m *seriesMergeWith) blockReaders(
+	blockStart time.Time,
+	series Series,
+) ([]xio.BlockReader, error) {
+	encoded, err := m.encode(blockStart, series.Datapoints)
+	if err != nil {
+		return nil, err
+	}
+	return []xio.BlockReader{{
+		SegmentReader: xio.NewSegmentReader(encoded),
+		Start:         blockStart,
+		BlockSize:     m.blockSize,
+	}}, nil
+}
+
+func tagsToIdentTags(tags models.Tags) ident.Tags {
+	identTags := make([]ident.Tag, 0, tags.Len())
+	for _, t := range tags.Tags {
+		identTags = append(identTags, ident.Tag{
+			Name:  ident.BytesID(t.Name),
+			Value: ident.BytesID(t.Value),
+		})
+	}
+	return ident.NewTags(identTags...)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNumShards = 4
+
+func newTestFileSetWriter(t *testing.T, dir string, blockSize time.Duration) (Writer, sharding.ShardSet) {
+	// NB: only half of the shards are written.
+	shards := []shard.Shard{
+		shard.NewShard(0).SetState(shard.Available),
+		shard.NewShard(1).SetState(shard.Available),
+	}
+	shardSet, err := sharding.NewShardSet(shards, sharding.DefaultHashFn(testNumShards))
+	require.NoError(t, err)
+
+	nsOpts := namespace.NewOptions().
+		SetRetentionOptions(namespace.NewRetentionOptions().SetBlockSize(blockSize)).
+		SetIndexOptions(namespace.NewIndexOptions().
+			SetEnabled(true).
+			SetBlockSize(2 * blockSize))
+	nsMetadata, err := namespace.NewMetadata(ident.StringID("testns"), nsOpts)
+	require.NoError(t, err)
+
+	fsOpts := fs.NewOptions().SetFilePathPrefix(dir)
+	writer, err := NewFileSetWriter(fsOpts, nsMetadata, shardSet, instrument.NewOptions())
+	require.NoError(t, err)
+	return writer, shardSet
+}
+
+func writeTestBlock(t *testing.T, writer Writer, blockStart time.Time, series []Series) {
+	for _, filter := range writer.Partitions() {
+		var partition []Series
+		for _, s := range series {
+			if filter(s.ID) {
+				partition = append(partition, s)
+			}
+		}
+		require.NoError(t, writer.Write(blockStart, partition))
+	}
+	require.NoError(t, writer.Flush(blockStart))
+}
+
+func newTestSeries(blockStart time.Time, num int, value float64) []Series {
+	series := make([]Series, 0, num)
+	for i := 0; i < num; i++ {
+		tags := models.NewTags(2, models.NewTagOptions()).
+			SetName([]byte("up")).
+			AddTag(models.Tag{Name: []byte("instance"), Value: []byte(fmt.Sprintf("host%d", i))})
+		series = append(series, Series{
+			ID:   ident.BytesID(tags.ID()),
+			Tags: tags,
+			Datapoints: []ts.Datapoint{
+				{Timestamp: blockStart, Value: value},
+				{Timestamp: blockStart.Add(15 * time.Second), Value: value + 1},
+			},
+		})
+	}
+	return series
+}
+
+func readTestFileSet(
+	t *testing.T,
+	dir string,
+	shard uint32,
+	blockStart time.Time,
+	volume int,
+) map[string][]ts.Datapoint {
+	reader, err := fs.NewReader(nil, fs.NewOptions().SetFilePathPrefix(dir))
+	require.NoError(t, err)
+
+	err = reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   ident.StringID("testns"),
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	result := make(map[string][]ts.Datapoint)
+	for {
+		id, tags, data, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, 2, tags.Remaining())
+		tags.Close()
+
+		data.IncRef()
+		var datapoints []ts.Datapoint
+		iter := m3tsz.NewReaderIterator(bytes.NewReader(data.Bytes()),
+			m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			datapoints = append(datapoints, ts.Datapoint{
+				Timestamp: dp.Timestamp,
+				Value:     dp.Value,
+			})
+		}
+		require.NoError(t, iter.Err())
+		data.DecRef()
+		result[id.String()] = datapoints
+	}
+	return result
+}
+
+func TestFileSetWriterWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prom-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Unix(0, 0).Add(10 * blockSize)
+		series     = newTestSeries(blockStart, 20, 1)
+	)
+	writer, shardSet := newTestFileSetWriter(t, dir, blockSize)
+	require.Len(t, writer.Partitions(), 2)
+	writeTestBlock(t, writer, blockStart, series)
+
+	expected := make(map[uint32]map[string][]ts.Datapoint)
+	for _, s := range series {
+		shard := shardSet.Lookup(s.ID)
+		if shard > 1 {
+			continue
+		}
+		if expected[shard] == nil {
+			expected[shard] = make(map[string][]ts.Datapoint)
+		}
+		expected[shard][s.ID.String()] = s.Datapoints
+	}
+	require.Len(t, expected, 2)
+
+	for shard, shardExpected := range expected {
+		actual := readTestFileSet(t, dir, shard, blockStart, 0)
+		require.Equal(t, len(shardExpected), len(actual))
+		for id, datapoints := range shardExpected {
+			require.Len(t, actual[id], len(datapoints))
+			for i, dp := range datapoints {
+				require.True(t, dp.Equal(actual[id][i]))
+			}
+		}
+	}
+
+	for shard := uint32(2); shard < testNumShards; shard++ {
+		exists, err := fs.DataFileSetExists(dir, ident.StringID("testns"),
+			shard, blockStart, 0)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+
+	indexFileSets, err := fs.IndexFileSetsAt(dir, ident.StringID("testns"),
+		blockStart.Truncate(2*blockSize))
+	require.NoError(t, err)
+	require.Len(t, indexFileSets, 1)
+}
+
+func TestFileSetWriterMergesExistingFileSets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prom-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = time.Unix(0, 0).Add(10 * blockSize)
+	)
+	writer, _ := newTestFileSetWriter(t, dir, blockSize)
+	writeTestBlock(t, writer, blockStart, newTestSeries(blockStart, 10, 1))
+	writeTestBlock(t, writer, blockStart,
+		newTestSeries(blockStart.Add(time.Minute), 20, 5))
+
+	var numSeries int
+	for shard := uint32(0); shard < 2; shard++ {
+		existing := readTestFileSet(t, dir, shard, blockStart, 0)
+		merged := readTestFileSet(t, dir, shard, blockStart, 1)
+		for id, datapoints := range merged {
+			if _, ok := existing[id]; ok {
+				require.Len(t, datapoints, 4)
+				require.Equal(t, float64(1), datapoints[0].Value)
+				require.Equal(t, float64(5), datapoints[2].Value)
+			} else {
+				require.Len(t, datapoints, 2)
+				require.Equal(t, float64(5), datapoints[0].Value)
+			}
+		}
+		numSeries += len(merged)
+	}
+	require.True(t, numSeries > 0)
+
+	indexFileSets, err := fs.IndexFileSetsAt(dir, ident.StringID("testns"),
+		blockStart.Truncate(2*blockSize))
+	require.NoError(t, err)
+	require.Len(t, indexFileSets, 2)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type importer struct {
+	source Source
+	writer Writer
+	opts   Options
+	logger *zap.Logger
+}
+
+// NewImporter returns an importer that reads the series of the source one
+// block at a time and writes them with the writer. The time range imported
+// is extended to the block boundaries of the namespace.
+func NewImporter(source Source, writer Writer, opts Options) (Importer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &importer{
+		source: source,
+		writer: writer,
+		opts:   opts,
+		logger: opts.InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (i *importer) Import() (Result, error) {
+	var (
+		result     Result
+		blockSize  = i.opts.BlockSize()
+		checkpoint = i.opts.Checkpoint()
+		dryRun     = i.opts.DryRun()
+		start, end = i.timeRange()
+	)
+	for blockStart := start; blockStart.Before(end); blockStart = blockStart.Add(blockSize) {
+		if checkpoint != nil && checkpoint.Imported(blockStart) {
+			result.SkippedBlocks++
+			continue
+		}
+
+		numSeries, numDatapoints, err := i.importBlock(blockStart)
+		if err != nil {
+			return result, err
+		}
+
+		if !dryRun {
+			if err := i.writer.Flush(blockStart); err != nil {
+				return result, fmt.Errorf("unable to flush block %s: %v", blockStart, err)
+			}
+			if checkpoint != nil {
+				if err := checkpoint.MarkImported(blockStart); err != nil {
+					return result, fmt.Errorf("unable to checkpoint block %s: %v", blockStart, err)
+				}
+			}
+		}
+
+		i.logger.Info("imported block",
+			zap.Time("blockStart", blockStart),
+			zap.Int("series", numSeries),
+			zap.Int("datapoints", numDatapoints),
+			zap.Bool("dryRun", dryRun))
+
+		result.Blocks++
+		result.Series += numSeries
+		result.Datapoints += numDatapoints
+	}
+	return result, nil
+}
+
+// importBlock reads and writes the series of the block one partition at a
+// time and returns the number of series and datapoints imported.
+func (i *importer) importBlock(blockStart time.Time) (int, int, error) {
+	var (
+		blockEnd      = blockStart.Add(i.opts.BlockSize())
+		dryRun        = i.opts.DryRun()
+		partitions    = []SeriesFilter{nil}
+		numSeries     int
+		numDatapoints int
+	)
+	if !dryRun {
+		partitions = i.writer.Partitions()
+	}
+
+	for _, filter := range partitions {
+		series, err := i.source.Read(blockStart, blockEnd, filter)
+		if err != nil {
+			return 0, 0, fmt.Errorf("unable to read block %s: %v", blockStart, err)
+		}
+
+		numSeries += len(series)
+		for _, s := range series {
+			numDatapoints += len(s.Datapoints)
+		}
+
+		if dryRun {
+			continue
+		}
+		if err := i.writer.Write(blockStart, series); err != nil {
+			return 0, 0, fmt.Errorf("unable to write block %s: %v", blockStart, err)
+		}
+	}
+	return numSeries, numDatapoints, nil
+}
+
+func (i *importer) timeRange() (time.Time, time.Time) {
+	start, end := i.source.Bounds()
+	if optStart := i.opts.Start(); !optStart.IsZero() && optStart.After(start) {
+		start = optStart
+	}
+	if optEnd := i.opts.End(); !optEnd.IsZero() && optEnd.Before(end) {
+		end = optEnd
+	}
+
+	blockSize := i.opts.BlockSize()
+	start = start.Truncate(blockSize)
+	if truncated := end.Truncate(blockSize); truncated.Before(end) {
+		end = truncated.Add(blockSize)
+	}
+	return start, end
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSource struct {
+	start time.Time
+	end   time.Time
+	reads []time.Time
+}
+
+func (s *testSource) Bounds() (time.Time, time.Time) {
+	return s.start, s.end
+}
+
+func (s *testSource) Read(start, end time.Time, filter SeriesFilter) ([]Series, error) {
+	s.reads = append(s.reads, start)
+	tags := models.NewTags(1, models.NewTagOptions()).
+		SetName([]byte("up"))
+	id := ident.BytesID(tags.ID())
+	if filter != nil && !filter(id) {
+		return nil, nil
+	}
+	return []Series{
+		{
+			ID:   id,
+			Tags: tags,
+			Datapoints: []ts.Datapoint{
+				{Timestamp: start, Value: 1},
+				{Timestamp: start.Add(time.Minute), Value: 2},
+			},
+		},
+	}, nil
+}
+
+func (s *testSource) Close() error {
+	return nil
+}
+
+type testWriter struct {
+	partitions []SeriesFilter
+	writes     []time.Time
+	flushes    []time.Time
+}
+
+func (w *testWriter) Partitions() []SeriesFilter {
+	if len(w.partitions) == 0 {
+		return []SeriesFilter{nil}
+	}
+	return w.partitions
+}
+
+func (w *testWriter) Write(blockStart time.Time, _ []Series) error {
+	w.writes = append(w.writes, blockStart)
+	return nil
+}
+
+func (w *testWriter) Flush(blockStart time.Time) error {
+	w.flushes = append(w.flushes, blockStart)
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	return nil
+}
+
+func newTestCheckpoint(t *testing.T) (Checkpoint, string, func()) {
+	dir, err := ioutil.TempDir("", "prom-import")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "checkpoint")
+	checkpoint, err := NewFileCheckpoint(path)
+	require.NoError(t, err)
+	return checkpoint, path, func() {
+		checkpoint.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestImporterImport(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		source    = &testSource{
+			start: start.Add(time.Minute),
+			end:   start.Add(2*blockSize + time.Minute),
+		}
+		writer = &testWriter{}
+	)
+
+	importer, err := NewImporter(source, writer, NewOptions().SetBlockSize(blockSize))
+	require.NoError(t, err)
+
+	result, err := importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, Result{Blocks: 3, Series: 3, Datapoints: 6}, result)
+
+	// The bounds of the source are extended to block boundaries.
+	expected := []time.Time{start, start.Add(blockSize), start.Add(2 * blockSize)}
+	require.Equal(t, expected, source.reads)
+	require.Equal(t, expected, writer.writes)
+	require.Equal(t, expected, writer.flushes)
+}
+
+func TestImporterImportPartitions(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		source    = &testSource{start: start, end: start.Add(2 * blockSize)}
+		writer    = &testWriter{
+			partitions: []SeriesFilter{
+				func(ident.ID) bool { return true },
+				func(ident.ID) bool { return false },
+			},
+		}
+	)
+
+	importer, err := NewImporter(source, writer, NewOptions().SetBlockSize(blockSize))
+	require.NoError(t, err)
+
+	result, err := importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, Result{Blocks: 2, Series: 2, Datapoints: 4}, result)
+
+	// Every partition of a block is read and written before it is flushed.
+	require.Equal(t, []time.Time{start, start, start.Add(blockSize), start.Add(blockSize)},
+		source.reads)
+	require.Equal(t, source.reads, writer.writes)
+	require.Equal(t, []time.Time{start, start.Add(blockSize)}, writer.flushes)
+}
+
+func TestImporterImportTimeRange(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		source    = &testSource{start: start, end: start.Add(4 * blockSize)}
+		writer    = &testWriter{}
+	)
+
+	opts := NewOptions().
+		SetBlockSize(blockSize).
+		SetStart(start.Add(blockSize + time.Minute)).
+		SetEnd(start.Add(2 * blockSize))
+	importer, err := NewImporter(source, writer, opts)
+	require.NoError(t, err)
+
+	result, err := importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Blocks)
+	require.Equal(t, []time.Time{start.Add(blockSize)}, writer.writes)
+}
+
+func TestImporterImportResume(t *testing.T) {
+	checkpoint, path, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		source    = &testSource{start: start, end: start.Add(3 * blockSize)}
+	)
+	require.NoError(t, checkpoint.MarkImported(start.Add(blockSize)))
+
+	writer := &testWriter{}
+	opts := NewOptions().
+		SetBlockSize(blockSize).
+		SetCheckpoint(checkpoint)
+	importer, err := NewImporter(source, writer, opts)
+	require.NoError(t, err)
+
+	result, err := importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Blocks)
+	require.Equal(t, 1, result.SkippedBlocks)
+	require.Equal(t, []time.Time{start, start.Add(2 * blockSize)}, writer.writes)
+
+	// Importing again after reopening the checkpoint imports nothing.
+	require.NoError(t, checkpoint.Close())
+	checkpoint, err = NewFileCheckpoint(path)
+	require.NoError(t, err)
+	defer checkpoint.Close()
+
+	writer = &testWriter{}
+	importer, err = NewImporter(source, writer, opts.SetCheckpoint(checkpoint))
+	require.NoError(t, err)
+
+	result, err = importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, Result{SkippedBlocks: 3}, result)
+	require.Empty(t, writer.writes)
+}
+
+func TestImporterImportDryRun(t *testing.T) {
+	checkpoint, _, cleanup := newTestCheckpoint(t)
+	defer cleanup()
+
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Unix(0, 0).Add(10 * blockSize)
+		source    = &testSource{start: start, end: start.Add(2 * blockSize)}
+	)
+
+	opts := NewOptions().
+		SetBlockSize(blockSize).
+		SetCheckpoint(checkpoint).
+		SetDryRun(true)
+	importer, err := NewImporter(source, nil, opts)
+	require.NoError(t, err)
+
+	result, err := importer.Import()
+	require.NoError(t, err)
+	require.Equal(t, Result{Blocks: 2, Series: 2, Datapoints: 4}, result)
+	require.False(t, checkpoint.Imported(start))
+	require.False(t, checkpoint.Imported(start.Add(blockSize)))
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// prom_import is a tool for importing the history of Prometheus TSDB blocks
+// into M3DB.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	aggsharding "github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/cluster/shard"
+	promimport "github.com/m3db/m3/src/cmd/tools/prom_import"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/query/models"
+	xconfig "github.com/m3db/m3/src/x/config"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	modeSession = "session"
+	modeFileSet = "fileset"
+)
+
+var (
+	optPrometheusDir  = flag.String("prometheus-dir", "", "Prometheus data directory or block directory to import")
+	optNamespace      = flag.String("namespace", "default", "Namespace to import into")
+	optBlockSize      = flag.Duration("block-size", 2*time.Hour, "Block size of the namespace")
+	optIndexBlockSize = flag.Duration("index-block-size", 0, "Index block size of the namespace, defaults to the block size, for fileset mode")
+	optStart          = flag.Int64("start", 0, "Start of the time range to import, defaults to the start of the blocks [in nsec]")
+	optEnd            = flag.Int64("end", 0, "End of the time range to import, exclusive, defaults to the end of the blocks [in nsec]")
+	optIDScheme       = flag.String("id-scheme", models.TypeLegacy.String(), "ID scheme of the coordinator tag options (legacy, quoted or prepend_meta)")
+	optMetricName     = flag.String("metric-name", "__name__", "Tag name the Prometheus metric name is converted to")
+	optBucketName     = flag.String("bucket-name", "le", "Tag name the Prometheus histogram bucket label is converted to")
+	optMode           = flag.String("mode", modeSession, "Import mode, either session to backfill with a client or fileset to write filesets")
+	optClientConfig   = flag.String("client-config", "", "Client configuration file, for session mode")
+	optPathPrefix     = flag.String("path-prefix", "/var/lib/m3db", "Path prefix to write filesets to, for fileset mode")
+	optNumShards      = flag.Int("num-shards", 0, "Number of shards of the placement, for fileset mode")
+	optShards         = flag.String("shards", "", "Shards to write filesets for such as 0..63, defaults to all shards, for fileset mode")
+	optCheckpointFile = flag.String("checkpoint-file", "", "File recording the imported blocks to resume an interrupted import")
+	optDryRun         = flag.Bool("dry-run", false, "Only read and count the series to import")
+)
+
+func main() {
+	flag.Parse()
+	if *optPrometheusDir == "" ||
+		*optNamespace == "" ||
+		*optBlockSize <= 0 ||
+		*optIndexBlockSize < 0 ||
+		*optStart < 0 ||
+		*optEnd < 0 ||
+		(*optMode != modeSession && *optMode != modeFileSet) {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+	iOpts := instrument.NewOptions().SetLogger(rawLogger)
+
+	tagOpts, err := newTagOptions()
+	if err != nil {
+		logger.Fatalf("invalid tag options: %v", err)
+	}
+
+	source, err := promimport.NewTSDBSource(*optPrometheusDir, tagOpts)
+	if err != nil {
+		logger.Fatalf("unable to open prometheus blocks: %v", err)
+	}
+	defer source.Close()
+
+	// NB: a dry run never writes so it does not need a writer.
+	var (
+		nsID   = ident.StringID(*optNamespace)
+		writer promimport.Writer
+	)
+	switch {
+	case *optDryRun:
+	case *optMode == modeSession:
+		writer, err = newSessionWriter(nsID, iOpts)
+	case *optMode == modeFileSet:
+		writer, err = newFileSetWriter(nsID, iOpts)
+	}
+	if err != nil {
+		logger.Fatalf("unable to create %s writer: %v", *optMode, err)
+	}
+	if writer != nil {
+		defer writer.Close()
+	}
+
+	opts := promimport.NewOptions().
+		SetBlockSize(*optBlockSize).
+		SetDryRun(*optDryRun).
+		SetInstrumentOptions(iOpts)
+	if *optStart > 0 {
+		opts = opts.SetStart(xtime.FromNanoseconds(*optStart))
+	}
+	if *optEnd > 0 {
+		opts = opts.SetEnd(xtime.FromNanoseconds(*optEnd))
+	}
+	if *optCheckpointFile != "" {
+		checkpoint, err := promimport.NewFileCheckpoint(*optCheckpointFile)
+		if err != nil {
+			logger.Fatalf("unable to open checkpoint: %v", err)
+		}
+		defer checkpoint.Close()
+		opts = opts.SetCheckpoint(checkpoint)
+	}
+
+	importer, err := promimport.NewImporter(source, writer, opts)
+	if err != nil {
+		logger.Fatalf("unable to create importer: %v", err)
+	}
+
+	result, err := importer.Import()
+	if err != nil {
+		logger.Fatalf("unable to import: %v", err)
+	}
+
+	logger.Infof("imported %d blocks (%d skipped), %d series, %d datapoints",
+		result.Blocks, result.SkippedBlocks, result.Series, result.Datapoints)
+}
+
+func newTagOptions() (models.TagOptions, error) {
+	var scheme models.IDSchemeType
+	for _, valid := range []models.IDSchemeType{
+		models.TypeLegacy,
+		models.TypeQuoted,
+		models.TypePrependMeta,
+	} {
+		if *optIDScheme == valid.String() {
+			scheme = valid
+		}
+	}
+	if scheme == models.TypeDefault {
+		return nil, fmt.Errorf("unknown id scheme: %s", *optIDScheme)
+	}
+
+	tagOpts := models.NewTagOptions().
+		SetIDSchemeType(scheme).
+		SetMetricName([]byte(*optMetricName)).
+		SetBucketName([]byte(*optBucketName))
+	return tagOpts, tagOpts.Validate()
+}
+
+func newSessionWriter(
+	nsID ident.ID,
+	iOpts instrument.Options,
+) (promimport.Writer, error) {
+	if *optClientConfig == "" {
+		return nil, fmt.Errorf("client config required")
+	}
+
+	var cfg client.Configuration
+	if err := xconfig.LoadFile(&cfg, *optClientConfig, xconfig.Options{}); err != nil {
+		return nil, err
+	}
+
+	c, err := cfg.NewAdminClient(client.ConfigurationParameters{
+		InstrumentOptions: iOpts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := c.DefaultAdminSession()
+	if err != nil {
+		return nil, err
+	}
+	return promimport.NewSessionWriter(session, nsID), nil
+}
+
+func newFileSetWriter(
+	nsID ident.ID,
+	iOpts instrument.Options,
+) (promimport.Writer, error) {
+	if *optNumShards <= 0 {
+		return nil, fmt.Errorf("number of shards required")
+	}
+
+	shardRange := *optShards
+	if shardRange == "" {
+		shardRange = fmt.Sprintf("0..%d", *optNumShards-1)
+	}
+	shardIDs, err := aggsharding.ParseShardSet(shardRange)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]shard.Shard, 0, len(shardIDs))
+	for id := range shardIDs {
+		if int(id) >= *optNumShards {
+			return nil, fmt.Errorf("shard %d out of range", id)
+		}
+		shards = append(shards, shard.NewShard(id).SetState(shard.Available))
+	}
+
+	shardSet, err := sharding.NewShardSet(shards, sharding.DefaultHashFn(*optNumShards))
+	if err != nil {
+		return nil, err
+	}
+
+	nsMetadata, err := newNamespaceMetadata(nsID)
+	if err != nil {
+		return nil, err
+	}
+
+	fsOpts := fs.NewOptions().
+		SetFilePathPrefix(*optPathPrefix).
+		SetInstrumentOptions(iOpts)
+	return promimport.NewFileSetWriter(fsOpts, nsMetadata, shardSet, iOpts)
+}
+
+func newNamespaceMetadata(nsID ident.ID) (namespace.Metadata, error) {
+	indexBlockSize := *optIndexBlockSize
+	if indexBlockSize == 0 {
+		indexBlockSize = *optBlockSize
+	}
+
+	// NB: the retention period is not used to write filesets, it only needs
+	// to cover the index block size for the options to be valid.
+	retentionOpts := namespace.NewRetentionOptions().SetBlockSize(*optBlockSize)
+	if retentionOpts.RetentionPeriod() < indexBlockSize {
+		retentionOpts = retentionOpts.SetRetentionPeriod(indexBlockSize)
+	}
+
+	nsOpts := namespace.NewOptions().
+		SetRetentionOptions(retentionOpts).
+		SetIndexOptions(namespace.NewIndexOptions().
+			SetEnabled(true).
+			SetBlockSize(indexBlockSize))
+	return namespace.NewMetadata(nsID, nsOpts)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultBlockSize = 2 * time.Hour
+)
+
+var (
+	errInvalidBlockSize = errors.New("block size must be positive")
+	errInvalidTimeRange = errors.New("end must be after start")
+)
+
+type options struct {
+	blockSize      time.Duration
+	start          time.Time
+	end            time.Time
+	dryRun         bool
+	checkpoint     Checkpoint
+	instrumentOpts instrument.Options
+}
+
+// NewOptions returns a new set of import options.
+func NewOptions() Options {
+	return &options{
+		blockSize:      defaultBlockSize,
+		instrumentOpts: instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.blockSize <= 0 {
+		return errInvalidBlockSize
+	}
+	if !o.start.IsZero() && !o.end.IsZero() && !o.end.After(o.start) {
+		return errInvalidTimeRange
+	}
+	return nil
+}
+
+func (o *options) SetBlockSize(value time.Duration) Options {
+	opts := *o
+	opts.blockSize = value
+	return &opts
+}
+
+func (o *options) BlockSize() time.Duration {
+	return o.blockSize
+}
+
+func (o *options) SetStart(value time.Time) Options {
+	opts := *o
+	opts.start = value
+	return &opts
+}
+
+func (o *options) Start() time.Time {
+	return o.start
+}
+
+func (o *options) SetEnd(value time.Time) Options {
+	opts := *o
+	opts.end = value
+	return &opts
+}
+
+func (o *options) End() time.Time {
+	return o.end
+}
+
+func (o *options) SetDryRun(value bool) Options {
+	opts := *o
+	opts.dryRun = value
+	return &opts
+}
+
+func (o *options) DryRun() bool {
+	return o.dryRun
+}
+
+func (o *options) SetCheckpoint(value Checkpoint) Options {
+	opts := *o
+	opts.checkpoint = value
+	return &opts
+}
+
+func (o *options) Checkpoint() Checkpoint {
+	return o.checkpoint
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type sessionWriter struct {
+	session   client.AdminSession
+	namespace ident.ID
+}
+
+// NewSessionWriter returns a writer that backfills series with the session,
+// which writes them directly into new fileset volumes of the replicas. The
+// blocks imported must have been flushed by the nodes, that is they must be
+// older than the buffer past of the namespace and within its retention.
+func NewSessionWriter(
+	session client.AdminSession,
+	namespace ident.ID,
+) Writer {
+	return &sessionWriter{
+		session:   session,
+		namespace: namespace,
+	}
+}
+
+func (w *sessionWriter) Partitions() []SeriesFilter {
+	// NB: backfills are batched by shard by the session.
+	return []SeriesFilter{nil}
+}
+
+func (w *sessionWriter) Write(blockStart time.Time, series []Series) error {
+	backfill := make([]client.BackfillSeries, 0, len(series))
+	for _, s := range series {
+		datapoints := make([]client.BackfillDatapoint, 0, len(s.Datapoints))
+		for _, dp := range s.Datapoints {
+			datapoints = append(datapoints, client.BackfillDatapoint{
+				Timestamp: dp.Timestamp,
+				Value:     dp.Value,
+				Unit:      xtime.Millisecond,
+			})
+		}
+		backfill = append(backfill, client.BackfillSeries{
+			ID:         s.ID,
+			Tags:       storage.TagsToIdentTagIterator(s.Tags),
+			Datapoints: datapoints,
+		})
+	}
+
+	return w.session.Backfill(w.namespace, blockStart, backfill)
+}
+
+func (w *sessionWriter) Flush(_ time.Time) error {
+	return nil
+}
+
+func (w *sessionWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package promimport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionWriterBackfills(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockStart = time.Now().Truncate(2 * time.Hour).Add(-24 * time.Hour)
+		series     = newTestSeries(blockStart, 2, 1)
+		session    = client.NewMockAdminSession(ctrl)
+		writer     = NewSessionWriter(session, ident.StringID("testns"))
+	)
+	session.EXPECT().
+		Backfill(ident.NewIDMatcher("testns"), blockStart, gomock.Any()).
+		DoAndReturn(func(_ ident.ID, _ time.Time, backfill []client.BackfillSeries) error {
+			require.Len(t, backfill, len(series))
+			for i, s := range backfill {
+				assert.True(t, series[i].ID.Equal(s.ID))
+				require.Equal(t, len(series[i].Tags.Tags), s.Tags.Remaining())
+				require.Len(t, s.Datapoints, len(series[i].Datapoints))
+				for j, dp := range s.Datapoints {
+					assert.True(t, series[i].Datapoints[j].Timestamp.Equal(dp.Timestamp))
+					assert.Equal(t, series[i].Datapoints[j].Value, dp.Value)
+					assert.Equal(t, xtime.Millisecond, dp.Unit)
+				}
+			}
+			return nil
+		})
+
+	require.NoError(t, writer.Write(blockStart, series))
+	require.NoError(t, writer.Close())
+}
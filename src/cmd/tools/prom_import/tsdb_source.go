@@ -0,0 +1,266 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/labels"
+)
+
+const (
+	blockMetaFile = "meta.json"
+)
+
+// allSeriesMatcher matches every series since every series has a name.
+var allSeriesMatcher = labels.NewMustRegexpMatcher("__name__", ".+")
+
+type tsdbSource struct {
+	blocks  []*tsdb.Block
+	tagOpts models.TagOptions
+	start   time.Time
+	end     time.Time
+}
+
+// NewTSDBSource returns a source reading the Prometheus TSDB blocks in dir,
+// which is either a single block directory or a Prometheus data directory.
+// Samples that are only in the write ahead log of a data directory are not
+// read, so Prometheus should be stopped and its head compacted first.
+func NewTSDBSource(dir string, tagOpts models.TagOptions) (Source, error) {
+	dirs, err := blockDirs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no blocks found in %s", dir)
+	}
+
+	s := &tsdbSource{tagOpts: tagOpts}
+	for _, dir := range dirs {
+		block, err := tsdb.OpenBlock(log.NewNopLogger(), dir, nil)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("unable to open block %s: %v", dir, err)
+		}
+		s.blocks = append(s.blocks, block)
+
+		// NB: the max time of a block is exclusive.
+		meta := block.Meta()
+		start, end := fromPromTimestamp(meta.MinTime), fromPromTimestamp(meta.MaxTime)
+		if s.start.IsZero() || start.Before(s.start) {
+			s.start = start
+		}
+		if end.After(s.end) {
+			s.end = end
+		}
+	}
+	return s, nil
+}
+
+func blockDirs(dir string) ([]string, error) {
+	if isBlockDir(dir) {
+		return []string{dir}, nil
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		if info.IsDir() && isBlockDir(path) {
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs, nil
+}
+
+func isBlockDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, blockMetaFile))
+	return err == nil
+}
+
+func (s *tsdbSource) Bounds() (time.Time, time.Time) {
+	return s.start, s.end
+}
+
+func (s *tsdbSource) Read(start, end time.Time, filter SeriesFilter) ([]Series, error) {
+	var (
+		mint = toPromTimestamp(start)
+		// NB: the max time of a querier is inclusive.
+		maxt   = toPromTimestamp(end) - 1
+		result []Series
+		byID   = make(map[string]int)
+		merged = make(map[int]struct{})
+	)
+	for _, block := range s.blocks {
+		if meta := block.Meta(); meta.MaxTime <= mint || meta.MinTime > maxt {
+			continue
+		}
+
+		querier, err := tsdb.NewBlockQuerier(block, mint, maxt)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.readBlock(querier, mint, maxt, filter, func(series Series) {
+			// NB: a series has a separate entry in every block it has
+			// samples in, so blocks overlapping the time range are merged.
+			key := series.ID.String()
+			if idx, ok := byID[key]; ok {
+				result[idx].Datapoints = append(result[idx].Datapoints, series.Datapoints...)
+				merged[idx] = struct{}{}
+				return
+			}
+			byID[key] = len(result)
+			result = append(result, series)
+		})
+		if closeErr := querier.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for idx := range merged {
+		result[idx].Datapoints = sortAndDedupe(result[idx].Datapoints)
+	}
+	return result, nil
+}
+
+func (s *tsdbSource) readBlock(
+	querier tsdb.Querier,
+	mint, maxt int64,
+	filter SeriesFilter,
+	fn func(series Series),
+) error {
+	set, err := querier.Select(allSeriesMatcher)
+	if err != nil {
+		return err
+	}
+
+	for set.Next() {
+		var (
+			series = set.At()
+			tags   = labelsToTags(series.Labels(), s.tagOpts)
+			id     = ident.BytesID(tags.ID())
+		)
+		// NB: filter before reading the samples so the chunks of series
+		// that are not read are not decoded.
+		if filter != nil && !filter(id) {
+			continue
+		}
+
+		var (
+			iter       = series.Iterator()
+			datapoints []ts.Datapoint
+		)
+		for iter.Next() {
+			t, v := iter.At()
+			if t < mint || t > maxt || value.IsStaleNaN(v) {
+				continue
+			}
+			datapoints = append(datapoints, ts.Datapoint{
+				Timestamp: fromPromTimestamp(t),
+				Value:     v,
+			})
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(datapoints) == 0 {
+			continue
+		}
+
+		fn(Series{
+			ID:         id,
+			Tags:       tags,
+			Datapoints: datapoints,
+		})
+	}
+	return set.Err()
+}
+
+func (s *tsdbSource) Close() error {
+	multiErr := xerrors.NewMultiError()
+	for _, block := range s.blocks {
+		multiErr = multiErr.Add(block.Close())
+	}
+	s.blocks = nil
+	return multiErr.FinalError()
+}
+
+// labelsToTags converts Prometheus labels to M3 tags the same way that
+// Prometheus remote writes are converted.
+func labelsToTags(lbls labels.Labels, tagOpts models.TagOptions) models.Tags {
+	promLabels := make([]prompb.Label, 0, len(lbls))
+	for _, l := range lbls {
+		promLabels = append(promLabels, prompb.Label{
+			Name:  []byte(l.Name),
+			Value: []byte(l.Value),
+		})
+	}
+	return storage.PromLabelsToM3Tags(promLabels, tagOpts)
+}
+
+func sortAndDedupe(datapoints []ts.Datapoint) []ts.Datapoint {
+	sort.SliceStable(datapoints, func(i, j int) bool {
+		return datapoints[i].Timestamp.Before(datapoints[j].Timestamp)
+	})
+
+	// NB: overlapping blocks can hold the same sample more than once, the
+	// sample of the block read last wins.
+	deduped := datapoints[:0]
+	for _, dp := range datapoints {
+		if n := len(deduped); n > 0 && deduped[n-1].Timestamp.Equal(dp.Timestamp) {
+			deduped[n-1] = dp
+			continue
+		}
+		deduped = append(deduped, dp)
+	}
+	return deduped
+}
+
+func toPromTimestamp(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromPromTimestamp(t int64) time.Time {
+	return time.Unix(0, t*int64(time.Millisecond))
+}
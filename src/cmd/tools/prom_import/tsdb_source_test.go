@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promimport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/prometheus/prometheus/tsdb/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsToTags(t *testing.T) {
+	tagOpts := models.NewTagOptions().
+		SetIDSchemeType(models.TypeQuoted).
+		SetMetricName([]byte("name"))
+
+	tags := labelsToTags(labels.FromStrings(
+		"__name__", "http_request_duration_seconds_bucket",
+		"le", "0.5",
+		"job", "api",
+	), tagOpts)
+
+	name, ok := tags.Name()
+	require.True(t, ok)
+	require.Equal(t, "http_request_duration_seconds_bucket", string(name))
+
+	bucket, ok := tags.Bucket()
+	require.True(t, ok)
+	require.Equal(t, "0.5", string(bucket))
+
+	require.Equal(t,
+		`{job="api",le="0.5",name="http_request_duration_seconds_bucket"}`,
+		string(tags.ID()))
+}
+
+func TestSortAndDedupe(t *testing.T) {
+	start := time.Unix(1000, 0)
+	datapoints := []ts.Datapoint{
+		{Timestamp: start.Add(2 * time.Second), Value: 2},
+		{Timestamp: start, Value: 0},
+		{Timestamp: start.Add(time.Second), Value: 1},
+		{Timestamp: start.Add(2 * time.Second), Value: 3},
+	}
+
+	require.Equal(t, []ts.Datapoint{
+		{Timestamp: start, Value: 0},
+		{Timestamp: start.Add(time.Second), Value: 1},
+		{Timestamp: start.Add(2 * time.Second), Value: 3},
+	}, sortAndDedupe(datapoints))
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package promimport imports the history of Prometheus TSDB blocks into M3DB.
+package promimport
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Series is a series read from Prometheus blocks with its labels converted
+// to M3 tags.
+type Series struct {
+	// ID is the ID of the series generated from its tags.
+	ID ident.ID
+
+	// Tags are the tags of the series.
+	Tags models.Tags
+
+	// Datapoints are the datapoints of the series sorted by time.
+	Datapoints []ts.Datapoint
+}
+
+// SeriesFilter returns whether the series with the ID should be read.
+type SeriesFilter func(id ident.ID) bool
+
+// Source reads series from Prometheus blocks.
+type Source interface {
+	// Bounds returns the time range covered by the source, end exclusive.
+	Bounds() (time.Time, time.Time)
+
+	// Read returns the series matching the filter with datapoints in the
+	// time range [start, end), excluding deleted and stale samples. A nil
+	// filter matches every series.
+	Read(start, end time.Time, filter SeriesFilter) ([]Series, error)
+
+	// Close closes the source.
+	Close() error
+}
+
+// Writer writes imported series into M3DB.
+type Writer interface {
+	// Partitions returns the filters splitting the series of a block into
+	// partitions that are read and written one at a time to bound memory
+	// use, a single nil filter writes all series of a block at once.
+	Partitions() []SeriesFilter
+
+	// Write writes the series of a partition of the block starting at block
+	// start. Writing the same block more than once must be safe so that an
+	// interrupted import can be resumed.
+	Write(blockStart time.Time, series []Series) error
+
+	// Flush is called once every partition of the block starting at block
+	// start has been written.
+	Flush(blockStart time.Time) error
+
+	// Close closes the writer.
+	Close() error
+}
+
+// Checkpoint records the blocks that have been imported so that an
+// interrupted import can be resumed.
+type Checkpoint interface {
+	// Imported returns whether the block starting at block start has been
+	// imported.
+	Imported(blockStart time.Time) bool
+
+	// MarkImported durably records that the block starting at block start
+	// has been imported.
+	MarkImported(blockStart time.Time) error
+
+	// Close closes the checkpoint.
+	Close() error
+}
+
+// Result is the result of an import.
+type Result struct {
+	// Blocks is the number of blocks imported.
+	Blocks int
+
+	// SkippedBlocks is the number of blocks skipped since the checkpoint
+	// records them as already imported.
+	SkippedBlocks int
+
+	// Series is the number of series imported, counted once per block.
+	Series int
+
+	// Datapoints is the number of datapoints imported.
+	Datapoints int
+}
+
+// Importer imports the series of a source into a writer.
+type Importer interface {
+	// Import imports all blocks of the source that have not been imported yet.
+	Import() (Result, error)
+}
+
+// Options are the options for importing Prometheus blocks.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetBlockSize sets the block size of the namespace imported into, series
+	// are read and written one block at a time.
+	SetBlockSize(value time.Duration) Options
+
+	// BlockSize returns the block size of the namespace imported into.
+	BlockSize() time.Duration
+
+	// SetStart sets the start of the time range to import, the zero value
+	// imports from the start of the source.
+	SetStart(value time.Time) Options
+
+	// Start returns the start of the time range to import.
+	Start() time.Time
+
+	// SetEnd sets the end of the time range to import, exclusive, the zero
+	// value imports until the end of the source.
+	SetEnd(value time.Time) Options
+
+	// End returns the end of the time range to import.
+	End() time.Time
+
+	// SetDryRun sets whether to only read and count the series to import
+	// without writing them or recording progress.
+	SetDryRun(value bool) Options
+
+	// DryRun returns whether to only read and count the series to import.
+	DryRun() bool
+
+	// SetCheckpoint sets the checkpoint recording the imported blocks, if
+	// not set every block is imported.
+	SetCheckpoint(value Checkpoint) Options
+
+	// Checkpoint returns the checkpoint recording the imported blocks.
+	Checkpoint() Checkpoint
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
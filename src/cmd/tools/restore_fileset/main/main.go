@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	optBackupDirectory = flag.String("backup-directory", "", "Directory filesets were backed up to")
+	optKeyPrefix       = flag.String("key-prefix", "", "Key prefix filesets were backed up with, defaults to the host ID")
+	optPathPrefix      = flag.String("path-prefix", "/var/lib/m3db", "Path prefix to restore filesets to")
+	optNamespace       = flag.String("namespace", "metrics", "Namespace")
+	optBlockStart      = flag.Int64("block-start", 0, "Start of the range of block starts to restore [in nsec]")
+	optBlockEnd        = flag.Int64("block-end", 0, "End of the range of block starts to restore, exclusive [in nsec]")
+)
+
+func main() {
+	flag.Parse()
+	if *optBackupDirectory == "" ||
+		*optKeyPrefix == "" ||
+		*optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optBlockStart < 0 ||
+		*optBlockEnd <= *optBlockStart {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	opts := backup.NewOptions().
+		SetObjectStore(backup.NewDirectoryObjectStore(*optBackupDirectory)).
+		SetKeyPrefix(*optKeyPrefix).
+		SetFilePathPrefix(*optPathPrefix)
+	opts = opts.SetInstrumentOptions(opts.InstrumentOptions().SetLogger(rawLogger))
+
+	result, err := backup.Restore(opts, ident.StringID(*optNamespace),
+		xtime.FromNanoseconds(*optBlockStart), xtime.FromNanoseconds(*optBlockEnd))
+	if err != nil {
+		logger.Fatalf("unable to restore: %v", err)
+	}
+
+	logger.Infof("successfully restored %d filesets (%d files)", result.FileSets, result.Files)
+}
@@ -368,6 +368,7 @@ func fixFileSet(
 		FileSetContentType: fileSet.ID.FileSetContentType,
 		Identifier:         fileSet.ID,
 		BlockSize:          reader.Status().BlockSize,
+		DataCompression:    reader.Status().DataCompression,
 	})
 	if err != nil {
 		return err
@@ -78,6 +78,9 @@ func (h *handler) Handle(conn net.Conn) {
 		case encoding.GaugeWithMetadatasType:
 			metric = current.GaugeWithMetadatas.Gauge.ToUnion()
 			metadatas = current.GaugeWithMetadatas.StagedMetadatas
+		case encoding.HistogramWithMetadatasType:
+			metric = current.HistogramWithMetadatas.Histogram.ToUnion()
+			metadatas = current.HistogramWithMetadatas.StagedMetadatas
 		default:
 			h.logger.Error("unrecognized message type",
 				zap.Any("messageType", current.Type),
@@ -260,6 +260,21 @@ func (mr *MockSessionMockRecorder) Aggregate(namespace, q, opts interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// DeleteSeries mocks base method
+func (m *MockSession) DeleteSeries(namespace ident.ID, q index.Query, startInclusive, endExclusive time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", namespace, q, startInclusive, endExclusive)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockSessionMockRecorder) DeleteSeries(namespace, q, startInclusive, endExclusive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockSession)(nil).DeleteSeries), namespace, q, startInclusive, endExclusive)
+}
+
 // ShardID mocks base method
 func (m *MockSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -848,6 +863,21 @@ func (mr *MockAdminSessionMockRecorder) Aggregate(namespace, q, opts interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockAdminSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// DeleteSeries mocks base method
+func (m *MockAdminSession) DeleteSeries(namespace ident.ID, q index.Query, startInclusive, endExclusive time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", namespace, q, startInclusive, endExclusive)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockAdminSessionMockRecorder) DeleteSeries(namespace, q, startInclusive, endExclusive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockAdminSession)(nil).DeleteSeries), namespace, q, startInclusive, endExclusive)
+}
+
 // ShardID mocks base method
 func (m *MockAdminSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -950,6 +980,20 @@ func (mr *MockAdminSessionMockRecorder) Truncate(namespace interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockAdminSession)(nil).Truncate), namespace)
 }
 
+// Backfill mocks base method
+func (m *MockAdminSession) Backfill(namespace ident.ID, blockStart time.Time, series []BackfillSeries) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", namespace, blockStart, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockAdminSessionMockRecorder) Backfill(namespace, blockStart, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*MockAdminSession)(nil).Backfill), namespace, blockStart, series)
+}
+
 // FetchBootstrapBlocksFromPeers mocks base method
 func (m *MockAdminSession) FetchBootstrapBlocksFromPeers(namespace namespace.Metadata, shard uint32, start, end time.Time, opts result.Options) (result.ShardResult, error) {
 	m.ctrl.T.Helper()
@@ -1509,6 +1553,90 @@ func (mr *MockOptionsMockRecorder) TruncateRequestTimeout() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateRequestTimeout", reflect.TypeOf((*MockOptions)(nil).TruncateRequestTimeout))
 }
 
+// SetDeleteSeriesRequestTimeout mocks base method
+func (m *MockOptions) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleteSeriesRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDeleteSeriesRequestTimeout indicates an expected call of SetDeleteSeriesRequestTimeout
+func (mr *MockOptionsMockRecorder) SetDeleteSeriesRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleteSeriesRequestTimeout", reflect.TypeOf((*MockOptions)(nil).SetDeleteSeriesRequestTimeout), value)
+}
+
+// DeleteSeriesRequestTimeout mocks base method
+func (m *MockOptions) DeleteSeriesRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeriesRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DeleteSeriesRequestTimeout indicates an expected call of DeleteSeriesRequestTimeout
+func (mr *MockOptionsMockRecorder) DeleteSeriesRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeriesRequestTimeout", reflect.TypeOf((*MockOptions)(nil).DeleteSeriesRequestTimeout))
+}
+
+// SetBackfillRequestTimeout mocks base method
+func (m *MockOptions) SetBackfillRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackfillRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackfillRequestTimeout indicates an expected call of SetBackfillRequestTimeout
+func (mr *MockOptionsMockRecorder) SetBackfillRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackfillRequestTimeout", reflect.TypeOf((*MockOptions)(nil).SetBackfillRequestTimeout), value)
+}
+
+// BackfillRequestTimeout mocks base method
+func (m *MockOptions) BackfillRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BackfillRequestTimeout indicates an expected call of BackfillRequestTimeout
+func (mr *MockOptionsMockRecorder) BackfillRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillRequestTimeout", reflect.TypeOf((*MockOptions)(nil).BackfillRequestTimeout))
+}
+
+// SetBackfillBatchSize mocks base method
+func (m *MockOptions) SetBackfillBatchSize(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackfillBatchSize", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackfillBatchSize indicates an expected call of SetBackfillBatchSize
+func (mr *MockOptionsMockRecorder) SetBackfillBatchSize(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackfillBatchSize", reflect.TypeOf((*MockOptions)(nil).SetBackfillBatchSize), value)
+}
+
+// BackfillBatchSize mocks base method
+func (m *MockOptions) BackfillBatchSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillBatchSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// BackfillBatchSize indicates an expected call of BackfillBatchSize
+func (mr *MockOptionsMockRecorder) BackfillBatchSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillBatchSize", reflect.TypeOf((*MockOptions)(nil).BackfillBatchSize))
+}
+
 // SetBackgroundConnectInterval mocks base method
 func (m *MockOptions) SetBackgroundConnectInterval(value time.Duration) Options {
 	m.ctrl.T.Helper()
@@ -2876,6 +3004,90 @@ func (mr *MockAdminOptionsMockRecorder) TruncateRequestTimeout() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).TruncateRequestTimeout))
 }
 
+// SetDeleteSeriesRequestTimeout mocks base method
+func (m *MockAdminOptions) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleteSeriesRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDeleteSeriesRequestTimeout indicates an expected call of SetDeleteSeriesRequestTimeout
+func (mr *MockAdminOptionsMockRecorder) SetDeleteSeriesRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleteSeriesRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).SetDeleteSeriesRequestTimeout), value)
+}
+
+// DeleteSeriesRequestTimeout mocks base method
+func (m *MockAdminOptions) DeleteSeriesRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeriesRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DeleteSeriesRequestTimeout indicates an expected call of DeleteSeriesRequestTimeout
+func (mr *MockAdminOptionsMockRecorder) DeleteSeriesRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeriesRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).DeleteSeriesRequestTimeout))
+}
+
+// SetBackfillRequestTimeout mocks base method
+func (m *MockAdminOptions) SetBackfillRequestTimeout(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackfillRequestTimeout", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackfillRequestTimeout indicates an expected call of SetBackfillRequestTimeout
+func (mr *MockAdminOptionsMockRecorder) SetBackfillRequestTimeout(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackfillRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).SetBackfillRequestTimeout), value)
+}
+
+// BackfillRequestTimeout mocks base method
+func (m *MockAdminOptions) BackfillRequestTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillRequestTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BackfillRequestTimeout indicates an expected call of BackfillRequestTimeout
+func (mr *MockAdminOptionsMockRecorder) BackfillRequestTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillRequestTimeout", reflect.TypeOf((*MockAdminOptions)(nil).BackfillRequestTimeout))
+}
+
+// SetBackfillBatchSize mocks base method
+func (m *MockAdminOptions) SetBackfillBatchSize(value int) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackfillBatchSize", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackfillBatchSize indicates an expected call of SetBackfillBatchSize
+func (mr *MockAdminOptionsMockRecorder) SetBackfillBatchSize(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackfillBatchSize", reflect.TypeOf((*MockAdminOptions)(nil).SetBackfillBatchSize), value)
+}
+
+// BackfillBatchSize mocks base method
+func (m *MockAdminOptions) BackfillBatchSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillBatchSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// BackfillBatchSize indicates an expected call of BackfillBatchSize
+func (mr *MockAdminOptionsMockRecorder) BackfillBatchSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillBatchSize", reflect.TypeOf((*MockAdminOptions)(nil).BackfillBatchSize))
+}
+
 // SetBackgroundConnectInterval mocks base method
 func (m *MockAdminOptions) SetBackgroundConnectInterval(value time.Duration) Options {
 	m.ctrl.T.Helper()
@@ -4097,6 +4309,21 @@ func (mr *MockclientSessionMockRecorder) Aggregate(namespace, q, opts interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockclientSession)(nil).Aggregate), namespace, q, opts)
 }
 
+// DeleteSeries mocks base method
+func (m *MockclientSession) DeleteSeries(namespace ident.ID, q index.Query, startInclusive, endExclusive time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", namespace, q, startInclusive, endExclusive)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockclientSessionMockRecorder) DeleteSeries(namespace, q, startInclusive, endExclusive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockclientSession)(nil).DeleteSeries), namespace, q, startInclusive, endExclusive)
+}
+
 // ShardID mocks base method
 func (m *MockclientSession) ShardID(id ident.ID) (uint32, error) {
 	m.ctrl.T.Helper()
@@ -4199,6 +4426,20 @@ func (mr *MockclientSessionMockRecorder) Truncate(namespace interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockclientSession)(nil).Truncate), namespace)
 }
 
+// Backfill mocks base method
+func (m *MockclientSession) Backfill(namespace ident.ID, blockStart time.Time, series []BackfillSeries) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", namespace, blockStart, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockclientSessionMockRecorder) Backfill(namespace, blockStart, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*MockclientSession)(nil).Backfill), namespace, blockStart, series)
+}
+
 // FetchBootstrapBlocksFromPeers mocks base method
 func (m *MockclientSession) FetchBootstrapBlocksFromPeers(namespace namespace.Metadata, shard uint32, start, end time.Time, opts result.Options) (result.ShardResult, error) {
 	m.ctrl.T.Helper()
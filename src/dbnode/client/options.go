@@ -83,6 +83,17 @@ const (
 	// defaultTruncateRequestTimeout is the default truncate request timeout
 	defaultTruncateRequestTimeout = 60 * time.Second
 
+	// defaultDeleteSeriesRequestTimeout is the default delete series request timeout
+	defaultDeleteSeriesRequestTimeout = 60 * time.Second
+
+	// defaultBackfillRequestTimeout is the default backfill request timeout,
+	// backfills wait for in progress flushes and rewrite whole blocks
+	defaultBackfillRequestTimeout = 10 * time.Minute
+
+	// defaultBackfillBatchSize is the default backfill batch size, each
+	// request rewrites the block of a shard so batches are kept large
+	defaultBackfillBatchSize = 4096
+
 	// defaultIdentifierPoolSize is the default identifier pool size
 	defaultIdentifierPoolSize = 8192
 
@@ -220,6 +231,9 @@ type options struct {
 	writeRequestTimeout                     time.Duration
 	fetchRequestTimeout                     time.Duration
 	truncateRequestTimeout                  time.Duration
+	deleteSeriesRequestTimeout              time.Duration
+	backfillRequestTimeout                  time.Duration
+	backfillBatchSize                       int
 	backgroundConnectInterval               time.Duration
 	backgroundConnectStutter                time.Duration
 	backgroundHealthCheckInterval           time.Duration
@@ -323,6 +337,9 @@ func newOptions() *options {
 		writeRequestTimeout:                     defaultWriteRequestTimeout,
 		fetchRequestTimeout:                     defaultFetchRequestTimeout,
 		truncateRequestTimeout:                  defaultTruncateRequestTimeout,
+		deleteSeriesRequestTimeout:              defaultDeleteSeriesRequestTimeout,
+		backfillRequestTimeout:                  defaultBackfillRequestTimeout,
+		backfillBatchSize:                       defaultBackfillBatchSize,
 		backgroundConnectInterval:               defaultBackgroundConnectInterval,
 		backgroundConnectStutter:                defaultBackgroundConnectStutter,
 		backgroundHealthCheckInterval:           defaultBackgroundHealthCheckInterval,
@@ -575,6 +592,36 @@ func (o *options) TruncateRequestTimeout() time.Duration {
 	return o.truncateRequestTimeout
 }
 
+func (o *options) SetDeleteSeriesRequestTimeout(value time.Duration) Options {
+	opts := *o
+	opts.deleteSeriesRequestTimeout = value
+	return &opts
+}
+
+func (o *options) DeleteSeriesRequestTimeout() time.Duration {
+	return o.deleteSeriesRequestTimeout
+}
+
+func (o *options) SetBackfillRequestTimeout(value time.Duration) Options {
+	opts := *o
+	opts.backfillRequestTimeout = value
+	return &opts
+}
+
+func (o *options) BackfillRequestTimeout() time.Duration {
+	return o.backfillRequestTimeout
+}
+
+func (o *options) SetBackfillBatchSize(value int) Options {
+	opts := *o
+	opts.backfillBatchSize = value
+	return &opts
+}
+
+func (o *options) BackfillBatchSize() int {
+	return o.backfillBatchSize
+}
+
 func (o *options) SetBackgroundConnectInterval(value time.Duration) Options {
 	opts := *o
 	opts.backgroundConnectInterval = value
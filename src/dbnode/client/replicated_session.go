@@ -237,6 +237,14 @@ func (s replicatedSession) FetchTaggedIDs(namespace ident.ID, q index.Query, opt
 	return s.session.FetchTaggedIDs(namespace, q, opts)
 }
 
+// DeleteSeries resolves the provided query to known IDs and deletes the
+// data for them within the given time range.
+func (s replicatedSession) DeleteSeries(
+	namespace ident.ID, q index.Query, startInclusive, endExclusive time.Time,
+) (int64, error) {
+	return s.session.DeleteSeries(namespace, q, startInclusive, endExclusive)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -283,6 +291,14 @@ func (s replicatedSession) Truncate(namespace ident.ID) (int64, error) {
 	return s.session.Truncate(namespace)
 }
 
+// Backfill writes the sorted datapoints of the series for the block directly
+// into new fileset volumes of the replicas in the primary cluster.
+func (s replicatedSession) Backfill(
+	namespace ident.ID, blockStart time.Time, series []BackfillSeries,
+) error {
+	return s.session.Backfill(namespace, blockStart, series)
+}
+
 // FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 // for each series using the runtime configurable bootstrap level consistency.
 func (s replicatedSession) FetchBootstrapBlocksFromPeers(
@@ -1867,6 +1867,237 @@ func (s *session) Truncate(namespace ident.ID) (int64, error) {
 	return truncated, resultErr.FinalError()
 }
 
+func (s *session) DeleteSeries(
+	namespace ident.ID,
+	q index.Query,
+	startInclusive, endExclusive time.Time,
+) (int64, error) {
+	request, err := convert.ToRPCDeleteSeriesRequest(namespace, q,
+		startInclusive, endExclusive)
+	if err != nil {
+		return 0, xerrors.NewInvalidParamsError(err)
+	}
+
+	var (
+		wg            sync.WaitGroup
+		enqueueErr    xerrors.MultiError
+		resultErrLock sync.Mutex
+		resultErr     xerrors.MultiError
+		deleted       int64
+	)
+
+	d := &deleteSeriesOp{request: request}
+	d.completionFn = func(result interface{}, err error) {
+		if err != nil {
+			resultErrLock.Lock()
+			resultErr = resultErr.Add(err)
+			resultErrLock.Unlock()
+		} else {
+			res := result.(*rpc.DeleteSeriesResult_)
+			atomic.AddInt64(&deleted, res.NumSeries)
+		}
+		wg.Done()
+	}
+
+	s.state.RLock()
+	for idx := range s.state.queues {
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(d); err != nil {
+			wg.Done()
+			enqueueErr = enqueueErr.Add(err)
+		}
+	}
+	s.state.RUnlock()
+
+	if err := enqueueErr.FinalError(); err != nil {
+		s.log.Error("failed to enqueue request", zap.Error(err))
+		return 0, err
+	}
+
+	// Wait for the series to be deleted on all replicas
+	wg.Wait()
+
+	return deleted, resultErr.FinalError()
+}
+
+func (s *session) Backfill(
+	namespace ident.ID,
+	blockStart time.Time,
+	series []BackfillSeries,
+) error {
+	blockStartValue, err := convert.ToValue(blockStart, rpc.TimeType_UNIX_NANOSECONDS)
+	if err != nil {
+		return xerrors.NewInvalidParamsError(err)
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return errSessionStatusNotOpen
+	}
+
+	// Group the series by shard since the receiving hosts write a new
+	// fileset volume for every shard of a request, requests spanning few
+	// shards keep the number of volumes written per block down.
+	var (
+		shardSet      = s.state.topoMap.ShardSet()
+		shards        []uint32
+		seriesByShard = make(map[uint32][]BackfillSeries)
+	)
+	for _, elem := range series {
+		shard := shardSet.Lookup(elem.ID)
+		if _, ok := seriesByShard[shard]; !ok {
+			shards = append(shards, shard)
+		}
+		seriesByShard[shard] = append(seriesByShard[shard], elem)
+	}
+	s.state.RUnlock()
+
+	batchSize := s.opts.BackfillBatchSize()
+	for _, shard := range shards {
+		shardSeries := seriesByShard[shard]
+		for len(shardSeries) > 0 {
+			size := batchSize
+			if size <= 0 || size > len(shardSeries) {
+				size = len(shardSeries)
+			}
+			err := s.backfillBatch(namespace, blockStartValue, shard, shardSeries[:size])
+			if err != nil {
+				return err
+			}
+			shardSeries = shardSeries[size:]
+		}
+	}
+
+	return nil
+}
+
+// backfillBatch sends a batch of series of a single shard to every replica
+// of the shard and waits for the write consistency level to be met.
+func (s *session) backfillBatch(
+	namespace ident.ID,
+	blockStart int64,
+	shard uint32,
+	series []BackfillSeries,
+) error {
+	rpcSeries := make([]*rpc.BackfillSeries, 0, len(series))
+	for _, elem := range series {
+		converted, err := s.newRPCBackfillSeries(elem)
+		if err != nil {
+			return xerrors.NewInvalidParamsError(err)
+		}
+		rpcSeries = append(rpcSeries, converted)
+	}
+
+	var (
+		wg            sync.WaitGroup
+		resultErrLock sync.Mutex
+		resultErrs    int32
+		errs          []error
+	)
+	completionFn := func(result interface{}, err error) {
+		if err != nil {
+			resultErrLock.Lock()
+			resultErrs++
+			errs = append(errs, err)
+			resultErrLock.Unlock()
+		}
+		wg.Done()
+	}
+
+	s.state.RLock()
+	if s.state.status != statusOpen {
+		s.state.RUnlock()
+		return errSessionStatusNotOpen
+	}
+
+	var (
+		level      = s.state.writeLevel
+		majority   = int32(s.state.majority)
+		enqueued   int32
+		enqueueErr error
+	)
+	err := s.state.topoMap.RouteShardForEach(shard, func(idx int, host topology.Host) {
+		if enqueueErr != nil {
+			return
+		}
+
+		b := &backfillOp{completionFn: completionFn}
+		b.request.NameSpace = namespace.Bytes()
+		b.request.BlockStart = blockStart
+		b.request.BlockStartTimeType = rpc.TimeType_UNIX_NANOSECONDS
+		b.request.Series = rpcSeries
+
+		wg.Add(1)
+		if err := s.state.queues[idx].Enqueue(b); err != nil {
+			wg.Done()
+			enqueueErr = err
+			return
+		}
+		enqueued++
+	})
+	s.state.RUnlock()
+
+	// Wait for any enqueued requests to complete before returning so that
+	// the series are no longer referenced once the call has returned.
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	if enqueueErr != nil {
+		// NB: if this happens we have a bug, once we are in the read
+		// lock the current queues should never be closed
+		s.log.Error("[invariant violated] failed to enqueue backfill", zap.Error(enqueueErr))
+		return enqueueErr
+	}
+
+	return s.writeConsistencyResult(level, majority, enqueued, enqueued,
+		resultErrs, errs)
+}
+
+func (s *session) newRPCBackfillSeries(series BackfillSeries) (*rpc.BackfillSeries, error) {
+	var encodedTags []byte
+	if series.Tags != nil {
+		encoder := s.pools.tagEncoder.Get()
+		defer encoder.Finalize()
+
+		if err := encoder.Encode(series.Tags); err != nil {
+			return nil, err
+		}
+		data, ok := encoder.Data()
+		if !ok {
+			return nil, errUnableToEncodeTags
+		}
+		// Copy the encoded tags since the encoder is returned to the pool.
+		encodedTags = append([]byte(nil), data.Bytes()...)
+	}
+
+	datapoints := make([]*rpc.Datapoint, 0, len(series.Datapoints))
+	for _, dp := range series.Datapoints {
+		timeType, err := convert.ToTimeType(dp.Unit)
+		if err != nil {
+			return nil, err
+		}
+		timestamp, err := convert.ToValue(dp.Timestamp, timeType)
+		if err != nil {
+			return nil, err
+		}
+		datapoints = append(datapoints, &rpc.Datapoint{
+			Timestamp:         timestamp,
+			Value:             dp.Value,
+			Annotation:        dp.Annotation,
+			TimestampTimeType: timeType,
+		})
+	}
+
+	return &rpc.BackfillSeries{
+		ID:          series.ID.Bytes(),
+		EncodedTags: encodedTags,
+		Datapoints:  datapoints,
+	}, nil
+}
+
 // NB(r): Excluding maligned struct check here as we can
 // live with a few extra bytes since this struct is only
 // ever passed by stack, its much more readable not optimized
@@ -0,0 +1,181 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackfillSeries(blockStart time.Time) []BackfillSeries {
+	return []BackfillSeries{
+		{
+			ID:   ident.StringID("foo"),
+			Tags: ident.NewTagsIterator(ident.NewTags(ident.StringTag("name", "foo"))),
+			Datapoints: []BackfillDatapoint{
+				{Timestamp: blockStart, Value: 1, Unit: xtime.Second},
+				{Timestamp: blockStart.Add(time.Minute), Value: 2, Unit: xtime.Second},
+			},
+		},
+		{
+			ID: ident.StringID("bar"),
+			Datapoints: []BackfillDatapoint{
+				{Timestamp: blockStart, Value: 3, Unit: xtime.Millisecond},
+			},
+		},
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+
+	blockStart := time.Now().Add(-24 * time.Hour).Truncate(2 * time.Hour)
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		func(idx int, op op) {
+			backfill, ok := op.(*backfillOp)
+			require.True(t, ok)
+			assert.Equal(t, []byte("metrics"), backfill.request.NameSpace)
+			assert.Equal(t, blockStart.UnixNano(), backfill.request.BlockStart)
+			assert.Equal(t, rpc.TimeType_UNIX_NANOSECONDS, backfill.request.BlockStartTimeType)
+
+			// Every host owns all of the shards in the test topology.
+			require.Len(t, backfill.request.Series, 2)
+			foo, bar := backfill.request.Series[0], backfill.request.Series[1]
+			assert.Equal(t, []byte("foo"), foo.ID)
+			assert.NotEmpty(t, foo.EncodedTags)
+			require.Len(t, foo.Datapoints, 2)
+			assert.Equal(t, blockStart.Add(time.Minute).Unix(), foo.Datapoints[1].Timestamp)
+			assert.Equal(t, rpc.TimeType_UNIX_SECONDS, foo.Datapoints[1].TimestampTimeType)
+
+			assert.Equal(t, []byte("bar"), bar.ID)
+			assert.Empty(t, bar.EncodedTags)
+			require.Len(t, bar.Datapoints, 1)
+			assert.Equal(t, rpc.TimeType_UNIX_MILLISECONDS, bar.Datapoints[0].TimestampTimeType)
+
+			backfill.completionFn(&rpc.BackfillResult_{NumSeries: 2}, nil)
+		},
+	})
+
+	assert.NoError(t, session.Open())
+
+	err = s.Backfill(ident.StringID("metrics"), blockStart, newTestBackfillSeries(blockStart))
+	require.NoError(t, err)
+
+	assert.NoError(t, session.Close())
+}
+
+func TestBackfillBatchesSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions().SetBackfillBatchSize(1)
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+
+	blockStart := time.Now().Add(-24 * time.Hour).Truncate(2 * time.Hour)
+	expectBatch := func(id string) testEnqueueFn {
+		return func(idx int, op op) {
+			backfill, ok := op.(*backfillOp)
+			require.True(t, ok)
+			require.Len(t, backfill.request.Series, 1)
+			assert.Equal(t, []byte(id), backfill.request.Series[0].ID)
+			backfill.completionFn(&rpc.BackfillResult_{NumSeries: 1}, nil)
+		}
+	}
+	mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+		expectBatch("foo"),
+		expectBatch("bar"),
+	})
+
+	assert.NoError(t, session.Open())
+
+	err = s.Backfill(ident.StringID("metrics"), blockStart, newTestBackfillSeries(blockStart))
+	require.NoError(t, err)
+
+	assert.NoError(t, session.Close())
+}
+
+func TestBackfillConsistencyLevel(t *testing.T) {
+	tests := []struct {
+		level         topology.ConsistencyLevel
+		failedHosts   int
+		expectSuccess bool
+	}{
+		{level: topology.ConsistencyLevelMajority, failedHosts: 1, expectSuccess: true},
+		{level: topology.ConsistencyLevelMajority, failedHosts: 2, expectSuccess: false},
+		{level: topology.ConsistencyLevelAll, failedHosts: 1, expectSuccess: false},
+		{level: topology.ConsistencyLevelOne, failedHosts: 2, expectSuccess: true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d", test.level, test.failedHosts), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			opts := newSessionTestOptions().SetWriteConsistencyLevel(test.level)
+			s, err := newSession(opts)
+			assert.NoError(t, err)
+			session := s.(*session)
+
+			blockStart := time.Now().Add(-24 * time.Hour).Truncate(2 * time.Hour)
+			mockHostQueues(ctrl, session, sessionTestReplicas, []testEnqueueFn{
+				func(idx int, op op) {
+					backfill, ok := op.(*backfillOp)
+					require.True(t, ok)
+					if idx < test.failedHosts {
+						backfill.completionFn(nil, errors.New("block not flushed"))
+						return
+					}
+					backfill.completionFn(&rpc.BackfillResult_{NumSeries: 2}, nil)
+				},
+			})
+
+			assert.NoError(t, session.Open())
+
+			err = s.Backfill(ident.StringID("metrics"), blockStart, newTestBackfillSeries(blockStart))
+			if test.expectSuccess {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.True(t, IsConsistencyResultError(err))
+			}
+
+			assert.NoError(t, session.Close())
+		})
+	}
+}
@@ -82,6 +82,11 @@ type Session interface {
 	// Aggregate aggregates values from the database for the given set of constraints.
 	Aggregate(namespace ident.ID, q index.Query, opts index.AggregationOptions) (iter AggregatedTagsIterator, exhaustive bool, err error)
 
+	// DeleteSeries resolves the provided query to known IDs and deletes the
+	// data for them within the given time range, returning the number of
+	// series deleted summed across all hosts.
+	DeleteSeries(namespace ident.ID, q index.Query, startInclusive, endExclusive time.Time) (int64, error)
+
 	// ShardID returns the given shard for an ID for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing.
@@ -187,6 +192,15 @@ type AdminSession interface {
 	// Truncate will truncate the namespace for a given shard.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// Backfill writes the sorted datapoints of the series for the block
+	// starting at blockStart directly into new fileset volumes on every
+	// replica, bypassing their in-memory buffers. It is intended for
+	// importing historical data and requires the block to have been flushed.
+	// The series are sent in batches of a single shard and each batch must
+	// succeed at the write consistency level of the session. Backfilling the
+	// same data again is safe, so failed calls can be retried.
+	Backfill(namespace ident.ID, blockStart time.Time, series []BackfillSeries) error
+
 	// FetchBootstrapBlocksFromPeers will fetch the most fulfilled block
 	// for each series using the runtime configurable bootstrap level consistency.
 	FetchBootstrapBlocksFromPeers(
@@ -226,6 +240,22 @@ type AdminSession interface {
 	) (PeerBlocksIter, error)
 }
 
+// BackfillSeries is a series and its datapoints, sorted by timestamp, to be
+// backfilled into a single block.
+type BackfillSeries struct {
+	ID         ident.ID
+	Tags       ident.TagIterator
+	Datapoints []BackfillDatapoint
+}
+
+// BackfillDatapoint is a single datapoint of a backfilled series.
+type BackfillDatapoint struct {
+	Timestamp  time.Time
+	Value      float64
+	Unit       xtime.Unit
+	Annotation []byte
+}
+
 // Options is a set of client options.
 type Options interface {
 	// Validate validates the options.
@@ -330,6 +360,26 @@ type Options interface {
 	// TruncateRequestTimeout returns the truncateRequestTimeout.
 	TruncateRequestTimeout() time.Duration
 
+	// SetDeleteSeriesRequestTimeout sets the deleteSeriesRequestTimeout.
+	SetDeleteSeriesRequestTimeout(value time.Duration) Options
+
+	// DeleteSeriesRequestTimeout returns the deleteSeriesRequestTimeout.
+	DeleteSeriesRequestTimeout() time.Duration
+
+	// SetBackfillRequestTimeout sets the backfillRequestTimeout.
+	SetBackfillRequestTimeout(value time.Duration) Options
+
+	// BackfillRequestTimeout returns the backfillRequestTimeout.
+	BackfillRequestTimeout() time.Duration
+
+	// SetBackfillBatchSize sets the maximum number of series of a single
+	// shard sent in one backfill request.
+	SetBackfillBatchSize(value int) Options
+
+	// BackfillBatchSize returns the maximum number of series of a single
+	// shard sent in one backfill request.
+	BackfillBatchSize() int
+
 	// SetBackgroundConnectInterval sets the backgroundConnectInterval.
 	SetBackgroundConnectInterval(value time.Duration) Options
 
@@ -52,6 +52,7 @@ type encoder struct {
 	ant ts.Annotation // current annotation
 
 	intVal     float64 // current int val
+	intDelta   float64 // current int delta, only tracked for counter encoding
 	numEncoded uint32  // whether any datapoints have been written yet
 	maxMult    uint8   // current max multiplier for int vals
 
@@ -74,13 +75,28 @@ func NewEncoder(
 	// will be used for this encoder.  If a pool is being used alloc when the
 	// `Reset` method is called.
 	initAllocIfEmpty := opts.EncoderPool() == nil
-	return &encoder{
+	enc := &encoder{
 		os:             encoding.NewOStream(bytes, initAllocIfEmpty, opts.BytesPool()),
 		opts:           opts,
 		tsEncoderState: NewTimestampEncoder(start, opts.DefaultTimeUnit(), opts),
 		closed:         false,
 		intOptimized:   intOptimized,
 	}
+	enc.tsEncoderState.ValueEncodingScheme = enc.valueEncodingScheme()
+	return enc
+}
+
+// valueEncodingScheme returns the scheme used to encode values, only int
+// optimized encoders support schemes other than the default scheme.
+func (enc *encoder) valueEncodingScheme() encoding.ValueEncodingScheme {
+	if !enc.intOptimized {
+		return encoding.DefaultValueEncodingScheme
+	}
+	return enc.opts.ValueEncodingScheme()
+}
+
+func (enc *encoder) isCounterEncoded() bool {
+	return enc.tsEncoderState.ValueEncodingScheme == encoding.CounterValueEncodingScheme
 }
 
 func (enc *encoder) SetSchema(descr namespace.SchemaDescr) {}
@@ -156,6 +172,11 @@ func (enc *encoder) writeNextValue(v float64) error {
 		return err
 	}
 
+	if enc.isCounterEncoded() {
+		enc.writeNextCounterVal(v, val, mult, isFloat)
+		return nil
+	}
+
 	var valDiff float64
 	if !isFloat {
 		valDiff = enc.intVal - val
@@ -170,6 +191,39 @@ func (enc *encoder) writeNextValue(v float64) error {
 	return nil
 }
 
+// writeNextCounterVal writes the val as the delta-of-delta from the previous
+// int vals. Decreasing vals are treated as counter resets and, along with
+// non int vals and deltas that can't be represented exactly, are written
+// as floats.
+func (enc *encoder) writeNextCounterVal(v, val float64, mult uint8, isFloat bool) {
+	if isFloat || (!enc.isFloat && val < enc.intVal) {
+		enc.writeFloatVal(math.Float64bits(v), 0)
+		return
+	}
+
+	predicted := enc.intVal + enc.intDelta
+	valDiff := predicted - val
+	if valDiff >= maxInt || valDiff <= minInt || predicted-valDiff != val {
+		enc.writeFloatVal(math.Float64bits(v), 0)
+		return
+	}
+
+	var (
+		prevIntVal = enc.intVal
+		wasFloat   = enc.isFloat
+	)
+	enc.writeIntVal(val, mult, isFloat, valDiff)
+	enc.intVal = val
+
+	// NB: the delta restarts from zero after converting from float to int
+	// since the previous int val may be stale, the iterator does the same.
+	if wasFloat {
+		enc.intDelta = 0
+	} else {
+		enc.intDelta = val - prevIntVal
+	}
+}
+
 // writeFloatVal writes the value as XOR of the
 // bits that represent the float
 func (enc *encoder) writeFloatVal(val uint64, mult uint8) {
@@ -181,6 +235,7 @@ func (enc *encoder) writeFloatVal(val uint64, mult uint8) {
 		enc.floatEnc.writeFullFloat(enc.os, val)
 		enc.isFloat = true
 		enc.maxMult = mult
+		enc.intDelta = 0
 		return
 	}
 
@@ -265,9 +320,11 @@ func (enc *encoder) reset(start time.Time, bytes checked.Bytes) {
 
 	timeUnit := initialTimeUnit(start, enc.opts.DefaultTimeUnit())
 	enc.tsEncoderState = NewTimestampEncoder(start, timeUnit, enc.opts)
+	enc.tsEncoderState.ValueEncodingScheme = enc.valueEncodingScheme()
 
 	enc.floatEnc = FloatEncoderAndIterator{}
 	enc.intVal = 0
+	enc.intDelta = 0
 	enc.isFloat = false
 	enc.maxMult = 0
 	enc.sigTracker = IntSigBitsTracker{}
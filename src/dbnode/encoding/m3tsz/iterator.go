@@ -38,6 +38,7 @@ type readerIterator struct {
 
 	err        error   // current error
 	intVal     float64 // current int value
+	intDelta   float64 // current int delta, only tracked for counter encoding
 	tsIterator TimestampIterator
 	floatIter  FloatEncoderAndIterator
 
@@ -103,6 +104,7 @@ func (it *readerIterator) readFirstValue() {
 
 	it.readIntSigMult()
 	it.readIntValDiff()
+	it.intDelta = 0
 }
 
 func (it *readerIterator) readNextValue() {
@@ -115,6 +117,10 @@ func (it *readerIterator) readNextValue() {
 
 	if it.readBits(1) == opcodeUpdate {
 		if it.readBits(1) == opcodeRepeat {
+			if it.isCounterEncoded() && !it.isFloat {
+				// Value matches the delta-of-delta prediction
+				it.addCounterIntValDiff(0)
+			}
 			return
 		}
 
@@ -124,11 +130,18 @@ func (it *readerIterator) readNextValue() {
 				it.err = err
 			}
 			it.isFloat = true
+			it.intDelta = 0
 			return
 		}
 
+		wasFloat := it.isFloat
 		it.readIntSigMult()
 		it.readIntValDiff()
+		if wasFloat {
+			// NB: the delta restarts from zero after converting from float
+			// to int to be consistent with the encoder.
+			it.intDelta = 0
+		}
 		it.isFloat = false
 		return
 	}
@@ -165,7 +178,29 @@ func (it *readerIterator) readIntValDiff() {
 		sign = 1.0
 	}
 
-	it.intVal += sign * float64(it.readBits(int(it.sig)))
+	diff := sign * float64(it.readBits(int(it.sig)))
+	if it.isCounterEncoded() {
+		it.addCounterIntValDiff(diff)
+		return
+	}
+
+	it.intVal += diff
+}
+
+// addCounterIntValDiff applies a delta-of-delta encoded diff to the current
+// int value, the arithmetic mirrors that of the encoder so that the decoded
+// values match exactly.
+func (it *readerIterator) addCounterIntValDiff(diff float64) {
+	var (
+		prev      = it.intVal
+		predicted = prev + it.intDelta
+	)
+	it.intVal = predicted + diff
+	it.intDelta = it.intVal - prev
+}
+
+func (it *readerIterator) isCounterEncoded() bool {
+	return it.tsIterator.ValueEncodingScheme == encoding.CounterValueEncodingScheme
 }
 
 func (it *readerIterator) readBits(numBits int) uint64 {
@@ -222,6 +257,7 @@ func (it *readerIterator) Reset(reader io.Reader, schema namespace.SchemaDescr)
 	it.err = nil
 	it.isFloat = false
 	it.intVal = 0.0
+	it.intDelta = 0.0
 	it.mult = 0
 	it.sig = 0
 	it.closed = false
@@ -26,6 +26,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/encoding/testgen"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/context"
@@ -102,16 +103,83 @@ func TestIntOverflow(t *testing.T) {
 	testRoundTrip(t, generateOverflowDatapoints())
 }
 
+func TestMonotonicCountsRoundTrip(t *testing.T) {
+	timeUnit := time.Second
+	numPoints := 1000
+	numIterations := 100
+	for i := 0; i < numIterations; i++ {
+		testRoundTrip(t, generateMonotonicCounterDatapoints(numPoints, timeUnit))
+	}
+}
+
+func TestCounterEncodingSmallerForMonotonicCounts(t *testing.T) {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	var (
+		input      = generateMonotonicCounterDatapoints(1000, time.Second)
+		counterLen = encodedLen(t, ctx, input, newTestCounterEncodingOptions())
+		defaultLen = encodedLen(t, ctx, input, nil)
+	)
+	require.True(t, counterLen < defaultLen,
+		"expected counter encoding len %d to be less than default len %d", counterLen, defaultLen)
+}
+
+func TestCounterEncodingResetFallsBackToFloat(t *testing.T) {
+	ctx := context.NewContext()
+	defer ctx.Close()
+
+	encoder := NewEncoder(testStartTime, nil, true, newTestCounterEncodingOptions()).(*encoder)
+	for i, v := range []float64{10, 20, 30, 5} {
+		dp := ts.Datapoint{Timestamp: testStartTime.Add(time.Duration(i) * time.Second), Value: v}
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		require.Equal(t, i == 3, encoder.isFloat)
+	}
+
+	validateRoundTrip(t, []ts.Datapoint{
+		{Timestamp: testStartTime, Value: 10},
+		{Timestamp: testStartTime.Add(time.Second), Value: 20},
+		{Timestamp: testStartTime.Add(2 * time.Second), Value: 30},
+		{Timestamp: testStartTime.Add(3 * time.Second), Value: 5},
+		{Timestamp: testStartTime.Add(4 * time.Second), Value: 15},
+	}, true, newTestCounterEncodingOptions())
+}
+
+func newTestCounterEncodingOptions() encoding.Options {
+	return encoding.NewOptions().
+		SetValueEncodingScheme(encoding.CounterValueEncodingScheme)
+}
+
+func encodedLen(
+	t *testing.T,
+	ctx context.Context,
+	input []ts.Datapoint,
+	opts encoding.Options,
+) int {
+	encoder := NewEncoder(testStartTime, nil, true, opts)
+	for _, v := range input {
+		require.NoError(t, encoder.Encode(v, xtime.Second, nil))
+	}
+	stream, ok := encoder.Stream(ctx)
+	require.True(t, ok)
+	segment, err := stream.Segment()
+	require.NoError(t, err)
+	return segment.Len()
+}
+
 func testRoundTrip(t *testing.T, input []ts.Datapoint) {
-	validateRoundTrip(t, input, true)
-	validateRoundTrip(t, input, false)
+	validateRoundTrip(t, input, true, nil)
+	validateRoundTrip(t, input, false, nil)
+	validateRoundTrip(t, input, true, newTestCounterEncodingOptions())
 }
 
-func validateRoundTrip(t *testing.T, input []ts.Datapoint, intOpt bool) {
+func validateRoundTrip(t *testing.T, input []ts.Datapoint, intOpt bool, opts encoding.Options) {
 	ctx := context.NewContext()
 	defer ctx.Close()
 
-	encoder := NewEncoder(testStartTime, nil, intOpt, nil)
+	// NB: the decoder always uses the default options as the value encoding
+	// scheme is read from the stream.
+	encoder := NewEncoder(testStartTime, nil, intOpt, opts)
 	for j, v := range input {
 		if j == 0 {
 			encoder.Encode(v, xtime.Millisecond, proto.EncodeVarint(10))
@@ -192,6 +260,35 @@ func generateMixSignIntDatapoints(numPoints int, timeUnit time.Duration) []ts.Da
 	return dps
 }
 
+func generateMonotonicCounterDatapoints(numPoints int, timeUnit time.Duration) []ts.Datapoint {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var startTime int64 = 1427162462
+	currentTime := time.Unix(startTime, 0)
+	endTime := testStartTime.Add(2 * time.Hour)
+	currentValue := 1.0
+	rate := float64(1 + r.Intn(100))
+	res := []ts.Datapoint{{currentTime, currentValue}}
+	for i := 1; i < numPoints; i++ {
+		currentTime = currentTime.Add(10 * time.Second)
+		switch p := r.Float64(); {
+		case p < 0.01:
+			// Counter reset
+			currentValue = 0
+		case p < 0.02:
+			currentValue = testgen.GenerateFloatVal(r, 3, 2)
+		case p < 0.1:
+			currentValue = math.Floor(currentValue) + float64(r.Intn(1000))
+		default:
+			currentValue = math.Floor(currentValue) + rate
+		}
+		if !currentTime.Before(endTime) {
+			break
+		}
+		res = append(res, ts.Datapoint{Timestamp: currentTime, Value: currentValue})
+	}
+	return res
+}
+
 func generateDataPoints(numPoints int, timeUnit time.Duration, numDig, numDec int) []ts.Datapoint {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	var startTime int64 = 1427162462
@@ -42,6 +42,10 @@ type TimestampEncoder struct {
 
 	TimeUnit xtime.Unit
 
+	// ValueEncodingScheme is written along with the first timestamp so that
+	// iterators can tell how the values that follow are encoded.
+	ValueEncodingScheme encoding.ValueEncodingScheme
+
 	// Used to keep track of time unit changes that occur directly via the WriteTimeUnit()
 	// API as opposed to indirectly via the WriteTime() API.
 	timeUnitEncodedManually bool
@@ -80,6 +84,7 @@ func (enc *TimestampEncoder) WriteFirstTime(
 	// if the start time is going to be a multiple of the time unit provided.
 	nt := xtime.ToNormalizedTime(enc.PrevTime, time.Nanosecond)
 	stream.WriteBits(uint64(nt), 64)
+	enc.maybeWriteValueEncodingScheme(stream)
 	return enc.WriteNextTime(stream, currTime, ant, timeUnit)
 }
 
@@ -128,6 +133,18 @@ func (enc *TimestampEncoder) maybeWriteTimeUnitChange(stream encoding.OStream, t
 	return true
 }
 
+// maybeWriteValueEncodingScheme encodes the value encoding scheme if it differs
+// from the default scheme, streams without it are read using the default scheme.
+func (enc *TimestampEncoder) maybeWriteValueEncodingScheme(stream encoding.OStream) {
+	if enc.ValueEncodingScheme == encoding.DefaultValueEncodingScheme {
+		return
+	}
+
+	scheme := enc.Options.MarkerEncodingScheme()
+	encoding.WriteSpecialMarker(stream, scheme, scheme.ValueEncoding())
+	stream.WriteByte(byte(enc.ValueEncodingScheme))
+}
+
 // shouldWriteTimeUnit determines whether we should write tu as a time unit.
 // Returns true if tu is valid and differs from the existing time unit, false otherwise.
 func (enc *TimestampEncoder) shouldWriteTimeUnit(timeUnit xtime.Unit) bool {
@@ -39,6 +39,10 @@ type TimestampIterator struct {
 
 	TimeUnit xtime.Unit
 
+	// ValueEncodingScheme is the scheme the values of the stream were
+	// encoded with, as read from the stream.
+	ValueEncodingScheme encoding.ValueEncodingScheme
+
 	Opts encoding.Options
 
 	TimeUnitChanged bool
@@ -190,11 +194,40 @@ func (it *TimestampIterator) tryReadMarker(stream encoding.IStream) (time.Durati
 			return 0, false, err
 		}
 		return markerOrDOD, true, nil
+	case mes.ValueEncoding():
+		_, err := stream.ReadBits(numBits)
+		if err != nil {
+			return 0, false, err
+		}
+		err = it.readValueEncodingScheme(stream)
+		if err != nil {
+			return 0, false, err
+		}
+		markerOrDOD, err := it.readMarkerOrDeltaOfDelta(stream)
+		if err != nil {
+			return 0, false, err
+		}
+		return markerOrDOD, true, nil
 	default:
 		return 0, false, nil
 	}
 }
 
+func (it *TimestampIterator) readValueEncodingScheme(stream encoding.IStream) error {
+	schemeBits, err := stream.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	scheme := encoding.ValueEncodingScheme(schemeBits)
+	if err := scheme.Validate(); err != nil {
+		return err
+	}
+	it.ValueEncodingScheme = scheme
+
+	return nil
+}
+
 func (it *TimestampIterator) readMarkerOrDeltaOfDelta(stream encoding.IStream) (time.Duration, error) {
 	if !it.SkipMarkers {
 		dod, success, err := it.tryReadMarker(stream)
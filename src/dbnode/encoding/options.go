@@ -43,6 +43,7 @@ type options struct {
 	defaultTimeUnit         xtime.Unit
 	timeEncodingSchemes     TimeEncodingSchemes
 	markerEncodingScheme    MarkerEncodingScheme
+	valueEncodingScheme     ValueEncodingScheme
 	encoderPool             EncoderPool
 	readerIteratorPool      ReaderIteratorPool
 	bytesPool               pool.CheckedBytesPool
@@ -58,6 +59,7 @@ func newOptions() Options {
 		defaultTimeUnit:        defaultDefaultTimeUnit,
 		timeEncodingSchemes:    defaultTimeEncodingSchemes,
 		markerEncodingScheme:   defaultMarkerEncodingScheme,
+		valueEncodingScheme:    DefaultValueEncodingScheme,
 		byteFieldDictLRUSize:   defaultByteFieldDictLRUSize,
 		iStreamReaderSizeM3TSZ: defaultIStreamReaderSizeM3TSZ,
 		iStreamReaderSizeProto: defaultIStreamReaderSizeProto,
@@ -99,6 +101,16 @@ func (o *options) MarkerEncodingScheme() MarkerEncodingScheme {
 	return o.markerEncodingScheme
 }
 
+func (o *options) SetValueEncodingScheme(value ValueEncodingScheme) Options {
+	opts := *o
+	opts.valueEncodingScheme = value
+	return &opts
+}
+
+func (o *options) ValueEncodingScheme() ValueEncodingScheme {
+	return o.valueEncodingScheme
+}
+
 func (o *options) SetEncoderPool(value EncoderPool) Options {
 	opts := *o
 	opts.encoderPool = value
@@ -21,6 +21,8 @@
 package encoding
 
 import (
+	"fmt"
+
 	"github.com/m3db/m3/src/x/checked"
 	xtime "github.com/m3db/m3/src/x/time"
 )
@@ -30,6 +32,7 @@ const (
 	defaultEndOfStreamMarker Marker = iota
 	defaultAnnotationMarker
 	defaultTimeUnitMarker
+	defaultValueEncodingMarker
 
 	// marker encoding information
 	defaultMarkerOpcode        = 0x100
@@ -59,6 +62,7 @@ var (
 		defaultEndOfStreamMarker,
 		defaultAnnotationMarker,
 		defaultTimeUnitMarker,
+		defaultValueEncodingMarker,
 	)
 )
 
@@ -179,6 +183,9 @@ type MarkerEncodingScheme interface {
 	// TimeUnit returns the time unit marker.
 	TimeUnit() Marker
 
+	// ValueEncoding returns the value encoding scheme marker.
+	ValueEncoding() Marker
+
 	// Tail will return the tail portion of a stream including the relevant bits
 	// in the last byte along with the end of stream marker.
 	Tail(streamLastByte byte, streamCurrentPosition int) checked.Bytes
@@ -191,6 +198,7 @@ type markerEncodingScheme struct {
 	endOfStream   Marker
 	annotation    Marker
 	timeUnit      Marker
+	valueEncoding Marker
 	tails         [256][8]checked.Bytes
 }
 
@@ -201,6 +209,7 @@ func newMarkerEncodingScheme(
 	endOfStream Marker,
 	annotation Marker,
 	timeUnit Marker,
+	valueEncoding Marker,
 ) MarkerEncodingScheme {
 	scheme := &markerEncodingScheme{
 		opcode:        opcode,
@@ -209,6 +218,7 @@ func newMarkerEncodingScheme(
 		endOfStream:   endOfStream,
 		annotation:    annotation,
 		timeUnit:      timeUnit,
+		valueEncoding: valueEncoding,
 	}
 	// NB(r): we precompute all possible tail streams dependent on last byte
 	// so we never have to pool or allocate tails for each stream when we
@@ -228,7 +238,8 @@ func newMarkerEncodingScheme(
 }
 
 // WriteSpecialMarker writes the marker that marks the start of a special symbol,
-// e.g., the eos marker, the annotation marker, or the time unit marker.
+// e.g., the eos marker, the annotation marker, the time unit marker, or the
+// value encoding scheme marker.
 func WriteSpecialMarker(os OStream, scheme MarkerEncodingScheme, marker Marker) {
 	os.WriteBits(scheme.Opcode(), scheme.NumOpcodeBits())
 	os.WriteBits(uint64(marker), scheme.NumValueBits())
@@ -240,4 +251,69 @@ func (mes *markerEncodingScheme) NumValueBits() int                  { return me
 func (mes *markerEncodingScheme) EndOfStream() Marker                { return mes.endOfStream }
 func (mes *markerEncodingScheme) Annotation() Marker                 { return mes.annotation }
 func (mes *markerEncodingScheme) TimeUnit() Marker                   { return mes.timeUnit }
+func (mes *markerEncodingScheme) ValueEncoding() Marker              { return mes.valueEncoding }
 func (mes *markerEncodingScheme) Tail(b byte, pos int) checked.Bytes { return mes.tails[int(b)][pos-1] }
+
+// ValueEncodingScheme is the scheme used to encode the values of a stream.
+type ValueEncodingScheme byte
+
+const (
+	// DefaultValueEncodingScheme encodes floats as XORs of the previous value
+	// and, when int optimized, ints as differences from the previous value.
+	DefaultValueEncodingScheme ValueEncodingScheme = iota
+
+	// CounterValueEncodingScheme encodes ints as the delta-of-delta from the
+	// previous values, which compresses steadily increasing counters down to
+	// a couple of bits per value. Decreasing values are treated as counter
+	// resets and written using the float encoding.
+	CounterValueEncodingScheme
+)
+
+var validValueEncodingSchemes = []ValueEncodingScheme{
+	DefaultValueEncodingScheme,
+	CounterValueEncodingScheme,
+}
+
+// Validate validates that the value encoding scheme is valid.
+func (s ValueEncodingScheme) Validate() error {
+	if s >= DefaultValueEncodingScheme && s <= CounterValueEncodingScheme {
+		return nil
+	}
+
+	return fmt.Errorf("invalid value encoding scheme: '%v' valid schemes are: %v",
+		s, validValueEncodingSchemes)
+}
+
+func (s ValueEncodingScheme) String() string {
+	switch s {
+	case DefaultValueEncodingScheme:
+		return "default"
+	case CounterValueEncodingScheme:
+		return "counter"
+	default:
+		return fmt.Sprintf("unknown: %d", s)
+	}
+}
+
+// UnmarshalYAML unmarshals a stored value encoding scheme.
+func (s *ValueEncodingScheme) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		*s = DefaultValueEncodingScheme
+		return nil
+	}
+
+	for _, valid := range validValueEncodingSchemes {
+		if str == valid.String() {
+			*s = valid
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid value encoding scheme: '%s' valid schemes are: %v",
+		str, validValueEncodingSchemes)
+}
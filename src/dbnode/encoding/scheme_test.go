@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestValueEncodingSchemeValidate(t *testing.T) {
+	for _, valid := range validValueEncodingSchemes {
+		require.NoError(t, valid.Validate())
+	}
+	require.Error(t, ValueEncodingScheme(255).Validate())
+}
+
+func TestValueEncodingSchemeUnmarshalYAML(t *testing.T) {
+	for _, valid := range validValueEncodingSchemes {
+		var scheme ValueEncodingScheme
+		require.NoError(t, yaml.Unmarshal([]byte(valid.String()), &scheme))
+		require.Equal(t, valid, scheme)
+	}
+
+	var scheme ValueEncodingScheme
+	require.Error(t, yaml.Unmarshal([]byte("gorilla"), &scheme))
+}
+
+func TestDefaultMarkerEncodingSchemeMarkersUnique(t *testing.T) {
+	scheme := NewOptions().MarkerEncodingScheme()
+	markers := []Marker{
+		scheme.EndOfStream(),
+		scheme.Annotation(),
+		scheme.TimeUnit(),
+		scheme.ValueEncoding(),
+	}
+	seen := make(map[Marker]struct{}, len(markers))
+	for _, marker := range markers {
+		require.True(t, uint64(marker) < 1<<uint(scheme.NumValueBits()))
+		seen[marker] = struct{}{}
+	}
+	require.Equal(t, len(markers), len(seen))
+}
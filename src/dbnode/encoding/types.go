@@ -106,6 +106,14 @@ type Options interface {
 	// MarkerEncodingScheme returns the marker encoding scheme.
 	MarkerEncodingScheme() MarkerEncodingScheme
 
+	// SetValueEncodingScheme sets the value encoding scheme used by int
+	// optimized encoders, readers detect the scheme from the stream itself.
+	SetValueEncodingScheme(value ValueEncodingScheme) Options
+
+	// ValueEncodingScheme returns the value encoding scheme used by int
+	// optimized encoders.
+	ValueEncodingScheme() ValueEncodingScheme
+
 	// SetEncoderPool sets the encoder pool.
 	SetEncoderPool(value EncoderPool) Options
 
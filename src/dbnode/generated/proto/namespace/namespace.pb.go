@@ -29,8 +29,11 @@
 		github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto
 
 	It has these top-level messages:
+		SeriesRetentionRule
 		RetentionOptions
 		IndexOptions
+		TieringOptions
+		DownsampleOptions
 		NamespaceOptions
 		Registry
 		SchemaOptions
@@ -56,20 +59,66 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+type CompressionType int32
+
+const (
+	CompressionType_NONE   CompressionType = 0
+	CompressionType_SNAPPY CompressionType = 1
+)
+
+var CompressionType_name = map[int32]string{
+	0: "NONE",
+	1: "SNAPPY",
+}
+var CompressionType_value = map[string]int32{
+	"NONE":   0,
+	"SNAPPY": 1,
+}
+
+func (x CompressionType) String() string {
+	return proto.EnumName(CompressionType_name, int32(x))
+}
+func (CompressionType) EnumDescriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{0} }
+
+type SeriesRetentionRule struct {
+	Filter      string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	PeriodNanos int64  `protobuf:"varint,2,opt,name=periodNanos,proto3" json:"periodNanos,omitempty"`
+}
+
+func (m *SeriesRetentionRule) Reset()                    { *m = SeriesRetentionRule{} }
+func (m *SeriesRetentionRule) String() string            { return proto.CompactTextString(m) }
+func (*SeriesRetentionRule) ProtoMessage()               {}
+func (*SeriesRetentionRule) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{0} }
+
+func (m *SeriesRetentionRule) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *SeriesRetentionRule) GetPeriodNanos() int64 {
+	if m != nil {
+		return m.PeriodNanos
+	}
+	return 0
+}
+
 type RetentionOptions struct {
-	RetentionPeriodNanos                     int64 `protobuf:"varint,1,opt,name=retentionPeriodNanos,proto3" json:"retentionPeriodNanos,omitempty"`
-	BlockSizeNanos                           int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
-	BufferFutureNanos                        int64 `protobuf:"varint,3,opt,name=bufferFutureNanos,proto3" json:"bufferFutureNanos,omitempty"`
-	BufferPastNanos                          int64 `protobuf:"varint,4,opt,name=bufferPastNanos,proto3" json:"bufferPastNanos,omitempty"`
-	BlockDataExpiry                          bool  `protobuf:"varint,5,opt,name=blockDataExpiry,proto3" json:"blockDataExpiry,omitempty"`
-	BlockDataExpiryAfterNotAccessPeriodNanos int64 `protobuf:"varint,6,opt,name=blockDataExpiryAfterNotAccessPeriodNanos,proto3" json:"blockDataExpiryAfterNotAccessPeriodNanos,omitempty"`
-	FutureRetentionPeriodNanos               int64 `protobuf:"varint,7,opt,name=futureRetentionPeriodNanos,proto3" json:"futureRetentionPeriodNanos,omitempty"`
+	RetentionPeriodNanos                     int64                  `protobuf:"varint,1,opt,name=retentionPeriodNanos,proto3" json:"retentionPeriodNanos,omitempty"`
+	BlockSizeNanos                           int64                  `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	BufferFutureNanos                        int64                  `protobuf:"varint,3,opt,name=bufferFutureNanos,proto3" json:"bufferFutureNanos,omitempty"`
+	BufferPastNanos                          int64                  `protobuf:"varint,4,opt,name=bufferPastNanos,proto3" json:"bufferPastNanos,omitempty"`
+	BlockDataExpiry                          bool                   `protobuf:"varint,5,opt,name=blockDataExpiry,proto3" json:"blockDataExpiry,omitempty"`
+	BlockDataExpiryAfterNotAccessPeriodNanos int64                  `protobuf:"varint,6,opt,name=blockDataExpiryAfterNotAccessPeriodNanos,proto3" json:"blockDataExpiryAfterNotAccessPeriodNanos,omitempty"`
+	FutureRetentionPeriodNanos               int64                  `protobuf:"varint,7,opt,name=futureRetentionPeriodNanos,proto3" json:"futureRetentionPeriodNanos,omitempty"`
+	SeriesRetentionRules                     []*SeriesRetentionRule `protobuf:"bytes,8,rep,name=seriesRetentionRules" json:"seriesRetentionRules,omitempty"`
 }
 
 func (m *RetentionOptions) Reset()                    { *m = RetentionOptions{} }
 func (m *RetentionOptions) String() string            { return proto.CompactTextString(m) }
 func (*RetentionOptions) ProtoMessage()               {}
-func (*RetentionOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{0} }
+func (*RetentionOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{1} }
 
 func (m *RetentionOptions) GetRetentionPeriodNanos() int64 {
 	if m != nil {
@@ -120,6 +169,13 @@ func (m *RetentionOptions) GetFutureRetentionPeriodNanos() int64 {
 	return 0
 }
 
+func (m *RetentionOptions) GetSeriesRetentionRules() []*SeriesRetentionRule {
+	if m != nil {
+		return m.SeriesRetentionRules
+	}
+	return nil
+}
+
 type IndexOptions struct {
 	Enabled        bool  `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
 	BlockSizeNanos int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
@@ -128,7 +184,7 @@ type IndexOptions struct {
 func (m *IndexOptions) Reset()                    { *m = IndexOptions{} }
 func (m *IndexOptions) String() string            { return proto.CompactTextString(m) }
 func (*IndexOptions) ProtoMessage()               {}
-func (*IndexOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{1} }
+func (*IndexOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{2} }
 
 func (m *IndexOptions) GetEnabled() bool {
 	if m != nil {
@@ -144,23 +200,107 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 	return 0
 }
 
+type TieringOptions struct {
+	Enabled        bool   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ColdAfterNanos int64  `protobuf:"varint,2,opt,name=coldAfterNanos,proto3" json:"coldAfterNanos,omitempty"`
+	FilePathPrefix string `protobuf:"bytes,3,opt,name=filePathPrefix,proto3" json:"filePathPrefix,omitempty"`
+}
+
+func (m *TieringOptions) Reset()                    { *m = TieringOptions{} }
+func (m *TieringOptions) String() string            { return proto.CompactTextString(m) }
+func (*TieringOptions) ProtoMessage()               {}
+func (*TieringOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{3} }
+
+func (m *TieringOptions) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *TieringOptions) GetColdAfterNanos() int64 {
+	if m != nil {
+		return m.ColdAfterNanos
+	}
+	return 0
+}
+
+func (m *TieringOptions) GetFilePathPrefix() string {
+	if m != nil {
+		return m.FilePathPrefix
+	}
+	return ""
+}
+
+type DownsampleOptions struct {
+	Enabled          bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	AfterNanos       int64    `protobuf:"varint,2,opt,name=afterNanos,proto3" json:"afterNanos,omitempty"`
+	ResolutionNanos  int64    `protobuf:"varint,3,opt,name=resolutionNanos,proto3" json:"resolutionNanos,omitempty"`
+	TargetNamespace  string   `protobuf:"bytes,4,opt,name=targetNamespace,proto3" json:"targetNamespace,omitempty"`
+	AggregationTypes []string `protobuf:"bytes,5,rep,name=aggregationTypes" json:"aggregationTypes,omitempty"`
+}
+
+func (m *DownsampleOptions) Reset()                    { *m = DownsampleOptions{} }
+func (m *DownsampleOptions) String() string            { return proto.CompactTextString(m) }
+func (*DownsampleOptions) ProtoMessage()               {}
+func (*DownsampleOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{4} }
+
+func (m *DownsampleOptions) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *DownsampleOptions) GetAfterNanos() int64 {
+	if m != nil {
+		return m.AfterNanos
+	}
+	return 0
+}
+
+func (m *DownsampleOptions) GetResolutionNanos() int64 {
+	if m != nil {
+		return m.ResolutionNanos
+	}
+	return 0
+}
+
+func (m *DownsampleOptions) GetTargetNamespace() string {
+	if m != nil {
+		return m.TargetNamespace
+	}
+	return ""
+}
+
+func (m *DownsampleOptions) GetAggregationTypes() []string {
+	if m != nil {
+		return m.AggregationTypes
+	}
+	return nil
+}
+
 type NamespaceOptions struct {
-	BootstrapEnabled  bool              `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
-	FlushEnabled      bool              `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
-	WritesToCommitLog bool              `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
-	CleanupEnabled    bool              `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
-	RepairEnabled     bool              `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
-	RetentionOptions  *RetentionOptions `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
-	SnapshotEnabled   bool              `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
-	IndexOptions      *IndexOptions     `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
-	SchemaOptions     *SchemaOptions    `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
-	ColdWritesEnabled bool              `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	BootstrapEnabled       bool               `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
+	FlushEnabled           bool               `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
+	WritesToCommitLog      bool               `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
+	CleanupEnabled         bool               `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
+	RepairEnabled          bool               `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
+	RetentionOptions       *RetentionOptions  `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
+	SnapshotEnabled        bool               `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
+	IndexOptions           *IndexOptions      `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	SchemaOptions          *SchemaOptions     `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
+	ColdWritesEnabled      bool               `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	TieringOptions         *TieringOptions    `protobuf:"bytes,11,opt,name=tieringOptions" json:"tieringOptions,omitempty"`
+	DataCompression        CompressionType    `protobuf:"varint,12,opt,name=dataCompression,proto3,enum=namespace.CompressionType" json:"dataCompression,omitempty"`
+	DownsampleOptions      *DownsampleOptions `protobuf:"bytes,13,opt,name=downsampleOptions" json:"downsampleOptions,omitempty"`
+	CounterEncodingEnabled bool               `protobuf:"varint,14,opt,name=counterEncodingEnabled,proto3" json:"counterEncodingEnabled,omitempty"`
 }
 
 func (m *NamespaceOptions) Reset()                    { *m = NamespaceOptions{} }
 func (m *NamespaceOptions) String() string            { return proto.CompactTextString(m) }
 func (*NamespaceOptions) ProtoMessage()               {}
-func (*NamespaceOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{2} }
+func (*NamespaceOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{5} }
 
 func (m *NamespaceOptions) GetBootstrapEnabled() bool {
 	if m != nil {
@@ -232,6 +372,34 @@ func (m *NamespaceOptions) GetColdWritesEnabled() bool {
 	return false
 }
 
+func (m *NamespaceOptions) GetTieringOptions() *TieringOptions {
+	if m != nil {
+		return m.TieringOptions
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetDataCompression() CompressionType {
+	if m != nil {
+		return m.DataCompression
+	}
+	return CompressionType_NONE
+}
+
+func (m *NamespaceOptions) GetDownsampleOptions() *DownsampleOptions {
+	if m != nil {
+		return m.DownsampleOptions
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetCounterEncodingEnabled() bool {
+	if m != nil {
+		return m.CounterEncodingEnabled
+	}
+	return false
+}
+
 type Registry struct {
 	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
 }
@@ -239,7 +407,7 @@ type Registry struct {
 func (m *Registry) Reset()                    { *m = Registry{} }
 func (m *Registry) String() string            { return proto.CompactTextString(m) }
 func (*Registry) ProtoMessage()               {}
-func (*Registry) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{3} }
+func (*Registry) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{6} }
 
 func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 	if m != nil {
@@ -249,11 +417,44 @@ func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 }
 
 func init() {
+	proto.RegisterType((*SeriesRetentionRule)(nil), "namespace.SeriesRetentionRule")
 	proto.RegisterType((*RetentionOptions)(nil), "namespace.RetentionOptions")
 	proto.RegisterType((*IndexOptions)(nil), "namespace.IndexOptions")
+	proto.RegisterType((*TieringOptions)(nil), "namespace.TieringOptions")
+	proto.RegisterType((*DownsampleOptions)(nil), "namespace.DownsampleOptions")
 	proto.RegisterType((*NamespaceOptions)(nil), "namespace.NamespaceOptions")
 	proto.RegisterType((*Registry)(nil), "namespace.Registry")
+	proto.RegisterEnum("namespace.CompressionType", CompressionType_name, CompressionType_value)
+}
+func (m *SeriesRetentionRule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SeriesRetentionRule) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Filter) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Filter)))
+		i += copy(dAtA[i:], m.Filter)
+	}
+	if m.PeriodNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.PeriodNanos))
+	}
+	return i, nil
 }
+
 func (m *RetentionOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -309,6 +510,18 @@ func (m *RetentionOptions) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.FutureRetentionPeriodNanos))
 	}
+	if len(m.SeriesRetentionRules) > 0 {
+		for _, msg := range m.SeriesRetentionRules {
+			dAtA[i] = 0x42
+			i++
+			i = encodeVarintNamespace(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -345,6 +558,104 @@ func (m *IndexOptions) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *TieringOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TieringOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Enabled {
+		dAtA[i] = 0x8
+		i++
+		if m.Enabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ColdAfterNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ColdAfterNanos))
+	}
+	if len(m.FilePathPrefix) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.FilePathPrefix)))
+		i += copy(dAtA[i:], m.FilePathPrefix)
+	}
+	return i, nil
+}
+
+func (m *DownsampleOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DownsampleOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Enabled {
+		dAtA[i] = 0x8
+		i++
+		if m.Enabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.AfterNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.AfterNanos))
+	}
+	if m.ResolutionNanos != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ResolutionNanos))
+	}
+	if len(m.TargetNamespace) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.TargetNamespace)))
+		i += copy(dAtA[i:], m.TargetNamespace)
+	}
+	if len(m.AggregationTypes) > 0 {
+		for _, s := range m.AggregationTypes {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
 func (m *NamespaceOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -460,6 +771,41 @@ func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if m.TieringOptions != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.TieringOptions.Size()))
+		n4, err := m.TieringOptions.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.DataCompression != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.DataCompression))
+	}
+	if m.DownsampleOptions != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.DownsampleOptions.Size()))
+		n5, err := m.DownsampleOptions.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.CounterEncodingEnabled {
+		dAtA[i] = 0x70
+		i++
+		if m.CounterEncodingEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -498,11 +844,11 @@ func (m *Registry) MarshalTo(dAtA []byte) (int, error) {
 				dAtA[i] = 0x12
 				i++
 				i = encodeVarintNamespace(dAtA, i, uint64(v.Size()))
-				n4, err := v.MarshalTo(dAtA[i:])
+				n6, err := v.MarshalTo(dAtA[i:])
 				if err != nil {
 					return 0, err
 				}
-				i += n4
+				i += n6
 			}
 		}
 	}
@@ -518,6 +864,19 @@ func encodeVarintNamespace(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	return offset + 1
 }
+func (m *SeriesRetentionRule) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Filter)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.PeriodNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.PeriodNanos))
+	}
+	return n
+}
+
 func (m *RetentionOptions) Size() (n int) {
 	var l int
 	_ = l
@@ -542,6 +901,12 @@ func (m *RetentionOptions) Size() (n int) {
 	if m.FutureRetentionPeriodNanos != 0 {
 		n += 1 + sovNamespace(uint64(m.FutureRetentionPeriodNanos))
 	}
+	if len(m.SeriesRetentionRules) > 0 {
+		for _, e := range m.SeriesRetentionRules {
+			l = e.Size()
+			n += 1 + l + sovNamespace(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -557,6 +922,47 @@ func (m *IndexOptions) Size() (n int) {
 	return n
 }
 
+func (m *TieringOptions) Size() (n int) {
+	var l int
+	_ = l
+	if m.Enabled {
+		n += 2
+	}
+	if m.ColdAfterNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.ColdAfterNanos))
+	}
+	l = len(m.FilePathPrefix)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	return n
+}
+
+func (m *DownsampleOptions) Size() (n int) {
+	var l int
+	_ = l
+	if m.Enabled {
+		n += 2
+	}
+	if m.AfterNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.AfterNanos))
+	}
+	if m.ResolutionNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.ResolutionNanos))
+	}
+	l = len(m.TargetNamespace)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if len(m.AggregationTypes) > 0 {
+		for _, s := range m.AggregationTypes {
+			l = len(s)
+			n += 1 + l + sovNamespace(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *NamespaceOptions) Size() (n int) {
 	var l int
 	_ = l
@@ -593,6 +999,20 @@ func (m *NamespaceOptions) Size() (n int) {
 	if m.ColdWritesEnabled {
 		n += 2
 	}
+	if m.TieringOptions != nil {
+		l = m.TieringOptions.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.DataCompression != 0 {
+		n += 1 + sovNamespace(uint64(m.DataCompression))
+	}
+	if m.DownsampleOptions != nil {
+		l = m.DownsampleOptions.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.CounterEncodingEnabled {
+		n += 2
+	}
 	return n
 }
 
@@ -628,7 +1048,7 @@ func sovNamespace(x uint64) (n int) {
 func sozNamespace(x uint64) (n int) {
 	return sovNamespace(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
-func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
+func (m *SeriesRetentionRule) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -651,17 +1071,17 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RetentionOptions: wiretype end group for non-group")
+			return fmt.Errorf("proto: SeriesRetentionRule: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RetentionOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SeriesRetentionRule: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RetentionPeriodNanos", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filter", wireType)
 			}
-			m.RetentionPeriodNanos = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -671,7 +1091,105 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RetentionPeriodNanos |= (int64(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeriodNanos", wireType)
+			}
+			m.PeriodNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeriodNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetentionOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetentionOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionPeriodNanos", wireType)
+			}
+			m.RetentionPeriodNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RetentionPeriodNanos |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -791,6 +1309,37 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeriesRetentionRules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SeriesRetentionRules = append(m.SeriesRetentionRules, &SeriesRetentionRule{})
+			if err := m.SeriesRetentionRules[len(m.SeriesRetentionRules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -901,6 +1450,290 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *TieringOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TieringOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TieringOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Enabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Enabled = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ColdAfterNanos", wireType)
+			}
+			m.ColdAfterNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ColdAfterNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FilePathPrefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FilePathPrefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DownsampleOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DownsampleOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DownsampleOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Enabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Enabled = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterNanos", wireType)
+			}
+			m.AfterNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AfterNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResolutionNanos", wireType)
+			}
+			m.ResolutionNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResolutionNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TargetNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregationTypes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AggregationTypes = append(m.AggregationTypes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1169,6 +2002,111 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.ColdWritesEnabled = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TieringOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TieringOptions == nil {
+				m.TieringOptions = &TieringOptions{}
+			}
+			if err := m.TieringOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataCompression", wireType)
+			}
+			m.DataCompression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DataCompression |= (CompressionType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownsampleOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DownsampleOptions == nil {
+				m.DownsampleOptions = &DownsampleOptions{}
+			}
+			if err := m.DownsampleOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CounterEncodingEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CounterEncodingEnabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -1473,41 +2411,58 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 575 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0x51, 0x6b, 0xd3, 0x50,
-	0x14, 0xc7, 0x4d, 0xb3, 0xad, 0xed, 0x59, 0xe7, 0xe2, 0x45, 0xb0, 0x54, 0x28, 0xa3, 0x8a, 0x14,
-	0x91, 0x06, 0xdb, 0x17, 0x51, 0x18, 0xcc, 0xad, 0x0e, 0x41, 0x6a, 0xb9, 0x13, 0x84, 0xbd, 0xdd,
-	0x24, 0xa7, 0x6d, 0x58, 0x92, 0x1b, 0xee, 0xbd, 0xd1, 0xd5, 0xcf, 0xe0, 0x83, 0xdf, 0xc3, 0x2f,
-	0xe2, 0xa3, 0x1f, 0x41, 0xea, 0xd7, 0xf0, 0x41, 0x72, 0x63, 0xba, 0x24, 0x1d, 0x32, 0xf6, 0x52,
-	0x6e, 0xff, 0xe7, 0x77, 0xce, 0xb9, 0x3d, 0xff, 0x73, 0x0b, 0xa7, 0x73, 0x5f, 0x2d, 0x12, 0x67,
-	0xe0, 0xf2, 0xd0, 0x0e, 0x47, 0x9e, 0x63, 0x87, 0x23, 0x5b, 0x0a, 0xd7, 0xf6, 0x9c, 0x88, 0x7b,
-	0x68, 0xcf, 0x31, 0x42, 0xc1, 0x14, 0x7a, 0x76, 0x2c, 0xb8, 0xe2, 0x76, 0xc4, 0x42, 0x94, 0x31,
-	0x73, 0xf1, 0xea, 0x34, 0xd0, 0x11, 0xd2, 0x5c, 0x0b, 0x9d, 0x93, 0xdb, 0xd6, 0x94, 0xee, 0x02,
-	0x43, 0x96, 0x15, 0xec, 0x7d, 0x35, 0xc1, 0xa2, 0xa8, 0x30, 0x52, 0x3e, 0x8f, 0xde, 0xc7, 0xe9,
-	0xa7, 0x24, 0x43, 0xb8, 0x2f, 0x72, 0x6d, 0x8a, 0xc2, 0xe7, 0xde, 0x84, 0x45, 0x5c, 0xb6, 0x8d,
-	0x03, 0xa3, 0x6f, 0xd2, 0x6b, 0x63, 0xe4, 0x09, 0xdc, 0x75, 0x02, 0xee, 0x5e, 0x9c, 0xf9, 0x5f,
-	0x30, 0xa3, 0x6b, 0x9a, 0xae, 0xa8, 0xe4, 0x19, 0xdc, 0x73, 0x92, 0xd9, 0x0c, 0xc5, 0x9b, 0x44,
-	0x25, 0xe2, 0x1f, 0x6a, 0x6a, 0x74, 0x33, 0x40, 0xfa, 0xb0, 0x9f, 0x89, 0x53, 0x26, 0x55, 0xc6,
-	0x6e, 0x69, 0xb6, 0x2a, 0x6b, 0x32, 0xed, 0x74, 0xc2, 0x14, 0x1b, 0x5f, 0xc6, 0xbe, 0x58, 0xb6,
-	0xb7, 0x0f, 0x8c, 0x7e, 0x83, 0x56, 0x65, 0x72, 0x0e, 0xfd, 0x8a, 0x74, 0x34, 0x53, 0x28, 0x26,
-	0x5c, 0x1d, 0xb9, 0x2e, 0x4a, 0x59, 0xfc, 0xc5, 0x3b, 0xba, 0xd9, 0x8d, 0x79, 0x72, 0x08, 0x9d,
-	0x99, 0xbe, 0x3e, 0xbd, 0x6e, 0x7e, 0x75, 0x5d, 0xed, 0x3f, 0x44, 0x6f, 0x0a, 0xad, 0xb7, 0x91,
-	0x87, 0x97, 0xb9, 0x13, 0x6d, 0xa8, 0x63, 0xc4, 0x9c, 0x00, 0x3d, 0x3d, 0xfc, 0x06, 0xcd, 0xbf,
-	0xde, 0x74, 0xde, 0xbd, 0x3f, 0x26, 0x58, 0x93, 0xdc, 0xfb, 0xbc, 0xec, 0x53, 0xb0, 0x1c, 0xce,
-	0x95, 0x54, 0x82, 0xc5, 0xe3, 0x52, 0xfd, 0x0d, 0x9d, 0xf4, 0xa0, 0x35, 0x0b, 0x12, 0xb9, 0xc8,
-	0xb9, 0x9a, 0xe6, 0x4a, 0x5a, 0x6a, 0xea, 0x67, 0xe1, 0x2b, 0x94, 0x1f, 0xf8, 0x31, 0x0f, 0x43,
-	0x5f, 0xbd, 0xe3, 0x73, 0x6d, 0x6a, 0x83, 0x6e, 0x06, 0xd2, 0xab, 0xbb, 0x01, 0xb2, 0x28, 0x59,
-	0xf7, 0xde, 0xd2, 0x68, 0x45, 0x25, 0x8f, 0x61, 0x4f, 0x60, 0xcc, 0x7c, 0x91, 0x63, 0x99, 0xa1,
-	0x65, 0x91, 0x9c, 0x82, 0x25, 0x2a, 0x0b, 0xac, 0x6d, 0xdb, 0x1d, 0x3e, 0x1c, 0x5c, 0x3d, 0x9f,
-	0xea, 0x8e, 0xd3, 0x8d, 0xa4, 0x74, 0x83, 0x64, 0xc4, 0x62, 0xb9, 0xe0, 0x2a, 0x6f, 0x58, 0xcf,
-	0x36, 0xa8, 0x22, 0x93, 0x57, 0xd0, 0xf2, 0x0b, 0x2e, 0xb5, 0x1b, 0xba, 0xdd, 0x83, 0x42, 0xbb,
-	0xa2, 0x89, 0xb4, 0x04, 0x93, 0x43, 0xd8, 0xcb, 0x5e, 0x60, 0x9e, 0xdd, 0xd4, 0xd9, 0xed, 0x42,
-	0xf6, 0x59, 0x31, 0x4e, 0xcb, 0x78, 0x3a, 0x6b, 0x97, 0x07, 0xde, 0x47, 0x3d, 0xd6, 0xfc, 0xa2,
-	0x90, 0xcd, 0x7a, 0x23, 0xd0, 0xfb, 0x6e, 0x40, 0x83, 0xe2, 0xdc, 0x97, 0x4a, 0x2c, 0xc9, 0x31,
-	0xc0, 0xba, 0x49, 0xfa, 0x9a, 0xcd, 0xfe, 0xee, 0xf0, 0x51, 0x69, 0x48, 0x19, 0x38, 0x58, 0x2f,
-	0x8c, 0x1c, 0x47, 0x4a, 0x2c, 0x69, 0x21, 0xad, 0x73, 0x0e, 0xfb, 0x95, 0x30, 0xb1, 0xc0, 0xbc,
-	0xc0, 0xa5, 0xde, 0xa0, 0x26, 0x4d, 0x8f, 0xe4, 0x39, 0x6c, 0x7f, 0x62, 0x41, 0x82, 0x7a, 0x5b,
-	0xca, 0x4e, 0x54, 0x97, 0x91, 0x66, 0xe4, 0xcb, 0xda, 0x0b, 0xe3, 0xb5, 0xf5, 0x63, 0xd5, 0x35,
-	0x7e, 0xae, 0xba, 0xc6, 0xaf, 0x55, 0xd7, 0xf8, 0xf6, 0xbb, 0x7b, 0xc7, 0xd9, 0xd1, 0x7f, 0x53,
-	0xa3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x60, 0xfa, 0xeb, 0x0c, 0x42, 0x05, 0x00, 0x00,
+	// 846 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xe1, 0x6e, 0xe3, 0x44,
+	0x10, 0x3e, 0x37, 0x6d, 0x9a, 0x4c, 0xdb, 0x34, 0x5d, 0x4e, 0x87, 0x29, 0x28, 0x8a, 0x02, 0x82,
+	0xa8, 0x42, 0x8d, 0x68, 0x25, 0x84, 0x40, 0x3a, 0xa9, 0xb4, 0xe1, 0x04, 0x42, 0x69, 0xb4, 0xad,
+	0x84, 0xb8, 0x7f, 0x1b, 0x7b, 0xe2, 0xac, 0xce, 0xf1, 0x5a, 0xbb, 0x6b, 0xae, 0xb9, 0x37, 0xe0,
+	0x1f, 0xef, 0xc1, 0x8b, 0xf0, 0x07, 0x89, 0x47, 0x38, 0x95, 0x17, 0x41, 0x5e, 0xd7, 0xe9, 0x7a,
+	0x5d, 0xa0, 0xba, 0x3f, 0x51, 0xfc, 0xcd, 0x37, 0x33, 0xde, 0x99, 0xef, 0x5b, 0xc3, 0x8b, 0x88,
+	0xeb, 0x45, 0x36, 0x3b, 0x0e, 0xc4, 0x72, 0xb4, 0x3c, 0x0d, 0x67, 0xa3, 0xe5, 0xe9, 0x48, 0xc9,
+	0x60, 0x14, 0xce, 0x12, 0x11, 0xe2, 0x28, 0xc2, 0x04, 0x25, 0xd3, 0x18, 0x8e, 0x52, 0x29, 0xb4,
+	0x18, 0x25, 0x6c, 0x89, 0x2a, 0x65, 0x01, 0xde, 0xff, 0x3b, 0x36, 0x11, 0xd2, 0x5e, 0x03, 0x87,
+	0x17, 0xef, 0x5a, 0x53, 0x05, 0x0b, 0x5c, 0xb2, 0xa2, 0xe0, 0xe0, 0x12, 0xde, 0xbb, 0x42, 0xc9,
+	0x51, 0x51, 0xd4, 0x98, 0x68, 0x2e, 0x12, 0x9a, 0xc5, 0x48, 0x9e, 0x41, 0x73, 0xce, 0x63, 0x8d,
+	0xd2, 0xf7, 0xfa, 0xde, 0xb0, 0x4d, 0xef, 0x9e, 0x48, 0x1f, 0x76, 0x52, 0x94, 0x5c, 0x84, 0x13,
+	0x96, 0x08, 0xe5, 0x6f, 0xf4, 0xbd, 0x61, 0x83, 0xda, 0xd0, 0xe0, 0x6d, 0x03, 0xba, 0xeb, 0x5a,
+	0x97, 0x69, 0xfe, 0xab, 0xc8, 0x09, 0x3c, 0x95, 0x25, 0x36, 0xb5, 0xf2, 0x3d, 0x93, 0xff, 0x60,
+	0x8c, 0x7c, 0x0a, 0x9d, 0x59, 0x2c, 0x82, 0x57, 0x57, 0xfc, 0x0d, 0xda, 0xdd, 0x1c, 0x94, 0x7c,
+	0x0e, 0x07, 0xb3, 0x6c, 0x3e, 0x47, 0xf9, 0x5d, 0xa6, 0x33, 0x79, 0x47, 0x6d, 0x18, 0x6a, 0x3d,
+	0x40, 0x86, 0xb0, 0x5f, 0x80, 0x53, 0xa6, 0x74, 0xc1, 0xdd, 0x34, 0x5c, 0x17, 0x36, 0xcc, 0xbc,
+	0xd3, 0x05, 0xd3, 0x6c, 0x7c, 0x93, 0x72, 0xb9, 0xf2, 0xb7, 0xfa, 0xde, 0xb0, 0x45, 0x5d, 0x98,
+	0xbc, 0x84, 0xa1, 0x03, 0x9d, 0xcd, 0x35, 0xca, 0x89, 0xd0, 0x67, 0x41, 0x80, 0x4a, 0xd9, 0x27,
+	0x6e, 0x9a, 0x66, 0x8f, 0xe6, 0x93, 0xe7, 0x70, 0x38, 0x37, 0xaf, 0x4f, 0x1f, 0x9a, 0xdf, 0xb6,
+	0xa9, 0xf6, 0x1f, 0x0c, 0x42, 0xe1, 0xa9, 0xaa, 0xef, 0x57, 0xf9, 0xad, 0x7e, 0x63, 0xb8, 0x73,
+	0xd2, 0x3b, 0xbe, 0x17, 0xd8, 0x03, 0x32, 0xa0, 0x0f, 0xe6, 0x0e, 0xa6, 0xb0, 0xfb, 0x7d, 0x12,
+	0xe2, 0x4d, 0xb9, 0x5d, 0x1f, 0xb6, 0x31, 0x61, 0xb3, 0x18, 0x43, 0xb3, 0xd0, 0x16, 0x2d, 0x1f,
+	0x1f, 0xbb, 0xc3, 0xc1, 0x1b, 0xe8, 0x5c, 0x73, 0x94, 0x3c, 0x89, 0x1e, 0x55, 0x33, 0x10, 0x71,
+	0x58, 0x8c, 0xcc, 0xae, 0x59, 0x45, 0x73, 0xde, 0x9c, 0xc7, 0x38, 0x65, 0x7a, 0x31, 0x95, 0x38,
+	0xe7, 0x37, 0x46, 0x14, 0x6d, 0xea, 0xa0, 0x83, 0x3f, 0x3d, 0x38, 0xb8, 0x10, 0xaf, 0x13, 0xc5,
+	0x96, 0x69, 0x8c, 0xff, 0xdf, 0xbf, 0x07, 0xc0, 0xdc, 0xde, 0x16, 0x92, 0xeb, 0x46, 0xa2, 0x12,
+	0x71, 0x96, 0x17, 0xb2, 0xd5, 0xe8, 0xc2, 0x39, 0x53, 0x33, 0x19, 0xa1, 0x9e, 0x94, 0x4b, 0x30,
+	0x5a, 0x6c, 0x53, 0x17, 0x26, 0x47, 0xd0, 0x65, 0x51, 0x24, 0x31, 0x62, 0x79, 0xf6, 0xf5, 0x2a,
+	0x45, 0xe5, 0x6f, 0xf5, 0x1b, 0xc3, 0x36, 0xad, 0xe1, 0x83, 0x5f, 0x9b, 0xd0, 0x5d, 0x67, 0x96,
+	0xc7, 0x39, 0x82, 0xee, 0x4c, 0x08, 0xad, 0xb4, 0x64, 0xe9, 0xb8, 0x72, 0xae, 0x1a, 0x4e, 0x06,
+	0xb0, 0x3b, 0x8f, 0x33, 0xb5, 0x28, 0x79, 0x1b, 0x86, 0x57, 0xc1, 0x72, 0xd3, 0xbd, 0x96, 0x5c,
+	0xa3, 0xba, 0x16, 0xe7, 0x62, 0xb9, 0xe4, 0xfa, 0x47, 0x11, 0x99, 0x63, 0xb6, 0x68, 0x3d, 0x60,
+	0x56, 0x16, 0x23, 0x4b, 0xb2, 0x75, 0xef, 0x4d, 0x43, 0x75, 0x50, 0xf2, 0x09, 0xec, 0x49, 0x4c,
+	0x19, 0x97, 0x25, 0xad, 0x30, 0x5c, 0x15, 0x24, 0x2f, 0xa0, 0x2b, 0x9d, 0x0b, 0xc6, 0xd8, 0x6a,
+	0xe7, 0xe4, 0x43, 0x4b, 0xce, 0xee, 0x1d, 0x44, 0x6b, 0x49, 0xf9, 0xfc, 0x55, 0xc2, 0x52, 0xb5,
+	0x10, 0xba, 0x6c, 0xb8, 0x5d, 0x38, 0xdc, 0x81, 0xc9, 0x37, 0xb0, 0xcb, 0x2d, 0xc5, 0xfb, 0x2d,
+	0xd3, 0xee, 0x7d, 0xab, 0x9d, 0x6d, 0x08, 0x5a, 0x21, 0x93, 0xe7, 0xb0, 0x57, 0x5c, 0xb9, 0x65,
+	0x76, 0xdb, 0x64, 0xfb, 0xb6, 0xf7, 0xec, 0x38, 0xad, 0xd2, 0xf3, 0x59, 0xe7, 0xd2, 0xfe, 0xc9,
+	0x8c, 0xb5, 0x7c, 0x51, 0x28, 0x66, 0x5d, 0x0b, 0x90, 0x33, 0xe8, 0xe8, 0x8a, 0x95, 0xfc, 0x1d,
+	0xd3, 0xee, 0x03, 0xab, 0x5d, 0xd5, 0x6b, 0xd4, 0x49, 0x20, 0x17, 0xb0, 0x1f, 0x32, 0xcd, 0xce,
+	0xc5, 0x32, 0x95, 0xa8, 0x14, 0x17, 0x89, 0xbf, 0xdb, 0xf7, 0x86, 0x9d, 0x93, 0x43, 0xab, 0x86,
+	0x15, 0xcd, 0x75, 0x47, 0xdd, 0x14, 0xf2, 0x03, 0x1c, 0x84, 0xae, 0xad, 0xfc, 0x3d, 0xf3, 0x2e,
+	0x1f, 0x59, 0x75, 0x6a, 0xd6, 0xa3, 0xf5, 0x34, 0xf2, 0x25, 0x3c, 0x0b, 0x44, 0x96, 0x68, 0x94,
+	0xe3, 0x24, 0x10, 0x21, 0x4f, 0xa2, 0x72, 0x0e, 0x1d, 0x33, 0x87, 0x7f, 0x89, 0x0e, 0x7e, 0xf7,
+	0xa0, 0x45, 0x31, 0xe2, 0x4a, 0xcb, 0x15, 0x39, 0x07, 0x58, 0xb7, 0xcd, 0x3f, 0x3d, 0xf9, 0x05,
+	0xf8, 0x71, 0x45, 0x31, 0x05, 0xf1, 0x78, 0xed, 0x1e, 0x35, 0x4e, 0xb4, 0x5c, 0x51, 0x2b, 0xed,
+	0xf0, 0x25, 0xec, 0x3b, 0x61, 0xd2, 0x85, 0xc6, 0x2b, 0x5c, 0xdd, 0x7d, 0x28, 0xf3, 0xbf, 0xe4,
+	0x0b, 0xd8, 0xfa, 0x85, 0xc5, 0x19, 0xfa, 0x1b, 0x35, 0x59, 0xba, 0xce, 0xa4, 0x05, 0xf3, 0xeb,
+	0x8d, 0xaf, 0xbc, 0xa3, 0xcf, 0x60, 0xdf, 0x99, 0x2a, 0x69, 0xc1, 0xe6, 0xe4, 0x72, 0x32, 0xee,
+	0x3e, 0x21, 0x00, 0xcd, 0xab, 0xc9, 0xd9, 0x74, 0xfa, 0x73, 0xd7, 0xfb, 0xb6, 0xfb, 0xc7, 0x6d,
+	0xcf, 0xfb, 0xeb, 0xb6, 0xe7, 0xbd, 0xbd, 0xed, 0x79, 0xbf, 0xfd, 0xdd, 0x7b, 0x32, 0x6b, 0x9a,
+	0xaf, 0xf9, 0xe9, 0x3f, 0x03, 0x00, 0xd6, 0x71, 0x77, 0xf7, 0x69, 0x08, 0x00, 0x00,
 }
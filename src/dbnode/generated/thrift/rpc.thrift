@@ -63,6 +63,8 @@ service Node {
 	void writeTaggedBatchRawV2(1: WriteTaggedBatchRawV2Request req) throws (1: WriteBatchRawErrors err)
 	void repair() throws (1: Error err)
 	TruncateResult truncate(1: TruncateRequest req) throws (1: Error err)
+	DeleteSeriesResult deleteSeries(1: DeleteSeriesRequest req) throws (1: Error err)
+	BackfillResult backfill(1: BackfillRequest req) throws (1: Error err)
 
 	// Management endpoints
 	NodeHealthResult health() throws (1: Error err)
@@ -296,6 +298,35 @@ struct TruncateResult {
 	1: required i64 numSeries
 }
 
+struct DeleteSeriesRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: optional TimeType rangeTimeType = TimeType.UNIX_SECONDS
+}
+
+struct DeleteSeriesResult {
+	1: required i64 numSeries
+}
+
+struct BackfillRequest {
+	1: required binary nameSpace
+	2: required i64 blockStart
+	3: required list<BackfillSeries> series
+	4: optional TimeType blockStartTimeType = TimeType.UNIX_SECONDS
+}
+
+struct BackfillSeries {
+	1: required binary id
+	2: required binary encodedTags
+	3: required list<Datapoint> datapoints
+}
+
+struct BackfillResult {
+	1: required i64 numSeries
+}
+
 struct NodeHealthResult {
 	1: required bool ok
 	2: required string status
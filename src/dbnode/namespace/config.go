@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/ident"
 )
 
@@ -48,15 +50,19 @@ func (m *MapConfiguration) Map() (Map, error) {
 
 // MetadataConfiguration is the configuration for a single namespace
 type MetadataConfiguration struct {
-	ID                string                  `yaml:"id" validate:"nonzero"`
-	BootstrapEnabled  *bool                   `yaml:"bootstrapEnabled"`
-	FlushEnabled      *bool                   `yaml:"flushEnabled"`
-	WritesToCommitLog *bool                   `yaml:"writesToCommitLog"`
-	CleanupEnabled    *bool                   `yaml:"cleanupEnabled"`
-	RepairEnabled     *bool                   `yaml:"repairEnabled"`
-	ColdWritesEnabled *bool                   `yaml:"coldWritesEnabled"`
-	Retention         retention.Configuration `yaml:"retention" validate:"nonzero"`
-	Index             IndexConfiguration      `yaml:"index"`
+	ID                     string                   `yaml:"id" validate:"nonzero"`
+	BootstrapEnabled       *bool                    `yaml:"bootstrapEnabled"`
+	FlushEnabled           *bool                    `yaml:"flushEnabled"`
+	WritesToCommitLog      *bool                    `yaml:"writesToCommitLog"`
+	CleanupEnabled         *bool                    `yaml:"cleanupEnabled"`
+	RepairEnabled          *bool                    `yaml:"repairEnabled"`
+	ColdWritesEnabled      *bool                    `yaml:"coldWritesEnabled"`
+	Retention              retention.Configuration  `yaml:"retention" validate:"nonzero"`
+	Index                  IndexConfiguration       `yaml:"index"`
+	Tiering                *TieringConfiguration    `yaml:"tiering"`
+	DataCompression        compression.Type         `yaml:"dataCompression"`
+	Downsample             *DownsampleConfiguration `yaml:"downsample"`
+	CounterEncodingEnabled *bool                    `yaml:"counterEncodingEnabled"`
 }
 
 // Metadata returns a Metadata corresponding to the receiver struct
@@ -84,6 +90,16 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.ColdWritesEnabled; v != nil {
 		opts = opts.SetColdWritesEnabled(*v)
 	}
+	if v := mc.Tiering; v != nil {
+		opts = opts.SetTieringOptions(v.Options())
+	}
+	opts = opts.SetDataCompression(mc.DataCompression)
+	if v := mc.Downsample; v != nil {
+		opts = opts.SetDownsampleOptions(v.Options())
+	}
+	if v := mc.CounterEncodingEnabled; v != nil {
+		opts = opts.SetCounterEncodingEnabled(*v)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
@@ -99,3 +115,40 @@ func (ic *IndexConfiguration) Options() IndexOptions {
 		SetEnabled(ic.Enabled).
 		SetBlockSize(ic.BlockSize)
 }
+
+// TieringConfiguration controls the knobs to tweak tiering configuration.
+type TieringConfiguration struct {
+	Enabled        bool          `yaml:"enabled"`
+	ColdAfter      time.Duration `yaml:"coldAfter" validate:"nonzero"`
+	FilePathPrefix string        `yaml:"filePathPrefix" validate:"nonzero"`
+}
+
+// Options returns the TieringOptions corresponding to the receiver struct.
+func (tc *TieringConfiguration) Options() TieringOptions {
+	return NewTieringOptions().
+		SetEnabled(tc.Enabled).
+		SetColdAfter(tc.ColdAfter).
+		SetFilePathPrefix(tc.FilePathPrefix)
+}
+
+// DownsampleConfiguration controls the knobs to tweak downsampling configuration.
+type DownsampleConfiguration struct {
+	Enabled          bool              `yaml:"enabled"`
+	After            time.Duration     `yaml:"after" validate:"nonzero"`
+	Resolution       time.Duration     `yaml:"resolution" validate:"nonzero"`
+	TargetNamespace  string            `yaml:"targetNamespace" validate:"nonzero"`
+	AggregationTypes aggregation.Types `yaml:"aggregationTypes"`
+}
+
+// Options returns the DownsampleOptions corresponding to the receiver struct.
+func (dc *DownsampleConfiguration) Options() DownsampleOptions {
+	opts := NewDownsampleOptions().
+		SetEnabled(dc.Enabled).
+		SetAfter(dc.After).
+		SetResolution(dc.Resolution).
+		SetTargetNamespace(ident.StringID(dc.TargetNamespace))
+	if len(dc.AggregationTypes) > 0 {
+		opts = opts.SetAggregationTypes(dc.AggregationTypes)
+	}
+	return opts
+}
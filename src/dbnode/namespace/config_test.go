@@ -24,7 +24,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/stretchr/testify/require"
@@ -85,13 +87,14 @@ func TestRegistryConfig(t *testing.T) {
 
 func TestMetadataConfig(t *testing.T) {
 	var (
-		id                = "someLongString"
-		bootstrapEnabled  = true
-		flushEnabled      = false
-		writesToCommitLog = true
-		cleanupEnabled    = false
-		repairEnabled     = false
-		retention         = retention.Configuration{
+		id                     = "someLongString"
+		bootstrapEnabled       = true
+		flushEnabled           = false
+		writesToCommitLog      = true
+		cleanupEnabled         = false
+		repairEnabled          = false
+		counterEncodingEnabled = true
+		retention              = retention.Configuration{
 			BlockSize:       time.Hour,
 			RetentionPeriod: time.Hour,
 			BufferFuture:    time.Minute,
@@ -101,15 +104,31 @@ func TestMetadataConfig(t *testing.T) {
 			Enabled:   true,
 			BlockSize: time.Hour,
 		}
+		tiering = &TieringConfiguration{
+			Enabled:        true,
+			ColdAfter:      30 * time.Minute,
+			FilePathPrefix: "/var/lib/m3db-cold",
+		}
+		downsample = &DownsampleConfiguration{
+			Enabled:          true,
+			After:            30 * time.Minute,
+			Resolution:       5 * time.Minute,
+			TargetNamespace:  "metrics-5m",
+			AggregationTypes: aggregation.Types{aggregation.Max},
+		}
 		config = &MetadataConfiguration{
-			ID:                id,
-			BootstrapEnabled:  &bootstrapEnabled,
-			FlushEnabled:      &flushEnabled,
-			WritesToCommitLog: &writesToCommitLog,
-			CleanupEnabled:    &cleanupEnabled,
-			RepairEnabled:     &repairEnabled,
-			Retention:         retention,
-			Index:             index,
+			ID:                     id,
+			BootstrapEnabled:       &bootstrapEnabled,
+			FlushEnabled:           &flushEnabled,
+			WritesToCommitLog:      &writesToCommitLog,
+			CleanupEnabled:         &cleanupEnabled,
+			RepairEnabled:          &repairEnabled,
+			Retention:              retention,
+			Index:                  index,
+			Tiering:                tiering,
+			DataCompression:        compression.Snappy,
+			Downsample:             downsample,
+			CounterEncodingEnabled: &counterEncodingEnabled,
 		}
 	)
 
@@ -125,6 +144,10 @@ func TestMetadataConfig(t *testing.T) {
 	require.Equal(t, repairEnabled, opts.RepairEnabled())
 	require.Equal(t, retention.Options(), opts.RetentionOptions())
 	require.Equal(t, index.Options(), opts.IndexOptions())
+	require.Equal(t, tiering.Options(), opts.TieringOptions())
+	require.Equal(t, compression.Snappy, opts.DataCompression())
+	require.True(t, downsample.Options().Equal(opts.DownsampleOptions()))
+	require.Equal(t, counterEncodingEnabled, opts.CounterEncodingEnabled())
 }
 
 func TestRegistryConfigFromBytes(t *testing.T) {
@@ -166,6 +189,16 @@ metadatas:
     index:
       enabled: true
       blockSize: 24h
+    dataCompression: snappy
+    downsample:
+      enabled: true
+      after: 48h
+      resolution: 1h
+      targetNamespace: metrics-1h:1y
+      aggregationTypes:
+        - Max
+        - Mean
+    counterEncodingEnabled: true
 `)
 
 	var conf MapConfiguration
@@ -230,5 +263,13 @@ metadatas:
 		SetBufferFuture(10 * time.Minute).
 		SetBufferPast(10 * time.Minute)
 	require.True(t, testRetentionOpts.Equal(opts.RetentionOptions()))
-
+	require.Equal(t, compression.Snappy, opts.DataCompression())
+	testDownsampleOpts := NewDownsampleOptions().
+		SetEnabled(true).
+		SetAfter(48 * time.Hour).
+		SetResolution(time.Hour).
+		SetTargetNamespace(ident.StringID("metrics-1h:1y")).
+		SetAggregationTypes(aggregation.Types{aggregation.Max, aggregation.Mean})
+	require.True(t, testDownsampleOpts.Equal(opts.DownsampleOptions()))
+	require.True(t, opts.CounterEncodingEnabled())
 }
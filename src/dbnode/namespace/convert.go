@@ -22,10 +22,13 @@ package namespace
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
 )
@@ -56,6 +59,16 @@ func ToRetention(
 		SetBlockDataExpiry(ro.BlockDataExpiry).
 		SetBlockDataExpiryAfterNotAccessedPeriod(
 			fromNanos(ro.BlockDataExpiryAfterNotAccessPeriodNanos))
+	if len(ro.SeriesRetentionRules) > 0 {
+		rules := make([]retention.SeriesRetentionRule, 0, len(ro.SeriesRetentionRules))
+		for _, rule := range ro.SeriesRetentionRules {
+			rules = append(rules, retention.SeriesRetentionRule{
+				Filter: rule.Filter,
+				Period: fromNanos(rule.PeriodNanos),
+			})
+		}
+		ropts = ropts.SetSeriesRetentionRules(rules)
+	}
 
 	if err := ropts.Validate(); err != nil {
 		return nil, err
@@ -64,6 +77,23 @@ func ToRetention(
 	return ropts, nil
 }
 
+func seriesRetentionRulesToProto(
+	rules []retention.SeriesRetentionRule,
+) []*nsproto.SeriesRetentionRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	result := make([]*nsproto.SeriesRetentionRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, &nsproto.SeriesRetentionRule{
+			Filter:      rule.Filter,
+			PeriodNanos: rule.Period.Nanoseconds(),
+		})
+	}
+	return result
+}
+
 // ToIndexOptions converts nsproto.IndexOptions to IndexOptions
 func ToIndexOptions(
 	io *nsproto.IndexOptions,
@@ -79,6 +109,96 @@ func ToIndexOptions(
 	return iopts, nil
 }
 
+// ToTieringOptions converts nsproto.TieringOptions to TieringOptions
+func ToTieringOptions(
+	to *nsproto.TieringOptions,
+) (TieringOptions, error) {
+	topts := NewTieringOptions()
+	if to == nil {
+		return topts, nil
+	}
+
+	topts = topts.SetEnabled(to.Enabled).
+		SetColdAfter(fromNanos(to.ColdAfterNanos)).
+		SetFilePathPrefix(to.FilePathPrefix)
+
+	return topts, nil
+}
+
+// ToDownsampleOptions converts nsproto.DownsampleOptions to DownsampleOptions
+func ToDownsampleOptions(
+	do *nsproto.DownsampleOptions,
+) (DownsampleOptions, error) {
+	dopts := NewDownsampleOptions()
+	if do == nil {
+		return dopts, nil
+	}
+
+	dopts = dopts.SetEnabled(do.Enabled).
+		SetAfter(fromNanos(do.AfterNanos)).
+		SetResolution(fromNanos(do.ResolutionNanos))
+	if do.TargetNamespace != "" {
+		dopts = dopts.SetTargetNamespace(ident.StringID(do.TargetNamespace))
+	}
+
+	if len(do.AggregationTypes) > 0 {
+		aggTypes := make(aggregation.Types, 0, len(do.AggregationTypes))
+		for _, str := range do.AggregationTypes {
+			aggType, err := aggregation.ParseType(str)
+			if err != nil {
+				return nil, err
+			}
+			aggTypes = append(aggTypes, aggType)
+		}
+		dopts = dopts.SetAggregationTypes(aggTypes)
+	}
+
+	return dopts, nil
+}
+
+func downsampleOptionsToProto(dopts DownsampleOptions) *nsproto.DownsampleOptions {
+	var targetNamespace string
+	if id := dopts.TargetNamespace(); id != nil {
+		targetNamespace = id.String()
+	}
+
+	aggTypes := make([]string, 0, len(dopts.AggregationTypes()))
+	for _, aggType := range dopts.AggregationTypes() {
+		aggTypes = append(aggTypes, aggType.String())
+	}
+
+	return &nsproto.DownsampleOptions{
+		Enabled:          dopts.Enabled(),
+		AfterNanos:       dopts.After().Nanoseconds(),
+		ResolutionNanos:  dopts.Resolution().Nanoseconds(),
+		TargetNamespace:  targetNamespace,
+		AggregationTypes: aggTypes,
+	}
+}
+
+// ToCompressionType converts nsproto.CompressionType to compression.Type
+func ToCompressionType(
+	ct nsproto.CompressionType,
+) (compression.Type, error) {
+	switch ct {
+	case nsproto.CompressionType_NONE:
+		return compression.None, nil
+	case nsproto.CompressionType_SNAPPY:
+		return compression.Snappy, nil
+	default:
+		return 0, fmt.Errorf("invalid compression type: %v", ct)
+	}
+}
+
+func compressionTypeToProto(t compression.Type) nsproto.CompressionType {
+	switch t {
+	case compression.Snappy:
+		return nsproto.CompressionType_SNAPPY
+	default:
+		return nsproto.CompressionType_NONE
+	}
+}
+
 // ToMetadata converts nsproto.Options to Metadata
 func ToMetadata(
 	id string,
@@ -98,6 +218,21 @@ func ToMetadata(
 		return nil, err
 	}
 
+	topts, err := ToTieringOptions(opts.TieringOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	dataCompression, err := ToCompressionType(opts.DataCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	dopts, err := ToDownsampleOptions(opts.DownsampleOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	sr, err := LoadSchemaHistory(opts.GetSchemaOptions())
 	if err != nil {
 		return nil, err
@@ -113,7 +248,11 @@ func ToMetadata(
 		SetSchemaHistory(sr).
 		SetRetentionOptions(ropts).
 		SetIndexOptions(iopts).
-		SetColdWritesEnabled(opts.ColdWritesEnabled)
+		SetColdWritesEnabled(opts.ColdWritesEnabled).
+		SetTieringOptions(topts).
+		SetDataCompression(dataCompression).
+		SetDownsampleOptions(dopts).
+		SetCounterEncodingEnabled(opts.CounterEncodingEnabled)
 
 	return NewMetadata(ident.StringID(id), mopts)
 }
@@ -148,6 +287,7 @@ func FromProto(protoRegistry nsproto.Registry) (Map, error) {
 func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 	ropts := opts.RetentionOptions()
 	iopts := opts.IndexOptions()
+	topts := opts.TieringOptions()
 
 	return &nsproto.NamespaceOptions{
 		BootstrapEnabled:  opts.BootstrapEnabled(),
@@ -165,11 +305,20 @@ func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 			BufferPastNanos:                          ropts.BufferPast().Nanoseconds(),
 			BlockDataExpiry:                          ropts.BlockDataExpiry(),
 			BlockDataExpiryAfterNotAccessPeriodNanos: ropts.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds(),
+			SeriesRetentionRules:                     seriesRetentionRulesToProto(ropts.SeriesRetentionRules()),
 		},
 		IndexOptions: &nsproto.IndexOptions{
 			Enabled:        iopts.Enabled(),
 			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
 		},
 		ColdWritesEnabled: opts.ColdWritesEnabled(),
+		TieringOptions: &nsproto.TieringOptions{
+			Enabled:        topts.Enabled(),
+			ColdAfterNanos: topts.ColdAfter().Nanoseconds(),
+			FilePathPrefix: topts.FilePathPrefix(),
+		},
+		DataCompression:        compressionTypeToProto(opts.DataCompression()),
+		DownsampleOptions:      downsampleOptionsToProto(opts.DownsampleOptions()),
+		CounterEncodingEnabled: opts.CounterEncodingEnabled(),
 	}
 }
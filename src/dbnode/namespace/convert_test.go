@@ -26,7 +26,9 @@ import (
 
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/stretchr/testify/require"
@@ -271,6 +273,81 @@ func TestFromProtoSnapshotEnabled(t *testing.T) {
 	require.Equal(t, !namespace.NewOptions().SnapshotEnabled(), md.Options().SnapshotEnabled())
 }
 
+func TestTieringOptionsRoundTrip(t *testing.T) {
+	opts := namespace.NewOptions().
+		SetTieringOptions(namespace.NewTieringOptions().
+			SetEnabled(true).
+			SetColdAfter(24 * time.Hour).
+			SetFilePathPrefix("/var/lib/m3db-cold"))
+
+	assertOptionsRoundTrip(t, opts)
+}
+
+func TestDataCompressionRoundTrip(t *testing.T) {
+	assertOptionsRoundTrip(t, namespace.NewOptions().
+		SetDataCompression(compression.Snappy))
+}
+
+func TestToCompressionTypeInvalid(t *testing.T) {
+	_, err := namespace.ToCompressionType(nsproto.CompressionType(42))
+	require.Error(t, err)
+}
+
+func TestDownsampleOptionsRoundTrip(t *testing.T) {
+	assertOptionsRoundTrip(t, namespace.NewOptions().
+		SetDownsampleOptions(namespace.NewDownsampleOptions().
+			SetEnabled(true).
+			SetAfter(24 * time.Hour).
+			SetResolution(time.Minute).
+			SetTargetNamespace(ident.StringID("ns1_downsampled")).
+			SetAggregationTypes(aggregation.Types{aggregation.Max, aggregation.Last})))
+}
+
+func TestToDownsampleOptionsInvalidAggregationType(t *testing.T) {
+	_, err := namespace.ToDownsampleOptions(&nsproto.DownsampleOptions{
+		AggregationTypes: []string{"NotAnAggregation"},
+	})
+	require.Error(t, err)
+}
+
+func TestCounterEncodingEnabledRoundTrip(t *testing.T) {
+	assertOptionsRoundTrip(t, namespace.NewOptions().
+		SetCounterEncodingEnabled(!namespace.NewOptions().CounterEncodingEnabled()))
+}
+
+func TestSeriesRetentionRulesRoundTrip(t *testing.T) {
+	ropts := retention.NewOptions().
+		SetSeriesRetentionRules([]retention.SeriesRetentionRule{
+			{Filter: "env:dev", Period: 6 * time.Hour},
+			{Filter: "env:staging service:api-*", Period: 12 * time.Hour},
+		})
+
+	assertOptionsRoundTrip(t, namespace.NewOptions().SetRetentionOptions(ropts))
+}
+
+func TestNamespaceToRetentionInvalidSeriesRetentionRule(t *testing.T) {
+	opts := validRetentionOpts
+	opts.SeriesRetentionRules = []*nsproto.SeriesRetentionRule{
+		{Filter: "env:dev", PeriodNanos: toNanos(1260)}, // 21h
+	}
+	_, err := namespace.ToRetention(&opts)
+	require.Error(t, err)
+}
+
+func assertOptionsRoundTrip(t *testing.T, opts namespace.Options) {
+	md, err := namespace.NewMetadata(ident.StringID("ns1"), opts)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	nsMap, err = namespace.FromProto(*namespace.ToProto(nsMap))
+	require.NoError(t, err)
+
+	observed, err := nsMap.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.True(t, opts.Equal(observed.Options()))
+}
+
 func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOptions, observed namespace.Metadata) {
 	require.Equal(t, name, observed.ID().String())
 	opts := observed.Options()
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+var (
+	// defaultDownsampleEnabled disables downsampling by default.
+	defaultDownsampleEnabled = false
+
+	// defaultDownsampleAggregationTypes keeps the last value of each
+	// resolution interval by default.
+	defaultDownsampleAggregationTypes = aggregation.Types{aggregation.Last}
+)
+
+type downsampleOpts struct {
+	enabled          bool
+	after            time.Duration
+	resolution       time.Duration
+	targetNamespace  ident.ID
+	aggregationTypes aggregation.Types
+}
+
+// NewDownsampleOptions returns a new DownsampleOptions.
+func NewDownsampleOptions() DownsampleOptions {
+	return &downsampleOpts{
+		enabled:          defaultDownsampleEnabled,
+		aggregationTypes: defaultDownsampleAggregationTypes,
+	}
+}
+
+func (d *downsampleOpts) Equal(value DownsampleOptions) bool {
+	return d.Enabled() == value.Enabled() &&
+		d.After() == value.After() &&
+		d.Resolution() == value.Resolution() &&
+		equalNamespaceIDs(d.TargetNamespace(), value.TargetNamespace()) &&
+		equalAggregationTypes(d.AggregationTypes(), value.AggregationTypes())
+}
+
+func (d *downsampleOpts) SetEnabled(value bool) DownsampleOptions {
+	do := *d
+	do.enabled = value
+	return &do
+}
+
+func (d *downsampleOpts) Enabled() bool {
+	return d.enabled
+}
+
+func (d *downsampleOpts) SetAfter(value time.Duration) DownsampleOptions {
+	do := *d
+	do.after = value
+	return &do
+}
+
+func (d *downsampleOpts) After() time.Duration {
+	return d.after
+}
+
+func (d *downsampleOpts) SetResolution(value time.Duration) DownsampleOptions {
+	do := *d
+	do.resolution = value
+	return &do
+}
+
+func (d *downsampleOpts) Resolution() time.Duration {
+	return d.resolution
+}
+
+func (d *downsampleOpts) SetTargetNamespace(value ident.ID) DownsampleOptions {
+	do := *d
+	do.targetNamespace = value
+	return &do
+}
+
+func (d *downsampleOpts) TargetNamespace() ident.ID {
+	return d.targetNamespace
+}
+
+func (d *downsampleOpts) SetAggregationTypes(value aggregation.Types) DownsampleOptions {
+	do := *d
+	do.aggregationTypes = value
+	return &do
+}
+
+func (d *downsampleOpts) AggregationTypes() aggregation.Types {
+	return d.aggregationTypes
+}
+
+func equalNamespaceIDs(a, b ident.ID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
+
+func equalAggregationTypes(a, b aggregation.Types) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsampleOptionsEqual(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.True(t, opts.Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetEnabled(true).Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetAfter(time.Hour).Equal(
+		opts.SetAfter(time.Hour*2)))
+	require.False(t, opts.SetResolution(time.Minute).Equal(
+		opts.SetResolution(time.Minute*5)))
+	require.True(t, opts.SetTargetNamespace(ident.StringID("a")).Equal(
+		opts.SetTargetNamespace(ident.StringID("a"))))
+	require.False(t, opts.SetTargetNamespace(ident.StringID("a")).Equal(
+		opts.SetTargetNamespace(ident.StringID("b"))))
+	require.False(t, opts.SetTargetNamespace(ident.StringID("a")).Equal(opts))
+	require.False(t, opts.SetAggregationTypes(aggregation.Types{aggregation.Max}).Equal(
+		opts.SetAggregationTypes(aggregation.Types{aggregation.Min})))
+}
+
+func TestDownsampleOptionsEnabled(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.False(t, opts.Enabled())
+	require.True(t, opts.SetEnabled(true).Enabled())
+	require.False(t, opts.SetEnabled(false).Enabled())
+}
+
+func TestDownsampleOptionsAfter(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.Equal(t, time.Hour, opts.SetAfter(time.Hour).After())
+}
+
+func TestDownsampleOptionsResolution(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.Equal(t, time.Minute, opts.SetResolution(time.Minute).Resolution())
+}
+
+func TestDownsampleOptionsTargetNamespace(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.Nil(t, opts.TargetNamespace())
+	require.Equal(t, "metrics-1m", opts.SetTargetNamespace(
+		ident.StringID("metrics-1m")).TargetNamespace().String())
+}
+
+func TestDownsampleOptionsAggregationTypes(t *testing.T) {
+	opts := NewDownsampleOptions()
+	require.Equal(t, aggregation.Types{aggregation.Last}, opts.AggregationTypes())
+	aggTypes := aggregation.Types{aggregation.Min, aggregation.Max}
+	require.Equal(t, aggTypes, opts.SetAggregationTypes(aggTypes).AggregationTypes())
+}
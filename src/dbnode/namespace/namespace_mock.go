@@ -29,7 +29,9 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/close"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -368,6 +370,118 @@ func (mr *MockOptionsMockRecorder) SchemaHistory() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaHistory", reflect.TypeOf((*MockOptions)(nil).SchemaHistory))
 }
 
+// SetTieringOptions mocks base method
+func (m *MockOptions) SetTieringOptions(value TieringOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTieringOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetTieringOptions indicates an expected call of SetTieringOptions
+func (mr *MockOptionsMockRecorder) SetTieringOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTieringOptions", reflect.TypeOf((*MockOptions)(nil).SetTieringOptions), value)
+}
+
+// TieringOptions mocks base method
+func (m *MockOptions) TieringOptions() TieringOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TieringOptions")
+	ret0, _ := ret[0].(TieringOptions)
+	return ret0
+}
+
+// TieringOptions indicates an expected call of TieringOptions
+func (mr *MockOptionsMockRecorder) TieringOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TieringOptions", reflect.TypeOf((*MockOptions)(nil).TieringOptions))
+}
+
+// SetDataCompression mocks base method
+func (m *MockOptions) SetDataCompression(value compression.Type) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDataCompression", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDataCompression indicates an expected call of SetDataCompression
+func (mr *MockOptionsMockRecorder) SetDataCompression(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDataCompression", reflect.TypeOf((*MockOptions)(nil).SetDataCompression), value)
+}
+
+// DataCompression mocks base method
+func (m *MockOptions) DataCompression() compression.Type {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DataCompression")
+	ret0, _ := ret[0].(compression.Type)
+	return ret0
+}
+
+// DataCompression indicates an expected call of DataCompression
+func (mr *MockOptionsMockRecorder) DataCompression() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataCompression", reflect.TypeOf((*MockOptions)(nil).DataCompression))
+}
+
+// SetDownsampleOptions mocks base method
+func (m *MockOptions) SetDownsampleOptions(value DownsampleOptions) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownsampleOptions", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDownsampleOptions indicates an expected call of SetDownsampleOptions
+func (mr *MockOptionsMockRecorder) SetDownsampleOptions(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownsampleOptions", reflect.TypeOf((*MockOptions)(nil).SetDownsampleOptions), value)
+}
+
+// DownsampleOptions mocks base method
+func (m *MockOptions) DownsampleOptions() DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownsampleOptions")
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// DownsampleOptions indicates an expected call of DownsampleOptions
+func (mr *MockOptionsMockRecorder) DownsampleOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownsampleOptions", reflect.TypeOf((*MockOptions)(nil).DownsampleOptions))
+}
+
+// SetCounterEncodingEnabled mocks base method
+func (m *MockOptions) SetCounterEncodingEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCounterEncodingEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCounterEncodingEnabled indicates an expected call of SetCounterEncodingEnabled
+func (mr *MockOptionsMockRecorder) SetCounterEncodingEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCounterEncodingEnabled", reflect.TypeOf((*MockOptions)(nil).SetCounterEncodingEnabled), value)
+}
+
+// CounterEncodingEnabled mocks base method
+func (m *MockOptions) CounterEncodingEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterEncodingEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CounterEncodingEnabled indicates an expected call of CounterEncodingEnabled
+func (mr *MockOptionsMockRecorder) CounterEncodingEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterEncodingEnabled", reflect.TypeOf((*MockOptions)(nil).CounterEncodingEnabled))
+}
+
 // MockIndexOptions is a mock of IndexOptions interface
 type MockIndexOptions struct {
 	ctrl     *gomock.Controller
@@ -461,6 +575,304 @@ func (mr *MockIndexOptionsMockRecorder) BlockSize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSize", reflect.TypeOf((*MockIndexOptions)(nil).BlockSize))
 }
 
+// MockTieringOptions is a mock of TieringOptions interface
+type MockTieringOptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockTieringOptionsMockRecorder
+}
+
+// MockTieringOptionsMockRecorder is the mock recorder for MockTieringOptions
+type MockTieringOptionsMockRecorder struct {
+	mock *MockTieringOptions
+}
+
+// NewMockTieringOptions creates a new mock instance
+func NewMockTieringOptions(ctrl *gomock.Controller) *MockTieringOptions {
+	mock := &MockTieringOptions{ctrl: ctrl}
+	mock.recorder = &MockTieringOptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTieringOptions) EXPECT() *MockTieringOptionsMockRecorder {
+	return m.recorder
+}
+
+// Equal mocks base method
+func (m *MockTieringOptions) Equal(value TieringOptions) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Equal", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Equal indicates an expected call of Equal
+func (mr *MockTieringOptionsMockRecorder) Equal(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockTieringOptions)(nil).Equal), value)
+}
+
+// SetEnabled mocks base method
+func (m *MockTieringOptions) SetEnabled(value bool) TieringOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", value)
+	ret0, _ := ret[0].(TieringOptions)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled
+func (mr *MockTieringOptionsMockRecorder) SetEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockTieringOptions)(nil).SetEnabled), value)
+}
+
+// Enabled mocks base method
+func (m *MockTieringOptions) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled
+func (mr *MockTieringOptionsMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockTieringOptions)(nil).Enabled))
+}
+
+// SetColdAfter mocks base method
+func (m *MockTieringOptions) SetColdAfter(value time.Duration) TieringOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetColdAfter", value)
+	ret0, _ := ret[0].(TieringOptions)
+	return ret0
+}
+
+// SetColdAfter indicates an expected call of SetColdAfter
+func (mr *MockTieringOptionsMockRecorder) SetColdAfter(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetColdAfter", reflect.TypeOf((*MockTieringOptions)(nil).SetColdAfter), value)
+}
+
+// ColdAfter mocks base method
+func (m *MockTieringOptions) ColdAfter() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ColdAfter")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// ColdAfter indicates an expected call of ColdAfter
+func (mr *MockTieringOptionsMockRecorder) ColdAfter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdAfter", reflect.TypeOf((*MockTieringOptions)(nil).ColdAfter))
+}
+
+// SetFilePathPrefix mocks base method
+func (m *MockTieringOptions) SetFilePathPrefix(value string) TieringOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFilePathPrefix", value)
+	ret0, _ := ret[0].(TieringOptions)
+	return ret0
+}
+
+// SetFilePathPrefix indicates an expected call of SetFilePathPrefix
+func (mr *MockTieringOptionsMockRecorder) SetFilePathPrefix(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFilePathPrefix", reflect.TypeOf((*MockTieringOptions)(nil).SetFilePathPrefix), value)
+}
+
+// FilePathPrefix mocks base method
+func (m *MockTieringOptions) FilePathPrefix() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePathPrefix")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// FilePathPrefix indicates an expected call of FilePathPrefix
+func (mr *MockTieringOptionsMockRecorder) FilePathPrefix() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathPrefix", reflect.TypeOf((*MockTieringOptions)(nil).FilePathPrefix))
+}
+
+// MockDownsampleOptions is a mock of DownsampleOptions interface
+type MockDownsampleOptions struct {
+	ctrl     *gomock.Controller
+	recorder *MockDownsampleOptionsMockRecorder
+}
+
+// MockDownsampleOptionsMockRecorder is the mock recorder for MockDownsampleOptions
+type MockDownsampleOptionsMockRecorder struct {
+	mock *MockDownsampleOptions
+}
+
+// NewMockDownsampleOptions creates a new mock instance
+func NewMockDownsampleOptions(ctrl *gomock.Controller) *MockDownsampleOptions {
+	mock := &MockDownsampleOptions{ctrl: ctrl}
+	mock.recorder = &MockDownsampleOptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDownsampleOptions) EXPECT() *MockDownsampleOptionsMockRecorder {
+	return m.recorder
+}
+
+// Equal mocks base method
+func (m *MockDownsampleOptions) Equal(value DownsampleOptions) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Equal", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Equal indicates an expected call of Equal
+func (mr *MockDownsampleOptionsMockRecorder) Equal(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockDownsampleOptions)(nil).Equal), value)
+}
+
+// SetEnabled mocks base method
+func (m *MockDownsampleOptions) SetEnabled(value bool) DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", value)
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled
+func (mr *MockDownsampleOptionsMockRecorder) SetEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockDownsampleOptions)(nil).SetEnabled), value)
+}
+
+// Enabled mocks base method
+func (m *MockDownsampleOptions) Enabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled
+func (mr *MockDownsampleOptionsMockRecorder) Enabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockDownsampleOptions)(nil).Enabled))
+}
+
+// SetAfter mocks base method
+func (m *MockDownsampleOptions) SetAfter(value time.Duration) DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAfter", value)
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// SetAfter indicates an expected call of SetAfter
+func (mr *MockDownsampleOptionsMockRecorder) SetAfter(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAfter", reflect.TypeOf((*MockDownsampleOptions)(nil).SetAfter), value)
+}
+
+// After mocks base method
+func (m *MockDownsampleOptions) After() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "After")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// After indicates an expected call of After
+func (mr *MockDownsampleOptionsMockRecorder) After() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "After", reflect.TypeOf((*MockDownsampleOptions)(nil).After))
+}
+
+// SetResolution mocks base method
+func (m *MockDownsampleOptions) SetResolution(value time.Duration) DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResolution", value)
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// SetResolution indicates an expected call of SetResolution
+func (mr *MockDownsampleOptionsMockRecorder) SetResolution(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResolution", reflect.TypeOf((*MockDownsampleOptions)(nil).SetResolution), value)
+}
+
+// Resolution mocks base method
+func (m *MockDownsampleOptions) Resolution() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolution")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Resolution indicates an expected call of Resolution
+func (mr *MockDownsampleOptionsMockRecorder) Resolution() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolution", reflect.TypeOf((*MockDownsampleOptions)(nil).Resolution))
+}
+
+// SetTargetNamespace mocks base method
+func (m *MockDownsampleOptions) SetTargetNamespace(value ident.ID) DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTargetNamespace", value)
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// SetTargetNamespace indicates an expected call of SetTargetNamespace
+func (mr *MockDownsampleOptionsMockRecorder) SetTargetNamespace(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTargetNamespace", reflect.TypeOf((*MockDownsampleOptions)(nil).SetTargetNamespace), value)
+}
+
+// TargetNamespace mocks base method
+func (m *MockDownsampleOptions) TargetNamespace() ident.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TargetNamespace")
+	ret0, _ := ret[0].(ident.ID)
+	return ret0
+}
+
+// TargetNamespace indicates an expected call of TargetNamespace
+func (mr *MockDownsampleOptionsMockRecorder) TargetNamespace() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TargetNamespace", reflect.TypeOf((*MockDownsampleOptions)(nil).TargetNamespace))
+}
+
+// SetAggregationTypes mocks base method
+func (m *MockDownsampleOptions) SetAggregationTypes(value aggregation.Types) DownsampleOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAggregationTypes", value)
+	ret0, _ := ret[0].(DownsampleOptions)
+	return ret0
+}
+
+// SetAggregationTypes indicates an expected call of SetAggregationTypes
+func (mr *MockDownsampleOptionsMockRecorder) SetAggregationTypes(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAggregationTypes", reflect.TypeOf((*MockDownsampleOptions)(nil).SetAggregationTypes), value)
+}
+
+// AggregationTypes mocks base method
+func (m *MockDownsampleOptions) AggregationTypes() aggregation.Types {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregationTypes")
+	ret0, _ := ret[0].(aggregation.Types)
+	return ret0
+}
+
+// AggregationTypes indicates an expected call of AggregationTypes
+func (mr *MockDownsampleOptionsMockRecorder) AggregationTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregationTypes", reflect.TypeOf((*MockDownsampleOptions)(nil).AggregationTypes))
+}
+
 // MockSchemaDescr is a mock of SchemaDescr interface
 type MockSchemaDescr struct {
 	ctrl     *gomock.Controller
@@ -23,6 +23,7 @@ package namespace
 import (
 	"errors"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
 )
 
@@ -47,25 +48,40 @@ const (
 
 	// Namespace with cold writes disabled by default.
 	defaultColdWritesEnabled = false
+
+	// Namespace with counter encoding disabled by default.
+	defaultCounterEncodingEnabled = false
 )
 
 var (
 	errIndexBlockSizePositive                       = errors.New("index block size must positive")
 	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
+	errTieringFilePathPrefixEmpty                   = errors.New("tiering file path prefix must be set")
+	errTieringColdAfterTooSmall                     = errors.New("tiering cold after must be >= namespace buffer past")
+	errTieringColdAfterTooLarge                     = errors.New("tiering cold after must be < namespace retention period")
+	errDownsampleTargetNamespaceEmpty               = errors.New("downsample target namespace must be set")
+	errDownsampleAfterTooSmall                      = errors.New("downsample after must be >= namespace buffer past")
+	errDownsampleAfterTooLarge                      = errors.New("downsample after must be < namespace retention period")
+	errDownsampleResolutionInvalid                  = errors.New("downsample resolution must be positive and divide the namespace block size")
+	errDownsampleAggregationTypesInvalid            = errors.New("downsample aggregation types must be set and valid for gauges")
 )
 
 type options struct {
-	bootstrapEnabled  bool
-	flushEnabled      bool
-	snapshotEnabled   bool
-	writesToCommitLog bool
-	cleanupEnabled    bool
-	repairEnabled     bool
-	coldWritesEnabled bool
-	retentionOpts     retention.Options
-	indexOpts         IndexOptions
-	schemaHis         SchemaHistory
+	bootstrapEnabled       bool
+	flushEnabled           bool
+	snapshotEnabled        bool
+	writesToCommitLog      bool
+	cleanupEnabled         bool
+	repairEnabled          bool
+	coldWritesEnabled      bool
+	retentionOpts          retention.Options
+	indexOpts              IndexOptions
+	schemaHis              SchemaHistory
+	tieringOpts            TieringOptions
+	dataCompression        compression.Type
+	downsampleOpts         DownsampleOptions
+	counterEncodingEnabled bool
 }
 
 // NewSchemaHistory returns an empty schema history.
@@ -76,16 +92,19 @@ func NewSchemaHistory() SchemaHistory {
 // NewOptions creates a new namespace options
 func NewOptions() Options {
 	return &options{
-		bootstrapEnabled:  defaultBootstrapEnabled,
-		flushEnabled:      defaultFlushEnabled,
-		snapshotEnabled:   defaultSnapshotEnabled,
-		writesToCommitLog: defaultWritesToCommitLog,
-		cleanupEnabled:    defaultCleanupEnabled,
-		repairEnabled:     defaultRepairEnabled,
-		coldWritesEnabled: defaultColdWritesEnabled,
-		retentionOpts:     retention.NewOptions(),
-		indexOpts:         NewIndexOptions(),
-		schemaHis:         NewSchemaHistory(),
+		bootstrapEnabled:       defaultBootstrapEnabled,
+		flushEnabled:           defaultFlushEnabled,
+		snapshotEnabled:        defaultSnapshotEnabled,
+		writesToCommitLog:      defaultWritesToCommitLog,
+		cleanupEnabled:         defaultCleanupEnabled,
+		repairEnabled:          defaultRepairEnabled,
+		coldWritesEnabled:      defaultColdWritesEnabled,
+		retentionOpts:          retention.NewOptions(),
+		indexOpts:              NewIndexOptions(),
+		schemaHis:              NewSchemaHistory(),
+		tieringOpts:            NewTieringOptions(),
+		downsampleOpts:         NewDownsampleOptions(),
+		counterEncodingEnabled: defaultCounterEncodingEnabled,
 	}
 }
 
@@ -93,6 +112,15 @@ func (o *options) Validate() error {
 	if err := o.retentionOpts.Validate(); err != nil {
 		return err
 	}
+	if err := o.validateTieringOptions(); err != nil {
+		return err
+	}
+	if err := o.dataCompression.Validate(); err != nil {
+		return err
+	}
+	if err := o.validateDownsampleOptions(); err != nil {
+		return err
+	}
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -124,7 +152,59 @@ func (o *options) Equal(value Options) bool {
 		o.coldWritesEnabled == value.ColdWritesEnabled() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
 		o.indexOpts.Equal(value.IndexOptions()) &&
-		o.schemaHis.Equal(value.SchemaHistory())
+		o.schemaHis.Equal(value.SchemaHistory()) &&
+		o.tieringOpts.Equal(value.TieringOptions()) &&
+		o.dataCompression == value.DataCompression() &&
+		o.downsampleOpts.Equal(value.DownsampleOptions()) &&
+		o.counterEncodingEnabled == value.CounterEncodingEnabled()
+}
+
+func (o *options) validateTieringOptions() error {
+	if !o.tieringOpts.Enabled() {
+		return nil
+	}
+	coldAfter := o.tieringOpts.ColdAfter()
+	if o.tieringOpts.FilePathPrefix() == "" {
+		return errTieringFilePathPrefixEmpty
+	}
+	// NB: blocks must not be moved while they can still be written to and
+	// flushed as warm writes.
+	if coldAfter < o.retentionOpts.BufferPast() {
+		return errTieringColdAfterTooSmall
+	}
+	if coldAfter >= o.retentionOpts.RetentionPeriod() {
+		return errTieringColdAfterTooLarge
+	}
+	return nil
+}
+
+func (o *options) validateDownsampleOptions() error {
+	if !o.downsampleOpts.Enabled() {
+		return nil
+	}
+	var (
+		after      = o.downsampleOpts.After()
+		resolution = o.downsampleOpts.Resolution()
+		aggTypes   = o.downsampleOpts.AggregationTypes()
+	)
+	if id := o.downsampleOpts.TargetNamespace(); id == nil || len(id.Bytes()) == 0 {
+		return errDownsampleTargetNamespaceEmpty
+	}
+	// NB: blocks must not be downsampled while they can still be written to
+	// and flushed as warm writes.
+	if after < o.retentionOpts.BufferPast() {
+		return errDownsampleAfterTooSmall
+	}
+	if after >= o.retentionOpts.RetentionPeriod() {
+		return errDownsampleAfterTooLarge
+	}
+	if resolution <= 0 || o.retentionOpts.BlockSize()%resolution != 0 {
+		return errDownsampleResolutionInvalid
+	}
+	if len(aggTypes) == 0 || !aggTypes.IsValidForGauge() {
+		return errDownsampleAggregationTypesInvalid
+	}
+	return nil
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -226,3 +306,43 @@ func (o *options) SetSchemaHistory(value SchemaHistory) Options {
 func (o *options) SchemaHistory() SchemaHistory {
 	return o.schemaHis
 }
+
+func (o *options) SetTieringOptions(value TieringOptions) Options {
+	opts := *o
+	opts.tieringOpts = value
+	return &opts
+}
+
+func (o *options) TieringOptions() TieringOptions {
+	return o.tieringOpts
+}
+
+func (o *options) SetDataCompression(value compression.Type) Options {
+	opts := *o
+	opts.dataCompression = value
+	return &opts
+}
+
+func (o *options) DataCompression() compression.Type {
+	return o.dataCompression
+}
+
+func (o *options) SetDownsampleOptions(value DownsampleOptions) Options {
+	opts := *o
+	opts.downsampleOpts = value
+	return &opts
+}
+
+func (o *options) DownsampleOptions() DownsampleOptions {
+	return o.downsampleOpts
+}
+
+func (o *options) SetCounterEncodingEnabled(value bool) Options {
+	opts := *o
+	opts.counterEncodingEnabled = value
+	return &opts
+}
+
+func (o *options) CounterEncodingEnabled() bool {
+	return o.counterEncodingEnabled
+}
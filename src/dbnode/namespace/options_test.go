@@ -25,7 +25,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -51,6 +54,44 @@ func TestOptionsEqualsIndexOpts(t *testing.T) {
 	require.False(t, o2.Equal(o1))
 }
 
+func TestOptionsEqualsTieringOpts(t *testing.T) {
+	o1 := NewOptions()
+	o2 := o1.SetTieringOptions(
+		o1.TieringOptions().SetEnabled(true))
+	require.True(t, o1.Equal(o1))
+	require.True(t, o2.Equal(o2))
+	require.False(t, o1.Equal(o2))
+	require.False(t, o2.Equal(o1))
+}
+
+func TestOptionsEqualsDataCompression(t *testing.T) {
+	o1 := NewOptions()
+	o2 := o1.SetDataCompression(compression.Snappy)
+	require.True(t, o1.Equal(o1))
+	require.True(t, o2.Equal(o2))
+	require.False(t, o1.Equal(o2))
+	require.False(t, o2.Equal(o1))
+}
+
+func TestOptionsEqualsCounterEncodingEnabled(t *testing.T) {
+	o1 := NewOptions()
+	o2 := o1.SetCounterEncodingEnabled(true)
+	require.True(t, o1.Equal(o1))
+	require.True(t, o2.Equal(o2))
+	require.False(t, o1.Equal(o2))
+	require.False(t, o2.Equal(o1))
+}
+
+func TestOptionsEqualsDownsampleOpts(t *testing.T) {
+	o1 := NewOptions()
+	o2 := o1.SetDownsampleOptions(
+		o1.DownsampleOptions().SetEnabled(true))
+	require.True(t, o1.Equal(o1))
+	require.True(t, o2.Equal(o2))
+	require.False(t, o1.Equal(o2))
+	require.False(t, o2.Equal(o1))
+}
+
 func TestOptionsEqualsSchema(t *testing.T) {
 	o1 := NewOptions()
 	s1, err := LoadSchemaHistory(testSchemaOptions)
@@ -180,3 +221,65 @@ func TestOptionsValidateNoIndexing(t *testing.T) {
 	rOpts.EXPECT().Validate().Return(nil)
 	require.NoError(t, o1.Validate())
 }
+
+func TestOptionsValidateTiering(t *testing.T) {
+	rOpts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute)
+	tOpts := NewTieringOptions().
+		SetEnabled(true).
+		SetColdAfter(24 * time.Hour).
+		SetFilePathPrefix("/var/lib/m3db-cold")
+	o1 := NewOptions().
+		SetRetentionOptions(rOpts).
+		SetTieringOptions(tOpts)
+	require.NoError(t, o1.Validate())
+
+	o2 := o1.SetTieringOptions(tOpts.SetFilePathPrefix(""))
+	require.Equal(t, errTieringFilePathPrefixEmpty, o2.Validate())
+
+	o3 := o1.SetTieringOptions(tOpts.SetColdAfter(time.Minute))
+	require.Equal(t, errTieringColdAfterTooSmall, o3.Validate())
+
+	o4 := o1.SetTieringOptions(tOpts.SetColdAfter(48 * time.Hour))
+	require.Equal(t, errTieringColdAfterTooLarge, o4.Validate())
+
+	o5 := o4.SetTieringOptions(o4.TieringOptions().SetEnabled(false))
+	require.NoError(t, o5.Validate())
+}
+
+func TestOptionsValidateDownsample(t *testing.T) {
+	rOpts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute)
+	dOpts := NewDownsampleOptions().
+		SetEnabled(true).
+		SetAfter(24 * time.Hour).
+		SetResolution(5 * time.Minute).
+		SetTargetNamespace(ident.StringID("metrics-5m"))
+	o1 := NewOptions().
+		SetRetentionOptions(rOpts).
+		SetDownsampleOptions(dOpts)
+	require.NoError(t, o1.Validate())
+
+	o2 := o1.SetDownsampleOptions(dOpts.SetTargetNamespace(nil))
+	require.Equal(t, errDownsampleTargetNamespaceEmpty, o2.Validate())
+
+	o3 := o1.SetDownsampleOptions(dOpts.SetAfter(time.Minute))
+	require.Equal(t, errDownsampleAfterTooSmall, o3.Validate())
+
+	o4 := o1.SetDownsampleOptions(dOpts.SetAfter(48 * time.Hour))
+	require.Equal(t, errDownsampleAfterTooLarge, o4.Validate())
+
+	o5 := o1.SetDownsampleOptions(dOpts.SetResolution(7 * time.Minute))
+	require.Equal(t, errDownsampleResolutionInvalid, o5.Validate())
+
+	o6 := o1.SetDownsampleOptions(dOpts.SetAggregationTypes(
+		aggregation.Types{aggregation.P99}))
+	require.Equal(t, errDownsampleAggregationTypesInvalid, o6.Validate())
+
+	o7 := o6.SetDownsampleOptions(o6.DownsampleOptions().SetEnabled(false))
+	require.NoError(t, o7.Validate())
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"time"
+)
+
+var (
+	// defaultTieringEnabled disables tiering by default.
+	defaultTieringEnabled = false
+)
+
+type tieringOpts struct {
+	enabled        bool
+	coldAfter      time.Duration
+	filePathPrefix string
+}
+
+// NewTieringOptions returns a new TieringOptions.
+func NewTieringOptions() TieringOptions {
+	return &tieringOpts{
+		enabled: defaultTieringEnabled,
+	}
+}
+
+func (t *tieringOpts) Equal(value TieringOptions) bool {
+	return t.Enabled() == value.Enabled() &&
+		t.ColdAfter() == value.ColdAfter() &&
+		t.FilePathPrefix() == value.FilePathPrefix()
+}
+
+func (t *tieringOpts) SetEnabled(value bool) TieringOptions {
+	to := *t
+	to.enabled = value
+	return &to
+}
+
+func (t *tieringOpts) Enabled() bool {
+	return t.enabled
+}
+
+func (t *tieringOpts) SetColdAfter(value time.Duration) TieringOptions {
+	to := *t
+	to.coldAfter = value
+	return &to
+}
+
+func (t *tieringOpts) ColdAfter() time.Duration {
+	return t.coldAfter
+}
+
+func (t *tieringOpts) SetFilePathPrefix(value string) TieringOptions {
+	to := *t
+	to.filePathPrefix = value
+	return &to
+}
+
+func (t *tieringOpts) FilePathPrefix() string {
+	return t.filePathPrefix
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieringOptionsEqual(t *testing.T) {
+	opts := NewTieringOptions()
+	require.True(t, opts.Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetEnabled(true).Equal(opts.SetEnabled(false)))
+	require.False(t, opts.SetColdAfter(time.Hour).Equal(
+		opts.SetColdAfter(time.Hour*2)))
+	require.False(t, opts.SetFilePathPrefix("/a").Equal(
+		opts.SetFilePathPrefix("/b")))
+}
+
+func TestTieringOptionsEnabled(t *testing.T) {
+	opts := NewTieringOptions()
+	require.False(t, opts.Enabled())
+	require.True(t, opts.SetEnabled(true).Enabled())
+	require.False(t, opts.SetEnabled(false).Enabled())
+}
+
+func TestTieringOptionsColdAfter(t *testing.T) {
+	opts := NewTieringOptions()
+	require.Equal(t, time.Hour, opts.SetColdAfter(time.Hour).ColdAfter())
+}
+
+func TestTieringOptionsFilePathPrefix(t *testing.T) {
+	opts := NewTieringOptions()
+	require.Equal(t, "/var/lib/m3db-cold", opts.SetFilePathPrefix("/var/lib/m3db-cold").FilePathPrefix())
+}
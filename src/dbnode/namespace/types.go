@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xclose "github.com/m3db/m3/src/x/close"
@@ -97,6 +99,34 @@ type Options interface {
 
 	// SchemaHistory returns the schema registry for this namespace.
 	SchemaHistory() SchemaHistory
+
+	// SetTieringOptions sets the TieringOptions.
+	SetTieringOptions(value TieringOptions) Options
+
+	// TieringOptions returns the TieringOptions.
+	TieringOptions() TieringOptions
+
+	// SetDataCompression sets the compression applied to series data when
+	// writing data filesets.
+	SetDataCompression(value compression.Type) Options
+
+	// DataCompression returns the compression applied to series data when
+	// writing data filesets.
+	DataCompression() compression.Type
+
+	// SetDownsampleOptions sets the DownsampleOptions.
+	SetDownsampleOptions(value DownsampleOptions) Options
+
+	// DownsampleOptions returns the DownsampleOptions.
+	DownsampleOptions() DownsampleOptions
+
+	// SetCounterEncodingEnabled sets whether series values in this namespace
+	// are encoded with the counter aware delta-of-delta value encoding scheme.
+	SetCounterEncodingEnabled(value bool) Options
+
+	// CounterEncodingEnabled returns whether series values in this namespace
+	// are encoded with the counter aware delta-of-delta value encoding scheme.
+	CounterEncodingEnabled() bool
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -117,6 +147,73 @@ type IndexOptions interface {
 	BlockSize() time.Duration
 }
 
+// TieringOptions controls moving the filesets of blocks that are no longer
+// hot from the primary file path prefix to a secondary, typically cheaper,
+// file path prefix. Filesets are read transparently from either location.
+type TieringOptions interface {
+	// Equal returns true if the provide value is equal to this one.
+	Equal(value TieringOptions) bool
+
+	// SetEnabled sets whether tiering is enabled.
+	SetEnabled(value bool) TieringOptions
+
+	// Enabled returns whether tiering is enabled.
+	Enabled() bool
+
+	// SetColdAfter sets how long after a block ends its filesets are moved
+	// to the secondary file path prefix.
+	SetColdAfter(value time.Duration) TieringOptions
+
+	// ColdAfter returns how long after a block ends its filesets are moved
+	// to the secondary file path prefix.
+	ColdAfter() time.Duration
+
+	// SetFilePathPrefix sets the secondary file path prefix.
+	SetFilePathPrefix(value string) TieringOptions
+
+	// FilePathPrefix returns the secondary file path prefix.
+	FilePathPrefix() string
+}
+
+// DownsampleOptions controls rewriting the data of blocks that have aged
+// past a threshold into another namespace at a coarser resolution.
+type DownsampleOptions interface {
+	// Equal returns true if the provide value is equal to this one.
+	Equal(value DownsampleOptions) bool
+
+	// SetEnabled sets whether downsampling is enabled.
+	SetEnabled(value bool) DownsampleOptions
+
+	// Enabled returns whether downsampling is enabled.
+	Enabled() bool
+
+	// SetAfter sets how long after a block ends its data is downsampled.
+	SetAfter(value time.Duration) DownsampleOptions
+
+	// After returns how long after a block ends its data is downsampled.
+	After() time.Duration
+
+	// SetResolution sets the resolution of the downsampled data.
+	SetResolution(value time.Duration) DownsampleOptions
+
+	// Resolution returns the resolution of the downsampled data.
+	Resolution() time.Duration
+
+	// SetTargetNamespace sets the namespace the downsampled data is written to.
+	SetTargetNamespace(value ident.ID) DownsampleOptions
+
+	// TargetNamespace returns the namespace the downsampled data is written to.
+	TargetNamespace() ident.ID
+
+	// SetAggregationTypes sets the aggregations applied to the datapoints
+	// of each resolution interval, each is written as a separate series.
+	SetAggregationTypes(value aggregation.Types) DownsampleOptions
+
+	// AggregationTypes returns the aggregations applied to the datapoints
+	// of each resolution interval, each is written as a separate series.
+	AggregationTypes() aggregation.Types
+}
+
 // SchemaDescr describes the schema for a complex type value.
 type SchemaDescr interface {
 	// DeployId returns the deploy id of the schema.
@@ -165,6 +165,37 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertDeleteSeriesRequest(t *testing.T) {
+	var (
+		ns    = ident.StringID("abc")
+		start = time.Now().Add(-900 * time.Hour)
+		end   = time.Now()
+	)
+	for _, pools := range []struct {
+		name string
+		pool convert.FetchTaggedConversionPools
+	}{
+		{"nil pools", nil},
+		{"valid pools", newTestPools()},
+	} {
+		t.Run(pools.name, func(t *testing.T) {
+			q, rpcQ := conjunctionQueryATestCase(t)
+			req, err := convert.ToRPCDeleteSeriesRequest(ns, index.Query{Query: q}, start, end)
+			require.NoError(t, err)
+			require.Equal(t, rpcQ, req.Query)
+			require.Equal(t, mustToRpcTime(t, start), req.RangeStart)
+			require.Equal(t, mustToRpcTime(t, end), req.RangeEnd)
+
+			id, observedQuery, observedStart, observedEnd, err := convert.FromRPCDeleteSeriesRequest(&req, pools.pool)
+			require.NoError(t, err)
+			require.Equal(t, ns.String(), id.String())
+			require.True(t, index.NewQueryMatcher(index.Query{Query: q}).Matches(observedQuery))
+			require.True(t, start.Equal(observedStart))
+			require.True(t, end.Equal(observedEnd))
+		})
+	}
+}
+
 func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	ns := ident.StringID("abc")
 	opts := index.AggregationOptions{
@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	idxconvert "github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -109,6 +110,8 @@ type serviceMetrics struct {
 	fetchBlocksMetadata     instrument.MethodMetrics
 	repair                  instrument.MethodMetrics
 	truncate                instrument.MethodMetrics
+	deleteSeries            instrument.MethodMetrics
+	backfill                instrument.MethodMetrics
 	fetchBatchRawRPCS       tally.Counter
 	fetchBatchRaw           instrument.BatchMethodMetrics
 	writeBatchRawRPCs       tally.Counter
@@ -129,6 +132,8 @@ func newServiceMetrics(scope tally.Scope, samplingRate float64) serviceMetrics {
 		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", samplingRate),
 		repair:                  instrument.NewMethodMetrics(scope, "repair", samplingRate),
 		truncate:                instrument.NewMethodMetrics(scope, "truncate", samplingRate),
+		deleteSeries:            instrument.NewMethodMetrics(scope, "deleteSeries", samplingRate),
+		backfill:                instrument.NewMethodMetrics(scope, "backfill", samplingRate),
 		fetchBatchRawRPCS:       scope.Counter("fetchBatchRaw-rpcs"),
 		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", samplingRate),
 		writeBatchRawRPCs:       scope.Counter("writeBatchRaw-rpcs"),
@@ -1731,6 +1736,121 @@ func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rp
 	return res, nil
 }
 
+func (s *service) DeleteSeries(tctx thrift.Context, req *rpc.DeleteSeriesRequest) (*rpc.DeleteSeriesResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	ns, query, start, end, err := convert.FromRPCDeleteSeriesRequest(req, s.pools)
+	if err != nil {
+		s.metrics.deleteSeries.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	deleted, err := db.DeleteSeries(ctx, ns, query, start, end)
+	if err != nil {
+		s.metrics.deleteSeries.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewDeleteSeriesResult_()
+	res.NumSeries = deleted
+
+	s.metrics.deleteSeries.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) Backfill(tctx thrift.Context, req *rpc.BackfillRequest) (*rpc.BackfillResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	blockStart, err := convert.ToTime(req.BlockStart, req.BlockStartTimeType)
+	if err != nil {
+		s.metrics.backfill.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	series, err := s.newBackfillSeries(ctx, req.Series)
+	if err != nil {
+		s.metrics.backfill.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	nsID := s.newID(ctx, req.NameSpace)
+	if err := db.Backfill(ctx, nsID, blockStart, series); err != nil {
+		s.metrics.backfill.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewBackfillResult_()
+	res.NumSeries = int64(len(series))
+
+	s.metrics.backfill.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) newBackfillSeries(
+	ctx context.Context,
+	elems []*rpc.BackfillSeries,
+) ([]storage.BackfillSeries, error) {
+	series := make([]storage.BackfillSeries, 0, len(elems))
+	for _, elem := range elems {
+		if elem == nil {
+			continue
+		}
+
+		id := s.newID(ctx, elem.ID)
+		var tags ident.Tags
+		if len(elem.EncodedTags) > 0 {
+			dec, err := s.newTagsDecoder(ctx, elem.EncodedTags)
+			if err != nil {
+				return nil, err
+			}
+			tags, err = idxconvert.TagsFromTagsIter(id, dec, s.pools.id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		datapoints := make([]storage.BackfillDatapoint, 0, len(elem.Datapoints))
+		for _, dp := range elem.Datapoints {
+			if dp == nil {
+				return nil, errRequiresDatapoint
+			}
+			unit, err := convert.ToUnit(dp.TimestampTimeType)
+			if err != nil {
+				return nil, err
+			}
+			d, err := unit.Value()
+			if err != nil {
+				return nil, err
+			}
+			datapoints = append(datapoints, storage.BackfillDatapoint{
+				Timestamp:  xtime.FromNormalizedTime(dp.Timestamp, d),
+				Value:      dp.Value,
+				Unit:       unit,
+				Annotation: dp.Annotation,
+			})
+		}
+
+		series = append(series, storage.BackfillSeries{
+			ID:         id,
+			Tags:       tags,
+			Datapoints: datapoints,
+		})
+	}
+	return series, nil
+}
+
 func (s *service) GetPersistRateLimit(
 	ctx thrift.Context,
 ) (*rpc.NodePersistRateLimitResult_, error) {
@@ -45,6 +45,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/serialize"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -2765,6 +2766,100 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceDeleteSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	end := start.Add(time.Hour)
+
+	nsID := "metrics"
+
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+
+	deleted := int64(2)
+
+	mockDB.EXPECT().DeleteSeries(
+		ctx,
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		start,
+		end,
+	).Return(deleted, nil)
+
+	rpcReq, err := convert.ToRPCDeleteSeriesRequest(ident.StringID(nsID), qry, start, end)
+	require.NoError(t, err)
+
+	r, err := service.DeleteSeries(tctx, &rpcReq)
+	require.NoError(t, err)
+	assert.Equal(t, deleted, r.NumSeries)
+}
+
+func TestServiceBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID       = "metrics"
+		blockStart = time.Now().Add(-24 * time.Hour).Truncate(2 * time.Hour)
+	)
+	mockDB.EXPECT().
+		Backfill(ctx, ident.NewIDMatcher(nsID), blockStart, gomock.Any()).
+		Do(func(_ context.Context, _ ident.ID, _ time.Time, series []storage.BackfillSeries) {
+			require.Len(t, series, 2)
+			assert.Equal(t, "foo", series[0].ID.String())
+			require.Len(t, series[0].Datapoints, 2)
+			assert.True(t, blockStart.Add(time.Minute).Equal(series[0].Datapoints[1].Timestamp))
+			assert.Equal(t, 2.0, series[0].Datapoints[1].Value)
+			assert.Equal(t, xtime.Second, series[0].Datapoints[1].Unit)
+			assert.Equal(t, "bar", series[1].ID.String())
+			assert.Empty(t, series[1].Datapoints)
+		}).
+		Return(nil)
+
+	r, err := service.Backfill(tctx, &rpc.BackfillRequest{
+		NameSpace:          []byte(nsID),
+		BlockStart:         blockStart.UnixNano(),
+		BlockStartTimeType: rpc.TimeType_UNIX_NANOSECONDS,
+		Series: []*rpc.BackfillSeries{
+			{
+				ID: []byte("foo"),
+				Datapoints: []*rpc.Datapoint{
+					{Timestamp: blockStart.Unix(), TimestampTimeType: rpc.TimeType_UNIX_SECONDS, Value: 1},
+					{Timestamp: blockStart.Add(time.Minute).Unix(), TimestampTimeType: rpc.TimeType_UNIX_SECONDS, Value: 2},
+				},
+			},
+			{
+				ID: []byte("bar"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), r.NumSeries)
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package compression defines the codecs used to compress persisted series
+// data.
+package compression
+
+import (
+	"fmt"
+)
+
+// Type is the codec used to compress series data persisted to disk, such as
+// the series data in the data files of a namespace's filesets.
+type Type uint8
+
+const (
+	// None stores series data as raw encoded streams.
+	None Type = iota
+
+	// Snappy compresses series data with snappy.
+	Snappy
+)
+
+var validTypes = []Type{
+	None,
+	Snappy,
+}
+
+// Validate validates that the compression type is valid.
+func (t Type) Validate() error {
+	if t >= None && t <= Snappy {
+		return nil
+	}
+
+	return fmt.Errorf("invalid compression type: '%v' valid types are: %v",
+		t, validTypes)
+}
+
+func (t Type) String() string {
+	switch t {
+	case None:
+		return "none"
+	case Snappy:
+		return "snappy"
+	default:
+		return fmt.Sprintf("unknown: %d", t)
+	}
+}
+
+// UnmarshalYAML unmarshals a stored compression type.
+func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		*t = None
+		return nil
+	}
+
+	for _, valid := range validTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid compression type: '%s' valid types are: %v",
+		str, validTypes)
+}
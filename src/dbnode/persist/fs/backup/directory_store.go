@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const tempFilePrefix = ".tmp-"
+
+var errInvalidKey = errors.New("invalid object key")
+
+type directoryObjectStore struct {
+	dir              string
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+}
+
+// NewDirectoryObjectStore returns an object store that keeps each object as
+// a file under a local directory, for use in tests or with a directory that
+// is mounted from a remote store.
+func NewDirectoryObjectStore(dir string) ObjectStore {
+	return &directoryObjectStore{
+		dir:              dir,
+		newFileMode:      defaultNewFileMode,
+		newDirectoryMode: defaultNewDirectoryMode,
+	}
+}
+
+func (s *directoryObjectStore) path(key string) (string, error) {
+	// NB: only accept clean relative keys so that objects can never be
+	// stored outside of the directory.
+	if key == "" || path.Clean("/" + key)[1:] != key {
+		return "", fmt.Errorf("%v: %s", errInvalidKey, key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *directoryObjectStore) Put(key string, r io.Reader) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), s.newDirectoryMode); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filePath, r, s.newFileMode)
+}
+
+// writeFileAtomic writes the contents of the reader to a temporary file that
+// is then renamed to the file path, so that a partially written file is never
+// visible at the file path.
+func writeFileAtomic(filePath string, r io.Reader, mode os.FileMode) error {
+	dir, base := filepath.Split(filePath)
+	f, err := ioutil.TempFile(dir, tempFilePrefix+base)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := f.Name()
+	if err := writeFile(f, r, mode); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+func writeFile(f *os.File, r io.Reader, mode os.FileMode) error {
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *directoryObjectStore) Get(key string) (io.ReadCloser, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *directoryObjectStore) Exists(key string) (bool, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (s *directoryObjectStore) List(prefix string) ([]string, error) {
+	// Only walk the deepest directory that all keys with the prefix share.
+	root := s.dir
+	if idx := strings.LastIndex(prefix, "/"); idx > 0 {
+		root = filepath.Join(s.dir, filepath.FromSlash(prefix[:idx]))
+	}
+
+	var keys []string
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
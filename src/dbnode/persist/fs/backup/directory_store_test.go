@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryObjectStorePutGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewDirectoryObjectStore(dir)
+
+	exists, err := store.Exists("a/b/c")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = store.Get("a/b/c")
+	require.Equal(t, ErrObjectNotFound, err)
+
+	require.NoError(t, store.Put("a/b/c", bytes.NewReader([]byte("foo"))))
+	require.NoError(t, store.Put("a/b/c", bytes.NewReader([]byte("bar"))))
+
+	exists, err = store.Exists("a/b/c")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	r, err := store.Get("a/b/c")
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "bar", string(b))
+
+	// No temporary files should be left behind.
+	infos, err := ioutil.ReadDir(filepath.Join(dir, "a", "b"))
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+}
+
+func TestDirectoryObjectStoreInvalidKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewDirectoryObjectStore(dir)
+	for _, key := range []string{"", "/a", "a/", "../a", "a/../b", "a//b"} {
+		require.Error(t, store.Put(key, bytes.NewReader(nil)), key)
+		_, err := store.Get(key)
+		require.Error(t, err, key)
+	}
+}
+
+func TestDirectoryObjectStoreList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewDirectoryObjectStore(dir)
+	for _, key := range []string{"a/b/c", "a/b/d", "a/bc", "a/e/f", "g"} {
+		require.NoError(t, store.Put(key, bytes.NewReader(nil)))
+	}
+
+	keys, err := store.List("a/b")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b/c", "a/b/d", "a/bc"}, keys)
+
+	keys, err = store.List("a/b/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b/c", "a/b/d"}, keys)
+
+	keys, err = store.List("")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b/c", "a/b/d", "a/bc", "a/e/f", "g"}, keys)
+
+	keys, err = store.List("x/")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
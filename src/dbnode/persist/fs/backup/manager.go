@@ -0,0 +1,270 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const checkpointFileSuffix = "checkpoint.db"
+
+type managerMetrics struct {
+	fileSets tally.Counter
+	files    tally.Counter
+	errors   tally.Counter
+	duration tally.Timer
+}
+
+func newManagerMetrics(scope tally.Scope) managerMetrics {
+	return managerMetrics{
+		fileSets: scope.Counter("filesets"),
+		files:    scope.Counter("files"),
+		errors:   scope.Counter("errors"),
+		duration: scope.Timer("duration"),
+	}
+}
+
+type manager struct {
+	sync.Mutex
+
+	opts    Options
+	store   ObjectStore
+	logger  *zap.Logger
+	metrics managerMetrics
+}
+
+// NewManager returns a new backup manager.
+func NewManager(opts Options) (Manager, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	return &manager{
+		opts:    opts,
+		store:   opts.ObjectStore(),
+		logger:  iOpts.Logger(),
+		metrics: newManagerMetrics(iOpts.MetricsScope().SubScope("backup")),
+	}, nil
+}
+
+func (m *manager) Backup(namespaces []namespace.Metadata) (Result, error) {
+	// NB: backups of the same filesets must never run concurrently.
+	m.Lock()
+	defer m.Unlock()
+
+	start := time.Now()
+	defer func() {
+		m.metrics.duration.Record(time.Since(start))
+	}()
+
+	var (
+		result         Result
+		multiErr       xerrors.MultiError
+		filePathPrefix = m.opts.FilePathPrefix()
+	)
+	for _, md := range namespaces {
+		nsID := md.ID()
+		// NB: filesets of tiered namespaces may only exist under the tiered
+		// file path prefix once they have been removed from the primary one.
+		for _, prefix := range fs.DataFilePathPrefixes(filePathPrefix, md.Options()) {
+			shards, err := subDirectories(fs.NamespaceDataDirPath(prefix, nsID))
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+
+			for _, dir := range shards {
+				shard, err := strconv.ParseUint(dir, 10, 32)
+				if err != nil {
+					// Not a shard directory.
+					continue
+				}
+
+				fileSets, err := fs.DataFiles(prefix, nsID, uint32(shard))
+				if err != nil {
+					multiErr = multiErr.Add(err)
+					continue
+				}
+
+				multiErr = m.backupFileSets(prefix, fileSets, &result, multiErr)
+			}
+		}
+
+		fileSets, err := fs.IndexFiles(filePathPrefix, nsID)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		multiErr = m.backupFileSets(filePathPrefix, fileSets, &result, multiErr)
+	}
+
+	if err := multiErr.FinalError(); err != nil {
+		m.metrics.errors.Inc(int64(multiErr.NumErrors()))
+		return result, err
+	}
+
+	if result.FileSets > 0 {
+		m.logger.Info("backed up filesets",
+			zap.Int("filesets", result.FileSets),
+			zap.Int("files", result.Files))
+	}
+
+	return result, nil
+}
+
+func (m *manager) backupFileSets(
+	filePathPrefix string,
+	fileSets fs.FileSetFilesSlice,
+	result *Result,
+	multiErr xerrors.MultiError,
+) xerrors.MultiError {
+	for _, fileSet := range fileSets {
+		files, err := m.backupFileSet(filePathPrefix, fileSet)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		if files > 0 {
+			result.FileSets++
+			result.Files += files
+			m.metrics.fileSets.Inc(1)
+			m.metrics.files.Inc(int64(files))
+		}
+	}
+
+	return multiErr
+}
+
+// backupFileSet uploads the files of a fileset if it is complete and has not
+// already been uploaded, returning the number of files uploaded. The
+// checkpoint file is uploaded last so that a fileset is only ever complete in
+// the object store once all of its files have been uploaded.
+func (m *manager) backupFileSet(
+	filePathPrefix string,
+	fileSet fs.FileSetFile,
+) (int, error) {
+	if !fileSet.HasCompleteCheckpointFile() {
+		return 0, nil
+	}
+
+	var (
+		checkpointPath string
+		filePaths      = make([]string, 0, len(fileSet.AbsoluteFilepaths))
+	)
+	for _, filePath := range fileSet.AbsoluteFilepaths {
+		if isCheckpointFile(filePath) {
+			checkpointPath = filePath
+			continue
+		}
+
+		filePaths = append(filePaths, filePath)
+	}
+
+	checkpointKey, err := m.key(filePathPrefix, checkpointPath)
+	if err != nil {
+		return 0, err
+	}
+
+	exists, err := m.store.Exists(checkpointKey)
+	if err != nil || exists {
+		return 0, err
+	}
+
+	for _, filePath := range append(filePaths, checkpointPath) {
+		if err := m.upload(filePathPrefix, filePath); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(filePaths) + 1, nil
+}
+
+func (m *manager) upload(filePathPrefix, filePath string) error {
+	key, err := m.key(filePathPrefix, filePath)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	return m.store.Put(key, f)
+}
+
+// key returns the object key of a file, which is its path relative to the
+// file path prefix it lives under, under the key prefix. Filesets that were
+// copied to the tiered file path prefix have the same key as the originals
+// so they are only backed up once and are restored to the primary prefix.
+func (m *manager) key(filePathPrefix, filePath string) (string, error) {
+	rel, err := filepath.Rel(filePathPrefix, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return objectKey(m.opts.KeyPrefix(), rel), nil
+}
+
+func objectKey(keyPrefix, relPath string) string {
+	return path.Join(keyPrefix, filepath.ToSlash(relPath))
+}
+
+func isCheckpointFile(filePath string) bool {
+	return strings.HasSuffix(filePath, checkpointFileSuffix)
+}
+
+func subDirectories(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+
+	return names, nil
+}
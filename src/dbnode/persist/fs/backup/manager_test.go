@@ -0,0 +1,253 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFileSet struct {
+	dir        string
+	blockStart time.Time
+	volume     int
+	complete   bool
+}
+
+func (f testFileSet) create(t *testing.T) {
+	require.NoError(t, os.MkdirAll(f.dir, 0755))
+	for _, suffix := range []string{"info", "data"} {
+		createTestFile(t, f.filePath(suffix), []byte(suffix))
+	}
+
+	checkpoint := make([]byte, fs.CheckpointFileSizeBytes)
+	if !f.complete {
+		checkpoint = nil
+	}
+	createTestFile(t, f.filePath("checkpoint"), checkpoint)
+}
+
+func (f testFileSet) filePath(suffix string) string {
+	return filepath.Join(f.dir, fmt.Sprintf("fileset-%d-%d-%s.db",
+		f.blockStart.UnixNano(), f.volume, suffix))
+}
+
+func createTestFile(t *testing.T, filePath string, b []byte) {
+	require.NoError(t, ioutil.WriteFile(filePath, b, 0666))
+}
+
+func newTestOptions(t *testing.T) (Options, func()) {
+	storeDir, err := ioutil.TempDir("", "backup-store")
+	require.NoError(t, err)
+	filePathPrefix, err := ioutil.TempDir("", "backup-data")
+	require.NoError(t, err)
+
+	opts := NewOptions().
+		SetObjectStore(NewDirectoryObjectStore(storeDir)).
+		SetKeyPrefix("host1").
+		SetFilePathPrefix(filePathPrefix)
+	return opts, func() {
+		os.RemoveAll(storeDir)
+		os.RemoveAll(filePathPrefix)
+	}
+}
+
+func TestManagerBackupAndRestore(t *testing.T) {
+	opts, cleanup := newTestOptions(t)
+	defer cleanup()
+
+	var (
+		ns         = ident.StringID("metrics")
+		prefix     = opts.FilePathPrefix()
+		blockStart = time.Now().Truncate(2 * time.Hour)
+		fileSets   = []testFileSet{
+			{
+				dir:        fs.ShardDataDirPath(prefix, ns, 0),
+				blockStart: blockStart,
+				complete:   true,
+			},
+			{
+				dir:        fs.ShardDataDirPath(prefix, ns, 1),
+				blockStart: blockStart,
+				volume:     1,
+				complete:   true,
+			},
+			{
+				dir:        fs.ShardDataDirPath(prefix, ns, 1),
+				blockStart: blockStart.Add(2 * time.Hour),
+				complete:   true,
+			},
+			{
+				// Incomplete filesets are not backed up.
+				dir:        fs.ShardDataDirPath(prefix, ns, 1),
+				blockStart: blockStart.Add(4 * time.Hour),
+			},
+			{
+				dir:        fs.NamespaceIndexDataDirPath(prefix, ns),
+				blockStart: blockStart,
+				complete:   true,
+			},
+		}
+	)
+	for _, fileSet := range fileSets {
+		fileSet.create(t)
+	}
+
+	md, err := namespace.NewMetadata(ns, namespace.NewOptions())
+	require.NoError(t, err)
+
+	mgr, err := NewManager(opts)
+	require.NoError(t, err)
+
+	result, err := mgr.Backup([]namespace.Metadata{md})
+	require.NoError(t, err)
+	require.Equal(t, Result{FileSets: 4, Files: 12}, result)
+
+	// Filesets that were already backed up are skipped.
+	result, err = mgr.Backup([]namespace.Metadata{md})
+	require.NoError(t, err)
+	require.Equal(t, Result{}, result)
+
+	keys, err := opts.ObjectStore().List("host1/")
+	require.NoError(t, err)
+	require.Len(t, keys, 12)
+	require.Contains(t, keys, fmt.Sprintf(
+		"host1/data/metrics/1/fileset-%d-1-checkpoint.db", blockStart.UnixNano()))
+	require.Contains(t, keys, fmt.Sprintf(
+		"host1/index/data/metrics/fileset-%d-0-data.db", blockStart.UnixNano()))
+
+	// Restore the first block into a new file path prefix.
+	restoreDir, err := ioutil.TempDir("", "backup-restore")
+	require.NoError(t, err)
+	defer os.RemoveAll(restoreDir)
+
+	restoreOpts := opts.SetFilePathPrefix(restoreDir)
+	result, err = Restore(restoreOpts, ns, blockStart, blockStart.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, Result{FileSets: 3, Files: 9}, result)
+
+	for _, fileSet := range fileSets[:2] {
+		restored := fileSet
+		restored.dir = filepath.Join(restoreDir, mustRel(t, prefix, fileSet.dir))
+		for _, suffix := range []string{"info", "data"} {
+			b, err := ioutil.ReadFile(restored.filePath(suffix))
+			require.NoError(t, err)
+			require.Equal(t, suffix, string(b))
+		}
+
+		exists, err := fs.CompleteCheckpointFileExists(restored.filePath("checkpoint"))
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	dataFiles, err := fs.DataFiles(restoreDir, ns, 1)
+	require.NoError(t, err)
+	require.Len(t, dataFiles, 1)
+
+	indexFiles, err := fs.IndexFiles(restoreDir, ns)
+	require.NoError(t, err)
+	require.Len(t, indexFiles, 1)
+	require.True(t, indexFiles[0].HasCompleteCheckpointFile())
+
+	// Filesets that are already complete on disk are skipped.
+	result, err = Restore(restoreOpts, ns, blockStart, blockStart.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, Result{}, result)
+}
+
+func TestManagerBackupTieredFileSets(t *testing.T) {
+	opts, cleanup := newTestOptions(t)
+	defer cleanup()
+
+	tieredDir, err := ioutil.TempDir("", "backup-tiered")
+	require.NoError(t, err)
+	defer os.RemoveAll(tieredDir)
+
+	var (
+		ns         = ident.StringID("metrics")
+		blockStart = time.Now().Truncate(2 * time.Hour)
+		fileSets   = []testFileSet{
+			{
+				dir:        fs.ShardDataDirPath(opts.FilePathPrefix(), ns, 0),
+				blockStart: blockStart,
+				complete:   true,
+			},
+			{
+				// Filesets only under the tiered file path prefix.
+				dir:        fs.ShardDataDirPath(tieredDir, ns, 0),
+				blockStart: blockStart.Add(-2 * time.Hour),
+				complete:   true,
+			},
+			{
+				// Filesets copied to the tiered file path prefix are only
+				// backed up once.
+				dir:        fs.ShardDataDirPath(tieredDir, ns, 0),
+				blockStart: blockStart,
+				complete:   true,
+			},
+		}
+	)
+	for _, fileSet := range fileSets {
+		fileSet.create(t)
+	}
+
+	md, err := namespace.NewMetadata(ns, namespace.NewOptions().
+		SetTieringOptions(namespace.NewTieringOptions().
+			SetEnabled(true).
+			SetColdAfter(24*time.Hour).
+			SetFilePathPrefix(tieredDir)))
+	require.NoError(t, err)
+
+	mgr, err := NewManager(opts)
+	require.NoError(t, err)
+
+	result, err := mgr.Backup([]namespace.Metadata{md})
+	require.NoError(t, err)
+	require.Equal(t, Result{FileSets: 2, Files: 6}, result)
+
+	for _, start := range []time.Time{blockStart, blockStart.Add(-2 * time.Hour)} {
+		exists, err := opts.ObjectStore().Exists(fmt.Sprintf(
+			"host1/data/metrics/0/fileset-%d-0-checkpoint.db", start.UnixNano()))
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+}
+
+func TestNewManagerInvalidOptions(t *testing.T) {
+	_, err := NewManager(NewOptions())
+	require.Equal(t, errNoObjectStore, err)
+}
+
+func mustRel(t *testing.T, base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	require.NoError(t, err)
+	return rel
+}
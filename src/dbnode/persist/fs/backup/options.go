@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"errors"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultNewFileMode      = os.FileMode(0666)
+	defaultNewDirectoryMode = os.ModeDir | os.FileMode(0755)
+)
+
+var (
+	errNoObjectStore    = errors.New("no object store set")
+	errNoFilePathPrefix = errors.New("no file path prefix set")
+)
+
+type options struct {
+	objectStore      ObjectStore
+	keyPrefix        string
+	filePathPrefix   string
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+	instrumentOpts   instrument.Options
+}
+
+// NewOptions returns a new set of backup options.
+func NewOptions() Options {
+	return &options{
+		filePathPrefix:   fs.NewOptions().FilePathPrefix(),
+		newFileMode:      defaultNewFileMode,
+		newDirectoryMode: defaultNewDirectoryMode,
+		instrumentOpts:   instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.objectStore == nil {
+		return errNoObjectStore
+	}
+	if o.filePathPrefix == "" {
+		return errNoFilePathPrefix
+	}
+	return nil
+}
+
+func (o *options) SetObjectStore(value ObjectStore) Options {
+	opts := *o
+	opts.objectStore = value
+	return &opts
+}
+
+func (o *options) ObjectStore() ObjectStore {
+	return o.objectStore
+}
+
+func (o *options) SetKeyPrefix(value string) Options {
+	opts := *o
+	opts.keyPrefix = value
+	return &opts
+}
+
+func (o *options) KeyPrefix() string {
+	return o.keyPrefix
+}
+
+func (o *options) SetFilePathPrefix(value string) Options {
+	opts := *o
+	opts.filePathPrefix = value
+	return &opts
+}
+
+func (o *options) FilePathPrefix() string {
+	return o.filePathPrefix
+}
+
+func (o *options) SetNewFileMode(value os.FileMode) Options {
+	opts := *o
+	opts.newFileMode = value
+	return &opts
+}
+
+func (o *options) NewFileMode() os.FileMode {
+	return o.newFileMode
+}
+
+func (o *options) SetNewDirectoryMode(value os.FileMode) Options {
+	opts := *o
+	opts.newDirectoryMode = value
+	return &opts
+}
+
+func (o *options) NewDirectoryMode() os.FileMode {
+	return o.newDirectoryMode
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
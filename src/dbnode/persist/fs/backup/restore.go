@@ -0,0 +1,234 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+
+	"go.uber.org/zap"
+)
+
+type fileSetKey struct {
+	dir         string
+	blockStart  time.Time
+	volumeIndex int
+}
+
+type fileSetKeys struct {
+	fileSetKey
+	keys          []string
+	checkpointKey string
+}
+
+type timeAndVolumeIndexFn func(fname string) (time.Time, int, error)
+
+// Restore downloads the complete filesets of a namespace with block starts in
+// [start, end) from the object store to disk, skipping filesets that are
+// already complete on disk. Checkpoint files are written last so that a
+// partially restored fileset is never considered complete.
+func Restore(
+	opts Options,
+	namespace ident.ID,
+	start, end time.Time,
+) (Result, error) {
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	var (
+		result Result
+		logger = opts.InstrumentOptions().Logger()
+		dirs   = []struct {
+			dir  string
+			fn   timeAndVolumeIndexFn
+			desc string
+		}{
+			{
+				dir:  fs.NamespaceDataDirPath("", namespace),
+				fn:   fs.TimeAndVolumeIndexFromDataFileSetFilename,
+				desc: "data",
+			},
+			{
+				dir:  fs.NamespaceIndexDataDirPath("", namespace),
+				fn:   fs.TimeAndVolumeIndexFromFileSetFilename,
+				desc: "index",
+			},
+		}
+	)
+	for _, d := range dirs {
+		fileSets, err := listFileSets(opts, d.dir, d.fn)
+		if err != nil {
+			return result, err
+		}
+
+		for _, fileSet := range fileSets {
+			blockStart := fileSet.blockStart
+			if blockStart.Before(start) || !blockStart.Before(end) {
+				continue
+			}
+
+			files, err := restoreFileSet(opts, fileSet)
+			if err != nil {
+				return result, err
+			}
+			if files == 0 {
+				continue
+			}
+
+			logger.Debug("restored fileset",
+				zap.String("type", d.desc),
+				zap.String("dir", fileSet.dir),
+				zap.Time("blockStart", blockStart),
+				zap.Int("volumeIndex", fileSet.volumeIndex))
+			result.FileSets++
+			result.Files += files
+		}
+	}
+
+	return result, nil
+}
+
+// listFileSets lists the filesets under a directory relative to the file
+// path prefix in the object store that have a checkpoint file.
+func listFileSets(
+	opts Options,
+	relDir string,
+	fn timeAndVolumeIndexFn,
+) ([]fileSetKeys, error) {
+	keys, err := opts.ObjectStore().List(objectKey(opts.KeyPrefix(), relDir) + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	byFileSet := make(map[fileSetKey]*fileSetKeys)
+	for _, key := range keys {
+		blockStart, volumeIndex, err := fn(key)
+		if err != nil {
+			// Not a fileset file.
+			continue
+		}
+
+		k := fileSetKey{
+			dir:         path.Dir(key),
+			blockStart:  blockStart,
+			volumeIndex: volumeIndex,
+		}
+		fileSet, ok := byFileSet[k]
+		if !ok {
+			fileSet = &fileSetKeys{fileSetKey: k}
+			byFileSet[k] = fileSet
+		}
+
+		if isCheckpointFile(key) {
+			fileSet.checkpointKey = key
+			continue
+		}
+
+		fileSet.keys = append(fileSet.keys, key)
+	}
+
+	fileSets := make([]fileSetKeys, 0, len(byFileSet))
+	for _, fileSet := range byFileSet {
+		// Filesets without a checkpoint file were only partially uploaded.
+		if fileSet.checkpointKey == "" {
+			continue
+		}
+
+		fileSets = append(fileSets, *fileSet)
+	}
+
+	sort.Slice(fileSets, func(i, j int) bool {
+		a, b := fileSets[i], fileSets[j]
+		if a.dir != b.dir {
+			return a.dir < b.dir
+		}
+		if !a.blockStart.Equal(b.blockStart) {
+			return a.blockStart.Before(b.blockStart)
+		}
+		return a.volumeIndex < b.volumeIndex
+	})
+
+	return fileSets, nil
+}
+
+func restoreFileSet(opts Options, fileSet fileSetKeys) (int, error) {
+	checkpointPath, err := localPath(opts, fileSet.checkpointKey)
+	if err != nil {
+		return 0, err
+	}
+
+	exists, err := fs.CompleteCheckpointFileExists(checkpointPath)
+	if err != nil || exists {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(checkpointPath), opts.NewDirectoryMode()); err != nil {
+		return 0, err
+	}
+
+	for _, key := range append(fileSet.keys, fileSet.checkpointKey) {
+		if err := download(opts, key); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(fileSet.keys) + 1, nil
+}
+
+func download(opts Options, key string) error {
+	filePath, err := localPath(opts, key)
+	if err != nil {
+		return err
+	}
+
+	r, err := opts.ObjectStore().Get(key)
+	if err != nil {
+		return fmt.Errorf("unable to get object %s: %v", key, err)
+	}
+
+	defer r.Close()
+
+	return writeFileAtomic(filePath, r, opts.NewFileMode())
+}
+
+// localPath returns the path on disk of the file with the given object key.
+func localPath(opts Options, key string) (string, error) {
+	rel := key
+	if keyPrefix := opts.KeyPrefix(); keyPrefix != "" {
+		prefix := path.Clean(keyPrefix) + "/"
+		if !strings.HasPrefix(key, prefix) {
+			return "", fmt.Errorf("object key %s does not have key prefix %s", key, keyPrefix)
+		}
+
+		rel = strings.TrimPrefix(key, prefix)
+	}
+
+	return filepath.Join(opts.FilePathPrefix(), filepath.FromSlash(rel)), nil
+}
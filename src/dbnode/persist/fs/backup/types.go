@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package backup uploads complete filesets to an object store and restores
+// them back to disk.
+package backup
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// ErrObjectNotFound is returned when an object does not exist in the object
+// store.
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore is a store of objects identified by slash separated keys.
+type ObjectStore interface {
+	// Put stores the contents of the reader as the object with the given key,
+	// replacing any existing object.
+	Put(key string, r io.Reader) error
+
+	// Get returns a reader of the object with the given key, or
+	// ErrObjectNotFound if it does not exist. The reader must be closed.
+	Get(key string) (io.ReadCloser, error)
+
+	// Exists returns whether the object with the given key exists.
+	Exists(key string) (bool, error)
+
+	// List returns the keys of all objects with the given key prefix.
+	List(prefix string) ([]string, error)
+}
+
+// Result is the result of backing up or restoring filesets.
+type Result struct {
+	// FileSets is the number of filesets transferred.
+	FileSets int
+
+	// Files is the number of files transferred.
+	Files int
+}
+
+// Manager backs up complete filesets to an object store.
+type Manager interface {
+	// Backup uploads the complete data and index filesets of the namespaces
+	// that have not already been uploaded to the object store, including
+	// data filesets under the tiered file path prefix of a namespace.
+	Backup(namespaces []namespace.Metadata) (Result, error)
+}
+
+// Options are the options for backing up and restoring filesets.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetObjectStore sets the object store filesets are backed up to.
+	SetObjectStore(value ObjectStore) Options
+
+	// ObjectStore returns the object store filesets are backed up to.
+	ObjectStore() ObjectStore
+
+	// SetKeyPrefix sets the prefix of the keys of backed up files, which
+	// should be unique to each host since the filesets of replicas differ.
+	SetKeyPrefix(value string) Options
+
+	// KeyPrefix returns the prefix of the keys of backed up files.
+	KeyPrefix() string
+
+	// SetFilePathPrefix sets the file path prefix of the filesets.
+	SetFilePathPrefix(value string) Options
+
+	// FilePathPrefix returns the file path prefix of the filesets.
+	FilePathPrefix() string
+
+	// SetNewFileMode sets the mode of restored files.
+	SetNewFileMode(value os.FileMode) Options
+
+	// NewFileMode returns the mode of restored files.
+	NewFileMode() os.FileMode
+
+	// SetNewDirectoryMode sets the mode of directories of restored files.
+	SetNewDirectoryMode(value os.FileMode) Options
+
+	// NewDirectoryMode returns the mode of directories of restored files.
+	NewDirectoryMode() os.FileMode
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
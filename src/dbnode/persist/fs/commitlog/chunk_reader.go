@@ -22,9 +22,12 @@ package commitlog
 
 import (
 	"bufio"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 )
 
 const (
@@ -36,11 +39,19 @@ const (
 	checksumDataEnd   = checksumDataStart + chunkHeaderChecksumDataLen
 )
 
+var errCommitLogReaderCompressionChangeMidChunk = errors.New("commit log reader compression changed in the middle of a chunk")
+
 type chunkReader struct {
 	fd        *os.File
 	buffer    *bufio.Reader
 	remaining int
 	charBuff  []byte
+
+	compression  compression.Type
+	compressed   []byte
+	decompressed []byte
+	// chunk is the unread part of the current chunk once decompressed.
+	chunk []byte
 }
 
 func newChunkReader(bufferLen int) *chunkReader {
@@ -54,6 +65,24 @@ func (r *chunkReader) reset(fd *os.File) {
 	r.fd = fd
 	r.buffer.Reset(fd)
 	r.remaining = 0
+	r.compression = compression.None
+	r.chunk = nil
+}
+
+// setCompression sets the compression of the chunks after the current one,
+// which must have been read in full.
+func (r *chunkReader) setCompression(value compression.Type) error {
+	if err := value.Validate(); err != nil {
+		return err
+	}
+	if value == r.compression {
+		return nil
+	}
+	if r.remaining != 0 {
+		return errCommitLogReaderCompressionChangeMidChunk
+	}
+	r.compression = value
+	return nil
 }
 
 func (r *chunkReader) readHeader() error {
@@ -80,6 +109,10 @@ func (r *chunkReader) readHeader() error {
 		return err
 	}
 
+	if r.compression != compression.None {
+		return r.readCompressedChunk(int(size), checksumData)
+	}
+
 	// Verify data checksum
 	data, err := r.buffer.Peek(int(size))
 	if err != nil {
@@ -96,6 +129,44 @@ func (r *chunkReader) readHeader() error {
 	return nil
 }
 
+func (r *chunkReader) readCompressedChunk(size int, checksumData uint32) error {
+	// Compressed chunks can be larger than the read buffer when the data
+	// does not compress well, so they are read out in full instead of peeked.
+	r.compressed = resizeBufferOrGrowIfNeeded(r.compressed, size)
+	if _, err := io.ReadFull(r.buffer, r.compressed); err != nil {
+		return err
+	}
+
+	// Verify data checksum
+	if digest.Checksum(r.compressed) != checksumData {
+		return errCommitLogReaderChunkSizeChecksumMismatch
+	}
+
+	decompressed, err := decompressChunk(r.compression, r.decompressed, r.compressed)
+	if err != nil {
+		return err
+	}
+
+	// Set remaining data to be consumed
+	r.decompressed = decompressed
+	r.chunk = decompressed
+	r.remaining = len(decompressed)
+
+	return nil
+}
+
+// readChunk reads from the current chunk, p must not be larger than the
+// remaining data of the chunk.
+func (r *chunkReader) readChunk(p []byte) (int, error) {
+	if r.compression == compression.None {
+		return r.buffer.Read(p)
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
 func (r *chunkReader) Read(p []byte) (int, error) {
 	size := len(p)
 	read := 0
@@ -103,7 +174,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 	if r.remaining < size {
 		// Copy any remaining
 		if r.remaining > 0 {
-			n, err := r.buffer.Read(p[:r.remaining])
+			n, err := r.readChunk(p[:r.remaining])
 			r.remaining -= n
 			read += n
 			if err != nil {
@@ -125,7 +196,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 		return read, err
 	}
 
-	n, err := r.buffer.Read(p)
+	n, err := r.readChunk(p)
 	r.remaining -= n
 	read += n
 	return read, err
@@ -30,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/context"
@@ -613,3 +614,31 @@ func (mr *MockOptionsMockRecorder) IdentifierPool() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdentifierPool", reflect.TypeOf((*MockOptions)(nil).IdentifierPool))
 }
+
+// SetChunkCompression mocks base method
+func (m *MockOptions) SetChunkCompression(value compression.Type) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChunkCompression", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetChunkCompression indicates an expected call of SetChunkCompression
+func (mr *MockOptionsMockRecorder) SetChunkCompression(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChunkCompression", reflect.TypeOf((*MockOptions)(nil).SetChunkCompression), value)
+}
+
+// ChunkCompression mocks base method
+func (m *MockOptions) ChunkCompression() compression.Type {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChunkCompression")
+	ret0, _ := ret[0].(compression.Type)
+	return ret0
+}
+
+// ChunkCompression indicates an expected call of ChunkCompression
+func (mr *MockOptionsMockRecorder) ChunkCompression() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChunkCompression", reflect.TypeOf((*MockOptions)(nil).ChunkCompression))
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/bitset"
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/context"
@@ -373,6 +374,57 @@ func TestCommitLogWrite(t *testing.T) {
 	assertCommitLogWritesByIterating(t, commitLog, writes)
 }
 
+func TestCommitLogWriteCompressed(t *testing.T) {
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteWait,
+	})
+	opts = opts.SetChunkCompression(compression.Snappy)
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+
+	// Write enough to span several chunks.
+	var writes []testWrite
+	for i := 0; i < 500; i++ {
+		tags := ident.NewTags(ident.StringTag("name", fmt.Sprintf("val%d", i%10)))
+		series := testSeries(uint64(i%10), fmt.Sprintf("foo.bar.%d", i%10), tags, uint32(i%10))
+		writes = append(writes, testWrite{series, time.Now(), float64(i), xtime.Second, nil, nil})
+	}
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+	require.NoError(t, commitLog.Close())
+
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
+func TestCommitLogReadCompressedAndUncompressed(t *testing.T) {
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteWait,
+	})
+	defer cleanup(t, opts)
+
+	// Write one set of commit logs uncompressed and another compressed, as
+	// happens when compression is enabled on a node with existing commit
+	// logs, and make sure both can be read back.
+	var writes []testWrite
+	for i, compressionType := range []compression.Type{
+		compression.None,
+		compression.Snappy,
+	} {
+		commitLog, err := NewCommitLog(opts.SetChunkCompression(compressionType))
+		require.NoError(t, err)
+		require.NoError(t, commitLog.Open())
+		batch := []testWrite{
+			{testSeries(uint64(i), fmt.Sprintf("foo.bar.%d", i), ident.NewTags(ident.StringTag("name", "val")), 127), time.Now(), 123.456, xtime.Second, []byte{1, 2, 3}, nil},
+		}
+		writeCommitLogs(t, scope, commitLog, batch).Wait()
+		require.NoError(t, commitLog.Close())
+		writes = append(writes, batch...)
+	}
+
+	commitLog := &commitLog{opts: opts}
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
 func TestReadCommitLogMissingMetadata(t *testing.T) {
 	readConc := 4
 	// Make sure we're not leaking goroutines
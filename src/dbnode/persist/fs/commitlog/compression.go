@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+
+	"github.com/golang/snappy"
+)
+
+// Chunks are compressed one at a time after the writer has buffered up a
+// batch of log entries, the chunk header size and checksum refer to the
+// compressed bytes so corruption is detected before decompressing.
+
+// compressChunk returns the compressed form of src, reusing dst if it has
+// enough capacity.
+func compressChunk(
+	compressionType compression.Type,
+	dst []byte,
+	src []byte,
+) ([]byte, error) {
+	switch compressionType {
+	case compression.None:
+		return append(dst[:0], src...), nil
+	case compression.Snappy:
+		return snappy.Encode(dst[:cap(dst)], src), nil
+	default:
+		return nil, errUnknownChunkCompression(compressionType)
+	}
+}
+
+// decompressChunk returns the decompressed form of src, reusing dst if it
+// has enough capacity.
+func decompressChunk(
+	compressionType compression.Type,
+	dst []byte,
+	src []byte,
+) ([]byte, error) {
+	switch compressionType {
+	case compression.None:
+		return append(dst[:0], src...), nil
+	case compression.Snappy:
+		n, err := snappy.DecodedLen(src)
+		if err != nil {
+			return nil, err
+		}
+		return snappy.Decode(resizeBufferOrGrowIfNeeded(dst, n), src)
+	default:
+		return nil, errUnknownChunkCompression(compressionType)
+	}
+}
+
+func errUnknownChunkCompression(compressionType compression.Type) error {
+	return fmt.Errorf("unknown commit log chunk compression: %v", compressionType)
+}
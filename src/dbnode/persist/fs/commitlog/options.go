@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
@@ -81,6 +82,7 @@ type options struct {
 	bytesPool               pool.CheckedBytesPool
 	identPool               ident.Pool
 	readConcurrency         int
+	chunkCompression        compression.Type
 }
 
 // NewOptions creates new commit log options
@@ -118,6 +120,10 @@ func (o *options) Validate() error {
 		return errReadConcurrencyPositive
 	}
 
+	if err := o.ChunkCompression().Validate(); err != nil {
+		return err
+	}
+
 	if float64(o.BacklogQueueSize())/float64(o.BacklogQueueChannelSize()) > MaximumQueueSizeQueueChannelSizeRatio {
 		return fmt.Errorf(
 			"BacklogQueueSize / BacklogQueueChannelSize ratio must be at most: %f, but was: %f",
@@ -246,3 +252,13 @@ func (o *options) SetIdentifierPool(value ident.Pool) Options {
 func (o *options) IdentifierPool() ident.Pool {
 	return o.identPool
 }
+
+func (o *options) SetChunkCompression(value compression.Type) Options {
+	opts := *o
+	opts.chunkCompression = value
+	return &opts
+}
+
+func (o *options) ChunkCompression() compression.Type {
+	return o.chunkCompression
+}
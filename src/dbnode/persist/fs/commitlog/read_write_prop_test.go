@@ -33,6 +33,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/serialize"
 
@@ -700,6 +701,10 @@ func (c *corruptingChunkWriter) reset(f xos.File) {
 		f, c.corruptionProbability, c.seed)
 }
 
+func (c *corruptingChunkWriter) setCompression(value compression.Type) {
+	c.chunkWriter.setCompression(value)
+}
+
 func (c *corruptingChunkWriter) Write(p []byte) (int, error) {
 	return c.chunkWriter.Write(p)
 }
@@ -134,6 +134,13 @@ func (r *reader) Open(filePath string) (int64, error) {
 		return 0, err
 	}
 
+	// Commit logs written before chunk compression was added decode with
+	// no compression and so are read as before.
+	if err := r.chunkReader.setCompression(info.ChunkCompression); err != nil {
+		r.Close()
+		return 0, err
+	}
+
 	r.fileReadID = commitLogFileReadCounter.Inc()
 
 	index := info.Index
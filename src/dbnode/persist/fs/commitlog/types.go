@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/x/context"
@@ -214,6 +215,16 @@ type Options interface {
 
 	// IdentifierPool returns the IdentifierPool to use for pooling identifiers.
 	IdentifierPool() ident.Pool
+
+	// SetChunkCompression sets the compression of the chunks written to new
+	// commit log files, commit logs are read with the compression recorded
+	// in their header regardless of this setting.
+	SetChunkCompression(value compression.Type) Options
+
+	// ChunkCompression returns the compression of the chunks written to new
+	// commit log files, commit logs are read with the compression recorded
+	// in their header regardless of this setting.
+	ChunkCompression() compression.Type
 }
 
 // FileFilterInfo contains information about a commitog file that can be used to
@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
@@ -89,6 +90,7 @@ type chunkWriter interface {
 	io.Writer
 
 	reset(f xos.File)
+	setCompression(value compression.Type)
 	close() error
 	isOpen() bool
 	sync() error
@@ -162,8 +164,10 @@ func (w *writer) Open() (persist.CommitLogFile, error) {
 	if err != nil {
 		return persist.CommitLogFile{}, err
 	}
+	compressionType := w.opts.ChunkCompression()
 	logInfo := schema.LogInfo{
-		Index: int64(index),
+		Index:            int64(index),
+		ChunkCompression: compressionType,
 	}
 	w.logEncoder.Reset()
 	if err := w.logEncoder.EncodeLogInfo(logInfo); err != nil {
@@ -175,12 +179,23 @@ func (w *writer) Open() (persist.CommitLogFile, error) {
 	}
 
 	w.chunkWriter.reset(fd)
+	w.chunkWriter.setCompression(compression.None)
 	w.buffer.Reset(w.chunkWriter)
 	if err := w.write(w.logEncoder.Bytes()); err != nil {
 		w.Close()
 		return persist.CommitLogFile{}, err
 	}
 
+	if compressionType != compression.None {
+		// The log info is flushed in a chunk of its own and left uncompressed
+		// so that readers can learn the compression of the chunks after it.
+		if err := w.buffer.Flush(); err != nil {
+			w.Close()
+			return persist.CommitLogFile{}, err
+		}
+		w.chunkWriter.setCompression(compressionType)
+	}
+
 	return persist.CommitLogFile{
 		FilePath: filePath,
 		Index:    int64(index),
@@ -317,10 +332,12 @@ func (w *writer) write(data []byte) error {
 }
 
 type fsChunkWriter struct {
-	fd      xos.File
-	flushFn flushFn
-	buff    []byte
-	fsync   bool
+	fd           xos.File
+	flushFn      flushFn
+	buff         []byte
+	fsync        bool
+	compression  compression.Type
+	compressBuff []byte
 }
 
 func newChunkWriter(flushFn flushFn, fsync bool) chunkWriter {
@@ -335,6 +352,10 @@ func (w *fsChunkWriter) reset(f xos.File) {
 	w.fd = f
 }
 
+func (w *fsChunkWriter) setCompression(value compression.Type) {
+	w.compression = value
+}
+
 func (w *fsChunkWriter) close() error {
 	err := w.fd.Close()
 	w.fd = nil
@@ -350,7 +371,18 @@ func (w *fsChunkWriter) sync() error {
 }
 
 func (w *fsChunkWriter) Write(p []byte) (int, error) {
-	size := len(p)
+	data := p
+	if w.compression != compression.None {
+		var err error
+		w.compressBuff, err = compressChunk(w.compression, w.compressBuff, p)
+		if err != nil {
+			w.flushFn(err)
+			return 0, err
+		}
+		data = w.compressBuff
+	}
+
+	size := len(data)
 
 	sizeStart, sizeEnd :=
 		0, chunkHeaderSizeLen
@@ -364,7 +396,7 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 
 	// Calculate checksums
 	checksumSize := digest.Checksum(w.buff[sizeStart:sizeEnd])
-	checksumData := digest.Checksum(p)
+	checksumData := digest.Checksum(data)
 
 	// Write checksums
 	digest.
@@ -375,7 +407,7 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 		WriteDigest(checksumData)
 
 	// Combine buffers to reduce to a single syscall
-	w.buff = append(w.buff[:chunkHeaderLen], p...)
+	w.buff = append(w.buff[:chunkHeaderLen], data...)
 
 	// Write contents to file descriptor
 	n, err := w.fd.Write(w.buff)
@@ -391,5 +423,10 @@ func (w *fsChunkWriter) Write(p []byte) (int, error) {
 
 	// Fire flush callback
 	w.flushFn(err)
+	if err == nil && w.compression != compression.None {
+		// Fewer bytes than were passed in may have been written to the file
+		// descriptor, report all of them as written since they were consumed.
+		n = len(p)
+	}
 	return n, err
 }
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+
+	"github.com/golang/snappy"
+)
+
+// Series data in data files is compressed one series segment at a time so
+// that the offset and size of each index entry continue to address a single
+// series and the seeker can still read any series with a single read.
+
+// compressData returns the compressed form of src, reusing dst if it has
+// enough capacity.
+func compressData(
+	compressionType compression.Type,
+	dst []byte,
+	src []byte,
+) ([]byte, error) {
+	switch compressionType {
+	case compression.None:
+		return append(dst[:0], src...), nil
+	case compression.Snappy:
+		return snappy.Encode(dst[:cap(dst)], src), nil
+	default:
+		return nil, errUnknownCompression(compressionType)
+	}
+}
+
+// decompressedDataLen returns the length of the series data once src has
+// been decompressed.
+func decompressedDataLen(
+	compressionType compression.Type,
+	src []byte,
+) (int, error) {
+	switch compressionType {
+	case compression.None:
+		return len(src), nil
+	case compression.Snappy:
+		return snappy.DecodedLen(src)
+	default:
+		return 0, errUnknownCompression(compressionType)
+	}
+}
+
+// decompressData decompresses src into dst, which must have a length of at
+// least the decompressed length of src, and returns the decompressed slice.
+func decompressData(
+	compressionType compression.Type,
+	dst []byte,
+	src []byte,
+) ([]byte, error) {
+	switch compressionType {
+	case compression.None:
+		return dst[:copy(dst, src)], nil
+	case compression.Snappy:
+		return snappy.Decode(dst, src)
+	default:
+		return nil, errUnknownCompression(compressionType)
+	}
+}
+
+func errUnknownCompression(compressionType compression.Type) error {
+	return fmt.Errorf("unknown data compression: %v", compressionType)
+}
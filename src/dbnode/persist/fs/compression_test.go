@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/persist/compression"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressDataRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("some series data "), 100)
+	for _, compressionType := range []compression.Type{
+		compression.None,
+		compression.Snappy,
+	} {
+		compressed, err := compressData(compressionType, nil, data)
+		require.NoError(t, err)
+		if compressionType != compression.None {
+			require.True(t, len(compressed) < len(data))
+		}
+
+		size, err := decompressedDataLen(compressionType, compressed)
+		require.NoError(t, err)
+		require.Equal(t, len(data), size)
+
+		decompressed, err := decompressData(compressionType, make([]byte, size), compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+	}
+}
+
+func TestCompressDataUnknownCompression(t *testing.T) {
+	compressionType := compression.Type(255)
+
+	_, err := compressData(compressionType, nil, []byte{1, 2, 3})
+	require.Error(t, err)
+
+	_, err = decompressedDataLen(compressionType, []byte{1, 2, 3})
+	require.Error(t, err)
+
+	_, err = decompressData(compressionType, nil, []byte{1, 2, 3})
+	require.Error(t, err)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+)
+
+// DownsampledMarkerFilePath returns the path of the file that marks a data
+// fileset under the given file path prefix as having been downsampled. The
+// marker lives beside the files of the fileset so it is cleaned up, tiered
+// and backed up along with them.
+func DownsampledMarkerFilePath(filePathPrefix string, id FileSetFileIdentifier) string {
+	shardDir := ShardDataDirPath(filePathPrefix, id.Namespace, id.Shard)
+	return dataFilesetPathFromTimeAndIndex(shardDir, id.BlockStart,
+		id.VolumeIndex, downsampledFileSuffix, false)
+}
+
+// DownsampledMarkerExists returns whether a data fileset under the given
+// file path prefix has been marked as downsampled.
+func DownsampledMarkerExists(filePathPrefix string, id FileSetFileIdentifier) (bool, error) {
+	return FileExists(DownsampledMarkerFilePath(filePathPrefix, id))
+}
+
+// WriteDownsampledMarker marks a data fileset under the given file path
+// prefix as having been downsampled.
+func WriteDownsampledMarker(
+	filePathPrefix string,
+	id FileSetFileIdentifier,
+	newFileMode os.FileMode,
+) error {
+	fd, err := OpenWritable(DownsampledMarkerFilePath(filePathPrefix, id), newFileMode)
+	if err != nil {
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"io"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+const (
+	// DefaultArrowBatchSize is the default number of rows of each record
+	// batch written by an Arrow writer.
+	DefaultArrowBatchSize = 65536
+)
+
+// ArrowSchema is the schema of the Arrow IPC streams written by Arrow
+// writers, tags are a list of name and value pairs.
+var ArrowSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.BinaryTypes.String},
+	{Name: "tags", Type: arrow.ListOf(arrow.StructOf(
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "value", Type: arrow.BinaryTypes.String},
+	))},
+	{Name: "timestamp", Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}},
+	{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "annotation", Type: arrow.BinaryTypes.Binary, Nullable: true},
+}, nil)
+
+type arrowWriter struct {
+	writer    *ipc.Writer
+	builder   *array.RecordBuilder
+	batchSize int
+	rows      int
+
+	ids         *array.StringBuilder
+	tags        *array.ListBuilder
+	tag         *array.StructBuilder
+	tagNames    *array.StringBuilder
+	tagValues   *array.StringBuilder
+	timestamps  *array.TimestampBuilder
+	values      *array.Float64Builder
+	annotations *array.BinaryBuilder
+}
+
+// NewArrowWriter returns a writer that writes series as an Arrow IPC stream
+// with the ArrowSchema schema, buffering rows into record batches of the
+// given size.
+func NewArrowWriter(w io.Writer, batchSize int) Writer {
+	if batchSize <= 0 {
+		batchSize = DefaultArrowBatchSize
+	}
+
+	var (
+		allocator = memory.NewGoAllocator()
+		builder   = array.NewRecordBuilder(allocator, ArrowSchema)
+		tags      = builder.Field(1).(*array.ListBuilder)
+		tag       = tags.ValueBuilder().(*array.StructBuilder)
+	)
+	return &arrowWriter{
+		writer: ipc.NewWriter(w,
+			ipc.WithSchema(ArrowSchema),
+			ipc.WithAllocator(allocator)),
+		builder:     builder,
+		batchSize:   batchSize,
+		ids:         builder.Field(0).(*array.StringBuilder),
+		tags:        tags,
+		tag:         tag,
+		tagNames:    tag.FieldBuilder(0).(*array.StringBuilder),
+		tagValues:   tag.FieldBuilder(1).(*array.StringBuilder),
+		timestamps:  builder.Field(2).(*array.TimestampBuilder),
+		values:      builder.Field(3).(*array.Float64Builder),
+		annotations: builder.Field(4).(*array.BinaryBuilder),
+	}
+}
+
+func (w *arrowWriter) Write(series Series) error {
+	var (
+		id   = series.ID.String()
+		tags = series.Tags.Values()
+	)
+	for _, dp := range series.Datapoints {
+		w.ids.Append(id)
+		w.tags.Append(true)
+		for _, tag := range tags {
+			w.tag.Append(true)
+			w.tagNames.Append(tag.Name.String())
+			w.tagValues.Append(tag.Value.String())
+		}
+		w.timestamps.Append(arrow.Timestamp(dp.Timestamp.UnixNano()))
+		w.values.Append(dp.Value)
+		if len(dp.Annotation) == 0 {
+			w.annotations.AppendNull()
+		} else {
+			w.annotations.Append(dp.Annotation)
+		}
+
+		w.rows++
+		if w.rows >= w.batchSize {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *arrowWriter) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	record := w.builder.NewRecord()
+	defer record.Release()
+
+	w.rows = 0
+	return w.writer.Write(record)
+}
+
+func (w *arrowWriter) Close() error {
+	defer w.builder.Release()
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return w.writer.Close()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrowWriterWrite(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		writer = NewArrowWriter(&buf, 2)
+		start  = time.Unix(1000, 0)
+	)
+	require.NoError(t, writer.Write(Series{
+		ID:   ident.StringID("foo"),
+		Tags: ident.NewTags(ident.StringTag("name", "foo"), ident.StringTag("env", "prod")),
+		Datapoints: []Datapoint{
+			{Timestamp: start, Value: 1, Annotation: ts.Annotation("a")},
+			{Timestamp: start.Add(time.Second), Value: 2},
+		},
+	}))
+	require.NoError(t, writer.Write(Series{
+		ID:         ident.StringID("bar"),
+		Tags:       ident.NewTags(ident.StringTag("name", "bar")),
+		Datapoints: []Datapoint{{Timestamp: start.Add(2 * time.Second), Value: 3}},
+	}))
+	require.NoError(t, writer.Close())
+
+	reader, err := ipc.NewReader(&buf)
+	require.NoError(t, err)
+	defer reader.Release()
+	require.True(t, reader.Schema().Equal(ArrowSchema))
+
+	type row struct {
+		id         string
+		tags       []string
+		timestamp  time.Time
+		value      float64
+		annotation []byte
+	}
+	var (
+		rows    []row
+		batches int
+	)
+	for reader.Next() {
+		batches++
+		record := reader.Record()
+		var (
+			ids         = record.Column(0).(*array.String)
+			tags        = record.Column(1).(*array.List)
+			tag         = tags.ListValues().(*array.Struct)
+			tagNames    = tag.Field(0).(*array.String)
+			tagValues   = tag.Field(1).(*array.String)
+			timestamps  = record.Column(2).(*array.Timestamp)
+			values      = record.Column(3).(*array.Float64)
+			annotations = record.Column(4).(*array.Binary)
+			offsets     = tags.Offsets()
+		)
+		for i := 0; i < int(record.NumRows()); i++ {
+			r := row{
+				id:        ids.Value(i),
+				timestamp: time.Unix(0, int64(timestamps.Value(i))),
+				value:     values.Value(i),
+			}
+			for j := offsets[i]; j < offsets[i+1]; j++ {
+				r.tags = append(r.tags, tagNames.Value(int(j))+"="+tagValues.Value(int(j)))
+			}
+			if !annotations.IsNull(i) {
+				r.annotation = annotations.Value(i)
+			}
+			rows = append(rows, r)
+		}
+	}
+	require.NoError(t, reader.Err())
+	require.Equal(t, 2, batches)
+
+	require.Equal(t, []row{
+		{id: "foo", tags: []string{"name=foo", "env=prod"}, timestamp: start, value: 1, annotation: []byte("a")},
+		{id: "foo", tags: []string{"name=foo", "env=prod"}, timestamp: start.Add(time.Second), value: 2},
+		{id: "bar", tags: []string{"name=bar"}, timestamp: start.Add(2 * time.Second), value: 3},
+	}, rows)
+	require.Equal(t, arrow.Nanosecond, ArrowSchema.Field(2).Type.(*arrow.TimestampType).Unit)
+}
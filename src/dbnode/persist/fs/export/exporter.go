@@ -0,0 +1,517 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/proto"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
+	"go.uber.org/zap"
+)
+
+type exporter struct {
+	opts   Options
+	logger *zap.Logger
+}
+
+// shardBlock is the latest complete flushed fileset and the snapshot fileset
+// of the latest complete snapshot of a block of a shard, either of which may
+// not exist.
+type shardBlock struct {
+	shard              uint32
+	blockStart         time.Time
+	data               fs.FileSetFile
+	dataFilePathPrefix string
+	snapshot           fs.FileSetFile
+}
+
+// NewExporter returns a new namespace exporter.
+func NewExporter(opts Options) (Exporter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &exporter{
+		opts:   opts,
+		logger: opts.InstrumentOptions().Logger(),
+	}, nil
+}
+
+func (e *exporter) Export(query Query, writer Writer) (Result, error) {
+	var (
+		result         Result
+		fsOpts         = e.opts.FilesystemOptions()
+		filePathPrefix = fsOpts.FilePathPrefix()
+		dataPrefixes   = []string{filePathPrefix}
+		nsCtx          = namespace.NewContextFrom(query.Namespace)
+	)
+	if tiered := e.opts.TieredFilePathPrefix(); tiered != "" {
+		// NB: filesets are copied in full to the tiered file path prefix
+		// before they are removed, so it is looked up first.
+		dataPrefixes = []string{tiered, filePathPrefix}
+	}
+
+	reader, err := fs.NewReader(nil, fsOpts)
+	if err != nil {
+		return result, err
+	}
+
+	snapshotID, err := e.latestSnapshotID()
+	if err != nil {
+		return result, err
+	}
+
+	shards, err := namespaceShards(dataPrefixes, nsCtx.ID)
+	if err != nil {
+		return result, err
+	}
+
+	for _, shard := range shards {
+		blocks, err := e.shardBlocks(dataPrefixes, query, shard, snapshotID)
+		if err != nil {
+			return result, err
+		}
+
+		for _, block := range blocks {
+			if err := e.exportBlock(reader, query, nsCtx, block, writer, &result); err != nil {
+				return result, fmt.Errorf("unable to export block %s of shard %d: %v",
+					block.blockStart, block.shard, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// latestSnapshotID returns the ID of the latest complete snapshot, or nil if
+// there is none. A snapshot is complete once its metadata file is written
+// after the snapshot filesets of every shard, so only its filesets are
+// consistent with each other.
+func (e *exporter) latestSnapshotID() (uuid.UUID, error) {
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(e.opts.FilesystemOptions())
+	if err != nil {
+		return nil, err
+	}
+	if len(metadatas) == 0 {
+		return nil, nil
+	}
+	return metadatas[len(metadatas)-1].ID.UUID, nil
+}
+
+func (e *exporter) shardBlocks(
+	dataPrefixes []string,
+	query Query,
+	shard uint32,
+	snapshotID uuid.UUID,
+) ([]shardBlock, error) {
+	nsID := query.Namespace.ID()
+	blocks := make(map[xtime.UnixNano]*shardBlock)
+	blockFor := func(blockStart time.Time) *shardBlock {
+		key := xtime.ToUnixNano(blockStart)
+		block, ok := blocks[key]
+		if !ok {
+			block = &shardBlock{shard: shard, blockStart: blockStart}
+			blocks[key] = block
+		}
+		return block
+	}
+
+	for _, prefix := range dataPrefixes {
+		files, err := fs.DataFiles(prefix, nsID, shard)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blockStart := range blockStartsBefore(files, query.End) {
+			if block := blockFor(blockStart); block.data.IsZero() {
+				if latest, ok := files.LatestVolumeForBlock(blockStart); ok {
+					block.data = latest
+					block.dataFilePathPrefix = prefix
+				}
+			}
+		}
+	}
+
+	if err := e.addSnapshots(query, shard, snapshotID, blockFor); err != nil {
+		return nil, err
+	}
+
+	result := make([]shardBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, *block)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].blockStart.Before(result[j].blockStart)
+	})
+	return result, nil
+}
+
+// addSnapshots sets the snapshot fileset of the blocks of the shard that are
+// part of the snapshot with the snapshot ID.
+func (e *exporter) addSnapshots(
+	query Query,
+	shard uint32,
+	snapshotID uuid.UUID,
+	blockFor func(blockStart time.Time) *shardBlock,
+) error {
+	if snapshotID == nil {
+		return nil
+	}
+
+	filePathPrefix := e.opts.FilesystemOptions().FilePathPrefix()
+	snapshots, err := fs.SnapshotFiles(filePathPrefix, query.Namespace.ID(), shard)
+	if err != nil {
+		return err
+	}
+
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		if !snapshot.ID.BlockStart.Before(query.End) || !snapshot.HasCompleteCheckpointFile() {
+			continue
+		}
+
+		_, id, err := snapshot.SnapshotTimeAndID()
+		if err != nil {
+			e.logger.Warn("skipping snapshot fileset with unreadable snapshot ID",
+				zap.Strings("files", snapshot.AbsoluteFilepaths),
+				zap.Error(err))
+			continue
+		}
+		if !uuid.Equal(id, snapshotID) {
+			continue
+		}
+
+		block := blockFor(snapshot.ID.BlockStart)
+		if block.snapshot.IsZero() || snapshot.ID.VolumeIndex > block.snapshot.ID.VolumeIndex {
+			block.snapshot = *snapshot
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportBlock(
+	reader fs.DataFileSetReader,
+	query Query,
+	nsCtx namespace.Context,
+	block shardBlock,
+	writer Writer,
+	result *Result,
+) error {
+	var (
+		series = make(map[string]*Series)
+		merged = make(map[string]struct{})
+	)
+	// NB: the snapshot is read last so that its datapoints, which are more
+	// recent, take precedence over those of the flushed fileset.
+	for _, fileSet := range []struct {
+		file           fs.FileSetFile
+		fileSetType    persist.FileSetType
+		filePathPrefix string
+	}{
+		{block.data, persist.FileSetFlushType, block.dataFilePathPrefix},
+		{block.snapshot, persist.FileSetSnapshotType, ""},
+	} {
+		if fileSet.file.IsZero() {
+			continue
+		}
+
+		err := reader.Open(fs.DataReaderOpenOptions{
+			Identifier:     fileSet.file.ID,
+			FileSetType:    fileSet.fileSetType,
+			FilePathPrefix: fileSet.filePathPrefix,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = e.readFileSet(reader, query, nsCtx, series, merged)
+		if closeErr := reader.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		result.FileSets++
+	}
+
+	for id := range merged {
+		series[id].Datapoints = sortAndDedupe(series[id].Datapoints)
+	}
+
+	ids := make([]string, 0, len(series))
+	for id, s := range series {
+		if len(s.Datapoints) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if query.Query != nil {
+		var err error
+		ids, err = matchingIDs(*query.Query, ids, series)
+		if err != nil {
+			return err
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		s := series[id]
+		if err := writer.Write(*s); err != nil {
+			return err
+		}
+		result.Series++
+		result.Datapoints += len(s.Datapoints)
+	}
+
+	e.logger.Debug("exported block",
+		zap.Uint32("shard", block.shard),
+		zap.Time("blockStart", block.blockStart),
+		zap.Int("series", len(ids)))
+	return nil
+}
+
+func (e *exporter) readFileSet(
+	reader fs.DataFileSetReader,
+	query Query,
+	nsCtx namespace.Context,
+	series map[string]*Series,
+	merged map[string]struct{},
+) error {
+	status := reader.Status()
+	if !status.BlockStart.Add(status.BlockSize).After(query.Start) {
+		return nil
+	}
+
+	for {
+		id, tagsIter, data, _, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data.IncRef()
+		iter := e.newReaderIterator(bytes.NewReader(data.Bytes()), nsCtx.Schema)
+		var datapoints []Datapoint
+		for iter.Next() {
+			dp, _, annotation := iter.Current()
+			if dp.Timestamp.Before(query.Start) || !dp.Timestamp.Before(query.End) {
+				continue
+			}
+			datapoints = append(datapoints, Datapoint{
+				Timestamp:  dp.Timestamp,
+				Value:      dp.Value,
+				Annotation: append(ts.Annotation(nil), annotation...),
+			})
+		}
+		err = iter.Err()
+		iter.Close()
+		data.DecRef()
+		data.Finalize()
+		if err != nil {
+			tagsIter.Close()
+			return err
+		}
+
+		key := id.String()
+		if existing, ok := series[key]; ok {
+			existing.Datapoints = append(existing.Datapoints, datapoints...)
+			merged[key] = struct{}{}
+			tagsIter.Close()
+			continue
+		}
+
+		tags, err := cloneTags(tagsIter)
+		if err != nil {
+			return err
+		}
+		series[key] = &Series{
+			ID:         ident.StringID(key),
+			Tags:       tags,
+			Datapoints: datapoints,
+		}
+	}
+}
+
+// newReaderIterator returns an iterator decoding series the way the namespace
+// encodes them, with the proto encoding if the namespace has a schema.
+func (e *exporter) newReaderIterator(
+	r io.Reader,
+	schema namespace.SchemaDescr,
+) encoding.ReaderIterator {
+	encodingOpts := e.opts.EncodingOptions()
+	if schema != nil {
+		return proto.NewIterator(r, schema, encodingOpts)
+	}
+	return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+}
+
+// matchingIDs returns the IDs of the series that match the query by
+// indexing them in a temporary segment.
+func matchingIDs(
+	query index.Query,
+	ids []string,
+	series map[string]*Series,
+) ([]string, error) {
+	segment, err := mem.NewSegment(0, mem.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer segment.Close()
+
+	for _, id := range ids {
+		s := series[id]
+		d, err := convert.FromMetricNoClone(s.ID, s.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := segment.Insert(d); err != nil {
+			return nil, err
+		}
+	}
+
+	segmentReader, err := segment.Reader()
+	if err != nil {
+		return nil, err
+	}
+	exec := executor.NewExecutor([]m3ninxindex.Reader{segmentReader})
+	defer exec.Close()
+
+	iter, err := exec.Execute(query.Query.SearchQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for iter.Next() {
+		matched = append(matched, string(iter.Current().ID))
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return nil, err
+	}
+	return matched, iter.Close()
+}
+
+func namespaceShards(filePathPrefixes []string, nsID ident.ID) ([]uint32, error) {
+	unique := make(map[uint32]struct{})
+	for _, prefix := range filePathPrefixes {
+		for _, dir := range []string{
+			fs.NamespaceDataDirPath(prefix, nsID),
+			fs.NamespaceSnapshotsDirPath(prefix, nsID),
+		} {
+			infos, err := ioutil.ReadDir(dir)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			for _, info := range infos {
+				shard, err := strconv.ParseUint(info.Name(), 10, 32)
+				if err != nil || !info.IsDir() {
+					continue
+				}
+				unique[uint32(shard)] = struct{}{}
+			}
+		}
+	}
+
+	shards := make([]uint32, 0, len(unique))
+	for shard := range unique {
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i] < shards[j]
+	})
+	return shards, nil
+}
+
+func blockStartsBefore(files fs.FileSetFilesSlice, end time.Time) []time.Time {
+	var (
+		blockStarts []time.Time
+		seen        = make(map[xtime.UnixNano]struct{})
+	)
+	for _, f := range files {
+		blockStart := f.ID.BlockStart
+		if !blockStart.Before(end) {
+			continue
+		}
+		if _, ok := seen[xtime.ToUnixNano(blockStart)]; ok {
+			continue
+		}
+		seen[xtime.ToUnixNano(blockStart)] = struct{}{}
+		blockStarts = append(blockStarts, blockStart)
+	}
+	return blockStarts
+}
+
+func cloneTags(iter ident.TagIterator) (ident.Tags, error) {
+	defer iter.Close()
+
+	tags := make([]ident.Tag, 0, iter.Remaining())
+	for iter.Next() {
+		tag := iter.Current()
+		tags = append(tags, ident.StringTag(tag.Name.String(), tag.Value.String()))
+	}
+	if err := iter.Err(); err != nil {
+		return ident.Tags{}, err
+	}
+	return ident.NewTags(tags...), nil
+}
+
+func sortAndDedupe(datapoints []Datapoint) []Datapoint {
+	sort.SliceStable(datapoints, func(i, j int) bool {
+		return datapoints[i].Timestamp.Before(datapoints[j].Timestamp)
+	})
+
+	deduped := datapoints[:0]
+	for _, dp := range datapoints {
+		if n := len(deduped); n > 0 && deduped[n-1].Timestamp.Equal(dp.Timestamp) {
+			deduped[n-1] = dp
+			continue
+		}
+		deduped = append(deduped, dp)
+	}
+	return deduped
+}
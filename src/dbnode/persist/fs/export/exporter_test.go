@@ -0,0 +1,272 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNamespace = ident.StringID("testns")
+	testBlockSize = 2 * time.Hour
+)
+
+type testWriter struct {
+	series []Series
+}
+
+func (w *testWriter) Write(series Series) error {
+	w.series = append(w.series, series)
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	return nil
+}
+
+type testFileSetSeries struct {
+	id         string
+	tags       []string
+	datapoints []Datapoint
+}
+
+func writeTestFileSet(
+	t *testing.T,
+	dir string,
+	shard uint32,
+	blockStart time.Time,
+	fileSetType persist.FileSetType,
+	snapshotID uuid.UUID,
+	series []testFileSetSeries,
+) {
+	writer, err := fs.NewWriter(fs.NewOptions().SetFilePathPrefix(dir))
+	require.NoError(t, err)
+
+	err = writer.Open(fs.DataWriterOpenOptions{
+		FileSetType: fileSetType,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  testNamespace,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		BlockSize: testBlockSize,
+		Snapshot: fs.DataWriterSnapshotOptions{
+			SnapshotTime: blockStart.Add(time.Hour),
+			SnapshotID:   snapshotID,
+		},
+	})
+	require.NoError(t, err)
+
+	for _, s := range series {
+		encoder := m3tsz.NewEncoder(blockStart, nil,
+			m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		for _, dp := range s.datapoints {
+			require.NoError(t, encoder.Encode(ts.Datapoint{
+				Timestamp: dp.Timestamp,
+				Value:     dp.Value,
+			}, xtime.Second, dp.Annotation))
+		}
+
+		segment := encoder.Discard()
+		data := []checked.Bytes{segment.Head, segment.Tail}
+		tags := ident.NewTags()
+		for i := 0; i < len(s.tags); i += 2 {
+			tags.Append(ident.StringTag(s.tags[i], s.tags[i+1]))
+		}
+		err := writer.WriteAll(ident.StringID(s.id), tags, data,
+			digest.SegmentChecksum(segment))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+}
+
+func writeTestSnapshotMetadata(t *testing.T, dir string, index int64, snapshotID uuid.UUID) {
+	writer := fs.NewSnapshotMetadataWriter(fs.NewOptions().SetFilePathPrefix(dir))
+	require.NoError(t, writer.Write(fs.SnapshotMetadataWriteArgs{
+		ID: fs.SnapshotMetadataIdentifier{
+			Index: index,
+			UUID:  snapshotID,
+		},
+		CommitlogIdentifier: persist.CommitLogFile{FilePath: "commitlog"},
+	}))
+}
+
+func newTestNamespaceMetadata(t *testing.T) namespace.Metadata {
+	md, err := namespace.NewMetadata(testNamespace, namespace.NewOptions())
+	require.NoError(t, err)
+	return md
+}
+
+func newTestExporter(t *testing.T, dir string) Exporter {
+	opts := NewOptions().
+		SetFilesystemOptions(fs.NewOptions().SetFilePathPrefix(dir))
+	exporter, err := NewExporter(opts)
+	require.NoError(t, err)
+	return exporter
+}
+
+func TestExporterExportMergesSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockStart = time.Unix(0, 0).Add(100 * testBlockSize)
+		at         = func(d time.Duration) time.Time { return blockStart.Add(d) }
+		snapshotID = uuid.NewRandom()
+	)
+	writeTestSnapshotMetadata(t, dir, 0, snapshotID)
+	writeTestFileSet(t, dir, 0, blockStart, persist.FileSetFlushType, nil, []testFileSetSeries{
+		{
+			id:   "foo",
+			tags: []string{"name", "foo"},
+			datapoints: []Datapoint{
+				{Timestamp: at(time.Minute), Value: 1, Annotation: ts.Annotation("a")},
+				{Timestamp: at(2 * time.Minute), Value: 2},
+			},
+		},
+	})
+	writeTestFileSet(t, dir, 0, blockStart, persist.FileSetSnapshotType, snapshotID, []testFileSetSeries{
+		{
+			id:   "foo",
+			tags: []string{"name", "foo"},
+			datapoints: []Datapoint{
+				{Timestamp: at(2 * time.Minute), Value: 2},
+				{Timestamp: at(3 * time.Minute), Value: 3},
+			},
+		},
+	})
+	writeTestFileSet(t, dir, 1, blockStart.Add(testBlockSize), persist.FileSetSnapshotType, snapshotID, []testFileSetSeries{
+		{
+			id:         "bar",
+			tags:       []string{"name", "bar"},
+			datapoints: []Datapoint{{Timestamp: at(testBlockSize + time.Minute), Value: 4}},
+		},
+	})
+
+	writer := &testWriter{}
+	result, err := newTestExporter(t, dir).Export(Query{
+		Namespace: newTestNamespaceMetadata(t),
+		Start:     at(2 * time.Minute),
+		End:       at(2 * testBlockSize),
+	}, writer)
+	require.NoError(t, err)
+	require.Equal(t, Result{FileSets: 3, Series: 2, Datapoints: 3}, result)
+
+	require.Len(t, writer.series, 2)
+	require.Equal(t, "foo", writer.series[0].ID.String())
+	require.Equal(t, []Datapoint{
+		{Timestamp: at(2 * time.Minute), Value: 2},
+		{Timestamp: at(3 * time.Minute), Value: 3},
+	}, writer.series[0].Datapoints)
+	require.Equal(t, "bar", writer.series[1].ID.String())
+	require.Equal(t, 1, writer.series[1].Tags.Len())
+}
+
+func TestExporterExportQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockStart = time.Unix(0, 0).Add(100 * testBlockSize)
+		datapoints = []Datapoint{{Timestamp: blockStart.Add(time.Minute), Value: 1}}
+	)
+	writeTestFileSet(t, dir, 0, blockStart, persist.FileSetFlushType, nil, []testFileSetSeries{
+		{id: "foo", tags: []string{"name", "foo", "env", "prod"}, datapoints: datapoints},
+		{id: "bar", tags: []string{"name", "bar", "env", "prod"}, datapoints: datapoints},
+		{id: "baz", tags: []string{"name", "baz", "env", "dev"}, datapoints: datapoints},
+	})
+
+	query, err := idx.NewRegexpQuery([]byte("name"), []byte("ba."))
+	require.NoError(t, err)
+
+	writer := &testWriter{}
+	_, err = newTestExporter(t, dir).Export(Query{
+		Namespace: newTestNamespaceMetadata(t),
+		Start:     blockStart,
+		End:       blockStart.Add(testBlockSize),
+		Query: &index.Query{
+			Query: idx.NewConjunctionQuery(query, idx.NewTermQuery([]byte("env"), []byte("prod"))),
+		},
+	}, writer)
+	require.NoError(t, err)
+
+	require.Len(t, writer.series, 1)
+	require.Equal(t, "bar", writer.series[0].ID.String())
+}
+
+func TestExporterExportLatestCompleteSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockStart   = time.Unix(0, 0).Add(100 * testBlockSize)
+		staleID      = uuid.NewRandom()
+		latestID     = uuid.NewRandom()
+		incompleteID = uuid.NewRandom()
+		newSeries    = func(id string, blockStart time.Time) []testFileSetSeries {
+			return []testFileSetSeries{{
+				id:         id,
+				tags:       []string{"name", id},
+				datapoints: []Datapoint{{Timestamp: blockStart.Add(time.Minute), Value: 1}},
+			}}
+		}
+	)
+	writeTestFileSet(t, dir, 0, blockStart, persist.FileSetSnapshotType, staleID,
+		newSeries("stale", blockStart))
+	writeTestFileSet(t, dir, 0, blockStart.Add(testBlockSize), persist.FileSetSnapshotType, latestID,
+		newSeries("latest", blockStart.Add(testBlockSize)))
+	writeTestFileSet(t, dir, 0, blockStart.Add(2*testBlockSize), persist.FileSetSnapshotType, incompleteID,
+		newSeries("incomplete", blockStart.Add(2*testBlockSize)))
+	writeTestSnapshotMetadata(t, dir, 0, staleID)
+	writeTestSnapshotMetadata(t, dir, 1, latestID)
+
+	writer := &testWriter{}
+	result, err := newTestExporter(t, dir).Export(Query{
+		Namespace: newTestNamespaceMetadata(t),
+		Start:     blockStart,
+		End:       blockStart.Add(3 * testBlockSize),
+	}, writer)
+	require.NoError(t, err)
+	require.Equal(t, Result{FileSets: 1, Series: 1, Datapoints: 1}, result)
+	require.Equal(t, "latest", writer.series[0].ID.String())
+}
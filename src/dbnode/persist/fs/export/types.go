@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package export exports the data of a namespace from its filesets into a
+// portable columnar format.
+package export
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Datapoint is an exported datapoint.
+type Datapoint struct {
+	Timestamp  time.Time
+	Value      float64
+	Annotation ts.Annotation
+}
+
+// Series is an exported series.
+type Series struct {
+	ID         ident.ID
+	Tags       ident.Tags
+	Datapoints []Datapoint
+}
+
+// Writer writes exported series in a columnar format with a row per
+// datapoint.
+type Writer interface {
+	// Write writes the datapoints of a series.
+	Write(series Series) error
+
+	// Close flushes any buffered rows and closes the writer.
+	Close() error
+}
+
+// Query selects the data to export.
+type Query struct {
+	// Namespace is the namespace to export, its options determine how
+	// series are decoded.
+	Namespace namespace.Metadata
+
+	// Start is the start of the time range to export.
+	Start time.Time
+
+	// End is the end of the time range to export, exclusive.
+	End time.Time
+
+	// Query optionally restricts the series exported to those matching it.
+	Query *index.Query
+}
+
+// Result is the result of an export.
+type Result struct {
+	// FileSets is the number of filesets read.
+	FileSets int
+
+	// Series is the number of series exported, counted once per shard and
+	// block.
+	Series int
+
+	// Datapoints is the number of datapoints exported.
+	Datapoints int
+}
+
+// Exporter exports the data of a namespace.
+type Exporter interface {
+	// Export reads the latest complete flushed fileset and the fileset of
+	// the latest complete snapshot of every shard and block in the time range
+	// and writes their merged series with the writer. Since complete
+	// filesets are immutable the export is consistent with the point in
+	// time each fileset was written, without reading through the database.
+	Export(query Query, writer Writer) (Result, error)
+}
+
+// Options are the options for exporting namespaces.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetFilesystemOptions sets the filesystem options, the filesets read
+	// are those under its file path prefix.
+	SetFilesystemOptions(value fs.Options) Options
+
+	// FilesystemOptions returns the filesystem options.
+	FilesystemOptions() fs.Options
+
+	// SetTieredFilePathPrefix sets the file path prefix that filesets of
+	// namespaces with tiering enabled are moved to, if any.
+	SetTieredFilePathPrefix(value string) Options
+
+	// TieredFilePathPrefix returns the file path prefix that filesets of
+	// namespaces with tiering enabled are moved to.
+	TieredFilePathPrefix() string
+
+	// SetEncodingOptions sets the encoding options used to decode series.
+	SetEncodingOptions(value encoding.Options) Options
+
+	// EncodingOptions returns the encoding options used to decode series.
+	EncodingOptions() encoding.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -1083,6 +1083,49 @@ func TestIndexFileSetsBefore(t *testing.T) {
 	}
 }
 
+func TestIndexFiles(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		ns1     = ident.StringID("abc")
+		now     = time.Now().Truncate(time.Hour)
+		timeFor = func(n int) time.Time { return now.Add(time.Hour * time.Duration(n)) }
+	)
+
+	files := indexFileSetFileIdentifiers{}
+	for _, id := range []struct {
+		block  int
+		volume int
+	}{{1, 0}, {1, 1}, {2, 0}} {
+		for _, suffix := range []string{infoFileSuffix, checkpointFileSuffix} {
+			files = append(files, indexFileSetFileIdentifier{
+				FileSetFileIdentifier: FileSetFileIdentifier{
+					BlockStart:         timeFor(id.block),
+					Namespace:          ns1,
+					VolumeIndex:        id.volume,
+					FileSetContentType: persist.FileSetIndexContentType,
+				},
+				Suffix: suffix,
+			})
+		}
+	}
+	files.create(t, dir)
+
+	results, err := IndexFiles(dir, ns1)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, expected := range []struct {
+		block  int
+		volume int
+	}{{1, 0}, {1, 1}, {2, 0}} {
+		require.True(t, results[i].ID.BlockStart.Equal(timeFor(expected.block)))
+		require.Equal(t, expected.volume, results[i].ID.VolumeIndex)
+		require.Len(t, results[i].AbsoluteFilepaths, 2)
+		require.True(t, results[i].HasCompleteCheckpointFile())
+	}
+}
+
 func TestSnapshotFileSnapshotTimeAndID(t *testing.T) {
 	var (
 		dir            = createTempDir(t)
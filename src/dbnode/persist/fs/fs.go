@@ -31,6 +31,8 @@ const (
 	digestFileSuffix         = "digest"
 	checkpointFileSuffix     = "checkpoint"
 	metadataFileSuffix       = "metadata"
+	downsampledFileSuffix    = "downsampled"
+	seriesRetentionSuffix    = "retention"
 	filesetFilePrefix        = "fileset"
 	commitLogFilePrefix      = "commitlog"
 	segmentFileSetFilePrefix = "segment"
@@ -46,6 +46,7 @@ type merger struct {
 	encoderPool    encoding.EncoderPool
 	contextPool    context.Pool
 	nsOpts         namespace.Options
+	filePathPrefix string
 }
 
 // NewMerger returns a new Merger. This implementation is in charge of merging
@@ -66,6 +67,7 @@ func NewMerger(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	fsOpts Options,
 ) Merger {
 	return &merger{
 		reader:         reader,
@@ -76,6 +78,7 @@ func NewMerger(
 		encoderPool:    encoderPool,
 		contextPool:    contextPool,
 		nsOpts:         nsOpts,
+		filePathPrefix: fsOpts.FilePathPrefix(),
 	}
 }
 
@@ -115,6 +118,21 @@ func (m *merger) Merge(
 		}
 	)
 
+	// NB: the fileset being merged may have been moved to the namespace's
+	// secondary tier, in which case it has to be read from there. The merged
+	// volume is always written under the primary file path prefix.
+	if nsOpts.TieringOptions().Enabled() {
+		filePathPrefix, ok, err := DataFileSetFilePathPrefix(
+			DataFilePathPrefixes(m.filePathPrefix, nsOpts),
+			nsID, shard, startTime, volume)
+		if err != nil {
+			return err
+		}
+		if ok {
+			openOpts.FilePathPrefix = filePathPrefix
+		}
+	}
+
 	if err := reader.Open(openOpts); err != nil {
 		return err
 	}
@@ -224,10 +242,12 @@ func (m *merger) Merge(
 		}
 		tagsToFinalize = append(tagsToFinalize, tags)
 
+		deleted := mergeWith.DeletedRanges(id, tags, blockStart)
+
 		// In the special (but common) case that we're just copying the series data from the old file
 		// into the new one without merging or adding any additional data we can avoid recalculating
 		// the checksum.
-		if len(segmentReaders) == 1 && hasInMemoryData == false {
+		if len(segmentReaders) == 1 && hasInMemoryData == false && deleted.IsEmpty() {
 			segment, err := segmentReaders[0].Segment()
 			if err != nil {
 				return err
@@ -237,7 +257,7 @@ func (m *merger) Merge(
 				return err
 			}
 		} else {
-			if err := persistSegmentReaders(id, tags, segmentReaders, iterResources, prepared.Persist); err != nil {
+			if err := persistSegmentReaders(id, tags, segmentReaders, deleted, iterResources, prepared.Persist); err != nil {
 				return err
 			}
 		}
@@ -255,7 +275,8 @@ func (m *merger) Merge(
 		func(id ident.ID, tags ident.Tags, mergeWithData []xio.BlockReader) error {
 			segmentReaders = segmentReaders[:0]
 			segmentReaders = appendBlockReadersToSegmentReaders(segmentReaders, mergeWithData)
-			err := persistSegmentReaders(id, tags, segmentReaders, iterResources, prepared.Persist)
+			deleted := mergeWith.DeletedRanges(id, tags, blockStart)
+			err := persistSegmentReaders(id, tags, segmentReaders, deleted, iterResources, prepared.Persist)
 			// Context is safe to close after persisting data to disk.
 			// Reset context here within the passed in function so that the
 			// context gets reset for each remaining series instead of getting
@@ -293,6 +314,7 @@ func persistSegmentReaders(
 	id ident.ID,
 	tags ident.Tags,
 	segReaders []xio.SegmentReader,
+	deleted xtime.Ranges,
 	ir iterResources,
 	persistFn persist.DataFn,
 ) error {
@@ -300,17 +322,28 @@ func persistSegmentReaders(
 		return nil
 	}
 
-	if len(segReaders) == 1 {
+	if !deleted.IsEmpty() {
+		blockRange := xtime.Range{Start: ir.blockStart, End: ir.blockStart.Add(ir.blockSize)}
+		if !deleted.Overlaps(blockRange) {
+			deleted = xtime.Ranges{}
+		} else if xtime.NewRanges(blockRange).RemoveRanges(deleted).IsEmpty() {
+			// All of the data for this series in this block has been deleted.
+			return nil
+		}
+	}
+
+	if len(segReaders) == 1 && deleted.IsEmpty() {
 		return persistSegmentReader(id, tags, segReaders[0], persistFn)
 	}
 
-	return persistIter(id, tags, segReaders, ir, persistFn)
+	return persistIter(id, tags, segReaders, deleted, ir, persistFn)
 }
 
 func persistIter(
 	id ident.ID,
 	tags ident.Tags,
 	segReaders []xio.SegmentReader,
+	deleted xtime.Ranges,
 	ir iterResources,
 	persistFn persist.DataFn,
 ) error {
@@ -319,7 +352,11 @@ func persistIter(
 	encoder := ir.encoderPool.Get()
 	encoder.Reset(ir.blockStart, ir.blockAllocSize, ir.schema)
 	for it.Next() {
-		if err := encoder.Encode(it.Current()); err != nil {
+		dp, unit, annotation := it.Current()
+		if isDeleted(deleted, dp.Timestamp) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
 			encoder.Close()
 			return err
 		}
@@ -330,9 +367,22 @@ func persistIter(
 	}
 
 	segment := encoder.Discard()
+	if segment.Len() == 0 {
+		// Every datapoint was deleted, nothing to persist.
+		return nil
+	}
 	return persistSegment(id, tags, segment, persistFn)
 }
 
+// isDeleted returns whether a datapoint at the given time falls within any
+// of the deleted ranges.
+func isDeleted(deleted xtime.Ranges, t time.Time) bool {
+	if deleted.IsEmpty() {
+		return false
+	}
+	return deleted.Overlaps(xtime.Range{Start: t, End: t.Add(time.Nanosecond)})
+}
+
 func persistSegmentReader(
 	id ident.ID,
 	tags ident.Tags,
@@ -425,11 +425,70 @@ func TestMergeWithNoData(t *testing.T) {
 	testMergeWith(t, diskData, mergeTargetData, expected)
 }
 
+func TestMergeWithDeletedRanges(t *testing.T) {
+	// This test scenario is when some of the series data has been deleted.
+	// id0 has a range of datapoints deleted, id1 has the whole block deleted
+	// and id2 in the merge target has a single datapoint deleted.
+	diskData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	diskData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(1 * time.Second), Value: 1},
+		{Timestamp: startTime.Add(2 * time.Second), Value: 2},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	diskData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(2 * time.Second), Value: 4},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 5},
+	}))
+
+	mergeTargetData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	mergeTargetData.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 6},
+		{Timestamp: startTime.Add(5 * time.Second), Value: 7},
+	}))
+
+	deleted := map[string]xtime.Ranges{
+		id0.String(): xtime.NewRanges(xtime.Range{
+			Start: startTime.Add(1 * time.Second),
+			End:   startTime.Add(3 * time.Second),
+		}),
+		id1.String(): xtime.NewRanges(xtime.Range{
+			Start: startTime.Add(-blockSize),
+			End:   startTime.Add(2 * blockSize),
+		}),
+		id2.String(): xtime.NewRanges(xtime.Range{
+			Start: startTime.Add(5 * time.Second),
+			End:   startTime.Add(6 * time.Second),
+		}),
+	}
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(0 * time.Second), Value: 0},
+		{Timestamp: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	expected.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{Timestamp: startTime.Add(4 * time.Second), Value: 6},
+	}))
+
+	testMergeWithDeleted(t, diskData, mergeTargetData, deleted, expected)
+}
+
 func testMergeWith(
 	t *testing.T,
 	diskData *checkedBytesMap,
 	mergeTargetData *checkedBytesMap,
 	expectedData *checkedBytesMap,
+) {
+	testMergeWithDeleted(t, diskData, mergeTargetData, nil, expectedData)
+}
+
+func testMergeWithDeleted(
+	t *testing.T,
+	diskData *checkedBytesMap,
+	mergeTargetData *checkedBytesMap,
+	deleted map[string]xtime.Ranges,
+	expectedData *checkedBytesMap,
 ) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -455,13 +514,18 @@ func testMergeWith(
 
 	nsOpts := namespace.NewOptions()
 	merger := NewMerger(reader, 0, srPool, multiIterPool,
-		identPool, encoderPool, contextPool, nsOpts)
+		identPool, encoderPool, contextPool, nsOpts, NewOptions())
 	fsID := FileSetFileIdentifier{
 		Namespace:  ident.StringID("test-ns"),
 		Shard:      uint32(8),
 		BlockStart: startTime,
 	}
 	mergeWith := mockMergeWithFromData(t, ctrl, diskData, mergeTargetData)
+	mergeWith.EXPECT().DeletedRanges(gomock.Any(), gomock.Any(), xtime.ToUnixNano(startTime)).
+		DoAndReturn(func(id ident.ID, _ ident.Tags, _ xtime.UnixNano) xtime.Ranges {
+			return deleted[id.String()]
+		}).
+		AnyTimes()
 	err := merger.Merge(fsID, mergeWith, 1, preparer, nsCtx)
 	require.NoError(t, err)
 
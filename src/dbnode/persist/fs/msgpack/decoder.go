@@ -26,6 +26,7 @@ import (
 	"io"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/pool"
 
@@ -258,6 +259,11 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 		opts.override = true
 		opts.numExpectedMinFields = 6
 		opts.numExpectedCurrFields = 9
+	case legacyEncodingIndexVersionV4:
+		// V4 had 10 fields.
+		opts.override = true
+		opts.numExpectedMinFields = 6
+		opts.numExpectedCurrFields = 10
 	}
 
 	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(indexInfoType, opts)
@@ -301,6 +307,15 @@ func (dec *Decoder) decodeIndexInfo() schema.IndexInfo {
 	// Decode fields added in V4.
 	indexInfo.VolumeIndex = int(dec.decodeVarint())
 
+	// At this point if its a V4 file we've decoded all the available fields.
+	if dec.legacy.decodeLegacyIndexInfoVersion == legacyEncodingIndexVersionV4 || actual < 11 {
+		dec.skip(numFieldsToSkip)
+		return indexInfo
+	}
+
+	// Decode fields added in V5.
+	indexInfo.DataCompression = compression.Type(dec.decodeVarint())
+
 	dec.skip(numFieldsToSkip)
 	return indexInfo
 }
@@ -404,7 +419,7 @@ func (dec *Decoder) decodeIndexSummary() (schema.IndexSummary, IndexSummaryToken
 }
 
 func (dec *Decoder) decodeLogInfo() schema.LogInfo {
-	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
+	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
 	if !ok {
 		return emptyLogInfo
 	}
@@ -415,6 +430,12 @@ func (dec *Decoder) decodeLogInfo() schema.LogInfo {
 	logInfo.DeprecatedDoNotUseDuration = dec.decodeVarint()
 
 	logInfo.Index = dec.decodeVarint()
+
+	// Commit logs written before chunk compression was added only have the
+	// first three fields and are uncompressed.
+	if actual >= 4 {
+		logInfo.ChunkCompression = compression.Type(dec.decodeVarint())
+	}
 	dec.skip(numFieldsToSkip)
 	if dec.err != nil {
 		return emptyLogInfo
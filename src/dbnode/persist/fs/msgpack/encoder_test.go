@@ -82,6 +82,7 @@ func testExpectedResultForIndexInfo(t *testing.T, indexInfo schema.IndexInfo) []
 		int64(indexInfo.FileType),
 		indexInfo.SnapshotID,
 		int64(indexInfo.VolumeIndex),
+		int64(indexInfo.DataCompression),
 	}
 }
 
@@ -113,6 +114,7 @@ func testExpectedResultForLogInfo(t *testing.T, logInfo schema.LogInfo) []interf
 		logInfo.DeprecatedDoNotUseStart,
 		logInfo.DeprecatedDoNotUseDuration,
 		logInfo.Index,
+		int64(logInfo.ChunkCompression),
 	}
 }
 
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/pool"
 
@@ -44,10 +45,11 @@ var (
 			NumElementsM: 2075674,
 			NumHashesK:   7,
 		},
-		SnapshotTime: time.Now().UnixNano(),
-		FileType:     persist.FileSetSnapshotType,
-		SnapshotID:   []byte("some_bytes"),
-		VolumeIndex:  1,
+		SnapshotTime:    time.Now().UnixNano(),
+		FileType:        persist.FileSetSnapshotType,
+		SnapshotID:      []byte("some_bytes"),
+		VolumeIndex:     1,
+		DataCompression: compression.Snappy,
 	}
 
 	testIndexEntry = schema.IndexEntry{
@@ -66,7 +68,8 @@ var (
 	}
 
 	testLogInfo = schema.LogInfo{
-		Index: 234,
+		Index:            234,
+		ChunkCompression: compression.Snappy,
 	}
 
 	testLogEntry = schema.LogEntry{
@@ -99,7 +102,7 @@ func TestIndexInfoRoundtrip(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V4 decoding code can handle the V1 file format.
+// Make sure the V5 decoding code can handle the V1 file format.
 func TestIndexInfoRoundTripBackwardsCompatibilityV1(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{encodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV1}
@@ -117,16 +120,19 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV1(t *testing.T) {
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
 		currVolumeIndex  = testIndexInfo.VolumeIndex
+		currCompression  = testIndexInfo.DataCompression
 	)
 	testIndexInfo.SnapshotTime = 0
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -136,7 +142,7 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV1(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V1 decoder code can handle the V4 file format.
+// Make sure the V1 decoder code can handle the V5 file format.
 func TestIndexInfoRoundTripForwardsCompatibilityV1(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{decodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV1}
@@ -152,6 +158,7 @@ func TestIndexInfoRoundTripForwardsCompatibilityV1(t *testing.T) {
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
 		currVolumeIndex  = testIndexInfo.VolumeIndex
+		currCompression  = testIndexInfo.DataCompression
 	)
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -162,11 +169,13 @@ func TestIndexInfoRoundTripForwardsCompatibilityV1(t *testing.T) {
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	dec.Reset(NewByteDecoderStream(enc.Bytes()))
@@ -175,7 +184,7 @@ func TestIndexInfoRoundTripForwardsCompatibilityV1(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V4 decoding code can handle the V2 file format.
+// Make sure the V5 decoding code can handle the V2 file format.
 func TestIndexInfoRoundTripBackwardsCompatibilityV2(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{encodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV2}
@@ -192,16 +201,19 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV2(t *testing.T) {
 		currFileType     = testIndexInfo.FileType
 		currSnapshotID   = testIndexInfo.SnapshotID
 		currVolumeIndex  = testIndexInfo.VolumeIndex
+		currCompression  = testIndexInfo.DataCompression
 	)
 	testIndexInfo.SnapshotTime = 0
 	testIndexInfo.FileType = 0
 	testIndexInfo.SnapshotID = nil
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.SnapshotTime = currSnapshotTime
 		testIndexInfo.FileType = currFileType
 		testIndexInfo.SnapshotID = currSnapshotID
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -211,7 +223,7 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV2(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V2 decoder code can handle the V4 file format.
+// Make sure the V2 decoder code can handle the V5 file format.
 func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{decodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV2}
@@ -224,6 +236,7 @@ func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 	// because the old decoder won't read the new fields.
 	currSnapshotID := testIndexInfo.SnapshotID
 	currVolumeIndex := testIndexInfo.VolumeIndex
+	currCompression := testIndexInfo.DataCompression
 
 	enc.EncodeIndexInfo(testIndexInfo)
 
@@ -231,9 +244,11 @@ func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 	// encoded the data.
 	testIndexInfo.SnapshotID = nil
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.SnapshotID = currSnapshotID
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	dec.Reset(NewByteDecoderStream(enc.Bytes()))
@@ -242,7 +257,7 @@ func TestIndexInfoRoundTripForwardsCompatibilityV2(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V4 decoding code can handle the V3 file format.
+// Make sure the V5 decoding code can handle the V3 file format.
 func TestIndexInfoRoundTripBackwardsCompatibilityV3(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{encodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV3}
@@ -256,10 +271,13 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV3(t *testing.T) {
 	// the old file format.
 	var (
 		currVolumeIndex = testIndexInfo.VolumeIndex
+		currCompression = testIndexInfo.DataCompression
 	)
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	enc.EncodeIndexInfo(testIndexInfo)
@@ -269,7 +287,7 @@ func TestIndexInfoRoundTripBackwardsCompatibilityV3(t *testing.T) {
 	require.Equal(t, testIndexInfo, res)
 }
 
-// Make sure the V3 decoder code can handle the V4 file format.
+// Make sure the V3 decoder code can handle the V5 file format.
 func TestIndexInfoRoundTripForwardsCompatibilityV3(t *testing.T) {
 	var (
 		opts = legacyEncodingOptions{decodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV3}
@@ -281,14 +299,70 @@ func TestIndexInfoRoundTripForwardsCompatibilityV3(t *testing.T) {
 	// and then restore them at the end of the test - This is required
 	// because the old decoder won't read the new fields.
 	currVolumeIndex := testIndexInfo.VolumeIndex
+	currCompression := testIndexInfo.DataCompression
 
 	enc.EncodeIndexInfo(testIndexInfo)
 
 	// Make sure to zero them before we compare, but after we have
 	// encoded the data.
 	testIndexInfo.VolumeIndex = 0
+	testIndexInfo.DataCompression = 0
 	defer func() {
 		testIndexInfo.VolumeIndex = currVolumeIndex
+		testIndexInfo.DataCompression = currCompression
+	}()
+
+	dec.Reset(NewByteDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeIndexInfo()
+	require.NoError(t, err)
+	require.Equal(t, testIndexInfo, res)
+}
+
+// Make sure the V5 decoding code can handle the V4 file format.
+func TestIndexInfoRoundTripBackwardsCompatibilityV4(t *testing.T) {
+	var (
+		opts = legacyEncodingOptions{encodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV4}
+		enc  = newEncoder(opts)
+		dec  = newDecoder(opts, nil)
+	)
+
+	// Set the default values on the fields that did not exist in V4,
+	// and then restore them at the end of the test - This is required
+	// because the new decoder won't try and read the new fields from
+	// the old file format.
+	currCompression := testIndexInfo.DataCompression
+	testIndexInfo.DataCompression = 0
+	defer func() {
+		testIndexInfo.DataCompression = currCompression
+	}()
+
+	enc.EncodeIndexInfo(testIndexInfo)
+	dec.Reset(NewByteDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeIndexInfo()
+	require.NoError(t, err)
+	require.Equal(t, testIndexInfo, res)
+}
+
+// Make sure the V4 decoder code can handle the V5 file format.
+func TestIndexInfoRoundTripForwardsCompatibilityV4(t *testing.T) {
+	var (
+		opts = legacyEncodingOptions{decodeLegacyIndexInfoVersion: legacyEncodingIndexVersionV4}
+		enc  = newEncoder(opts)
+		dec  = newDecoder(opts, nil)
+	)
+
+	// Set the default values on the fields that did not exist in V4
+	// and then restore them at the end of the test - This is required
+	// because the old decoder won't read the new fields.
+	currCompression := testIndexInfo.DataCompression
+
+	enc.EncodeIndexInfo(testIndexInfo)
+
+	// Make sure to zero them before we compare, but after we have
+	// encoded the data.
+	testIndexInfo.DataCompression = 0
+	defer func() {
+		testIndexInfo.DataCompression = currCompression
 	}()
 
 	dec.Reset(NewByteDecoderStream(enc.Bytes()))
@@ -401,6 +475,27 @@ func TestLogInfoRoundtrip(t *testing.T) {
 	require.Equal(t, testLogInfo, res)
 }
 
+// Make sure the decoder can read the log info of commit logs written before
+// chunk compression was added.
+func TestLogInfoRoundtripBackwardsCompatibility(t *testing.T) {
+	var (
+		enc = NewEncoder()
+		dec = NewDecoder(nil)
+	)
+	enc.encodeRootObject(logInfoVersion, logInfoType)
+	enc.encodeArrayLenFn(3) // Commit logs without chunk compression had 3 fields.
+	enc.encodeVarintFn(testLogInfo.DeprecatedDoNotUseStart)
+	enc.encodeVarintFn(testLogInfo.DeprecatedDoNotUseDuration)
+	enc.encodeVarintFn(testLogInfo.Index)
+	require.NoError(t, enc.err)
+
+	dec.Reset(NewByteDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeLogInfo()
+	require.NoError(t, err)
+	require.Equal(t, testLogInfo.Index, res.Index)
+	require.Equal(t, compression.None, res.ChunkCompression)
+}
+
 func TestLogEntryRoundtrip(t *testing.T) {
 	var (
 		enc = NewEncoder()
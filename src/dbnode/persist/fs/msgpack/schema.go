@@ -99,12 +99,12 @@ const (
 	// correct number of fields is encoded into the files. These values need
 	// to be incremened whenever we add new fields to an object.
 	currNumRootObjectFields           = 2
-	currNumIndexInfoFields            = 10
+	currNumIndexInfoFields            = 11
 	currNumIndexSummariesInfoFields   = 1
 	currNumIndexBloomFilterInfoFields = 2
 	currNumIndexEntryFields           = 6
 	currNumIndexSummaryFields         = 3
-	currNumLogInfoFields              = 3
+	currNumLogInfoFields              = 4
 	currNumLogEntryFields             = 7
 	currNumLogMetadataFields          = 3
 )
@@ -479,6 +479,11 @@ func (pm *persistManager) PrepareData(opts persist.DataPrepareOptions) (persist.
 			VolumeIndex: volumeIndex,
 		},
 	}
+	if opts.FileSetType == persist.FileSetFlushType {
+		// Snapshots are short lived and frequently rewritten so only flushed
+		// filesets are worth the cost of compressing.
+		dataWriterOpts.DataCompression = nsMetadata.Options().DataCompression()
+	}
 	if err := pm.dataPM.writer.Open(dataWriterOpts); err != nil {
 		return prepared, err
 	}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/checked"
@@ -79,6 +80,8 @@ type reader struct {
 
 	entries         int
 	bloomFilterInfo schema.IndexBloomFilterInfo
+	compression     compression.Type
+	compressedBuf   []byte
 	entriesRead     int
 	metadataRead    int
 	decoder         *msgpack.Decoder
@@ -136,6 +139,11 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		err         error
 	)
 
+	filePathPrefix := r.filePathPrefix
+	if opts.FilePathPrefix != "" {
+		filePathPrefix = opts.FilePathPrefix
+	}
+
 	var (
 		shardDir            string
 		checkpointFilepath  string
@@ -148,7 +156,7 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 
 	switch opts.FileSetType {
 	case persist.FileSetSnapshotType:
-		shardDir = ShardSnapshotsDirPath(r.filePathPrefix, namespace, shard)
+		shardDir = ShardSnapshotsDirPath(filePathPrefix, namespace, shard)
 		checkpointFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, checkpointFileSuffix)
 		infoFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, infoFileSuffix)
 		digestFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, digestFileSuffix)
@@ -156,7 +164,7 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 		indexFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, indexFileSuffix)
 		dataFilepath = filesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, dataFileSuffix)
 	case persist.FileSetFlushType:
-		shardDir = ShardDataDirPath(r.filePathPrefix, namespace, shard)
+		shardDir = ShardDataDirPath(filePathPrefix, namespace, shard)
 
 		isLegacy := false
 		if volumeIndex == 0 {
@@ -260,6 +268,8 @@ func (r *reader) Status() DataFileSetReaderStatus {
 		Volume:     r.volume,
 		BlockStart: r.start,
 		BlockSize:  time.Duration(r.blockSize),
+
+		DataCompression: r.compression,
 	}
 }
 
@@ -302,6 +312,7 @@ func (r *reader) readInfo(size int) error {
 	r.entriesRead = 0
 	r.metadataRead = 0
 	r.bloomFilterInfo = info.BloomFilter
+	r.compression = info.DataCompression
 	return nil
 }
 
@@ -335,6 +346,27 @@ func (r *reader) Read() (ident.ID, ident.TagIterator, checked.Bytes, uint32, err
 
 	entry := r.indexEntriesByOffsetAsc[r.entriesRead]
 
+	var (
+		data checked.Bytes
+		err  error
+	)
+	if r.compression != compression.None {
+		data, err = r.readCompressedData(entry)
+	} else {
+		data, err = r.readData(entry)
+	}
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	id := r.entryClonedID(entry.ID)
+	tags := r.entryClonedEncodedTagsIter(entry.EncodedTags)
+
+	r.entriesRead++
+	return id, tags, data, uint32(entry.Checksum), nil
+}
+
+func (r *reader) readData(entry schema.IndexEntry) (checked.Bytes, error) {
 	var data checked.Bytes
 	if r.bytesPool != nil {
 		data = r.bytesPool.Get(int(entry.Size))
@@ -349,17 +381,49 @@ func (r *reader) Read() (ident.ID, ident.TagIterator, checked.Bytes, uint32, err
 
 	n, err := r.dataReader.Read(data.Bytes())
 	if err != nil {
-		return nil, nil, nil, 0, err
+		return nil, err
 	}
 	if n != int(entry.Size) {
-		return nil, nil, nil, 0, errReadNotExpectedSize
+		return nil, errReadNotExpectedSize
 	}
+	return data, nil
+}
 
-	id := r.entryClonedID(entry.ID)
-	tags := r.entryClonedEncodedTagsIter(entry.EncodedTags)
+func (r *reader) readCompressedData(entry schema.IndexEntry) (checked.Bytes, error) {
+	if cap(r.compressedBuf) < int(entry.Size) {
+		r.compressedBuf = make([]byte, entry.Size)
+	}
+	compressed := r.compressedBuf[:entry.Size]
 
-	r.entriesRead++
-	return id, tags, data, uint32(entry.Checksum), nil
+	n, err := r.dataReader.Read(compressed)
+	if err != nil {
+		return nil, err
+	}
+	if n != int(entry.Size) {
+		return nil, errReadNotExpectedSize
+	}
+
+	size, err := decompressedDataLen(r.compression, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	var data checked.Bytes
+	if r.bytesPool != nil {
+		data = r.bytesPool.Get(size)
+		data.IncRef()
+		defer data.DecRef()
+		data.Resize(size)
+	} else {
+		data = checked.NewBytes(make([]byte, size), nil)
+		data.IncRef()
+		defer data.DecRef()
+	}
+
+	if _, err := decompressData(r.compression, data.Bytes(), compressed); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (r *reader) ReadMetadata() (ident.ID, ident.TagIterator, int, uint32, error) {
@@ -368,9 +432,19 @@ func (r *reader) ReadMetadata() (ident.ID, ident.TagIterator, int, uint32, error
 	}
 
 	entry := r.indexEntriesByOffsetAsc[r.metadataRead]
+	length := int(entry.Size)
+	if r.compression != compression.None {
+		// The index entry records the size of the compressed data, the length
+		// of the series is stored with the compressed data itself.
+		compressed := r.dataMmap[entry.Offset : entry.Offset+entry.Size]
+		decompressedLen, err := decompressedDataLen(r.compression, compressed)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		length = decompressedLen
+	}
 	id := r.entryClonedID(entry.ID)
 	tags := r.entryClonedEncodedTagsIter(entry.EncodedTags)
-	length := int(entry.Size)
 	checksum := uint32(entry.Checksum)
 
 	r.metadataRead++
@@ -489,6 +563,7 @@ func (r *reader) Close() error {
 	bytesPool := r.bytesPool
 	tagDecoderPool := r.tagDecoderPool
 	indexEntriesByOffsetAsc := r.indexEntriesByOffsetAsc
+	compressedBuf := r.compressedBuf
 
 	// Reset struct
 	*r = reader{}
@@ -507,6 +582,7 @@ func (r *reader) Close() error {
 	r.bytesPool = bytesPool
 	r.tagDecoderPool = tagDecoderPool
 	r.indexEntriesByOffsetAsc = indexEntriesByOffsetAsc
+	r.compressedBuf = compressedBuf
 
 	return multiErr.FinalError()
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/bloom"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -120,6 +121,15 @@ func writeTestDataWithVolume(
 		writerOpts.Snapshot.SnapshotID = testSnapshotID
 	}
 
+	writeTestDataWithOpenOptions(t, w, writerOpts, entries)
+}
+
+func writeTestDataWithOpenOptions(
+	t *testing.T,
+	w DataFileSetWriter,
+	writerOpts DataWriterOpenOptions,
+	entries []testEntry,
+) {
 	err := w.Open(writerOpts)
 	assert.NoError(t, err)
 
@@ -272,6 +282,55 @@ func TestSimpleReadWrite(t *testing.T) {
 	readTestData(t, r, 0, testWriterStart, entries)
 }
 
+func TestCompressedReadWrite(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	entries := []testEntry{
+		{"foo", nil, []byte{1, 2, 3}},
+		{"bar", nil, []byte{4, 5, 6}},
+		{"baz", nil, make([]byte, 65536)},
+		{"cat", nil, make([]byte, 100000)},
+		{"foo+bar=baz,qux=qaz", map[string]string{
+			"bar": "baz",
+			"qux": "qaz",
+		}, []byte{7, 8, 9}},
+	}
+
+	w := newTestWriter(t, filePathPrefix)
+	writeTestDataWithOpenOptions(t, w, DataWriterOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+		BlockSize:       testBlockSize,
+		FileSetType:     persist.FileSetFlushType,
+		DataCompression: compression.Snappy,
+	}, entries)
+
+	r := newTestReader(t, filePathPrefix)
+	readTestData(t, r, 0, testWriterStart, entries)
+
+	err := r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, compression.Snappy, r.Status().DataCompression)
+	for i := 0; i < r.Entries(); i++ {
+		_, _, data, _, err := r.Read()
+		require.NoError(t, err)
+		data.Finalize()
+	}
+	require.NoError(t, r.Validate())
+	require.NoError(t, r.Close())
+}
+
 func TestCheckpointFileSizeBytesSize(t *testing.T) {
 	// These values need to match so that the logic for determining whether
 	// a checkpoint file is complete or not remains correct.
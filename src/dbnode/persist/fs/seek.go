@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	xmsgpack "github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/checked"
@@ -64,8 +65,9 @@ type seeker struct {
 
 	// Data read from the indexInfo file. Note that we use xtime.UnixNano
 	// instead of time.Time to avoid keeping an extra pointer around.
-	start     xtime.UnixNano
-	blockSize time.Duration
+	start       xtime.UnixNano
+	blockSize   time.Duration
+	compression compression.Type
 
 	dataFd        *os.File
 	indexFd       *os.File
@@ -223,6 +225,7 @@ func (s *seeker) Open(
 	}
 	s.start = xtime.UnixNano(info.BlockStart)
 	s.blockSize = time.Duration(info.BlockSize)
+	s.compression = info.DataCompression
 
 	err = s.validateIndexFileDigest(
 		indexFdWithDigest, expectedDigests.indexDigest)
@@ -326,6 +329,10 @@ func (s *seeker) SeekByIndexEntry(
 	entry IndexEntry,
 	resources ReusableSeekerResources,
 ) (checked.Bytes, error) {
+	if s.compression != compression.None {
+		return s.seekCompressedByIndexEntry(entry, resources)
+	}
+
 	resources.offsetFileReader.reset(s.dataFd, entry.Offset)
 
 	// Obtain an appropriately sized buffer.
@@ -363,6 +370,51 @@ func (s *seeker) SeekByIndexEntry(
 	return buffer, nil
 }
 
+// seekCompressedByIndexEntry reads the compressed data of a series in a single
+// read and decompresses it, the size of an index entry is the size of the
+// compressed data while the checksum is of the decompressed data.
+func (s *seeker) seekCompressedByIndexEntry(
+	entry IndexEntry,
+	resources ReusableSeekerResources,
+) (checked.Bytes, error) {
+	resources.offsetFileReader.reset(s.dataFd, entry.Offset)
+
+	compressed := resources.compressedDataBytesPool.Get(int(entry.Size))[:entry.Size]
+	defer resources.compressedDataBytesPool.Put(compressed)
+
+	if _, err := io.ReadFull(resources.offsetFileReader, compressed); err != nil {
+		return nil, err
+	}
+
+	size, err := decompressedDataLen(s.compression, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtain an appropriately sized buffer.
+	var buffer checked.Bytes
+	if s.opts.bytesPool != nil {
+		buffer = s.opts.bytesPool.Get(size)
+		buffer.IncRef()
+		defer buffer.DecRef()
+		buffer.Resize(size)
+	} else {
+		buffer = checked.NewBytes(make([]byte, size), nil)
+		buffer.IncRef()
+		defer buffer.DecRef()
+	}
+
+	underlyingBuf, err := decompressData(s.compression, buffer.Bytes(), compressed)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Checksum != digest.Checksum(underlyingBuf) {
+		return nil, errSeekChecksumMismatch
+	}
+
+	return buffer, nil
+}
+
 // SeekIndexEntry performs the following steps:
 //
 //     1. Go to the indexLookup and it will give us an offset that is a good starting
@@ -493,6 +545,7 @@ func (s *seeker) ConcurrentClone() (ConcurrentDataFileSetSeeker, error) {
 
 	seeker := &seeker{
 		opts:          s.opts,
+		compression:   s.compression,
 		indexFileSize: s.indexFileSize,
 		// BloomFilter is concurrency safe.
 		bloomFilter: s.bloomFilter,
@@ -542,6 +595,9 @@ type ReusableSeekerResources struct {
 	// since the ReusableSeekerResources is only ever used by a single seeker at
 	// a time, we can size this pool such that it almost never has to allocate.
 	decodeIndexEntryBytesPool pool.BytesPool
+	// This pool is used to read the compressed data of a series before it is
+	// decompressed into a buffer from the seeker's bytes pool.
+	compressedDataBytesPool pool.BytesPool
 
 	seekerOpenResources reusableSeekerOpenResources
 }
@@ -575,6 +631,7 @@ func NewReusableSeekerResources(opts Options) ReusableSeekerResources {
 		byteDecoderStream:         xmsgpack.NewByteDecoderStream(nil),
 		offsetFileReader:          newOffsetFileReader(),
 		decodeIndexEntryBytesPool: newSimpleBytesPool(),
+		compressedDataBytesPool:   newSimpleBytesPool(),
 		seekerOpenResources:       newReusableSeekerOpenResources(opts),
 	}
 }
@@ -736,8 +736,11 @@ func (m *seekerManager) newOpenSeeker(
 	blockStart time.Time,
 	volume int,
 ) (DataFileSetSeeker, error) {
-	exists, err := DataFileSetExists(
-		m.filePathPrefix, m.namespace, shard, blockStart, volume)
+	// NB: the fileset may have been moved to the namespace's secondary tier.
+	filePathPrefixes := DataFilePathPrefixes(m.filePathPrefix,
+		m.namespaceMetadata.Options())
+	filePathPrefix, exists, err := DataFileSetFilePathPrefix(
+		filePathPrefixes, m.namespace, shard, blockStart, volume)
 	if err != nil {
 		return nil, err
 	}
@@ -752,7 +755,7 @@ func (m *seekerManager) newOpenSeeker(
 	defer m.unreadBuf.Unlock()
 
 	seekerIface := NewSeeker(
-		m.filePathPrefix,
+		filePathPrefix,
 		m.opts.DataReaderBufferSize(),
 		m.opts.InfoReaderBufferSize(),
 		m.bytesPool,
@@ -21,6 +21,7 @@
 package fs
 
 import (
+	"bytes"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -29,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/stretchr/testify/assert"
@@ -224,6 +226,76 @@ func TestSeek(t *testing.T) {
 	assert.NoError(t, s.Close())
 }
 
+func TestSeekCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	w := newTestWriter(t, filePathPrefix)
+	writerOpts := DataWriterOpenOptions{
+		BlockSize: testBlockSize,
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: testWriterStart,
+		},
+		DataCompression: compression.Snappy,
+	}
+	largeData := bytes.Repeat([]byte{1, 2, 3}, 10000)
+	err = w.Open(writerOpts)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(
+		ident.StringID("foo1"),
+		ident.NewTags(ident.StringTag("num", "1")),
+		bytesRefd([]byte{1, 2, 1}),
+		digest.Checksum([]byte{1, 2, 1})))
+	assert.NoError(t, w.Write(
+		ident.StringID("foo2"),
+		ident.NewTags(ident.StringTag("num", "2")),
+		bytesRefd(largeData),
+		digest.Checksum(largeData)))
+	assert.NoError(t, w.Close())
+
+	resources := newTestReusableSeekerResources()
+	s := newTestSeeker(filePathPrefix)
+	err = s.Open(testNs1ID, 0, testWriterStart, 0, resources)
+	assert.NoError(t, err)
+
+	entry, err := s.SeekIndexEntry(ident.StringID("foo2"), resources)
+	require.NoError(t, err)
+	require.True(t, int(entry.Size) < len(largeData))
+
+	data, err := s.SeekByIndexEntry(entry, resources)
+	require.NoError(t, err)
+
+	data.IncRef()
+	defer data.DecRef()
+	assert.Equal(t, largeData, data.Bytes())
+
+	data, err = s.SeekByID(ident.StringID("foo1"), resources)
+	require.NoError(t, err)
+
+	data.IncRef()
+	defer data.DecRef()
+	assert.Equal(t, []byte{1, 2, 1}, data.Bytes())
+
+	clone, err := s.ConcurrentClone()
+	require.NoError(t, err)
+
+	data, err = clone.SeekByID(ident.StringID("foo2"), resources)
+	require.NoError(t, err)
+
+	data.IncRef()
+	defer data.DecRef()
+	assert.Equal(t, largeData, data.Bytes())
+
+	assert.NoError(t, clone.Close())
+	assert.NoError(t, s.Close())
+}
+
 // TestSeekIDNotExists is similar to TestSeek, but it covers more edge cases
 // around IDs not existing.
 func TestSeekIDNotExists(t *testing.T) {
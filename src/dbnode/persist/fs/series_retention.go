@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+)
+
+// SeriesRetentionMarkerFilePath returns the path of the file that records
+// which series retention rules a data fileset under the given file path
+// prefix was written with. Like the downsampled marker it lives beside the
+// files of the fileset.
+func SeriesRetentionMarkerFilePath(filePathPrefix string, id FileSetFileIdentifier) string {
+	shardDir := ShardDataDirPath(filePathPrefix, id.Namespace, id.Shard)
+	return dataFilesetPathFromTimeAndIndex(shardDir, id.BlockStart,
+		id.VolumeIndex, seriesRetentionSuffix, false)
+}
+
+// ReadSeriesRetentionMarker returns the digest of the series retention rules
+// a data fileset under the given file path prefix was written with, and
+// false if the fileset has no valid marker.
+func ReadSeriesRetentionMarker(
+	filePathPrefix string,
+	id FileSetFileIdentifier,
+) (uint32, bool, error) {
+	data, err := ioutil.ReadFile(SeriesRetentionMarkerFilePath(filePathPrefix, id))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if len(data) != digest.DigestLenBytes {
+		// A torn marker is treated as missing so the rules get reapplied.
+		return 0, false, nil
+	}
+	return digest.ToBuffer(data).ReadDigest(), true, nil
+}
+
+// WriteSeriesRetentionMarker records the digest of the series retention
+// rules a data fileset under the given file path prefix was written with.
+func WriteSeriesRetentionMarker(
+	filePathPrefix string,
+	id FileSetFileIdentifier,
+	rulesDigest uint32,
+	newFileMode os.FileMode,
+) error {
+	fd, err := OpenWritable(SeriesRetentionMarkerFilePath(filePathPrefix, id), newFileMode)
+	if err != nil {
+		return err
+	}
+	if err := digest.NewBuffer().WriteDigestToFile(fd, rulesDigest); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
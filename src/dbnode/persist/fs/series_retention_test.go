@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesRetentionMarker(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		blockStart = time.Unix(0, 0).Add(testBlockSize)
+		entries    = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+		}
+	)
+	w := newTestWriter(t, dir)
+	writeTestData(t, w, 0, blockStart, entries, persist.FileSetFlushType)
+
+	fileSets, err := DataFiles(dir, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Len(t, fileSets, 1)
+	numFiles := len(fileSets[0].AbsoluteFilepaths)
+
+	id := fileSets[0].ID
+	_, ok, err := ReadSeriesRetentionMarker(dir, id)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, WriteSeriesRetentionMarker(dir, id, 42, 0666))
+	rulesDigest, ok, err := ReadSeriesRetentionMarker(dir, id)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint32(42), rulesDigest)
+
+	// The marker is listed with the rest of the fileset.
+	fileSets, err = DataFiles(dir, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Len(t, fileSets, 1)
+	require.Len(t, fileSets[0].AbsoluteFilepaths, numFiles+1)
+	require.Contains(t, fileSets[0].AbsoluteFilepaths, SeriesRetentionMarkerFilePath(dir, id))
+
+	// Torn markers are treated as missing.
+	require.NoError(t, ioutil.WriteFile(SeriesRetentionMarkerFilePath(dir, id), []byte{1}, 0666))
+	_, ok, err = ReadSeriesRetentionMarker(dir, id)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
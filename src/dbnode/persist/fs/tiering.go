@@ -0,0 +1,195 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// DataFilePathPrefixes returns the file path prefixes that the data filesets
+// of a namespace can live under, in the order they should be looked up in.
+// Filesets are copied in full to the tiered file path prefix before they are
+// removed from the primary file path prefix, so the tiered file path prefix
+// is looked up first.
+func DataFilePathPrefixes(filePathPrefix string, nsOpts namespace.Options) []string {
+	tieringOpts := nsOpts.TieringOptions()
+	if !tieringOpts.Enabled() {
+		return []string{filePathPrefix}
+	}
+
+	return []string{tieringOpts.FilePathPrefix(), filePathPrefix}
+}
+
+// DataFileSetFilePathPrefix returns the first of the file path prefixes that
+// a complete data fileset exists under, and whether one was found.
+func DataFileSetFilePathPrefix(
+	filePathPrefixes []string,
+	namespace ident.ID,
+	shard uint32,
+	blockStart time.Time,
+	volume int,
+) (string, bool, error) {
+	for _, filePathPrefix := range filePathPrefixes {
+		exists, err := DataFileSetExists(filePathPrefix, namespace, shard, blockStart, volume)
+		if err != nil {
+			return "", false, err
+		}
+		if exists {
+			return filePathPrefix, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// TieredReadInfoFileResult is the result of reading a data info file under
+// one of a set of file path prefixes.
+type TieredReadInfoFileResult struct {
+	ReadInfoFileResult
+
+	// FilePathPrefix is the file path prefix the info file was read from.
+	FilePathPrefix string
+}
+
+type blockStartAndVolume struct {
+	blockStart xtime.UnixNano
+	volume     int
+}
+
+// ReadTieredInfoFiles reads all the valid data info entries under each of the
+// file path prefixes. A fileset that exists under more than one of the file
+// path prefixes, which happens if moving it between tiers was interrupted, is
+// only returned for the first of them.
+func ReadTieredInfoFiles(
+	filePathPrefixes []string,
+	namespace ident.ID,
+	shard uint32,
+	readerBufferSize int,
+	decodingOpts msgpack.DecodingOptions,
+) []TieredReadInfoFileResult {
+	var (
+		results []TieredReadInfoFileResult
+		seen    = make(map[blockStartAndVolume]struct{})
+	)
+	for _, filePathPrefix := range filePathPrefixes {
+		infoFiles := ReadInfoFiles(filePathPrefix, namespace, shard,
+			readerBufferSize, decodingOpts)
+		for _, result := range infoFiles {
+			if result.Err.Error() == nil {
+				key := blockStartAndVolume{
+					blockStart: xtime.UnixNano(result.Info.BlockStart),
+					volume:     result.Info.VolumeIndex,
+				}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+
+			results = append(results, TieredReadInfoFileResult{
+				ReadInfoFileResult: result,
+				FilePathPrefix:     filePathPrefix,
+			})
+		}
+	}
+
+	return results
+}
+
+// CopyDataFileSet copies a complete data fileset to the same location under
+// another file path prefix. The checkpoint file is copied last so that the
+// copy is only considered complete once all of its files have been copied,
+// and nothing is copied if a complete copy already exists.
+func CopyDataFileSet(
+	fileSet FileSetFile,
+	toFilePathPrefix string,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error {
+	id := fileSet.ID
+	if !fileSet.HasCompleteCheckpointFile() {
+		return fmt.Errorf("data fileset for namespace %s shard %d block start %v volume %d is not complete",
+			id.Namespace, id.Shard, id.BlockStart, id.VolumeIndex)
+	}
+
+	exists, err := DataFileSetExists(toFilePathPrefix, id.Namespace, id.Shard,
+		id.BlockStart, id.VolumeIndex)
+	if err != nil || exists {
+		return err
+	}
+
+	shardDir := ShardDataDirPath(toFilePathPrefix, id.Namespace, id.Shard)
+	if err := os.MkdirAll(shardDir, newDirectoryMode); err != nil {
+		return err
+	}
+
+	var checkpointFilePath string
+	for _, filePath := range fileSet.AbsoluteFilepaths {
+		if strings.Contains(filePath, checkpointFileSuffix) {
+			checkpointFilePath = filePath
+			continue
+		}
+
+		toFilePath := filepath.Join(shardDir, filepath.Base(filePath))
+		if err := copyFile(filePath, toFilePath, newFileMode); err != nil {
+			return err
+		}
+	}
+
+	toFilePath := filepath.Join(shardDir, filepath.Base(checkpointFilePath))
+	return copyFile(checkpointFilePath, toFilePath, newFileMode)
+}
+
+func copyFile(fromFilePath, toFilePath string, perm os.FileMode) error {
+	from, err := os.Open(fromFilePath)
+	if err != nil {
+		return err
+	}
+
+	defer from.Close()
+
+	to, err := OpenWritable(toFilePath, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(to, from); err != nil {
+		to.Close()
+		return err
+	}
+
+	if err := to.Sync(); err != nil {
+		to.Close()
+		return err
+	}
+
+	return to.Close()
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataFilePathPrefixes(t *testing.T) {
+	opts := namespace.NewOptions()
+	require.Equal(t, []string{"/primary"}, DataFilePathPrefixes("/primary", opts))
+
+	opts = opts.SetTieringOptions(namespace.NewTieringOptions().
+		SetEnabled(true).
+		SetFilePathPrefix("/secondary"))
+	require.Equal(t, []string{"/secondary", "/primary"}, DataFilePathPrefixes("/primary", opts))
+}
+
+func TestCopyDataFileSetAndReadTiered(t *testing.T) {
+	primary := createTempDir(t)
+	defer os.RemoveAll(primary)
+	secondary := createTempDir(t)
+	defer os.RemoveAll(secondary)
+
+	var (
+		blockStart = time.Unix(0, 0).Add(testBlockSize)
+		entries    = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", nil, []byte{4, 5, 6}},
+		}
+		prefixes = []string{secondary, primary}
+	)
+	w := newTestWriter(t, primary)
+	writeTestData(t, w, 0, blockStart, entries, persist.FileSetFlushType)
+	writeTestData(t, w, 0, blockStart.Add(testBlockSize), entries, persist.FileSetFlushType)
+
+	prefix, ok, err := DataFileSetFilePathPrefix(prefixes, testNs1ID, 0, blockStart, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, primary, prefix)
+
+	fileSets, err := DataFiles(primary, testNs1ID, 0)
+	require.NoError(t, err)
+	require.Len(t, fileSets, 2)
+	require.NoError(t, CopyDataFileSet(fileSets[0], secondary, 0666, 0755))
+	// Copying again is a no-op since the copy is complete.
+	require.NoError(t, CopyDataFileSet(fileSets[0], secondary, 0666, 0755))
+
+	prefix, ok, err = DataFileSetFilePathPrefix(prefixes, testNs1ID, 0, blockStart, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, secondary, prefix)
+
+	prefix, ok, err = DataFileSetFilePathPrefix(prefixes, testNs1ID, 0, blockStart.Add(testBlockSize), 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, primary, prefix)
+
+	_, ok, err = DataFileSetFilePathPrefix(prefixes, testNs1ID, 0, blockStart.Add(2*testBlockSize), 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// The fileset that exists under both prefixes is only read once.
+	results := ReadTieredInfoFiles(prefixes, testNs1ID, 0,
+		testReaderBufferSize, testDefaultOpts.DecodingOptions())
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.NoError(t, result.Err.Error())
+	}
+	require.Equal(t, blockStart.UnixNano(), results[0].Info.BlockStart)
+	require.Equal(t, secondary, results[0].FilePathPrefix)
+	require.Equal(t, blockStart.Add(testBlockSize).UnixNano(), results[1].Info.BlockStart)
+	require.Equal(t, primary, results[1].FilePathPrefix)
+
+	// Remove the fileset from the primary and read it from the secondary.
+	require.NoError(t, DeleteFiles(fileSets[0].AbsoluteFilepaths))
+	r := newTestReader(t, primary)
+	require.NoError(t, r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      0,
+			BlockStart: blockStart,
+		},
+		FilePathPrefix: secondary,
+	}))
+	require.Equal(t, len(entries), r.Entries())
+	require.NoError(t, r.Close())
+}
+
+func TestCopyDataFileSetIncomplete(t *testing.T) {
+	primary := createTempDir(t)
+	defer os.RemoveAll(primary)
+	secondary := createTempDir(t)
+	defer os.RemoveAll(secondary)
+
+	fileSet := NewFileSetFile(FileSetFileIdentifier{
+		Namespace:  testNs1ID,
+		BlockStart: time.Unix(0, 0),
+	}, primary)
+	require.Error(t, CopyDataFileSet(fileSet, secondary, 0666, 0755))
+
+	_, err := os.Stat(filepath.Join(secondary, dataDirName))
+	require.True(t, os.IsNotExist(err))
+}
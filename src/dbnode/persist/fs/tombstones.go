@@ -0,0 +1,274 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	shardTombstonesFileName = "tombstones" + fileSuffix
+	shardTombstonesVersion  = 1
+)
+
+var (
+	errShardTombstonesChecksumMismatch = errors.New("shard tombstones file checksum mismatch")
+	errShardTombstonesUnknownVersion   = errors.New("shard tombstones file has unknown version")
+	errShardTombstonesTruncated        = errors.New("shard tombstones file is truncated")
+)
+
+// ShardTombstones are the time ranges of series data deleted from a shard
+// along with the block starts whose filesets may still hold deleted data.
+type ShardTombstones struct {
+	Deleted  map[string]xtime.Ranges
+	Unmerged []xtime.UnixNano
+}
+
+// ShardTombstonesFilePath returns the path of the file that records the
+// tombstones of a shard under the given file path prefix.
+func ShardTombstonesFilePath(prefix string, namespace ident.ID, shard uint32) string {
+	return path.Join(ShardDataDirPath(prefix, namespace, shard), shardTombstonesFileName)
+}
+
+// ReadShardTombstones reads the tombstones of a shard, a shard without a
+// tombstones file has no tombstones.
+func ReadShardTombstones(
+	prefix string,
+	namespace ident.ID,
+	shard uint32,
+) (ShardTombstones, error) {
+	data, err := ioutil.ReadFile(ShardTombstonesFilePath(prefix, namespace, shard))
+	if os.IsNotExist(err) {
+		return ShardTombstones{}, nil
+	}
+	if err != nil {
+		return ShardTombstones{}, err
+	}
+	return decodeShardTombstones(data)
+}
+
+// WriteShardTombstones replaces the tombstones file of a shard. The file is
+// written beside the existing one and renamed over it so that a crash never
+// leaves a partially written file behind.
+func WriteShardTombstones(
+	prefix string,
+	namespace ident.ID,
+	shard uint32,
+	tombstones ShardTombstones,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error {
+	shardDir := ShardDataDirPath(prefix, namespace, shard)
+	if err := os.MkdirAll(shardDir, newDirectoryMode); err != nil {
+		return err
+	}
+
+	var (
+		filePath = ShardTombstonesFilePath(prefix, namespace, shard)
+		tmpPath  = filePath + ".tmp"
+	)
+	fd, err := OpenWritable(tmpPath, newFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(encodeShardTombstones(tombstones)); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(shardDir)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+// encodeShardTombstones encodes the tombstones as a version followed by
+// the deleted ranges of every series, the unmerged block starts and a
+// checksum of everything before it.
+func encodeShardTombstones(tombstones ShardTombstones) []byte {
+	ids := make([]string, 0, len(tombstones.Deleted))
+	for id := range tombstones.Deleted {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	buf := make([]byte, 0, 64*len(ids)+8*len(tombstones.Unmerged)+16)
+	buf = appendUvarint(buf, shardTombstonesVersion)
+	buf = appendUvarint(buf, uint64(len(ids)))
+	for _, id := range ids {
+		ranges := tombstones.Deleted[id]
+		buf = appendUvarint(buf, uint64(len(id)))
+		buf = append(buf, id...)
+		buf = appendUvarint(buf, uint64(ranges.Len()))
+		for it := ranges.Iter(); it.Next(); {
+			r := it.Value()
+			buf = appendVarint(buf, r.Start.UnixNano())
+			buf = appendVarint(buf, r.End.UnixNano())
+		}
+	}
+	buf = appendUvarint(buf, uint64(len(tombstones.Unmerged)))
+	for _, blockStart := range tombstones.Unmerged {
+		buf = appendVarint(buf, int64(blockStart))
+	}
+
+	checksum := digest.NewBuffer()
+	checksum.WriteDigest(digest.Checksum(buf))
+	return append(buf, checksum...)
+}
+
+func decodeShardTombstones(data []byte) (ShardTombstones, error) {
+	if len(data) < digest.DigestLenBytes {
+		return ShardTombstones{}, errShardTombstonesTruncated
+	}
+	var (
+		n        = len(data) - digest.DigestLenBytes
+		expected = digest.ToBuffer(data[n:]).ReadDigest()
+	)
+	data = data[:n]
+	if digest.Checksum(data) != expected {
+		return ShardTombstones{}, errShardTombstonesChecksumMismatch
+	}
+
+	d := tombstonesDecoder{data: data}
+	if version := d.uvarint(); d.err == nil && version != shardTombstonesVersion {
+		return ShardTombstones{}, errShardTombstonesUnknownVersion
+	}
+
+	numIDs := d.uvarint()
+	result := ShardTombstones{
+		Deleted: make(map[string]xtime.Ranges, d.capacity(numIDs)),
+	}
+	for i := uint64(0); i < numIDs && d.err == nil; i++ {
+		var (
+			id        = string(d.bytes(d.uvarint()))
+			numRanges = d.uvarint()
+			ranges    = xtime.Ranges{}
+		)
+		for j := uint64(0); j < numRanges && d.err == nil; j++ {
+			ranges = ranges.AddRange(xtime.Range{
+				Start: time.Unix(0, d.varint()),
+				End:   time.Unix(0, d.varint()),
+			})
+		}
+		result.Deleted[id] = ranges
+	}
+
+	numUnmerged := d.uvarint()
+	result.Unmerged = make([]xtime.UnixNano, 0, d.capacity(numUnmerged))
+	for i := uint64(0); i < numUnmerged && d.err == nil; i++ {
+		result.Unmerged = append(result.Unmerged, xtime.UnixNano(d.varint()))
+	}
+
+	if d.err != nil {
+		return ShardTombstones{}, d.err
+	}
+	return result, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	return append(buf, scratch[:n]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(scratch[:], v)
+	return append(buf, scratch[:n]...)
+}
+
+type tombstonesDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *tombstonesDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errShardTombstonesTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *tombstonesDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errShardTombstonesTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *tombstonesDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.data)) < n {
+		d.err = errShardTombstonesTruncated
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// capacity bounds a decoded count by the remaining bytes so that a corrupt
+// count cannot cause a huge allocation.
+func (d *tombstonesDecoder) capacity(n uint64) int {
+	if remaining := uint64(len(d.data)); n > remaining {
+		return int(remaining)
+	}
+	return int(n)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardTombstonesReadWrite(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	// Shards without a tombstones file have no tombstones.
+	tombstones, err := ReadShardTombstones(dir, testNs1ID, 1)
+	require.NoError(t, err)
+	require.Empty(t, tombstones.Deleted)
+	require.Empty(t, tombstones.Unmerged)
+
+	start := time.Unix(0, 0).Add(testBlockSize)
+	written := ShardTombstones{
+		Deleted: map[string]xtime.Ranges{
+			"foo": xtime.NewRanges(
+				xtime.Range{Start: start, End: start.Add(time.Minute)},
+				xtime.Range{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)},
+			),
+			"bar": xtime.NewRanges(xtime.Range{Start: start, End: start.Add(testBlockSize)}),
+		},
+		Unmerged: []xtime.UnixNano{xtime.ToUnixNano(start)},
+	}
+	require.NoError(t, WriteShardTombstones(dir, testNs1ID, 1, written, 0666, 0755))
+
+	tombstones, err = ReadShardTombstones(dir, testNs1ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, written.Unmerged, tombstones.Unmerged)
+	require.Len(t, tombstones.Deleted, 2)
+	for id, ranges := range written.Deleted {
+		require.Equal(t, ranges.String(), tombstones.Deleted[id].String())
+	}
+
+	// The tombstones file is not mistaken for a fileset.
+	fileSets, err := DataFiles(dir, testNs1ID, 1)
+	require.NoError(t, err)
+	require.Empty(t, fileSets)
+
+	// Corrupt files are rejected rather than silently dropping tombstones.
+	filePath := ShardTombstonesFilePath(dir, testNs1ID, 1)
+	data, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	data[len(data)/2]++
+	require.NoError(t, ioutil.WriteFile(filePath, data, 0666))
+	_, err = ReadShardTombstones(dir, testNs1ID, 1)
+	require.Equal(t, errShardTombstonesChecksumMismatch, err)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -61,6 +62,8 @@ type DataWriterOpenOptions struct {
 	FileSetContentType persist.FileSetContentType
 	Identifier         FileSetFileIdentifier
 	BlockSize          time.Duration
+	// DataCompression is the compression applied to the data of each series.
+	DataCompression compression.Type
 	// Only used when writing snapshot files
 	Snapshot DataWriterSnapshotOptions
 }
@@ -109,12 +112,17 @@ type DataFileSetReaderStatus struct {
 	Volume     int
 	Open       bool
 	BlockSize  time.Duration
+	// DataCompression is the compression applied to the data of each series.
+	DataCompression compression.Type
 }
 
 // DataReaderOpenOptions is options struct for the reader open method.
 type DataReaderOpenOptions struct {
 	Identifier  FileSetFileIdentifier
 	FileSetType persist.FileSetType
+	// FilePathPrefix, if set, overrides the file path prefix of the reader
+	// for this open, such as when the fileset lives on a secondary tier.
+	FilePathPrefix string
 }
 
 // DataFileSetReader provides an unsynchronized reader for a TSDB file set
@@ -526,6 +534,15 @@ type MergeWith interface {
 		fn ForEachRemainingFn,
 		nsCtx namespace.Context,
 	) error
+
+	// DeletedRanges returns the time ranges of data for the given series and
+	// block start that have been deleted or have expired and must not be
+	// persisted.
+	DeletedRanges(
+		seriesID ident.ID,
+		tags ident.Tags,
+		blockStart xtime.UnixNano,
+	) xtime.Ranges
 }
 
 // Merger is in charge of merging filesets with some target MergeWith interface.
@@ -550,4 +567,5 @@ type NewMergerFn func(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	fsOpts Options,
 ) Merger
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/bloom"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 	"github.com/m3db/m3/src/dbnode/persist/fs/msgpack"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
 	"github.com/m3db/m3/src/x/checked"
@@ -74,12 +75,15 @@ type writer struct {
 	volumeIndex  int
 	snapshotTime time.Time
 	snapshotID   uuid.UUID
+	compression  compression.Type
 
 	currIdx            int64
 	currOffset         int64
 	encoder            *msgpack.Encoder
 	digestBuf          digest.Buffer
 	singleCheckedBytes []checked.Bytes
+	uncompressedBuf    []byte
+	compressedBuf      []byte
 	tagEncoderPool     serialize.TagEncoderPool
 	err                error
 }
@@ -227,6 +231,7 @@ func (w *writer) reset(opts DataWriterOpenOptions) {
 	w.volumeIndex = opts.Identifier.VolumeIndex
 	w.snapshotTime = opts.Snapshot.SnapshotTime
 	w.snapshotID = opts.Snapshot.SnapshotID
+	w.compression = opts.DataCompression
 	w.currIdx = 0
 	w.currOffset = 0
 	w.err = nil
@@ -301,13 +306,24 @@ func (w *writer) writeAll(
 		size:           uint32(size),
 		checksum:       checksum,
 	}
-	for _, d := range data {
-		if d == nil {
-			continue
+	if w.compression != compression.None {
+		compressed, err := w.compressData(data)
+		if err != nil {
+			return err
 		}
-		if err := w.writeData(d.Bytes()); err != nil {
+		if err := w.writeData(compressed); err != nil {
 			return err
 		}
+		entry.size = uint32(len(compressed))
+	} else {
+		for _, d := range data {
+			if d == nil {
+				continue
+			}
+			if err := w.writeData(d.Bytes()); err != nil {
+				return err
+			}
+		}
 	}
 
 	w.indexEntries = append(w.indexEntries, entry)
@@ -316,6 +332,25 @@ func (w *writer) writeAll(
 	return nil
 }
 
+// compressData compresses the segments of a series as a single chunk, the
+// checksum of the series remains that of the uncompressed data.
+func (w *writer) compressData(data []checked.Bytes) ([]byte, error) {
+	w.uncompressedBuf = w.uncompressedBuf[:0]
+	for _, d := range data {
+		if d == nil {
+			continue
+		}
+		w.uncompressedBuf = append(w.uncompressedBuf, d.Bytes()...)
+	}
+
+	compressed, err := compressData(w.compression, w.compressedBuf, w.uncompressedBuf)
+	if err != nil {
+		return nil, err
+	}
+	w.compressedBuf = compressed
+	return compressed, nil
+}
+
 func (w *writer) Close() error {
 	err := w.close()
 	if w.err != nil {
@@ -542,13 +577,14 @@ func (w *writer) writeInfoFileContents(
 	}
 
 	info := schema.IndexInfo{
-		BlockStart:   xtime.ToNanoseconds(w.start),
-		VolumeIndex:  w.volumeIndex,
-		SnapshotTime: xtime.ToNanoseconds(w.snapshotTime),
-		SnapshotID:   snapshotBytes,
-		BlockSize:    int64(w.blockSize),
-		Entries:      w.currIdx,
-		MajorVersion: schema.MajorVersion,
+		BlockStart:      xtime.ToNanoseconds(w.start),
+		VolumeIndex:     w.volumeIndex,
+		SnapshotTime:    xtime.ToNanoseconds(w.snapshotTime),
+		SnapshotID:      snapshotBytes,
+		BlockSize:       int64(w.blockSize),
+		Entries:         w.currIdx,
+		MajorVersion:    schema.MajorVersion,
+		DataCompression: w.compression,
 		Summaries: schema.IndexSummariesInfo{
 			Summaries: int64(summaries),
 		},
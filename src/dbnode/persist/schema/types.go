@@ -22,6 +22,7 @@ package schema
 
 import (
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/compression"
 )
 
 // MajorVersion is the major schema version for a set of fileset files,
@@ -31,16 +32,17 @@ const MajorVersion = 1
 
 // IndexInfo stores metadata information about block filesets
 type IndexInfo struct {
-	MajorVersion int64
-	BlockStart   int64
-	BlockSize    int64
-	Entries      int64
-	Summaries    IndexSummariesInfo
-	BloomFilter  IndexBloomFilterInfo
-	SnapshotTime int64
-	FileType     persist.FileSetType
-	SnapshotID   []byte
-	VolumeIndex  int
+	MajorVersion    int64
+	BlockStart      int64
+	BlockSize       int64
+	Entries         int64
+	Summaries       IndexSummariesInfo
+	BloomFilter     IndexBloomFilterInfo
+	SnapshotTime    int64
+	FileType        persist.FileSetType
+	SnapshotID      []byte
+	VolumeIndex     int
+	DataCompression compression.Type
 }
 
 // IndexSummariesInfo stores metadata about the summaries
@@ -79,6 +81,10 @@ type LogInfo struct {
 	DeprecatedDoNotUseDuration int64
 
 	Index int64
+
+	// ChunkCompression is the compression of the chunks that follow the
+	// chunk holding the log info, it is always written uncompressed.
+	ChunkCompression compression.Type
 }
 
 // LogEntry stores per-entry data in a commit log
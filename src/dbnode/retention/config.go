@@ -26,13 +26,20 @@ import (
 
 // Configuration is the set of knobs to configure retention options
 type Configuration struct {
-	RetentionPeriod                       time.Duration  `yaml:"retentionPeriod" validate:"nonzero"`
-	FutureRetentionPeriod                 time.Duration  `yaml:"futureRetentionPeriod" validate:"nonzero"`
-	BlockSize                             time.Duration  `yaml:"blockSize" validate:"nonzero"`
-	BufferFuture                          time.Duration  `yaml:"bufferFuture" validate:"nonzero"`
-	BufferPast                            time.Duration  `yaml:"bufferPast" validate:"nonzero"`
-	BlockDataExpiry                       *bool          `yaml:"blockDataExpiry"`
-	BlockDataExpiryAfterNotAccessedPeriod *time.Duration `yaml:"blockDataExpiryAfterNotAccessedPeriod"`
+	RetentionPeriod                       time.Duration             `yaml:"retentionPeriod" validate:"nonzero"`
+	FutureRetentionPeriod                 time.Duration             `yaml:"futureRetentionPeriod" validate:"nonzero"`
+	BlockSize                             time.Duration             `yaml:"blockSize" validate:"nonzero"`
+	BufferFuture                          time.Duration             `yaml:"bufferFuture" validate:"nonzero"`
+	BufferPast                            time.Duration             `yaml:"bufferPast" validate:"nonzero"`
+	BlockDataExpiry                       *bool                     `yaml:"blockDataExpiry"`
+	BlockDataExpiryAfterNotAccessedPeriod *time.Duration            `yaml:"blockDataExpiryAfterNotAccessedPeriod"`
+	SeriesRules                           []SeriesRuleConfiguration `yaml:"seriesRules"`
+}
+
+// SeriesRuleConfiguration is the configuration of a series retention rule
+type SeriesRuleConfiguration struct {
+	Filter string        `yaml:"filter" validate:"nonzero"`
+	Period time.Duration `yaml:"period" validate:"nonzero"`
 }
 
 // Options returns `Options` corresponding to the provided struct values
@@ -49,5 +56,15 @@ func (c *Configuration) Options() Options {
 	if v := c.BlockDataExpiryAfterNotAccessedPeriod; v != nil {
 		opts = opts.SetBlockDataExpiryAfterNotAccessedPeriod(*v)
 	}
+	if len(c.SeriesRules) > 0 {
+		rules := make([]SeriesRetentionRule, 0, len(c.SeriesRules))
+		for _, rule := range c.SeriesRules {
+			rules = append(rules, SeriesRetentionRule{
+				Filter: rule.Filter,
+				Period: rule.Period,
+			})
+		}
+		opts = opts.SetSeriesRetentionRules(rules)
+	}
 	return opts
 }
@@ -42,6 +42,9 @@ func TestConfigurationAssignment(t *testing.T) {
 			BufferPast:                            bufferPast,
 			BlockDataExpiry:                       &blockDataExpiry,
 			BlockDataExpiryAfterNotAccessedPeriod: &blockDataExpiryAfterNotAccessedPeriod,
+			SeriesRules: []SeriesRuleConfiguration{
+				{Filter: "env:dev", Period: blockSize},
+			},
 		}
 	)
 
@@ -52,4 +55,7 @@ func TestConfigurationAssignment(t *testing.T) {
 	require.Equal(t, bufferPast, opts.BufferPast())
 	require.Equal(t, blockDataExpiry, opts.BlockDataExpiry())
 	require.Equal(t, blockDataExpiryAfterNotAccessedPeriod, opts.BlockDataExpiryAfterNotAccessedPeriod())
+	require.Equal(t, []SeriesRetentionRule{
+		{Filter: "env:dev", Period: blockSize},
+	}, opts.SeriesRetentionRules())
 }
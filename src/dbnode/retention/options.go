@@ -22,6 +22,7 @@ package retention
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -58,6 +59,9 @@ var (
 	errBufferFutureTooLarge    = errors.New("buffer future must be smaller than block size")
 	errBufferPastTooLarge      = errors.New("buffer past must be smaller than block size")
 	errRetentionPeriodTooSmall = errors.New("retention period must not be smaller than block size")
+
+	errSeriesRetentionPeriodTooSmall = errors.New("series retention rule period must not be smaller than block size")
+	errSeriesRetentionPeriodTooLarge = errors.New("series retention rule period must not be larger than retention period")
 )
 
 type options struct {
@@ -68,6 +72,7 @@ type options struct {
 	bufferPast                       time.Duration
 	dataExpiryAfterNotAccessedPeriod time.Duration
 	dataExpiry                       bool
+	seriesRetentionRules             []SeriesRetentionRule
 }
 
 // NewOptions creates new retention options
@@ -102,6 +107,17 @@ func (o *options) Validate() error {
 	if o.retentionPeriod < o.blockSize {
 		return errRetentionPeriodTooSmall
 	}
+	for _, rule := range o.seriesRetentionRules {
+		if _, err := parseSeriesRetentionFilter(rule.Filter); err != nil {
+			return fmt.Errorf("invalid series retention rule %s: %v", rule.String(), err)
+		}
+		if rule.Period < o.blockSize {
+			return errSeriesRetentionPeriodTooSmall
+		}
+		if rule.Period > o.retentionPeriod {
+			return errSeriesRetentionPeriodTooLarge
+		}
+	}
 	return nil
 }
 
@@ -112,7 +128,20 @@ func (o *options) Equal(value Options) bool {
 		o.bufferFuture == value.BufferFuture() &&
 		o.bufferPast == value.BufferPast() &&
 		o.dataExpiry == value.BlockDataExpiry() &&
-		o.dataExpiryAfterNotAccessedPeriod == value.BlockDataExpiryAfterNotAccessedPeriod()
+		o.dataExpiryAfterNotAccessedPeriod == value.BlockDataExpiryAfterNotAccessedPeriod() &&
+		seriesRetentionRulesEqual(o.seriesRetentionRules, value.SeriesRetentionRules())
+}
+
+func seriesRetentionRulesEqual(a, b []SeriesRetentionRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func (o *options) SetRetentionPeriod(value time.Duration) Options {
@@ -184,3 +213,13 @@ func (o *options) SetBlockDataExpiryAfterNotAccessedPeriod(value time.Duration)
 func (o *options) BlockDataExpiryAfterNotAccessedPeriod() time.Duration {
 	return o.dataExpiryAfterNotAccessedPeriod
 }
+
+func (o *options) SetSeriesRetentionRules(value []SeriesRetentionRule) Options {
+	opts := *o
+	opts.seriesRetentionRules = value
+	return &opts
+}
+
+func (o *options) SeriesRetentionRules() []SeriesRetentionRule {
+	return o.seriesRetentionRules
+}
@@ -277,3 +277,31 @@ func (mr *MockOptionsMockRecorder) BlockDataExpiryAfterNotAccessedPeriod() *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockDataExpiryAfterNotAccessedPeriod", reflect.TypeOf((*MockOptions)(nil).BlockDataExpiryAfterNotAccessedPeriod))
 }
+
+// SetSeriesRetentionRules mocks base method
+func (m *MockOptions) SetSeriesRetentionRules(value []SeriesRetentionRule) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSeriesRetentionRules", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetSeriesRetentionRules indicates an expected call of SetSeriesRetentionRules
+func (mr *MockOptionsMockRecorder) SetSeriesRetentionRules(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSeriesRetentionRules", reflect.TypeOf((*MockOptions)(nil).SetSeriesRetentionRules), value)
+}
+
+// SeriesRetentionRules mocks base method
+func (m *MockOptions) SeriesRetentionRules() []SeriesRetentionRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesRetentionRules")
+	ret0, _ := ret[0].([]SeriesRetentionRule)
+	return ret0
+}
+
+// SeriesRetentionRules indicates an expected call of SeriesRetentionRules
+func (mr *MockOptionsMockRecorder) SeriesRetentionRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesRetentionRules", reflect.TypeOf((*MockOptions)(nil).SeriesRetentionRules))
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+var (
+	errSeriesRetentionFilterEmpty = errors.New("series retention rule filter must not be empty")
+)
+
+// SeriesRetentionRule overrides the retention period of the series whose
+// tags match its filter.
+type SeriesRetentionRule struct {
+	// Filter is a tags filter in the metrics filters syntax, i.e. space
+	// separated "name:pattern" pairs such as "env:dev service:api-*",
+	// a series matches the rule when its tags match every pair.
+	Filter string
+
+	// Period is how long the data of the matching series is kept.
+	Period time.Duration
+}
+
+// String returns the string representation of the rule.
+func (r SeriesRetentionRule) String() string {
+	return fmt.Sprintf("%s=%s", r.Filter, r.Period.String())
+}
+
+// TagFilters returns the filters of the rule by tag name, a series matches
+// the rule when the values of its tags match every filter.
+func (r SeriesRetentionRule) TagFilters() (map[string]filters.Filter, error) {
+	values, err := parseSeriesRetentionFilter(r.Filter)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]filters.Filter, len(values))
+	for name, value := range values {
+		f, err := filters.NewFilterFromFilterValue(value)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = f
+	}
+	return result, nil
+}
+
+// SeriesRetentionMatcher resolves the retention period of series from a
+// list of series retention rules.
+type SeriesRetentionMatcher interface {
+	// RetentionPeriod returns the period of the first rule that matches the
+	// tags and true, or false if no rule matches. The iterator is consumed.
+	RetentionPeriod(tags ident.TagIterator) (time.Duration, bool)
+}
+
+type seriesRetentionTagFilter struct {
+	idx    int
+	filter filters.Filter
+}
+
+type seriesRetentionMatcherRule struct {
+	filterIdxs []int
+	period     time.Duration
+}
+
+type seriesRetentionMatcher struct {
+	// filtersByName holds the tag filters of all rules by tag name.
+	filtersByName map[string][]seriesRetentionTagFilter
+	numFilters    int
+	rules         []seriesRetentionMatcherRule
+}
+
+// NewSeriesRetentionMatcher returns a new series retention matcher for
+// the rules, rules are evaluated in order.
+func NewSeriesRetentionMatcher(
+	rules []SeriesRetentionRule,
+) (SeriesRetentionMatcher, error) {
+	m := &seriesRetentionMatcher{
+		filtersByName: make(map[string][]seriesRetentionTagFilter),
+		rules:         make([]seriesRetentionMatcherRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		tagFilters, err := rule.TagFilters()
+		if err != nil {
+			return nil, err
+		}
+		compiled := seriesRetentionMatcherRule{
+			filterIdxs: make([]int, 0, len(tagFilters)),
+			period:     rule.Period,
+		}
+		for name, f := range tagFilters {
+			idx := m.numFilters
+			m.numFilters++
+			m.filtersByName[name] = append(m.filtersByName[name], seriesRetentionTagFilter{
+				idx:    idx,
+				filter: f,
+			})
+			compiled.filterIdxs = append(compiled.filterIdxs, idx)
+		}
+		m.rules = append(m.rules, compiled)
+	}
+	return m, nil
+}
+
+func (m *seriesRetentionMatcher) RetentionPeriod(
+	tags ident.TagIterator,
+) (time.Duration, bool) {
+	if len(m.rules) == 0 {
+		return 0, false
+	}
+
+	// Evaluate the filters as the tags are iterated since the tag bytes are
+	// only valid until the iterator is advanced. Like the tags filters of
+	// rules, a filter on a tag the series does not have never matches.
+	matched := make([]bool, m.numFilters)
+	for tags.Next() {
+		tag := tags.Current()
+		for _, f := range m.filtersByName[tag.Name.String()] {
+			matched[f.idx] = f.filter.Matches(tag.Value.Bytes())
+		}
+	}
+	if tags.Err() != nil {
+		return 0, false
+	}
+
+	for _, rule := range m.rules {
+		if rule.matches(matched) {
+			return rule.period, true
+		}
+	}
+	return 0, false
+}
+
+func (r seriesRetentionMatcherRule) matches(matched []bool) bool {
+	for _, idx := range r.filterIdxs {
+		if !matched[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSeriesRetentionFilter(str string) (filters.TagFilterValueMap, error) {
+	values, err := filters.ValidateTagsFilter(str)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errSeriesRetentionFilterEmpty
+	}
+	return values, nil
+}
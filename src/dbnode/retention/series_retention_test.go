@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesRetentionMatcher(t *testing.T) {
+	matcher, err := NewSeriesRetentionMatcher([]SeriesRetentionRule{
+		{Filter: "env:dev service:api-*", Period: 2 * time.Hour},
+		{Filter: "slo:true", Period: 48 * time.Hour},
+		{Filter: "env:!prod", Period: 24 * time.Hour},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		tags   ident.Tags
+		period time.Duration
+		ok     bool
+	}{
+		{
+			tags:   ident.NewTags(ident.StringTag("env", "dev"), ident.StringTag("service", "api-foo")),
+			period: 2 * time.Hour,
+			ok:     true,
+		},
+		{
+			// Only matches the negated filter.
+			tags:   ident.NewTags(ident.StringTag("env", "dev"), ident.StringTag("service", "web")),
+			period: 24 * time.Hour,
+			ok:     true,
+		},
+		{
+			// First matching rule wins.
+			tags:   ident.NewTags(ident.StringTag("env", "dev"), ident.StringTag("slo", "true")),
+			period: 48 * time.Hour,
+			ok:     true,
+		},
+		{
+			// Series missing a filtered tag never match.
+			tags: ident.NewTags(ident.StringTag("service", "api-foo")),
+			ok:   false,
+		},
+		{
+			tags: ident.NewTags(ident.StringTag("env", "prod")),
+			ok:   false,
+		},
+	}
+	for _, test := range tests {
+		period, ok := matcher.RetentionPeriod(ident.NewTagsIterator(test.tags))
+		require.Equal(t, test.ok, ok)
+		require.Equal(t, test.period, period)
+	}
+}
+
+func TestSeriesRetentionMatcherInvalidFilter(t *testing.T) {
+	_, err := NewSeriesRetentionMatcher([]SeriesRetentionRule{
+		{Filter: "env", Period: time.Hour},
+	})
+	require.Error(t, err)
+
+	_, err = NewSeriesRetentionMatcher([]SeriesRetentionRule{
+		{Filter: " ", Period: time.Hour},
+	})
+	require.Error(t, err)
+}
+
+func TestSeriesRetentionRulesValidate(t *testing.T) {
+	opts := NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour)
+	require.NoError(t, opts.SetSeriesRetentionRules([]SeriesRetentionRule{
+		{Filter: "env:dev", Period: 2 * time.Hour},
+		{Filter: "env:staging", Period: 48 * time.Hour},
+	}).Validate())
+
+	require.Error(t, opts.SetSeriesRetentionRules([]SeriesRetentionRule{
+		{Filter: "env:dev", Period: time.Hour},
+	}).Validate())
+	require.Error(t, opts.SetSeriesRetentionRules([]SeriesRetentionRule{
+		{Filter: "env:dev", Period: 72 * time.Hour},
+	}).Validate())
+	require.Error(t, opts.SetSeriesRetentionRules([]SeriesRetentionRule{
+		{Filter: "env", Period: 2 * time.Hour},
+	}).Validate())
+}
+
+func TestSeriesRetentionRulesEqual(t *testing.T) {
+	rules := []SeriesRetentionRule{{Filter: "env:dev", Period: 2 * time.Hour}}
+	opts := NewOptions().SetSeriesRetentionRules(rules)
+	require.True(t, opts.Equal(NewOptions().SetSeriesRetentionRules(rules)))
+	require.False(t, opts.Equal(NewOptions()))
+	require.False(t, opts.Equal(NewOptions().SetSeriesRetentionRules([]SeriesRetentionRule{
+		{Filter: "env:dev", Period: 4 * time.Hour},
+	})))
+}
@@ -77,4 +77,12 @@ type Options interface {
 	// BlockDataExpiryAfterNotAccessedPeriod returns the period that blocks data should
 	// be expired after not being accessed for a given duration
 	BlockDataExpiryAfterNotAccessedPeriod() time.Duration
+
+	// SetSeriesRetentionRules sets the rules that shorten the retention
+	// period of the series matching them
+	SetSeriesRetentionRules(value []SeriesRetentionRule) Options
+
+	// SeriesRetentionRules returns the rules that shorten the retention
+	// period of the series matching them
+	SeriesRetentionRules() []SeriesRetentionRule
 }
@@ -53,6 +53,7 @@ import (
 	ttcluster "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/cluster"
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
 	"github.com/m3db/m3/src/dbnode/retention"
@@ -492,6 +493,10 @@ func Run(runOpts RunOptions) {
 		SetFlushInterval(cfg.CommitLog.FlushEvery).
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize))
+	if cfg.CommitLog.ChunkCompression != nil {
+		opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
+			SetChunkCompression(*cfg.CommitLog.ChunkCompression))
+	}
 
 	// Setup the block retriever
 	switch seriesCachePolicy {
@@ -837,6 +842,27 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("could not create cluster topology watch", zap.Error(err))
 	}
 
+	if cfg.Backup != nil && cfg.Backup.Enabled {
+		keyPrefix := cfg.Backup.KeyPrefix
+		if keyPrefix == "" {
+			keyPrefix = hostID
+		}
+
+		backupOpts := backup.NewOptions().
+			SetObjectStore(backup.NewDirectoryObjectStore(cfg.Backup.Directory)).
+			SetKeyPrefix(keyPrefix).
+			SetFilePathPrefix(fsopts.FilePathPrefix()).
+			SetInstrumentOptions(iopts)
+		backupMgr, err := backup.NewManager(backupOpts)
+		if err != nil {
+			logger.Fatal("could not create backup manager", zap.Error(err))
+		}
+
+		// NB: filesets are backed up by the filesystem manager once they
+		// have been flushed.
+		opts = opts.SetBackupManager(backupMgr)
+	}
+
 	opts = opts.SetSchemaRegistry(schemaRegistry)
 	db, err := cluster.NewDatabase(hostID, topo, clusterTopoWatch, opts)
 	if err != nil {
@@ -1454,6 +1480,22 @@ func withEncodingAndPoolingOptions(
 		return m3tsz.NewEncoder(time.Time{}, nil, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
 	})
 
+	// NB: the counter encoder pool is only used by namespaces with counter
+	// encoding enabled so it uses the default pool size rather than the
+	// encoder pool policy to avoid doubling the preallocated encoders. The
+	// counter value encoding only applies to M3TSZ encoded values.
+	counterEncoderPoolOpts := pool.NewObjectPoolOptions()
+	counterEncoderPoolOpts = counterEncoderPoolOpts.SetInstrumentOptions(
+		counterEncoderPoolOpts.InstrumentOptions().
+			SetMetricsScope(scope.SubScope("counter-encoder-pool")))
+	counterEncoderPool := encoding.NewEncoderPool(counterEncoderPoolOpts)
+	counterEncodingOpts := encodingOpts.
+		SetEncoderPool(counterEncoderPool).
+		SetValueEncodingScheme(encoding.CounterValueEncodingScheme)
+	counterEncoderPool.Init(func() encoding.Encoder {
+		return m3tsz.NewEncoder(time.Time{}, nil, m3tsz.DefaultIntOptimizationEnabled, counterEncodingOpts)
+	})
+
 	iteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
 		if cfg.Proto != nil && cfg.Proto.Enabled {
 			return proto.NewIterator(r, descr, encodingOpts)
@@ -1482,6 +1524,7 @@ func withEncodingAndPoolingOptions(
 		SetBytesPool(bytesPool).
 		SetContextPool(contextPool).
 		SetEncoderPool(encoderPool).
+		SetCounterEncoderPool(counterEncoderPool).
 		SetReaderIteratorPool(iteratorPool).
 		SetMultiReaderIteratorPool(multiIteratorPool).
 		SetIdentifierPool(identifierPool).
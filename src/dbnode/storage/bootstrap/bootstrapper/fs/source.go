@@ -210,13 +210,13 @@ func (s *fileSystemSource) availability(
 ) (result.ShardTimeRanges, error) {
 	result := make(map[uint32]xtime.Ranges, len(shardsTimeRanges))
 	for shard, ranges := range shardsTimeRanges {
-		result[shard] = s.shardAvailability(md.ID(), shard, ranges)
+		result[shard] = s.shardAvailability(md, shard, ranges)
 	}
 	return result, nil
 }
 
 func (s *fileSystemSource) shardAvailability(
-	namespace ident.ID,
+	md namespace.Metadata,
 	shard uint32,
 	targetRangesForShard xtime.Ranges,
 ) xtime.Ranges {
@@ -224,8 +224,8 @@ func (s *fileSystemSource) shardAvailability(
 		return xtime.Ranges{}
 	}
 
-	readInfoFilesResults := fs.ReadInfoFiles(s.fsopts.FilePathPrefix(),
-		namespace, shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.dataFilePathPrefixes(md),
+		md.ID(), shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
 
 	var tr xtime.Ranges
 	for i := 0; i < len(readInfoFilesResults); i++ {
@@ -233,7 +233,7 @@ func (s *fileSystemSource) shardAvailability(
 		if err := result.Err.Error(); err != nil {
 			s.log.Error("unable to read info files in shardAvailability",
 				zap.Uint32("shard", shard),
-				zap.Stringer("namespace", namespace),
+				zap.Stringer("namespace", md.ID()),
 				zap.Error(err),
 				zap.Any("targetRangesForShard", targetRangesForShard),
 				zap.String("filepath", result.Err.Filepath()),
@@ -251,6 +251,13 @@ func (s *fileSystemSource) shardAvailability(
 	return tr
 }
 
+// dataFilePathPrefixes returns the file path prefixes the data filesets of a
+// namespace can live under, which includes the namespace's secondary tier if
+// tiering is enabled.
+func (s *fileSystemSource) dataFilePathPrefixes(md namespace.Metadata) []string {
+	return fs.DataFilePathPrefixes(s.fsopts.FilePathPrefix(), md.Options())
+}
+
 func (s *fileSystemSource) enqueueReaders(
 	run runType,
 	ns namespace.Metadata,
@@ -340,7 +347,7 @@ func (s *fileSystemSource) newShardReaders(
 	shard uint32,
 	tr xtime.Ranges,
 ) shardReaders {
-	readInfoFilesResults := fs.ReadInfoFiles(s.fsopts.FilePathPrefix(),
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.dataFilePathPrefixes(ns),
 		ns.ID(), shard, s.fsopts.InfoReaderBufferSize(), s.fsopts.DecodingOptions())
 	if len(readInfoFilesResults) == 0 {
 		// No readers.
@@ -388,6 +395,7 @@ func (s *fileSystemSource) newShardReaders(
 				Shard:      shard,
 				BlockStart: blockStart,
 			},
+			FilePathPrefix: result.FilePathPrefix,
 		}
 		if err := r.Open(openOpts); err != nil {
 			s.log.Error("unable to open fileset files",
@@ -979,7 +987,7 @@ func (s *fileSystemSource) bootstrapDataRunResultFromAvailability(
 		if ranges.IsEmpty() {
 			continue
 		}
-		availability := s.shardAvailability(md.ID(), shard, ranges)
+		availability := s.shardAvailability(md, shard, ranges)
 		remaining := ranges.RemoveRanges(availability)
 		if !remaining.IsEmpty() {
 			unfulfilled.AddRanges(result.ShardTimeRanges{
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
@@ -198,16 +199,19 @@ func (m *cleanupManager) deleteInactiveNamespaceFiles() error {
 // deleteInactiveDataFiles will delete data files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath)
+	return m.deleteInactiveDataFileSetFiles(fs.NamespaceDataDirPath, fs.DataFilePathPrefixes)
 }
 
 // deleteInactiveDataSnapshotFiles will delete snapshot files for shards that the node no longer owns
 // which can occur in the case of topology changes
 func (m *cleanupManager) deleteInactiveDataSnapshotFiles() error {
-	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath)
+	return m.deleteInactiveDataFileSetFiles(fs.NamespaceSnapshotsDirPath, primaryFilePathPrefixes)
 }
 
-func (m *cleanupManager) deleteInactiveDataFileSetFiles(filesetFilesDirPathFn func(string, ident.ID) string) error {
+func (m *cleanupManager) deleteInactiveDataFileSetFiles(
+	filesetFilesDirPathFn func(string, ident.ID) string,
+	filePathPrefixesFn func(string, namespace.Options) []string,
+) error {
 	multiErr := xerrors.NewMultiError()
 	filePathPrefix := m.database.Options().CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	namespaces, err := m.database.GetOwnedNamespaces()
@@ -216,17 +220,25 @@ func (m *cleanupManager) deleteInactiveDataFileSetFiles(filesetFilesDirPathFn fu
 	}
 	for _, n := range namespaces {
 		var activeShards []string
-		namespaceDirPath := filesetFilesDirPathFn(filePathPrefix, n.ID())
 		for _, s := range n.GetOwnedShards() {
 			shard := fmt.Sprintf("%d", s.ID())
 			activeShards = append(activeShards, shard)
 		}
-		multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(namespaceDirPath, activeShards))
+		for _, prefix := range filePathPrefixesFn(filePathPrefix, n.Options()) {
+			namespaceDirPath := filesetFilesDirPathFn(prefix, n.ID())
+			multiErr = multiErr.Add(m.deleteInactiveDirectoriesFn(namespaceDirPath, activeShards))
+		}
 	}
 
 	return multiErr.FinalError()
 }
 
+// primaryFilePathPrefixes returns only the primary file path prefix, for
+// files such as snapshots that are never moved to a secondary tier.
+func primaryFilePathPrefixes(filePathPrefix string, _ namespace.Options) []string {
+	return []string{filePathPrefix}
+}
+
 func (m *cleanupManager) cleanupDataFiles(t time.Time) error {
 	multiErr := xerrors.NewMultiError()
 	namespaces, err := m.database.GetOwnedNamespaces()
@@ -73,6 +73,9 @@ var (
 	// errWriterDoesNotImplementWriteBatch is raised when the provided ts.BatchWriter does not implement
 	// ts.WriteBatch.
 	errWriterDoesNotImplementWriteBatch = errors.New("provided writer does not implement ts.WriteBatch")
+
+	// errDatabaseNotBootstrappedToBackfill raised when trying to backfill before the database is bootstrapped.
+	errDatabaseNotBootstrappedToBackfill = errors.New("database is not yet bootstrapped to backfill")
 )
 
 type databaseState int
@@ -112,6 +115,10 @@ type db struct {
 	log     *zap.Logger
 
 	writeBatchPool *ts.WriteBatchPool
+
+	// backfillLock serializes backfills since each needs exclusive use of
+	// the persist manager.
+	backfillLock sync.Mutex
 }
 
 type databaseMetrics struct {
@@ -900,6 +907,60 @@ func (d *db) Truncate(namespace ident.ID) (int64, error) {
 	return n.Truncate()
 }
 
+func (d *db) DeleteSeries(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	start, end time.Time,
+) (int64, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return 0, err
+	}
+	return n.DeleteSeries(ctx, query, start, end)
+}
+
+func (d *db) Backfill(
+	ctx context.Context,
+	namespace ident.ID,
+	blockStart time.Time,
+	series []BackfillSeries,
+) error {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return err
+	}
+	if !d.IsBootstrapped() {
+		return xerrors.NewRetryableError(errDatabaseNotBootstrappedToBackfill)
+	}
+
+	d.backfillLock.Lock()
+	defer d.backfillLock.Unlock()
+
+	// Backfills write new fileset volumes the same way cold flushes do, so
+	// wait for any in progress file operations to finish and keep them from
+	// starting until the backfill is done. File operations are disabled by
+	// reference so this does not re-enable them during a bootstrap that
+	// overlaps with the backfill.
+	d.mediator.DisableFileOps()
+	defer d.mediator.EnableFileOps()
+
+	flushPersist, err := d.opts.PersistManager().StartFlushPersist()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	if err := n.Backfill(ctx, blockStart, series, flushPersist); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	if err := flushPersist.DoneFlush(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
+	return multiErr.FinalError()
+}
+
 func (d *db) IsOverloaded() bool {
 	queueSize := float64(d.commitLog.QueueLength())
 	queueCapacity := float64(d.opts.CommitLogOptions().BacklogQueueSize())
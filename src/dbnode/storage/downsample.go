@@ -0,0 +1,396 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	maggregation "github.com/m3db/m3/src/metrics/aggregation"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	// downsampleAggregationTagName is the name of the tag added to the
+	// downsampled series of each aggregation type when a namespace is
+	// downsampled with more than one aggregation type.
+	downsampleAggregationTagName = "__aggregation__"
+)
+
+type downsampledMarkerExistsFn func(filePathPrefix string, id fs.FileSetFileIdentifier) (bool, error)
+
+type writeDownsampledMarkerFn func(
+	filePathPrefix string,
+	id fs.FileSetFileIdentifier,
+	newFileMode os.FileMode,
+) error
+
+type downsampleFileSetFn func(
+	n databaseNamespace,
+	fileSet fs.FileSetFile,
+	filePathPrefix string,
+	downsampleOpts namespace.DownsampleOptions,
+) error
+
+type downsampleManager struct {
+	sync.RWMutex
+
+	database database
+	opts     Options
+
+	filePathPrefix            string
+	dataFilesFn               dataFilesFn
+	downsampledMarkerExistsFn downsampledMarkerExistsFn
+	writeDownsampledMarkerFn  writeDownsampledMarkerFn
+	downsampleFileSetFn       downsampleFileSetFn
+	downsampleInProgress      bool
+	metrics                   downsampleManagerMetrics
+}
+
+type downsampleManagerMetrics struct {
+	status             tally.Gauge
+	downsampledFileSet tally.Counter
+	errors             tally.Counter
+}
+
+func newDownsampleManagerMetrics(scope tally.Scope) downsampleManagerMetrics {
+	fsScope := scope.SubScope("downsample")
+	return downsampleManagerMetrics{
+		status:             scope.Gauge("downsample"),
+		downsampledFileSet: fsScope.Counter("downsampled-fileset"),
+		errors:             fsScope.Counter("errors"),
+	}
+}
+
+func newDownsampleManager(database database, scope tally.Scope) databaseDownsampleManager {
+	opts := database.Options()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	m := &downsampleManager{
+		database:                  database,
+		opts:                      opts,
+		filePathPrefix:            fsOpts.FilePathPrefix(),
+		dataFilesFn:               fs.DataFiles,
+		downsampledMarkerExistsFn: fs.DownsampledMarkerExists,
+		writeDownsampledMarkerFn:  fs.WriteDownsampledMarker,
+		metrics:                   newDownsampleManagerMetrics(scope),
+	}
+	m.downsampleFileSetFn = m.downsampleFileSet
+	return m
+}
+
+func (m *downsampleManager) Downsample(t time.Time) error {
+	m.Lock()
+	m.downsampleInProgress = true
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.downsampleInProgress = false
+		m.Unlock()
+	}()
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		downsampleOpts := n.Options().DownsampleOptions()
+		if !downsampleOpts.Enabled() {
+			continue
+		}
+
+		targetID := downsampleOpts.TargetNamespace()
+		if _, ok := m.database.Namespace(targetID); !ok {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"downsample target namespace %s for namespace %s does not exist",
+				targetID.String(), n.ID().String()))
+			continue
+		}
+
+		var (
+			downsampleBefore = t.Add(-downsampleOpts.After())
+			earliestToRetain = retention.FlushTimeStart(n.Options().RetentionOptions(), t)
+			blockSize        = n.Options().RetentionOptions().BlockSize()
+		)
+		for _, shard := range n.GetOwnedShards() {
+			if err := m.downsampleShard(n, shard, downsampleOpts,
+				earliestToRetain, downsampleBefore, blockSize); err != nil {
+				multiErr = multiErr.Add(fmt.Errorf(
+					"encountered errors when downsampling data files for namespace %s shard %d: %v",
+					n.ID().String(), shard.ID(), err))
+			}
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (m *downsampleManager) Report() {
+	m.RLock()
+	downsampleInProgress := m.downsampleInProgress
+	m.RUnlock()
+
+	if downsampleInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+type downsampleCandidate struct {
+	fileSet        fs.FileSetFile
+	filePathPrefix string
+}
+
+func (m *downsampleManager) downsampleShard(
+	n databaseNamespace,
+	shard databaseShard,
+	downsampleOpts namespace.DownsampleOptions,
+	earliestToRetain time.Time,
+	downsampleBefore time.Time,
+	blockSize time.Duration,
+) error {
+	// Only the latest complete volume of each block is downsampled, if the
+	// block is compacted into a new volume later on then the new volume is
+	// downsampled again and overwrites the previously downsampled data.
+	latest := make(map[xtime.UnixNano]downsampleCandidate)
+	filePathPrefixes := fs.DataFilePathPrefixes(m.filePathPrefix, n.Options())
+	for _, filePathPrefix := range filePathPrefixes {
+		fileSets, err := m.dataFilesFn(filePathPrefix, n.ID(), shard.ID())
+		if err != nil {
+			return err
+		}
+
+		for _, fileSet := range fileSets {
+			blockStart := fileSet.ID.BlockStart
+			if blockStart.Before(earliestToRetain) ||
+				blockStart.Add(blockSize).After(downsampleBefore) ||
+				!fileSet.HasCompleteCheckpointFile() {
+				continue
+			}
+
+			// A fileset can exist under more than one prefix while it is
+			// being tiered, prefer the prefix that is looked up first.
+			key := xtime.ToUnixNano(blockStart)
+			if existing, ok := latest[key]; ok &&
+				existing.fileSet.ID.VolumeIndex >= fileSet.ID.VolumeIndex {
+				continue
+			}
+			latest[key] = downsampleCandidate{
+				fileSet:        fileSet,
+				filePathPrefix: filePathPrefix,
+			}
+		}
+	}
+
+	var (
+		multiErr    = xerrors.NewMultiError()
+		newFileMode = m.opts.CommitLogOptions().FilesystemOptions().NewFileMode()
+	)
+	for _, candidate := range latest {
+		id := candidate.fileSet.ID
+		downsampled, err := m.downsampledMarkerExistsFn(candidate.filePathPrefix, id)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		if downsampled {
+			continue
+		}
+
+		if err := m.downsampleFileSetFn(n, candidate.fileSet,
+			candidate.filePathPrefix, downsampleOpts); err != nil {
+			m.metrics.errors.Inc(1)
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		if err := m.writeDownsampledMarkerFn(candidate.filePathPrefix,
+			id, newFileMode); err != nil {
+			m.metrics.errors.Inc(1)
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		m.metrics.downsampledFileSet.Inc(1)
+	}
+
+	return multiErr.FinalError()
+}
+
+// downsampleFileSet reads every series of a fileset, aggregates its
+// datapoints into intervals of the downsample resolution and writes the
+// aggregated values to the target namespace.
+func (m *downsampleManager) downsampleFileSet(
+	n databaseNamespace,
+	fileSet fs.FileSetFile,
+	filePathPrefix string,
+	downsampleOpts namespace.DownsampleOptions,
+) error {
+	reader, err := fs.NewReader(m.opts.BytesPool(), m.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		return err
+	}
+	if err := reader.Open(fs.DataReaderOpenOptions{
+		Identifier:     fileSet.ID,
+		FileSetType:    persist.FileSetFlushType,
+		FilePathPrefix: filePathPrefix,
+	}); err != nil {
+		return fmt.Errorf("unable to open fileset for downsampling: %v", err)
+	}
+	defer reader.Close()
+
+	var (
+		resolution = downsampleOpts.Resolution()
+		aggTypes   = downsampleOpts.AggregationTypes()
+		aggOpts    = raggregation.NewOptions()
+		iter       = m.opts.ReaderIteratorPool().Get()
+	)
+	defer iter.Close()
+	aggOpts.ResetSetData(aggTypes)
+	_, unit := xtime.MaxUnitForDuration(resolution)
+
+	for {
+		id, tagsIter, data, _, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		segment := ts.NewSegment(data, nil, ts.FinalizeNone)
+		iter.Reset(xio.NewSegmentReader(segment), n.Schema())
+		buckets, err := downsampleSeries(iter, resolution, aggOpts)
+		data.DecRef()
+		data.Finalize()
+
+		if err == nil {
+			err = m.writeDownsampledSeries(downsampleOpts.TargetNamespace(),
+				aggTypes, unit, id, tagsIter, buckets)
+		}
+		id.Finalize()
+		tagsIter.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (m *downsampleManager) writeDownsampledSeries(
+	targetID ident.ID,
+	aggTypes maggregation.Types,
+	unit xtime.Unit,
+	id ident.ID,
+	tagsIter ident.TagIterator,
+	buckets []downsampleBucket,
+) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	// The ID and tags returned by the reader are only valid until the next
+	// read, so take copies that can be held on to by the target namespace.
+	tags := ident.NewTags()
+	for tagsIter.Next() {
+		tag := tagsIter.Current()
+		tags.Append(ident.StringTag(tag.Name.String(), tag.Value.String()))
+	}
+	if err := tagsIter.Err(); err != nil {
+		return err
+	}
+
+	ctx := m.opts.ContextPool().Get()
+	defer ctx.Close()
+
+	for _, aggType := range aggTypes {
+		var (
+			seriesID   = ident.StringID(id.String())
+			seriesTags = tags
+		)
+		if len(aggTypes) > 1 {
+			seriesID = ident.StringID(fmt.Sprintf("%s,%s=%s",
+				id.String(), downsampleAggregationTagName, aggType.String()))
+			seriesTags = ident.NewTags(tags.Values()...)
+			seriesTags.Append(ident.StringTag(downsampleAggregationTagName, aggType.String()))
+		}
+
+		for _, bucket := range buckets {
+			if err := m.database.WriteTagged(ctx, targetID, seriesID,
+				ident.NewTagsIterator(seriesTags), bucket.start,
+				bucket.gauge.ValueOf(aggType), unit, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// downsampleBucket is the aggregated datapoints of a series in a single
+// interval of the downsample resolution.
+type downsampleBucket struct {
+	start time.Time
+	gauge raggregation.Gauge
+}
+
+// downsampleSeries aggregates the datapoints of a series into intervals of
+// the given resolution, the aggregated values are timestamped at the start
+// of each interval.
+func downsampleSeries(
+	iter encoding.Iterator,
+	resolution time.Duration,
+	aggOpts raggregation.Options,
+) ([]downsampleBucket, error) {
+	var buckets []downsampleBucket
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		if math.IsNaN(dp.Value) {
+			continue
+		}
+
+		start := dp.Timestamp.Truncate(resolution)
+		if n := len(buckets); n == 0 || !buckets[n-1].start.Equal(start) {
+			buckets = append(buckets, downsampleBucket{
+				start: start,
+				gauge: raggregation.NewGauge(aggOpts),
+			})
+		}
+		buckets[len(buckets)-1].gauge.Update(dp.Value)
+	}
+	return buckets, iter.Err()
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ts"
+	maggregation "github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newDownsampleManagerTestNamespace(
+	ctrl *gomock.Controller,
+	downsampleOpts namespace.DownsampleOptions,
+	shards ...databaseShard,
+) *MockdatabaseNamespace {
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("ns")).AnyTimes()
+	ns.EXPECT().Options().Return(namespaceOptions.SetDownsampleOptions(downsampleOpts)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(shards).AnyTimes()
+	return ns
+}
+
+func TestDownsampleManagerDownsamplesAgedFileSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockSize      = namespaceOptions.RetentionOptions().BlockSize()
+		now            = time.Now().Truncate(blockSize)
+		downsampleOpts = namespace.NewDownsampleOptions().
+				SetEnabled(true).
+				SetAfter(6 * time.Hour).
+				SetResolution(time.Minute).
+				SetTargetNamespace(ident.StringID("target"))
+		agedBlockStart        = now.Add(-24 * time.Hour)
+		downsampledBlockStart = now.Add(-10 * time.Hour)
+		fileSets              = fs.FileSetFilesSlice{
+			newTieringManagerTestFileSet(agedBlockStart, 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(agedBlockStart, 1, fs.EvalTrue),
+			newTieringManagerTestFileSet(downsampledBlockStart, 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(now.Add(-12*time.Hour), 0, fs.EvalFalse),
+			newTieringManagerTestFileSet(now.Add(-4*time.Hour), 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(now.Add(-72*time.Hour), 0, fs.EvalTrue),
+		}
+	)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+
+	ns := newDownsampleManagerTestNamespace(ctrl, downsampleOpts, shard)
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+	db.EXPECT().Namespace(ident.NewIDMatcher("target")).Return(ns, true)
+
+	mgr := newDownsampleManager(db, tally.NoopScope).(*downsampleManager)
+	mgr.filePathPrefix = "/primary"
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		return fileSets, nil
+	}
+	mgr.downsampledMarkerExistsFn = func(_ string, id fs.FileSetFileIdentifier) (bool, error) {
+		return id.BlockStart.Equal(downsampledBlockStart), nil
+	}
+
+	var downsampled, marked []fs.FileSetFile
+	mgr.downsampleFileSetFn = func(
+		_ databaseNamespace,
+		fileSet fs.FileSetFile,
+		filePathPrefix string,
+		opts namespace.DownsampleOptions,
+	) error {
+		require.Equal(t, "/primary", filePathPrefix)
+		require.True(t, downsampleOpts.Equal(opts))
+		downsampled = append(downsampled, fileSet)
+		return nil
+	}
+	mgr.writeDownsampledMarkerFn = func(
+		filePathPrefix string,
+		id fs.FileSetFileIdentifier,
+		_ os.FileMode,
+	) error {
+		require.Equal(t, "/primary", filePathPrefix)
+		require.Equal(t, fileSets[1].ID, id)
+		marked = append(marked, fileSets[1])
+		return nil
+	}
+
+	require.NoError(t, mgr.Downsample(now))
+	require.Equal(t, []fs.FileSetFile{fileSets[1]}, downsampled)
+	require.Equal(t, []fs.FileSetFile{fileSets[1]}, marked)
+}
+
+func TestDownsampleManagerDownsampleErrorSkipsMarker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockSize      = namespaceOptions.RetentionOptions().BlockSize()
+		now            = time.Now().Truncate(blockSize)
+		downsampleOpts = namespace.NewDownsampleOptions().
+				SetEnabled(true).
+				SetAfter(6 * time.Hour).
+				SetResolution(time.Minute).
+				SetTargetNamespace(ident.StringID("target"))
+	)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+
+	ns := newDownsampleManagerTestNamespace(ctrl, downsampleOpts, shard)
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+	db.EXPECT().Namespace(ident.NewIDMatcher("target")).Return(ns, true)
+
+	mgr := newDownsampleManager(db, tally.NoopScope).(*downsampleManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{
+			newTieringManagerTestFileSet(now.Add(-24*time.Hour), 0, fs.EvalTrue),
+		}, nil
+	}
+	mgr.downsampledMarkerExistsFn = func(string, fs.FileSetFileIdentifier) (bool, error) {
+		return false, nil
+	}
+	mgr.downsampleFileSetFn = func(
+		databaseNamespace,
+		fs.FileSetFile,
+		string,
+		namespace.DownsampleOptions,
+	) error {
+		return errors.New("an error")
+	}
+	mgr.writeDownsampledMarkerFn = func(string, fs.FileSetFileIdentifier, os.FileMode) error {
+		require.FailNow(t, "unexpected write of downsampled marker")
+		return nil
+	}
+
+	require.Error(t, mgr.Downsample(now))
+}
+
+func TestDownsampleManagerDownsampleMissingTargetNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	downsampleOpts := namespace.NewDownsampleOptions().
+		SetEnabled(true).
+		SetAfter(6 * time.Hour).
+		SetResolution(time.Minute).
+		SetTargetNamespace(ident.StringID("target"))
+	ns := newDownsampleManagerTestNamespace(ctrl, downsampleOpts)
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+	db.EXPECT().Namespace(ident.NewIDMatcher("target")).Return(nil, false)
+
+	mgr := newDownsampleManager(db, tally.NoopScope).(*downsampleManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		require.FailNow(t, "unexpected listing of data files")
+		return nil, nil
+	}
+
+	require.Error(t, mgr.Downsample(time.Now()))
+}
+
+func TestDownsampleManagerDownsampleSkipsNamespacesWithoutDownsampling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns := newDownsampleManagerTestNamespace(ctrl, namespace.NewDownsampleOptions())
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+
+	mgr := newDownsampleManager(db, tally.NoopScope).(*downsampleManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		require.FailNow(t, "unexpected listing of data files")
+		return nil, nil
+	}
+
+	require.NoError(t, mgr.Downsample(time.Now()))
+}
+
+func TestDownsampleSeries(t *testing.T) {
+	var (
+		opts      = DefaultTestOptions()
+		blockSize = time.Hour
+		start     = time.Now().Truncate(blockSize)
+		ctx       = context.NewContext()
+	)
+	defer ctx.Close()
+
+	encoder := opts.EncoderPool().Get()
+	encoder.Reset(start, 0, nil)
+	for i, v := range []float64{1, 3, math.NaN(), 2, 10} {
+		dp := ts.Datapoint{
+			Timestamp: start.Add(time.Duration(i) * 20 * time.Second),
+			Value:     v,
+		}
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+	}
+	reader, ok := encoder.Stream(ctx)
+	require.True(t, ok)
+
+	iter := opts.ReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset(reader, nil)
+
+	buckets, err := downsampleSeries(iter, time.Minute, raggregation.NewOptions())
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+
+	require.True(t, start.Equal(buckets[0].start))
+	require.Equal(t, 3.0, buckets[0].gauge.ValueOf(maggregation.Max))
+	require.Equal(t, 2.0, buckets[0].gauge.ValueOf(maggregation.Mean))
+	require.Equal(t, 2.0, buckets[0].gauge.ValueOf(maggregation.Count))
+
+	require.True(t, start.Add(time.Minute).Equal(buckets[1].start))
+	require.Equal(t, 10.0, buckets[1].gauge.ValueOf(maggregation.Last))
+	require.Equal(t, 12.0, buckets[1].gauge.ValueOf(maggregation.Sum))
+}
+
+func TestDownsampleManagerWriteDownsampledSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		start    = time.Now().Truncate(time.Hour)
+		aggTypes = maggregation.Types{maggregation.Min, maggregation.Max}
+		bucket   = downsampleBucket{
+			start: start,
+			gauge: raggregation.NewGauge(raggregation.NewOptions()),
+		}
+	)
+	bucket.gauge.Update(1)
+	bucket.gauge.Update(5)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	for _, aggType := range aggTypes {
+		expectedTags := ident.NewTags(
+			ident.StringTag("city", "nyc"),
+			ident.StringTag(downsampleAggregationTagName, aggType.String()))
+		db.EXPECT().WriteTagged(gomock.Any(), ident.NewIDMatcher("target"),
+			ident.NewIDMatcher("foo,__aggregation__="+aggType.String()),
+			ident.NewTagIterMatcher(ident.NewTagsIterator(expectedTags)),
+			start, bucket.gauge.ValueOf(aggType), xtime.Minute, nil).Return(nil)
+	}
+
+	mgr := newDownsampleManager(db, tally.NoopScope).(*downsampleManager)
+	tags := ident.NewTagsIterator(ident.NewTags(ident.StringTag("city", "nyc")))
+	require.NoError(t, mgr.writeDownsampledSeries(ident.StringID("target"), aggTypes,
+		xtime.Minute, ident.StringID("foo"), tags, []downsampleBucket{bucket}))
+}
@@ -24,6 +24,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 
 	"go.uber.org/zap"
@@ -85,13 +86,15 @@ const (
 type fileSystemManager struct {
 	databaseFlushManager
 	databaseCleanupManager
+	databaseTieringManager
+	databaseDownsampleManager
 	sync.RWMutex
 
 	log      *zap.Logger
 	database database
 	opts     Options
 	status   fileOpStatus
-	enabled  bool
+	disabled int
 }
 
 func newFileSystemManager(
@@ -103,22 +106,25 @@ func newFileSystemManager(
 	scope := instrumentOpts.MetricsScope().SubScope("fs")
 	fm := newFlushManager(database, commitLog, scope)
 	cm := newCleanupManager(database, commitLog, scope)
+	tm := newTieringManager(database, scope)
+	dm := newDownsampleManager(database, scope)
 
 	return &fileSystemManager{
-		databaseFlushManager:   fm,
-		databaseCleanupManager: cm,
-		log:                    instrumentOpts.Logger(),
-		database:               database,
-		opts:                   opts,
-		status:                 fileOpNotStarted,
-		enabled:                true,
+		databaseFlushManager:      fm,
+		databaseCleanupManager:    cm,
+		databaseTieringManager:    tm,
+		databaseDownsampleManager: dm,
+		log:                       instrumentOpts.Logger(),
+		database:                  database,
+		opts:                      opts,
+		status:                    fileOpNotStarted,
 	}
 }
 
 func (m *fileSystemManager) Disable() fileOpStatus {
 	m.Lock()
 	status := m.status
-	m.enabled = false
+	m.disabled++
 	m.Unlock()
 	return status
 }
@@ -126,7 +132,9 @@ func (m *fileSystemManager) Disable() fileOpStatus {
 func (m *fileSystemManager) Enable() fileOpStatus {
 	m.Lock()
 	status := m.status
-	m.enabled = true
+	if m.disabled > 0 {
+		m.disabled--
+	}
 	m.Unlock()
 	return status
 }
@@ -156,9 +164,18 @@ func (m *fileSystemManager) Run(
 		if err := m.Cleanup(t); err != nil {
 			m.log.Error("error when cleaning up data", zap.Time("time", t), zap.Error(err))
 		}
+		if err := m.Tier(t); err != nil {
+			m.log.Error("error when tiering data", zap.Time("time", t), zap.Error(err))
+		}
+		if err := m.Downsample(t); err != nil {
+			m.log.Error("error when downsampling data", zap.Time("time", t), zap.Error(err))
+		}
 		if err := m.Flush(t); err != nil {
 			m.log.Error("error when flushing data", zap.Time("time", t), zap.Error(err))
 		}
+		if err := m.backup(); err != nil {
+			m.log.Error("error when backing up data", zap.Time("time", t), zap.Error(err))
+		}
 		m.Lock()
 		m.status = fileOpNotStarted
 		m.Unlock()
@@ -174,9 +191,33 @@ func (m *fileSystemManager) Run(
 
 func (m *fileSystemManager) Report() {
 	m.databaseCleanupManager.Report()
+	m.databaseTieringManager.Report()
+	m.databaseDownsampleManager.Report()
 	m.databaseFlushManager.Report()
 }
 
+// backup uploads the filesets completed by the flush, and any that previously
+// failed to upload, to the object store if backups are enabled.
+func (m *fileSystemManager) backup() error {
+	backupMgr := m.opts.BackupManager()
+	if backupMgr == nil {
+		return nil
+	}
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	mds := make([]namespace.Metadata, 0, len(namespaces))
+	for _, n := range namespaces {
+		mds = append(mds, n.Metadata())
+	}
+
+	_, err = backupMgr.Backup(mds)
+	return err
+}
+
 func (m *fileSystemManager) shouldRunWithLock() bool {
-	return m.enabled && m.status != fileOpInProgress && m.database.IsBootstrapped()
+	return m.disabled == 0 && m.status != fileOpInProgress && m.database.IsBootstrapped()
 }
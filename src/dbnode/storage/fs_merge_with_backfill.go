@@ -0,0 +1,208 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	errBackfillDuplicateSeries    = errors.New("backfill contains duplicate series")
+	errBackfillDatapointsUnsorted = errors.New("backfill datapoints must be sorted by strictly increasing timestamp")
+)
+
+// fsMergeWithBackfill implements fs.MergeWith, where the merge target is the
+// data of a backfill for a single block. The datapoints of each series are
+// encoded up front so the block can be merged without putting the data
+// through the series buffers.
+type fsMergeWithBackfill struct {
+	blockStart   xtime.UnixNano
+	blockSize    time.Duration
+	tombstones   *shardTombstones
+	seriesExpiry *shardSeriesExpiry
+	series       []backfillSegment
+	seriesIdx    map[string]int
+}
+
+type backfillSegment struct {
+	id      ident.ID
+	tags    ident.Tags
+	segment ts.Segment
+	read    bool
+}
+
+func newFSMergeWithBackfill(
+	blockStart time.Time,
+	blockSize time.Duration,
+	series []BackfillSeries,
+	encoderPool encoding.EncoderPool,
+	blockAllocSize int,
+	tombstones *shardTombstones,
+	seriesExpiry *shardSeriesExpiry,
+	nsCtx namespace.Context,
+) (*fsMergeWithBackfill, error) {
+	m := &fsMergeWithBackfill{
+		blockStart:   xtime.ToUnixNano(blockStart),
+		blockSize:    blockSize,
+		tombstones:   tombstones,
+		seriesExpiry: seriesExpiry,
+		series:       make([]backfillSegment, 0, len(series)),
+		seriesIdx:    make(map[string]int, len(series)),
+	}
+
+	blockEnd := blockStart.Add(blockSize)
+	for _, s := range series {
+		if len(s.Datapoints) == 0 {
+			continue
+		}
+
+		key := string(s.ID.Bytes())
+		if _, ok := m.seriesIdx[key]; ok {
+			m.close()
+			return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"%v: %s", errBackfillDuplicateSeries, s.ID.String()))
+		}
+
+		encoder := encoderPool.Get()
+		encoder.Reset(blockStart, blockAllocSize, nsCtx.Schema)
+		var prev time.Time
+		for i, dp := range s.Datapoints {
+			if dp.Timestamp.Before(blockStart) || !dp.Timestamp.Before(blockEnd) {
+				encoder.Close()
+				m.close()
+				return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+					"backfill datapoint for series %s at %v is outside of block [%v, %v)",
+					s.ID.String(), dp.Timestamp, blockStart, blockEnd))
+			}
+			if i > 0 && !dp.Timestamp.After(prev) {
+				encoder.Close()
+				m.close()
+				return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+					"%v: series %s", errBackfillDatapointsUnsorted, s.ID.String()))
+			}
+			prev = dp.Timestamp
+
+			err := encoder.Encode(ts.Datapoint{
+				Timestamp: dp.Timestamp,
+				Value:     dp.Value,
+			}, dp.Unit, dp.Annotation)
+			if err != nil {
+				encoder.Close()
+				m.close()
+				return nil, err
+			}
+		}
+
+		m.seriesIdx[key] = len(m.series)
+		m.series = append(m.series, backfillSegment{
+			id:      s.ID,
+			tags:    s.Tags,
+			segment: encoder.Discard(),
+		})
+	}
+
+	return m, nil
+}
+
+func (m *fsMergeWithBackfill) Read(
+	ctx context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	if blockStart != m.blockStart {
+		return nil, false, nil
+	}
+
+	idx, ok := m.seriesIdx[string(seriesID.Bytes())]
+	if !ok {
+		return nil, false, nil
+	}
+
+	// Mark the series as read so that it is not written again when looping
+	// through the remaining series.
+	m.series[idx].read = true
+	return m.blockReaders(idx), true, nil
+}
+
+func (m *fsMergeWithBackfill) blockReaders(idx int) []xio.BlockReader {
+	return []xio.BlockReader{{
+		SegmentReader: xio.NewSegmentReader(m.series[idx].segment),
+		Start:         m.blockStart.ToTime(),
+		BlockSize:     m.blockSize,
+	}}
+}
+
+// ForEachRemaining writes the backfilled series that do not yet have any
+// data in the fileset being merged, the IDs and tags are owned by the caller
+// of the backfill and live for as long as the merge.
+func (m *fsMergeWithBackfill) ForEachRemaining(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	fn fs.ForEachRemainingFn,
+	nsCtx namespace.Context,
+) error {
+	if blockStart != m.blockStart {
+		return nil
+	}
+
+	for i := range m.series {
+		if m.series[i].read {
+			continue
+		}
+		m.series[i].read = true
+		if err := fn(m.series[i].id, m.series[i].tags, m.blockReaders(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *fsMergeWithBackfill) DeletedRanges(
+	seriesID ident.ID,
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) xtime.Ranges {
+	return seriesDeletedRanges(m.tombstones, m.seriesExpiry, seriesID, tags, blockStart)
+}
+
+// close returns the encoded data of the series back to the pools, it must
+// only be called once the merge has completed.
+func (m *fsMergeWithBackfill) close() {
+	for i := range m.series {
+		m.series[i].segment.Finalize()
+	}
+	m.series = nil
+	m.seriesIdx = nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackfillSeries(id string, start time.Time, values ...float64) BackfillSeries {
+	dps := make([]BackfillDatapoint, 0, len(values))
+	for i, v := range values {
+		dps = append(dps, BackfillDatapoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Value:     v,
+			Unit:      xtime.Second,
+		})
+	}
+	return BackfillSeries{
+		ID:         ident.StringID(id),
+		Tags:       ident.NewTags(ident.StringTag("name", id)),
+		Datapoints: dps,
+	}
+}
+
+func newTestFSMergeWithBackfill(
+	blockStart time.Time,
+	series []BackfillSeries,
+) (*fsMergeWithBackfill, error) {
+	opts := DefaultTestOptions()
+	return newFSMergeWithBackfill(blockStart, 2*time.Hour, series,
+		opts.EncoderPool(), 0, newShardTombstones(2*time.Hour), nil, namespace.Context{})
+}
+
+func readBackfillValues(t *testing.T, blocks []xio.BlockReader) []float64 {
+	require.Len(t, blocks, 1)
+
+	iter := DefaultTestOptions().ReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset(blocks[0].SegmentReader, nil)
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	return values
+}
+
+func TestFSMergeWithBackfillRead(t *testing.T) {
+	var (
+		ctx        = context.NewContext()
+		blockStart = time.Now().Truncate(2 * time.Hour).Add(-24 * time.Hour)
+		unixStart  = xtime.ToUnixNano(blockStart)
+		nsCtx      = namespace.Context{}
+	)
+	mergeWith, err := newTestFSMergeWithBackfill(blockStart, []BackfillSeries{
+		newTestBackfillSeries("foo", blockStart, 1, 2, 3),
+		newTestBackfillSeries("bar", blockStart.Add(time.Hour), 4, 5),
+		newTestBackfillSeries("empty", blockStart),
+	})
+	require.NoError(t, err)
+	defer mergeWith.close()
+
+	blocks, ok, err := mergeWith.Read(ctx, ident.StringID("foo"), unixStart, nsCtx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []float64{1, 2, 3}, readBackfillValues(t, blocks))
+
+	// Series without datapoints and other blocks have nothing to merge.
+	_, ok, err = mergeWith.Read(ctx, ident.StringID("empty"), unixStart, nsCtx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = mergeWith.Read(ctx, ident.StringID("foo"), xtime.ToUnixNano(blockStart.Add(2*time.Hour)), nsCtx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Only the series that were not read are remaining.
+	var remaining []string
+	err = mergeWith.ForEachRemaining(ctx, unixStart, func(
+		seriesID ident.ID,
+		tags ident.Tags,
+		data []xio.BlockReader,
+	) error {
+		remaining = append(remaining, seriesID.String())
+		assert.Equal(t, []float64{4, 5}, readBackfillValues(t, data))
+		return nil
+	}, nsCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar"}, remaining)
+}
+
+func TestFSMergeWithBackfillInvalidSeries(t *testing.T) {
+	blockStart := time.Now().Truncate(2 * time.Hour).Add(-24 * time.Hour)
+
+	unsorted := newTestBackfillSeries("foo", blockStart, 1, 2)
+	unsorted.Datapoints[0], unsorted.Datapoints[1] = unsorted.Datapoints[1], unsorted.Datapoints[0]
+
+	tests := []struct {
+		name   string
+		series []BackfillSeries
+	}{
+		{
+			name:   "unsorted",
+			series: []BackfillSeries{unsorted},
+		},
+		{
+			name: "before block",
+			series: []BackfillSeries{
+				newTestBackfillSeries("foo", blockStart.Add(-time.Minute), 1),
+			},
+		},
+		{
+			name: "after block",
+			series: []BackfillSeries{
+				newTestBackfillSeries("foo", blockStart.Add(2*time.Hour), 1),
+			},
+		},
+		{
+			name: "duplicate series",
+			series: []BackfillSeries{
+				newTestBackfillSeries("foo", blockStart, 1),
+				newTestBackfillSeries("foo", blockStart.Add(time.Minute), 2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestFSMergeWithBackfill(blockStart, tt.series)
+			require.Error(t, err)
+			assert.True(t, xerrors.IsInvalidParams(err))
+		})
+	}
+}
@@ -39,6 +39,8 @@ import (
 type fsMergeWithMem struct {
 	shard              databaseShard
 	retriever          series.QueryableBlockRetriever
+	tombstones         *shardTombstones
+	seriesExpiry       *shardSeriesExpiry
 	dirtySeries        *dirtySeriesMap
 	dirtySeriesToWrite map[xtime.UnixNano]*idList
 }
@@ -46,12 +48,16 @@ type fsMergeWithMem struct {
 func newFSMergeWithMem(
 	shard databaseShard,
 	retriever series.QueryableBlockRetriever,
+	tombstones *shardTombstones,
+	seriesExpiry *shardSeriesExpiry,
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
 ) fs.MergeWith {
 	return &fsMergeWithMem{
 		shard:              shard,
 		retriever:          retriever,
+		tombstones:         tombstones,
+		seriesExpiry:       seriesExpiry,
 		dirtySeries:        dirtySeries,
 		dirtySeriesToWrite: dirtySeriesToWrite,
 	}
@@ -148,3 +154,11 @@ func (m *fsMergeWithMem) ForEachRemaining(
 
 	return nil
 }
+
+func (m *fsMergeWithMem) DeletedRanges(
+	seriesID ident.ID,
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) xtime.Ranges {
+	return seriesDeletedRanges(m.tombstones, m.seriesExpiry, seriesID, tags, blockStart)
+}
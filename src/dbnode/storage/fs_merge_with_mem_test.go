@@ -23,6 +23,7 @@ package storage
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/series"
@@ -76,7 +77,7 @@ func TestRead(t *testing.T) {
 			Return(fetchedBlocks, nil)
 	}
 
-	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite)
+	mergeWith := newFSMergeWithMem(shard, retriever, newShardTombstones(time.Hour), nil, dirtySeries, dirtySeriesToWrite)
 
 	for _, d := range data {
 		require.True(t, dirtySeries.Contains(idAndBlockStart{blockStart: d.start, id: d.id}))
@@ -160,7 +161,7 @@ func TestForEachRemaining(t *testing.T) {
 		addDirtySeries(dirtySeries, dirtySeriesToWrite, d.id, d.start)
 	}
 
-	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite)
+	mergeWith := newFSMergeWithMem(shard, retriever, newShardTombstones(time.Hour), nil, dirtySeries, dirtySeriesToWrite)
 
 	var forEachCalls []ident.ID
 	shard.EXPECT().TagsFromSeriesID(gomock.Any()).Return(ident.Tags{}, true, nil).Times(2)
@@ -25,6 +25,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
+	"github.com/m3db/m3/src/x/ident"
+
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +72,19 @@ func TestFileSystemManagerShouldRunEnableDisable(t *testing.T) {
 	require.False(t, mgr.shouldRunWithLock())
 	mgr.Enable()
 	require.True(t, mgr.shouldRunWithLock())
+
+	// File operations stay disabled until every disable is reverted.
+	mgr.Disable()
+	mgr.Disable()
+	mgr.Enable()
+	require.False(t, mgr.shouldRunWithLock())
+	mgr.Enable()
+	require.True(t, mgr.shouldRunWithLock())
+
+	// Extra enables do not leave file operations enabled across a disable.
+	mgr.Enable()
+	mgr.Disable()
+	require.False(t, mgr.shouldRunWithLock())
 }
 
 func TestFileSystemManagerRun(t *testing.T) {
@@ -78,17 +95,73 @@ func TestFileSystemManagerRun(t *testing.T) {
 
 	fm := NewMockdatabaseFlushManager(ctrl)
 	cm := NewMockdatabaseCleanupManager(ctrl)
+	tm := NewMockdatabaseTieringManager(ctrl)
+	dm := NewMockdatabaseDownsampleManager(ctrl)
 	fsm := newFileSystemManager(database, nil, DefaultTestOptions())
 	mgr := fsm.(*fileSystemManager)
 	mgr.databaseFlushManager = fm
 	mgr.databaseCleanupManager = cm
+	mgr.databaseTieringManager = tm
+	mgr.databaseDownsampleManager = dm
 
 	ts := time.Now()
 	gomock.InOrder(
 		cm.EXPECT().Cleanup(ts).Return(errors.New("foo")),
+		tm.EXPECT().Tier(ts).Return(errors.New("baz")),
+		dm.EXPECT().Downsample(ts).Return(errors.New("qux")),
 		fm.EXPECT().Flush(ts).Return(errors.New("bar")),
 	)
 
 	mgr.Run(ts, syncRun, noForce)
 	require.Equal(t, fileOpNotStarted, mgr.status)
 }
+
+type testBackupManager struct {
+	namespaces []namespace.Metadata
+}
+
+func (m *testBackupManager) Backup(
+	namespaces []namespace.Metadata,
+) (backup.Result, error) {
+	m.namespaces = namespaces
+	return backup.Result{}, nil
+}
+
+func TestFileSystemManagerRunBacksUpAfterFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	database := newMockdatabase(ctrl)
+	database.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+
+	md, err := namespace.NewMetadata(ident.StringID("foo"), namespace.NewOptions())
+	require.NoError(t, err)
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Metadata().Return(md)
+
+	var (
+		fm        = NewMockdatabaseFlushManager(ctrl)
+		cm        = NewMockdatabaseCleanupManager(ctrl)
+		tm        = NewMockdatabaseTieringManager(ctrl)
+		dm        = NewMockdatabaseDownsampleManager(ctrl)
+		backupMgr = &testBackupManager{}
+		opts      = DefaultTestOptions().SetBackupManager(backupMgr)
+	)
+	fsm := newFileSystemManager(database, nil, opts)
+	mgr := fsm.(*fileSystemManager)
+	mgr.databaseFlushManager = fm
+	mgr.databaseCleanupManager = cm
+	mgr.databaseTieringManager = tm
+	mgr.databaseDownsampleManager = dm
+
+	ts := time.Now()
+	gomock.InOrder(
+		cm.EXPECT().Cleanup(ts).Return(nil),
+		tm.EXPECT().Tier(ts).Return(nil),
+		dm.EXPECT().Downsample(ts).Return(nil),
+		fm.EXPECT().Flush(ts).Return(nil),
+		database.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil),
+	)
+
+	mgr.Run(ts, syncRun, noForce)
+	require.Equal(t, []namespace.Metadata{md}, backupMgr.namespaces)
+}
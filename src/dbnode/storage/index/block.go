@@ -28,17 +28,22 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/dbnode/storage/index/segments"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
 	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
 	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
@@ -111,6 +116,17 @@ type block struct {
 	backgroundSegments  []*readableSeg
 	shardRangesSegments []blockShardRangesSegments
 
+	// seriesRetentionRules are the series retention rules of the namespace
+	// in order, series that have expired in the block are excluded from
+	// query and aggregate results.
+	seriesRetentionRules []blockSeriesRetentionRule
+
+	// deletedSeries resolves the series that have had all of their data
+	// within the block deleted, they are excluded from query and aggregate
+	// results.
+	deletedSeries DeletedSeries
+	deletedCache  deletedSeriesCache
+
 	newFieldsAndTermsIteratorFn newFieldsAndTermsIteratorFn
 	newExecutorFn               newExecutorFn
 	blockStart                  time.Time
@@ -153,6 +169,57 @@ func newBlockMetrics(s tally.Scope) blockMetrics {
 	}
 }
 
+// blockSeriesRetentionRule is a series retention rule along with a searcher
+// for the documents of the series that match its tag filters.
+type blockSeriesRetentionRule struct {
+	period   time.Duration
+	searcher search.Searcher
+}
+
+func newBlockSeriesRetentionRules(
+	rules []retention.SeriesRetentionRule,
+) ([]blockSeriesRetentionRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	result := make([]blockSeriesRetentionRule, 0, len(rules))
+	for _, rule := range rules {
+		tagFilters, err := rule.TagFilters()
+		if err != nil {
+			return nil, err
+		}
+		// Like the matcher, a filter on a tag the series does not have never
+		// matches so each filter only matches the terms of its tag.
+		searchers := make(search.Searchers, 0, len(tagFilters))
+		for name, f := range tagFilters {
+			searchers = append(searchers, searcher.NewRangeSearcher([]byte(name),
+				m3ninxindex.TermRange{Filter: f.Matches}))
+		}
+		s, err := searcher.NewConjunctionSearcher(searchers, nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, blockSeriesRetentionRule{
+			period:   rule.Period,
+			searcher: s,
+		})
+	}
+	return result, nil
+}
+
+// deletedSeriesCache caches the IDs of the series deleted within a block and
+// the postings of their documents in each of the block's segments, until the
+// version of the deleted series changes.
+type deletedSeriesCache struct {
+	sync.Mutex
+
+	valid    bool
+	version  uint64
+	ids      map[string]struct{}
+	postings map[segment.Segment]postings.List
+}
+
 // blockShardsSegments is a collection of segments that has a mapping of what shards
 // and time ranges they completely cover, this can only ever come from computing
 // from data that has come from shards, either on an index flush or a bootstrap.
@@ -177,6 +244,11 @@ func NewBlock(
 ) (Block, error) {
 	blockSize := md.Options().IndexOptions().BlockSize()
 	iopts := indexOpts.InstrumentOptions()
+	seriesRetentionRules, err := newBlockSeriesRetentionRules(
+		md.Options().RetentionOptions().SeriesRetentionRules())
+	if err != nil {
+		return nil, err
+	}
 	b := &block{
 		state:      blockStateOpen,
 		blockStart: blockStart,
@@ -187,6 +259,9 @@ func NewBlock(
 		nsMD:       md,
 		metrics:    newBlockMetrics(iopts.MetricsScope()),
 		logger:     iopts.Logger(),
+
+		seriesRetentionRules: seriesRetentionRules,
+		deletedSeries:        indexOpts.DeletedSeries(),
 	}
 	b.newFieldsAndTermsIteratorFn = newFieldsAndTermsIterator
 	b.newExecutorFn = b.executorWithRLock
@@ -817,8 +892,19 @@ func (b *block) queryWithSpan(
 		}
 	}()
 
+	// Series that have expired within the block are excluded by the query
+	// itself rather than by looking up their retention for every document.
+	searchQuery := query.Query.SearchQuery()
+	expired, err := b.expiredSeriesSearcher()
+	if err != nil {
+		return false, err
+	}
+	if expired != nil {
+		searchQuery = &excludeQuery{query: searchQuery, exclude: expired}
+	}
+
 	// FOLLOWUP(prateek): push down QueryOptions to restrict results
-	iter, err := exec.Execute(query.Query.SearchQuery())
+	iter, err := exec.Execute(searchQuery)
 	if err != nil {
 		return false, err
 	}
@@ -856,12 +942,18 @@ func (b *block) queryWithSpan(
 		docsPool.Put(batch)
 	}()
 
+	deletedIDs := b.deletedIDs()
 	for iter.Next() {
 		if opts.LimitExceeded(size) {
 			break
 		}
 
-		batch = append(batch, iter.Current())
+		doc := iter.Current()
+		if isDeleted(deletedIDs, doc.ID) {
+			continue
+		}
+
+		batch = append(batch, doc)
 		if len(batch) < batchSize {
 			continue
 		}
@@ -891,6 +983,221 @@ func (b *block) queryWithSpan(
 	return exhaustive, nil
 }
 
+// deletedIDs returns the IDs of the series that have had all of their data
+// within the block deleted.
+func (b *block) deletedIDs() map[string]struct{} {
+	if b.deletedSeries == nil {
+		return nil
+	}
+
+	b.deletedCache.Lock()
+	defer b.deletedCache.Unlock()
+	return b.deletedIDsWithLock()
+}
+
+func (b *block) deletedIDsWithLock() map[string]struct{} {
+	c := &b.deletedCache
+	version := b.deletedSeries.DeletedVersion()
+	if c.valid && c.version == version {
+		return c.ids
+	}
+
+	// NB: the cached IDs are replaced rather than updated in place since
+	// they are read without holding the lock.
+	var ids map[string]struct{}
+	blockRange := xtime.Range{Start: b.blockStart, End: b.blockEnd}
+	b.deletedSeries.ForEachDeleted(blockRange, func(id []byte) {
+		if ids == nil {
+			ids = make(map[string]struct{})
+		}
+		ids[string(id)] = struct{}{}
+	})
+	c.valid = true
+	c.version = version
+	c.ids = ids
+	c.postings = nil
+	return ids
+}
+
+// deletedPostings returns the postings of the documents of the deleted series
+// in each of the segments, the postings are only looked up for segments they
+// have not already been looked up for since the deleted series last changed.
+func (b *block) deletedPostings(segs []segment.Segment) ([]postings.List, error) {
+	if b.deletedSeries == nil {
+		return nil, nil
+	}
+
+	b.deletedCache.Lock()
+	defer b.deletedCache.Unlock()
+	ids := b.deletedIDsWithLock()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var (
+		c      = &b.deletedCache
+		result = make([]postings.List, 0, len(segs))
+		cached = make(map[segment.Segment]postings.List, len(segs))
+	)
+	for _, s := range segs {
+		pl, ok := c.postings[s]
+		if !ok {
+			var err error
+			pl, err = segmentDeletedPostings(s, ids)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cached[s] = pl
+		result = append(result, pl)
+	}
+	// Only the postings of the current segments are kept so that those of
+	// segments that have since been compacted are released.
+	c.postings = cached
+	return result, nil
+}
+
+func isDeleted(deletedIDs map[string]struct{}, id []byte) bool {
+	if len(deletedIDs) == 0 {
+		return false
+	}
+	_, ok := deletedIDs[string(id)]
+	return ok
+}
+
+// segmentDeletedPostings returns the postings of the documents of the deleted
+// series in the segment, or nil if the segment holds none of them.
+func segmentDeletedPostings(
+	s segment.Segment,
+	deletedIDs map[string]struct{},
+) (postings.List, error) {
+	if len(deletedIDs) == 0 {
+		return nil, nil
+	}
+
+	reader, err := s.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var result postings.MutableList
+	for id := range deletedIDs {
+		pl, err := reader.MatchTerm(doc.IDReservedFieldName, []byte(id))
+		if err != nil {
+			return nil, err
+		}
+		if pl.IsEmpty() {
+			continue
+		}
+		if result == nil {
+			result = roaring.NewPostingsList()
+		}
+		if err := result.AddIterator(pl.Iterator()); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// expiredSeriesSearcher returns a searcher for the documents of the series
+// that have fallen out of the retention period of their series retention rule
+// within the block, or nil if no rule has expired the block. The retention of
+// a series is that of the first rule it matches, so a later rule only expires
+// the series that no earlier unexpired rule matches.
+func (b *block) expiredSeriesSearcher() (search.Searcher, error) {
+	if len(b.seriesRetentionRules) == 0 {
+		return nil, nil
+	}
+
+	var (
+		now      = b.opts.ClockOptions().NowFn()()
+		retained search.Searchers
+		expired  search.Searchers
+	)
+	for _, rule := range b.seriesRetentionRules {
+		if b.blockEnd.Add(rule.period).After(now) {
+			retained = append(retained, rule.searcher)
+			continue
+		}
+		if len(retained) == 0 {
+			expired = append(expired, rule.searcher)
+			continue
+		}
+		s, err := searcher.NewConjunctionSearcher(search.Searchers{rule.searcher},
+			retained[:len(retained):len(retained)])
+		if err != nil {
+			return nil, err
+		}
+		expired = append(expired, s)
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	return searcher.NewDisjunctionSearcher(expired)
+}
+
+// segmentExpiredPostings returns the postings of the documents in the segment
+// that are matched by the searcher for expired series.
+func segmentExpiredPostings(
+	s segment.Segment,
+	expired search.Searcher,
+) (postings.List, error) {
+	reader, err := s.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return expired.Search(reader)
+}
+
+// unionPostings returns the union of the postings lists, either of which may
+// be nil.
+func unionPostings(a, b postings.List) (postings.List, error) {
+	if a == nil || a.IsEmpty() {
+		return b, nil
+	}
+	if b == nil || b.IsEmpty() {
+		return a, nil
+	}
+	result := a.Clone()
+	if err := result.Union(b); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// excludeQuery is a query that excludes the documents matched by a searcher
+// from those matched by another query. It is only executed locally against
+// the segments of a block, so it is serialized as the query it wraps.
+type excludeQuery struct {
+	query   search.Query
+	exclude search.Searcher
+}
+
+func (q *excludeQuery) Searcher() (search.Searcher, error) {
+	s, err := q.query.Searcher()
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewConjunctionSearcher(search.Searchers{s},
+		search.Searchers{q.exclude})
+}
+
+func (q *excludeQuery) Equal(o search.Query) bool {
+	other, ok := o.(*excludeQuery)
+	return ok && q.exclude == other.exclude && q.query.Equal(other.query)
+}
+
+func (q *excludeQuery) ToProto() *querypb.Query {
+	return q.query.ToProto()
+}
+
+func (q *excludeQuery) String() string {
+	return fmt.Sprintf("exclude(%s)", q.query.String())
+}
+
 func (b *block) closeExecutorAsync(exec search.Executor) {
 	// Note: This only happens if closing the readers isn't clean.
 	if err := exec.Close(); err != nil {
@@ -1018,11 +1325,35 @@ func (b *block) aggregateWithSpan(
 	}()
 
 	segs := b.segmentsWithRLock()
-	for _, s := range segs {
+	deleted, err := b.deletedPostings(segs)
+	if err != nil {
+		return false, err
+	}
+	expired, err := b.expiredSeriesSearcher()
+	if err != nil {
+		return false, err
+	}
+	for i, s := range segs {
 		if opts.LimitExceeded(size) {
 			break
 		}
 
+		iterateOpts.excludePostings = nil
+		if deleted != nil {
+			iterateOpts.excludePostings = deleted[i]
+		}
+		if expired != nil {
+			expiredPostings, err := segmentExpiredPostings(s, expired)
+			if err != nil {
+				return false, err
+			}
+			iterateOpts.excludePostings, err = unionPostings(
+				iterateOpts.excludePostings, expiredPostings)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		err = iter.Reset(s, iterateOpts)
 		if err != nil {
 			return false, err
@@ -615,6 +615,173 @@ func TestBlockMockQueryExecutorExecIterCloseErr(t *testing.T) {
 	ctx.BlockingClose()
 }
 
+func TestBlockE2EInsertQuerySeriesRetentionExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The second rule expires the series with a "some" tag unless the first
+	// rule, which has not expired the block, matches them first.
+	ropts := retention.NewOptions().
+		SetBlockSize(time.Hour).
+		SetRetentionPeriod(24 * time.Hour).
+		SetSeriesRetentionRules([]retention.SeriesRetentionRule{
+			{Filter: "bar:qux", Period: 12 * time.Hour},
+			{Filter: "some:*", Period: 2 * time.Hour},
+		})
+	iopts := namespace.NewIndexOptions().
+		SetEnabled(true).
+		SetBlockSize(time.Hour)
+	testMD, err := namespace.NewMetadata(ident.StringID("testNs"),
+		namespace.NewOptions().SetRetentionOptions(ropts).SetIndexOptions(iopts))
+	require.NoError(t, err)
+
+	// The block ended more than the period of the second rule ago.
+	blockStart := time.Now().Truncate(time.Hour).Add(-4 * time.Hour)
+	blk, err := NewBlock(blockStart, testMD, BlockOptions{}, testOpts)
+	require.NoError(t, err)
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: time.Hour,
+	})
+	for _, d := range []doc.Document{testDoc1(), testDoc2(), testDoc3()} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(xtime.ToUnixNano(blockStart))
+		h.EXPECT().OnIndexSuccess(xtime.ToUnixNano(blockStart))
+		batch.Append(WriteBatchEntry{
+			Timestamp:     blockStart.Add(time.Minute),
+			OnIndexSeries: h,
+		}, d)
+	}
+	res, err := b.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), res.NumSuccess)
+
+	q, err := idx.NewRegexpQuery([]byte("bar"), []byte(".*"))
+	require.NoError(t, err)
+
+	ctx := context.NewContext()
+	defer ctx.BlockingClose()
+
+	results := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
+	exhaustive, err := b.Query(ctx, resource.NewCancellableLifetime(),
+		Query{q}, QueryOptions{}, results, emptyLogFields)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+
+	// Only the series matching the second rule have expired.
+	require.Equal(t, 2, results.Map().Len())
+	_, ok = results.Map().Get(ident.StringID(string(testDoc1().ID)))
+	require.True(t, ok)
+	_, ok = results.Map().Get(ident.StringID(string(testDoc3().ID)))
+	require.True(t, ok)
+
+	aggResults := NewAggregateResults(ident.StringID("ns"), AggregateResultsOptions{
+		SizeLimit: 10,
+		Type:      AggregateTagNamesAndValues,
+	}, testOpts)
+	exhaustive, err = b.Aggregate(ctx, resource.NewCancellableLifetime(),
+		QueryOptions{Limit: 10}, aggResults, emptyLogFields)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+	assertAggregateResultsMapEquals(t, map[string][]string{
+		"bar":  []string{"baz", "qux"},
+		"some": []string{"other"},
+	}, aggResults)
+}
+
+func TestBlockMockQueryDeletedSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	deleted := NewMockDeletedSeries(ctrl)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts.SetDeletedSeries(deleted))
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	exec := search.NewMockExecutor(ctrl)
+	b.newExecutorFn = func() (search.Executor, error) {
+		return exec, nil
+	}
+
+	deleted.EXPECT().DeletedVersion().Return(uint64(1))
+	deleted.EXPECT().
+		ForEachDeleted(xtime.Range{Start: b.blockStart, End: b.blockEnd}, gomock.Any()).
+		Do(func(_ xtime.Range, fn func(id []byte)) {
+			fn(testDoc2().ID)
+		})
+
+	dIter := doc.NewMockIterator(ctrl)
+	gomock.InOrder(
+		exec.EXPECT().Execute(gomock.Any()).Return(dIter, nil),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc1()),
+		dIter.EXPECT().Next().Return(true),
+		dIter.EXPECT().Current().Return(testDoc2()),
+		dIter.EXPECT().Next().Return(false),
+		dIter.EXPECT().Err().Return(nil),
+		dIter.EXPECT().Close().Return(nil),
+		exec.EXPECT().Close().Return(nil),
+	)
+	results := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
+
+	ctx := context.NewContext()
+
+	exhaustive, err := b.Query(ctx, resource.NewCancellableLifetime(),
+		defaultQuery, QueryOptions{}, results, emptyLogFields)
+	require.NoError(t, err)
+	require.True(t, exhaustive)
+
+	// The deleted series is excluded.
+	require.Equal(t, 1, results.Map().Len())
+	_, ok = results.Map().Get(ident.StringID(string(testDoc1().ID)))
+	require.True(t, ok)
+
+	// NB(r): Make sure to call finalizers blockingly (to finish
+	// the expected close calls)
+	ctx.BlockingClose()
+}
+
+func TestBlockDeletedIDsCachedUntilVersionChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	deleted := NewMockDeletedSeries(ctrl)
+	blk, err := NewBlock(start, testMD, BlockOptions{}, testOpts.SetDeletedSeries(deleted))
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	blockRange := xtime.Range{Start: b.blockStart, End: b.blockEnd}
+	gomock.InOrder(
+		deleted.EXPECT().DeletedVersion().Return(uint64(1)),
+		deleted.EXPECT().ForEachDeleted(blockRange, gomock.Any()).
+			Do(func(_ xtime.Range, fn func(id []byte)) {
+				fn(testDoc1().ID)
+			}),
+		deleted.EXPECT().DeletedVersion().Return(uint64(1)),
+		deleted.EXPECT().DeletedVersion().Return(uint64(2)),
+		deleted.EXPECT().ForEachDeleted(blockRange, gomock.Any()).
+			Do(func(_ xtime.Range, fn func(id []byte)) {
+				fn(testDoc1().ID)
+				fn(testDoc2().ID)
+			}),
+	)
+
+	require.Equal(t, map[string]struct{}{"foo": {}}, b.deletedIDs())
+	// The deleted series are not looked up again for the same version.
+	require.Equal(t, map[string]struct{}{"foo": {}}, b.deletedIDs())
+	require.Equal(t, map[string]struct{}{"foo": {}, "something": {}}, b.deletedIDs())
+}
+
 func TestBlockMockQueryLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -148,6 +148,45 @@ func TestFieldsTermsIteratorTermsOnly(t *testing.T) {
 	}, slice)
 }
 
+func TestFieldsTermsIteratorExcludePostings(t *testing.T) {
+	s := newFieldsTermsIterSetup(
+		pair{"a", "b"}, pair{"a", "c"},
+		pair{"d", "e"}, pair{"d", "f"},
+		pair{"g", "h"},
+	)
+	seg := s.asSegment(t)
+
+	excluded, err := segmentDeletedPostings(seg, map[string]struct{}{
+		"id_a_b": struct{}{},
+		"id_a_c": struct{}{},
+		"id_d_e": struct{}{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, excluded.Len())
+
+	iter, err := newFieldsAndTermsIterator(seg, fieldsAndTermsIteratorOpts{
+		iterateTerms:    true,
+		excludePostings: excluded,
+	})
+	require.NoError(t, err)
+	requireSlicesEqual(t, []pair{
+		pair{"d", "f"},
+		pair{"g", "h"},
+	}, toSlice(t, iter))
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+
+	iter, err = newFieldsAndTermsIterator(seg, fieldsAndTermsIteratorOpts{
+		excludePostings: excluded,
+	})
+	require.NoError(t, err)
+	requireSlicesEqual(t, []pair{
+		pair{"d", ""}, pair{"g", ""},
+	}, toSlice(t, iter))
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+}
+
 func TestFieldsTermsIteratorEmptyTerm(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{T: t})
 	defer ctrl.Finish()
@@ -22,6 +22,7 @@ package index
 
 import (
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
 	xerrors "github.com/m3db/m3/src/x/errors"
 )
 
@@ -30,6 +31,9 @@ type fieldsAndTermsIteratorOpts struct {
 	iterateTerms bool
 	allowFn      allowFn
 	fieldIterFn  newFieldIterFn
+	// excludePostings are the documents to leave out, fields and terms that
+	// only occur in these documents are skipped.
+	excludePostings postings.List
 }
 
 func (o fieldsAndTermsIteratorOpts) allow(f []byte) bool {
@@ -39,6 +43,25 @@ func (o fieldsAndTermsIteratorOpts) allow(f []byte) bool {
 	return o.allowFn(f)
 }
 
+func (o fieldsAndTermsIteratorOpts) hasExclusions() bool {
+	return o.excludePostings != nil && !o.excludePostings.IsEmpty()
+}
+
+// excluded returns whether every document in the postings list is excluded.
+func (o fieldsAndTermsIteratorOpts) excluded(pl postings.List) bool {
+	if !o.hasExclusions() {
+		return false
+	}
+	iter := pl.Iterator()
+	defer iter.Close()
+	for iter.Next() {
+		if !o.excludePostings.Contains(iter.Current()) {
+			return false
+		}
+	}
+	return iter.Err() == nil
+}
+
 func (o fieldsAndTermsIteratorOpts) newFieldIter(s segment.Segment) (segment.FieldsIterator, error) {
 	if o.fieldIterFn == nil {
 		return s.FieldsIterable().Fields()
@@ -110,6 +133,18 @@ func (fti *fieldsAndTermsIter) setNextField() bool {
 		if !fti.opts.allow(field) {
 			continue
 		}
+		if !fti.opts.iterateTerms && fti.opts.hasExclusions() {
+			// When only iterating fields the terms are checked here so that
+			// fields only held by excluded documents are skipped.
+			ok, err := fti.hasTermNotExcluded(field)
+			if err != nil {
+				fti.err = err
+				return false
+			}
+			if !ok {
+				continue
+			}
+		}
 		fti.current.field = field
 		return true
 	}
@@ -118,11 +153,32 @@ func (fti *fieldsAndTermsIter) setNextField() bool {
 	return false
 }
 
+func (fti *fieldsAndTermsIter) hasTermNotExcluded(field []byte) (bool, error) {
+	termsIter, err := fti.seg.TermsIterable().Terms(field)
+	if err != nil {
+		return false, err
+	}
+	for termsIter.Next() {
+		if _, pl := termsIter.Current(); !fti.opts.excluded(pl) {
+			return true, termsIter.Close()
+		}
+	}
+	if err := termsIter.Err(); err != nil {
+		termsIter.Close()
+		return false, err
+	}
+	return false, termsIter.Close()
+}
+
 func (fti *fieldsAndTermsIter) setNext() bool {
 	// check if current field has another term
 	if fti.termIter != nil {
-		if fti.termIter.Next() {
-			fti.current.term, _ = fti.termIter.Current()
+		for fti.termIter.Next() {
+			term, pl := fti.termIter.Current()
+			if fti.opts.excluded(pl) {
+				continue
+			}
+			fti.current.term = term
 			return true
 		}
 		if err := fti.termIter.Err(); err != nil {
@@ -160,7 +216,12 @@ func (fti *fieldsAndTermsIter) setNext() bool {
 		return fti.setNext()
 	}
 
-	fti.current.term, _ = fti.termIter.Current()
+	term, pl := fti.termIter.Current()
+	if fti.opts.excluded(pl) {
+		// i.e. the first term is excluded, move on to the following ones
+		return fti.setNext()
+	}
+	fti.current.term = term
 	return true
 }
 
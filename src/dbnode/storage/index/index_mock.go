@@ -1523,3 +1523,80 @@ func (mr *MockOptionsMockRecorder) ForwardIndexThreshold() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardIndexThreshold", reflect.TypeOf((*MockOptions)(nil).ForwardIndexThreshold))
 }
+
+// SetDeletedSeries mocks base method
+func (m *MockOptions) SetDeletedSeries(value DeletedSeries) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeletedSeries", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDeletedSeries indicates an expected call of SetDeletedSeries
+func (mr *MockOptionsMockRecorder) SetDeletedSeries(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeletedSeries", reflect.TypeOf((*MockOptions)(nil).SetDeletedSeries), value)
+}
+
+// DeletedSeries mocks base method
+func (m *MockOptions) DeletedSeries() DeletedSeries {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedSeries")
+	ret0, _ := ret[0].(DeletedSeries)
+	return ret0
+}
+
+// DeletedSeries indicates an expected call of DeletedSeries
+func (mr *MockOptionsMockRecorder) DeletedSeries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedSeries", reflect.TypeOf((*MockOptions)(nil).DeletedSeries))
+}
+
+// MockDeletedSeries is a mock of DeletedSeries interface
+type MockDeletedSeries struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletedSeriesMockRecorder
+}
+
+// MockDeletedSeriesMockRecorder is the mock recorder for MockDeletedSeries
+type MockDeletedSeriesMockRecorder struct {
+	mock *MockDeletedSeries
+}
+
+// NewMockDeletedSeries creates a new mock instance
+func NewMockDeletedSeries(ctrl *gomock.Controller) *MockDeletedSeries {
+	mock := &MockDeletedSeries{ctrl: ctrl}
+	mock.recorder = &MockDeletedSeriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDeletedSeries) EXPECT() *MockDeletedSeriesMockRecorder {
+	return m.recorder
+}
+
+// ForEachDeleted mocks base method
+func (m *MockDeletedSeries) ForEachDeleted(r time0.Range, fn func([]byte)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForEachDeleted", r, fn)
+}
+
+// ForEachDeleted indicates an expected call of ForEachDeleted
+func (mr *MockDeletedSeriesMockRecorder) ForEachDeleted(r, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachDeleted", reflect.TypeOf((*MockDeletedSeries)(nil).ForEachDeleted), r, fn)
+}
+
+// DeletedVersion mocks base method
+func (m *MockDeletedSeries) DeletedVersion() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedVersion")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// DeletedVersion indicates an expected call of DeletedVersion
+func (mr *MockDeletedSeriesMockRecorder) DeletedVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedVersion", reflect.TypeOf((*MockDeletedSeries)(nil).DeletedVersion))
+}
@@ -119,6 +119,7 @@ type opts struct {
 	backgroundCompactionPlannerOpts compaction.PlannerOptions
 	postingsListCache               *PostingsListCache
 	readThroughSegmentOptions       ReadThroughSegmentOptions
+	deletedSeries                   DeletedSeries
 }
 
 var undefinedUUIDFn = func() ([]byte, error) { return nil, errIDGenerationDisabled }
@@ -401,3 +402,13 @@ func (o *opts) SetForwardIndexThreshold(value float64) Options {
 func (o *opts) ForwardIndexThreshold() float64 {
 	return o.forwardIndexThreshold
 }
+
+func (o *opts) SetDeletedSeries(value DeletedSeries) Options {
+	opts := *o
+	opts.deletedSeries = value
+	return &opts
+}
+
+func (o *opts) DeletedSeries() DeletedSeries {
+	return o.deletedSeries
+}
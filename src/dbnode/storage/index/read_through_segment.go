@@ -227,6 +227,15 @@ func (s *readThroughSegmentReader) MatchTerm(
 	return pl, err
 }
 
+// MatchRange queries the underlying segment, range queries are not cached
+// since the term range filter cannot be used as a cache key.
+func (s *readThroughSegmentReader) MatchRange(
+	field []byte,
+	r index.TermRange,
+) (postings.List, error) {
+	return s.reader.MatchRange(field, r)
+}
+
 // MatchField returns a cached posting list or queries the underlying
 // segment if their is a cache miss.
 func (s *readThroughSegmentReader) MatchField(field []byte) (postings.List, error) {
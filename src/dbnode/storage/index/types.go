@@ -892,4 +892,23 @@ type Options interface {
 
 	// ForwardIndexProbability returns the threshold for forward writes.
 	ForwardIndexThreshold() float64
+
+	// SetDeletedSeries sets the lookup of series whose data has been deleted.
+	SetDeletedSeries(value DeletedSeries) Options
+
+	// DeletedSeries returns the lookup of series whose data has been deleted.
+	DeletedSeries() DeletedSeries
+}
+
+// DeletedSeries resolves the series that have had their data deleted so that
+// index blocks can exclude them from query and aggregate results.
+type DeletedSeries interface {
+	// ForEachDeleted calls the function with the ID of every series that has
+	// had all of its data within the time range deleted.
+	ForEachDeleted(r xtime.Range, fn func(id []byte))
+
+	// DeletedVersion returns a version of the deleted series that changes
+	// whenever series are deleted or their deletions are dropped, so that
+	// the deleted series can be cached until it does.
+	DeletedVersion() uint64
 }
@@ -29,9 +29,11 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
@@ -56,8 +58,12 @@ import (
 )
 
 var (
-	errNamespaceAlreadyClosed    = errors.New("namespace already closed")
-	errNamespaceIndexingDisabled = errors.New("namespace indexing is disabled")
+	errNamespaceAlreadyClosed      = errors.New("namespace already closed")
+	errNamespaceIndexingDisabled   = errors.New("namespace indexing is disabled")
+	errNamespaceInvalidDeleteRange = errors.New("delete range start must be before end")
+	errNamespaceBackfillUnaligned  = errors.New("backfill block start must be aligned to the block size")
+	errNamespaceBackfillOutOfRange = errors.New("backfill block must be within retention and already flushable")
+	errNamespaceNoCounterEncoders  = errors.New("namespace counter encoding is enabled but there is no counter encoder pool")
 )
 
 type commitLogWriter interface {
@@ -281,6 +287,15 @@ func newDatabaseNamespaceMetrics(scope tally.Scope, samplingRate float64) databa
 	}
 }
 
+// namespaceEncoderPool returns the pool of encoders of a namespace, which use
+// the counter value encoding if it is enabled for the namespace.
+func namespaceEncoderPool(opts Options, nsOpts namespace.Options) encoding.EncoderPool {
+	if nsOpts.CounterEncodingEnabled() && opts.CounterEncoderPool() != nil {
+		return opts.CounterEncoderPool()
+	}
+	return opts.EncoderPool()
+}
+
 func newDatabaseNamespace(
 	metadata namespace.Metadata,
 	shardSet sharding.ShardSet,
@@ -314,23 +329,23 @@ func newDatabaseNamespace(
 	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions()).
 		SetStats(series.NewStats(scope)).
 		SetColdWritesEnabled(nopts.ColdWritesEnabled())
+	if nopts.CounterEncodingEnabled() {
+		// NB: only the encoders differ, iterators detect the value encoding
+		// scheme from the stream so the shared iterator pools can be used.
+		encoderPool := opts.CounterEncoderPool()
+		if encoderPool == nil {
+			return nil, errNamespaceNoCounterEncoders
+		}
+		seriesOpts = seriesOpts.
+			SetEncoderPool(encoderPool).
+			SetDatabaseBlockOptions(seriesOpts.DatabaseBlockOptions().SetEncoderPool(encoderPool))
+	}
 	if err := seriesOpts.Validate(); err != nil {
 		return nil, fmt.Errorf(
 			"unable to create namespace %v, invalid series options: %v",
 			metadata.ID().String(), err)
 	}
 
-	var (
-		index namespaceIndex
-		err   error
-	)
-	if metadata.Options().IndexOptions().Enabled() {
-		index, err = newNamespaceIndex(metadata, shardSet, opts)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	n := &dbNamespace{
 		id:                     id,
 		shutdownCh:             make(chan struct{}),
@@ -346,12 +361,22 @@ func newDatabaseNamespace(
 		log:                    logger,
 		increasingIndex:        increasingIndex,
 		commitLogWriter:        commitLogWriter,
-		reverseIndex:           index,
 		tickWorkers:            tickWorkers,
 		tickWorkersConcurrency: tickWorkersConcurrency,
 		metrics:                newDatabaseNamespaceMetrics(scope, iops.MetricsSamplingRate()),
 	}
 
+	if metadata.Options().IndexOptions().Enabled() {
+		// The index excludes series whose data has been deleted from the
+		// shards of the namespace.
+		indexOpts := opts.IndexOptions().SetDeletedSeries(n)
+		index, err := newNamespaceIndex(metadata, shardSet, opts.SetIndexOptions(indexOpts))
+		if err != nil {
+			return nil, err
+		}
+		n.reverseIndex = index
+	}
+
 	sl, err := opts.SchemaRegistry().RegisterListener(id, n)
 	// Fail to create namespace is schema listener can not be registered successfully.
 	// If proto is disabled, err will always be nil.
@@ -367,6 +392,27 @@ func newDatabaseNamespace(
 	return n, nil
 }
 
+// ForEachDeleted implements index.DeletedSeries.
+func (n *dbNamespace) ForEachDeleted(r xtime.Range, fn func(id []byte)) {
+	for _, shard := range n.GetOwnedShards() {
+		shard.ForEachDeletedSeries(r, fn)
+	}
+}
+
+// DeletedVersion implements index.DeletedSeries. Every change to the
+// tombstones of a shard results in a version greater than that of every
+// other shard, so the greatest version of the owned shards changes whenever
+// any of them do.
+func (n *dbNamespace) DeletedVersion() uint64 {
+	var version uint64
+	for _, shard := range n.GetOwnedShards() {
+		if v := shard.DeletedSeriesVersion(); v > version {
+			version = v
+		}
+	}
+	return version
+}
+
 // SetSchemaHistory implements namespace.SchemaListener.
 func (n *dbNamespace) SetSchemaHistory(value namespace.SchemaHistory) {
 	n.Lock()
@@ -1103,8 +1149,10 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	n.RUnlock()
 
 	// If repair is enabled we still need cold flush regardless of whether cold writes is
-	// enabled since repairs are dependent on the cold flushing logic.
-	if !n.nopts.ColdWritesEnabled() && !n.nopts.RepairEnabled() {
+	// enabled since repairs are dependent on the cold flushing logic. The same goes for
+	// series retention rules since cold flushes rewrite the blocks they expire.
+	hasSeriesRetentionRules := len(n.nopts.RetentionOptions().SeriesRetentionRules()) > 0
+	if !n.nopts.ColdWritesEnabled() && !n.nopts.RepairEnabled() && !hasSeriesRetentionRules {
 		n.metrics.flushColdData.ReportSuccess(n.nowFn().Sub(callStart))
 		return nil
 	}
@@ -1260,6 +1308,104 @@ func (n *dbNamespace) Truncate() (int64, error) {
 	return totalNumSeries, nil
 }
 
+func (n *dbNamespace) DeleteSeries(
+	ctx context.Context,
+	query index.Query,
+	start, end time.Time,
+) (int64, error) {
+	if !start.Before(end) {
+		return 0, xerrors.NewInvalidParamsError(errNamespaceInvalidDeleteRange)
+	}
+
+	res, err := n.QueryIDs(ctx, query, index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Group the series by shard so that each shard persists its tombstones
+	// once, the index then excludes them from the blocks that no longer hold
+	// any of their data.
+	var (
+		entries = res.Results.Map().Iter()
+		shards  = make(map[databaseShard][]ident.ID)
+	)
+	for _, entry := range entries {
+		id := entry.Key()
+		shard, _, err := n.shardFor(id)
+		if err != nil {
+			return 0, err
+		}
+		shards[shard] = append(shards[shard], id)
+	}
+
+	var multiErr xerrors.MultiError
+	for shard, ids := range shards {
+		if err := shard.DeleteSeries(ids, start, end); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	if err := multiErr.FinalError(); err != nil {
+		return 0, err
+	}
+
+	return int64(len(entries)), nil
+}
+
+func (n *dbNamespace) Backfill(
+	ctx context.Context,
+	blockStart time.Time,
+	series []BackfillSeries,
+	flushPersist persist.FlushPreparer,
+) error {
+	var (
+		ropts     = n.nopts.RetentionOptions()
+		blockSize = ropts.BlockSize()
+		now       = n.nowFn()
+	)
+	if !blockStart.Equal(blockStart.Truncate(blockSize)) {
+		return xerrors.NewInvalidParamsError(errNamespaceBackfillUnaligned)
+	}
+	// Blocks that can still be written to are filled by regular writes.
+	if blockStart.Before(retention.FlushTimeStart(ropts, now)) ||
+		blockStart.After(retention.FlushTimeEnd(ropts, now)) {
+		return xerrors.NewInvalidParamsError(errNamespaceBackfillOutOfRange)
+	}
+
+	// Group the series by the shard that owns them, each shard writes its
+	// own fileset volume.
+	var (
+		shards        []databaseShard
+		seriesByShard = make(map[uint32][]BackfillSeries)
+		nsCtx         namespace.Context
+	)
+	for _, s := range series {
+		shard, shardNsCtx, err := n.shardFor(s.ID)
+		if err != nil {
+			return err
+		}
+		nsCtx = shardNsCtx
+		if _, ok := seriesByShard[shard.ID()]; !ok {
+			shards = append(shards, shard)
+		}
+		seriesByShard[shard.ID()] = append(seriesByShard[shard.ID()], s)
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, shard := range shards {
+		// NB: Errors are not wrapped so that invalid params and retryable
+		// errors are surfaced as such to the caller.
+		err := shard.Backfill(blockStart, seriesByShard[shard.ID()], flushPersist, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
 func (n *dbNamespace) Repair(
 	repairer databaseShardRepairer,
 	tr xtime.Range,
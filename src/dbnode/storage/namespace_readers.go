@@ -195,8 +195,32 @@ func (m *namespaceReaderManager) filesetExistsAt(
 		return false, err
 	}
 
-	return m.filesetExistsFn(m.fsOpts.FilePathPrefix(),
-		m.namespace.ID(), shard, blockStart, latestVolume)
+	_, exists, err := m.filePathPrefixAt(shard, blockStart, latestVolume)
+	return exists, err
+}
+
+// filePathPrefixAt returns the file path prefix that a volume of a fileset
+// lives under, which can be the namespace's secondary tier if tiering is
+// enabled, and whether the fileset exists.
+func (m *namespaceReaderManager) filePathPrefixAt(
+	shard uint32,
+	blockStart time.Time,
+	volume int,
+) (string, bool, error) {
+	filePathPrefixes := fs.DataFilePathPrefixes(m.fsOpts.FilePathPrefix(),
+		m.namespace.Options())
+	for _, filePathPrefix := range filePathPrefixes {
+		exists, err := m.filesetExistsFn(filePathPrefix,
+			m.namespace.ID(), shard, blockStart, volume)
+		if err != nil {
+			return "", false, err
+		}
+		if exists {
+			return filePathPrefix, true, nil
+		}
+	}
+
+	return "", false, nil
 }
 
 type cachedReaderForKeyResult struct {
@@ -302,6 +326,17 @@ func (m *namespaceReaderManager) get(
 	// reader or newly allocated, either way need to prepare it)
 	reader := lookup.closedReader
 
+	// NB: only look up where the fileset lives if it can have been moved to
+	// the namespace's secondary tier, otherwise the reader's file path prefix
+	// is used.
+	var filePathPrefix string
+	if m.namespace.Options().TieringOptions().Enabled() {
+		filePathPrefix, _, err = m.filePathPrefixAt(shard, blockStart, latestVolume)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	openOpts := fs.DataReaderOpenOptions{
 		Identifier: fs.FileSetFileIdentifier{
 			Namespace:   m.namespace.ID(),
@@ -309,6 +344,7 @@ func (m *namespaceReaderManager) get(
 			BlockStart:  blockStart,
 			VolumeIndex: latestVolume,
 		},
+		FilePathPrefix: filePathPrefix,
 	}
 	if err := reader.Open(openOpts); err != nil {
 		return nil, err
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
@@ -143,6 +144,22 @@ func TestNamespaceName(t *testing.T) {
 	require.True(t, defaultTestNs1ID.Equal(ns.ID()))
 }
 
+func TestNamespaceEncoderPool(t *testing.T) {
+	var (
+		opts           = DefaultTestOptions()
+		counterEncoder = encoding.NewEncoderPool(nil)
+		nsOpts         = namespace.NewOptions()
+	)
+	assert.Equal(t, opts.EncoderPool(), namespaceEncoderPool(opts, nsOpts))
+
+	// Falls back to the default pool without a counter encoder pool.
+	nsOpts = nsOpts.SetCounterEncodingEnabled(true)
+	assert.Equal(t, opts.EncoderPool(), namespaceEncoderPool(opts, nsOpts))
+
+	opts = opts.SetCounterEncoderPool(counterEncoder)
+	assert.Equal(t, counterEncoder, namespaceEncoderPool(opts, nsOpts))
+}
+
 func TestNamespaceTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -34,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
@@ -142,6 +143,7 @@ type options struct {
 	seriesPool                     series.DatabaseSeriesPool
 	bytesPool                      pool.CheckedBytesPool
 	encoderPool                    encoding.EncoderPool
+	counterEncoderPool             encoding.EncoderPool
 	segmentReaderPool              xio.SegmentReaderPool
 	readerIteratorPool             encoding.ReaderIteratorPool
 	multiReaderIteratorPool        encoding.MultiReaderIteratorPool
@@ -157,6 +159,7 @@ type options struct {
 	schemaReg                      namespace.SchemaRegistry
 	blockLeaseManager              block.LeaseManager
 	memoryTracker                  MemoryTracker
+	backupManager                  backup.Manager
 }
 
 // NewOptions creates a new set of storage options with defaults
@@ -461,6 +464,16 @@ func (o *options) SetEncodingM3TSZPooled() Options {
 	})
 	opts.encoderPool = encoderPool
 
+	// initialize counter encoder pool
+	counterEncoderPool := encoding.NewEncoderPool(opts.poolOpts)
+	counterEncodingOpts := encodingOpts.
+		SetEncoderPool(counterEncoderPool).
+		SetValueEncodingScheme(encoding.CounterValueEncodingScheme)
+	counterEncoderPool.Init(func() encoding.Encoder {
+		return m3tsz.NewEncoder(timeZero, nil, m3tsz.DefaultIntOptimizationEnabled, counterEncodingOpts)
+	})
+	opts.counterEncoderPool = counterEncoderPool
+
 	// initialize single reader iterator pool
 	readerIteratorPool.Init(func(r io.Reader, descr namespace.SchemaDescr) encoding.ReaderIterator {
 		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
@@ -594,6 +607,16 @@ func (o *options) EncoderPool() encoding.EncoderPool {
 	return o.encoderPool
 }
 
+func (o *options) SetCounterEncoderPool(value encoding.EncoderPool) Options {
+	opts := *o
+	opts.counterEncoderPool = value
+	return &opts
+}
+
+func (o *options) CounterEncoderPool() encoding.EncoderPool {
+	return o.counterEncoderPool
+}
+
 func (o *options) SetSegmentReaderPool(value xio.SegmentReaderPool) Options {
 	opts := *o
 	opts.segmentReaderPool = value
@@ -744,3 +767,13 @@ func (o *options) SetMemoryTracker(memTracker MemoryTracker) Options {
 func (o *options) MemoryTracker() MemoryTracker {
 	return o.memoryTracker
 }
+
+func (o *options) SetBackupManager(value backup.Manager) Options {
+	opts := *o
+	opts.backupManager = value
+	return &opts
+}
+
+func (o *options) BackupManager() backup.Manager {
+	return o.backupManager
+}
@@ -77,6 +77,7 @@ var (
 	errFlushStateIsNotInitialized          = errors.New("shard flush state is not initialized")
 	errFlushStateAlreadyInitialized        = errors.New("shard flush state is already initialized")
 	errTriedToLoadNilSeries                = errors.New("tried to load nil series into shard")
+	errShardBackfillBlockNotFlushed        = errors.New("shard block must be flushed before it can be backfilled")
 
 	// ErrDatabaseLoadLimitHit is the error returned when the database load limit
 	// is hit or exceeded.
@@ -160,6 +161,8 @@ type dbShard struct {
 	increasingIndex          increasingIndex
 	seriesPool               series.DatabaseSeriesPool
 	reverseIndex             namespaceIndex
+	tombstones               *shardTombstones
+	seriesExpiry             *shardSeriesExpiry
 	insertQueue              *dbShardInsertQueue
 	lookup                   *shardMap
 	list                     *list.List
@@ -265,6 +268,7 @@ func newDatabaseShard(
 		increasingIndex:      increasingIndex,
 		seriesPool:           opts.DatabaseSeriesPool(),
 		reverseIndex:         reverseIndex,
+		tombstones:           newShardTombstones(namespaceMetadata.Options().RetentionOptions().BlockSize()),
 		lookup:               newShardMap(shardMapOptions{}),
 		list:                 list.New(),
 		newMergerFn:          fs.NewMerger,
@@ -284,6 +288,20 @@ func newDatabaseShard(
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
 		s.nowFn, scope)
 
+	seriesExpiry, err := newShardSeriesExpiry(
+		namespaceMetadata.Options().RetentionOptions(), s.nowFn)
+	if err != nil {
+		// The rules are validated along with the namespace options.
+		instrument.EmitAndLogInvariantViolation(opts.InstrumentOptions(), func(l *zap.Logger) {
+			l.With(
+				zap.String("namespace", namespaceMetadata.ID().String()),
+				zap.Uint32("shard", shard),
+				zap.Error(err),
+			).Error("invalid series retention rules, series retention rules not enforced")
+		})
+	}
+	s.seriesExpiry = seriesExpiry
+
 	registerRuntimeOptionsListener := func(listener runtime.OptionsListener) {
 		elem := opts.RuntimeOptionsManager().RegisterListener(listener)
 		s.runtimeOptsListenClosers = append(s.runtimeOptsListenClosers, elem)
@@ -1041,6 +1059,29 @@ func (s *dbShard) ReadEncoded(
 	id ident.ID,
 	start, end time.Time,
 	nsCtx namespace.Context,
+) ([][]xio.BlockReader, error) {
+	queryRange := xtime.Range{Start: start, End: end}
+	deleted := s.tombstones.deletedRanges(id)
+	if !deleted.Overlaps(queryRange) {
+		return s.readEncoded(ctx, id, start, end, nsCtx)
+	}
+	if xtime.NewRanges(queryRange).RemoveRanges(deleted).IsEmpty() {
+		// All the data requested has been deleted.
+		return nil, nil
+	}
+
+	blocks, err := s.readEncoded(ctx, id, start, end, nsCtx)
+	if err != nil {
+		return nil, err
+	}
+	return filterDeleted(ctx, blocks, deleted, s.opts, s.namespace.Options(), nsCtx)
+}
+
+func (s *dbShard) readEncoded(
+	ctx context.Context,
+	id ident.ID,
+	start, end time.Time,
+	nsCtx namespace.Context,
 ) ([][]xio.BlockReader, error) {
 	s.RLock()
 	entry, _, err := s.lookupEntryWithLock(id)
@@ -1509,6 +1550,41 @@ func (s *dbShard) FetchBlocks(
 	id ident.ID,
 	starts []time.Time,
 	nsCtx namespace.Context,
+) ([]block.FetchBlockResult, error) {
+	results, err := s.fetchBlocks(ctx, id, starts, nsCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocks are fetched by peers bootstrapping the shard so the deleted
+	// data must be left out for it to stay deleted on the peers.
+	deleted := s.tombstones.deletedRanges(id)
+	if deleted.IsEmpty() {
+		return results, nil
+	}
+	for i := range results {
+		if results[i].Err != nil || len(results[i].Blocks) == 0 {
+			continue
+		}
+		filtered, err := filterDeleted(ctx, [][]xio.BlockReader{results[i].Blocks},
+			deleted, s.opts, s.namespace.Options(), nsCtx)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Blocks = nil
+		if len(filtered) > 0 {
+			results[i].Blocks = filtered[0]
+		}
+	}
+	return results, nil
+}
+
+func (s *dbShard) fetchBlocks(
+	ctx context.Context,
+	id ident.ID,
+	starts []time.Time,
+	nsCtx namespace.Context,
 ) ([]block.FetchBlockResult, error) {
 	s.RLock()
 	entry, _, err := s.lookupEntryWithLock(id)
@@ -1561,6 +1637,46 @@ func (s *dbShard) FetchBlocksForColdFlush(
 	return entry.Series.FetchBlocksForColdFlush(ctx, start, version, nsCtx)
 }
 
+func (s *dbShard) DeleteSeries(ids []ident.ID, start, end time.Time) error {
+	r := xtime.Range{Start: start, End: end}
+	for _, id := range ids {
+		s.tombstones.add(id, r)
+	}
+	// Persist before returning so that an acknowledged deletion is not
+	// undone by a restart.
+	return s.persistTombstones()
+}
+
+func (s *dbShard) ForEachDeletedSeries(r xtime.Range, fn func(id []byte)) {
+	s.tombstones.forEachDeleted(r, fn)
+}
+
+func (s *dbShard) DeletedSeriesVersion() uint64 {
+	return s.tombstones.currentVersion()
+}
+
+// loadTombstones adds the tombstones persisted for the shard to the ones
+// held in memory.
+func (s *dbShard) loadTombstones() error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	tombstones, err := fs.ReadShardTombstones(fsOpts.FilePathPrefix(),
+		s.namespace.ID(), s.ID())
+	if err != nil {
+		return err
+	}
+	s.tombstones.load(tombstones)
+	return nil
+}
+
+// persistTombstones writes the tombstones of the shard to disk.
+func (s *dbShard) persistTombstones() error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	return s.tombstones.persist(func(tombstones fs.ShardTombstones) error {
+		return fs.WriteShardTombstones(fsOpts.FilePathPrefix(), s.namespace.ID(),
+			s.ID(), tombstones, fsOpts.NewFileMode(), fsOpts.NewDirectoryMode())
+	})
+}
+
 func (s *dbShard) fetchActiveBlocksMetadata(
 	ctx context.Context,
 	start, end time.Time,
@@ -1856,7 +1972,7 @@ func (s *dbShard) initializeFlushStates() {
 
 func (s *dbShard) UpdateFlushStates() {
 	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
-	readInfoFilesResults := fs.ReadInfoFiles(fsOpts.FilePathPrefix(), s.namespace.ID(), s.shard,
+	readInfoFilesResults := fs.ReadTieredInfoFiles(s.dataFilePathPrefixes(), s.namespace.ID(), s.shard,
 		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions())
 
 	for _, result := range readInfoFilesResults {
@@ -1905,6 +2021,12 @@ func (s *dbShard) Bootstrap() error {
 
 	multiErr := xerrors.NewMultiError()
 
+	// Load the tombstones before any data is served so that deleted data
+	// is not returned again after a restart.
+	if err := s.loadTombstones(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
 	// Initialize the flush states if we haven't called prepare bootstrap.
 	if err := s.PrepareBootstrap(); err != nil {
 		multiErr = multiErr.Add(err)
@@ -2163,7 +2285,38 @@ func (s *dbShard) ColdFlush(
 		return loopErr
 	}
 
-	if dirtySeries.Len() == 0 {
+	// Blocks that have had series data deleted need to be rewritten even if
+	// they have no cold writes so that the deleted data is dropped from disk.
+	tombstonedBlockStarts := s.tombstones.unmergedBlockStarts()
+	for blockStart := range tombstonedBlockStarts {
+		hasWarmFlushed, err := s.hasWarmFlushed(blockStart.ToTime())
+		if err != nil {
+			return err
+		}
+		if !hasWarmFlushed {
+			// Data still in memory is filtered when it is warm flushed and
+			// cold flushed thereafter.
+			delete(tombstonedBlockStarts, blockStart)
+			continue
+		}
+		if dirtySeriesToWrite[blockStart] == nil {
+			dirtySeriesToWrite[blockStart] = newIDList(idElementPool)
+		}
+	}
+
+	// Similarly blocks that series retention rules have expired since their
+	// latest volume was written need to be rewritten to drop expired series.
+	expiredBlockStarts, err := s.seriesExpiredBlockStarts(blockStatesSnapshot.Snapshot)
+	if err != nil {
+		return err
+	}
+	for _, blockStart := range expiredBlockStarts {
+		if dirtySeriesToWrite[blockStart] == nil {
+			dirtySeriesToWrite[blockStart] = newIDList(idElementPool)
+		}
+	}
+
+	if dirtySeries.Len() == 0 && len(tombstonedBlockStarts) == 0 && len(expiredBlockStarts) == 0 {
 		// Early exit if there is nothing dirty to merge. dirtySeriesToWrite
 		// may be non-empty when dirtySeries is empty because we purposely
 		// leave empty seriesLists in the dirtySeriesToWrite map to avoid having
@@ -2171,10 +2324,14 @@ func (s *dbShard) ColdFlush(
 		return nil
 	}
 
+	nsOpts := s.namespace.Options()
 	merger := s.newMergerFn(resources.fsReader, s.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
 		s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
-		s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(), s.namespace.Options())
-	mergeWithMem := s.newFSMergeWithMemFn(s, s, dirtySeries, dirtySeriesToWrite)
+		s.opts.IdentifierPool(), namespaceEncoderPool(s.opts, nsOpts), s.opts.ContextPool(), nsOpts,
+		s.opts.CommitLogOptions().FilesystemOptions())
+	mergeWithMem := s.newFSMergeWithMemFn(s, s, s.tombstones, s.seriesExpiry,
+		dirtySeries, dirtySeriesToWrite)
+	tombstonesMerged := false
 	// Loop through each block that we know has ColdWrites. Since each block
 	// has its own fileset, if we encounter an error while trying to persist
 	// a block, we continue to try persisting other blocks.
@@ -2193,57 +2350,288 @@ func (s *dbShard) ColdFlush(
 			VolumeIndex: coldVersion,
 		}
 
+		// Take the digest of the expired rules before merging so that rules
+		// expiring mid merge are applied by the next cold flush.
+		rulesDigest, hasExpiredRules := s.seriesExpiry.expiredRulesDigest(blockStart)
 		nextVersion := coldVersion + 1
 		err = merger.Merge(fsID, mergeWithMem, nextVersion, flushPreparer, nsCtx)
 		if err != nil {
 			multiErr = multiErr.Add(err)
 			continue
 		}
+		if version, ok := tombstonedBlockStarts[blockStart]; ok {
+			if s.tombstones.markMerged(blockStart, version) {
+				tombstonesMerged = true
+			}
+		}
+		if hasExpiredRules {
+			err := s.writeSeriesRetentionMarker(startTime, nextVersion, rulesDigest)
+			multiErr = multiErr.Add(err)
+		}
 
-		// After writing the full block successfully update the ColdVersionFlushed number. This will
-		// allow the SeekerManager to open a lease on the latest version of the fileset files because
-		// the BlockLeaseVerifier will check the ColdVersionFlushed value, but the buffer only looks at
-		// ColdVersionRetrievable so a concurrent tick will not yet cause the blocks in memory to be
-		// evicted (which is the desired behavior because we haven't updated the open leases yet which
-		// means the newly written data is not available for querying via the SeekerManager yet.)
-		s.setFlushStateColdVersionFlushed(startTime, nextVersion)
-
-		// Notify all block leasers that a new volume for the namespace/shard/blockstart
-		// has been created. This will block until all leasers have relinquished their
-		// leases.
-		_, err = s.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
-			Namespace:  s.namespace.ID(),
-			Shard:      s.ID(),
-			BlockStart: startTime,
-		}, block.LeaseState{Volume: nextVersion})
-		// After writing the full block successfully **and** propagating the new lease to the
-		// BlockLeaseManager, update the ColdVersionRetrievable in the flush state. Once this function
-		// completes concurrent ticks will be able to evict the data from memory that was just flushed
-		// (which is now safe to do since the SeekerManager has been notified of the presence of new
-		// files).
-		//
-		// NB(rartoul): Ideally the ColdVersionRetrievable would only be updated if the call to UpdateOpenLeases
-		// succeeded, but that would allow the ColdVersionRetrievable and ColdVersionFlushed numbers to drift
-		// which would increase the complexity of the code to address a situation that is probably not
-		// recoverable (failure to UpdateOpenLeases is an invariant violated error).
-		s.setFlushStateColdVersionRetrievable(startTime, nextVersion)
-		if err != nil {
-			instrument.EmitAndLogInvariantViolation(s.opts.InstrumentOptions(), func(l *zap.Logger) {
-				l.With(
-					zap.String("namespace", s.namespace.ID().String()),
-					zap.Uint32("shard", s.ID()),
-					zap.Time("blockStart", startTime),
-					zap.Int("nextVersion", nextVersion),
-				).Error("failed to update open leases after updating flush state cold version")
-			})
+		if err := s.markColdVolumeFlushed(startTime, nextVersion); err != nil {
 			multiErr = multiErr.Add(err)
-			continue
 		}
 	}
 
+	if tombstonesMerged {
+		multiErr = multiErr.Add(s.persistTombstones())
+	}
+
 	return multiErr.FinalError()
 }
 
+// seriesExpiredBlockStarts returns the warm flushed block starts whose latest
+// volume was not written with all the series retention rules that have
+// expired the block applied.
+func (s *dbShard) seriesExpiredBlockStarts(
+	blockStates map[xtime.UnixNano]series.BlockState,
+) ([]xtime.UnixNano, error) {
+	if !s.seriesExpiry.enabled() {
+		return nil, nil
+	}
+
+	var expired []xtime.UnixNano
+	for blockStart, state := range blockStates {
+		if !state.WarmRetrievable {
+			continue
+		}
+		rulesDigest, ok := s.seriesExpiry.expiredRulesDigest(blockStart)
+		if !ok {
+			continue
+		}
+		applied, ok := s.seriesExpiry.appliedDigest(blockStart)
+		if !ok {
+			var err error
+			applied, ok, err = s.readSeriesRetentionMarker(blockStart.ToTime())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				s.seriesExpiry.markApplied(blockStart, applied)
+			}
+		}
+		if ok && applied == rulesDigest {
+			continue
+		}
+		expired = append(expired, blockStart)
+	}
+	return expired, nil
+}
+
+// readSeriesRetentionMarker returns the digest of the series retention rules
+// applied to the latest volume of the block, looking in every data path
+// since the volume may have been moved to the tiered path.
+func (s *dbShard) readSeriesRetentionMarker(blockStart time.Time) (uint32, bool, error) {
+	coldVersion, err := s.RetrievableBlockColdVersion(blockStart)
+	if err != nil {
+		return 0, false, err
+	}
+	id := fs.FileSetFileIdentifier{
+		Namespace:   s.namespace.ID(),
+		Shard:       s.ID(),
+		BlockStart:  blockStart,
+		VolumeIndex: coldVersion,
+	}
+	for _, filePathPrefix := range s.dataFilePathPrefixes() {
+		rulesDigest, ok, err := fs.ReadSeriesRetentionMarker(filePathPrefix, id)
+		if err != nil || ok {
+			return rulesDigest, ok, err
+		}
+	}
+	return 0, false, nil
+}
+
+// writeSeriesRetentionMarker records the digest of the series retention
+// rules that a newly written volume of the block was merged with.
+func (s *dbShard) writeSeriesRetentionMarker(
+	blockStart time.Time,
+	volumeIndex int,
+	rulesDigest uint32,
+) error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	id := fs.FileSetFileIdentifier{
+		Namespace:   s.namespace.ID(),
+		Shard:       s.ID(),
+		BlockStart:  blockStart,
+		VolumeIndex: volumeIndex,
+	}
+	err := fs.WriteSeriesRetentionMarker(fsOpts.FilePathPrefix(), id,
+		rulesDigest, fsOpts.NewFileMode())
+	if err != nil {
+		return err
+	}
+	s.seriesExpiry.markApplied(xtime.ToUnixNano(blockStart), rulesDigest)
+	return nil
+}
+
+// markColdVolumeFlushed makes a newly written cold volume of the block
+// visible to readers and allows the data it contains to be evicted from
+// memory.
+func (s *dbShard) markColdVolumeFlushed(startTime time.Time, nextVersion int) error {
+	// After writing the full block successfully update the ColdVersionFlushed number. This will
+	// allow the SeekerManager to open a lease on the latest version of the fileset files because
+	// the BlockLeaseVerifier will check the ColdVersionFlushed value, but the buffer only looks at
+	// ColdVersionRetrievable so a concurrent tick will not yet cause the blocks in memory to be
+	// evicted (which is the desired behavior because we haven't updated the open leases yet which
+	// means the newly written data is not available for querying via the SeekerManager yet.)
+	s.setFlushStateColdVersionFlushed(startTime, nextVersion)
+
+	// Notify all block leasers that a new volume for the namespace/shard/blockstart
+	// has been created. This will block until all leasers have relinquished their
+	// leases.
+	_, err := s.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
+		Namespace:  s.namespace.ID(),
+		Shard:      s.ID(),
+		BlockStart: startTime,
+	}, block.LeaseState{Volume: nextVersion})
+	// After writing the full block successfully **and** propagating the new lease to the
+	// BlockLeaseManager, update the ColdVersionRetrievable in the flush state. Once this function
+	// completes concurrent ticks will be able to evict the data from memory that was just flushed
+	// (which is now safe to do since the SeekerManager has been notified of the presence of new
+	// files).
+	//
+	// NB(rartoul): Ideally the ColdVersionRetrievable would only be updated if the call to UpdateOpenLeases
+	// succeeded, but that would allow the ColdVersionRetrievable and ColdVersionFlushed numbers to drift
+	// which would increase the complexity of the code to address a situation that is probably not
+	// recoverable (failure to UpdateOpenLeases is an invariant violated error).
+	s.setFlushStateColdVersionRetrievable(startTime, nextVersion)
+	if err != nil {
+		instrument.EmitAndLogInvariantViolation(s.opts.InstrumentOptions(), func(l *zap.Logger) {
+			l.With(
+				zap.String("namespace", s.namespace.ID().String()),
+				zap.Uint32("shard", s.ID()),
+				zap.Time("blockStart", startTime),
+				zap.Int("nextVersion", nextVersion),
+			).Error("failed to update open leases after updating flush state cold version")
+		})
+		return err
+	}
+
+	return nil
+}
+
+func (s *dbShard) Backfill(
+	blockStart time.Time,
+	series []BackfillSeries,
+	flushPreparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+) error {
+	if !s.IsBootstrapped() {
+		return xerrors.NewRetryableError(errShardIsNotBootstrapped)
+	}
+
+	// Backfilled data is merged with the latest volume of the block, which
+	// only exists once the block has been warm flushed. Blocks that were never
+	// written to are not warm flushed at all so the error is not retryable.
+	hasWarmFlushed, err := s.hasWarmFlushed(blockStart)
+	if err != nil {
+		return err
+	}
+	if !hasWarmFlushed {
+		return xerrors.NewInvalidParamsError(errShardBackfillBlockNotFlushed)
+	}
+
+	var (
+		blockSize = s.namespace.Options().RetentionOptions().BlockSize()
+		bopts     = s.opts.DatabaseBlockOptions()
+	)
+	mergeWith, err := newFSMergeWithBackfill(blockStart, blockSize, series,
+		namespaceEncoderPool(s.opts, s.namespace.Options()), bopts.DatabaseBlockAllocSize(), s.tombstones,
+		s.seriesExpiry, nsCtx)
+	if err != nil {
+		return err
+	}
+	defer mergeWith.close()
+
+	if len(mergeWith.series) == 0 {
+		return nil
+	}
+
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	fsReader, err := fs.NewReader(s.opts.BytesPool(), fsOpts)
+	if err != nil {
+		return err
+	}
+
+	coldVersion, err := s.RetrievableBlockColdVersion(blockStart)
+	if err != nil {
+		return err
+	}
+
+	var (
+		tombstonedBlockStarts = s.tombstones.unmergedBlockStarts()
+		unixBlockStart        = xtime.ToUnixNano(blockStart)
+		nextVersion           = coldVersion + 1
+		fsID                  = fs.FileSetFileIdentifier{
+			Namespace:   s.namespace.ID(),
+			Shard:       s.ID(),
+			BlockStart:  blockStart,
+			VolumeIndex: coldVersion,
+		}
+	)
+	merger := s.newMergerFn(fsReader, bopts.DatabaseBlockAllocSize(),
+		s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
+		s.opts.IdentifierPool(), namespaceEncoderPool(s.opts, s.namespace.Options()), s.opts.ContextPool(),
+		s.namespace.Options(), fsOpts)
+	rulesDigest, hasExpiredRules := s.seriesExpiry.expiredRulesDigest(unixBlockStart)
+	if err := merger.Merge(fsID, mergeWith, nextVersion, flushPreparer, nsCtx); err != nil {
+		return err
+	}
+	if version, ok := tombstonedBlockStarts[unixBlockStart]; ok {
+		if s.tombstones.markMerged(unixBlockStart, version) {
+			if err := s.persistTombstones(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.markColdVolumeFlushed(blockStart, nextVersion); err != nil {
+		return err
+	}
+	if hasExpiredRules {
+		if err := s.writeSeriesRetentionMarker(blockStart, nextVersion, rulesDigest); err != nil {
+			return err
+		}
+	}
+	return s.indexBackfilledSeries(series)
+}
+
+// indexBackfilledSeries inserts the tagged series of a backfill into the
+// reverse index for each index block their datapoints fall into, the same
+// way tagged cold writes are indexed.
+func (s *dbShard) indexBackfilledSeries(series []BackfillSeries) error {
+	if s.reverseIndex == nil {
+		return nil
+	}
+
+	for _, elem := range series {
+		if len(elem.Datapoints) == 0 || len(elem.Tags.Values()) == 0 {
+			continue
+		}
+
+		entry, err := s.writableSeries(elem.ID, ident.NewTagsIterator(elem.Tags))
+		if err != nil {
+			return err
+		}
+		for _, dp := range elem.Datapoints {
+			if !entry.NeedsIndexUpdate(s.reverseIndex.BlockStartForWriteTime(dp.Timestamp)) {
+				continue
+			}
+			if err = s.insertSeriesForIndexingAsyncBatched(entry, dp.Timestamp, false); err != nil {
+				break
+			}
+		}
+		// Release the reference taken on the entry by writableSeries.
+		entry.DecrementReaderWriterCount()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *dbShard) Snapshot(
 	blockStart time.Time,
 	snapshotTime time.Time,
@@ -2399,22 +2787,35 @@ func (s *dbShard) removeAnyFlushStatesTooEarly(startTime time.Time) {
 }
 
 func (s *dbShard) CleanupExpiredFileSets(earliestToRetain time.Time) error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	expired, err := s.filesetPathsBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
-	if err != nil {
-		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
-			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	if s.tombstones.removeBefore(earliestToRetain) {
+		if err := s.persistTombstones(); err != nil {
+			return err
+		}
+	}
+	s.seriesExpiry.removeBefore(earliestToRetain)
+
+	var expired []string
+	for _, filePathPrefix := range s.dataFilePathPrefixes() {
+		paths, err := s.filesetPathsBeforeFn(filePathPrefix, s.namespace.ID(), s.ID(), earliestToRetain)
+		if err != nil {
+			return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+				filePathPrefix, s.namespace.ID(), s.ID(), err)
+		}
+		expired = append(expired, paths...)
 	}
 
 	return s.deleteFilesFn(expired)
 }
 
 func (s *dbShard) CleanupCompactedFileSets() error {
-	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
-	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
-	if err != nil {
-		return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
-			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	var filesets fs.FileSetFilesSlice
+	for _, filePathPrefix := range s.dataFilePathPrefixes() {
+		matched, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+		if err != nil {
+			return fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+				filePathPrefix, s.namespace.ID(), s.ID(), err)
+		}
+		filesets = append(filesets, matched...)
 	}
 
 	// Get a snapshot of all states here to prevent constantly getting/releasing
@@ -2438,6 +2839,14 @@ func (s *dbShard) CleanupCompactedFileSets() error {
 	return s.deleteFilesFn(toDelete.Filepaths())
 }
 
+// dataFilePathPrefixes returns the file path prefixes the data filesets of
+// the shard can live under, which includes the namespace's secondary tier if
+// tiering is enabled.
+func (s *dbShard) dataFilePathPrefixes() []string {
+	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	return fs.DataFilePathPrefixes(filePathPrefix, s.namespace.Options())
+}
+
 func (s *dbShard) Repair(
 	ctx context.Context,
 	nsCtx namespace.Context,
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -105,6 +106,48 @@ func TestShardInsertNamespaceIndex(t *testing.T) {
 	require.Equal(t, []byte("value"), indexWrites[0].Fields[0].Value)
 }
 
+func TestShardBackfillInsertsNamespaceIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		opts          = DefaultTestOptions()
+		blockSize     = opts.SeriesOptions().RetentionOptions().BlockSize()
+		t0            = time.Now().Truncate(blockSize).Add(-10 * blockSize)
+		idxBlockStart = xtime.ToUnixNano(t0)
+		indexWrites   []doc.Document
+	)
+	idx := NewMocknamespaceIndex(ctrl)
+	idx.EXPECT().BlockStartForWriteTime(gomock.Any()).Return(idxBlockStart).AnyTimes()
+	idx.EXPECT().WriteBatch(gomock.Any()).Do(
+		func(batch *index.WriteBatch) {
+			indexWrites = append(indexWrites, batch.PendingDocs()...)
+			for i, e := range batch.PendingEntries() {
+				e.OnIndexSeries.OnIndexSuccess(idxBlockStart)
+				e.OnIndexSeries.OnIndexFinalize(idxBlockStart)
+				batch.PendingEntries()[i].OnIndexSeries = nil
+			}
+		}).Return(nil).AnyTimes()
+
+	shard := testDatabaseShardWithIndexFn(t, opts, idx)
+	shard.newMergerFn = newMergerTestFn
+	defer shard.Close()
+	require.NoError(t, shard.Bootstrap())
+	shard.markWarmFlushStateSuccess(t0)
+
+	untagged := newTestBackfillSeries("bar", t0, 4)
+	untagged.Tags = ident.Tags{}
+	series := []BackfillSeries{newTestBackfillSeries("foo", t0, 1, 2, 3), untagged}
+	require.NoError(t, shard.Backfill(t0, series,
+		persist.NewMockFlushPreparer(ctrl), namespace.Context{}))
+
+	// Each tagged series is indexed once per index block.
+	require.Len(t, indexWrites, 1)
+	require.Equal(t, []byte("foo"), indexWrites[0].ID)
+	require.Equal(t, []byte("name"), indexWrites[0].Fields[0].Name)
+	require.Equal(t, []byte("foo"), indexWrites[0].Fields[0].Value)
+}
+
 func TestShardAsyncInsertNamespaceIndex(t *testing.T) {
 	defer leaktest.CheckTimeout(t, 2*time.Second)()
 
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// shardSeriesExpiry enforces the series retention rules of a namespace on
+// the filesets of a shard. The data of a series in a block is treated as
+// deleted once the block has fallen out of the retention period of the first
+// rule matching the series tags. Cold flushes rewrite the blocks that rules
+// have expired and record the digest of the applied rules in a marker beside
+// the new volume so that blocks are only rewritten once per rule.
+type shardSeriesExpiry struct {
+	sync.Mutex
+	rules     []retention.SeriesRetentionRule
+	matcher   retention.SeriesRetentionMatcher
+	blockSize time.Duration
+	nowFn     clock.NowFn
+	// applied caches the digest of the rules applied to the latest volume of
+	// each block as read from or written to its marker.
+	applied map[xtime.UnixNano]uint32
+}
+
+func newShardSeriesExpiry(
+	ropts retention.Options,
+	nowFn clock.NowFn,
+) (*shardSeriesExpiry, error) {
+	rules := ropts.SeriesRetentionRules()
+	matcher, err := retention.NewSeriesRetentionMatcher(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &shardSeriesExpiry{
+		rules:     rules,
+		matcher:   matcher,
+		blockSize: ropts.BlockSize(),
+		nowFn:     nowFn,
+		applied:   make(map[xtime.UnixNano]uint32),
+	}, nil
+}
+
+// enabled returns whether there are any series retention rules to enforce.
+func (e *shardSeriesExpiry) enabled() bool {
+	return e != nil && len(e.rules) > 0
+}
+
+// deletedRanges returns the whole block if it has expired for the series
+// with the given tags, otherwise no ranges.
+func (e *shardSeriesExpiry) deletedRanges(
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) xtime.Ranges {
+	if !e.enabled() {
+		return xtime.Ranges{}
+	}
+
+	period, ok := e.matcher.RetentionPeriod(ident.NewTagsIterator(tags))
+	if !ok {
+		return xtime.Ranges{}
+	}
+	var (
+		start = blockStart.ToTime()
+		end   = start.Add(e.blockSize)
+	)
+	if end.Add(period).After(e.nowFn()) {
+		return xtime.Ranges{}
+	}
+	return xtime.NewRanges(xtime.Range{Start: start, End: end})
+}
+
+// expiredRulesDigest returns the digest of the rules that have expired the
+// block, and false if no rule has expired it yet.
+func (e *shardSeriesExpiry) expiredRulesDigest(blockStart xtime.UnixNano) (uint32, bool) {
+	if !e.enabled() {
+		return 0, false
+	}
+
+	var (
+		end     = blockStart.ToTime().Add(e.blockSize)
+		now     = e.nowFn()
+		buf     bytes.Buffer
+		expired bool
+	)
+	for _, rule := range e.rules {
+		if end.Add(rule.Period).After(now) {
+			continue
+		}
+		buf.WriteString(rule.String())
+		buf.WriteByte('\n')
+		expired = true
+	}
+	if !expired {
+		return 0, false
+	}
+	return digest.Checksum(buf.Bytes()), true
+}
+
+// appliedDigest returns the cached digest of the rules applied to the latest
+// volume of the block, if known.
+func (e *shardSeriesExpiry) appliedDigest(blockStart xtime.UnixNano) (uint32, bool) {
+	e.Lock()
+	rulesDigest, ok := e.applied[blockStart]
+	e.Unlock()
+	return rulesDigest, ok
+}
+
+// markApplied caches the digest of the rules applied to the latest volume of
+// the block.
+func (e *shardSeriesExpiry) markApplied(blockStart xtime.UnixNano, rulesDigest uint32) {
+	e.Lock()
+	e.applied[blockStart] = rulesDigest
+	e.Unlock()
+}
+
+// removeBefore drops the cached digests of blocks that have fallen out of
+// retention.
+func (e *shardSeriesExpiry) removeBefore(earliest time.Time) {
+	if !e.enabled() {
+		return
+	}
+
+	e.Lock()
+	for blockStart := range e.applied {
+		if blockStart.ToTime().Add(e.blockSize).Before(earliest) {
+			delete(e.applied, blockStart)
+		}
+	}
+	e.Unlock()
+}
+
+// seriesDeletedRanges returns the time ranges of the series data in the
+// block that have either been deleted or have expired.
+func seriesDeletedRanges(
+	tombstones *shardTombstones,
+	expiry *shardSeriesExpiry,
+	seriesID ident.ID,
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) xtime.Ranges {
+	deleted := tombstones.deletedRanges(seriesID)
+	expired := expiry.deletedRanges(tags, blockStart)
+	if expired.IsEmpty() {
+		return deleted
+	}
+	return deleted.AddRanges(expired)
+}
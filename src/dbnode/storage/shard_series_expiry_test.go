@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func testSeriesRetentionOpts() retention.Options {
+	return defaultTestRetentionOpts.SetSeriesRetentionRules([]retention.SeriesRetentionRule{
+		{Filter: "env:dev", Period: 4 * time.Hour},
+		{Filter: "env:staging", Period: 8 * time.Hour},
+	})
+}
+
+func TestShardSeriesExpiryDeletedRanges(t *testing.T) {
+	now := time.Now().Truncate(2 * time.Hour)
+	expiry, err := newShardSeriesExpiry(testSeriesRetentionOpts(), func() time.Time {
+		return now
+	})
+	require.NoError(t, err)
+	require.True(t, expiry.enabled())
+
+	var (
+		dev        = ident.NewTags(ident.StringTag("env", "dev"))
+		prod       = ident.NewTags(ident.StringTag("env", "prod"))
+		expired    = now.Add(-6 * time.Hour)
+		notExpired = now.Add(-4 * time.Hour)
+	)
+	deleted := expiry.deletedRanges(dev, xtime.ToUnixNano(expired))
+	require.Equal(t, xtime.NewRanges(xtime.Range{
+		Start: expired,
+		End:   expired.Add(2 * time.Hour),
+	}).String(), deleted.String())
+	require.True(t, expiry.deletedRanges(dev, xtime.ToUnixNano(notExpired)).IsEmpty())
+	require.True(t, expiry.deletedRanges(prod, xtime.ToUnixNano(expired)).IsEmpty())
+
+	// A nil expiry never expires anything.
+	var disabled *shardSeriesExpiry
+	require.False(t, disabled.enabled())
+	require.True(t, disabled.deletedRanges(dev, xtime.ToUnixNano(expired)).IsEmpty())
+}
+
+func TestShardSeriesExpiryExpiredRulesDigest(t *testing.T) {
+	now := time.Now().Truncate(2 * time.Hour)
+	expiry, err := newShardSeriesExpiry(testSeriesRetentionOpts(), func() time.Time {
+		return now
+	})
+	require.NoError(t, err)
+
+	_, ok := expiry.expiredRulesDigest(xtime.ToUnixNano(now.Add(-4 * time.Hour)))
+	require.False(t, ok)
+
+	// Blocks expired by different sets of rules have different digests.
+	devDigest, ok := expiry.expiredRulesDigest(xtime.ToUnixNano(now.Add(-6 * time.Hour)))
+	require.True(t, ok)
+	allDigest, ok := expiry.expiredRulesDigest(xtime.ToUnixNano(now.Add(-10 * time.Hour)))
+	require.True(t, ok)
+	require.NotEqual(t, devDigest, allDigest)
+
+	otherDevDigest, ok := expiry.expiredRulesDigest(xtime.ToUnixNano(now.Add(-8 * time.Hour)))
+	require.True(t, ok)
+	require.Equal(t, devDigest, otherDevDigest)
+
+	expiry.markApplied(xtime.ToUnixNano(now.Add(-10*time.Hour)), allDigest)
+	applied, ok := expiry.appliedDigest(xtime.ToUnixNano(now.Add(-10 * time.Hour)))
+	require.True(t, ok)
+	require.Equal(t, allDigest, applied)
+
+	expiry.removeBefore(now)
+	_, ok = expiry.appliedDigest(xtime.ToUnixNano(now.Add(-10 * time.Hour)))
+	require.False(t, ok)
+}
+
+func TestShardColdFlushSeriesRetentionExpiredBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	nowFn := func() time.Time {
+		return now
+	}
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir)
+	opts = opts.
+		SetClockOptions(opts.ClockOptions().SetNowFn(nowFn)).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts))
+
+	ropts := testSeriesRetentionOpts()
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID,
+		defaultTestNs1Opts.SetRetentionOptions(ropts))
+	require.NoError(t, err)
+	seriesOpts := NewSeriesOptionsFromOptions(opts, ropts).
+		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
+		SetBufferBucketPool(series.NewBufferBucketPool(nil))
+	shard := newDatabaseShard(metadata, 0, nil,
+		newNamespaceReaderManager(metadata, tally.NoopScope, opts),
+		&testIncreasingIndex{}, nil, true, opts, seriesOpts).(*dbShard)
+	defer shard.Close()
+	require.NoError(t, shard.Bootstrap())
+	shard.newMergerFn = newMergerTestFn
+	shard.newFSMergeWithMemFn = newFSMergeWithMemTestFn
+	require.NoError(t, os.MkdirAll(fs.ShardDataDirPath(dir, defaultTestNs1ID, 0), 0755))
+
+	blockSize := ropts.BlockSize()
+	t0 := now.Truncate(blockSize).Add(-10 * blockSize)
+	t1 := t0.Add(blockSize)
+	// The latest block has not been expired by any rule.
+	t2 := now.Truncate(blockSize).Add(-blockSize)
+	shard.markWarmFlushStateSuccess(t0)
+	shard.markWarmFlushStateSuccess(t1)
+	shard.markWarmFlushStateSuccess(t2)
+
+	coldFlush := func() {
+		resources := coldFlushReuseableResources{
+			dirtySeries:        newDirtySeriesMap(dirtySeriesMapOptions{}),
+			dirtySeriesToWrite: make(map[xtime.UnixNano]*idList),
+			idElementPool:      newIDElementPool(nil),
+			fsReader:           fs.NewMockDataFileSetReader(ctrl),
+		}
+		err := shard.ColdFlush(persist.NewMockFlushPreparer(ctrl),
+			resources, namespace.Context{})
+		require.NoError(t, err)
+	}
+	requireColdVersions := func(expected map[time.Time]int) {
+		for blockStart, version := range expected {
+			coldVersion, err := shard.RetrievableBlockColdVersion(blockStart)
+			require.NoError(t, err)
+			require.Equal(t, version, coldVersion)
+		}
+	}
+
+	// Expired blocks are rewritten even though nothing is dirty.
+	coldFlush()
+	requireColdVersions(map[time.Time]int{t0: 1, t1: 1, t2: 0})
+	for _, blockStart := range []time.Time{t0, t1} {
+		_, ok, err := fs.ReadSeriesRetentionMarker(dir, fs.FileSetFileIdentifier{
+			Namespace:   defaultTestNs1ID,
+			Shard:       0,
+			BlockStart:  blockStart,
+			VolumeIndex: 1,
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	// Blocks are not rewritten again for the same rules.
+	coldFlush()
+	requireColdVersions(map[time.Time]int{t0: 1, t1: 1, t2: 0})
+
+	// Nor after the cached digests are lost, e.g. on restart.
+	shard.seriesExpiry.applied = make(map[xtime.UnixNano]uint32)
+	coldFlush()
+	requireColdVersions(map[time.Time]int{t0: 1, t1: 1, t2: 0})
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -47,6 +48,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -673,6 +675,141 @@ func TestShardColdFlushNoMergeIfNothingDirty(t *testing.T) {
 	}
 }
 
+// TestShardColdFlushTieredBlock ensures that a block whose latest volume has
+// been moved to the namespace's secondary tier is merged from there, and that
+// the merged volume is written under the primary file path prefix.
+func TestShardColdFlushTieredBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	nowFn := func() time.Time {
+		return now
+	}
+	var (
+		primaryDir   = path.Join(dir, "primary")
+		secondaryDir = path.Join(dir, "secondary")
+		opts         = DefaultTestOptions()
+		fsOpts       = opts.CommitLogOptions().FilesystemOptions().
+				SetFilePathPrefix(primaryDir)
+	)
+	opts = opts.
+		SetClockOptions(opts.ClockOptions().SetNowFn(nowFn)).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts))
+
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.
+		SetTieringOptions(namespace.NewTieringOptions().
+			SetEnabled(true).
+			SetColdAfter(6*time.Hour).
+			SetFilePathPrefix(secondaryDir)))
+	require.NoError(t, err)
+	seriesOpts := NewSeriesOptionsFromOptions(opts, defaultTestRetentionOpts).
+		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
+		SetBufferBucketPool(series.NewBufferBucketPool(nil))
+	shard := newDatabaseShard(metadata, 0, nil,
+		newNamespaceReaderManager(metadata, tally.NoopScope, opts),
+		&testIncreasingIndex{}, nil, true, opts, seriesOpts).(*dbShard)
+	defer shard.Close()
+	require.NoError(t, shard.Bootstrap())
+	shard.newFSMergeWithMemFn = newFSMergeWithMemTestFn
+
+	blockSize := defaultTestRetentionOpts.BlockSize()
+	blockStart := now.Truncate(blockSize).Add(-10 * blockSize)
+	shard.markWarmFlushStateSuccess(blockStart)
+
+	// Write the warm flushed volume straight to the secondary tier as if it
+	// had been moved there.
+	writer, err := fs.NewWriter(fsOpts.SetFilePathPrefix(secondaryDir))
+	require.NoError(t, err)
+	require.NoError(t, writer.Open(fs.DataWriterOpenOptions{
+		FileSetType: persist.FileSetFlushType,
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  defaultTestNs1ID,
+			Shard:      shard.ID(),
+			BlockStart: blockStart,
+		},
+		BlockSize: blockSize,
+	}))
+	require.NoError(t, writer.Close())
+
+	curr := series.NewMockDatabaseSeries(ctrl)
+	curr.EXPECT().ID().Return(ident.StringID("foo"))
+	curr.EXPECT().ColdFlushBlockStarts(gomock.Any()).
+		Return(optimizedTimesFromTimes([]time.Time{blockStart}))
+	shard.list.PushBack(lookup.NewEntry(curr, 0))
+
+	fsReader, err := fs.NewReader(opts.BytesPool(), fsOpts)
+	require.NoError(t, err)
+	resources := coldFlushReuseableResources{
+		dirtySeries:        newDirtySeriesMap(dirtySeriesMapOptions{}),
+		dirtySeriesToWrite: make(map[xtime.UnixNano]*idList),
+		idElementPool:      newIDElementPool(nil),
+		fsReader:           fsReader,
+	}
+	pm, err := fs.NewPersistManager(fsOpts)
+	require.NoError(t, err)
+	preparer, err := pm.StartFlushPersist()
+	require.NoError(t, err)
+	require.NoError(t, shard.ColdFlush(preparer, resources, namespace.Context{}))
+	require.NoError(t, preparer.DoneFlush())
+
+	coldVersion, err := shard.RetrievableBlockColdVersion(blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 1, coldVersion)
+	exists, err := fs.DataFileSetExists(primaryDir, defaultTestNs1ID,
+		shard.ID(), blockStart, 1)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestShardBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	nowFn := func() time.Time {
+		return now
+	}
+	opts := DefaultTestOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+	blockSize := opts.SeriesOptions().RetentionOptions().BlockSize()
+	shard := testDatabaseShard(t, opts)
+	shard.newMergerFn = newMergerTestFn
+
+	t0 := now.Truncate(blockSize).Add(-10 * blockSize)
+	t1 := t0.Add(blockSize)
+	series := []BackfillSeries{newTestBackfillSeries("foo", t0, 1, 2, 3)}
+	preparer := persist.NewMockFlushPreparer(ctrl)
+	nsCtx := namespace.Context{}
+
+	// Backfills are rejected until the shard is bootstrapped.
+	err := shard.Backfill(t0, series, preparer, nsCtx)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsRetryableError(err))
+	require.NoError(t, shard.Bootstrap())
+
+	// Backfills are rejected until the block has been warm flushed.
+	shard.markWarmFlushStateSuccess(t0)
+	err = shard.Backfill(t1, []BackfillSeries{newTestBackfillSeries("foo", t1, 1)}, preparer, nsCtx)
+	require.Error(t, err)
+	assert.False(t, xerrors.IsRetryableError(err))
+	assert.True(t, xerrors.IsInvalidParams(err))
+
+	// Backfills without any datapoints do not write a new volume.
+	require.NoError(t, shard.Backfill(t0, []BackfillSeries{newTestBackfillSeries("foo", t0)}, preparer, nsCtx))
+	coldVersion, err := shard.RetrievableBlockColdVersion(t0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, coldVersion)
+
+	require.NoError(t, shard.Backfill(t0, series, preparer, nsCtx))
+	coldVersion, err = shard.RetrievableBlockColdVersion(t0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, coldVersion)
+}
+
 func newMergerTestFn(
 	reader fs.DataFileSetReader,
 	blockAllocSize int,
@@ -682,6 +819,7 @@ func newMergerTestFn(
 	encoderPool encoding.EncoderPool,
 	contextPool context.Pool,
 	nsOpts namespace.Options,
+	fsOpts fs.Options,
 ) fs.Merger {
 	return &noopMerger{}
 }
@@ -701,6 +839,8 @@ func (m *noopMerger) Merge(
 func newFSMergeWithMemTestFn(
 	shard databaseShard,
 	retriever series.QueryableBlockRetriever,
+	tombstones *shardTombstones,
+	seriesExpiry *shardSeriesExpiry,
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
 ) fs.MergeWith {
@@ -727,6 +867,14 @@ func (m *noopMergeWith) ForEachRemaining(
 	return nil
 }
 
+func (m *noopMergeWith) DeletedRanges(
+	seriesID ident.ID,
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) xtime.Ranges {
+	return xtime.Ranges{}
+}
+
 func TestShardSnapshotShardNotBootstrapped(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,305 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// shardTombstonesVersion is shared by the tombstones of every shard so that a
+// change to the tombstones of any shard always results in a version greater
+// than that of every other shard.
+var shardTombstonesVersion uint64
+
+// shardTombstones tracks the time ranges of series data that have been
+// deleted from a shard. Reads filter out datapoints that fall within a
+// deleted range and cold flushes drop them when rewriting filesets. The
+// tombstones are persisted per shard so that deleted data stays deleted
+// across restarts.
+type shardTombstones struct {
+	sync.RWMutex
+	// persistLock serializes persisting so that an older snapshot of the
+	// tombstones never overwrites a newer one.
+	persistLock sync.Mutex
+	blockSize   time.Duration
+	deleted     map[string]xtime.Ranges
+	// unmerged holds the block starts whose filesets may still contain
+	// deleted data, mapped to a version that is incremented on every deletion
+	// touching the block so that deletions racing a cold flush are not lost.
+	unmerged map[xtime.UnixNano]int
+	// version changes whenever series are deleted or tombstones are dropped.
+	version uint64
+}
+
+func newShardTombstones(blockSize time.Duration) *shardTombstones {
+	return &shardTombstones{
+		blockSize: blockSize,
+		deleted:   make(map[string]xtime.Ranges),
+		unmerged:  make(map[xtime.UnixNano]int),
+	}
+}
+
+// add marks the data of the series within the time range as deleted.
+func (t *shardTombstones) add(id ident.ID, r xtime.Range) {
+	if r.IsEmpty() {
+		return
+	}
+
+	t.Lock()
+	key := id.String()
+	t.deleted[key] = t.deleted[key].AddRange(r)
+	for blockStart := r.Start.Truncate(t.blockSize); blockStart.Before(r.End); blockStart = blockStart.Add(t.blockSize) {
+		t.unmerged[xtime.ToUnixNano(blockStart)]++
+	}
+	t.version = atomic.AddUint64(&shardTombstonesVersion, 1)
+	t.Unlock()
+}
+
+// deletedRanges returns the deleted time ranges of the series, if any.
+func (t *shardTombstones) deletedRanges(id ident.ID) xtime.Ranges {
+	t.RLock()
+	ranges := t.deleted[id.String()]
+	t.RUnlock()
+	return ranges
+}
+
+// forEachDeleted calls the function with the ID of every series that has had
+// all of its data within the time range deleted.
+func (t *shardTombstones) forEachDeleted(r xtime.Range, fn func(id []byte)) {
+	t.RLock()
+	for key, ranges := range t.deleted {
+		if !ranges.Overlaps(r) {
+			continue
+		}
+		if xtime.NewRanges(r).RemoveRanges(ranges).IsEmpty() {
+			fn([]byte(key))
+		}
+	}
+	t.RUnlock()
+}
+
+// currentVersion returns the version of the deleted series, it changes
+// whenever series are deleted or tombstones are dropped.
+func (t *shardTombstones) currentVersion() uint64 {
+	t.RLock()
+	version := t.version
+	t.RUnlock()
+	return version
+}
+
+// unmergedBlockStarts returns a snapshot of the block starts that need their
+// filesets rewritten along with their current versions.
+func (t *shardTombstones) unmergedBlockStarts() map[xtime.UnixNano]int {
+	t.RLock()
+	if len(t.unmerged) == 0 {
+		t.RUnlock()
+		return nil
+	}
+	result := make(map[xtime.UnixNano]int, len(t.unmerged))
+	for blockStart, version := range t.unmerged {
+		result[blockStart] = version
+	}
+	t.RUnlock()
+	return result
+}
+
+// markMerged records that the fileset for the block start has been rewritten
+// without the deleted data, unless more data was deleted in the meantime,
+// and returns whether the block start was marked merged.
+func (t *shardTombstones) markMerged(blockStart xtime.UnixNano, version int) bool {
+	t.Lock()
+	defer t.Unlock()
+	if curr, ok := t.unmerged[blockStart]; ok && curr == version {
+		delete(t.unmerged, blockStart)
+		return true
+	}
+	return false
+}
+
+// removeBefore drops any tombstones for data before the given time since that
+// data has fallen out of retention, and returns whether any were dropped.
+func (t *shardTombstones) removeBefore(earliest time.Time) bool {
+	var (
+		expired = xtime.Range{Start: time.Unix(0, 0), End: earliest}
+		removed = false
+	)
+	t.Lock()
+	defer t.Unlock()
+	for key, ranges := range t.deleted {
+		if !ranges.Overlaps(expired) {
+			continue
+		}
+		removed = true
+		ranges = ranges.RemoveRange(expired)
+		if ranges.IsEmpty() {
+			delete(t.deleted, key)
+			continue
+		}
+		t.deleted[key] = ranges
+	}
+	for blockStart := range t.unmerged {
+		if blockStart.ToTime().Add(t.blockSize).Before(earliest) {
+			removed = true
+			delete(t.unmerged, blockStart)
+		}
+	}
+	if removed {
+		t.version = atomic.AddUint64(&shardTombstonesVersion, 1)
+	}
+	return removed
+}
+
+// load adds the tombstones read from disk to the ones already held.
+func (t *shardTombstones) load(tombstones fs.ShardTombstones) {
+	t.Lock()
+	for key, ranges := range tombstones.Deleted {
+		t.deleted[key] = t.deleted[key].AddRanges(ranges)
+	}
+	for _, blockStart := range tombstones.Unmerged {
+		t.unmerged[blockStart]++
+	}
+	t.version = atomic.AddUint64(&shardTombstonesVersion, 1)
+	t.Unlock()
+}
+
+// persist writes a snapshot of the tombstones with the given function.
+func (t *shardTombstones) persist(fn func(fs.ShardTombstones) error) error {
+	t.persistLock.Lock()
+	defer t.persistLock.Unlock()
+
+	t.RLock()
+	tombstones := fs.ShardTombstones{
+		Deleted:  make(map[string]xtime.Ranges, len(t.deleted)),
+		Unmerged: make([]xtime.UnixNano, 0, len(t.unmerged)),
+	}
+	for key, ranges := range t.deleted {
+		tombstones.Deleted[key] = ranges
+	}
+	for blockStart := range t.unmerged {
+		tombstones.Unmerged = append(tombstones.Unmerged, blockStart)
+	}
+	t.RUnlock()
+
+	return fn(tombstones)
+}
+
+// filterDeleted removes the datapoints that fall within the deleted ranges
+// from the blocks read for a series. Blocks that do not overlap with any
+// deleted range are returned as is and blocks that are completely deleted are
+// dropped.
+func filterDeleted(
+	ctx context.Context,
+	blocks [][]xio.BlockReader,
+	deleted xtime.Ranges,
+	opts Options,
+	nsOpts namespace.Options,
+	nsCtx namespace.Context,
+) ([][]xio.BlockReader, error) {
+	filtered := make([][]xio.BlockReader, 0, len(blocks))
+	for _, readers := range blocks {
+		if len(readers) == 0 {
+			continue
+		}
+
+		var (
+			start      = readers[0].Start
+			blockSize  = readers[0].BlockSize
+			blockRange = xtime.Range{Start: start, End: start.Add(blockSize)}
+		)
+		if !deleted.Overlaps(blockRange) {
+			filtered = append(filtered, readers)
+			continue
+		}
+		if xtime.NewRanges(blockRange).RemoveRanges(deleted).IsEmpty() {
+			continue
+		}
+
+		reader, ok, err := filterDeletedFromBlock(readers, deleted, opts, nsOpts, nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		ctx.RegisterFinalizer(reader)
+		filtered = append(filtered, []xio.BlockReader{{
+			SegmentReader: reader,
+			Start:         start,
+			BlockSize:     blockSize,
+		}})
+	}
+	return filtered, nil
+}
+
+func filterDeletedFromBlock(
+	readers []xio.BlockReader,
+	deleted xtime.Ranges,
+	opts Options,
+	nsOpts namespace.Options,
+	nsCtx namespace.Context,
+) (xio.SegmentReader, bool, error) {
+	var (
+		start      = readers[0].Start
+		blockSize  = readers[0].BlockSize
+		segReaders = make([]xio.SegmentReader, 0, len(readers))
+	)
+	for _, reader := range readers {
+		segReaders = append(segReaders, reader.SegmentReader)
+	}
+
+	iter := opts.MultiReaderIteratorPool().Get()
+	iter.Reset(segReaders, start, blockSize, nsCtx.Schema)
+	defer iter.Close()
+
+	// NB: the block must be re-encoded with the value encoding scheme of the
+	// namespace it was read from.
+	encoder := namespaceEncoderPool(opts, nsOpts).Get()
+	encoder.Reset(start, opts.DatabaseBlockOptions().DatabaseBlockAllocSize(), nsCtx.Schema)
+	for iter.Next() {
+		dp, unit, annotation := iter.Current()
+		if deleted.Overlaps(xtime.Range{Start: dp.Timestamp, End: dp.Timestamp.Add(time.Nanosecond)}) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
+			encoder.Close()
+			return nil, false, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		encoder.Close()
+		return nil, false, err
+	}
+
+	segment := encoder.Discard()
+	if segment.Len() == 0 {
+		return nil, false, nil
+	}
+	return xio.NewSegmentReader(segment), true, nil
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardTombstonesAddAndMerge(t *testing.T) {
+	var (
+		blockSize = time.Hour
+		start     = time.Now().Truncate(blockSize)
+		id        = ident.StringID("foo")
+		tombs     = newShardTombstones(blockSize)
+	)
+
+	assert.True(t, tombs.deletedRanges(id).IsEmpty())
+	assert.Nil(t, tombs.unmergedBlockStarts())
+	version := tombs.currentVersion()
+
+	tombs.add(id, xtime.Range{
+		Start: start.Add(30 * time.Minute),
+		End:   start.Add(90 * time.Minute),
+	})
+	assert.True(t, tombs.currentVersion() > version)
+	assert.True(t, tombs.deletedRanges(id).Overlaps(xtime.Range{
+		Start: start.Add(45 * time.Minute),
+		End:   start.Add(50 * time.Minute),
+	}))
+	assert.True(t, tombs.deletedRanges(ident.StringID("bar")).IsEmpty())
+
+	unmerged := tombs.unmergedBlockStarts()
+	require.Equal(t, 2, len(unmerged))
+	first := xtime.ToUnixNano(start)
+	second := xtime.ToUnixNano(start.Add(blockSize))
+	assert.Equal(t, 1, unmerged[first])
+	assert.Equal(t, 1, unmerged[second])
+
+	// A deletion racing the merge of the first block keeps it unmerged.
+	tombs.add(id, xtime.Range{Start: start, End: start.Add(time.Minute)})
+	tombs.markMerged(first, unmerged[first])
+	tombs.markMerged(second, unmerged[second])
+
+	unmerged = tombs.unmergedBlockStarts()
+	require.Equal(t, 1, len(unmerged))
+	assert.Equal(t, 2, unmerged[first])
+
+	version = tombs.currentVersion()
+	tombs.removeBefore(start.Add(2 * blockSize))
+	assert.True(t, tombs.deletedRanges(id).IsEmpty())
+	assert.Nil(t, tombs.unmergedBlockStarts())
+	assert.True(t, tombs.currentVersion() > version)
+}
+
+func TestShardTombstonesForEachDeleted(t *testing.T) {
+	var (
+		blockSize = time.Hour
+		start     = time.Now().Truncate(blockSize)
+		tombs     = newShardTombstones(blockSize)
+	)
+
+	// Adjacent deletions together cover the first block.
+	tombs.add(ident.StringID("foo"), xtime.Range{Start: start, End: start.Add(30 * time.Minute)})
+	tombs.add(ident.StringID("foo"), xtime.Range{Start: start.Add(30 * time.Minute), End: start.Add(blockSize)})
+	tombs.add(ident.StringID("bar"), xtime.Range{Start: start, End: start.Add(30 * time.Minute)})
+
+	var deleted []string
+	tombs.forEachDeleted(xtime.Range{Start: start, End: start.Add(blockSize)}, func(id []byte) {
+		deleted = append(deleted, string(id))
+	})
+	assert.Equal(t, []string{"foo"}, deleted)
+
+	deleted = nil
+	tombs.forEachDeleted(xtime.Range{Start: start.Add(blockSize), End: start.Add(2 * blockSize)}, func(id []byte) {
+		deleted = append(deleted, string(id))
+	})
+	assert.Empty(t, deleted)
+}
+
+func TestShardDeleteSeriesPersistsTombstones(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir)
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
+		SetFilesystemOptions(fsOpts))
+
+	var (
+		blockSize = defaultTestRetentionOpts.BlockSize()
+		start     = time.Now().Truncate(blockSize).Add(-blockSize)
+		end       = start.Add(blockSize)
+		id        = ident.StringID("foo")
+	)
+	s := testDatabaseShard(t, opts)
+	require.NoError(t, s.DeleteSeries([]ident.ID{id}, start, end))
+	s.Close()
+
+	// A restarted shard loads the tombstones when it bootstraps.
+	s = testDatabaseShard(t, opts)
+	defer s.Close()
+	assert.True(t, s.tombstones.deletedRanges(id).IsEmpty())
+	require.NoError(t, s.Bootstrap())
+	assert.True(t, s.tombstones.deletedRanges(id).Overlaps(xtime.Range{Start: start, End: end}))
+
+	var deleted []string
+	s.ForEachDeletedSeries(xtime.Range{Start: start, End: end}, func(id []byte) {
+		deleted = append(deleted, string(id))
+	})
+	assert.Equal(t, []string{"foo"}, deleted)
+
+	unmerged := s.tombstones.unmergedBlockStarts()
+	require.Equal(t, 1, len(unmerged))
+	_, ok := unmerged[xtime.ToUnixNano(start)]
+	assert.True(t, ok)
+}
+
+func TestShardTombstonesFilterDeleted(t *testing.T) {
+	var (
+		opts      = DefaultTestOptions()
+		blockSize = time.Hour
+		start     = time.Now().Truncate(blockSize)
+		nsCtx     = namespace.Context{}
+		ctx       = context.NewContext()
+	)
+	defer ctx.Close()
+
+	newBlock := func(blockStart time.Time) []xio.BlockReader {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(blockStart, 0, nil)
+		for i := 0; i < 6; i++ {
+			dp := ts.Datapoint{
+				Timestamp: blockStart.Add(time.Duration(i) * 10 * time.Minute),
+				Value:     float64(i),
+			}
+			require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		}
+		reader, ok := encoder.Stream(ctx)
+		require.True(t, ok)
+		return []xio.BlockReader{{
+			SegmentReader: reader,
+			Start:         blockStart,
+			BlockSize:     blockSize,
+		}}
+	}
+
+	blocks := [][]xio.BlockReader{
+		newBlock(start),
+		newBlock(start.Add(blockSize)),
+		newBlock(start.Add(2 * blockSize)),
+	}
+
+	// Delete the second half of the first block and the whole second block.
+	deleted := xtime.NewRanges(xtime.Range{
+		Start: start.Add(30 * time.Minute),
+		End:   start.Add(2 * blockSize),
+	})
+	filtered, err := filterDeleted(ctx, blocks, deleted, opts, namespace.NewOptions(), nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(filtered))
+
+	// Untouched blocks are returned as is.
+	assert.Equal(t, blocks[2], filtered[1])
+
+	iter := opts.MultiReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset([]xio.SegmentReader{filtered[0][0].SegmentReader},
+		start, blockSize, nil)
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []float64{0, 1, 2}, values)
+}
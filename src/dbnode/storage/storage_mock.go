@@ -34,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
@@ -457,6 +458,35 @@ func (mr *MockDatabaseMockRecorder) Truncate(namespace interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockDatabase)(nil).Truncate), namespace)
 }
 
+// DeleteSeries mocks base method
+func (m *MockDatabase) DeleteSeries(ctx context.Context, namespace ident.ID, query index.Query, start, end time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockDatabaseMockRecorder) DeleteSeries(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockDatabase)(nil).DeleteSeries), ctx, namespace, query, start, end)
+}
+
+// Backfill mocks base method
+func (m *MockDatabase) Backfill(ctx context.Context, namespace ident.ID, blockStart time.Time, series []BackfillSeries) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", ctx, namespace, blockStart, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockDatabaseMockRecorder) Backfill(ctx, namespace, blockStart, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*MockDatabase)(nil).Backfill), ctx, namespace, blockStart, series)
+}
+
 // BootstrapState mocks base method
 func (m *MockDatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -852,6 +882,35 @@ func (mr *MockdatabaseMockRecorder) Truncate(namespace interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*Mockdatabase)(nil).Truncate), namespace)
 }
 
+// DeleteSeries mocks base method
+func (m *Mockdatabase) DeleteSeries(ctx context.Context, namespace ident.ID, query index.Query, start, end time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockdatabaseMockRecorder) DeleteSeries(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*Mockdatabase)(nil).DeleteSeries), ctx, namespace, query, start, end)
+}
+
+// Backfill mocks base method
+func (m *Mockdatabase) Backfill(ctx context.Context, namespace ident.ID, blockStart time.Time, series []BackfillSeries) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", ctx, namespace, blockStart, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockdatabaseMockRecorder) Backfill(ctx, namespace, blockStart, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*Mockdatabase)(nil).Backfill), ctx, namespace, blockStart, series)
+}
+
 // BootstrapState mocks base method
 func (m *Mockdatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -1416,6 +1475,35 @@ func (mr *MockdatabaseNamespaceMockRecorder) Truncate() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockdatabaseNamespace)(nil).Truncate))
 }
 
+// DeleteSeries mocks base method
+func (m *MockdatabaseNamespace) DeleteSeries(ctx context.Context, query index.Query, start, end time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ctx, query, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockdatabaseNamespaceMockRecorder) DeleteSeries(ctx, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockdatabaseNamespace)(nil).DeleteSeries), ctx, query, start, end)
+}
+
+// Backfill mocks base method
+func (m *MockdatabaseNamespace) Backfill(ctx context.Context, blockStart time.Time, series []BackfillSeries, flushPersist persist.FlushPreparer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", ctx, blockStart, series, flushPersist)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockdatabaseNamespaceMockRecorder) Backfill(ctx, blockStart, series, flushPersist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*MockdatabaseNamespace)(nil).Backfill), ctx, blockStart, series, flushPersist)
+}
+
 // Repair mocks base method
 func (m *MockdatabaseNamespace) Repair(repairer databaseShardRepairer, tr time0.Range) error {
 	m.ctrl.T.Helper()
@@ -1766,6 +1854,60 @@ func (mr *MockdatabaseShardMockRecorder) FetchBlocksMetadataV2(ctx, start, end,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataV2", reflect.TypeOf((*MockdatabaseShard)(nil).FetchBlocksMetadataV2), ctx, start, end, limit, pageToken, opts)
 }
 
+// DeleteSeries mocks base method
+func (m *MockdatabaseShard) DeleteSeries(ids []ident.ID, start, end time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSeries", ids, start, end)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSeries indicates an expected call of DeleteSeries
+func (mr *MockdatabaseShardMockRecorder) DeleteSeries(ids, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSeries", reflect.TypeOf((*MockdatabaseShard)(nil).DeleteSeries), ids, start, end)
+}
+
+// ForEachDeletedSeries mocks base method
+func (m *MockdatabaseShard) ForEachDeletedSeries(r time0.Range, fn func([]byte)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForEachDeletedSeries", r, fn)
+}
+
+// ForEachDeletedSeries indicates an expected call of ForEachDeletedSeries
+func (mr *MockdatabaseShardMockRecorder) ForEachDeletedSeries(r, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachDeletedSeries", reflect.TypeOf((*MockdatabaseShard)(nil).ForEachDeletedSeries), r, fn)
+}
+
+// DeletedSeriesVersion mocks base method
+func (m *MockdatabaseShard) DeletedSeriesVersion() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedSeriesVersion")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// DeletedSeriesVersion indicates an expected call of DeletedSeriesVersion
+func (mr *MockdatabaseShardMockRecorder) DeletedSeriesVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedSeriesVersion", reflect.TypeOf((*MockdatabaseShard)(nil).DeletedSeriesVersion))
+}
+
+// Backfill mocks base method
+func (m *MockdatabaseShard) Backfill(blockStart time.Time, series []BackfillSeries, flushPreparer persist.FlushPreparer, nsCtx namespace.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backfill", blockStart, series, flushPreparer, nsCtx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backfill indicates an expected call of Backfill
+func (mr *MockdatabaseShardMockRecorder) Backfill(blockStart, series, flushPreparer, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backfill", reflect.TypeOf((*MockdatabaseShard)(nil).Backfill), blockStart, series, flushPreparer, nsCtx)
+}
+
 // PrepareBootstrap mocks base method
 func (m *MockdatabaseShard) PrepareBootstrap() error {
 	m.ctrl.T.Helper()
@@ -2387,6 +2529,104 @@ func (mr *MockdatabaseCleanupManagerMockRecorder) Report() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseCleanupManager)(nil).Report))
 }
 
+// MockdatabaseTieringManager is a mock of databaseTieringManager interface
+type MockdatabaseTieringManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseTieringManagerMockRecorder
+}
+
+// MockdatabaseTieringManagerMockRecorder is the mock recorder for MockdatabaseTieringManager
+type MockdatabaseTieringManagerMockRecorder struct {
+	mock *MockdatabaseTieringManager
+}
+
+// NewMockdatabaseTieringManager creates a new mock instance
+func NewMockdatabaseTieringManager(ctrl *gomock.Controller) *MockdatabaseTieringManager {
+	mock := &MockdatabaseTieringManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseTieringManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockdatabaseTieringManager) EXPECT() *MockdatabaseTieringManagerMockRecorder {
+	return m.recorder
+}
+
+// Tier mocks base method
+func (m *MockdatabaseTieringManager) Tier(t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tier", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tier indicates an expected call of Tier
+func (mr *MockdatabaseTieringManagerMockRecorder) Tier(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tier", reflect.TypeOf((*MockdatabaseTieringManager)(nil).Tier), t)
+}
+
+// Report mocks base method
+func (m *MockdatabaseTieringManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report
+func (mr *MockdatabaseTieringManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseTieringManager)(nil).Report))
+}
+
+// MockdatabaseDownsampleManager is a mock of databaseDownsampleManager interface
+type MockdatabaseDownsampleManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseDownsampleManagerMockRecorder
+}
+
+// MockdatabaseDownsampleManagerMockRecorder is the mock recorder for MockdatabaseDownsampleManager
+type MockdatabaseDownsampleManagerMockRecorder struct {
+	mock *MockdatabaseDownsampleManager
+}
+
+// NewMockdatabaseDownsampleManager creates a new mock instance
+func NewMockdatabaseDownsampleManager(ctrl *gomock.Controller) *MockdatabaseDownsampleManager {
+	mock := &MockdatabaseDownsampleManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseDownsampleManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockdatabaseDownsampleManager) EXPECT() *MockdatabaseDownsampleManagerMockRecorder {
+	return m.recorder
+}
+
+// Downsample mocks base method
+func (m *MockdatabaseDownsampleManager) Downsample(t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Downsample", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Downsample indicates an expected call of Downsample
+func (mr *MockdatabaseDownsampleManagerMockRecorder) Downsample(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Downsample", reflect.TypeOf((*MockdatabaseDownsampleManager)(nil).Downsample), t)
+}
+
+// Report mocks base method
+func (m *MockdatabaseDownsampleManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report
+func (mr *MockdatabaseDownsampleManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseDownsampleManager)(nil).Report))
+}
+
 // MockdatabaseFileSystemManager is a mock of databaseFileSystemManager interface
 type MockdatabaseFileSystemManager struct {
 	ctrl     *gomock.Controller
@@ -3524,6 +3764,34 @@ func (mr *MockOptionsMockRecorder) EncoderPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncoderPool", reflect.TypeOf((*MockOptions)(nil).EncoderPool))
 }
 
+// SetCounterEncoderPool mocks base method
+func (m *MockOptions) SetCounterEncoderPool(value encoding.EncoderPool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCounterEncoderPool", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetCounterEncoderPool indicates an expected call of SetCounterEncoderPool
+func (mr *MockOptionsMockRecorder) SetCounterEncoderPool(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCounterEncoderPool", reflect.TypeOf((*MockOptions)(nil).SetCounterEncoderPool), value)
+}
+
+// CounterEncoderPool mocks base method
+func (m *MockOptions) CounterEncoderPool() encoding.EncoderPool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterEncoderPool")
+	ret0, _ := ret[0].(encoding.EncoderPool)
+	return ret0
+}
+
+// CounterEncoderPool indicates an expected call of CounterEncoderPool
+func (mr *MockOptionsMockRecorder) CounterEncoderPool() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterEncoderPool", reflect.TypeOf((*MockOptions)(nil).CounterEncoderPool))
+}
+
 // SetSegmentReaderPool mocks base method
 func (m *MockOptions) SetSegmentReaderPool(value xio.SegmentReaderPool) Options {
 	m.ctrl.T.Helper()
@@ -3944,6 +4212,34 @@ func (mr *MockOptionsMockRecorder) MemoryTracker() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemoryTracker", reflect.TypeOf((*MockOptions)(nil).MemoryTracker))
 }
 
+// SetBackupManager mocks base method
+func (m *MockOptions) SetBackupManager(value backup.Manager) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackupManager", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackupManager indicates an expected call of SetBackupManager
+func (mr *MockOptionsMockRecorder) SetBackupManager(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackupManager", reflect.TypeOf((*MockOptions)(nil).SetBackupManager), value)
+}
+
+// BackupManager mocks base method
+func (m *MockOptions) BackupManager() backup.Manager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackupManager")
+	ret0, _ := ret[0].(backup.Manager)
+	return ret0
+}
+
+// BackupManager indicates an expected call of BackupManager
+func (mr *MockOptionsMockRecorder) BackupManager() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupManager", reflect.TypeOf((*MockOptions)(nil).BackupManager))
+}
+
 // MockMemoryTracker is a mock of MemoryTracker interface
 type MockMemoryTracker struct {
 	ctrl     *gomock.Controller
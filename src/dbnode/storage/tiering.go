@@ -0,0 +1,219 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+)
+
+type dataFilesFn func(filePathPrefix string, namespace ident.ID, shard uint32) (fs.FileSetFilesSlice, error)
+
+type copyDataFileSetFn func(
+	fileSet fs.FileSetFile,
+	toFilePathPrefix string,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) error
+
+type tieringManager struct {
+	sync.RWMutex
+
+	database database
+	opts     Options
+
+	filePathPrefix    string
+	dataFilesFn       dataFilesFn
+	copyDataFileSetFn copyDataFileSetFn
+	deleteFilesFn     deleteFilesFn
+	tieringInProgress bool
+	metrics           tieringManagerMetrics
+}
+
+type tieringManagerMetrics struct {
+	status       tally.Gauge
+	movedFileSet tally.Counter
+	errors       tally.Counter
+}
+
+func newTieringManagerMetrics(scope tally.Scope) tieringManagerMetrics {
+	fsScope := scope.SubScope("tiering")
+	return tieringManagerMetrics{
+		status:       scope.Gauge("tiering"),
+		movedFileSet: fsScope.Counter("moved-fileset"),
+		errors:       fsScope.Counter("errors"),
+	}
+}
+
+func newTieringManager(database database, scope tally.Scope) databaseTieringManager {
+	opts := database.Options()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	return &tieringManager{
+		database:          database,
+		opts:              opts,
+		filePathPrefix:    fsOpts.FilePathPrefix(),
+		dataFilesFn:       fs.DataFiles,
+		copyDataFileSetFn: fs.CopyDataFileSet,
+		deleteFilesFn:     fs.DeleteFiles,
+		metrics:           newTieringManagerMetrics(scope),
+	}
+}
+
+func (m *tieringManager) Tier(t time.Time) error {
+	m.Lock()
+	m.tieringInProgress = true
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.tieringInProgress = false
+		m.Unlock()
+	}()
+
+	namespaces, err := m.database.GetOwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		tieringOpts := n.Options().TieringOptions()
+		if !tieringOpts.Enabled() {
+			continue
+		}
+
+		var (
+			coldBefore       = t.Add(-tieringOpts.ColdAfter())
+			earliestToRetain = retention.FlushTimeStart(n.Options().RetentionOptions(), t)
+			blockSize        = n.Options().RetentionOptions().BlockSize()
+			toFilePathPrefix = tieringOpts.FilePathPrefix()
+		)
+		for _, shard := range n.GetOwnedShards() {
+			if err := m.tierShard(n.ID(), shard, toFilePathPrefix,
+				earliestToRetain, coldBefore, blockSize); err != nil {
+				multiErr = multiErr.Add(fmt.Errorf(
+					"encountered errors when tiering data files for namespace %s shard %d: %v",
+					n.ID().String(), shard.ID(), err))
+			}
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (m *tieringManager) Report() {
+	m.RLock()
+	tieringInProgress := m.tieringInProgress
+	m.RUnlock()
+
+	if tieringInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+func (m *tieringManager) tierShard(
+	namespace ident.ID,
+	shard databaseShard,
+	toFilePathPrefix string,
+	earliestToRetain time.Time,
+	coldBefore time.Time,
+	blockSize time.Duration,
+) error {
+	fileSets, err := m.dataFilesFn(m.filePathPrefix, namespace, shard.ID())
+	if err != nil {
+		return err
+	}
+
+	// Only the latest complete volume of each block is moved, any older
+	// volumes are left for the cleanup of compacted filesets.
+	latest := make(map[xtime.UnixNano]fs.FileSetFile)
+	for _, fileSet := range fileSets {
+		blockStart := fileSet.ID.BlockStart
+		if blockStart.Before(earliestToRetain) ||
+			blockStart.Add(blockSize).After(coldBefore) ||
+			!fileSet.HasCompleteCheckpointFile() {
+			continue
+		}
+
+		key := xtime.ToUnixNano(blockStart)
+		if existing, ok := latest[key]; ok &&
+			existing.ID.VolumeIndex >= fileSet.ID.VolumeIndex {
+			continue
+		}
+		latest[key] = fileSet
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, fileSet := range latest {
+		flushState, err := shard.FlushState(fileSet.ID.BlockStart)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		// Leases can only move forward, so skip volumes that are older than
+		// the volume readers have already been moved to.
+		if fileSet.ID.VolumeIndex < flushState.ColdVersionRetrievable {
+			continue
+		}
+		if err := m.moveFileSet(fileSet, toFilePathPrefix); err != nil {
+			m.metrics.errors.Inc(1)
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		m.metrics.movedFileSet.Inc(1)
+	}
+
+	return multiErr.FinalError()
+}
+
+// moveFileSet copies a fileset to the secondary file path prefix, moves any
+// open readers of the block over to the copy and then removes the original.
+func (m *tieringManager) moveFileSet(fileSet fs.FileSetFile, toFilePathPrefix string) error {
+	fsOpts := m.opts.CommitLogOptions().FilesystemOptions()
+	if err := m.copyDataFileSetFn(fileSet, toFilePathPrefix,
+		fsOpts.NewFileMode(), fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	id := fileSet.ID
+	if _, err := m.opts.BlockLeaseManager().UpdateOpenLeases(block.LeaseDescriptor{
+		Namespace:  id.Namespace,
+		Shard:      id.Shard,
+		BlockStart: id.BlockStart,
+	}, block.LeaseState{Volume: id.VolumeIndex}); err != nil {
+		return fmt.Errorf("unable to update open leases for moved fileset: %v", err)
+	}
+
+	return m.deleteFilesFn(fileSet.AbsoluteFilepaths)
+}
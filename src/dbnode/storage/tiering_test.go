@@ -0,0 +1,198 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func newTieringManagerTestNamespace(
+	ctrl *gomock.Controller,
+	tieringOpts namespace.TieringOptions,
+	shards ...databaseShard,
+) *MockdatabaseNamespace {
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("ns")).AnyTimes()
+	ns.EXPECT().Options().Return(namespaceOptions.SetTieringOptions(tieringOpts)).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(shards).AnyTimes()
+	return ns
+}
+
+func newTieringManagerTestFileSet(
+	blockStart time.Time,
+	volume int,
+	checkpoint fs.LazyEvalBool,
+) fs.FileSetFile {
+	return fs.FileSetFile{
+		ID: fs.FileSetFileIdentifier{
+			Namespace:   ident.StringID("ns"),
+			BlockStart:  blockStart,
+			Shard:       0,
+			VolumeIndex: volume,
+		},
+		AbsoluteFilepaths: []string{
+			fmt.Sprintf("/primary/%d-%d", blockStart.UnixNano(), volume),
+		},
+		CachedHasCompleteCheckpointFile: checkpoint,
+	}
+}
+
+func TestTieringManagerTierMovesColdFileSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockSize   = namespaceOptions.RetentionOptions().BlockSize()
+		now         = time.Now().Truncate(blockSize)
+		tieringOpts = namespace.NewTieringOptions().
+				SetEnabled(true).
+				SetColdAfter(6 * time.Hour).
+				SetFilePathPrefix("/secondary")
+		coldBlockStart       = now.Add(-24 * time.Hour)
+		retrievedBlockStart  = now.Add(-10 * time.Hour)
+		incompleteBlockStart = now.Add(-12 * time.Hour)
+		fileSets             = fs.FileSetFilesSlice{
+			newTieringManagerTestFileSet(coldBlockStart, 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(coldBlockStart, 1, fs.EvalTrue),
+			newTieringManagerTestFileSet(retrievedBlockStart, 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(incompleteBlockStart, 0, fs.EvalFalse),
+			newTieringManagerTestFileSet(now.Add(-4*time.Hour), 0, fs.EvalTrue),
+			newTieringManagerTestFileSet(now.Add(-72*time.Hour), 0, fs.EvalTrue),
+		}
+	)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shard.EXPECT().FlushState(coldBlockStart).
+		Return(fileOpState{ColdVersionRetrievable: 1}, nil)
+	shard.EXPECT().FlushState(retrievedBlockStart).
+		Return(fileOpState{ColdVersionRetrievable: 1}, nil)
+
+	leaseMgr := block.NewMockLeaseManager(ctrl)
+	leaseMgr.EXPECT().UpdateOpenLeases(block.LeaseDescriptor{
+		Namespace:  ident.StringID("ns"),
+		Shard:      0,
+		BlockStart: coldBlockStart,
+	}, block.LeaseState{Volume: 1}).Return(block.UpdateLeasesResult{}, nil)
+
+	ns := newTieringManagerTestNamespace(ctrl, tieringOpts, shard)
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions().SetBlockLeaseManager(leaseMgr)).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+
+	mgr := newTieringManager(db, tally.NoopScope).(*tieringManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		return fileSets, nil
+	}
+
+	var copied []fs.FileSetFile
+	mgr.copyDataFileSetFn = func(
+		fileSet fs.FileSetFile,
+		toFilePathPrefix string,
+		_ os.FileMode,
+		_ os.FileMode,
+	) error {
+		require.Equal(t, "/secondary", toFilePathPrefix)
+		copied = append(copied, fileSet)
+		return nil
+	}
+
+	var deleted []string
+	mgr.deleteFilesFn = func(files []string) error {
+		deleted = append(deleted, files...)
+		return nil
+	}
+
+	require.NoError(t, mgr.Tier(now))
+	require.Equal(t, []fs.FileSetFile{fileSets[1]}, copied)
+	require.Equal(t, fileSets[1].AbsoluteFilepaths, deleted)
+}
+
+func TestTieringManagerTierCopyErrorKeepsFileSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockSize   = namespaceOptions.RetentionOptions().BlockSize()
+		now         = time.Now().Truncate(blockSize)
+		tieringOpts = namespace.NewTieringOptions().
+				SetEnabled(true).
+				SetColdAfter(6 * time.Hour).
+				SetFilePathPrefix("/secondary")
+		blockStart = now.Add(-24 * time.Hour)
+	)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shard.EXPECT().FlushState(blockStart).Return(fileOpState{}, nil)
+
+	ns := newTieringManagerTestNamespace(ctrl, tieringOpts, shard)
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+
+	mgr := newTieringManager(db, tally.NoopScope).(*tieringManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{
+			newTieringManagerTestFileSet(blockStart, 0, fs.EvalTrue),
+		}, nil
+	}
+	mgr.copyDataFileSetFn = func(fs.FileSetFile, string, os.FileMode, os.FileMode) error {
+		return errors.New("an error")
+	}
+	mgr.deleteFilesFn = func(files []string) error {
+		require.FailNow(t, "unexpected delete of files", "%v", files)
+		return nil
+	}
+
+	require.Error(t, mgr.Tier(now))
+}
+
+func TestTieringManagerTierSkipsNamespacesWithoutTiering(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ns := newTieringManagerTestNamespace(ctrl, namespace.NewTieringOptions())
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(DefaultTestOptions()).AnyTimes()
+	db.EXPECT().GetOwnedNamespaces().Return([]databaseNamespace{ns}, nil)
+
+	mgr := newTieringManager(db, tally.NoopScope).(*tieringManager)
+	mgr.dataFilesFn = func(string, ident.ID, uint32) (fs.FileSetFilesSlice, error) {
+		require.FailNow(t, "unexpected listing of data files")
+		return nil, nil
+	}
+
+	require.NoError(t, mgr.Tier(time.Now()))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/fs/backup"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/sharding"
@@ -61,6 +62,22 @@ type IndexedErrorHandler interface {
 	HandleError(index int, err error)
 }
 
+// BackfillSeries is a series and its datapoints, sorted by timestamp, to be
+// written directly into the fileset volumes of a single block.
+type BackfillSeries struct {
+	ID         ident.ID
+	Tags       ident.Tags
+	Datapoints []BackfillDatapoint
+}
+
+// BackfillDatapoint is a single datapoint of a backfilled series.
+type BackfillDatapoint struct {
+	Timestamp  time.Time
+	Value      float64
+	Unit       xtime.Unit
+	Annotation ts.Annotation
+}
+
 // Database is a time series database.
 type Database interface {
 	// Options returns the database options.
@@ -210,6 +227,26 @@ type Database interface {
 	// Truncate truncates data for the given namespace.
 	Truncate(namespace ident.ID) (int64, error)
 
+	// DeleteSeries deletes the data within [start, end) of the series in the
+	// given namespace matching the query, returning the number of series
+	// that had data deleted.
+	DeleteSeries(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		start, end time.Time,
+	) (int64, error)
+
+	// Backfill writes the sorted datapoints of the series for the given
+	// block directly into a new fileset volume of the block, bypassing the
+	// in-memory buffer. The block must already have been flushed.
+	Backfill(
+		ctx context.Context,
+		namespace ident.ID,
+		blockStart time.Time,
+		series []BackfillSeries,
+	) error
+
 	// BootstrapState captures and returns a snapshot of the databases'
 	// bootstrap state.
 	BootstrapState() DatabaseBootstrapState
@@ -370,6 +407,23 @@ type databaseNamespace interface {
 	// Truncate truncates the in-memory data for this namespace.
 	Truncate() (int64, error)
 
+	// DeleteSeries deletes the data within [start, end) of the series
+	// matching the query.
+	DeleteSeries(
+		ctx context.Context,
+		query index.Query,
+		start, end time.Time,
+	) (int64, error)
+
+	// Backfill writes the sorted datapoints of the series for the given
+	// block directly into new fileset volumes of the owning shards.
+	Backfill(
+		ctx context.Context,
+		blockStart time.Time,
+		series []BackfillSeries,
+		flushPreparer persist.FlushPreparer,
+	) error
+
 	// Repair repairs the namespace data for a given time range
 	Repair(repairer databaseShardRepairer, tr xtime.Range) error
 
@@ -493,6 +547,29 @@ type databaseShard interface {
 		opts block.FetchBlocksMetadataOptions,
 	) (block.FetchBlocksMetadataResults, PageToken, error)
 
+	// DeleteSeries marks the data of the series within [start, end) as
+	// deleted, it will no longer be returned by reads and is dropped from
+	// filesets the next time they are rewritten. The deletion is persisted
+	// before returning.
+	DeleteSeries(ids []ident.ID, start, end time.Time) error
+
+	// ForEachDeletedSeries calls the function with the ID of every series
+	// that has had all of its data within the time range deleted.
+	ForEachDeletedSeries(r xtime.Range, fn func(id []byte))
+
+	// DeletedSeriesVersion returns a version of the deleted series that
+	// changes whenever series are deleted or their deletions are dropped.
+	DeletedSeriesVersion() uint64
+
+	// Backfill merges the sorted datapoints of the series with the flushed
+	// data of the block and writes the result as a new fileset volume.
+	Backfill(
+		blockStart time.Time,
+		series []BackfillSeries,
+		flushPreparer persist.FlushPreparer,
+		nsCtx namespace.Context,
+	) error
+
 	// PrepareBootstrap prepares the shard for bootstrapping by ensuring
 	// it knows which flushed files reside on disk.
 	PrepareBootstrap() error
@@ -700,6 +777,27 @@ type databaseCleanupManager interface {
 	Report()
 }
 
+// databaseTieringManager manages moving persisted data to secondary storage.
+type databaseTieringManager interface {
+	// Tier moves the data of blocks that are no longer hot to the secondary
+	// storage of their namespace.
+	Tier(t time.Time) error
+
+	// Report reports runtime information.
+	Report()
+}
+
+// databaseDownsampleManager manages rewriting persisted data at a coarser
+// resolution into another namespace.
+type databaseDownsampleManager interface {
+	// Downsample aggregates the data of blocks that have aged past the
+	// downsample threshold of their namespace into the target namespace.
+	Downsample(t time.Time) error
+
+	// Report reports runtime information.
+	Report()
+}
+
 // databaseFileSystemManager manages the database related filesystem activities.
 type databaseFileSystemManager interface {
 	// Cleanup cleans up data not needed in the persistent storage.
@@ -710,9 +808,12 @@ type databaseFileSystemManager interface {
 
 	// Disable disables the filesystem manager and prevents it from
 	// performing file operations, returns the current file operation status.
+	// File operations stay disabled until every call to Disable has been
+	// matched by a call to Enable.
 	Disable() fileOpStatus
 
-	// Enable enables the filesystem manager to perform file operations.
+	// Enable reverts a call to Disable, enabling the filesystem manager to
+	// perform file operations once no other callers have it disabled.
 	Enable() fileOpStatus
 
 	// Status returns the file operation status.
@@ -963,6 +1064,14 @@ type Options interface {
 	// EncoderPool returns the contextPool.
 	EncoderPool() encoding.EncoderPool
 
+	// SetCounterEncoderPool sets the pool of encoders using the counter value
+	// encoding scheme, used by namespaces with counter encoding enabled.
+	SetCounterEncoderPool(value encoding.EncoderPool) Options
+
+	// CounterEncoderPool returns the pool of encoders using the counter value
+	// encoding scheme, used by namespaces with counter encoding enabled.
+	CounterEncoderPool() encoding.EncoderPool
+
 	// SetSegmentReaderPool sets the contextPool.
 	SetSegmentReaderPool(value xio.SegmentReaderPool) Options
 
@@ -1052,6 +1161,14 @@ type Options interface {
 
 	// MemoryTracker returns the MemoryTracker.
 	MemoryTracker() MemoryTracker
+
+	// SetBackupManager sets the manager that backs up filesets to an object
+	// store after each flush, if nil filesets are not backed up.
+	SetBackupManager(value backup.Manager) Options
+
+	// BackupManager returns the manager that backs up filesets to an object
+	// store after each flush.
+	BackupManager() backup.Manager
 }
 
 // MemoryTracker tracks memory.
@@ -1107,6 +1224,8 @@ const (
 type newFSMergeWithMemFn func(
 	shard databaseShard,
 	retriever series.QueryableBlockRetriever,
+	tombstones *shardTombstones,
+	seriesExpiry *shardSeriesExpiry,
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
 ) fs.MergeWith
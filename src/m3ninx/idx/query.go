@@ -94,6 +94,34 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term
+// beginning with the given prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewRangeQuery returns a new query for finding documents which have a term
+// lexicographically within the given bounds, an empty bound is unbounded.
+func NewRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) Query {
+	return Query{
+		query: query.NewRangeQuery(field, min, max, minInclusive, maxInclusive),
+	}
+}
+
+// NewNumericRangeQuery returns a new query for finding documents which have a
+// numeric term within the given bounds, an empty bound is unbounded.
+func NewNumericRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) (Query, error) {
+	q, err := query.NewNumericRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		query: q,
+	}, nil
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// NewPrefixTermRange returns a term range matching all terms with the given prefix.
+func NewPrefixTermRange(prefix []byte) TermRange {
+	return TermRange{
+		Begin: prefix,
+		End:   prefixSuccessor(prefix),
+	}
+}
+
+// NewLexicographicTermRange returns a term range matching all terms between the
+// given bounds when compared lexicographically, an empty bound is unbounded.
+func NewLexicographicTermRange(
+	min, max []byte,
+	minInclusive, maxInclusive bool,
+) TermRange {
+	var r TermRange
+	if len(min) > 0 {
+		r.Begin = min
+		if !minInclusive {
+			// NB: The immediate successor of a term is the term with a zero
+			// byte appended to it.
+			r.Begin = immediateSuccessor(min)
+		}
+	}
+	if len(max) > 0 {
+		r.End = max
+		if maxInclusive {
+			r.End = immediateSuccessor(max)
+		}
+	}
+	return r
+}
+
+// NewNumericTermRange returns a term range matching all terms which are
+// numbers between the given bounds, terms which are not numbers never match.
+// Use infinity for an unbounded range.
+func NewNumericTermRange(
+	min, max float64,
+	minInclusive, maxInclusive bool,
+) TermRange {
+	return TermRange{
+		Filter: func(term []byte) bool {
+			v, err := strconv.ParseFloat(string(term), 64)
+			if err != nil || math.IsNaN(v) {
+				return false
+			}
+			if v < min || (v == min && !minInclusive) {
+				return false
+			}
+			if v > max || (v == max && !maxInclusive) {
+				return false
+			}
+			return true
+		},
+	}
+}
+
+// Contains returns whether the term falls within the term range.
+func (r TermRange) Contains(term []byte) bool {
+	if r.Begin != nil && bytes.Compare(term, r.Begin) < 0 {
+		return false
+	}
+	if r.End != nil && bytes.Compare(term, r.End) >= 0 {
+		return false
+	}
+	return r.Filter == nil || r.Filter(term)
+}
+
+func immediateSuccessor(term []byte) []byte {
+	succ := make([]byte, len(term)+1)
+	copy(succ, term)
+	return succ
+}
+
+// prefixSuccessor returns the smallest term that is greater than all terms
+// with the given prefix, or nil if there is no such term.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] == math.MaxUint8 {
+			continue
+		}
+		succ := make([]byte, i+1)
+		copy(succ, prefix)
+		succ[i]++
+		return succ
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixTermRange(t *testing.T) {
+	r := NewPrefixTermRange([]byte("api-"))
+	require.Equal(t, []byte("api."), r.End)
+	require.True(t, r.Contains([]byte("api-")))
+	require.True(t, r.Contains([]byte("api-gateway")))
+	require.False(t, r.Contains([]byte("api")))
+	require.False(t, r.Contains([]byte("api.")))
+	require.False(t, r.Contains([]byte("web")))
+
+	r = NewPrefixTermRange([]byte{'a', math.MaxUint8, math.MaxUint8})
+	require.Equal(t, []byte("b"), r.End)
+	require.True(t, r.Contains([]byte{'a', math.MaxUint8, math.MaxUint8, 'c'}))
+
+	r = NewPrefixTermRange([]byte{math.MaxUint8})
+	require.Nil(t, r.End)
+	require.True(t, r.Contains([]byte{math.MaxUint8, math.MaxUint8}))
+}
+
+func TestLexicographicTermRange(t *testing.T) {
+	tests := []struct {
+		min, max                   string
+		minInclusive, maxInclusive bool
+		matches                    []string
+		nonMatches                 []string
+	}{
+		{
+			min: "b", max: "d", minInclusive: true, maxInclusive: false,
+			matches:    []string{"b", "b0", "c", "czz"},
+			nonMatches: []string{"a", "d", "d0"},
+		},
+		{
+			min: "b", max: "d", minInclusive: false, maxInclusive: true,
+			matches:    []string{"b0", "c", "d"},
+			nonMatches: []string{"a", "b", "d0"},
+		},
+		{
+			min: "", max: "c", maxInclusive: false,
+			matches:    []string{"", "a", "bzz"},
+			nonMatches: []string{"c", "d"},
+		},
+		{
+			min: "c", max: "", minInclusive: true,
+			matches:    []string{"c", "zzz"},
+			nonMatches: []string{"", "bzz"},
+		},
+	}
+
+	for _, test := range tests {
+		r := NewLexicographicTermRange([]byte(test.min), []byte(test.max),
+			test.minInclusive, test.maxInclusive)
+		for _, term := range test.matches {
+			require.True(t, r.Contains([]byte(term)), term)
+		}
+		for _, term := range test.nonMatches {
+			require.False(t, r.Contains([]byte(term)), term)
+		}
+	}
+}
+
+func TestNumericTermRange(t *testing.T) {
+	r := NewNumericTermRange(2, 10, true, false)
+	for _, term := range []string{"2", "2.5", "9", "9.999", "3e0"} {
+		require.True(t, r.Contains([]byte(term)), term)
+	}
+	for _, term := range []string{"1", "10", "100", "abc", "", "NaN"} {
+		require.False(t, r.Contains([]byte(term)), term)
+	}
+
+	r = NewNumericTermRange(math.Inf(-1), 0, false, true)
+	require.True(t, r.Contains([]byte("-1000")))
+	require.True(t, r.Contains([]byte("0")))
+	require.False(t, r.Contains([]byte("0.1")))
+}
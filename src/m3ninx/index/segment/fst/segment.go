@@ -521,6 +521,70 @@ func (r *fsSegment) matchRegexpWithRLock(field []byte, compiled index.CompiledRe
 	return pl, nil
 }
 
+func (r *fsSegment) MatchRange(field []byte, termRange index.TermRange) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	var (
+		fstCloser     = x.NewSafeCloser(termsFST)
+		iter, iterErr = termsFST.Iterator(termRange.Begin, termRange.End)
+		iterCloser    = x.NewSafeCloser(iter)
+		pls           []postings.List
+	)
+	defer func() {
+		iterCloser.Close()
+		fstCloser.Close()
+	}()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+
+		if iterErr != nil {
+			return nil, iterErr
+		}
+
+		term, postingsOffset := iter.Current()
+		if termRange.Filter == nil || termRange.Filter(term) {
+			nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
+			if err != nil {
+				return nil, err
+			}
+			pls = append(pls, nextPl)
+		}
+		iterErr = iter.Next()
+	}
+
+	pl, err := roaring.Union(pls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := iterCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
 func (r *fsSegment) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -786,6 +850,17 @@ func (sr *fsSegmentReader) MatchRegexp(field []byte, compiled index.CompiledRege
 	return pl, err
 }
 
+func (sr *fsSegmentReader) MatchRange(field []byte, termRange index.TermRange) (postings.List, error) {
+	sr.RLock()
+	if sr.closed {
+		sr.RUnlock()
+		return nil, errReaderClosed
+	}
+	pl, err := sr.fsSegment.MatchRange(field, termRange)
+	sr.RUnlock()
+	return pl, err
+}
+
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	sr.RLock()
 	if sr.closed {
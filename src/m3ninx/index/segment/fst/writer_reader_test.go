@@ -343,6 +343,47 @@ func TestPostingsListEqualForMatchTerm(t *testing.T) {
 	}
 }
 
+func TestPostingsListEqualForMatchRange(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			memFieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			memFields := toSlice(t, memFieldsIter)
+
+			for _, f := range memFields {
+				memTermsIter, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				memTerms := toTermPostings(t, memTermsIter)
+
+				for term := range memTerms {
+					if len(term) == 0 {
+						continue
+					}
+					ranges := []index.TermRange{
+						index.NewPrefixTermRange([]byte(term[:1])),
+						index.NewLexicographicTermRange([]byte(term), nil, true, false),
+						index.NewLexicographicTermRange(nil, []byte(term), false, false),
+					}
+					for _, r := range ranges {
+						memPl, err := memReader.MatchRange(f, r)
+						require.NoError(t, err)
+						fstPl, err := fstReader.MatchRange(f, r)
+						require.NoError(t, err)
+						require.True(t, memPl.Equal(fstPl),
+							fmt.Sprintf("%s:%s - [%v] != [%v]", string(f), term, pprintIter(memPl), pprintIter(fstPl)))
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestPostingsListContainsID(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
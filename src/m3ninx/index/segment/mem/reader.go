@@ -96,6 +96,20 @@ func (r *reader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postin
 	return r.segment.matchRegexp(field, compileRE)
 }
 
+func (r *reader) MatchRange(field []byte, termRange index.TermRange) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// A reader can return IDs in the posting list which are greater than its limit.
+	// The reader only guarantees that when fetching the documents associated with a
+	// postings list through a call to Docs, IDs greater than or equal to the limit
+	// will be filtered out.
+	return r.segment.matchRange(field, termRange)
+}
+
 func (r *reader) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
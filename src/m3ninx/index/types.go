@@ -72,6 +72,10 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchRange returns a postings list over all documents which have a term
+	// for the given field within the given term range.
+	MatchRange(field []byte, r TermRange) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
@@ -93,6 +97,17 @@ type CompiledRegex struct {
 	PrefixEnd   []byte
 }
 
+// TermRange is a range of terms, terms are compared lexicographically
+// against the bounds of the range before being passed to the filter.
+type TermRange struct {
+	// Begin is the inclusive lower bound of the range, nil if unbounded.
+	Begin []byte
+	// End is the exclusive upper bound of the range, nil if unbounded.
+	End []byte
+	// Filter optionally restricts the terms within the bounds further.
+	Filter func(term []byte) bool
+}
+
 // DocRetriever returns the document associated with a postings ID. It returns
 // ErrDocNotFound if there is no document corresponding to the given postings ID.
 type DocRetriever interface {
@@ -63,6 +63,17 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_Range:
+		if q.Range.Numeric {
+			return NewNumericRangeQuery(q.Range.Field, q.Range.Min, q.Range.Max,
+				q.Range.MinInclusive, q.Range.MaxInclusive)
+		}
+		return NewRangeQuery(q.Range.Field, q.Range.Min, q.Range.Max,
+			q.Range.MinInclusive, q.Range.MaxInclusive), nil
+
 	case *querypb.Query_Negation:
 		inner, err := unmarshal(q.Negation.Query)
 		if err != nil {
@@ -49,6 +49,18 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "range query",
+			query: NewRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+		},
+		{
+			name:  "numeric range query",
+			query: MustCreateNumericRangeQuery([]byte("weight"), []byte("1.5"), nil, false, false),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
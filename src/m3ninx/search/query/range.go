@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// RangeQuery finds documents which have a term for the given field within the
+// given bounds. Terms are compared lexicographically unless the query is numeric,
+// in which case terms and bounds are compared as numbers. An empty bound is
+// unbounded.
+type RangeQuery struct {
+	field        []byte
+	min          []byte
+	max          []byte
+	minInclusive bool
+	maxInclusive bool
+	numeric      bool
+	termRange    index.TermRange
+}
+
+// NewRangeQuery constructs a new lexicographic RangeQuery for the given field and bounds.
+func NewRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) search.Query {
+	return &RangeQuery{
+		field:        field,
+		min:          min,
+		max:          max,
+		minInclusive: minInclusive,
+		maxInclusive: maxInclusive,
+		termRange:    index.NewLexicographicTermRange(min, max, minInclusive, maxInclusive),
+	}
+}
+
+// NewNumericRangeQuery constructs a new numeric RangeQuery for the given field and
+// bounds, it returns an error if either of the bounds is not a number.
+func NewNumericRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) (search.Query, error) {
+	minValue, err := parseNumericBound(min, math.Inf(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	maxValue, err := parseNumericBound(max, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RangeQuery{
+		field:        field,
+		min:          min,
+		max:          max,
+		minInclusive: minInclusive,
+		maxInclusive: maxInclusive,
+		numeric:      true,
+		termRange:    index.NewNumericTermRange(minValue, maxValue, minInclusive, maxInclusive),
+	}, nil
+}
+
+// MustCreateNumericRangeQuery is like NewNumericRangeQuery but panics if the query cannot be created.
+func MustCreateNumericRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) search.Query {
+	q, err := NewNumericRangeQuery(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func parseNumericBound(bound []byte, unbounded float64) (float64, error) {
+	if len(bound) == 0 {
+		return unbounded, nil
+	}
+
+	v, err := strconv.ParseFloat(string(bound), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid numeric range bound %s: %v", bound, err)
+	}
+	if math.IsNaN(v) {
+		return 0, fmt.Errorf("invalid numeric range bound %s", bound)
+	}
+	return v, nil
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *RangeQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewRangeSearcher(q.field, q.termRange), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *RangeQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*RangeQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) &&
+		bytes.Equal(q.min, inner.min) &&
+		bytes.Equal(q.max, inner.max) &&
+		q.minInclusive == inner.minInclusive &&
+		q.maxInclusive == inner.maxInclusive &&
+		q.numeric == inner.numeric
+}
+
+// ToProto returns the Protobuf query struct corresponding to the range query.
+func (q *RangeQuery) ToProto() *querypb.Query {
+	rng := querypb.RangeQuery{
+		Field:        q.field,
+		Min:          q.min,
+		Max:          q.max,
+		MinInclusive: q.minInclusive,
+		MaxInclusive: q.maxInclusive,
+		Numeric:      q.numeric,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Range{Range: &rng},
+	}
+}
+
+func (q *RangeQuery) String() string {
+	var (
+		left  = "("
+		right = ")"
+		kind  = "range"
+	)
+	if q.minInclusive {
+		left = "["
+	}
+	if q.maxInclusive {
+		right = "]"
+	}
+	if q.numeric {
+		kind = "numericRange"
+	}
+	return fmt.Sprintf("%s(%s, %s%s, %s%s)", kind, q.field, left, q.min, q.max, right)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package searcher
+
+import (
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/search"
+)
+
+type rangeSearcher struct {
+	field     []byte
+	termRange index.TermRange
+}
+
+// NewRangeSearcher returns a new searcher for finding documents which have a term
+// for the given field within the given term range.
+func NewRangeSearcher(field []byte, termRange index.TermRange) search.Searcher {
+	return &rangeSearcher{
+		field:     field,
+		termRange: termRange,
+	}
+}
+
+// NewPrefixSearcher returns a new searcher for finding documents which have a term
+// for the given field beginning with the given prefix.
+func NewPrefixSearcher(field, prefix []byte) search.Searcher {
+	return NewRangeSearcher(field, index.NewPrefixTermRange(prefix))
+}
+
+func (s *rangeSearcher) Search(r index.Reader) (postings.List, error) {
+	return r.MatchRange(s.field, s.termRange)
+}
@@ -156,6 +156,16 @@ func (a Type) IsValidForTimer() bool {
 	}
 }
 
+// IsValidForHistogram if an Type is valid for Histogram.
+func (a Type) IsValidForHistogram() bool {
+	switch a {
+	case Mean, Count, Sum:
+		return true
+	default:
+		return false
+	}
+}
+
 // Quantile returns the quantile represented by the Type.
 func (a Type) Quantile() (float64, bool) {
 	switch a {
@@ -339,6 +349,16 @@ func (aggTypes Types) IsValidForTimer() bool {
 	return true
 }
 
+// IsValidForHistogram checks if the list of aggregation types is valid for Histogram.
+func (aggTypes Types) IsValidForHistogram() bool {
+	for _, aggType := range aggTypes {
+		if !aggType.IsValidForHistogram() {
+			return false
+		}
+	}
+	return true
+}
+
 // PooledQuantiles returns all the quantiles found in the list
 // of aggregation types. Using a floats pool if available.
 //
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/x/pool"
 
 	"github.com/stretchr/testify/require"
@@ -36,10 +37,12 @@ func TestTypesOptionsValidateDefault(t *testing.T) {
 	require.Equal(t, defaultDefaultCounterAggregationTypes, o.DefaultCounterAggregationTypes())
 	require.Equal(t, defaultDefaultTimerAggregationTypes, o.DefaultTimerAggregationTypes())
 	require.Equal(t, defaultDefaultGaugeAggregationTypes, o.DefaultGaugeAggregationTypes())
+	require.Equal(t, defaultDefaultHistogramAggregationTypes, o.DefaultHistogramAggregationTypes())
 	require.NotNil(t, o.QuantileTypeStringFn())
 	require.NotNil(t, o.CounterTypeStringTransformFn())
 	require.NotNil(t, o.TimerTypeStringTransformFn())
 	require.NotNil(t, o.GaugeTypeStringTransformFn())
+	require.NotNil(t, o.HistogramTypeStringTransformFn())
 
 	// Validate derived options
 	opts := o.(*options)
@@ -47,6 +50,7 @@ func TestTypesOptionsValidateDefault(t *testing.T) {
 	require.Equal(t, typeStrings(nil), opts.counterTypeStrings)
 	require.Equal(t, typeStrings(nil), opts.timerTypeStrings)
 	require.Equal(t, typeStrings(nil), opts.gaugeTypeStrings)
+	require.Equal(t, typeStrings(nil), opts.histogramTypeStrings)
 }
 
 func TestOptionsSetDefaultCounterAggregationTypes(t *testing.T) {
@@ -71,6 +75,14 @@ func TestOptionsSetDefaultGaugeAggregationTypes(t *testing.T) {
 	require.Equal(t, typeStrings(nil), o.(*options).gaugeTypeStrings)
 }
 
+func TestOptionsSetDefaultHistogramAggregationTypes(t *testing.T) {
+	aggTypes := Types{Mean}
+	o := NewTypesOptions().SetDefaultHistogramAggregationTypes(aggTypes)
+	require.Equal(t, aggTypes, o.DefaultHistogramAggregationTypes())
+	require.True(t, o.IsContainedInDefaultAggregationTypes(Mean, metric.HistogramType))
+	require.False(t, o.IsContainedInDefaultAggregationTypes(Sum, metric.HistogramType))
+}
+
 func TestOptionsSetTimerQuantileTypeStringFn(t *testing.T) {
 	fn := func(q float64) []byte { return []byte(fmt.Sprintf("%1.2f", q)) }
 	o := NewTypesOptions().SetQuantileTypeStringFn(fn)
@@ -147,6 +159,23 @@ func TestOptionSetGaugeTypeStringTranformFn(t *testing.T) {
 	}
 }
 
+func TestOptionSetHistogramTypeStringTranformFn(t *testing.T) {
+	inputs := []struct {
+		aggType  Type
+		expected []byte
+	}{
+		{aggType: Mean, expected: []byte(".mean")},
+		{aggType: Count, expected: []byte(".count")},
+		{aggType: Sum, expected: []byte(".sum")},
+	}
+
+	o := NewTypesOptions().SetHistogramTypeStringTransformFn(SuffixTransform)
+	for _, input := range inputs {
+		require.Equal(t, input.expected, o.TypeStringForHistogram(input.aggType))
+		require.Equal(t, input.aggType, o.TypeForHistogram(input.expected))
+	}
+}
+
 func TestOptionSetAllTypeStringTranformFns(t *testing.T) {
 	o := NewTypesOptions().
 		SetCounterTypeStringTransformFn(EmptyTransform).
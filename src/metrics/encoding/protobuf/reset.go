@@ -48,6 +48,7 @@ func resetMetricWithMetadatasProto(pb *metricpb.MetricWithMetadatas) {
 	resetGaugeWithMetadatasProto(pb.GaugeWithMetadatas)
 	resetForwardedMetricWithMetadataProto(pb.ForwardedMetricWithMetadata)
 	resetTimedMetricWithMetadataProto(pb.TimedMetricWithMetadata)
+	resetHistogramWithMetadatasProto(pb.HistogramWithMetadatas)
 }
 
 func resetCounterWithMetadatasProto(pb *metricpb.CounterWithMetadatas) {
@@ -74,6 +75,14 @@ func resetGaugeWithMetadatasProto(pb *metricpb.GaugeWithMetadatas) {
 	resetMetadatas(&pb.Metadatas)
 }
 
+func resetHistogramWithMetadatasProto(pb *metricpb.HistogramWithMetadatas) {
+	if pb == nil {
+		return
+	}
+	resetHistogram(&pb.Histogram)
+	resetMetadatas(&pb.Metadatas)
+}
+
 func resetForwardedMetricWithMetadataProto(pb *metricpb.ForwardedMetricWithMetadata) {
 	if pb == nil {
 		return
@@ -122,6 +131,16 @@ func resetGauge(pb *metricpb.Gauge) {
 	pb.Value = 0.0
 }
 
+func resetHistogram(pb *metricpb.Histogram) {
+	if pb == nil {
+		return
+	}
+	pb.Id = pb.Id[:0]
+	pb.Bounds = pb.Bounds[:0]
+	pb.Counts = pb.Counts[:0]
+	pb.Sum = 0.0
+}
+
 func resetForwardedMetric(pb *metricpb.ForwardedMetric) {
 	if pb == nil {
 		return
@@ -149,6 +149,9 @@ func (it *unaggregatedIterator) decodeMessage(size int) error {
 	case metricpb.MetricWithMetadatas_TIMED_METRIC_WITH_METADATA:
 		it.msg.Type = encoding.TimedMetricWithMetadataType
 		it.err = it.msg.TimedMetricWithMetadata.FromProto(it.pb.TimedMetricWithMetadata)
+	case metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS:
+		it.msg.Type = encoding.HistogramWithMetadatasType
+		it.err = it.msg.HistogramWithMetadatas.FromProto(it.pb.HistogramWithMetadatas)
 	default:
 		it.err = fmt.Errorf("unrecognized message type: %v", it.pb.Type)
 	}
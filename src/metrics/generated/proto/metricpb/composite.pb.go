@@ -38,6 +38,7 @@
 		TimedMetricWithStoragePolicy
 		AggregatedMetric
 		MetricWithMetadatas
+		HistogramWithMetadatas
 		PipelineMetadata
 		Metadata
 		StagedMetadata
@@ -49,6 +50,7 @@
 		Gauge
 		TimedMetric
 		ForwardedMetric
+		Histogram
 */
 package metricpb
 
@@ -80,6 +82,7 @@ const (
 	MetricWithMetadatas_GAUGE_WITH_METADATAS           MetricWithMetadatas_Type = 3
 	MetricWithMetadatas_FORWARDED_METRIC_WITH_METADATA MetricWithMetadatas_Type = 4
 	MetricWithMetadatas_TIMED_METRIC_WITH_METADATA     MetricWithMetadatas_Type = 5
+	MetricWithMetadatas_HISTOGRAM_WITH_METADATAS       MetricWithMetadatas_Type = 6
 )
 
 var MetricWithMetadatas_Type_name = map[int32]string{
@@ -89,6 +92,7 @@ var MetricWithMetadatas_Type_name = map[int32]string{
 	3: "GAUGE_WITH_METADATAS",
 	4: "FORWARDED_METRIC_WITH_METADATA",
 	5: "TIMED_METRIC_WITH_METADATA",
+	6: "HISTOGRAM_WITH_METADATAS",
 }
 var MetricWithMetadatas_Type_value = map[string]int32{
 	"UNKNOWN":                        0,
@@ -97,6 +101,7 @@ var MetricWithMetadatas_Type_value = map[string]int32{
 	"GAUGE_WITH_METADATAS":           3,
 	"FORWARDED_METRIC_WITH_METADATA": 4,
 	"TIMED_METRIC_WITH_METADATA":     5,
+	"HISTOGRAM_WITH_METADATAS":       6,
 }
 
 func (x MetricWithMetadatas_Type) String() string {
@@ -291,6 +296,7 @@ type MetricWithMetadatas struct {
 	GaugeWithMetadatas          *GaugeWithMetadatas          `protobuf:"bytes,4,opt,name=gauge_with_metadatas,json=gaugeWithMetadatas" json:"gauge_with_metadatas,omitempty"`
 	ForwardedMetricWithMetadata *ForwardedMetricWithMetadata `protobuf:"bytes,5,opt,name=forwarded_metric_with_metadata,json=forwardedMetricWithMetadata" json:"forwarded_metric_with_metadata,omitempty"`
 	TimedMetricWithMetadata     *TimedMetricWithMetadata     `protobuf:"bytes,6,opt,name=timed_metric_with_metadata,json=timedMetricWithMetadata" json:"timed_metric_with_metadata,omitempty"`
+	HistogramWithMetadatas      *HistogramWithMetadatas      `protobuf:"bytes,7,opt,name=histogram_with_metadatas,json=histogramWithMetadatas" json:"histogram_with_metadatas,omitempty"`
 }
 
 func (m *MetricWithMetadatas) Reset()                    { *m = MetricWithMetadatas{} }
@@ -340,6 +346,37 @@ func (m *MetricWithMetadatas) GetTimedMetricWithMetadata() *TimedMetricWithMetad
 	return nil
 }
 
+func (m *MetricWithMetadatas) GetHistogramWithMetadatas() *HistogramWithMetadatas {
+	if m != nil {
+		return m.HistogramWithMetadatas
+	}
+	return nil
+}
+
+type HistogramWithMetadatas struct {
+	Histogram Histogram       `protobuf:"bytes,1,opt,name=histogram" json:"histogram"`
+	Metadatas StagedMetadatas `protobuf:"bytes,2,opt,name=metadatas" json:"metadatas"`
+}
+
+func (m *HistogramWithMetadatas) Reset()                    { *m = HistogramWithMetadatas{} }
+func (m *HistogramWithMetadatas) String() string            { return proto.CompactTextString(m) }
+func (*HistogramWithMetadatas) ProtoMessage()               {}
+func (*HistogramWithMetadatas) Descriptor() ([]byte, []int) { return fileDescriptorComposite, []int{8} }
+
+func (m *HistogramWithMetadatas) GetHistogram() Histogram {
+	if m != nil {
+		return m.Histogram
+	}
+	return Histogram{}
+}
+
+func (m *HistogramWithMetadatas) GetMetadatas() StagedMetadatas {
+	if m != nil {
+		return m.Metadatas
+	}
+	return StagedMetadatas{}
+}
+
 func init() {
 	proto.RegisterType((*CounterWithMetadatas)(nil), "metricpb.CounterWithMetadatas")
 	proto.RegisterType((*BatchTimerWithMetadatas)(nil), "metricpb.BatchTimerWithMetadatas")
@@ -349,6 +386,7 @@ func init() {
 	proto.RegisterType((*TimedMetricWithStoragePolicy)(nil), "metricpb.TimedMetricWithStoragePolicy")
 	proto.RegisterType((*AggregatedMetric)(nil), "metricpb.AggregatedMetric")
 	proto.RegisterType((*MetricWithMetadatas)(nil), "metricpb.MetricWithMetadatas")
+	proto.RegisterType((*HistogramWithMetadatas)(nil), "metricpb.HistogramWithMetadatas")
 	proto.RegisterEnum("metricpb.MetricWithMetadatas_Type", MetricWithMetadatas_Type_name, MetricWithMetadatas_Type_value)
 }
 func (m *CounterWithMetadatas) Marshal() (dAtA []byte, err error) {
@@ -656,6 +694,50 @@ func (m *MetricWithMetadatas) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n18
 	}
+	if m.HistogramWithMetadatas != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.HistogramWithMetadatas.Size()))
+		n19, err := m.HistogramWithMetadatas.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	return i, nil
+}
+
+func (m *HistogramWithMetadatas) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HistogramWithMetadatas) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintComposite(dAtA, i, uint64(m.Histogram.Size()))
+	n20, err := m.Histogram.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n20
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintComposite(dAtA, i, uint64(m.Metadatas.Size()))
+	n21, err := m.Metadatas.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n21
 	return i, nil
 }
 
@@ -765,6 +847,20 @@ func (m *MetricWithMetadatas) Size() (n int) {
 		l = m.TimedMetricWithMetadata.Size()
 		n += 1 + l + sovComposite(uint64(l))
 	}
+	if m.HistogramWithMetadatas != nil {
+		l = m.HistogramWithMetadatas.Size()
+		n += 1 + l + sovComposite(uint64(l))
+	}
+	return n
+}
+
+func (m *HistogramWithMetadatas) Size() (n int) {
+	var l int
+	_ = l
+	l = m.Histogram.Size()
+	n += 1 + l + sovComposite(uint64(l))
+	l = m.Metadatas.Size()
+	n += 1 + l + sovComposite(uint64(l))
 	return n
 }
 
@@ -1753,6 +1849,149 @@ func (m *MetricWithMetadatas) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramWithMetadatas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HistogramWithMetadatas == nil {
+				m.HistogramWithMetadatas = &HistogramWithMetadatas{}
+			}
+			if err := m.HistogramWithMetadatas.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipComposite(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthComposite
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HistogramWithMetadatas) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowComposite
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HistogramWithMetadatas: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HistogramWithMetadatas: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Histogram", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Histogram.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadatas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Metadatas.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipComposite(dAtA[iNdEx:])
@@ -1884,51 +2123,55 @@ func init() {
 }
 
 var fileDescriptorComposite = []byte{
-	// 732 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0xcd, 0x6e, 0xd3, 0x5a,
-	0x10, 0xc7, 0xeb, 0x36, 0x69, 0x7b, 0x27, 0xbd, 0xbd, 0xb9, 0xe7, 0xe6, 0x36, 0x21, 0xad, 0x0c,
-	0xb5, 0x04, 0x42, 0x42, 0xc4, 0xa2, 0x91, 0xa8, 0x50, 0x05, 0x92, 0xf3, 0xd1, 0x34, 0x42, 0x4d,
-	0x91, 0xeb, 0x2a, 0x12, 0x8b, 0x5a, 0xfe, 0xaa, 0x63, 0x44, 0x62, 0xcb, 0x3e, 0x51, 0x55, 0xb1,
-	0x61, 0x09, 0x3b, 0x24, 0xc4, 0x1b, 0xb0, 0xe5, 0x3d, 0xba, 0x83, 0x27, 0x40, 0xa8, 0xbc, 0x08,
-	0xb2, 0x7d, 0x1c, 0xdb, 0xc7, 0x0e, 0x42, 0xed, 0xce, 0x9e, 0x99, 0xff, 0x6f, 0xfe, 0x39, 0x9e,
-	0x39, 0x81, 0x9e, 0x69, 0xe1, 0xd1, 0x54, 0x6d, 0x68, 0xf6, 0x98, 0x1f, 0x37, 0x75, 0x95, 0x1f,
-	0x37, 0x79, 0xcf, 0xd5, 0xf8, 0xb1, 0x81, 0x5d, 0x4b, 0xf3, 0x78, 0xd3, 0x98, 0x18, 0xae, 0x82,
-	0x0d, 0x9d, 0x77, 0x5c, 0x1b, 0xdb, 0x24, 0xee, 0xa8, 0xbc, 0x66, 0x8f, 0x1d, 0xdb, 0xb3, 0xb0,
-	0xd1, 0x08, 0x12, 0x68, 0x35, 0xca, 0xd4, 0x1f, 0x26, 0x90, 0xa6, 0x6d, 0xda, 0xa1, 0x52, 0x9d,
-	0x9e, 0x05, 0x6f, 0x21, 0xc6, 0x7f, 0x0a, 0x85, 0xf5, 0xce, 0x75, 0x1d, 0x84, 0x0f, 0x84, 0xb2,
-	0x7f, 0x03, 0x8a, 0xa2, 0x2b, 0x58, 0xb9, 0xa6, 0x1b, 0xc7, 0x7e, 0x6d, 0x69, 0x17, 0x8e, 0x4a,
-	0x1e, 0x42, 0x0a, 0xf7, 0x8e, 0x81, 0x4a, 0xdb, 0x9e, 0x4e, 0xb0, 0xe1, 0x0e, 0x2d, 0x3c, 0x3a,
-	0x24, 0x3d, 0x3c, 0xf4, 0x08, 0x56, 0xb4, 0x30, 0x5e, 0x63, 0xee, 0x30, 0xf7, 0x4b, 0x3b, 0xff,
-	0x36, 0x22, 0x27, 0x0d, 0x22, 0x68, 0x15, 0x2e, 0xbf, 0xdf, 0x5e, 0x10, 0xa3, 0x3a, 0xf4, 0x14,
-	0xfe, 0x8a, 0x3c, 0x7a, 0xb5, 0xc5, 0x40, 0x74, 0x2b, 0x16, 0x1d, 0x63, 0xc5, 0x34, 0xf4, 0x59,
-	0x03, 0x22, 0x8e, 0x15, 0xdc, 0x27, 0x06, 0xaa, 0x2d, 0x05, 0x6b, 0x23, 0xc9, 0x1a, 0xd3, 0x6e,
-	0xf6, 0xa0, 0xa4, 0xfa, 0x29, 0x19, 0xfb, 0x39, 0xe2, 0xa8, 0x12, 0xc3, 0x63, 0x1d, 0xe1, 0x82,
-	0x3a, 0x8b, 0xdc, 0xd4, 0xd7, 0x5b, 0x06, 0x50, 0x4f, 0x99, 0x9a, 0x46, 0xda, 0xd2, 0x03, 0x28,
-	0x9a, 0x7e, 0x94, 0x98, 0xf9, 0x27, 0x26, 0x06, 0xc5, 0x84, 0x13, 0xd6, 0xdc, 0xd4, 0xc2, 0x47,
-	0x06, 0x36, 0xf7, 0x6d, 0xf7, 0x5c, 0x71, 0xf5, 0xa0, 0xce, 0xb5, 0xb4, 0xa4, 0x19, 0xb4, 0x0b,
-	0xcb, 0x21, 0x8c, 0x98, 0x49, 0xb0, 0x29, 0x19, 0x61, 0x93, 0x72, 0xb4, 0x07, 0xab, 0x51, 0x97,
-	0xac, 0x2d, 0x22, 0x8d, 0xba, 0x10, 0xe9, 0x4c, 0xc0, 0xbd, 0x67, 0xa0, 0xea, 0x9f, 0x70, 0x9e,
-	0xa3, 0x26, 0xe5, 0xe8, 0xff, 0x18, 0x9b, 0x90, 0x50, 0x6e, 0x9e, 0x64, 0xdc, 0x54, 0xb3, 0xb2,
-	0x7c, 0x2f, 0x9f, 0x19, 0xd8, 0xa2, 0xbc, 0x1c, 0x63, 0xdb, 0x55, 0x4c, 0xe3, 0x45, 0x30, 0xee,
-	0xe8, 0x19, 0xac, 0xf9, 0xb3, 0xa3, 0xcb, 0x7f, 0x6e, 0xab, 0x84, 0xe3, 0x10, 0xea, 0xc0, 0xba,
-	0x17, 0x02, 0xe5, 0x70, 0x81, 0x66, 0x0e, 0xa3, 0xc5, 0x6a, 0xa4, 0x1a, 0x12, 0xc6, 0xdf, 0x5e,
-	0x32, 0xc8, 0xbd, 0x81, 0xb2, 0x60, 0x9a, 0xae, 0x61, 0xfa, 0x8b, 0x39, 0x23, 0xa7, 0x8f, 0xea,
-	0x5e, 0xae, 0xa7, 0xcc, 0x2f, 0xa2, 0xce, 0x6e, 0x1b, 0xd6, 0x8c, 0x89, 0x66, 0xeb, 0x86, 0x3c,
-	0x51, 0x26, 0x76, 0x38, 0x64, 0x4b, 0x62, 0x29, 0x8c, 0x0d, 0xfc, 0x10, 0xf7, 0xb5, 0x08, 0xff,
-	0x65, 0x3f, 0x95, 0x87, 0x1e, 0x43, 0x01, 0x5f, 0x38, 0xe1, 0x20, 0xaf, 0xef, 0x70, 0x71, 0xfb,
-	0x9c, 0xe2, 0x86, 0x74, 0xe1, 0x18, 0x62, 0x50, 0x8f, 0x24, 0xd8, 0x20, 0xab, 0x2f, 0x9f, 0x5b,
-	0x78, 0x24, 0xd3, 0x13, 0xce, 0x66, 0x6e, 0x8c, 0x14, 0x4a, 0xac, 0x68, 0x79, 0x17, 0xcf, 0x29,
-	0xd4, 0x13, 0xab, 0x4e, 0x93, 0x97, 0x02, 0xf2, 0x76, 0xde, 0xe6, 0xa7, 0xe1, 0x55, 0x75, 0xce,
-	0x55, 0x32, 0x80, 0x4a, 0xb0, 0x93, 0x34, 0xb9, 0x10, 0x90, 0xb7, 0xa8, 0x35, 0x4e, 0x43, 0x91,
-	0x99, 0xbd, 0x07, 0x5e, 0x01, 0x7b, 0x16, 0xed, 0x18, 0x19, 0xae, 0x34, 0xba, 0x56, 0x0c, 0xc8,
-	0x77, 0xe7, 0xee, 0x64, 0x92, 0x27, 0x6e, 0x9e, 0xfd, 0x66, 0xcf, 0x4f, 0xa1, 0x9e, 0x1c, 0x62,
-	0xaa, 0xcf, 0x32, 0x7d, 0x36, 0x73, 0x96, 0x53, 0xac, 0xe2, 0xfc, 0x04, 0xf7, 0x85, 0x81, 0x82,
-	0xff, 0x81, 0x51, 0x09, 0x56, 0x4e, 0x06, 0xcf, 0x07, 0x47, 0xc3, 0x41, 0x79, 0x01, 0xd5, 0x61,
-	0xa3, 0x7d, 0x74, 0x32, 0x90, 0xba, 0xa2, 0x3c, 0xec, 0x4b, 0x07, 0xf2, 0x61, 0x57, 0x12, 0x3a,
-	0x82, 0x24, 0x1c, 0x97, 0x19, 0xc4, 0x42, 0xbd, 0x25, 0x48, 0xed, 0x03, 0x59, 0xea, 0x1f, 0x66,
-	0xf3, 0x8b, 0xa8, 0x06, 0x95, 0x9e, 0x70, 0xd2, 0xeb, 0xd2, 0x99, 0x25, 0xc4, 0x01, 0xbb, 0x7f,
-	0x24, 0x0e, 0x05, 0xb1, 0xd3, 0xed, 0xf8, 0x09, 0xb1, 0xdf, 0x4e, 0x17, 0x95, 0x0b, 0x3e, 0xdd,
-	0xe7, 0xce, 0xc9, 0x17, 0x5b, 0xfd, 0xcb, 0x2b, 0x96, 0xf9, 0x76, 0xc5, 0x32, 0x3f, 0xae, 0x58,
-	0xe6, 0xc3, 0x4f, 0x76, 0xe1, 0xe5, 0xee, 0x35, 0xff, 0x5d, 0xd5, 0xe5, 0xe0, 0xbd, 0xf9, 0x2b,
-	0x00, 0x00, 0xff, 0xff, 0x1a, 0x70, 0xed, 0x30, 0x67, 0x08, 0x00, 0x00,
+	// 794 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x96, 0xdf, 0x6a, 0xe3, 0x56,
+	0x10, 0xc6, 0xa3, 0xc4, 0x71, 0x92, 0x71, 0x9a, 0xba, 0x27, 0xae, 0xad, 0x3a, 0x41, 0x4d, 0x04,
+	0x2d, 0x85, 0x52, 0x9b, 0xc6, 0xd0, 0x50, 0x42, 0x0b, 0xf2, 0x9f, 0xd8, 0xa6, 0xd8, 0x2e, 0xb2,
+	0x82, 0x21, 0x17, 0x11, 0x92, 0xac, 0xc8, 0x2a, 0x95, 0x65, 0xa4, 0x63, 0x42, 0xe8, 0x4d, 0x2f,
+	0xdb, 0xbb, 0xc0, 0xb2, 0x6f, 0xb0, 0x0f, 0x93, 0x65, 0x6f, 0xf6, 0x09, 0x96, 0x25, 0xfb, 0x22,
+	0x8b, 0xa4, 0x23, 0x4b, 0x3a, 0x96, 0x97, 0x25, 0xbe, 0x93, 0x66, 0xe6, 0xfb, 0xcd, 0x97, 0xc9,
+	0x8c, 0x12, 0x68, 0x1b, 0x26, 0x9e, 0xcc, 0xd5, 0x8a, 0x66, 0x5b, 0x55, 0xab, 0x36, 0x56, 0xab,
+	0x56, 0xad, 0xea, 0x3a, 0x5a, 0xd5, 0xd2, 0xb1, 0x63, 0x6a, 0x6e, 0xd5, 0xd0, 0xa7, 0xba, 0xa3,
+	0x60, 0x7d, 0x5c, 0x9d, 0x39, 0x36, 0xb6, 0x49, 0x7c, 0xa6, 0x56, 0x35, 0xdb, 0x9a, 0xd9, 0xae,
+	0x89, 0xf5, 0x8a, 0x9f, 0x40, 0xbb, 0x61, 0xa6, 0xfc, 0x53, 0x0c, 0x69, 0xd8, 0x86, 0x1d, 0x28,
+	0xd5, 0xf9, 0xad, 0xff, 0x16, 0x60, 0xbc, 0xa7, 0x40, 0x58, 0x6e, 0x3e, 0xd7, 0x41, 0xf0, 0x40,
+	0x28, 0x97, 0x6b, 0x50, 0x94, 0xb1, 0x82, 0x95, 0x67, 0xba, 0x99, 0xd9, 0x7f, 0x9b, 0xda, 0xfd,
+	0x4c, 0x25, 0x0f, 0x01, 0x85, 0xff, 0x8f, 0x81, 0x42, 0xc3, 0x9e, 0x4f, 0xb1, 0xee, 0x8c, 0x4c,
+	0x3c, 0xe9, 0x91, 0x1e, 0x2e, 0xfa, 0x19, 0x76, 0xb4, 0x20, 0xce, 0x32, 0x27, 0xcc, 0x0f, 0xb9,
+	0xb3, 0xaf, 0x2a, 0xa1, 0x93, 0x0a, 0x11, 0xd4, 0x33, 0x8f, 0xef, 0xbe, 0xdd, 0x10, 0xc3, 0x3a,
+	0xf4, 0x1b, 0xec, 0x85, 0x1e, 0x5d, 0x76, 0xd3, 0x17, 0x7d, 0x13, 0x89, 0x86, 0x58, 0x31, 0xf4,
+	0xf1, 0xa2, 0x01, 0x11, 0x47, 0x0a, 0xfe, 0x25, 0x03, 0xa5, 0xba, 0x82, 0xb5, 0x89, 0x64, 0x5a,
+	0xb4, 0x9b, 0x0b, 0xc8, 0xa9, 0x5e, 0x4a, 0xc6, 0xa6, 0xb5, 0x70, 0x54, 0x88, 0xe0, 0x91, 0x8e,
+	0x70, 0x41, 0x5d, 0x44, 0xd6, 0xf5, 0xf5, 0x2f, 0x03, 0xa8, 0xad, 0xcc, 0x0d, 0x3d, 0x69, 0xe9,
+	0x47, 0xd8, 0x36, 0xbc, 0x28, 0x31, 0xf3, 0x65, 0x44, 0xf4, 0x8b, 0x09, 0x27, 0xa8, 0x59, 0xd7,
+	0xc2, 0x0b, 0x06, 0x8e, 0x2e, 0x6d, 0xe7, 0x4e, 0x71, 0xc6, 0x7e, 0x9d, 0x63, 0x6a, 0x71, 0x33,
+	0xe8, 0x1c, 0xb2, 0x01, 0x8c, 0x65, 0x68, 0x36, 0x25, 0x23, 0x6c, 0x52, 0x8e, 0x2e, 0x60, 0x37,
+	0xec, 0xc2, 0x6e, 0xae, 0x90, 0x86, 0x5d, 0x88, 0x74, 0x21, 0xe0, 0xff, 0x67, 0xa0, 0xe4, 0x4d,
+	0x38, 0xcd, 0x51, 0x8d, 0x72, 0xf4, 0x75, 0x84, 0x8d, 0x49, 0x28, 0x37, 0xbf, 0x2e, 0xb9, 0x29,
+	0x2d, 0xcb, 0xd2, 0xbd, 0xbc, 0x62, 0xe0, 0x98, 0xf2, 0x32, 0xc4, 0xb6, 0xa3, 0x18, 0xfa, 0x9f,
+	0xfe, 0xba, 0xa3, 0xdf, 0x61, 0xdf, 0xdb, 0x9d, 0xb1, 0xfc, 0xf9, 0xb6, 0x72, 0x38, 0x0a, 0xa1,
+	0x26, 0x1c, 0xb8, 0x01, 0x50, 0x0e, 0x0e, 0x68, 0xe1, 0x30, 0x3c, 0xac, 0x4a, 0xa2, 0x21, 0x61,
+	0x7c, 0xe1, 0xc6, 0x83, 0xfc, 0x3f, 0x90, 0x17, 0x0c, 0xc3, 0xd1, 0x0d, 0x05, 0xc7, 0xc8, 0xc9,
+	0x51, 0x7d, 0x9f, 0xea, 0x69, 0xe9, 0x27, 0xa2, 0x66, 0x77, 0x0a, 0xfb, 0xfa, 0x54, 0xb3, 0xc7,
+	0xba, 0x3c, 0x55, 0xa6, 0x76, 0xb0, 0x64, 0x5b, 0x62, 0x2e, 0x88, 0xf5, 0xbd, 0x10, 0xff, 0x3a,
+	0x0b, 0x87, 0xcb, 0xbf, 0x2a, 0x17, 0xfd, 0x02, 0x19, 0x7c, 0x3f, 0x0b, 0x16, 0xf9, 0xe0, 0x8c,
+	0x8f, 0xda, 0xa7, 0x14, 0x57, 0xa4, 0xfb, 0x99, 0x2e, 0xfa, 0xf5, 0x48, 0x82, 0x22, 0x39, 0x7d,
+	0xf9, 0xce, 0xc4, 0x13, 0x99, 0xde, 0x70, 0x6e, 0xe9, 0x8b, 0x91, 0x40, 0x89, 0x05, 0x2d, 0x25,
+	0x8a, 0x6e, 0xa0, 0x1c, 0x3b, 0x75, 0x9a, 0xbc, 0xe5, 0x93, 0x4f, 0xd3, 0x2e, 0x3f, 0x09, 0x2f,
+	0xa9, 0xe9, 0x09, 0xd4, 0x87, 0x82, 0x7f, 0x93, 0x34, 0x39, 0xe3, 0x93, 0x8f, 0xa9, 0x33, 0x4e,
+	0x42, 0x91, 0xb1, 0x14, 0x43, 0x7f, 0x01, 0x77, 0x1b, 0xde, 0x18, 0x59, 0xae, 0x24, 0x9a, 0xdd,
+	0xf6, 0xc9, 0xdf, 0xad, 0xbc, 0xc9, 0x38, 0x4f, 0x3c, 0xba, 0xfd, 0xc4, 0x9d, 0xdf, 0x40, 0x39,
+	0xbe, 0xc4, 0x54, 0x9f, 0x2c, 0x3d, 0x9b, 0x15, 0xc7, 0x29, 0x96, 0xf0, 0x8a, 0xab, 0xbd, 0x06,
+	0x76, 0x62, 0xba, 0xd8, 0x36, 0x1c, 0xc5, 0xa2, 0xe7, 0xb3, 0xe3, 0xd3, 0x4f, 0x22, 0x7a, 0x27,
+	0xac, 0x4c, 0xce, 0xa8, 0x38, 0x49, 0x8d, 0xf3, 0x6f, 0x18, 0xc8, 0x78, 0xcb, 0x83, 0x72, 0xb0,
+	0x73, 0xd5, 0xff, 0xa3, 0x3f, 0x18, 0xf5, 0xf3, 0x1b, 0xa8, 0x0c, 0xc5, 0xc6, 0xe0, 0xaa, 0x2f,
+	0xb5, 0x44, 0x79, 0xd4, 0x95, 0x3a, 0x72, 0xaf, 0x25, 0x09, 0x4d, 0x41, 0x12, 0x86, 0x79, 0x06,
+	0x71, 0x50, 0xae, 0x0b, 0x52, 0xa3, 0x23, 0x4b, 0xdd, 0xde, 0x72, 0x7e, 0x13, 0xb1, 0x50, 0x68,
+	0x0b, 0x57, 0xed, 0x16, 0x9d, 0xd9, 0x42, 0x3c, 0x70, 0x97, 0x03, 0x71, 0x24, 0x88, 0xcd, 0x56,
+	0xd3, 0x4b, 0x88, 0xdd, 0x46, 0xb2, 0x28, 0x9f, 0xf1, 0xe8, 0x1e, 0x77, 0x45, 0x7e, 0x1b, 0x1d,
+	0x03, 0xdb, 0xe9, 0x0e, 0xa5, 0x41, 0x5b, 0x14, 0x7a, 0x74, 0x87, 0x2c, 0xff, 0xc0, 0x40, 0x31,
+	0x7d, 0x00, 0xe8, 0x1c, 0xf6, 0x16, 0x23, 0x20, 0x27, 0x7d, 0x98, 0x32, 0xb5, 0xf0, 0x2b, 0xbf,
+	0xa8, 0x5d, 0xf3, 0x8f, 0x44, 0xbd, 0xfb, 0xf8, 0xc4, 0x31, 0x6f, 0x9f, 0x38, 0xe6, 0xfd, 0x13,
+	0xc7, 0x3c, 0x7c, 0xe0, 0x36, 0xae, 0xcf, 0x9f, 0xf9, 0xaf, 0x86, 0x9a, 0xf5, 0xdf, 0x6b, 0x1f,
+	0x07, 0x00, 0x28, 0xbf, 0xb9, 0xb3, 0x74, 0x09, 0x00, 0x00,
 }
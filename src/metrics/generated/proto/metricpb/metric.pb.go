@@ -39,10 +39,11 @@ var _ = math.Inf
 type MetricType int32
 
 const (
-	MetricType_UNKNOWN MetricType = 0
-	MetricType_COUNTER MetricType = 1
-	MetricType_TIMER   MetricType = 2
-	MetricType_GAUGE   MetricType = 3
+	MetricType_UNKNOWN   MetricType = 0
+	MetricType_COUNTER   MetricType = 1
+	MetricType_TIMER     MetricType = 2
+	MetricType_GAUGE     MetricType = 3
+	MetricType_HISTOGRAM MetricType = 4
 )
 
 var MetricType_name = map[int32]string{
@@ -50,12 +51,14 @@ var MetricType_name = map[int32]string{
 	1: "COUNTER",
 	2: "TIMER",
 	3: "GAUGE",
+	4: "HISTOGRAM",
 }
 var MetricType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"COUNTER": 1,
-	"TIMER":   2,
-	"GAUGE":   3,
+	"UNKNOWN":   0,
+	"COUNTER":   1,
+	"TIMER":     2,
+	"GAUGE":     3,
+	"HISTOGRAM": 4,
 }
 
 func (x MetricType) String() string {
@@ -215,12 +218,56 @@ func (m *ForwardedMetric) GetValues() []float64 {
 	return nil
 }
 
+// Histogram is a set of bucketed observations. The counts are the number of
+// observations in each bucket, where bucket i contains values no larger than
+// bounds[i], and the last count is for values larger than all bounds.
+type Histogram struct {
+	Id     []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bounds []float64 `protobuf:"fixed64,2,rep,packed,name=bounds" json:"bounds,omitempty"`
+	Counts []int64   `protobuf:"varint,3,rep,packed,name=counts" json:"counts,omitempty"`
+	Sum    float64   `protobuf:"fixed64,4,opt,name=sum,proto3" json:"sum,omitempty"`
+}
+
+func (m *Histogram) Reset()                    { *m = Histogram{} }
+func (m *Histogram) String() string            { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()               {}
+func (*Histogram) Descriptor() ([]byte, []int) { return fileDescriptorMetric, []int{5} }
+
+func (m *Histogram) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Histogram) GetBounds() []float64 {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+func (m *Histogram) GetCounts() []int64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Counter)(nil), "metricpb.Counter")
 	proto.RegisterType((*BatchTimer)(nil), "metricpb.BatchTimer")
 	proto.RegisterType((*Gauge)(nil), "metricpb.Gauge")
 	proto.RegisterType((*TimedMetric)(nil), "metricpb.TimedMetric")
 	proto.RegisterType((*ForwardedMetric)(nil), "metricpb.ForwardedMetric")
+	proto.RegisterType((*Histogram)(nil), "metricpb.Histogram")
 	proto.RegisterEnum("metricpb.MetricType", MetricType_name, MetricType_value)
 }
 func (m *Counter) Marshal() (dAtA []byte, err error) {
@@ -400,6 +447,64 @@ func (m *ForwardedMetric) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Histogram) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Histogram) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Bounds) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.Bounds)*8))
+		for _, num := range m.Bounds {
+			f3 := math.Float64bits(float64(num))
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(f3))
+			i += 8
+		}
+	}
+	if len(m.Counts) > 0 {
+		dAtA5 := make([]byte, len(m.Counts)*10)
+		var j4 int
+		for _, num1 := range m.Counts {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA5[j4] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j4++
+			}
+			dAtA5[j4] = uint8(num)
+			j4++
+		}
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(j4))
+		i += copy(dAtA[i:], dAtA5[:j4])
+	}
+	if m.Sum != 0 {
+		dAtA[i] = 0x21
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	return i, nil
+}
+
 func encodeVarintMetric(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -486,6 +591,29 @@ func (m *ForwardedMetric) Size() (n int) {
 	return n
 }
 
+func (m *Histogram) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
+	if len(m.Bounds) > 0 {
+		n += 1 + sovMetric(uint64(len(m.Bounds)*8)) + len(m.Bounds)*8
+	}
+	if len(m.Counts) > 0 {
+		l = 0
+		for _, e := range m.Counts {
+			l += sovMetric(uint64(e))
+		}
+		n += 1 + sovMetric(uint64(l)) + l
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	return n
+}
+
 func sovMetric(x uint64) (n int) {
 	for {
 		n++
@@ -1113,6 +1241,206 @@ func (m *ForwardedMetric) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Histogram) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetric
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Histogram: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Histogram: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.Bounds = append(m.Bounds, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.Bounds = append(m.Bounds, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bounds", wireType)
+			}
+		case 3:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (int64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Counts = append(m.Counts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMetric
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (int64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Counts = append(m.Counts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Counts", wireType)
+			}
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetric(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetric
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipMetric(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1223,27 +1551,30 @@ func init() {
 }
 
 var fileDescriptorMetric = []byte{
-	// 340 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x72, 0x49, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
-	0x2e, 0x4a, 0xd6, 0xcf, 0x4d, 0x2d, 0x29, 0xca, 0x4c, 0x2e, 0xd6, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d,
-	0x4a, 0x2c, 0x49, 0x4d, 0xd1, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x87, 0x8a, 0x17, 0x24, 0x41, 0x19,
-	0x7a, 0x60, 0x51, 0x21, 0x0e, 0x98, 0xb0, 0x92, 0x3e, 0x17, 0xbb, 0x73, 0x7e, 0x69, 0x5e, 0x49,
-	0x6a, 0x91, 0x10, 0x1f, 0x17, 0x53, 0x66, 0x8a, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x4f, 0x10, 0x53,
-	0x66, 0x8a, 0x90, 0x08, 0x17, 0x6b, 0x59, 0x62, 0x4e, 0x69, 0xaa, 0x04, 0x93, 0x02, 0xa3, 0x06,
-	0x73, 0x10, 0x84, 0xa3, 0x64, 0xc2, 0xc5, 0xe5, 0x94, 0x58, 0x92, 0x9c, 0x11, 0x92, 0x99, 0x8b,
-	0x45, 0x8f, 0x18, 0x17, 0x1b, 0x58, 0x59, 0xb1, 0x04, 0x93, 0x02, 0xb3, 0x06, 0x63, 0x10, 0x94,
-	0xa7, 0xa4, 0xcb, 0xc5, 0xea, 0x9e, 0x58, 0x9a, 0x9e, 0x8a, 0xdf, 0x12, 0x46, 0x98, 0x25, 0x35,
-	0x5c, 0xdc, 0x20, 0xf3, 0x53, 0x7c, 0xc1, 0xce, 0x14, 0xd2, 0xe0, 0x62, 0x29, 0xa9, 0x2c, 0x48,
-	0x05, 0x6b, 0xe3, 0x33, 0x12, 0xd1, 0x83, 0xb9, 0x5e, 0x0f, 0x22, 0x1f, 0x52, 0x59, 0x90, 0x1a,
-	0x04, 0x56, 0x01, 0x35, 0x9e, 0x09, 0x6e, 0xbc, 0x2c, 0x17, 0x57, 0x49, 0x66, 0x6e, 0x6a, 0x7c,
-	0x5e, 0x62, 0x5e, 0x7e, 0xb1, 0x04, 0x33, 0xd8, 0x23, 0x9c, 0x20, 0x11, 0x3f, 0x90, 0x00, 0xc2,
-	0x76, 0x16, 0x64, 0xdb, 0x9b, 0x18, 0xb9, 0xf8, 0xdd, 0xf2, 0x8b, 0xca, 0x13, 0x8b, 0x52, 0x68,
-	0xef, 0x04, 0x44, 0x88, 0xb1, 0x20, 0x87, 0x98, 0x96, 0x0d, 0x17, 0x17, 0xc2, 0x68, 0x21, 0x6e,
-	0x2e, 0xf6, 0x50, 0x3f, 0x6f, 0x3f, 0xff, 0x70, 0x3f, 0x01, 0x06, 0x10, 0xc7, 0xd9, 0x3f, 0xd4,
-	0x2f, 0xc4, 0x35, 0x48, 0x80, 0x51, 0x88, 0x93, 0x8b, 0x35, 0xc4, 0xd3, 0xd7, 0x35, 0x48, 0x80,
-	0x09, 0xc4, 0x74, 0x77, 0x0c, 0x75, 0x77, 0x15, 0x60, 0x76, 0xf2, 0x3c, 0xf1, 0x48, 0x8e, 0xf1,
-	0xc2, 0x23, 0x39, 0xc6, 0x07, 0x8f, 0xe4, 0x18, 0x27, 0x3c, 0x96, 0x63, 0x88, 0x32, 0x27, 0x33,
-	0xe1, 0x24, 0xb1, 0x81, 0xf9, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x24, 0x3b, 0xb0, 0x62,
-	0x7a, 0x02, 0x00, 0x00,
+	// 392 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x52, 0x4d, 0xab, 0xd3, 0x40,
+	0x14, 0x7d, 0x93, 0xa4, 0xef, 0x99, 0xfb, 0xf4, 0x19, 0x86, 0x22, 0xd9, 0x18, 0x4a, 0x57, 0x41,
+	0x30, 0x01, 0x2b, 0xb8, 0x6e, 0x6b, 0x4d, 0x4b, 0x69, 0x0a, 0x63, 0x8a, 0x20, 0x88, 0xe4, 0x63,
+	0x48, 0x03, 0x4e, 0x26, 0x4c, 0x26, 0x4a, 0xc1, 0x95, 0xbf, 0xc0, 0x9f, 0xe5, 0xd2, 0x9f, 0x20,
+	0xf5, 0x8f, 0xc8, 0xa4, 0x69, 0xab, 0x50, 0x5c, 0x08, 0xee, 0xee, 0x39, 0xb9, 0xf7, 0x9e, 0x73,
+	0x4f, 0x06, 0x5e, 0xe6, 0x85, 0xdc, 0x36, 0x89, 0x97, 0x72, 0xe6, 0xb3, 0x51, 0x96, 0xf8, 0x6c,
+	0xe4, 0xd7, 0x22, 0xf5, 0x19, 0x95, 0xa2, 0x48, 0x6b, 0x3f, 0xa7, 0x25, 0x15, 0xb1, 0xa4, 0x99,
+	0x5f, 0x09, 0x2e, 0x79, 0xc7, 0x57, 0x49, 0x57, 0x78, 0x2d, 0x8b, 0xef, 0x1d, 0xe9, 0xa1, 0x0f,
+	0x37, 0x53, 0xde, 0x94, 0x92, 0x0a, 0x7c, 0x07, 0x5a, 0x91, 0xd9, 0x68, 0x80, 0xdc, 0xfb, 0x44,
+	0x2b, 0x32, 0xdc, 0x87, 0xde, 0xc7, 0xf8, 0x43, 0x43, 0x6d, 0x6d, 0x80, 0x5c, 0x9d, 0x1c, 0xc0,
+	0xf0, 0x39, 0xc0, 0x24, 0x96, 0xe9, 0x36, 0x2a, 0xd8, 0x85, 0x99, 0x47, 0x70, 0xdd, 0xb6, 0xd5,
+	0xb6, 0x36, 0xd0, 0x5d, 0x44, 0x3a, 0x34, 0x7c, 0x0a, 0xbd, 0x20, 0x6e, 0x72, 0xfa, 0x77, 0x11,
+	0x74, 0x14, 0xf9, 0x0c, 0xb7, 0x6a, 0x7f, 0xb6, 0x6a, 0x6d, 0x62, 0x17, 0x0c, 0xb9, 0xab, 0x68,
+	0x3b, 0x76, 0xf7, 0xac, 0xef, 0x1d, 0xdd, 0x7b, 0x87, 0xef, 0xd1, 0xae, 0xa2, 0xa4, 0xed, 0xe8,
+	0xd6, 0x6b, 0xa7, 0xf5, 0x8f, 0x01, 0x64, 0xc1, 0xe8, 0xfb, 0x32, 0x2e, 0x79, 0x6d, 0xeb, 0xed,
+	0x21, 0xa6, 0x62, 0x42, 0x45, 0x9c, 0xd5, 0x8d, 0xdf, 0xd5, 0xbf, 0x20, 0x78, 0xf8, 0x8a, 0x8b,
+	0x4f, 0xb1, 0xc8, 0xfe, 0xbf, 0x85, 0x73, 0x62, 0xc6, 0x1f, 0x89, 0xbd, 0x03, 0x73, 0x5e, 0xd4,
+	0x92, 0xe7, 0x22, 0x66, 0x97, 0x62, 0x4e, 0x78, 0x53, 0x66, 0xa7, 0x98, 0x0f, 0x48, 0xf1, 0xa9,
+	0xfa, 0x9b, 0x4a, 0x47, 0x77, 0x75, 0xd2, 0x21, 0x6c, 0x81, 0x5e, 0x37, 0xac, 0xbb, 0x52, 0x95,
+	0x4f, 0x96, 0x00, 0x67, 0xe7, 0xf8, 0x16, 0x6e, 0x36, 0xe1, 0x32, 0x5c, 0xbf, 0x09, 0xad, 0x2b,
+	0x05, 0xa6, 0xeb, 0x4d, 0x18, 0xcd, 0x88, 0x85, 0xb0, 0x09, 0xbd, 0x68, 0xb1, 0x9a, 0x11, 0x4b,
+	0x53, 0x65, 0x30, 0xde, 0x04, 0x33, 0x4b, 0xc7, 0x0f, 0xc0, 0x9c, 0x2f, 0x5e, 0x47, 0xeb, 0x80,
+	0x8c, 0x57, 0x96, 0x31, 0x59, 0x7c, 0xdb, 0x3b, 0xe8, 0xfb, 0xde, 0x41, 0x3f, 0xf6, 0x0e, 0xfa,
+	0xfa, 0xd3, 0xb9, 0x7a, 0xfb, 0xe2, 0x1f, 0x9f, 0x69, 0x72, 0xdd, 0xe2, 0xd1, 0xaf, 0x01, 0x00,
+	0xe7, 0x29, 0xd4, 0x24, 0xe8, 0x02, 0x00, 0x00,
 }
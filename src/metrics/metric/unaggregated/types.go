@@ -36,6 +36,7 @@ var (
 	errNilCounterWithMetadatasProto    = errors.New("nil counter with metadatas proto message")
 	errNilBatchTimerWithMetadatasProto = errors.New("nil batch timer with metadatas proto message")
 	errNilGaugeWithMetadatasProto      = errors.New("nil gauge with metadatas proto message")
+	errNilHistogramWithMetadatasProto  = errors.New("nil histogram with metadatas proto message")
 )
 
 // Counter is a counter containing the counter ID and the counter value.
@@ -119,6 +120,45 @@ func (g *Gauge) FromProto(pb metricpb.Gauge) {
 	g.Value = pb.Value
 }
 
+// Histogram is a histogram containing the histogram ID, the upper bounds of
+// its buckets, the number of observations in each bucket and the sum of all
+// observations. The bounds are sorted in ascending order and there is one more
+// count than there are bounds, with the last count being the number of
+// observations larger than the largest bound.
+type Histogram struct {
+	ID     id.RawID
+	Bounds []float64
+	Counts []int64
+	Sum    float64
+}
+
+// ToUnion converts the histogram to a metric union.
+func (h Histogram) ToUnion() MetricUnion {
+	return MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              h.ID,
+		HistogramBounds: h.Bounds,
+		HistogramCounts: h.Counts,
+		HistogramSum:    h.Sum,
+	}
+}
+
+// ToProto converts the histogram to a protobuf message in place.
+func (h Histogram) ToProto(pb *metricpb.Histogram) {
+	pb.Id = h.ID
+	pb.Bounds = h.Bounds
+	pb.Counts = h.Counts
+	pb.Sum = h.Sum
+}
+
+// FromProto converts the protobuf message to a histogram in place.
+func (h *Histogram) FromProto(pb metricpb.Histogram) {
+	h.ID = pb.Id
+	h.Bounds = pb.Bounds
+	h.Counts = pb.Counts
+	h.Sum = pb.Sum
+}
+
 // CounterWithPoliciesList is a counter with applicable policies list.
 type CounterWithPoliciesList struct {
 	Counter
@@ -218,18 +258,48 @@ func (gm *GaugeWithMetadatas) FromProto(pb *metricpb.GaugeWithMetadatas) error {
 	return nil
 }
 
+// HistogramWithMetadatas is a histogram with applicable metadatas.
+type HistogramWithMetadatas struct {
+	Histogram
+	metadata.StagedMetadatas
+}
+
+// ToProto converts the histogram with metadatas to a protobuf message in place.
+func (hm HistogramWithMetadatas) ToProto(pb *metricpb.HistogramWithMetadatas) error {
+	if err := hm.StagedMetadatas.ToProto(&pb.Metadatas); err != nil {
+		return err
+	}
+	hm.Histogram.ToProto(&pb.Histogram)
+	return nil
+}
+
+// FromProto converts the protobuf message to a histogram with metadatas in place.
+func (hm *HistogramWithMetadatas) FromProto(pb *metricpb.HistogramWithMetadatas) error {
+	if pb == nil {
+		return errNilHistogramWithMetadatasProto
+	}
+	if err := hm.StagedMetadatas.FromProto(pb.Metadatas); err != nil {
+		return err
+	}
+	hm.Histogram.FromProto(pb.Histogram)
+	return nil
+}
+
 // MetricUnion is a union of different types of metrics, only one of which is valid
 // at any given time. The actual type of the metric depends on the type field,
 // which determines which value field is valid. Note that if the timer values are
 // allocated from a pool, the TimerValPool should be set to the originating pool,
 // and the caller is responsible for returning the timer values to the pool.
 type MetricUnion struct {
-	Type          metric.Type
-	ID            id.RawID
-	CounterVal    int64
-	BatchTimerVal []float64
-	GaugeVal      float64
-	TimerValPool  pool.FloatsPool
+	Type            metric.Type
+	ID              id.RawID
+	CounterVal      int64
+	BatchTimerVal   []float64
+	GaugeVal        float64
+	TimerValPool    pool.FloatsPool
+	HistogramBounds []float64
+	HistogramCounts []int64
+	HistogramSum    float64
 }
 
 var emptyMetricUnion MetricUnion
@@ -243,6 +313,11 @@ func (m *MetricUnion) String() string {
 		return fmt.Sprintf("{type:%s,id:%s,value:%v}", m.Type, m.ID.String(), m.BatchTimerVal)
 	case metric.GaugeType:
 		return fmt.Sprintf("{type:%s,id:%s,value:%f}", m.Type, m.ID.String(), m.GaugeVal)
+	case metric.HistogramType:
+		return fmt.Sprintf(
+			"{type:%s,id:%s,bounds:%v,counts:%v,sum:%f}",
+			m.Type, m.ID.String(), m.HistogramBounds, m.HistogramCounts, m.HistogramSum,
+		)
 	default:
 		return fmt.Sprintf(
 			"{type:%d,id:%s,counterVal:%d,batchTimerVal:%v,gaugeVal:%f}",
@@ -262,3 +337,13 @@ func (m *MetricUnion) BatchTimer() BatchTimer { return BatchTimer{ID: m.ID, Valu
 
 // Gauge returns the gauge metric.
 func (m *MetricUnion) Gauge() Gauge { return Gauge{ID: m.ID, Value: m.GaugeVal} }
+
+// Histogram returns the histogram metric.
+func (m *MetricUnion) Histogram() Histogram {
+	return Histogram{
+		ID:     m.ID,
+		Bounds: m.HistogramBounds,
+		Counts: m.HistogramCounts,
+		Sum:    m.HistogramSum,
+	}
+}
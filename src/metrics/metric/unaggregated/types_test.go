@@ -69,6 +69,19 @@ var (
 		ID:       []byte("testGauge"),
 		GaugeVal: 45.28,
 	}
+	testHistogram = Histogram{
+		ID:     []byte("testHistogram"),
+		Bounds: []float64{0.1, 1, 10},
+		Counts: []int64{3, 5, 0, 2},
+		Sum:    128.5,
+	}
+	testHistogramUnion = MetricUnion{
+		Type:            metric.HistogramType,
+		ID:              []byte("testHistogram"),
+		HistogramBounds: []float64{0.1, 1, 10},
+		HistogramCounts: []int64{3, 5, 0, 2},
+		HistogramSum:    128.5,
+	}
 	testMetadatas = metadata.StagedMetadatas{
 		{
 			CutoverNanos: 1234,
@@ -163,6 +176,10 @@ var (
 		Gauge:           testGauge,
 		StagedMetadatas: testMetadatas,
 	}
+	testHistogramWithMetadatas = HistogramWithMetadatas{
+		Histogram:       testHistogram,
+		StagedMetadatas: testMetadatas,
+	}
 	testCounterProto = metricpb.Counter{
 		Id:    []byte("testCounter"),
 		Value: 1234,
@@ -175,6 +192,12 @@ var (
 		Id:    []byte("testGauge"),
 		Value: 45.28,
 	}
+	testHistogramProto = metricpb.Histogram{
+		Id:     []byte("testHistogram"),
+		Bounds: []float64{0.1, 1, 10},
+		Counts: []int64{3, 5, 0, 2},
+		Sum:    128.5,
+	}
 	testMetadatasProto = metricpb.StagedMetadatas{
 		Metadatas: []metricpb.StagedMetadata{
 			{
@@ -300,6 +323,10 @@ var (
 		Gauge:     testGaugeProto,
 		Metadatas: testMetadatasProto,
 	}
+	testHistogramWithMetadatasProto = metricpb.HistogramWithMetadatas{
+		Histogram: testHistogramProto,
+		Metadatas: testMetadatasProto,
+	}
 )
 
 func TestCounterToUnion(t *testing.T) {
@@ -380,6 +407,36 @@ func TestGaugeRoundTrip(t *testing.T) {
 	require.Equal(t, testGauge, c)
 }
 
+func TestHistogramToUnion(t *testing.T) {
+	require.Equal(t, testHistogramUnion, testHistogram.ToUnion())
+}
+
+func TestHistogramToProto(t *testing.T) {
+	var pb metricpb.Histogram
+	testHistogram.ToProto(&pb)
+	require.Equal(t, testHistogramProto, pb)
+}
+
+func TestHistogramFromProto(t *testing.T) {
+	var h Histogram
+	h.FromProto(testHistogramProto)
+	require.Equal(t, testHistogram, h)
+}
+
+func TestHistogramRoundTrip(t *testing.T) {
+	var (
+		pb metricpb.Histogram
+		h  Histogram
+	)
+	testHistogram.ToProto(&pb)
+	h.FromProto(pb)
+	require.Equal(t, testHistogram, h)
+}
+
+func TestMetricUnionHistogram(t *testing.T) {
+	require.Equal(t, testHistogram, testHistogramUnion.Histogram())
+}
+
 func TestCounterWithMetadatasToProto(t *testing.T) {
 	var pb metricpb.CounterWithMetadatas
 	require.NoError(t, testCounterWithMetadatas.ToProto(&pb))
@@ -514,3 +571,30 @@ func TestGaugeWithMetadatasRoundTrip(t *testing.T) {
 	require.NoError(t, g.FromProto(&pb))
 	require.Equal(t, testGaugeWithMetadatas, g)
 }
+
+func TestHistogramWithMetadatasToProto(t *testing.T) {
+	var pb metricpb.HistogramWithMetadatas
+	require.NoError(t, testHistogramWithMetadatas.ToProto(&pb))
+	require.Equal(t, testHistogramWithMetadatasProto, pb)
+}
+
+func TestHistogramWithMetadatasFromProto(t *testing.T) {
+	var h HistogramWithMetadatas
+	require.NoError(t, h.FromProto(&testHistogramWithMetadatasProto))
+	require.Equal(t, testHistogramWithMetadatas, h)
+}
+
+func TestHistogramWithMetadatasFromProtoNilProto(t *testing.T) {
+	var h HistogramWithMetadatas
+	require.Equal(t, errNilHistogramWithMetadatasProto, h.FromProto(nil))
+}
+
+func TestHistogramWithMetadatasRoundTrip(t *testing.T) {
+	var (
+		pb metricpb.HistogramWithMetadatas
+		h  HistogramWithMetadatas
+	)
+	require.NoError(t, testHistogramWithMetadatas.ToProto(&pb))
+	require.NoError(t, h.FromProto(&pb))
+	require.Equal(t, testHistogramWithMetadatas, h)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	kvaudit "github.com/m3db/m3/src/cluster/kv/audit"
+	clusterplacement "github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+// recordingClient is a cluster client that records an envelope for every
+// version of a managed value written through its stores and placement
+// services. Transaction stores are passed through unrecorded as managed
+// values are not written through them.
+type recordingClient struct {
+	clusterclient.Client
+
+	log    kvaudit.Log
+	e      *auditpb.Envelope
+	opts   kvaudit.Options
+	logger *zap.Logger
+}
+
+func newRecordingClient(
+	client clusterclient.Client,
+	log kvaudit.Log,
+	e *auditpb.Envelope,
+	opts kvaudit.Options,
+) clusterclient.Client {
+	return &recordingClient{
+		Client: client,
+		log:    log,
+		e:      e,
+		opts:   opts,
+		logger: opts.InstrumentOptions().Logger(),
+	}
+}
+
+func (c *recordingClient) KV() (kv.Store, error) {
+	store, err := c.Client.KV()
+	if err != nil {
+		return nil, err
+	}
+	return kvaudit.NewStore(store, c.log,
+		storeIDFn(kv.NewOverrideOptions()), c.e, c.opts), nil
+}
+
+func (c *recordingClient) Store(opts kv.OverrideOptions) (kv.Store, error) {
+	store, err := c.Client.Store(opts)
+	if err != nil {
+		return nil, err
+	}
+	return kvaudit.NewStore(store, c.log, storeIDFn(opts), c.e, c.opts), nil
+}
+
+func (c *recordingClient) Services(
+	opts services.OverrideOptions,
+) (services.Services, error) {
+	s, err := c.Client.Services(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingServices{Services: s, client: c}, nil
+}
+
+func (c *recordingClient) record(id string, version int) {
+	if err := c.log.Record(id, version, c.e); err != nil {
+		c.logger.Warn("unable to record change",
+			zap.String("id", id), zap.Int("version", version), zap.Error(err))
+	}
+}
+
+// storeIDFn maps the managed keys of a store with the given override options
+// to the ids their changes are recorded under.
+func storeIDFn(opts kv.OverrideOptions) kvaudit.IDFn {
+	zone, env := opts.Zone(), opts.Environment()
+	if zone == "" {
+		zone = handleroptions.DefaultServiceZone
+	}
+	if env == "" {
+		env = handleroptions.DefaultServiceEnvironment
+	}
+	return func(key string) string {
+		switch opts.Namespace() {
+		case topicNamespace:
+			return auditID(topicKind, key)
+		case "":
+			if key == namespace.M3DBNodeNamespacesKey {
+				return auditID(namespaceKind, zone, env, key)
+			}
+		}
+		return ""
+	}
+}
+
+// recordingServices returns placement services that record the placements
+// they write.
+type recordingServices struct {
+	services.Services
+
+	client *recordingClient
+}
+
+func (s *recordingServices) PlacementService(
+	sid services.ServiceID,
+	opts clusterplacement.Options,
+) (clusterplacement.Service, error) {
+	ps, err := s.Services.PlacementService(sid, opts)
+	if err != nil || (opts != nil && opts.Dryrun()) {
+		// Dry runs return placements without writing them.
+		return ps, err
+	}
+	return &recordingPlacementService{
+		Service: ps,
+		id: auditID(placementKind, sid.Zone(),
+			sid.Environment(), sid.Name()),
+		client: s.client,
+	}, nil
+}
+
+// recordingPlacementService records the version of every placement it
+// writes. MarkAllShardsAvailable is not recorded as it returns the current
+// placement when there were no shards to mark, which cannot be told apart
+// from a placement it wrote.
+type recordingPlacementService struct {
+	clusterplacement.Service
+
+	id     string
+	client *recordingClient
+}
+
+func (s *recordingPlacementService) record(
+	p clusterplacement.Placement,
+	err error,
+) (clusterplacement.Placement, error) {
+	if err == nil {
+		s.client.record(s.id, p.Version())
+	}
+	return p, err
+}
+
+func (s *recordingPlacementService) Set(
+	p clusterplacement.Placement,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.Set(p))
+}
+
+func (s *recordingPlacementService) CheckAndSet(
+	p clusterplacement.Placement,
+	version int,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.CheckAndSet(p, version))
+}
+
+func (s *recordingPlacementService) SetIfNotExist(
+	p clusterplacement.Placement,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.SetIfNotExist(p))
+}
+
+func (s *recordingPlacementService) SetProto(p proto.Message) (int, error) {
+	version, err := s.Service.SetProto(p)
+	if err == nil {
+		s.client.record(s.id, version)
+	}
+	return version, err
+}
+
+func (s *recordingPlacementService) CheckAndSetProto(
+	p proto.Message,
+	version int,
+) (int, error) {
+	newVersion, err := s.Service.CheckAndSetProto(p, version)
+	if err == nil {
+		s.client.record(s.id, newVersion)
+	}
+	return newVersion, err
+}
+
+func (s *recordingPlacementService) BuildInitialPlacement(
+	instances []clusterplacement.Instance,
+	numShards int,
+	rf int,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.BuildInitialPlacement(instances, numShards, rf))
+}
+
+func (s *recordingPlacementService) AddReplica() (clusterplacement.Placement, error) {
+	return s.record(s.Service.AddReplica())
+}
+
+func (s *recordingPlacementService) AddInstances(
+	candidates []clusterplacement.Instance,
+) (clusterplacement.Placement, []clusterplacement.Instance, error) {
+	p, added, err := s.Service.AddInstances(candidates)
+	p, err = s.record(p, err)
+	return p, added, err
+}
+
+func (s *recordingPlacementService) RemoveInstances(
+	leavingInstanceIDs []string,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.RemoveInstances(leavingInstanceIDs))
+}
+
+func (s *recordingPlacementService) ReplaceInstances(
+	leavingInstanceIDs []string,
+	candidates []clusterplacement.Instance,
+) (clusterplacement.Placement, []clusterplacement.Instance, error) {
+	p, used, err := s.Service.ReplaceInstances(leavingInstanceIDs, candidates)
+	p, err = s.record(p, err)
+	return p, used, err
+}
+
+func (s *recordingPlacementService) MarkShardsAvailable(
+	instanceID string,
+	shardIDs ...uint32,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.MarkShardsAvailable(instanceID, shardIDs...))
+}
+
+func (s *recordingPlacementService) MarkInstanceAvailable(
+	instanceID string,
+) (clusterplacement.Placement, error) {
+	return s.record(s.Service.MarkInstanceAvailable(instanceID))
+}
@@ -0,0 +1,413 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	kvaudit "github.com/m3db/m3/src/cluster/kv/audit"
+	clusterplacement "github.com/m3db/m3/src/cluster/placement"
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	dbnamespace "github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/metrics/generated/proto/rulepb"
+	"github.com/m3db/m3/src/metrics/matcher"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/msg/generated/proto/topicpb"
+	msgtopic "github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/api/v1/handler/placement"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/handler/topic"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// AuthorHeader is the header used to set the author recorded for a change.
+	AuthorHeader = "M3-Change-Author"
+	// ReasonHeader is the header used to set the reason recorded for a change.
+	ReasonHeader = "M3-Change-Reason"
+
+	// RulesetPathName is the ruleset part of the API path.
+	RulesetPathName = "ruleset"
+
+	historyPathName     = "history"
+	rollbackPathName    = "rollback"
+	rulesetNamespaceVar = "namespace"
+
+	placementKind = "placement"
+	namespaceKind = "namespace"
+	topicKind     = "topic"
+	rulesetKind   = "ruleset"
+
+	// topicNamespace is the kv namespace the topic service stores topics in.
+	topicNamespace = "/topic"
+)
+
+var (
+	// RulesetURL is the url prefix of the ruleset history and rollback
+	// handlers.
+	RulesetURL = fmt.Sprintf("%s/%s/{%s}", handler.RoutePrefixV1,
+		RulesetPathName, rulesetNamespaceVar)
+
+	errInstrumentOptionsNotSet = errors.New("instrument options not set")
+	errNoRulesetNamespace      = errors.New("no ruleset namespace specified")
+)
+
+// HandlerOptions is the options struct for the audit handlers.
+type HandlerOptions struct {
+	clusterClient       clusterclient.Client
+	defaults            []handleroptions.ServiceOptionsDefault
+	m3AggServiceOptions *handleroptions.M3AggServiceOptions
+	instrumentOptions   instrument.Options
+	nowFn               func() time.Time
+}
+
+// NewHandlerOptions is the constructor function for HandlerOptions.
+func NewHandlerOptions(
+	client clusterclient.Client,
+	defaults []handleroptions.ServiceOptionsDefault,
+	m3AggOpts *handleroptions.M3AggServiceOptions,
+	instrumentOpts instrument.Options,
+) (HandlerOptions, error) {
+	if instrumentOpts == nil {
+		return HandlerOptions{}, errInstrumentOptionsNotSet
+	}
+	return HandlerOptions{
+		clusterClient:       client,
+		defaults:            defaults,
+		m3AggServiceOptions: m3AggOpts,
+		instrumentOptions:   instrumentOpts,
+		nowFn:               time.Now,
+	}, nil
+}
+
+// target is a managed kv value whose changes are audited.
+type target interface {
+	// id returns the id changes to the value are recorded under.
+	id() string
+
+	// version returns the current version of the value, or zero if the
+	// value was never set.
+	version() (int, error)
+
+	// value returns the value at the given version.
+	value(version int) (proto.Message, error)
+
+	// rollback writes the value at the given version back as a new version
+	// and returns the new version.
+	rollback(version int) (int, error)
+}
+
+// targetFn resolves the target of a request.
+type targetFn func(r *http.Request) (target, error)
+
+// kvTarget is a value stored directly under a kv key.
+type kvTarget struct {
+	auditID    string
+	store      kv.Store
+	key        string
+	newFn      func() proto.Message
+	validateFn kvaudit.ValidateFn
+}
+
+func (t kvTarget) id() string {
+	return t.auditID
+}
+
+func (t kvTarget) version() (int, error) {
+	v, err := t.store.Get(t.key)
+	if err == kv.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return v.Version(), nil
+}
+
+func (t kvTarget) value(version int) (proto.Message, error) {
+	values, err := t.store.History(t.key, version, version+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("version %d of %s not found", version, t.auditID)
+	}
+	m := t.newFn()
+	if err := values[0].Unmarshal(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (t kvTarget) rollback(version int) (int, error) {
+	return kvaudit.Rollback(t.store, t.key, version, t.validateFn)
+}
+
+// placementTarget is a placement, which is read and written through the
+// placement service so that rolled back placements are validated and staged
+// placements keep their snapshots.
+type placementTarget struct {
+	auditID string
+	service clusterplacement.Service
+	nowFn   func() time.Time
+}
+
+func (t placementTarget) id() string {
+	return t.auditID
+}
+
+func (t placementTarget) version() (int, error) {
+	p, err := t.service.Placement()
+	if err == kv.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return p.Version(), nil
+}
+
+func (t placementTarget) value(version int) (proto.Message, error) {
+	p, err := t.service.PlacementForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return p.Proto()
+}
+
+func (t placementTarget) rollback(version int) (int, error) {
+	current, err := t.service.Placement()
+	if err != nil {
+		return 0, err
+	}
+	if version < 1 || version >= current.Version() {
+		return 0, kvaudit.ErrInvalidRollbackVersion
+	}
+
+	p, err := t.service.PlacementForVersion(version)
+	if err != nil {
+		return 0, err
+	}
+	p = p.Clone()
+	if p.IsStaged() {
+		// Staged placements must cut over after the current placement.
+		p = p.SetCutoverNanos(t.nowFn().UnixNano())
+	}
+
+	p, err = t.service.CheckAndSet(p, current.Version())
+	if err != nil {
+		return 0, err
+	}
+	return p.Version(), nil
+}
+
+func (o HandlerOptions) placementTargetFn(serviceName string) targetFn {
+	return func(r *http.Request) (target, error) {
+		svc := handleroptions.ServiceNameAndDefaults{
+			ServiceName: serviceName,
+			Defaults:    o.defaults,
+		}
+		opts := handleroptions.NewServiceOptions(svc, r.Header, o.m3AggServiceOptions)
+		service, err := placement.Service(o.clusterClient, opts, o.nowFn(), nil)
+		if err != nil {
+			return nil, err
+		}
+		return placementTarget{
+			auditID: auditID(placementKind, opts.ServiceZone,
+				opts.ServiceEnvironment, opts.ServiceName),
+			service: service,
+			nowFn:   o.nowFn,
+		}, nil
+	}
+}
+
+func (o HandlerOptions) namespaceTarget(r *http.Request) (target, error) {
+	svc := handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+		Defaults:    o.defaults,
+	}
+	opts := handleroptions.NewServiceOptions(svc, r.Header, nil)
+	store, err := o.clusterClient.Store(kv.NewOverrideOptions().
+		SetEnvironment(opts.ServiceEnvironment).
+		SetZone(opts.ServiceZone))
+	if err != nil {
+		return nil, err
+	}
+	return kvTarget{
+		auditID: auditID(namespaceKind, opts.ServiceZone,
+			opts.ServiceEnvironment, namespace.M3DBNodeNamespacesKey),
+		store:      store,
+		key:        namespace.M3DBNodeNamespacesKey,
+		newFn:      func() proto.Message { return &nsproto.Registry{} },
+		validateFn: validateNamespaces,
+	}, nil
+}
+
+func (o HandlerOptions) topicTarget(r *http.Request) (target, error) {
+	name := topic.DefaultTopicName
+	if v := strings.TrimSpace(r.Header.Get(topic.HeaderTopicName)); v != "" {
+		name = v
+	}
+	store, err := o.clusterClient.Store(kv.NewOverrideOptions().
+		SetNamespace(topicNamespace))
+	if err != nil {
+		return nil, err
+	}
+	return kvTarget{
+		auditID:    auditID(topicKind, name),
+		store:      store,
+		key:        name,
+		newFn:      func() proto.Message { return &topicpb.Topic{} },
+		validateFn: validateTopic,
+	}, nil
+}
+
+func (o HandlerOptions) rulesetTarget(r *http.Request) (target, error) {
+	ns := mux.Vars(r)[rulesetNamespaceVar]
+	if ns == "" {
+		return nil, errNoRulesetNamespace
+	}
+	store, err := o.clusterClient.KV()
+	if err != nil {
+		return nil, err
+	}
+	key := matcher.NewOptions().RuleSetKeyFn()([]byte(ns))
+	return kvTarget{
+		auditID:    auditID(rulesetKind, ns),
+		store:      store,
+		key:        key,
+		newFn:      func() proto.Message { return &rulepb.RuleSet{} },
+		validateFn: validateRuleset,
+	}, nil
+}
+
+// validateNamespaces checks that a namespace registry converts to valid
+// namespace metadata, as the namespace handlers require before writing one.
+func validateNamespaces(v kv.Value) error {
+	var registry nsproto.Registry
+	if err := v.Unmarshal(&registry); err != nil {
+		return err
+	}
+	_, err := dbnamespace.FromProto(registry)
+	return err
+}
+
+// validateTopic checks a topic the way the topic service does before writing
+// one.
+func validateTopic(v kv.Value) error {
+	var pb topicpb.Topic
+	if err := v.Unmarshal(&pb); err != nil {
+		return err
+	}
+	t, err := msgtopic.NewTopicFromProto(&pb)
+	if err != nil {
+		return err
+	}
+	return t.Validate()
+}
+
+// validateRuleset checks that a ruleset parses into rules.
+func validateRuleset(v kv.Value) error {
+	var pb rulepb.RuleSet
+	if err := v.Unmarshal(&pb); err != nil {
+		return err
+	}
+	_, err := rules.NewRuleSetFromProto(v.Version(), &pb, rules.NewOptions())
+	return err
+}
+
+// log returns the audit log, which is kept in the default kv store so that
+// changes to all managed keys are recorded in one place.
+func (o HandlerOptions) log() (kvaudit.Log, error) {
+	store, err := o.clusterClient.KV()
+	if err != nil {
+		return nil, err
+	}
+	return kvaudit.NewLog(store, o.logOptions())
+}
+
+func (o HandlerOptions) logOptions() kvaudit.Options {
+	return kvaudit.NewOptions().
+		SetNowFn(o.nowFn).
+		SetInstrumentOptions(o.instrumentOptions)
+}
+
+// requestAuthor returns the author to record for changes made by a request,
+// which is its authenticated identity if it has one and the author header
+// otherwise.
+func requestAuthor(r *http.Request) string {
+	if author := authenticatedAuthor(r); author != "" {
+		return author
+	}
+	return r.Header.Get(AuthorHeader)
+}
+
+// authenticatedAuthor returns the common name of the verified client
+// certificate a request was made with, or the empty string if there is none.
+func authenticatedAuthor(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+		len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
+
+func auditID(kind string, parts ...string) string {
+	return path.Join(append([]string{kind}, parts...)...)
+}
+
+// RegisterRoutes registers the audit history and rollback routes.
+func RegisterRoutes(r *mux.Router, opts HandlerOptions) {
+	wrapped := func(n http.Handler) http.Handler {
+		return logging.WithResponseTimeAndPanicErrorLogging(n, opts.instrumentOptions)
+	}
+	register := func(prefix string, fn targetFn) {
+		r.HandleFunc(path.Join(prefix, historyPathName),
+			wrapped(NewHistoryHandler(opts, fn)).ServeHTTP).
+			Methods(HistoryHTTPMethod)
+		r.HandleFunc(path.Join(prefix, rollbackPathName),
+			wrapped(NewRollbackHandler(opts, fn)).ServeHTTP).
+			Methods(RollbackHTTPMethod)
+	}
+
+	register(placement.M3DBGetURL,
+		opts.placementTargetFn(handleroptions.M3DBServiceName))
+	register(placement.M3AggGetURL,
+		opts.placementTargetFn(handleroptions.M3AggregatorServiceName))
+	register(placement.M3CoordinatorGetURL,
+		opts.placementTargetFn(handleroptions.M3CoordinatorServiceName))
+	register(namespace.M3DBGetURL, opts.namespaceTarget)
+	register(topic.GetURL, opts.topicTarget)
+	register(RulesetURL, opts.rulesetTarget)
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	kvaudit "github.com/m3db/m3/src/cluster/kv/audit"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"go.uber.org/zap"
+)
+
+const (
+	// HistoryHTTPMethod is the HTTP method used with the history resources.
+	HistoryHTTPMethod = http.MethodGet
+
+	fromParam = "from"
+	toParam   = "to"
+
+	defaultHistoryVersions = 10
+	diffContextLines       = 3
+)
+
+var (
+	errInvalidHistoryRange = errors.New("from must be at least 1 and not after to")
+)
+
+// HistoryResponse is the version history of a managed value.
+type HistoryResponse struct {
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// HistoryEntry is a single version of a managed value, along with who
+// changed it, why and how it differs from the version before it.
+type HistoryEntry struct {
+	Version         int             `json:"version"`
+	Author          string          `json:"author,omitempty"`
+	Reason          string          `json:"reason,omitempty"`
+	Timestamp       string          `json:"timestamp,omitempty"`
+	RollbackVersion int             `json:"rollbackVersion,omitempty"`
+	Value           json.RawMessage `json:"value"`
+	Diff            []string        `json:"diff,omitempty"`
+}
+
+// HistoryHandler is the handler for the version history of a managed value.
+type HistoryHandler struct {
+	HandlerOptions
+
+	targetFn targetFn
+}
+
+// NewHistoryHandler returns a new HistoryHandler.
+func NewHistoryHandler(opts HandlerOptions, fn targetFn) *HistoryHandler {
+	return &HistoryHandler{HandlerOptions: opts, targetFn: fn}
+}
+
+func (h *HistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	t, err := h.targetFn(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	current, err := t.version()
+	if err != nil {
+		logger.Error("unable to get current version", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	if current == 0 {
+		xhttp.Error(w, kv.ErrNotFound, http.StatusNotFound)
+		return
+	}
+
+	from, to, pErr := parseHistoryRange(r, current)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	log, err := h.log()
+	if err != nil {
+		logger.Error("unable to get audit log", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := history(t, log, current, from, to)
+	if err != nil {
+		logger.Error("unable to get history", zap.String("id", t.id()), zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+// parseHistoryRange returns the inclusive range of versions requested,
+// defaulting to the most recent versions.
+func parseHistoryRange(r *http.Request, current int) (int, int, *xhttp.ParseError) {
+	to := current
+	if v := r.URL.Query().Get(toParam); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+		if parsed < to {
+			to = parsed
+		}
+	}
+
+	from := to - defaultHistoryVersions + 1
+	if from < 1 {
+		from = 1
+	}
+	if v := r.URL.Query().Get(fromParam); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, xhttp.NewParseError(err, http.StatusBadRequest)
+		}
+		from = parsed
+	}
+
+	if from < 1 || from > to {
+		return 0, 0, xhttp.NewParseError(errInvalidHistoryRange, http.StatusBadRequest)
+	}
+	return from, to, nil
+}
+
+func history(
+	t target,
+	log kvaudit.Log,
+	current, from, to int,
+) (HistoryResponse, error) {
+	resp := HistoryResponse{
+		ID:      t.id(),
+		Version: current,
+		Entries: make([]HistoryEntry, 0, to-from+1),
+	}
+
+	var prev string
+	if from > 1 {
+		m, err := t.value(from - 1)
+		if err != nil {
+			return HistoryResponse{}, err
+		}
+		if prev, err = marshalValue(m); err != nil {
+			return HistoryResponse{}, err
+		}
+	}
+
+	for version := from; version <= to; version++ {
+		m, err := t.value(version)
+		if err != nil {
+			return HistoryResponse{}, err
+		}
+		value, err := marshalValue(m)
+		if err != nil {
+			return HistoryResponse{}, err
+		}
+
+		entry := HistoryEntry{
+			Version: version,
+			Value:   json.RawMessage(value),
+			Diff:    lineDiff(prev, value),
+		}
+		e, err := log.Envelope(t.id(), version)
+		switch err {
+		case nil:
+			entry.Author = e.Author
+			entry.Reason = e.Reason
+			entry.RollbackVersion = int(e.RollbackVersion)
+			if e.TimestampNanos != 0 {
+				entry.Timestamp = time.Unix(0, e.TimestampNanos).UTC().Format(time.RFC3339Nano)
+			}
+		case kv.ErrNotFound:
+			// The version was written without going through an audited endpoint.
+		default:
+			return HistoryResponse{}, err
+		}
+
+		resp.Entries = append(resp.Entries, entry)
+		prev = value
+	}
+	return resp, nil
+}
+
+func marshalValue(m proto.Message) (string, error) {
+	return (&jsonpb.Marshaler{Indent: "  "}).MarshalToString(m)
+}
+
+// lineDiff returns the lines changed between two values prefixed with "+" or
+// "-", surrounded by a few unchanged lines prefixed with a space. Elided
+// unchanged lines are replaced with "...".
+func lineDiff(from, to string) []string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(from, to)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	var result []string
+	for i, d := range diffs {
+		if d.Text == "" {
+			continue
+		}
+		textLines := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			result = appendPrefixed(result, "+", textLines)
+		case diffmatchpatch.DiffDelete:
+			result = appendPrefixed(result, "-", textLines)
+		case diffmatchpatch.DiffEqual:
+			var (
+				first = i == 0
+				last  = i == len(diffs)-1
+			)
+			switch {
+			case first && last:
+				// The values are identical.
+			case first:
+				if len(textLines) > diffContextLines {
+					result = append(result, "...")
+					textLines = textLines[len(textLines)-diffContextLines:]
+				}
+				result = appendPrefixed(result, " ", textLines)
+			case last:
+				if len(textLines) > diffContextLines {
+					result = appendPrefixed(result, " ", textLines[:diffContextLines])
+					result = append(result, "...")
+				} else {
+					result = appendPrefixed(result, " ", textLines)
+				}
+			case len(textLines) > 2*diffContextLines:
+				result = appendPrefixed(result, " ", textLines[:diffContextLines])
+				result = append(result, "...")
+				result = appendPrefixed(result, " ", textLines[len(textLines)-diffContextLines:])
+			default:
+				result = appendPrefixed(result, " ", textLines)
+			}
+		}
+	}
+	return result
+}
+
+func appendPrefixed(result []string, prefix string, lines []string) []string {
+	for _, l := range lines {
+		result = append(result, prefix+l)
+	}
+	return result
+}
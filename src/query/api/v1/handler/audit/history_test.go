@@ -0,0 +1,204 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/service"
+	"github.com/m3db/m3/src/cluster/placement/storage"
+	"github.com/m3db/m3/src/cluster/services"
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/metrics/generated/proto/rulepb"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Unix(1600000000, 0)
+
+type testSetup struct {
+	opts           HandlerOptions
+	router         *mux.Router
+	store          kv.Store
+	placementStore kv.Store
+}
+
+func newTestSetup(t *testing.T, ctrl *gomock.Controller) testSetup {
+	var (
+		store          = mem.NewStore()
+		placementStore = mem.NewStore()
+		mockClient     = client.NewMockClient(ctrl)
+		mockServices   = services.NewMockServices(ctrl)
+	)
+	mockClient.EXPECT().Store(gomock.Any()).Return(store, nil).AnyTimes()
+	mockClient.EXPECT().KV().Return(store, nil).AnyTimes()
+	mockClient.EXPECT().Services(gomock.Any()).Return(mockServices, nil).AnyTimes()
+	mockServices.EXPECT().PlacementService(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, opts placement.Options) (placement.Service, error) {
+			return service.NewPlacementService(
+				storage.NewPlacementStorage(placementStore, "", opts), opts), nil
+		},
+	).AnyTimes()
+
+	opts, err := NewHandlerOptions(mockClient, nil, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	opts.nowFn = func() time.Time { return testNow }
+
+	router := mux.NewRouter()
+	RegisterRoutes(router, opts)
+	return testSetup{
+		opts:           opts,
+		router:         router,
+		store:          store,
+		placementStore: placementStore,
+	}
+}
+
+func (s testSetup) serve(t *testing.T, r *http.Request, resp interface{}) int {
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	if resp != nil && w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	}
+	return w.Code
+}
+
+func testRegistry(ids ...string) *nsproto.Registry {
+	reg := &nsproto.Registry{Namespaces: make(map[string]*nsproto.NamespaceOptions)}
+	for _, id := range ids {
+		reg.Namespaces[id] = &nsproto.NamespaceOptions{BootstrapEnabled: true}
+	}
+	return reg
+}
+
+func TestNamespaceHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestSetup(t, ctrl)
+	historyURL := path.Join(namespace.M3DBGetURL, historyPathName)
+
+	// No namespaces set yet.
+	req := httptest.NewRequest(HistoryHTTPMethod, historyURL, nil)
+	require.Equal(t, http.StatusNotFound, s.serve(t, req, nil))
+
+	_, err := s.store.Set(namespace.M3DBNodeNamespacesKey, testRegistry("a"))
+	require.NoError(t, err)
+	_, err = s.store.Set(namespace.M3DBNodeNamespacesKey, testRegistry("a", "b"))
+	require.NoError(t, err)
+
+	log, err := s.opts.log()
+	require.NoError(t, err)
+	id := auditID(namespaceKind, handleroptions.DefaultServiceZone,
+		handleroptions.DefaultServiceEnvironment, namespace.M3DBNodeNamespacesKey)
+	require.NoError(t, log.Record(id, 2, &auditpb.Envelope{
+		Author: "alice",
+		Reason: "add namespace b",
+	}))
+
+	var resp HistoryResponse
+	req = httptest.NewRequest(HistoryHTTPMethod, historyURL, nil)
+	require.Equal(t, http.StatusOK, s.serve(t, req, &resp))
+	require.Equal(t, id, resp.ID)
+	require.Equal(t, 2, resp.Version)
+	require.Equal(t, 2, len(resp.Entries))
+
+	first, second := resp.Entries[0], resp.Entries[1]
+	require.Equal(t, 1, first.Version)
+	require.Equal(t, "", first.Author)
+	require.NotEmpty(t, first.Diff)
+	require.Equal(t, 2, second.Version)
+	require.Equal(t, "alice", second.Author)
+	require.Equal(t, "add namespace b", second.Reason)
+	require.Equal(t, testNow.UTC().Format(time.RFC3339Nano), second.Timestamp)
+	require.Contains(t, second.Diff, `+    "b": {`)
+
+	var reg nsproto.Registry
+	require.NoError(t, json.Unmarshal(second.Value, &reg))
+	require.Equal(t, 2, len(reg.Namespaces))
+
+	// Only the requested range is returned, diffed against the version before it.
+	resp = HistoryResponse{}
+	req = httptest.NewRequest(HistoryHTTPMethod, historyURL+"?from=2", nil)
+	require.Equal(t, http.StatusOK, s.serve(t, req, &resp))
+	require.Equal(t, 1, len(resp.Entries))
+	require.Equal(t, second.Diff, resp.Entries[0].Diff)
+
+	req = httptest.NewRequest(HistoryHTTPMethod, historyURL+"?from=2&to=1", nil)
+	require.Equal(t, http.StatusBadRequest, s.serve(t, req, nil))
+	req = httptest.NewRequest(HistoryHTTPMethod, historyURL+"?from=x", nil)
+	require.Equal(t, http.StatusBadRequest, s.serve(t, req, nil))
+}
+
+func TestRulesetHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestSetup(t, ctrl)
+	for _, updatedAt := range []int64{1, 2, 3} {
+		_, err := s.store.Set("/ruleset/foo", &rulepb.RuleSet{
+			Namespace:          "foo",
+			LastUpdatedAtNanos: updatedAt,
+		})
+		require.NoError(t, err)
+	}
+
+	var resp HistoryResponse
+	req := httptest.NewRequest(HistoryHTTPMethod,
+		"/api/v1/ruleset/foo/history?to=2", nil)
+	require.Equal(t, http.StatusOK, s.serve(t, req, &resp))
+	require.Equal(t, "ruleset/foo", resp.ID)
+	require.Equal(t, 3, resp.Version)
+	require.Equal(t, 2, len(resp.Entries))
+	require.Equal(t, 2, resp.Entries[1].Version)
+
+	req = httptest.NewRequest(HistoryHTTPMethod, "/api/v1/ruleset/bar/history", nil)
+	require.Equal(t, http.StatusNotFound, s.serve(t, req, nil))
+}
+
+func TestLineDiff(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14"
+	to := "1\n2\n3\n4\n5\nX\n7\n8\n9\n10\n11\n12\n13\nY"
+	require.Equal(t, []string{
+		"...", " 3", " 4", " 5",
+		"-6", "+X",
+		" 7", " 8", " 9", "...", " 11", " 12", " 13",
+		"-14", "+Y",
+	}, lineDiff(from, to))
+
+	require.Nil(t, lineDiff(from, from))
+	require.Equal(t, []string{"+a", "+b"}, lineDiff("", "a\nb"))
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	kvaudit "github.com/m3db/m3/src/cluster/kv/audit"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"go.uber.org/zap"
+)
+
+// recordingHandler records who changed a managed value and why for requests
+// that change it.
+type recordingHandler struct {
+	HandlerOptions
+
+	next http.Handler
+
+	sync.Mutex
+	auditLog kvaudit.Log
+}
+
+// NewRecordingHandler returns a handler that serves requests that may change
+// managed values with a cluster client scoped to the request. The client
+// records an audit envelope for every version of a managed value the request
+// writes, at the version the write returned. The recorded author is the
+// authenticated identity of the request where there is one, and the author
+// header otherwise.
+func NewRecordingHandler(next http.Handler, opts HandlerOptions) http.Handler {
+	return &recordingHandler{HandlerOptions: opts, next: next}
+}
+
+func (h *recordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	log, err := h.getLog()
+	if err != nil {
+		logger := logging.WithContext(r.Context(), h.instrumentOptions)
+		logger.Warn("unable to get audit log", zap.Error(err))
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	client := newRecordingClient(h.clusterClient, log, &auditpb.Envelope{
+		Author: requestAuthor(r),
+		Reason: r.Header.Get(ReasonHeader),
+	}, h.logOptions())
+	ctx := handleroptions.NewClusterClientContext(r.Context(), client)
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (h *recordingHandler) getLog() (kvaudit.Log, error) {
+	h.Lock()
+	defer h.Unlock()
+	if h.auditLog != nil {
+		return h.auditLog, nil
+	}
+	log, err := h.log()
+	if err != nil {
+		return nil, err
+	}
+	h.auditLog = log
+	return log, nil
+}
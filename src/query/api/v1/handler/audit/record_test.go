@@ -0,0 +1,188 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	placementhandler "github.com/m3db/m3/src/query/api/v1/handler/placement"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestSetup(t, ctrl)
+	var (
+		scoped bool
+		next   = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := handleroptions.ClusterClient(r.Context(), nil)
+			scoped = client != nil
+			if !scoped {
+				return
+			}
+			store, err := client.Store(kv.NewOverrideOptions())
+			require.NoError(t, err)
+
+			// A concurrent write by another client is not attributed to the
+			// request.
+			_, err = s.store.Set(namespace.M3DBNodeNamespacesKey, testRegistry("a"))
+			require.NoError(t, err)
+			_, err = store.Set(namespace.M3DBNodeNamespacesKey, testRegistry("a", "b"))
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		h  = NewRecordingHandler(next, s.opts)
+		id = auditID(namespaceKind, handleroptions.DefaultServiceZone,
+			handleroptions.DefaultServiceEnvironment, namespace.M3DBNodeNamespacesKey)
+	)
+	log, err := s.opts.log()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, namespace.M3DBAddURL, nil)
+	req.Header.Set(AuthorHeader, "carol")
+	req.Header.Set(ReasonHeader, "new namespace")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.True(t, scoped)
+
+	_, err = log.Envelope(id, 1)
+	require.Equal(t, kv.ErrNotFound, err)
+
+	// The write is recorded even though the request failed afterwards.
+	e, err := log.Envelope(id, 2)
+	require.NoError(t, err)
+	require.Equal(t, "carol", e.Author)
+	require.Equal(t, "new namespace", e.Reason)
+	require.Equal(t, testNow.UnixNano(), e.TimestampNanos)
+
+	// Reads are served with the handlers' own client.
+	h.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, namespace.M3DBGetURL, nil))
+	require.False(t, scoped)
+}
+
+func TestRecordingHandlerPlacement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		s   = newTestSetup(t, ctrl)
+		sid = services.NewServiceID().
+			SetName(handleroptions.M3CoordinatorServiceName).
+			SetEnvironment(handleroptions.DefaultServiceEnvironment).
+			SetZone(handleroptions.DefaultServiceZone)
+		opts = placement.NewOptions().SetIsSharded(false)
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := handleroptions.ClusterClient(r.Context(), nil)
+			cs, err := client.Services(services.NewOverrideOptions())
+			require.NoError(t, err)
+
+			// Dry runs write nothing to record.
+			ps, err := cs.PlacementService(sid, opts.SetDryrun(true))
+			require.NoError(t, err)
+			_, ok := ps.(*recordingPlacementService)
+			require.False(t, ok)
+
+			ps, err = cs.PlacementService(sid, opts)
+			require.NoError(t, err)
+			p, err := placement.NewPlacementFromProto(&placementpb.Placement{
+				Instances: map[string]*placementpb.Instance{},
+			})
+			require.NoError(t, err)
+			instances, err := placementhandler.ConvertInstancesProto([]*placementpb.Instance{
+				{Id: "host1", IsolationGroup: "rack1", Zone: "test", Weight: 1, Endpoint: "host1:1234"},
+			})
+			require.NoError(t, err)
+			_, err = ps.SetIfNotExist(p.SetInstances(instances))
+			require.NoError(t, err)
+		})
+	)
+	req := httptest.NewRequest(http.MethodPost, placementhandler.M3CoordinatorGetURL, nil)
+	req.Header.Set(AuthorHeader, "dave")
+	NewRecordingHandler(next, s.opts).ServeHTTP(httptest.NewRecorder(), req)
+
+	log, err := s.opts.log()
+	require.NoError(t, err)
+	e, err := log.Envelope(auditID(placementKind, sid.Zone(), sid.Environment(),
+		sid.Name()), 1)
+	require.NoError(t, err)
+	require.Equal(t, "dave", e.Author)
+}
+
+func TestRequestAuthor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, namespace.M3DBAddURL, nil)
+	req.Header.Set(AuthorHeader, "mallory")
+	require.Equal(t, "mallory", requestAuthor(req))
+
+	// The authenticated identity takes precedence over the header.
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{
+			{{Subject: pkix.Name{CommonName: "alice"}}},
+		},
+	}
+	require.Equal(t, "alice", requestAuthor(req))
+}
+
+func TestStoreIDFn(t *testing.T) {
+	key := namespace.M3DBNodeNamespacesKey
+	for _, test := range []struct {
+		opts     kv.OverrideOptions
+		key      string
+		expected string
+	}{
+		{
+			opts: kv.NewOverrideOptions(),
+			key:  key,
+			expected: auditID(namespaceKind, handleroptions.DefaultServiceZone,
+				handleroptions.DefaultServiceEnvironment, key),
+		},
+		{
+			opts:     kv.NewOverrideOptions().SetZone("z").SetEnvironment("e"),
+			key:      key,
+			expected: auditID(namespaceKind, "z", "e", key),
+		},
+		{
+			opts:     kv.NewOverrideOptions().SetNamespace(topicNamespace),
+			key:      "foo",
+			expected: auditID(topicKind, "foo"),
+		},
+		{
+			opts: kv.NewOverrideOptions(),
+			key:  "foo",
+		},
+	} {
+		require.Equal(t, test.expected, storeIDFn(test.opts)(test.key))
+	}
+}
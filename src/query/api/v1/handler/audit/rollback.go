@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/auditpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	kvaudit "github.com/m3db/m3/src/cluster/kv/audit"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// RollbackHTTPMethod is the HTTP method used with the rollback resources.
+	RollbackHTTPMethod = http.MethodPost
+)
+
+// RollbackRequest is the request to roll a managed value back to a previous
+// version. The author and reason default to the change headers, and the
+// author is always the authenticated identity of the request if it has one.
+type RollbackRequest struct {
+	Version int    `json:"version"`
+	Author  string `json:"author"`
+	Reason  string `json:"reason"`
+}
+
+// RollbackResponse is the response of a rollback.
+type RollbackResponse struct {
+	ID           string `json:"id"`
+	Version      int    `json:"version"`
+	RolledBackTo int    `json:"rolledBackTo"`
+}
+
+// RollbackHandler is the handler for rolling back a managed value.
+type RollbackHandler struct {
+	HandlerOptions
+
+	targetFn targetFn
+}
+
+// NewRollbackHandler returns a new RollbackHandler.
+func NewRollbackHandler(opts HandlerOptions, fn targetFn) *RollbackHandler {
+	return &RollbackHandler{HandlerOptions: opts, targetFn: fn}
+}
+
+func (h *RollbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	req, pErr := parseRollbackRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	t, err := h.targetFn(r)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	log, err := h.log()
+	if err != nil {
+		logger.Error("unable to get audit log", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	version, err := t.rollback(req.Version)
+	switch {
+	case err == nil:
+	case err == kvaudit.ErrInvalidRollbackVersion, xerrors.IsInvalidParams(err):
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	case err == kv.ErrNotFound:
+		xhttp.Error(w, err, http.StatusNotFound)
+		return
+	case err == kv.ErrVersionMismatch:
+		xhttp.Error(w, err, http.StatusConflict)
+		return
+	default:
+		logger.Error("unable to roll back",
+			zap.String("id", t.id()), zap.Int("version", req.Version), zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	// The rollback is already applied, so failing to record it is not
+	// surfaced to the caller.
+	if err := log.Record(t.id(), version, &auditpb.Envelope{
+		Author:          req.Author,
+		Reason:          req.Reason,
+		RollbackVersion: int32(req.Version),
+	}); err != nil {
+		logger.Warn("unable to record rollback",
+			zap.String("id", t.id()), zap.Int("version", version), zap.Error(err))
+	}
+
+	xhttp.WriteJSONResponse(w, RollbackResponse{
+		ID:           t.id(),
+		Version:      version,
+		RolledBackTo: req.Version,
+	}, logger)
+}
+
+func parseRollbackRequest(r *http.Request) (*RollbackRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	var req RollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	if req.Version < 1 {
+		return nil, xhttp.NewParseError(kvaudit.ErrInvalidRollbackVersion, http.StatusBadRequest)
+	}
+	if author := authenticatedAuthor(r); author != "" {
+		req.Author = author
+	} else if req.Author == "" {
+		req.Author = r.Header.Get(AuthorHeader)
+	}
+	if req.Reason == "" {
+		req.Reason = r.Header.Get(ReasonHeader)
+	}
+	return &req, nil
+}
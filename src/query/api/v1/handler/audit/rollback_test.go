@@ -0,0 +1,160 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/service"
+	"github.com/m3db/m3/src/cluster/placement/storage"
+	"github.com/m3db/m3/src/msg/generated/proto/topicpb"
+	placementhandler "github.com/m3db/m3/src/query/api/v1/handler/placement"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/handler/topic"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicRollbackHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestSetup(t, ctrl)
+	for _, numShards := range []uint32{1024, 2048} {
+		_, err := s.store.Set(topic.DefaultTopicName, &topicpb.Topic{
+			Name:           topic.DefaultTopicName,
+			NumberOfShards: numShards,
+		})
+		require.NoError(t, err)
+	}
+
+	rollbackURL := path.Join(topic.GetURL, rollbackPathName)
+	req := httptest.NewRequest(RollbackHTTPMethod, rollbackURL,
+		strings.NewReader(`{"version":1,"reason":"shard count change"}`))
+	req.Header.Set(AuthorHeader, "bob")
+
+	var resp RollbackResponse
+	require.Equal(t, http.StatusOK, s.serve(t, req, &resp))
+	require.Equal(t, RollbackResponse{
+		ID:           "topic/aggregated_metrics",
+		Version:      3,
+		RolledBackTo: 1,
+	}, resp)
+
+	v, err := s.store.Get(topic.DefaultTopicName)
+	require.NoError(t, err)
+	require.Equal(t, 3, v.Version())
+	var tp topicpb.Topic
+	require.NoError(t, v.Unmarshal(&tp))
+	require.Equal(t, uint32(1024), tp.NumberOfShards)
+
+	log, err := s.opts.log()
+	require.NoError(t, err)
+	e, err := log.Envelope(resp.ID, 3)
+	require.NoError(t, err)
+	require.Equal(t, "bob", e.Author)
+	require.Equal(t, "shard count change", e.Reason)
+	require.Equal(t, int32(1), e.RollbackVersion)
+	require.Equal(t, testNow.UnixNano(), e.TimestampNanos)
+
+	// Rolling back to the current version or later is rejected.
+	for _, body := range []string{`{"version":3}`, `{"version":0}`, `{`} {
+		req = httptest.NewRequest(RollbackHTTPMethod, rollbackURL, strings.NewReader(body))
+		require.Equal(t, http.StatusBadRequest, s.serve(t, req, nil), body)
+	}
+
+	req = httptest.NewRequest(RollbackHTTPMethod, rollbackURL, strings.NewReader(`{"version":1}`))
+	req.Header.Set(topic.HeaderTopicName, "other")
+	require.Equal(t, http.StatusNotFound, s.serve(t, req, nil))
+
+	// Invalid topics are not rolled back to.
+	for _, numShards := range []uint32{0, 1024} {
+		_, err := s.store.Set(topic.DefaultTopicName, &topicpb.Topic{
+			Name:           topic.DefaultTopicName,
+			NumberOfShards: numShards,
+		})
+		require.NoError(t, err)
+	}
+	req = httptest.NewRequest(RollbackHTTPMethod, rollbackURL, strings.NewReader(`{"version":4}`))
+	require.Equal(t, http.StatusBadRequest, s.serve(t, req, nil))
+	v, err = s.store.Get(topic.DefaultTopicName)
+	require.NoError(t, err)
+	require.Equal(t, 5, v.Version())
+}
+
+func TestPlacementRollbackHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestSetup(t, ctrl)
+	opts := placement.NewOptions().SetIsSharded(false)
+	ps := service.NewPlacementService(
+		storage.NewPlacementStorage(s.placementStore, "", opts), opts)
+
+	instances := []*placementpb.Instance{
+		{Id: "host1", IsolationGroup: "rack1", Zone: "test", Weight: 1, Endpoint: "host1:1234"},
+		{Id: "host2", IsolationGroup: "rack2", Zone: "test", Weight: 1, Endpoint: "host2:1234"},
+	}
+	for i := 1; i <= len(instances); i++ {
+		p, err := placement.NewPlacementFromProto(&placementpb.Placement{
+			Instances: map[string]*placementpb.Instance{},
+		})
+		require.NoError(t, err)
+		converted, err := placementhandler.ConvertInstancesProto(instances[:i])
+		require.NoError(t, err)
+		_, err = ps.Set(p.SetInstances(converted))
+		require.NoError(t, err)
+	}
+
+	rollbackURL := path.Join(placementhandler.M3CoordinatorGetURL, rollbackPathName)
+	req := httptest.NewRequest(RollbackHTTPMethod, rollbackURL,
+		strings.NewReader(`{"version":1}`))
+
+	var resp RollbackResponse
+	require.Equal(t, http.StatusOK, s.serve(t, req, &resp))
+	require.Equal(t, auditID(placementKind, handleroptions.DefaultServiceZone,
+		handleroptions.DefaultServiceEnvironment,
+		handleroptions.M3CoordinatorServiceName), resp.ID)
+	require.Equal(t, 3, resp.Version)
+
+	p, err := ps.Placement()
+	require.NoError(t, err)
+	require.Equal(t, 3, p.Version())
+	require.Equal(t, 1, p.NumInstances())
+	_, ok := p.Instance("host1")
+	require.True(t, ok)
+
+	// The history endpoint shows the rollback.
+	var history HistoryResponse
+	req = httptest.NewRequest(HistoryHTTPMethod,
+		path.Join(placementhandler.M3CoordinatorGetURL, historyPathName), nil)
+	require.Equal(t, http.StatusOK, s.serve(t, req, &history))
+	require.Equal(t, 3, len(history.Entries))
+	require.Equal(t, 1, history.Entries[2].RollbackVersion)
+	require.Contains(t, history.Entries[2].Diff, `-    "host2": {`)
+}
@@ -130,9 +130,9 @@ var (
 type dbType string
 
 type createHandler struct {
+	client                 clusterclient.Client
 	placementInitHandler   *placement.InitHandler
 	placementGetHandler    *placement.GetHandler
-	namespaceAddHandler    *namespace.AddHandler
 	namespaceGetHandler    *namespace.GetHandler
 	namespaceDeleteHandler *namespace.DeleteHandler
 	embeddedDbCfg          *dbconfig.DBConfiguration
@@ -154,9 +154,9 @@ func NewCreateHandler(
 		return nil, err
 	}
 	return &createHandler{
+		client:                 client,
 		placementInitHandler:   placement.NewInitHandler(placementHandlerOptions),
 		placementGetHandler:    placement.NewGetHandler(placementHandlerOptions),
-		namespaceAddHandler:    namespace.NewAddHandler(client, instrumentOpts),
 		namespaceGetHandler:    namespace.NewGetHandler(client, instrumentOpts),
 		namespaceDeleteHandler: namespace.NewDeleteHandler(client, instrumentOpts),
 		embeddedDbCfg:          embeddedDbCfg,
@@ -223,7 +223,10 @@ func (h *createHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	opts := handleroptions.NewServiceOptions(h.serviceNameAndDefaults(),
 		r.Header, nil)
-	nsRegistry, err = h.namespaceAddHandler.Add(namespaceRequest, opts)
+	// Add the namespace with the cluster client the request is scoped to.
+	namespaceAddHandler := namespace.NewAddHandler(
+		handleroptions.ClusterClient(ctx, h.client), h.instrumentOpts)
+	nsRegistry, err = namespaceAddHandler.Add(namespaceRequest, opts)
 	if err != nil {
 		logger.Error("unable to add namespace", zap.Error(err))
 		xhttp.Error(w, err, http.StatusInternalServerError)
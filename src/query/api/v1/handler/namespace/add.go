@@ -86,7 +86,8 @@ func (h *AddHandler) ServeHTTP(
 	}
 
 	opts := handleroptions.NewServiceOptions(svc, r.Header, nil)
-	nsRegistry, err := h.Add(md, opts)
+	scoped := AddHandler(Handler(*h).forRequest(r))
+	nsRegistry, err := scoped.Add(md, opts)
 	if err != nil {
 		if err == errNamespaceExists {
 			logger.Error("namespace already exists", zap.Error(err))
@@ -68,6 +68,13 @@ type Handler struct {
 	instrumentOpts instrument.Options
 }
 
+// forRequest returns a copy of the handler that serves a request with the
+// cluster client middleware may have scoped to it.
+func (h Handler) forRequest(r *http.Request) Handler {
+	h.client = handleroptions.ClusterClient(r.Context(), h.client)
+	return h
+}
+
 // Metadata returns the current metadata in the given store and its version
 func Metadata(store kv.Store) ([]namespace.Metadata, int, error) {
 	value, err := store.Get(M3DBNodeNamespacesKey)
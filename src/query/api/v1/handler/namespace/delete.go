@@ -89,7 +89,8 @@ func (h *DeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.Delete(id)
+	scoped := DeleteHandler(Handler(*h).forRequest(r))
+	err := scoped.Delete(id)
 	if err != nil {
 		logger.Error("unable to delete namespace", zap.Error(err))
 		if err == errNamespaceNotFound {
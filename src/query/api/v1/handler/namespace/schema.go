@@ -81,7 +81,8 @@ func (h *SchemaHandler) ServeHTTP(
 	}
 
 	opts := handleroptions.NewServiceOptions(svc, r.Header, nil)
-	resp, err := h.Add(md, opts)
+	scoped := SchemaHandler(Handler(*h).forRequest(r))
+	resp, err := scoped.Add(md, opts)
 	if err != nil {
 		if err == kv.ErrNotFound || xerrors.InnerError(err) == kv.ErrNotFound {
 			logger.Error("namespaces metadata key does not exist", zap.Error(err))
@@ -166,7 +167,8 @@ func (h *SchemaResetHandler) ServeHTTP(
 	}
 
 	opts := handleroptions.NewServiceOptions(svc, r.Header, nil)
-	resp, err := h.Reset(md, opts)
+	scoped := SchemaResetHandler(Handler(*h).forRequest(r))
+	resp, err := scoped.Reset(md, opts)
 	if err != nil {
 		if err == kv.ErrNotFound || xerrors.InnerError(err) == kv.ErrNotFound {
 			logger.Error("namespaces metadata key does not exist", zap.Error(err))
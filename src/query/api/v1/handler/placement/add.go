@@ -134,7 +134,7 @@ func (h *AddHandler) Add(
 	if !req.Force {
 		validateFn = validateAllAvailable
 	}
-	service, _, err := ServiceWithAlgo(h.clusterClient, serviceOpts, h.nowFn(), validateFn)
+	service, _, err := ServiceWithAlgo(h.requestClusterClient(httpReq), serviceOpts, h.nowFn(), validateFn)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,203 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/algo"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// BalanceHTTPMethod is the HTTP method for the balance endpoint.
+	BalanceHTTPMethod = http.MethodPost
+
+	balancePathName = "balance"
+
+	// defaultBalanceMaxMoves is the number of shards moved by a balance
+	// request that does not set maxMoves.
+	defaultBalanceMaxMoves = 1
+)
+
+var (
+	// M3DBBalanceURL is the url for the m3db balance handler (method POST).
+	M3DBBalanceURL = path.Join(handler.RoutePrefixV1,
+		M3DBServicePlacementPathName, balancePathName)
+
+	errBalanceNoShardLoads = errors.New("must specify shardLoads")
+)
+
+// BalanceRequest is the request to move shards between the instances of the
+// placement to balance them on the observed load of the shards.
+type BalanceRequest struct {
+	ShardLoads map[uint32]BalanceShardLoad `json:"shardLoads"`
+	MaxMoves   int                         `json:"maxMoves"`
+	Force      bool                        `json:"force"`
+	Confirm    bool                        `json:"confirm"`
+}
+
+// BalanceShardLoad is the observed load of a shard.
+type BalanceShardLoad struct {
+	SeriesCount int64   `json:"seriesCount"`
+	WriteRate   float64 `json:"writeRate"`
+	DiskBytes   int64   `json:"diskBytes"`
+}
+
+// BalanceHandler is the handler for placement balances.
+type BalanceHandler Handler
+
+// NewBalanceHandler returns a new BalanceHandler.
+func NewBalanceHandler(opts HandlerOptions) *BalanceHandler {
+	return &BalanceHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *BalanceHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	req, pErr := h.parseRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	p, dryRun, err := h.Balance(svc, r, req)
+	if err == kv.ErrNotFound {
+		logger.Error("placement not found", zap.Error(err))
+		xhttp.Error(w, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if xerrors.IsInvalidParams(err) {
+			status = http.StatusBadRequest
+		}
+		logger.Error("unable to balance placement", zap.Error(err))
+		xhttp.Error(w, err, status)
+		return
+	}
+
+	placementProto, err := p.Proto()
+	if err != nil {
+		logger.Error("unable to get placement protobuf", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := &admin.PlacementSetResponse{
+		Placement: placementProto,
+		Version:   int32(p.Version()),
+		DryRun:    dryRun,
+	}
+
+	xhttp.WriteProtoMsgJSONResponse(w, resp, logger)
+}
+
+func (h *BalanceHandler) parseRequest(r *http.Request) (*BalanceRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	req := &BalanceRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	if len(req.ShardLoads) == 0 {
+		return nil, xhttp.NewParseError(errBalanceNoShardLoads, http.StatusBadRequest)
+	}
+	if req.MaxMoves <= 0 {
+		req.MaxMoves = defaultBalanceMaxMoves
+	}
+
+	return req, nil
+}
+
+// Balance moves up to the requested number of shards between the instances
+// of the placement to balance them on the observed shard loads, returning
+// the resulting placement and whether it was only previewed. The placement
+// is only updated once confirmed.
+func (h *BalanceHandler) Balance(
+	svc handleroptions.ServiceNameAndDefaults,
+	httpReq *http.Request,
+	req *BalanceRequest,
+) (placement.Placement, bool, error) {
+	serviceOpts := handleroptions.NewServiceOptions(svc,
+		httpReq.Header, h.m3AggServiceOptions)
+	service, _, err := ServiceWithAlgo(h.requestClusterClient(httpReq),
+		serviceOpts, h.nowFn(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	curPlacement, err := service.Placement()
+	if err != nil {
+		return nil, false, err
+	}
+	if !req.Force {
+		if err := validateAllAvailable(curPlacement); err != nil {
+			return nil, false, xerrors.NewInvalidParamsError(err)
+		}
+	}
+
+	loads := make(map[uint32]placement.ShardLoad, len(req.ShardLoads))
+	for id, load := range req.ShardLoads {
+		loads[id] = placement.ShardLoad{
+			SeriesCount: load.SeriesCount,
+			WriteRate:   load.WriteRate,
+			DiskBytes:   load.DiskBytes,
+		}
+	}
+	// Only M3DB placements are balanced, their algorithm options are the
+	// sharded defaults of the service zone.
+	algoOpts := placement.NewOptions().
+		SetValidZone(serviceOpts.ServiceZone).
+		SetIsSharded(true)
+	newPlacement, err := algo.BalanceShardLoad(curPlacement, loads,
+		req.MaxMoves, algoOpts)
+	if err != nil {
+		return nil, false, xerrors.NewInvalidParamsError(err)
+	}
+
+	if !req.Confirm {
+		return newPlacement.SetVersion(curPlacement.Version() + 1), true, nil
+	}
+
+	// Ensure the placement we're updating is still the one that was
+	// balanced.
+	newPlacement, err = service.CheckAndSet(newPlacement, curPlacement.Version())
+	return newPlacement, false, err
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBalanceTestPlacement() placement.Placement {
+	instance := func(id, group string, shardIDs ...uint32) placement.Instance {
+		shards := make([]shard.Shard, 0, len(shardIDs))
+		for _, shardID := range shardIDs {
+			shards = append(shards, shard.NewShard(shardID).SetState(shard.Available))
+		}
+		return placement.NewInstance().
+			SetID(id).
+			SetIsolationGroup(group).
+			SetZone("z1").
+			SetWeight(1).
+			SetShards(shard.NewShards(shards))
+	}
+	return placement.NewPlacement().
+		SetInstances([]placement.Instance{
+			instance("A", "r1", 0, 1, 2, 3),
+			instance("B", "r2", 4),
+			instance("C", "r3", 5),
+		}).
+		SetShards([]uint32{0, 1, 2, 3, 4, 5}).
+		SetReplicaFactor(1).
+		SetIsSharded(true).
+		SetVersion(3)
+}
+
+func TestPlacementBalanceHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockPlacementService := SetupPlacementTest(t, ctrl)
+	handlerOpts, err := NewHandlerOptions(mockClient, config.Configuration{}, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handler := NewBalanceHandler(handlerOpts)
+	svcDefaults := handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+	}
+
+	serve := func(body string) admin.PlacementSetResponse {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(BalanceHTTPMethod, M3DBBalanceURL, strings.NewReader(body))
+		handler.ServeHTTP(svcDefaults, w, req)
+		resp := w.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result admin.PlacementSetResponse
+		require.NoError(t, jsonpb.Unmarshal(resp.Body, &result))
+		return result
+	}
+	leaving := func(p *placementpb.Placement) int {
+		var n int
+		for _, s := range p.Instances["A"].Shards {
+			if s.State == placementpb.ShardState_LEAVING {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Without confirmation the balanced placement is only previewed, and
+	// a single shard moves by default.
+	mockPlacementService.EXPECT().Placement().Return(newBalanceTestPlacement(), nil)
+	resp := serve(`{"shardLoads": {"0": {"seriesCount": 100}}}`)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int32(4), resp.Version)
+	assert.Equal(t, 1, leaving(resp.Placement))
+
+	mockPlacementService.EXPECT().Placement().Return(newBalanceTestPlacement(), nil)
+	mockPlacementService.EXPECT().CheckAndSet(gomock.Any(), 3).DoAndReturn(
+		func(p placement.Placement, version int) (placement.Placement, error) {
+			return p.SetVersion(version + 1), nil
+		})
+	resp = serve(`{"shardLoads": {"0": {"seriesCount": 100}}, "maxMoves": 2, "confirm": true}`)
+	assert.False(t, resp.DryRun)
+	assert.Equal(t, int32(4), resp.Version)
+	assert.Equal(t, 2, leaving(resp.Placement))
+}
+
+func TestPlacementBalanceHandler_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockPlacementService := SetupPlacementTest(t, ctrl)
+	handlerOpts, err := NewHandlerOptions(mockClient, config.Configuration{}, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handler := NewBalanceHandler(handlerOpts)
+	svcDefaults := handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(BalanceHTTPMethod, M3DBBalanceURL, strings.NewReader(`{}`))
+	handler.ServeHTTP(svcDefaults, w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	// Shards must be available unless forced.
+	initializing := newBalanceTestPlacement()
+	instance, ok := initializing.Instance("B")
+	require.True(t, ok)
+	instance.Shards().Add(shard.NewShard(4).SetState(shard.Initializing))
+	mockPlacementService.EXPECT().Placement().Return(initializing, nil)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(BalanceHTTPMethod, M3DBBalanceURL,
+		strings.NewReader(`{"shardLoads": {"0": {"seriesCount": 100}}}`))
+	handler.ServeHTTP(svcDefaults, w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
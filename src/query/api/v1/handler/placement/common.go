@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/placement/algo"
+	"github.com/m3db/m3/src/cluster/placement/operator"
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
@@ -77,6 +78,7 @@ type HandlerOptions struct {
 
 	m3AggServiceOptions *handleroptions.M3AggServiceOptions
 	instrumentOptions   instrument.Options
+	operators           *operatorRegistry
 }
 
 // NewHandlerOptions is the constructor function for HandlerOptions.
@@ -89,14 +91,31 @@ func NewHandlerOptions(
 	if instrumentOpts == nil {
 		return HandlerOptions{}, errInstrumentOptionsNotSet
 	}
+	var operatorCfg operator.Configuration
+	if cfg.ClusterManagement != nil {
+		operatorCfg = cfg.ClusterManagement.PlacementOperator
+	}
+	operatorOpts := operatorCfg.NewOptions(instrumentOpts.SetMetricsScope(
+		instrumentOpts.MetricsScope().SubScope("placement-operator")))
 	return HandlerOptions{
 		clusterClient:       client,
 		config:              cfg,
 		m3AggServiceOptions: m3AggOpts,
 		instrumentOptions:   instrumentOpts,
+		operators: newOperatorRegistry(operatorOpts,
+			operatorCfg.NewElectionOptions()),
 	}, nil
 }
 
+// requestClusterClient returns the cluster client to serve a request with,
+// which middleware may have scoped to the request.
+func (o HandlerOptions) requestClusterClient(r *http.Request) clusterclient.Client {
+	if r == nil {
+		return o.clusterClient
+	}
+	return handleroptions.ClusterClient(r.Context(), o.clusterClient)
+}
+
 // Handler represents a generic handler for placement endpoints.
 type Handler struct {
 	HandlerOptions
@@ -299,6 +318,35 @@ func RegisterRoutes(
 	r.HandleFunc(M3AggReplaceURL, replaceFn).Methods(ReplaceHTTPMethod)
 	r.HandleFunc(M3CoordinatorReplaceURL, replaceFn).Methods(ReplaceHTTPMethod)
 
+	// Plan
+	var (
+		planHandler = NewPlanHandler(opts)
+		planFn      = applyMiddleware(planHandler.ServeHTTP, defaults, opts.instrumentOptions)
+	)
+	r.HandleFunc(M3DBPlanURL, planFn).Methods(PlanHTTPMethod)
+	r.HandleFunc(M3AggPlanURL, planFn).Methods(PlanHTTPMethod)
+	r.HandleFunc(M3CoordinatorPlanURL, planFn).Methods(PlanHTTPMethod)
+
+	// Balance, only M3DB placements are balanced on shard load.
+	var (
+		balanceHandler = NewBalanceHandler(opts)
+		balanceFn      = applyMiddleware(balanceHandler.ServeHTTP, defaults, opts.instrumentOptions)
+	)
+	r.HandleFunc(M3DBBalanceURL, balanceFn).Methods(BalanceHTTPMethod)
+
+	// Operation, only M3DB instances report bootstrap health.
+	var (
+		operationStartHandler  = NewOperationStartHandler(opts)
+		operationGetHandler    = NewOperationGetHandler(opts)
+		operationCancelHandler = NewOperationCancelHandler(opts)
+		operationStartFn       = applyMiddleware(operationStartHandler.ServeHTTP, defaults, opts.instrumentOptions)
+		operationGetFn         = applyMiddleware(operationGetHandler.ServeHTTP, defaults, opts.instrumentOptions)
+		operationCancelFn      = applyMiddleware(operationCancelHandler.ServeHTTP, defaults, opts.instrumentOptions)
+	)
+	r.HandleFunc(M3DBOperationURL, operationStartFn).Methods(OperationStartHTTPMethod)
+	r.HandleFunc(M3DBOperationURL, operationGetFn).Methods(OperationGetHTTPMethod)
+	r.HandleFunc(M3DBOperationURL, operationCancelFn).Methods(OperationCancelHTTPMethod)
+
 	// Set
 	var (
 		setHandler = NewSetHandler(opts)
@@ -95,7 +95,7 @@ func (h *DeleteHandler) ServeHTTP(
 		opts  = handleroptions.NewServiceOptions(svc, r.Header, h.m3AggServiceOptions)
 	)
 
-	service, algo, err := ServiceWithAlgo(h.clusterClient, opts, h.nowFn(), nil)
+	service, algo, err := ServiceWithAlgo(h.requestClusterClient(r), opts, h.nowFn(), nil)
 	if err != nil {
 		xhttp.Error(w, err, http.StatusInternalServerError)
 		return
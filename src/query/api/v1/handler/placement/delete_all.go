@@ -77,7 +77,7 @@ func (h *DeleteAllHandler) ServeHTTP(
 		opts   = handleroptions.NewServiceOptions(svc, r.Header, h.m3AggServiceOptions)
 	)
 
-	service, err := Service(h.clusterClient, opts, h.nowFn(), nil)
+	service, err := Service(h.requestClusterClient(r), opts, h.nowFn(), nil)
 	if err != nil {
 		xhttp.Error(w, err, http.StatusInternalServerError)
 		return
@@ -121,7 +121,7 @@ func (h *GetHandler) Get(
 	}
 
 	opts := handleroptions.NewServiceOptions(svc, headers, h.m3AggServiceOptions)
-	service, err := Service(h.clusterClient, opts, h.nowFn(), nil)
+	service, err := Service(h.requestClusterClient(httpReq), opts, h.nowFn(), nil)
 	if err != nil {
 		return nil, false, err
 	}
@@ -130,7 +130,7 @@ func (h *InitHandler) Init(
 
 	serviceOpts := handleroptions.NewServiceOptions(svc, httpReq.Header,
 		h.m3AggServiceOptions)
-	service, err := Service(h.clusterClient, serviceOpts, h.nowFn(), nil)
+	service, err := Service(h.requestClusterClient(httpReq), serviceOpts, h.nowFn(), nil)
 	if err != nil {
 		return nil, err
 	}
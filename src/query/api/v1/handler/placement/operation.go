@@ -0,0 +1,370 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement/operator"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// OperationStartHTTPMethod is the HTTP method to start a placement
+	// operation.
+	OperationStartHTTPMethod = http.MethodPost
+
+	// OperationGetHTTPMethod is the HTTP method to get the placement
+	// operation.
+	OperationGetHTTPMethod = http.MethodGet
+
+	// OperationCancelHTTPMethod is the HTTP method to cancel the placement
+	// operation.
+	OperationCancelHTTPMethod = http.MethodDelete
+
+	operationPathName = "operation"
+)
+
+var (
+	// M3DBOperationURL is the url for the m3db placement operation handlers.
+	M3DBOperationURL = path.Join(handler.RoutePrefixV1,
+		M3DBServicePlacementPathName, operationPathName)
+
+	errOperatorsClosed = errors.New("placement operators are closed")
+)
+
+// OperationRequest is the request to start a placement operation.
+type OperationRequest struct {
+	Type        operator.OperationType `json:"type"`
+	Instances   []json.RawMessage      `json:"instances"`
+	InstanceIDs []string               `json:"instanceIds"`
+	BatchSize   int                    `json:"batchSize"`
+}
+
+// operatorRegistry holds the operator of each service so that a service has
+// a single operator running its operations. With election options the
+// operators of a service campaign so that only one coordinator runs its
+// operations.
+type operatorRegistry struct {
+	sync.Mutex
+
+	opts           operator.Options
+	electionOpts   services.ElectionOptions
+	operators      map[string]operator.Operator
+	leaderServices []services.LeaderService
+	closed         bool
+}
+
+func newOperatorRegistry(
+	opts operator.Options,
+	electionOpts services.ElectionOptions,
+) *operatorRegistry {
+	return &operatorRegistry{
+		opts:         opts,
+		electionOpts: electionOpts,
+		operators:    make(map[string]operator.Operator),
+	}
+}
+
+// operator returns the operator of the service, creating it and resuming its
+// stored operation on first use.
+func (r *operatorRegistry) operator(
+	client clusterclient.Client,
+	serviceOpts handleroptions.ServiceOptions,
+	now time.Time,
+) (operator.Operator, error) {
+	key := serviceOpts.ServiceID().String()
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.closed {
+		return nil, errOperatorsClosed
+	}
+	if op, ok := r.operators[key]; ok {
+		return op, nil
+	}
+
+	// The operator must change the placement, never only preview changes.
+	serviceOpts.DryRun = false
+	service, _, err := ServiceWithAlgo(client, serviceOpts, now, nil)
+	if err != nil {
+		return nil, err
+	}
+	store, err := client.Store(kv.NewOverrideOptions().
+		SetEnvironment(serviceOpts.ServiceEnvironment).
+		SetZone(serviceOpts.ServiceZone))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := r.opts.SetKey(r.opts.Key() + "/" + serviceOpts.ServiceName)
+	var leaderService services.LeaderService
+	if r.electionOpts != nil {
+		campaignOpts, err := services.NewCampaignOptions()
+		if err != nil {
+			return nil, err
+		}
+		cs, err := client.Services(services.NewOverrideOptions())
+		if err != nil {
+			return nil, err
+		}
+		leaderService, err = cs.LeaderService(serviceOpts.ServiceID(), r.electionOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.
+			SetLeaderService(leaderService).
+			SetCampaignOptions(campaignOpts)
+	}
+
+	op, err := operator.NewOperator(service, store, opts)
+	if err == nil {
+		if err = op.Resume(); err != nil {
+			op.Close()
+		}
+	}
+	if err != nil {
+		if leaderService != nil {
+			leaderService.Close()
+		}
+		return nil, err
+	}
+
+	r.operators[key] = op
+	if leaderService != nil {
+		r.leaderServices = append(r.leaderServices, leaderService)
+	}
+	return op, nil
+}
+
+// close closes the operators and their leader services, giving up their
+// leadership.
+func (r *operatorRegistry) close() error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.closed {
+		return errOperatorsClosed
+	}
+	r.closed = true
+
+	multiErr := xerrors.NewMultiError()
+	for _, op := range r.operators {
+		multiErr = multiErr.Add(op.Close())
+	}
+	for _, leaderService := range r.leaderServices {
+		multiErr = multiErr.Add(leaderService.Close())
+	}
+	return multiErr.FinalError()
+}
+
+// StartOperators creates the placement operator of the M3DB service, which
+// resumes its stored operation and campaigns to run the operations of the
+// service. It is called on server startup, CloseOperators stops the
+// operators on shutdown.
+func (o HandlerOptions) StartOperators(
+	defaults []handleroptions.ServiceOptionsDefault,
+) error {
+	if o.clusterClient == nil {
+		return nil
+	}
+
+	serviceOpts := handleroptions.NewServiceOptions(
+		handleroptions.ServiceNameAndDefaults{
+			ServiceName: handleroptions.M3DBServiceName,
+			Defaults:    defaults,
+		}, nil, nil)
+	_, err := o.operators.operator(o.clusterClient, serviceOpts, time.Now())
+	return err
+}
+
+// CloseOperators stops the placement operators, their operations are
+// resumed by the operators of other coordinators or on the next startup.
+func (o HandlerOptions) CloseOperators() error {
+	return o.operators.close()
+}
+
+func operationErrorStatus(err error) int {
+	switch {
+	case err == operator.ErrNoOperation:
+		return http.StatusNotFound
+	case err == operator.ErrOperationInProgress,
+		err == operator.ErrOperationNotRunning:
+		return http.StatusConflict
+	case xerrors.IsInvalidParams(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// OperationStartHandler is the handler to start placement operations.
+type OperationStartHandler Handler
+
+// NewOperationStartHandler returns a new OperationStartHandler.
+func NewOperationStartHandler(opts HandlerOptions) *OperationStartHandler {
+	return &OperationStartHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *OperationStartHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	req, pErr := h.parseRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	serviceOpts := handleroptions.NewServiceOptions(svc,
+		r.Header, h.m3AggServiceOptions)
+	op, err := h.operators.operator(h.clusterClient, serviceOpts, h.nowFn())
+	if err != nil {
+		logger.Error("unable to get placement operator", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	operation, err := op.Start(req)
+	if err != nil {
+		logger.Error("unable to start placement operation", zap.Error(err))
+		xhttp.Error(w, err, operationErrorStatus(err))
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, operation, logger)
+}
+
+func (h *OperationStartHandler) parseRequest(
+	r *http.Request,
+) (operator.OperationRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	var req OperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return operator.OperationRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	instancesProto, err := unmarshalInstancesJSON(req.Instances)
+	if err != nil {
+		return operator.OperationRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	instances, err := ConvertInstancesProto(instancesProto)
+	if err != nil {
+		return operator.OperationRequest{}, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	return operator.OperationRequest{
+		Type:        req.Type,
+		Instances:   instances,
+		InstanceIDs: req.InstanceIDs,
+		BatchSize:   req.BatchSize,
+	}, nil
+}
+
+// OperationGetHandler is the handler to get the placement operation.
+type OperationGetHandler Handler
+
+// NewOperationGetHandler returns a new OperationGetHandler.
+func NewOperationGetHandler(opts HandlerOptions) *OperationGetHandler {
+	return &OperationGetHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *OperationGetHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	serviceOpts := handleroptions.NewServiceOptions(svc,
+		r.Header, h.m3AggServiceOptions)
+	op, err := h.operators.operator(h.clusterClient, serviceOpts, h.nowFn())
+	if err != nil {
+		logger.Error("unable to get placement operator", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	operation, err := op.Operation()
+	if err != nil {
+		if err != operator.ErrNoOperation {
+			logger.Error("unable to get placement operation", zap.Error(err))
+		}
+		xhttp.Error(w, err, operationErrorStatus(err))
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, operation, logger)
+}
+
+// OperationCancelHandler is the handler to cancel the placement operation.
+type OperationCancelHandler Handler
+
+// NewOperationCancelHandler returns a new OperationCancelHandler.
+func NewOperationCancelHandler(opts HandlerOptions) *OperationCancelHandler {
+	return &OperationCancelHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *OperationCancelHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	serviceOpts := handleroptions.NewServiceOptions(svc,
+		r.Header, h.m3AggServiceOptions)
+	op, err := h.operators.operator(h.clusterClient, serviceOpts, h.nowFn())
+	if err != nil {
+		logger.Error("unable to get placement operator", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	operation, err := op.Cancel()
+	if err != nil {
+		logger.Error("unable to cancel placement operation", zap.Error(err))
+		xhttp.Error(w, err, operationErrorStatus(err))
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, operation, logger)
+}
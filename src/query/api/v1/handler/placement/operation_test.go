@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement/operator"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceHandler interface {
+	ServeHTTP(svc handleroptions.ServiceNameAndDefaults, w http.ResponseWriter, r *http.Request)
+}
+
+func TestPlacementOperationHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The placement is initializing so the operation waits before applying
+	// its first batch.
+	mockClient := setupPlacementTest(t, ctrl, newValidInitPlacement())
+	mockClient.EXPECT().Store(gomock.Any()).Return(mem.NewStore(), nil)
+	handlerOpts, err := NewHandlerOptions(
+		mockClient, config.Configuration{}, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handlerOpts.operators = newOperatorRegistry(operator.NewOptions().
+		SetPollInterval(time.Hour), nil)
+	defer func() {
+		require.NoError(t, handlerOpts.CloseOperators())
+	}()
+
+	var (
+		startHandler  = NewOperationStartHandler(handlerOpts)
+		getHandler    = NewOperationGetHandler(handlerOpts)
+		cancelHandler = NewOperationCancelHandler(handlerOpts)
+		svcDefaults   = handleroptions.ServiceNameAndDefaults{
+			ServiceName: handleroptions.M3DBServiceName,
+		}
+	)
+	serve := func(h serviceHandler, method, body string) (int, string) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, M3DBOperationURL, strings.NewReader(body))
+		h.ServeHTTP(svcDefaults, w, req)
+		resp := w.Result()
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	code, _ := serve(getHandler, OperationGetHTTPMethod, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = serve(startHandler, OperationStartHTTPMethod, `{"type": "scale_in"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, body := serve(startHandler, OperationStartHTTPMethod,
+		`{"type": "scale_in", "instanceIds": ["A"]}`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, `"state":"running"`)
+	assert.Contains(t, body, `"batches":[["A"]]`)
+
+	code, _ = serve(startHandler, OperationStartHTTPMethod,
+		`{"type": "scale_in", "instanceIds": ["B"]}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, body = serve(getHandler, OperationGetHTTPMethod, "")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, `"state":"running"`)
+
+	code, body = serve(cancelHandler, OperationCancelHTTPMethod, "")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, `"state":"cancelled"`)
+
+	code, _ = serve(cancelHandler, OperationCancelHTTPMethod, "")
+	assert.Equal(t, http.StatusConflict, code)
+}
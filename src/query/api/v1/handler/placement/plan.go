@@ -0,0 +1,312 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/planner"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"go.uber.org/zap"
+)
+
+const (
+	// PlanHTTPMethod is the HTTP method for the the plan endpoint.
+	PlanHTTPMethod = http.MethodPost
+
+	planPathName = "plan"
+)
+
+var (
+	// M3DBPlanURL is the url for the m3db plan handler (method POST).
+	M3DBPlanURL = path.Join(handler.RoutePrefixV1,
+		M3DBServicePlacementPathName, planPathName)
+
+	// M3AggPlanURL is the url for the m3aggregator plan handler (method
+	// POST).
+	M3AggPlanURL = path.Join(handler.RoutePrefixV1,
+		M3AggServicePlacementPathName, planPathName)
+
+	// M3CoordinatorPlanURL is the url for the m3coordinator plan handler
+	// (method POST).
+	M3CoordinatorPlanURL = path.Join(handler.RoutePrefixV1,
+		M3CoordinatorServicePlacementPathName, planPathName)
+
+	errPlanNoOperation = errors.New(
+		"must specify exactly one of addInstances, removeInstanceIds or leavingInstanceIds")
+	errPlanNoCandidates = errors.New("must specify candidates with leavingInstanceIds")
+)
+
+// PlanRequest is the request to plan a placement change, exactly one of
+// adding instances, removing instances or replacing instances must be set.
+type PlanRequest struct {
+	AddInstances         []*placementpb.Instance
+	RemoveInstanceIDs    []string
+	LeavingInstanceIDs   []string
+	Candidates           []*placementpb.Instance
+	ShardSizes           map[uint32]int64
+	StreamBytesPerSecond int64
+}
+
+// planRequestJSON is the wire format of a PlanRequest, instances are decoded
+// with jsonpb to accept the same format as the add and replace endpoints.
+type planRequestJSON struct {
+	AddInstances         []json.RawMessage `json:"addInstances"`
+	RemoveInstanceIDs    []string          `json:"removeInstanceIds"`
+	LeavingInstanceIDs   []string          `json:"leavingInstanceIds"`
+	Candidates           []json.RawMessage `json:"candidates"`
+	ShardSizes           map[uint32]int64  `json:"shardSizes"`
+	StreamBytesPerSecond int64             `json:"streamBytesPerSecond"`
+}
+
+// PlanResponse is the response of a placement change plan.
+type PlanResponse struct {
+	Version           int                   `json:"version"`
+	ShardsMoved       int                   `json:"shardsMoved"`
+	TotalBytes        int64                 `json:"totalBytes"`
+	EstimatedDuration string                `json:"estimatedDuration"`
+	UnsizedShards     []uint32              `json:"unsizedShards,omitempty"`
+	Moves             []PlanShardMove       `json:"moves"`
+	Instances         []PlanInstanceChange  `json:"instances"`
+	UnderReplicated   []PlanUnderReplicated `json:"underReplicated"`
+}
+
+// PlanShardMove is a shard moving between instances.
+type PlanShardMove struct {
+	Shard uint32 `json:"shard"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+	Bytes int64  `json:"bytes"`
+}
+
+// PlanInstanceChange is the change to a single instance.
+type PlanInstanceChange struct {
+	ID                string   `json:"id"`
+	IsolationGroup    string   `json:"isolationGroup"`
+	Added             bool     `json:"added,omitempty"`
+	Removed           bool     `json:"removed,omitempty"`
+	ShardsIn          []uint32 `json:"shardsIn,omitempty"`
+	ShardsOut         []uint32 `json:"shardsOut,omitempty"`
+	BytesIn           int64    `json:"bytesIn"`
+	BytesOut          int64    `json:"bytesOut"`
+	EstimatedDuration string   `json:"estimatedDuration"`
+}
+
+// PlanUnderReplicated is a shard that is under-replicated while the change
+// is in progress.
+type PlanUnderReplicated struct {
+	Shard             uint32 `json:"shard"`
+	AvailableReplicas int    `json:"availableReplicas"`
+}
+
+// PlanHandler is the handler for placement change plans.
+type PlanHandler Handler
+
+// NewPlanHandler returns a new PlanHandler.
+func NewPlanHandler(opts HandlerOptions) *PlanHandler {
+	return &PlanHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *PlanHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOptions)
+
+	req, pErr := h.parseRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	target, plan, err := h.Plan(svc, r, req)
+	if err != nil {
+		logger.Error("unable to plan placement change", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, newPlanResponse(target, plan), logger)
+}
+
+func (h *PlanHandler) parseRequest(r *http.Request) (*PlanRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	var wire planRequestJSON
+	if err := json.NewDecoder(r.Body).Decode(&wire); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	addInstances, err := unmarshalInstancesJSON(wire.AddInstances)
+	if err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	candidates, err := unmarshalInstancesJSON(wire.Candidates)
+	if err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	req := &PlanRequest{
+		AddInstances:         addInstances,
+		RemoveInstanceIDs:    wire.RemoveInstanceIDs,
+		LeavingInstanceIDs:   wire.LeavingInstanceIDs,
+		Candidates:           candidates,
+		ShardSizes:           wire.ShardSizes,
+		StreamBytesPerSecond: wire.StreamBytesPerSecond,
+	}
+
+	var ops int
+	for _, set := range []bool{
+		len(req.AddInstances) > 0,
+		len(req.RemoveInstanceIDs) > 0,
+		len(req.LeavingInstanceIDs) > 0,
+	} {
+		if set {
+			ops++
+		}
+	}
+	if ops != 1 {
+		return nil, xhttp.NewParseError(errPlanNoOperation, http.StatusBadRequest)
+	}
+	if len(req.LeavingInstanceIDs) > 0 && len(req.Candidates) == 0 {
+		return nil, xhttp.NewParseError(errPlanNoCandidates, http.StatusBadRequest)
+	}
+
+	return req, nil
+}
+
+func unmarshalInstancesJSON(raw []json.RawMessage) ([]*placementpb.Instance, error) {
+	instances := make([]*placementpb.Instance, 0, len(raw))
+	for _, b := range raw {
+		instance := &placementpb.Instance{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(b), instance); err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// Plan computes the placement the requested change would result in and the
+// plan to get there from the current placement, without persisting it.
+func (h *PlanHandler) Plan(
+	svc handleroptions.ServiceNameAndDefaults,
+	httpReq *http.Request,
+	req *PlanRequest,
+) (placement.Placement, planner.ChangePlan, error) {
+	serviceOpts := handleroptions.NewServiceOptions(svc,
+		httpReq.Header, h.m3AggServiceOptions)
+	// Planning must never modify the placement.
+	serviceOpts.DryRun = true
+	service, _, err := ServiceWithAlgo(h.requestClusterClient(httpReq),
+		serviceOpts, h.nowFn(), nil)
+	if err != nil {
+		return nil, planner.ChangePlan{}, err
+	}
+
+	current, err := service.Placement()
+	if err != nil {
+		return nil, planner.ChangePlan{}, err
+	}
+
+	var target placement.Placement
+	switch {
+	case len(req.AddInstances) > 0:
+		instances, err := ConvertInstancesProto(req.AddInstances)
+		if err != nil {
+			return nil, planner.ChangePlan{}, err
+		}
+		target, _, err = service.AddInstances(instances)
+		if err != nil {
+			return nil, planner.ChangePlan{}, err
+		}
+	case len(req.RemoveInstanceIDs) > 0:
+		target, err = service.RemoveInstances(req.RemoveInstanceIDs)
+		if err != nil {
+			return nil, planner.ChangePlan{}, err
+		}
+	default:
+		candidates, err := ConvertInstancesProto(req.Candidates)
+		if err != nil {
+			return nil, planner.ChangePlan{}, err
+		}
+		target, _, err = service.ReplaceInstances(req.LeavingInstanceIDs, candidates)
+		if err != nil {
+			return nil, planner.ChangePlan{}, err
+		}
+	}
+
+	plannerOpts := planner.NewChangePlannerOptions().
+		SetShardSizes(req.ShardSizes)
+	if req.StreamBytesPerSecond > 0 {
+		plannerOpts = plannerOpts.SetStreamBytesPerSecond(req.StreamBytesPerSecond)
+	}
+	plan, err := planner.NewChangePlanner(plannerOpts).Plan(current, target)
+	if err != nil {
+		return nil, planner.ChangePlan{}, err
+	}
+	return target, plan, nil
+}
+
+func newPlanResponse(target placement.Placement, plan planner.ChangePlan) PlanResponse {
+	resp := PlanResponse{
+		Version:           target.Version(),
+		ShardsMoved:       plan.ShardsMoved,
+		TotalBytes:        plan.TotalBytes,
+		EstimatedDuration: plan.EstimatedDuration.String(),
+		UnsizedShards:     plan.UnsizedShards,
+		Moves:             make([]PlanShardMove, 0, len(plan.Moves)),
+		Instances:         make([]PlanInstanceChange, 0, len(plan.Instances)),
+		UnderReplicated:   make([]PlanUnderReplicated, 0, len(plan.UnderReplicated)),
+	}
+	for _, m := range plan.Moves {
+		resp.Moves = append(resp.Moves, PlanShardMove(m))
+	}
+	for _, c := range plan.Instances {
+		resp.Instances = append(resp.Instances, PlanInstanceChange{
+			ID:                c.ID,
+			IsolationGroup:    c.IsolationGroup,
+			Added:             c.Added,
+			Removed:           c.Removed,
+			ShardsIn:          c.ShardsIn,
+			ShardsOut:         c.ShardsOut,
+			BytesIn:           c.BytesIn,
+			BytesOut:          c.BytesOut,
+			EstimatedDuration: c.EstimatedDuration.String(),
+		})
+	}
+	for _, s := range plan.UnderReplicated {
+		resp.UnderReplicated = append(resp.UnderReplicated, PlanUnderReplicated(s))
+	}
+	return resp
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlanRequest(body string) *http.Request {
+	return httptest.NewRequest(PlanHTTPMethod, M3DBPlanURL, strings.NewReader(body))
+}
+
+func newPlanTestPlacement(instances ...placement.Instance) placement.Placement {
+	return placement.NewPlacement().
+		SetInstances(instances).
+		SetShards([]uint32{1}).
+		SetReplicaFactor(2).
+		SetIsSharded(true)
+}
+
+func newPlanTestInstance(id, group string, s shard.Shard) placement.Instance {
+	return placement.NewInstance().
+		SetID(id).
+		SetIsolationGroup(group).
+		SetZone("z1").
+		SetWeight(1).
+		SetShards(shard.NewShards([]shard.Shard{s}))
+}
+
+func TestPlacementPlanHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockPlacementService := SetupPlacementTest(t, ctrl)
+	handlerOpts, err := NewHandlerOptions(mockClient, config.Configuration{}, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handler := NewPlanHandler(handlerOpts)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	current := newPlanTestPlacement(
+		newPlanTestInstance("A", "r1", shard.NewShard(1).SetState(shard.Available)),
+		newPlanTestInstance("B", "r2", shard.NewShard(1).SetState(shard.Available)),
+	)
+	target := newPlanTestPlacement(
+		newPlanTestInstance("A", "r1", shard.NewShard(1).SetState(shard.Leaving)),
+		newPlanTestInstance("B", "r2", shard.NewShard(1).SetState(shard.Available)),
+		newPlanTestInstance("C", "r1", shard.NewShard(1).SetState(shard.Initializing).SetSourceID("A")),
+	)
+	mockPlacementService.EXPECT().Placement().Return(current, nil)
+	mockPlacementService.EXPECT().ReplaceInstances([]string{"A"}, gomock.Any()).Return(target, nil, nil)
+
+	w := httptest.NewRecorder()
+	req := newPlanRequest(`{
+		"leavingInstanceIds": ["A"],
+		"candidates": [{"id": "C", "isolationGroup": "r1", "zone": "z1", "weight": 1}],
+		"shardSizes": {"1": 1024},
+		"streamBytesPerSecond": 512
+	}`)
+	svcDefaults := handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+	}
+	handler.ServeHTTP(svcDefaults, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"version":0,"shardsMoved":1,"totalBytes":1024,"estimatedDuration":"2s",`+
+		`"moves":[{"shard":1,"from":"A","to":"C","bytes":1024}],`+
+		`"instances":[`+
+		`{"id":"A","isolationGroup":"r1","removed":true,"shardsOut":[1],"bytesIn":0,"bytesOut":1024,"estimatedDuration":"2s"},`+
+		`{"id":"C","isolationGroup":"r1","added":true,"shardsIn":[1],"bytesIn":1024,"bytesOut":0,"estimatedDuration":"2s"}],`+
+		`"underReplicated":[{"shard":1,"availableReplicas":1}]}`, string(body))
+}
+
+func TestPlacementPlanHandler_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, _ := SetupPlacementTest(t, ctrl)
+	handlerOpts, err := NewHandlerOptions(mockClient, config.Configuration{}, nil, instrument.NewOptions())
+	require.NoError(t, err)
+	handler := NewPlanHandler(handlerOpts)
+
+	svcDefaults := handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+	}
+	for _, body := range []string{
+		`{}`,
+		`{"removeInstanceIds": ["A"], "leavingInstanceIds": ["B"]}`,
+		`{"leavingInstanceIds": ["A"]}`,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(svcDefaults, w, newPlanRequest(body))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, body)
+	}
+}
@@ -131,7 +131,7 @@ func (h *ReplaceHandler) Replace(
 
 	serviceOpts := handleroptions.NewServiceOptions(svc,
 		httpReq.Header, h.m3AggServiceOptions)
-	service, algo, err := ServiceWithAlgo(h.clusterClient,
+	service, algo, err := ServiceWithAlgo(h.requestClusterClient(httpReq),
 		serviceOpts, h.nowFn(), nil)
 	if err != nil {
 		return nil, err
@@ -84,7 +84,7 @@ func (h *SetHandler) ServeHTTP(
 
 	serviceOpts := handleroptions.NewServiceOptions(svc,
 		r.Header, h.m3AggServiceOptions)
-	service, _, err := ServiceWithAlgo(h.clusterClient,
+	service, _, err := ServiceWithAlgo(h.requestClusterClient(r),
 		serviceOpts, h.nowFn(), nil)
 	if err != nil {
 		logger.Error("unable to create placement service", zap.Error(err))
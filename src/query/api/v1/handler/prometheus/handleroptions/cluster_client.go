@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handleroptions
+
+import (
+	"context"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+)
+
+type clusterClientKeyType int
+
+const clusterClientKey clusterClientKeyType = iota
+
+// NewClusterClientContext returns a context that carries a cluster client
+// for handlers to use in place of their own while serving a request, which
+// lets middleware scope the writes a request makes.
+func NewClusterClientContext(
+	ctx context.Context,
+	client clusterclient.Client,
+) context.Context {
+	return context.WithValue(ctx, clusterClientKey, client)
+}
+
+// ClusterClient returns the cluster client carried by the context, or the
+// given client if the context does not carry one.
+func ClusterClient(
+	ctx context.Context,
+	client clusterclient.Client,
+) clusterclient.Client {
+	if c, ok := ctx.Value(clusterClientKey).(clusterclient.Client); ok {
+		return c
+	}
+	return client
+}
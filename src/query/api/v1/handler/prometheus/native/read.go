@@ -31,9 +31,11 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/instrument"
@@ -72,6 +74,8 @@ type PromReadHandler struct {
 	limitsCfg           *config.LimitsConfiguration
 	timeoutOps          *prometheus.TimeoutOpts
 	engine              executor.Engine
+	resultsCache        cache.ResultsCache
+	tenantManager       tenant.Manager
 	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
 	tagOpts             models.TagOptions
 	promReadMetrics     promReadMetrics
@@ -122,6 +126,8 @@ func NewPromReadHandler(opts options.HandlerOptions) *PromReadHandler {
 
 	h := &PromReadHandler{
 		engine:              opts.Engine(),
+		resultsCache:        opts.ResultsCache(),
+		tenantManager:       opts.TenantManager(),
 		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
 		tagOpts:             opts.TagOptions(),
 		limitsCfg:           &limits,
@@ -144,10 +150,20 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	enforcer, release, err := tenant.AdmitQuery(h.tenantManager, r, fetchOpts)
+	if err != nil {
+		h.promReadMetrics.fetchErrorsClient.Inc(1)
+		xhttp.Error(w, err, http.StatusTooManyRequests)
+		return
+	}
+
+	defer release()
 	queryOpts := &executor.QueryOptions{
 		QueryContextOptions: models.QueryContextOptions{
 			LimitMaxTimeseries: fetchOpts.Limit,
-		}}
+		},
+		Enforcer: enforcer,
+	}
 
 	restrictOpts := fetchOpts.RestrictQueryOptions.GetRestrictByType()
 	if restrictOpts != nil {
@@ -205,13 +221,23 @@ func (h *PromReadHandler) ServeHTTPWithEngine(
 		return nil, emptyReqParams, &RespError{Err: err, Code: http.StatusBadRequest}
 	}
 
-	result, err := read(ctx, engine, opts, fetchOpts, h.tagOpts,
-		w, params, h.instrumentOpts)
+	result, err := h.readWithCache(ctx, engine, opts, fetchOpts, w, params)
+	if err == nil {
+		err = tenant.CheckFetchedSeries(h.tenantManager, fetchOpts, result.meta)
+	}
 	if err != nil {
 		sp := xopentracing.SpanFromContextOrNoop(ctx)
 		sp.LogFields(opentracinglog.Error(err))
 		opentracingext.Error.Set(sp, true)
 		logger.Error("unable to fetch data", zap.Error(err))
+		if tenant.IsLimitError(err) {
+			h.promReadMetrics.fetchErrorsClient.Inc(1)
+			return nil, emptyReqParams, &RespError{
+				Err:  err,
+				Code: http.StatusTooManyRequests,
+			}
+		}
+
 		h.promReadMetrics.fetchErrorsServer.Inc(1)
 		return nil, emptyReqParams, &RespError{
 			Err:  err,
@@ -225,6 +251,39 @@ func (h *PromReadHandler) ServeHTTPWithEngine(
 	return result.series, params, nil
 }
 
+// readWithCache reads the query results, serving them from the results cache
+// where possible if one is configured.
+func (h *PromReadHandler) readWithCache(
+	ctx context.Context,
+	engine executor.Engine,
+	opts *executor.QueryOptions,
+	fetchOpts *storage.FetchOptions,
+	w http.ResponseWriter,
+	params models.RequestParams,
+) (readResult, error) {
+	// NB: only results from the handler's own engine are cached.
+	if h.resultsCache == nil || engine != h.engine {
+		return read(ctx, engine, opts, fetchOpts, h.tagOpts,
+			w, params, h.instrumentOpts)
+	}
+
+	result, err := h.resultsCache.Read(params, fetchOpts,
+		func(params models.RequestParams) (cache.Result, error) {
+			r, err := read(ctx, engine, opts, fetchOpts, h.tagOpts,
+				w, params, h.instrumentOpts)
+			if err != nil {
+				return cache.Result{}, err
+			}
+
+			return cache.Result{Series: r.series, Meta: r.meta}, nil
+		})
+	if err != nil {
+		return readResult{meta: block.NewResultMetadata()}, err
+	}
+
+	return readResult{series: result.Series, meta: result.Meta}, nil
+}
+
 func (h *PromReadHandler) validateRequest(params *models.RequestParams) error {
 	// Impose a rough limit on the number of returned time series. This is intended to prevent things like
 	// querying from the beginning of time with a 1s step size.
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/instrument"
 	xhttp "github.com/m3db/m3/src/x/net/http"
@@ -57,6 +58,7 @@ type PromReadInstantHandler struct {
 	engine              executor.Engine
 	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
 	tagOpts             models.TagOptions
+	tenantManager       tenant.Manager
 	timeoutOpts         *prometheus.TimeoutOpts
 	instrumentOpts      instrument.Options
 }
@@ -68,6 +70,7 @@ func NewPromReadInstantHandler(
 		engine:              opts.Engine(),
 		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
 		tagOpts:             opts.TagOptions(),
+		tenantManager:       opts.TenantManager(),
 		timeoutOpts:         opts.TimeoutOpts(),
 		instrumentOpts:      opts.InstrumentOpts(),
 	}
@@ -83,6 +86,13 @@ func (h *PromReadInstantHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	enforcer, release, err := tenant.AdmitQuery(h.tenantManager, r, fetchOpts)
+	if err != nil {
+		xhttp.Error(w, err, http.StatusTooManyRequests)
+		return
+	}
+
+	defer release()
 	params, rErr := parseInstantaneousParams(r, h.engine.Options(),
 		h.timeoutOpts, fetchOpts, h.instrumentOpts)
 	if rErr != nil {
@@ -97,7 +107,9 @@ func (h *PromReadInstantHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	queryOpts := &executor.QueryOptions{
 		QueryContextOptions: models.QueryContextOptions{
 			LimitMaxTimeseries: fetchOpts.Limit,
-		}}
+		},
+		Enforcer: enforcer,
+	}
 
 	restrictOpts := fetchOpts.RestrictQueryOptions.GetRestrictByType()
 	if restrictOpts != nil {
@@ -110,9 +122,17 @@ func (h *PromReadInstantHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 
 	result, err := read(ctx, h.engine, queryOpts, fetchOpts,
 		h.tagOpts, w, params, h.instrumentOpts)
+	if err == nil {
+		err = tenant.CheckFetchedSeries(h.tenantManager, fetchOpts, result.meta)
+	}
 	if err != nil {
 		logger.Error("unable to fetch data", zap.Error(err))
-		xhttp.Error(w, err, http.StatusInternalServerError)
+		code := http.StatusInternalServerError
+		if tenant.IsLimitError(err) {
+			code = http.StatusTooManyRequests
+		}
+
+		xhttp.Error(w, err, code)
 		return
 	}
 
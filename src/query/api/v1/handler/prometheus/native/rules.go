@@ -0,0 +1,210 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// PromRulesURL is the url for listing the loaded recording and alerting
+	// rules, in the Prometheus rules API format.
+	PromRulesURL = handler.RoutePrefixV1 + "/rules"
+
+	// PromAlertsURL is the url for listing the active alerts, in the
+	// Prometheus alerts API format.
+	PromAlertsURL = handler.RoutePrefixV1 + "/alerts"
+
+	// PromRulesHTTPMethod is the HTTP method used with the rules and alerts
+	// resources.
+	PromRulesHTTPMethod = http.MethodGet
+
+	statusSuccess = "success"
+)
+
+type rulesResponse struct {
+	Status string         `json:"status"`
+	Data   rulesDiscovery `json:"data"`
+}
+
+type rulesDiscovery struct {
+	Groups []ruleGroup `json:"groups"`
+}
+
+type ruleGroup struct {
+	Name           string        `json:"name"`
+	File           string        `json:"file"`
+	Interval       float64       `json:"interval"`
+	Rules          []ruleSummary `json:"rules"`
+	LastEvaluation time.Time     `json:"lastEvaluation"`
+	EvaluationTime float64       `json:"evaluationTime"`
+}
+
+type ruleSummary struct {
+	Name        string            `json:"name"`
+	Query       string            `json:"query"`
+	Duration    float64           `json:"duration,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Alerts      []alert           `json:"alerts,omitempty"`
+	Health      rules.Health      `json:"health"`
+	LastError   string            `json:"lastError,omitempty"`
+	Type        rules.RuleType    `json:"type"`
+}
+
+type alertsResponse struct {
+	Status string         `json:"status"`
+	Data   alertDiscovery `json:"data"`
+}
+
+type alertDiscovery struct {
+	Alerts []alert `json:"alerts"`
+}
+
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	State       string            `json:"state"`
+	ActiveAt    time.Time         `json:"activeAt"`
+	Value       string            `json:"value"`
+}
+
+type promRulesHandler struct {
+	manager        rules.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewPromRulesHandler returns a new instance of the rules handler.
+func NewPromRulesHandler(opts options.HandlerOptions) http.Handler {
+	return &promRulesHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *promRulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	groups := h.manager.Groups()
+	result := rulesResponse{
+		Status: statusSuccess,
+		Data: rulesDiscovery{
+			Groups: make([]ruleGroup, 0, len(groups)),
+		},
+	}
+
+	for _, g := range groups {
+		group := ruleGroup{
+			Name:           g.Name(),
+			File:           g.File(),
+			Interval:       g.Interval().Seconds(),
+			Rules:          make([]ruleSummary, 0, len(g.Rules())),
+			LastEvaluation: g.LastEvaluation(),
+			EvaluationTime: g.EvaluationDuration().Seconds(),
+		}
+
+		for _, rule := range g.Rules() {
+			summary := ruleSummary{
+				Name:   rule.Name(),
+				Query:  rule.Query(),
+				Labels: tagsToMap(rule.Labels()),
+				Health: rule.Health(),
+				Type:   rule.Type(),
+			}
+
+			if err := rule.LastError(); err != nil {
+				summary.LastError = err.Error()
+			}
+
+			if alerting, ok := rule.(*rules.AlertingRule); ok {
+				summary.Duration = alerting.HoldDuration().Seconds()
+				summary.Annotations = tagsToMap(alerting.Annotations())
+				summary.Alerts = toAlerts(alerting.ActiveAlerts())
+			}
+
+			group.Rules = append(group.Rules, summary)
+		}
+
+		result.Data.Groups = append(result.Data.Groups, group)
+	}
+
+	xhttp.WriteJSONResponse(w, result, h.instrumentOpts.Logger())
+}
+
+type promAlertsHandler struct {
+	manager        rules.Manager
+	instrumentOpts instrument.Options
+}
+
+// NewPromAlertsHandler returns a new instance of the alerts handler.
+func NewPromAlertsHandler(opts options.HandlerOptions) http.Handler {
+	return &promAlertsHandler{
+		manager:        opts.RuleManager(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *promAlertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := alertsResponse{
+		Status: statusSuccess,
+		Data: alertDiscovery{
+			Alerts: []alert{},
+		},
+	}
+
+	for _, rule := range h.manager.AlertingRules() {
+		result.Data.Alerts = append(result.Data.Alerts,
+			toAlerts(rule.ActiveAlerts())...)
+	}
+
+	xhttp.WriteJSONResponse(w, result, h.instrumentOpts.Logger())
+}
+
+func toAlerts(active []rules.Alert) []alert {
+	alerts := make([]alert, 0, len(active))
+	for _, a := range active {
+		alerts = append(alerts, alert{
+			Labels:      tagsToMap(a.Labels),
+			Annotations: tagsToMap(a.Annotations),
+			State:       a.State.String(),
+			ActiveAt:    a.ActiveAt,
+			Value:       strconv.FormatFloat(a.Value, 'e', -1, 64),
+		})
+	}
+
+	return alerts
+}
+
+func tagsToMap(tags models.Tags) map[string]string {
+	m := make(map[string]string, len(tags.Tags))
+	for _, t := range tags.Tags {
+		m[string(t.Name)] = string(t.Value)
+	}
+
+	return m
+}
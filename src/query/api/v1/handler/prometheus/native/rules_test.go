@@ -0,0 +1,159 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
+	"github.com/m3db/m3/src/query/storage/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+type testRuleManager struct {
+	groups []*rules.Group
+}
+
+func (m *testRuleManager) Start() error { return nil }
+func (m *testRuleManager) Close() error { return nil }
+
+func (m *testRuleManager) Groups() []*rules.Group { return m.groups }
+
+func (m *testRuleManager) AlertingRules() []*rules.AlertingRule {
+	var result []*rules.AlertingRule
+	for _, g := range m.groups {
+		for _, r := range g.Rules() {
+			if alerting, ok := r.(*rules.AlertingRule); ok {
+				result = append(result, alerting)
+			}
+		}
+	}
+
+	return result
+}
+
+func newTestRuleManager(now time.Time) rules.Manager {
+	tagOpts := models.NewTagOptions()
+	labels := models.NewTags(1, tagOpts).AddTag(models.Tag{
+		Name:  []byte("severity"),
+		Value: []byte("page"),
+	})
+
+	recording := rules.NewRecordingRule("job:up", "up", models.EmptyTags())
+	alerting := rules.NewAlertingRule("InstanceDown", "up == 0", time.Minute,
+		labels, models.EmptyTags(), tagOpts)
+
+	query := func(_ context.Context, _ string, t time.Time) (rules.Vector, error) {
+		tags := models.NewTags(1, tagOpts).AddTag(models.Tag{
+			Name:  []byte("instance"),
+			Value: []byte("a"),
+		})
+
+		return rules.Vector{{Tags: tags, Value: 0, Timestamp: t}}, nil
+	}
+
+	group := rules.NewGroup("example", "rules.yml", 10*time.Second,
+		[]rules.Rule{recording, alerting}, rules.GroupOptions{
+			QueryFunc: query,
+			Appender:  mock.NewMockStorage(),
+			NowFn:     time.Now,
+			Logger:    zap.NewNop(),
+			Scope:     tally.NoopScope,
+		})
+
+	group.Eval(context.Background(), now)
+	return &testRuleManager{groups: []*rules.Group{group}}
+}
+
+func TestPromRulesHandler(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	opts := options.EmptyHandlerOptions().
+		SetRuleManager(newTestRuleManager(now))
+	handler := NewPromRulesHandler(opts)
+
+	req := httptest.NewRequest(PromRulesHTTPMethod, PromRulesURL, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp rulesResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, statusSuccess, resp.Status)
+	require.Equal(t, 1, len(resp.Data.Groups))
+
+	group := resp.Data.Groups[0]
+	assert.Equal(t, "example", group.Name)
+	assert.Equal(t, "rules.yml", group.File)
+	assert.Equal(t, float64(10), group.Interval)
+	assert.True(t, now.Equal(group.LastEvaluation))
+	require.Equal(t, 2, len(group.Rules))
+
+	assert.Equal(t, "job:up", group.Rules[0].Name)
+	assert.Equal(t, rules.RecordingRuleType, group.Rules[0].Type)
+	assert.Equal(t, rules.HealthGood, group.Rules[0].Health)
+	assert.Equal(t, 0, len(group.Rules[0].Alerts))
+
+	assert.Equal(t, "InstanceDown", group.Rules[1].Name)
+	assert.Equal(t, "up == 0", group.Rules[1].Query)
+	assert.Equal(t, rules.AlertingRuleType, group.Rules[1].Type)
+	assert.Equal(t, float64(60), group.Rules[1].Duration)
+	assert.Equal(t, map[string]string{"severity": "page"}, group.Rules[1].Labels)
+	require.Equal(t, 1, len(group.Rules[1].Alerts))
+	assert.Equal(t, "pending", group.Rules[1].Alerts[0].State)
+}
+
+func TestPromAlertsHandler(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	opts := options.EmptyHandlerOptions().
+		SetRuleManager(newTestRuleManager(now))
+	handler := NewPromAlertsHandler(opts)
+
+	req := httptest.NewRequest(PromRulesHTTPMethod, PromAlertsURL, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp alertsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, statusSuccess, resp.Status)
+	require.Equal(t, 1, len(resp.Data.Alerts))
+
+	alert := resp.Data.Alerts[0]
+	assert.Equal(t, map[string]string{
+		"alertname": "InstanceDown",
+		"instance":  "a",
+		"severity":  "page",
+	}, alert.Labels)
+	assert.Equal(t, "pending", alert.State)
+	assert.True(t, now.Equal(alert.ActiveAt))
+	assert.Equal(t, "0e+00", alert.Value)
+}
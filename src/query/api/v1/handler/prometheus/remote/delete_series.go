@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// PromDeleteSeriesURL is the url for the prom delete series handler.
+	PromDeleteSeriesURL = handler.RoutePrefixV1 + "/admin/tsdb/delete_series"
+)
+
+var (
+	// PromDeleteSeriesHTTPMethods are the HTTP methods for this handler.
+	PromDeleteSeriesHTTPMethods = []string{http.MethodPost, http.MethodPut}
+
+	errNoClusters = errors.New("no m3db clusters configured")
+)
+
+// PromDeleteSeriesHandler represents a handler for the prometheus
+// delete series endpoint, it deletes the data of all series matching
+// the given selectors from every configured namespace.
+type PromDeleteSeriesHandler struct {
+	clusters       m3.Clusters
+	tagOptions     models.TagOptions
+	instrumentOpts instrument.Options
+}
+
+// DeleteSeriesResult is the result of a delete series request.
+type DeleteSeriesResult struct {
+	NumSeries int64 `json:"numSeries"`
+}
+
+// NewPromDeleteSeriesHandler returns a new instance of handler.
+func NewPromDeleteSeriesHandler(opts options.HandlerOptions) http.Handler {
+	return &PromDeleteSeriesHandler{
+		clusters:       opts.Clusters(),
+		tagOptions:     opts.TagOptions(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *PromDeleteSeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), handler.HeaderKey, r.Header)
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	if h.clusters == nil {
+		xhttp.Error(w, errNoClusters, http.StatusBadRequest)
+		return
+	}
+
+	queries, err := prometheus.ParseSeriesMatchQuery(r, h.tagOptions)
+	if err != nil {
+		logger.Error("unable to parse series match values to query", zap.Error(err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var result DeleteSeriesResult
+	for _, query := range queries {
+		n, err := h.deleteSeries(query)
+		if err != nil {
+			logger.Error("unable to delete series",
+				zap.String("query", query.Raw), zap.Error(err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		result.NumSeries += n
+	}
+
+	xhttp.WriteJSONResponse(w, result, logger)
+}
+
+func (h *PromDeleteSeriesHandler) deleteSeries(
+	query *storage.FetchQuery,
+) (int64, error) {
+	m3query, err := storage.FetchQueryToM3Query(query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := query.Start
+	if start.IsZero() {
+		// NB: Zero time cannot be represented as unix nanoseconds.
+		start = time.Unix(0, 0)
+	}
+
+	var deleted int64
+	for _, ns := range h.clusters.ClusterNamespaces() {
+		n, err := ns.Session().DeleteSeries(ns.NamespaceID(), m3query,
+			start, query.End)
+		if err != nil {
+			return 0, err
+		}
+
+		deleted += n
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromDeleteSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	unaggregated := client.NewMockSession(ctrl)
+	aggregated := client.NewMockSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_unaggregated"),
+		Session:     unaggregated,
+		Retention:   24 * time.Hour,
+	}, m3.AggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics_aggregated"),
+		Session:     aggregated,
+		Retention:   7 * 24 * time.Hour,
+		Resolution:  time.Minute,
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1000, 0)
+	end := time.Unix(2000, 0)
+	unaggregated.EXPECT().
+		DeleteSeries(ident.NewIDMatcher("metrics_unaggregated"), gomock.Any(),
+			start, end).
+		Return(int64(3), nil)
+	aggregated.EXPECT().
+		DeleteSeries(ident.NewIDMatcher("metrics_aggregated"), gomock.Any(),
+			start, end).
+		Return(int64(2), nil)
+
+	opts := options.EmptyHandlerOptions().
+		SetClusters(clusters).
+		SetTagOptions(models.NewTagOptions()).
+		SetInstrumentOpts(instrument.NewOptions())
+	h := NewPromDeleteSeriesHandler(opts)
+
+	form := url.Values{}
+	form.Set("match[]", `up{job="foo"}`)
+	form.Set("start", "1000")
+	form.Set("end", "2000")
+	req := httptest.NewRequest(http.MethodPost, PromDeleteSeriesURL,
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	body, err := ioutil.ReadAll(rr.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"numSeries":5}`, string(body))
+}
+
+func TestPromDeleteSeriesNoClusters(t *testing.T) {
+	opts := options.EmptyHandlerOptions().
+		SetTagOptions(models.NewTagOptions()).
+		SetInstrumentOpts(instrument.NewOptions())
+	h := NewPromDeleteSeriesHandler(opts)
+
+	req := httptest.NewRequest(http.MethodPost, PromDeleteSeriesURL, nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
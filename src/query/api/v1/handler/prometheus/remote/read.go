@@ -32,10 +32,12 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/cost"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/util/logging"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/instrument"
@@ -61,6 +63,7 @@ type PromReadHandler struct {
 	promReadMetrics     promReadMetrics
 	timeoutOpts         *prometheus.TimeoutOpts
 	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	tenantManager       tenant.Manager
 	keepEmpty           bool
 	instrumentOpts      instrument.Options
 }
@@ -74,6 +77,7 @@ func NewPromReadHandler(opts options.HandlerOptions) http.Handler {
 		promReadMetrics:     newPromReadMetrics(taggedScope),
 		timeoutOpts:         opts.TimeoutOpts(),
 		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		tenantManager:       opts.TenantManager(),
 		keepEmpty:           opts.Config().ResultOptions.KeepNans,
 		instrumentOpts:      opts.InstrumentOpts(),
 	}
@@ -121,10 +125,35 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	enforcer, release, err := tenant.AdmitQuery(h.tenantManager, r, fetchOpts)
+	if err != nil {
+		h.promReadMetrics.fetchErrorsClient.Inc(1)
+		xhttp.Error(w, err, http.StatusTooManyRequests)
+		return
+	}
+
+	defer release()
+	if enforcer != nil {
+		// NB: remote reads are not evaluated by the engine, so account for
+		// the fetched datapoints directly against the tenant's enforcer.
+		queryEnforcer := enforcer.Child(cost.QueryLevel)
+		defer queryEnforcer.Close()
+		fetchOpts.Enforcer = queryEnforcer
+	}
+
 	readResult, err := h.read(ctx, w, req, timeout, fetchOpts)
+	if err == nil {
+		err = tenant.CheckFetchedSeries(h.tenantManager, fetchOpts, readResult.meta)
+	}
 	if err != nil {
-		h.promReadMetrics.fetchErrorsServer.Inc(1)
 		logger.Error("unable to fetch data", zap.Error(err))
+		if tenant.IsLimitError(err) {
+			h.promReadMetrics.fetchErrorsClient.Inc(1)
+			xhttp.Error(w, err, http.StatusTooManyRequests)
+			return
+		}
+
+		h.promReadMetrics.fetchErrorsServer.Inc(1)
 		xhttp.Error(w, err, http.StatusInternalServerError)
 		return
 	}
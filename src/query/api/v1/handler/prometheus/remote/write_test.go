@@ -39,6 +39,7 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/tenant"
 	xclock "github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/instrument"
@@ -102,6 +103,38 @@ func TestPromWrite(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestPromWriteTenantLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	tenantManager, err := tenant.NewManager(tenant.NewOptions().
+		SetDefaultLimits(tenant.TenantLimits{
+			Default: tenant.Limits{MaxWriteDatapointsPerSecond: 1},
+		}))
+	require.NoError(t, err)
+
+	opts := makeOptions(mockDownsamplerAndWriter).
+		SetTenantManager(tenantManager)
+	handler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		promReq := test.GeneratePromWriteRequest()
+		promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+		req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+		writer := httptest.NewRecorder()
+		handler.ServeHTTP(writer, req)
+		resp := writer.Result()
+		require.Equal(t, expected, resp.StatusCode)
+	}
+}
+
 func TestPromWriteError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -29,6 +29,7 @@ import (
 
 	"github.com/m3db/m3/src/query/api/experimental/annotated"
 	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/api/v1/handler/audit"
 	"github.com/m3db/m3/src/query/api/v1/handler/database"
 	"github.com/m3db/m3/src/query/api/v1/handler/graphite"
 	"github.com/m3db/m3/src/query/api/v1/handler/influxdb"
@@ -67,10 +68,11 @@ var (
 
 // Handler represents the top-level HTTP handler.
 type Handler struct {
-	router         *mux.Router
-	handler        http.Handler
-	options        options.HandlerOptions
-	customHandlers []options.CustomHandler
+	router               *mux.Router
+	handler              http.Handler
+	options              options.HandlerOptions
+	customHandlers       []options.CustomHandler
+	placementHandlerOpts *placement.HandlerOptions
 }
 
 // Router returns the http handler registered with all relevant routes for query.
@@ -203,11 +205,28 @@ func (h *Handler) RegisterRoutes() error {
 		wrapped(native.NewPromThresholdHandler(h.options)).ServeHTTP,
 	).Methods(native.PromThresholdHTTPMethod)
 
+	// Rule and alert endpoints, only available if rule evaluation is enabled.
+	if h.options.RuleManager() != nil {
+		h.router.HandleFunc(native.PromRulesURL,
+			wrapped(native.NewPromRulesHandler(h.options)).ServeHTTP,
+		).Methods(native.PromRulesHTTPMethod)
+		h.router.HandleFunc(native.PromAlertsURL,
+			wrapped(native.NewPromAlertsHandler(h.options)).ServeHTTP,
+		).Methods(native.PromRulesHTTPMethod)
+	}
+
 	// Series match endpoints.
 	h.router.HandleFunc(remote.PromSeriesMatchURL,
 		wrapped(remote.NewPromSeriesMatchHandler(h.options)).ServeHTTP,
 	).Methods(remote.PromSeriesMatchHTTPMethods...)
 
+	// Series delete endpoints.
+	if h.options.Clusters() != nil {
+		h.router.HandleFunc(remote.PromDeleteSeriesURL,
+			wrapped(remote.NewPromDeleteSeriesHandler(h.options)).ServeHTTP,
+		).Methods(remote.PromDeleteSeriesHTTPMethods...)
+	}
+
 	// Graphite endpoints.
 	h.router.HandleFunc(graphite.ReadURL,
 		wrapped(graphite.NewRenderHandler(h.options)).ServeHTTP,
@@ -262,9 +281,20 @@ func (h *Handler) RegisterRoutes() error {
 
 		placement.RegisterRoutes(h.router,
 			serviceOptionDefaults, placementOpts)
+		h.placementHandlerOpts = &placementOpts
 		namespace.RegisterRoutes(h.router, clusterClient, serviceOptionDefaults, instrumentOpts)
 		topic.RegisterRoutes(h.router, clusterClient, config, instrumentOpts)
 
+		// Audit history and rollback, changes to managed values made through
+		// any endpoint are recorded.
+		auditOpts, err := audit.NewHandlerOptions(clusterClient,
+			serviceOptionDefaults, h.m3AggServiceOptions(), instrumentOpts)
+		if err != nil {
+			return err
+		}
+		audit.RegisterRoutes(h.router, auditOpts)
+		h.handler = audit.NewRecordingHandler(h.handler, auditOpts)
+
 		// Experimental endpoints.
 		if config.Experimental.Enabled {
 			experimentalAnnotatedWriteHandler := annotated.NewHandler(
@@ -298,6 +328,24 @@ func (h *Handler) RegisterRoutes() error {
 	return nil
 }
 
+// Start starts the background work of the registered handlers, which is
+// running the placement operations of the M3DB service. It must be called
+// after RegisterRoutes, Close stops it.
+func (h *Handler) Start() error {
+	if h.placementHandlerOpts == nil {
+		return nil
+	}
+	return h.placementHandlerOpts.StartOperators(h.options.ServiceOptionDefaults())
+}
+
+// Close stops the background work of the registered handlers.
+func (h *Handler) Close() error {
+	if h.placementHandlerOpts == nil {
+		return nil
+	}
+	return h.placementHandlerOpts.CloseOperators()
+}
+
 func (h *Handler) placementOpts() (placement.HandlerOptions, error) {
 	return placement.NewHandlerOptions(
 		h.options.ClusterClient(),
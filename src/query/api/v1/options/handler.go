@@ -31,11 +31,14 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/cache"
 	"github.com/m3db/m3/src/query/cost"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/rules"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
 )
@@ -137,6 +140,21 @@ type HandlerOptions interface {
 	// SetNowFn sets the now function.
 	SetNowFn(f clock.NowFn) HandlerOptions
 
+	// RuleManager returns the rule manager.
+	RuleManager() rules.Manager
+	// SetRuleManager sets the rule manager.
+	SetRuleManager(m rules.Manager) HandlerOptions
+
+	// ResultsCache returns the range query results cache.
+	ResultsCache() cache.ResultsCache
+	// SetResultsCache sets the range query results cache.
+	SetResultsCache(c cache.ResultsCache) HandlerOptions
+
+	// TenantManager returns the tenant limits manager.
+	TenantManager() tenant.Manager
+	// SetTenantManager sets the tenant limits manager.
+	SetTenantManager(m tenant.Manager) HandlerOptions
+
 	// InstrumentOpts returns the instrumentation optoins.
 	InstrumentOpts() instrument.Options
 	// SetInstrumentOpts sets instrumentation options.
@@ -163,6 +181,9 @@ type handlerOptions struct {
 	placementServiceNames []string
 	serviceOptionDefaults []handleroptions.ServiceOptionsDefault
 	nowFn                 clock.NowFn
+	ruleManager           rules.Manager
+	resultsCache          cache.ResultsCache
+	tenantManager         tenant.Manager
 }
 
 // EmptyHandlerOptions returns  default handler options.
@@ -406,3 +427,33 @@ func (o *handlerOptions) SetNowFn(n clock.NowFn) HandlerOptions {
 	options.nowFn = n
 	return &options
 }
+
+func (o *handlerOptions) RuleManager() rules.Manager {
+	return o.ruleManager
+}
+
+func (o *handlerOptions) SetRuleManager(m rules.Manager) HandlerOptions {
+	options := *o
+	options.ruleManager = m
+	return &options
+}
+
+func (o *handlerOptions) ResultsCache() cache.ResultsCache {
+	return o.resultsCache
+}
+
+func (o *handlerOptions) SetResultsCache(c cache.ResultsCache) HandlerOptions {
+	options := *o
+	options.resultsCache = c
+	return &options
+}
+
+func (o *handlerOptions) TenantManager() tenant.Manager {
+	return o.tenantManager
+}
+
+func (o *handlerOptions) SetTenantManager(m tenant.Manager) HandlerOptions {
+	options := *o
+	options.tenantManager = m
+	return &options
+}
@@ -37,6 +37,8 @@ func (t BlockType) String() string {
 		return "container"
 	case BlockEmpty:
 		return "empty"
+	case BlockUnconsolidated:
+		return "unconsolidated"
 	case BlockTest:
 		return "test"
 	}
@@ -47,6 +47,9 @@ const (
 	BlockContainer
 	// BlockEmpty is a block with metadata but no series or values.
 	BlockEmpty
+	// BlockUnconsolidated is a block of series with raw datapoints, which only
+	// supports series iteration.
+	BlockUnconsolidated
 	// BlockTest is a block used for testing only.
 	BlockTest
 )
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"errors"
+	"fmt"
+)
+
+type unconsolidatedBlock struct {
+	meta   Metadata
+	series []UnconsolidatedSeries
+}
+
+// NewUnconsolidatedBlock creates a block from a list of series with raw
+// datapoints. It supports series iteration only.
+func NewUnconsolidatedBlock(
+	meta Metadata,
+	series []UnconsolidatedSeries,
+) Block {
+	return &unconsolidatedBlock{
+		meta:   meta,
+		series: series,
+	}
+}
+
+func (b *unconsolidatedBlock) Close() error { return nil }
+
+func (b *unconsolidatedBlock) Info() BlockInfo {
+	return NewBlockInfo(BlockUnconsolidated)
+}
+
+func (b *unconsolidatedBlock) Meta() Metadata {
+	return b.meta
+}
+
+// StepIter is invalid for an unconsolidated block.
+func (b *unconsolidatedBlock) StepIter() (StepIter, error) {
+	return nil, errors.New("step iterator undefined for an unconsolidated block")
+}
+
+func (b *unconsolidatedBlock) SeriesIter() (SeriesIter, error) {
+	return newUnconsolidatedSeriesIter(b.series), nil
+}
+
+func (b *unconsolidatedBlock) MultiSeriesIter(
+	concurrency int,
+) ([]SeriesIterBatch, error) {
+	if concurrency < 1 {
+		return nil, fmt.Errorf("batch size %d must be greater than 0", concurrency)
+	}
+
+	var (
+		count      = len(b.series)
+		iters      = make([]SeriesIterBatch, 0, concurrency)
+		chunkSize  = count / concurrency
+		remainder  = count % concurrency
+		chunkSizes = make([]int, concurrency)
+	)
+
+	for i := range chunkSizes {
+		chunkSizes[i] = chunkSize
+		if i < remainder {
+			chunkSizes[i]++
+		}
+	}
+
+	start := 0
+	for _, chunkSize := range chunkSizes {
+		end := start + chunkSize
+		iters = append(iters, SeriesIterBatch{
+			Iter: newUnconsolidatedSeriesIter(b.series[start:end]),
+			Size: end - start,
+		})
+
+		start = end
+	}
+
+	return iters, nil
+}
+
+type unconsolidatedSeriesIter struct {
+	idx    int
+	series []UnconsolidatedSeries
+}
+
+func newUnconsolidatedSeriesIter(series []UnconsolidatedSeries) SeriesIter {
+	return &unconsolidatedSeriesIter{
+		idx:    -1,
+		series: series,
+	}
+}
+
+func (it *unconsolidatedSeriesIter) Close()           {}
+func (it *unconsolidatedSeriesIter) Err() error       { return nil }
+func (it *unconsolidatedSeriesIter) SeriesCount() int { return len(it.series) }
+
+func (it *unconsolidatedSeriesIter) SeriesMeta() []SeriesMeta {
+	metas := make([]SeriesMeta, 0, len(it.series))
+	for _, s := range it.series {
+		metas = append(metas, s.Meta)
+	}
+
+	return metas
+}
+
+func (it *unconsolidatedSeriesIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.series)
+}
+
+func (it *unconsolidatedSeriesIter) Current() UnconsolidatedSeries {
+	return it.series[it.idx]
+}
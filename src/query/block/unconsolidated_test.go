@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildUnconsolidatedSeries(count int) []UnconsolidatedSeries {
+	now := time.Now()
+	series := make([]UnconsolidatedSeries, 0, count)
+	for i := 0; i < count; i++ {
+		tags := models.EmptyTags().AddTag(models.Tag{
+			Name:  []byte("a"),
+			Value: []byte{byte('a' + i)},
+		})
+
+		series = append(series, NewUnconsolidatedSeries(ts.Datapoints{
+			{Timestamp: now, Value: float64(i)},
+			{Timestamp: now.Add(time.Second), Value: float64(i * 10)},
+		}, SeriesMeta{Name: tags.ID(), Tags: tags}))
+	}
+
+	return series
+}
+
+func TestUnconsolidatedBlock(t *testing.T) {
+	meta := Metadata{
+		Bounds: models.Bounds{
+			Start:    time.Now(),
+			Duration: time.Minute,
+			StepSize: time.Second,
+		},
+	}
+
+	series := buildUnconsolidatedSeries(3)
+	b := NewUnconsolidatedBlock(meta, series)
+	assert.Equal(t, BlockUnconsolidated, b.Info().Type())
+	assert.True(t, meta.Equals(b.Meta()))
+
+	_, err := b.StepIter()
+	assert.Error(t, err)
+
+	iter, err := b.SeriesIter()
+	require.NoError(t, err)
+	assert.Equal(t, 3, iter.SeriesCount())
+	require.Len(t, iter.SeriesMeta(), 3)
+
+	i := 0
+	for iter.Next() {
+		assert.Equal(t, series[i], iter.Current())
+		i++
+	}
+
+	assert.Equal(t, 3, i)
+	assert.NoError(t, iter.Err())
+	assert.NoError(t, b.Close())
+}
+
+func TestUnconsolidatedBlockMultiSeriesIter(t *testing.T) {
+	series := buildUnconsolidatedSeries(5)
+	b := NewUnconsolidatedBlock(Metadata{}, series)
+
+	_, err := b.MultiSeriesIter(0)
+	assert.Error(t, err)
+
+	batches, err := b.MultiSeriesIter(3)
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+
+	expectedSizes := []int{2, 2, 1}
+	idx := 0
+	for i, batch := range batches {
+		assert.Equal(t, expectedSizes[i], batch.Size)
+		for batch.Iter.Next() {
+			assert.Equal(t, series[idx], batch.Iter.Current())
+			idx++
+		}
+	}
+
+	assert.Equal(t, 5, idx)
+}
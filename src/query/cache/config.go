@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cache
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const defaultMemoryCapacity = 10000
+
+// Configuration is the configuration for the range query results cache.
+type Configuration struct {
+	// SplitInterval is the interval queries are split by and results are
+	// cached at, defaults to a day.
+	SplitInterval *time.Duration `yaml:"splitInterval"`
+
+	// MaxFreshness is how far behind now an interval must end before its
+	// results are cached, defaults to ten minutes.
+	MaxFreshness *time.Duration `yaml:"maxFreshness"`
+
+	// Memory is the configuration for the in-memory cache backend.
+	Memory MemoryConfiguration `yaml:"memory"`
+}
+
+// MemoryConfiguration is the configuration for the in-memory cache backend.
+type MemoryConfiguration struct {
+	// Capacity is the maximum number of cached intervals.
+	Capacity int `yaml:"capacity"`
+}
+
+// NewResultsCache creates a new results cache from the configuration.
+func (c Configuration) NewResultsCache(
+	resolutionFn ResolutionFn,
+	tagOpts models.TagOptions,
+	instrumentOpts instrument.Options,
+) (ResultsCache, error) {
+	capacity := c.Memory.Capacity
+	if capacity == 0 {
+		capacity = defaultMemoryCapacity
+	}
+
+	backend, err := NewMemoryBackend(capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := NewOptions().
+		SetBackend(backend).
+		SetTagOptions(tagOpts).
+		SetInstrumentOptions(instrumentOpts)
+	if resolutionFn != nil {
+		opts = opts.SetResolutionFn(resolutionFn)
+	}
+	if c.SplitInterval != nil {
+		opts = opts.SetSplitInterval(*c.SplitInterval)
+	}
+	if c.MaxFreshness != nil {
+		opts = opts.SetMaxFreshness(*c.MaxFreshness)
+	}
+
+	return NewResultsCache(opts)
+}
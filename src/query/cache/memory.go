@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+type memoryBackend struct {
+	sync.Mutex
+
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+// NewMemoryBackend returns an in-memory backend holding at most capacity
+// entries, evicting the least recently used entries once full.
+func NewMemoryBackend(capacity int) (Backend, error) {
+	if capacity <= 0 {
+		return nil, errInvalidMemoryCapacity
+	}
+
+	return &memoryBackend{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		lru:      list.New(),
+	}, nil
+}
+
+func (b *memoryBackend) Get(key string) ([]byte, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	elem, ok := b.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	b.lru.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).value, true
+}
+
+func (b *memoryBackend) Set(key string, value []byte) {
+	b.Lock()
+	defer b.Unlock()
+
+	if elem, ok := b.entries[key]; ok {
+		elem.Value.(*memoryEntry).value = value
+		b.lru.MoveToFront(elem)
+		return
+	}
+
+	b.entries[key] = b.lru.PushFront(&memoryEntry{key: key, value: value})
+	for b.lru.Len() > b.capacity {
+		oldest := b.lru.Back()
+		b.lru.Remove(oldest)
+		delete(b.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
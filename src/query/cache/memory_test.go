@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackendEvictsLeastRecentlyUsed(t *testing.T) {
+	backend, err := NewMemoryBackend(2)
+	require.NoError(t, err)
+
+	backend.Set("a", []byte("1"))
+	backend.Set("b", []byte("2"))
+
+	v, ok := backend.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	// NB: b is now the least recently used entry.
+	backend.Set("c", []byte("3"))
+	_, ok = backend.Get("b")
+	assert.False(t, ok)
+
+	v, ok = backend.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	backend.Set("c", []byte("4"))
+	v, ok = backend.Get("c")
+	require.True(t, ok)
+	assert.Equal(t, []byte("4"), v)
+}
+
+func TestMemoryBackendInvalidCapacity(t *testing.T) {
+	_, err := NewMemoryBackend(0)
+	require.Error(t, err)
+}
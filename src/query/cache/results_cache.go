@@ -0,0 +1,432 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+// cacheEntry is the encoded form of the results of a split interval.
+type cacheEntry struct {
+	Resolutions []int64
+	Series      []cacheSeries
+}
+
+type cacheSeries struct {
+	Name   []byte
+	Tags   []models.Tag
+	Values []float64
+}
+
+// extent is a step aligned sub-range of a query, along with the step aligned
+// range of the split interval it falls in. All ends are exclusive.
+type extent struct {
+	start         time.Time
+	end           time.Time
+	intervalStart time.Time
+	intervalEnd   time.Time
+}
+
+func (e extent) full() bool {
+	return e.start.Equal(e.intervalStart) && e.end.Equal(e.intervalEnd)
+}
+
+type resultsCacheMetrics struct {
+	hits     tally.Counter
+	misses   tally.Counter
+	uncached tally.Counter
+	stores   tally.Counter
+	bypassed tally.Counter
+	errors   tally.Counter
+}
+
+func newResultsCacheMetrics(scope tally.Scope) resultsCacheMetrics {
+	return resultsCacheMetrics{
+		hits:     scope.Counter("hits"),
+		misses:   scope.Counter("misses"),
+		uncached: scope.Counter("uncached"),
+		stores:   scope.Counter("stores"),
+		bypassed: scope.Counter("bypassed"),
+		errors:   scope.Counter("errors"),
+	}
+}
+
+type resultsCache struct {
+	backend       Backend
+	splitInterval time.Duration
+	maxFreshness  time.Duration
+	resolutionFn  ResolutionFn
+	tagOpts       models.TagOptions
+	nowFn         clock.NowFn
+	logger        *zap.Logger
+	metrics       resultsCacheMetrics
+}
+
+// NewResultsCache returns a new results cache.
+func NewResultsCache(opts Options) (ResultsCache, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	instrumentOpts := opts.InstrumentOptions()
+	return &resultsCache{
+		backend:       opts.Backend(),
+		splitInterval: opts.SplitInterval(),
+		maxFreshness:  opts.MaxFreshness(),
+		resolutionFn:  opts.ResolutionFn(),
+		tagOpts:       opts.TagOptions(),
+		nowFn:         opts.ClockOptions().NowFn(),
+		logger:        instrumentOpts.Logger(),
+		metrics:       newResultsCacheMetrics(instrumentOpts.MetricsScope()),
+	}, nil
+}
+
+func noopResolutionFn(_, _, _ time.Time, _ *storage.FetchOptions) (string, error) {
+	return "", nil
+}
+
+func (c *resultsCache) Read(
+	params models.RequestParams,
+	fetchOpts *storage.FetchOptions,
+	fn ReadFn,
+) (Result, error) {
+	if !cacheable(params) {
+		c.metrics.bypassed.Inc(1)
+		return fn(params)
+	}
+
+	now := params.Now
+	if now.IsZero() {
+		now = c.nowFn()
+	}
+
+	var (
+		cutoff  = now.Add(-c.maxFreshness)
+		extents = c.split(params)
+		results = make([]Result, 0, len(extents))
+	)
+	for _, e := range extents {
+		result, err := c.readExtent(params, fetchOpts, now, cutoff, e, fn)
+		if err != nil {
+			return Result{}, err
+		}
+
+		results = append(results, result)
+	}
+
+	return merge(params, extents, results), nil
+}
+
+// cacheable returns true if the query steps are aligned to the step size, so
+// that results computed for split intervals line up with those of any other
+// query with the same step.
+func cacheable(params models.RequestParams) bool {
+	step := int64(params.Step)
+	return step > 0 &&
+		params.Start.UnixNano()%step == 0 &&
+		params.ExclusiveEnd().After(params.Start)
+}
+
+// split splits the query into step aligned extents, one per split interval.
+func (c *resultsCache) split(params models.RequestParams) []extent {
+	var (
+		step     = int64(params.Step)
+		interval = int64(c.splitInterval)
+		end      = params.ExclusiveEnd().UnixNano()
+		extents  []extent
+	)
+	for start := params.Start.UnixNano(); start < end; {
+		boundary := start - start%interval
+		if start < 0 && start%interval != 0 {
+			boundary -= interval
+		}
+
+		intervalStart := alignUp(boundary, step)
+		intervalEnd := alignUp(boundary+interval, step)
+		extentEnd := intervalEnd
+		if extentEnd > end {
+			extentEnd = end
+		}
+
+		extents = append(extents, extent{
+			start:         time.Unix(0, start),
+			end:           time.Unix(0, extentEnd),
+			intervalStart: time.Unix(0, intervalStart),
+			intervalEnd:   time.Unix(0, intervalEnd),
+		})
+		start = intervalEnd
+	}
+
+	return extents
+}
+
+func alignUp(t, step int64) int64 {
+	rem := t % step
+	if rem == 0 {
+		return t
+	}
+	if rem < 0 {
+		return t - rem
+	}
+	return t + step - rem
+}
+
+func numSteps(start, end time.Time, step time.Duration) int {
+	return int(end.Sub(start) / step)
+}
+
+func (c *resultsCache) readExtent(
+	params models.RequestParams,
+	fetchOpts *storage.FetchOptions,
+	now time.Time,
+	cutoff time.Time,
+	e extent,
+	fn ReadFn,
+) (Result, error) {
+	// Intervals that may still receive data are never cached.
+	if e.intervalEnd.After(cutoff) {
+		c.metrics.uncached.Inc(1)
+		return execute(params, e, fn)
+	}
+
+	key, err := c.key(params, fetchOpts, now, e)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if value, ok := c.backend.Get(key); ok {
+		result, err := c.decode(value, params.Step, e)
+		if err == nil {
+			c.metrics.hits.Inc(1)
+			return result, nil
+		}
+
+		c.metrics.errors.Inc(1)
+		c.logger.Warn("could not decode cached results", zap.Error(err))
+	}
+
+	c.metrics.misses.Inc(1)
+	result, err := execute(params, e, fn)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Only complete results for whole intervals are cached.
+	if !e.full() || !result.Meta.Exhaustive || len(result.Meta.Warnings) > 0 {
+		return result, nil
+	}
+
+	value, err := encode(result)
+	if err != nil {
+		c.metrics.errors.Inc(1)
+		c.logger.Warn("could not encode results to cache", zap.Error(err))
+		return result, nil
+	}
+
+	c.backend.Set(key, value)
+	c.metrics.stores.Inc(1)
+	return result, nil
+}
+
+func execute(params models.RequestParams, e extent, fn ReadFn) (Result, error) {
+	params.Start = e.start
+	params.End = e.end
+	params.IncludeEnd = false
+	return fn(params)
+}
+
+// key returns the cache key for the split interval of the extent. Any change
+// to the query, its step or lookback, the fetch restrictions, or the resolution
+// of the data the interval resolves to yields a different key.
+func (c *resultsCache) key(
+	params models.RequestParams,
+	fetchOpts *storage.FetchOptions,
+	now time.Time,
+	e extent,
+) (string, error) {
+	resolution, err := c.resolutionFn(now,
+		e.intervalStart.Add(-params.LookbackDuration), e.intervalEnd, fetchOpts)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%d\x00%s\x00%s", params.Query,
+		params.Step, e.intervalStart.UnixNano(), params.LookbackDuration,
+		fetchOptionsKey(fetchOpts), resolution)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fetchOptionsKey encodes every fetch option that changes the results of a
+// query so that results fetched with different options are never shared.
+func fetchOptionsKey(fetchOpts *storage.FetchOptions) string {
+	if fetchOpts == nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "limit=%d,block=%d,resolution=%t,tenant=%q",
+		fetchOpts.Limit, fetchOpts.BlockType, fetchOpts.IncludeResolution,
+		fetchOpts.Tenant)
+	if lookback := fetchOpts.LookbackDuration; lookback != nil {
+		fmt.Fprintf(&buf, ",lookback=%d", *lookback)
+	}
+	if fanout := fetchOpts.FanoutOptions; fanout != nil {
+		fmt.Fprintf(&buf, ",fanout=%d/%d/%d", fanout.FanoutUnaggregated,
+			fanout.FanoutAggregated, fanout.FanoutAggregatedOptimized)
+	}
+	if restrict := fetchOpts.RestrictQueryOptions.GetRestrictByType(); restrict != nil {
+		fmt.Fprintf(&buf, ",restrict=%d/%s", restrict.MetricsType,
+			restrict.StoragePolicy.String())
+	}
+	if restrict := fetchOpts.RestrictQueryOptions.GetRestrictByTag(); restrict != nil {
+		fmt.Fprintf(&buf, ",restrictTags=%q", restrict.Restrict.String())
+		if restrict.Strip != nil {
+			buf.WriteString(",strip=")
+			for _, name := range restrict.Strip {
+				fmt.Fprintf(&buf, "%q", name)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func encode(result Result) ([]byte, error) {
+	entry := cacheEntry{
+		Resolutions: result.Meta.Resolutions,
+		Series:      make([]cacheSeries, 0, len(result.Series)),
+	}
+	for _, s := range result.Series {
+		values := make([]float64, s.Len())
+		for i := range values {
+			values[i] = s.Values().ValueAt(i)
+		}
+
+		entry.Series = append(entry.Series, cacheSeries{
+			Name:   s.Name(),
+			Tags:   s.Tags.Tags,
+			Values: values,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode decodes the cached results of a split interval, returning only the
+// steps that fall within the extent.
+func (c *resultsCache) decode(
+	value []byte,
+	step time.Duration,
+	e extent,
+) (Result, error) {
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(value)).Decode(&entry); err != nil {
+		return Result{}, err
+	}
+
+	var (
+		offset = numSteps(e.intervalStart, e.start, step)
+		count  = numSteps(e.start, e.end, step)
+		meta   = block.NewResultMetadata()
+		series = make([]*ts.Series, 0, len(entry.Series))
+	)
+	meta.Resolutions = entry.Resolutions
+	for _, s := range entry.Series {
+		if len(s.Values) < offset+count {
+			return Result{}, fmt.Errorf(
+				"cached series has %d values, expected at least %d",
+				len(s.Values), offset+count)
+		}
+
+		values := ts.NewFixedStepValues(step, count, math.NaN(), e.start)
+		for i := 0; i < count; i++ {
+			values.SetValueAt(i, s.Values[offset+i])
+		}
+
+		tags := models.NewTags(len(s.Tags), c.tagOpts).AddTags(s.Tags)
+		series = append(series, ts.NewSeries(s.Name, values, tags))
+	}
+
+	return Result{Series: series, Meta: meta}, nil
+}
+
+// merge stitches together the results of each extent of a query, matching
+// series across extents by their tags.
+func merge(
+	params models.RequestParams,
+	extents []extent,
+	results []Result,
+) Result {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	var (
+		step   = params.Step
+		total  = numSteps(params.Start, params.ExclusiveEnd(), step)
+		meta   = block.NewResultMetadata()
+		byID   = make(map[string]int)
+		series []*ts.Series
+		values []ts.FixedResolutionMutableValues
+		offset int
+	)
+	for i, result := range results {
+		meta = meta.CombineMetadata(result.Meta)
+		for _, s := range result.Series {
+			id := string(s.Tags.ID())
+			idx, ok := byID[id]
+			if !ok {
+				idx = len(series)
+				byID[id] = idx
+				v := ts.NewFixedStepValues(step, total, math.NaN(), params.Start)
+				values = append(values, v)
+				series = append(series, ts.NewSeries(s.Name(), v, s.Tags))
+			}
+
+			v := values[idx]
+			for j := 0; j < s.Len() && offset+j < total; j++ {
+				v.SetValueAt(offset+j, s.Values().ValueAt(j))
+			}
+		}
+
+		offset += numSteps(extents[i].start, extents[i].end, step)
+	}
+
+	return Result{Series: series, Meta: meta}
+}
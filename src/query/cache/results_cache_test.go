@@ -0,0 +1,315 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cache
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type testReader struct {
+	calls  []models.RequestParams
+	meta   block.ResultMetadata
+	tagOpt models.TagOptions
+}
+
+func newTestReader() *testReader {
+	return &testReader{
+		meta:   block.NewResultMetadata(),
+		tagOpt: models.NewTagOptions(),
+	}
+}
+
+func (r *testReader) tags(name string) models.Tags {
+	return models.NewTags(1, r.tagOpt).AddTag(models.Tag{
+		Name:  []byte("__name__"),
+		Value: []byte(name),
+	})
+}
+
+// read returns a series with the hours since the test start as values, and a
+// second series that only has values from the second day onwards.
+func (r *testReader) read(params models.RequestParams) (Result, error) {
+	r.calls = append(r.calls, params)
+
+	var (
+		count  = numSteps(params.Start, params.ExclusiveEnd(), params.Step)
+		hours  = ts.NewFixedStepValues(params.Step, count, math.NaN(), params.Start)
+		late   = ts.NewFixedStepValues(params.Step, count, math.NaN(), params.Start)
+		series = []*ts.Series{ts.NewSeries([]byte("hours"), hours, r.tags("hours"))}
+	)
+	hasLate := false
+	for i := 0; i < count; i++ {
+		t := params.Start.Add(time.Duration(i) * params.Step)
+		hours.SetValueAt(i, t.Sub(testStart).Hours())
+		if !t.Before(testStart.Add(24 * time.Hour)) {
+			late.SetValueAt(i, 1)
+			hasLate = true
+		}
+	}
+	if hasLate {
+		series = append(series, ts.NewSeries([]byte("late"), late, r.tags("late")))
+	}
+
+	return Result{Series: series, Meta: r.meta}, nil
+}
+
+func newTestResultsCache(t *testing.T, resolutionFn ResolutionFn) ResultsCache {
+	backend, err := NewMemoryBackend(100)
+	require.NoError(t, err)
+
+	opts := NewOptions().
+		SetBackend(backend).
+		SetSplitInterval(24 * time.Hour).
+		SetMaxFreshness(time.Hour)
+	if resolutionFn != nil {
+		opts = opts.SetResolutionFn(resolutionFn)
+	}
+
+	cache, err := NewResultsCache(opts)
+	require.NoError(t, err)
+	return cache
+}
+
+func testParams(start, end, now time.Time) models.RequestParams {
+	return models.RequestParams{
+		Query:            "hours",
+		Start:            start,
+		End:              end,
+		Now:              now,
+		Step:             time.Hour,
+		LookbackDuration: 5 * time.Minute,
+	}
+}
+
+func requireSeriesValues(
+	t *testing.T,
+	expected map[string][]float64,
+	result Result,
+) {
+	require.Equal(t, len(expected), len(result.Series))
+	for _, s := range result.Series {
+		values, ok := expected[string(s.Name())]
+		require.True(t, ok, "unexpected series %s", s.Name())
+		require.Equal(t, len(values), s.Len(), "series %s", s.Name())
+		for i, v := range values {
+			if math.IsNaN(v) {
+				assert.True(t, math.IsNaN(s.Values().ValueAt(i)),
+					"series %s step %d", s.Name(), i)
+				continue
+			}
+			assert.Equal(t, v, s.Values().ValueAt(i), "series %s step %d", s.Name(), i)
+		}
+	}
+}
+
+func expectedValues(from, count int, lateFrom int) map[string][]float64 {
+	hours := make([]float64, 0, count)
+	late := make([]float64, 0, count)
+	for i := from; i < from+count; i++ {
+		hours = append(hours, float64(i))
+		if i >= lateFrom {
+			late = append(late, 1)
+		} else {
+			late = append(late, math.NaN())
+		}
+	}
+
+	return map[string][]float64{"hours": hours, "late": late}
+}
+
+func TestResultsCacheCachesCompletedIntervals(t *testing.T) {
+	var (
+		cache  = newTestResultsCache(t, nil)
+		reader = newTestReader()
+		start  = testStart
+		end    = testStart.Add(60 * time.Hour)
+		now    = testStart.Add(96 * time.Hour)
+		params = testParams(start, end, now)
+	)
+
+	result, err := cache.Read(params, storage.NewFetchOptions(), reader.read)
+	require.NoError(t, err)
+	requireSeriesValues(t, expectedValues(0, 60, 24), result)
+	require.Equal(t, 3, len(reader.calls))
+	assert.Equal(t, start, reader.calls[0].Start)
+	assert.Equal(t, start.Add(24*time.Hour), reader.calls[0].End)
+	assert.Equal(t, start.Add(48*time.Hour), reader.calls[2].Start)
+	assert.Equal(t, end, reader.calls[2].End)
+
+	// The first two days are cached, the partial third day is not.
+	reader.calls = nil
+	result, err = cache.Read(params, storage.NewFetchOptions(), reader.read)
+	require.NoError(t, err)
+	requireSeriesValues(t, expectedValues(0, 60, 24), result)
+	require.Equal(t, 1, len(reader.calls))
+	assert.Equal(t, start.Add(48*time.Hour), reader.calls[0].Start)
+
+	// A query starting part way through a cached day is served from the
+	// cached day.
+	reader.calls = nil
+	params = testParams(start.Add(6*time.Hour), start.Add(30*time.Hour), now)
+	params.IncludeEnd = true
+	result, err = cache.Read(params, storage.NewFetchOptions(), reader.read)
+	require.NoError(t, err)
+	requireSeriesValues(t, expectedValues(6, 25, 24), result)
+	require.Equal(t, 0, len(reader.calls))
+}
+
+func TestResultsCacheDoesNotCacheFreshIntervals(t *testing.T) {
+	var (
+		cache  = newTestResultsCache(t, nil)
+		reader = newTestReader()
+		start  = testStart
+		end    = testStart.Add(36 * time.Hour)
+		params = testParams(start, end, testStart.Add(36*time.Hour))
+	)
+
+	for i := 0; i < 2; i++ {
+		reader.calls = nil
+		result, err := cache.Read(params, storage.NewFetchOptions(), reader.read)
+		require.NoError(t, err)
+		requireSeriesValues(t, expectedValues(0, 36, 24), result)
+	}
+
+	// Only the tail of the query that is still receiving data is fetched.
+	require.Equal(t, 1, len(reader.calls))
+	assert.Equal(t, start.Add(24*time.Hour), reader.calls[0].Start)
+	assert.Equal(t, end, reader.calls[0].End)
+}
+
+func TestResultsCacheDoesNotCacheIncompleteResults(t *testing.T) {
+	var (
+		cache  = newTestResultsCache(t, nil)
+		reader = newTestReader()
+		params = testParams(testStart, testStart.Add(24*time.Hour),
+			testStart.Add(96*time.Hour))
+	)
+
+	reader.meta.Exhaustive = false
+	for i := 0; i < 2; i++ {
+		_, err := cache.Read(params, storage.NewFetchOptions(), reader.read)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, len(reader.calls))
+}
+
+func TestResultsCacheInvalidatedOnResolutionChange(t *testing.T) {
+	var (
+		resolution   = "10s"
+		resolutionFn = func(
+			_, _, _ time.Time,
+			_ *storage.FetchOptions,
+		) (string, error) {
+			return resolution, nil
+		}
+		cache  = newTestResultsCache(t, resolutionFn)
+		reader = newTestReader()
+		params = testParams(testStart, testStart.Add(24*time.Hour),
+			testStart.Add(96*time.Hour))
+	)
+
+	read := func() {
+		result, err := cache.Read(params, storage.NewFetchOptions(), reader.read)
+		require.NoError(t, err)
+		requireSeriesValues(t, map[string][]float64{
+			"hours": expectedValues(0, 24, 24)["hours"],
+		}, result)
+	}
+
+	read()
+	read()
+	require.Equal(t, 1, len(reader.calls))
+
+	resolution = "1m"
+	read()
+	require.Equal(t, 2, len(reader.calls))
+
+	// A changed lookback also misses the cache.
+	params.LookbackDuration = time.Minute
+	read()
+	require.Equal(t, 3, len(reader.calls))
+}
+
+func TestResultsCacheBypassesUnalignedQueries(t *testing.T) {
+	var (
+		cache  = newTestResultsCache(t, nil)
+		reader = newTestReader()
+		params = testParams(testStart.Add(time.Minute), testStart.Add(48*time.Hour),
+			testStart.Add(96*time.Hour))
+	)
+
+	_, err := cache.Read(params, storage.NewFetchOptions(), reader.read)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(reader.calls))
+	assert.Equal(t, params, reader.calls[0])
+}
+
+func TestResultsCacheKeyedByFetchOptions(t *testing.T) {
+	var (
+		cache  = newTestResultsCache(t, nil)
+		reader = newTestReader()
+		params = testParams(testStart, testStart.Add(24*time.Hour),
+			testStart.Add(96*time.Hour))
+	)
+
+	read := func(fetchOpts *storage.FetchOptions) {
+		_, err := cache.Read(params, fetchOpts, reader.read)
+		require.NoError(t, err)
+	}
+
+	fetchOpts := storage.NewFetchOptions()
+	read(fetchOpts)
+	read(fetchOpts)
+	require.Equal(t, 1, len(reader.calls))
+
+	// A different tenant misses the cache.
+	tenantOpts := fetchOpts.Clone()
+	tenantOpts.Tenant = "foo"
+	read(tenantOpts)
+	require.Equal(t, 2, len(reader.calls))
+
+	// A different tag restriction misses the cache.
+	matcher, err := models.NewMatcher(models.MatchEqual,
+		[]byte("env"), []byte("prod"))
+	require.NoError(t, err)
+	restrictOpts := fetchOpts.Clone()
+	restrictOpts.RestrictQueryOptions = &storage.RestrictQueryOptions{
+		RestrictByTag: &storage.RestrictByTag{
+			Restrict: models.Matchers{matcher},
+		},
+	}
+	read(restrictOpts)
+	require.Equal(t, 3, len(reader.calls))
+	read(restrictOpts)
+	require.Equal(t, 3, len(reader.calls))
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package cache provides a step aligned results cache for range queries.
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	errNoBackend             = errors.New("no results cache backend set")
+	errInvalidSplitInterval  = errors.New("results cache split interval must be positive")
+	errInvalidMaxFreshness   = errors.New("results cache max freshness must not be negative")
+	errNoResolutionFn        = errors.New("no results cache resolution function set")
+	errInvalidMemoryCapacity = errors.New("results cache memory capacity must be positive")
+)
+
+// Backend is a pluggable store for encoded query results.
+type Backend interface {
+	// Get returns the value stored for the key, if any.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the value for the key.
+	Set(key string, value []byte)
+}
+
+// Result is the result of a range query.
+type Result struct {
+	// Series is the list of resulting series.
+	Series []*ts.Series
+	// Meta is the metadata for the result.
+	Meta block.ResultMetadata
+}
+
+// ReadFn executes a range query for the given request params.
+type ReadFn func(params models.RequestParams) (Result, error)
+
+// ResolutionFn returns a key describing the resolution and retention of the
+// data that a query over the given range would currently be served from.
+// Cached results are only reused while this key stays the same.
+type ResolutionFn func(
+	now, start, end time.Time,
+	fetchOpts *storage.FetchOptions,
+) (string, error)
+
+// ResultsCache caches the results of range queries. Queries are split into
+// step aligned intervals, completed intervals are cached and only uncached
+// intervals are executed.
+type ResultsCache interface {
+	// Read returns the results for the range query described by params, using
+	// cached results where available and calling fn for any uncached ranges.
+	Read(
+		params models.RequestParams,
+		fetchOpts *storage.FetchOptions,
+		fn ReadFn,
+	) (Result, error)
+}
+
+// Options are the options for the results cache.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetBackend sets the backend used to store results.
+	SetBackend(value Backend) Options
+
+	// Backend returns the backend used to store results.
+	Backend() Backend
+
+	// SetSplitInterval sets the interval queries are split by.
+	SetSplitInterval(value time.Duration) Options
+
+	// SplitInterval returns the interval queries are split by.
+	SplitInterval() time.Duration
+
+	// SetMaxFreshness sets how far behind now an interval must end before its
+	// results are cached, allowing for late arriving data.
+	SetMaxFreshness(value time.Duration) Options
+
+	// MaxFreshness returns how far behind now an interval must end before its
+	// results are cached, allowing for late arriving data.
+	MaxFreshness() time.Duration
+
+	// SetResolutionFn sets the resolution function.
+	SetResolutionFn(value ResolutionFn) Options
+
+	// ResolutionFn returns the resolution function.
+	ResolutionFn() ResolutionFn
+
+	// SetTagOptions sets the tag options.
+	SetTagOptions(value models.TagOptions) Options
+
+	// TagOptions returns the tag options.
+	TagOptions() models.TagOptions
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -34,6 +34,8 @@ const (
 	QueryLevel = "query"
 	// GlobalLevel identifies global enforcers.
 	GlobalLevel = "global"
+	// TenantLevel identifies per-tenant enforcers.
+	TenantLevel = "tenant"
 )
 
 // ChainedEnforcer is a cost.Enforcer implementation which tracks resource usage implements cost.Enforcer to enforce
@@ -44,6 +46,11 @@ type ChainedEnforcer interface {
 	// Child creates a new ChainedEnforcer which rolls up to this one.
 	Child(resourceName string) ChainedEnforcer
 
+	// ChildWithEnforcer creates a new ChainedEnforcer which rolls up to this
+	// one, enforced by the given enforcer rather than the next configured one.
+	// Its children are created in the same way as this enforcer's children.
+	ChildWithEnforcer(resourceName string, local cost.Enforcer) ChainedEnforcer
+
 	// Close indicates that all resources have been returned for this
 	// ChainedEnforcer. It should inform all parent enforcers that the
 	// resources have been freed.
@@ -149,13 +156,41 @@ func (ce *chainedEnforcer) Add(c cost.Cost) cost.Report {
 func (ce *chainedEnforcer) wrapLocalResult(localR cost.Report) cost.Report {
 	if localR.Error != nil {
 		return cost.Report{
-			Cost:  localR.Cost,
-			Error: fmt.Errorf("exceeded %s limit: %s", ce.resourceName, localR.Error.Error()),
+			Cost: localR.Cost,
+			Error: exceededLimitError{
+				resourceName: ce.resourceName,
+				inner:        localR.Error,
+			},
 		}
 	}
 	return localR
 }
 
+// exceededLimitError is the error returned when the limit of a resource is
+// exceeded.
+type exceededLimitError struct {
+	resourceName string
+	inner        error
+}
+
+func (e exceededLimitError) Error() string {
+	return fmt.Sprintf("exceeded %s limit: %s", e.resourceName, e.inner.Error())
+}
+
+func (e exceededLimitError) InnerError() error {
+	return e.inner
+}
+
+// ExceededLimitResource returns the name of the resource whose limit was
+// exceeded if the error was returned from exceeding a limit.
+func ExceededLimitResource(err error) (string, bool) {
+	if e, ok := err.(exceededLimitError); ok {
+		return e.resourceName, true
+	}
+
+	return "", false
+}
+
 // Child creates a new chainedEnforcer whose resource consumption rolls up into this instance.
 func (ce *chainedEnforcer) Child(resourceName string) ChainedEnforcer {
 	// no more models; just return a noop default. TODO: this could be a panic case? Technically speaking it's
@@ -176,6 +211,21 @@ func (ce *chainedEnforcer) Child(resourceName string) ChainedEnforcer {
 	}
 }
 
+// ChildWithEnforcer creates a new chainedEnforcer enforced by the given
+// enforcer whose resource consumption rolls up into this instance.
+func (ce *chainedEnforcer) ChildWithEnforcer(
+	resourceName string,
+	local cost.Enforcer,
+) ChainedEnforcer {
+	return &chainedEnforcer{
+		resourceName: resourceName,
+		parent:       ce,
+		local:        local,
+		models:       ce.models,
+		reporter:     upcastReporterOrNoop(local.Reporter()),
+	}
+}
+
 // Clone on a chainedEnforcer is a noop--TODO: implement?
 func (ce *chainedEnforcer) Clone() cost.Enforcer {
 	return ce
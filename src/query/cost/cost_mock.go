@@ -140,6 +140,20 @@ func (mr *MockChainedEnforcerMockRecorder) Child(resourceName interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Child", reflect.TypeOf((*MockChainedEnforcer)(nil).Child), resourceName)
 }
 
+// ChildWithEnforcer mocks base method
+func (m *MockChainedEnforcer) ChildWithEnforcer(resourceName string, local cost0.Enforcer) ChainedEnforcer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChildWithEnforcer", resourceName, local)
+	ret0, _ := ret[0].(ChainedEnforcer)
+	return ret0
+}
+
+// ChildWithEnforcer indicates an expected call of ChildWithEnforcer
+func (mr *MockChainedEnforcerMockRecorder) ChildWithEnforcer(resourceName, local interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChildWithEnforcer", reflect.TypeOf((*MockChainedEnforcer)(nil).ChildWithEnforcer), resourceName, local)
+}
+
 // Close mocks base method
 func (m *MockChainedEnforcer) Close() {
 	m.ctrl.T.Helper()
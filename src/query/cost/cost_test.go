@@ -21,6 +21,7 @@
 package cost
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -203,6 +204,44 @@ func TestChainedEnforcer_Add(t *testing.T) {
 	})
 }
 
+func TestChainedEnforcer_ChildWithEnforcer(t *testing.T) {
+	global, err := NewChainedEnforcer(GlobalLevel, []cost.Enforcer{
+		newTestEnforcer(cost.Limit{Threshold: 100.0, Enabled: true}),
+		newTestEnforcer(cost.Limit{Threshold: 50.0, Enabled: true}),
+	})
+	require.NoError(t, err)
+
+	tenant := global.ChildWithEnforcer(TenantLevel,
+		newTestEnforcer(cost.Limit{Threshold: 10.0, Enabled: true}))
+	query := tenant.Child(QueryLevel)
+
+	r := query.Add(6.0)
+	require.NoError(t, r.Error)
+	test.AssertCurrentCost(t, 6.0, tenant)
+	test.AssertCurrentCost(t, 6.0, global)
+
+	r = query.Add(6.0)
+	require.Error(t, r.Error)
+	resource, ok := ExceededLimitResource(r.Error)
+	require.True(t, ok)
+	assert.Equal(t, TenantLevel, resource)
+
+	query.Close()
+	test.AssertCurrentCost(t, 0.0, tenant)
+	test.AssertCurrentCost(t, 0.0, global)
+}
+
+func TestExceededLimitResource(t *testing.T) {
+	_, ok := ExceededLimitResource(errors.New("foo"))
+	assert.False(t, ok)
+
+	pqe := newTestChainedEnforcer(100.0, 5.0)
+	r := pqe.Add(6.0)
+	resource, ok := ExceededLimitResource(r.Error)
+	require.True(t, ok)
+	assert.Equal(t, "query", resource)
+}
+
 func TestChainedEnforcer_State(t *testing.T) {
 	pqe := newTestChainedEnforcer(10.0, 5.0)
 	pqe.Add(15.0)
@@ -41,6 +41,10 @@ type engine struct {
 // QueryOptions can be used to pass custom flags to engine.
 type QueryOptions struct {
 	QueryContextOptions models.QueryContextOptions
+
+	// Enforcer is the enforcer the per query enforcer rolls up into, if not
+	// set the engine's global enforcer is used.
+	Enforcer qcost.ChainedEnforcer
 }
 
 // Query is the result after execution.
@@ -125,7 +129,12 @@ func (e *engine) ExecuteExpr(
 	fetchOpts *storage.FetchOptions,
 	params models.RequestParams,
 ) (Result, error) {
-	perQueryEnforcer := e.opts.GlobalEnforcer().Child(qcost.QueryLevel)
+	parentEnforcer := e.opts.GlobalEnforcer()
+	if opts.Enforcer != nil {
+		parentEnforcer = opts.Enforcer
+	}
+
+	perQueryEnforcer := parentEnforcer.Child(qcost.QueryLevel)
 	defer perQueryEnforcer.Close()
 	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
 	nodes, edges, err := req.compile(ctx, parser)
@@ -133,6 +142,11 @@ func (e *engine) ExecuteExpr(
 		return nil, err
 	}
 
+	if shards := e.queryShards(params); len(shards) > 0 {
+		return e.executeExprSharded(ctx, nodes, edges, opts, fetchOpts, params,
+			shards, perQueryEnforcer)
+	}
+
 	pp, err := req.plan(ctx, nodes, edges)
 	if err != nil {
 		return nil, err
@@ -163,6 +177,51 @@ func (e *engine) ExecuteExpr(
 	return result, nil
 }
 
+// executeExprSharded evaluates the query as a set of time shards, sending the
+// merged result of all shards as a single block.
+func (e *engine) executeExprSharded(
+	ctx context.Context,
+	nodes parser.Nodes,
+	edges parser.Edges,
+	opts *QueryOptions,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+	shards []queryShard,
+	perQueryEnforcer qcost.ChainedEnforcer,
+) (Result, error) {
+	// NB: validate the plan once up front so that planning errors are returned
+	// directly rather than through the result channel.
+	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
+	if _, err := req.plan(ctx, nodes, edges); err != nil {
+		return nil, err
+	}
+
+	var (
+		result   = newResultNode()
+		scope    = e.opts.InstrumentOptions().MetricsScope()
+		queryCtx = models.NewQueryContext(ctx, scope, perQueryEnforcer,
+			opts.QueryContextOptions)
+	)
+
+	go func() {
+		bl, err := e.executeSharded(ctx, queryCtx, nodes, edges, fetchOpts,
+			params, shards)
+		if err != nil {
+			result.abort(err)
+			return
+		}
+
+		if err := result.Process(queryCtx, parser.NodeID(""), bl); err != nil {
+			result.abort(err)
+			return
+		}
+
+		result.done()
+	}()
+
+	return result, nil
+}
+
 func (e *engine) Options() EngineOptions {
 	return e.opts
 }
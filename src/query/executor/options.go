@@ -29,18 +29,24 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 )
 
+const defaultShardConcurrency = 4
+
 type engineOptions struct {
-	instrumentOpts   instrument.Options
-	globalEnforcer   qcost.ChainedEnforcer
-	store            storage.Storage
-	parseOptions     promql.ParseOptions
-	lookbackDuration time.Duration
+	instrumentOpts    instrument.Options
+	globalEnforcer    qcost.ChainedEnforcer
+	store             storage.Storage
+	parseOptions      promql.ParseOptions
+	lookbackDuration  time.Duration
+	shardInterval     time.Duration
+	shardConcurrency  int
+	shardBoundariesFn ShardBoundariesFn
 }
 
 // NewEngineOptions returns a new instance of options used to create an engine.
 func NewEngineOptions() EngineOptions {
 	return &engineOptions{
-		parseOptions: promql.NewParseOptions(),
+		parseOptions:     promql.NewParseOptions(),
+		shardConcurrency: defaultShardConcurrency,
 	}
 }
 
@@ -93,3 +99,33 @@ func (o *engineOptions) SetParseOptions(p promql.ParseOptions) EngineOptions {
 	opts.parseOptions = p
 	return &opts
 }
+
+func (o *engineOptions) ShardInterval() time.Duration {
+	return o.shardInterval
+}
+
+func (o *engineOptions) SetShardInterval(v time.Duration) EngineOptions {
+	opts := *o
+	opts.shardInterval = v
+	return &opts
+}
+
+func (o *engineOptions) ShardConcurrency() int {
+	return o.shardConcurrency
+}
+
+func (o *engineOptions) SetShardConcurrency(v int) EngineOptions {
+	opts := *o
+	opts.shardConcurrency = v
+	return &opts
+}
+
+func (o *engineOptions) ShardBoundariesFn() ShardBoundariesFn {
+	return o.shardBoundariesFn
+}
+
+func (o *engineOptions) SetShardBoundariesFn(v ShardBoundariesFn) EngineOptions {
+	opts := *o
+	opts.shardBoundariesFn = v
+	return &opts
+}
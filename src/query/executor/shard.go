@@ -0,0 +1,505 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package executor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/opentracing"
+)
+
+// queryShard is a step aligned time range of a query that is evaluated
+// independently of the rest of the query. The end is exclusive.
+type queryShard struct {
+	start time.Time
+	end   time.Time
+}
+
+// params returns the request params used to evaluate the shard.
+func (s queryShard) params(params models.RequestParams) models.RequestParams {
+	params.Start = s.start
+	params.End = s.end
+	params.IncludeEnd = false
+	return params
+}
+
+// queryShards splits the query range into time shards of at most the
+// configured shard interval, additionally splitting at any boundaries where
+// the namespaces the range resolves to change. Returns nil if the query is not
+// split.
+func (e *engine) queryShards(params models.RequestParams) []queryShard {
+	interval := e.opts.ShardInterval()
+	if interval <= 0 || params.Step <= 0 {
+		return nil
+	}
+
+	end := params.ExclusiveEnd()
+	if end.Sub(params.Start) <= interval {
+		return nil
+	}
+
+	var boundaries []time.Time
+	if fn := e.opts.ShardBoundariesFn(); fn != nil {
+		now := params.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+
+		boundaries = fn(now, params.Start, end)
+		sort.Slice(boundaries, func(i, j int) bool {
+			return boundaries[i].Before(boundaries[j])
+		})
+	}
+
+	var shards []queryShard
+	for start := params.Start; start.Before(end); {
+		next := start.Add(interval)
+		for _, b := range boundaries {
+			if b.After(start) && b.Before(next) {
+				next = b
+				break
+			}
+		}
+
+		// NB: shards must start on a step of the query so that each shard
+		// evaluates the same steps the unsplit query would.
+		steps := (next.Sub(params.Start) + params.Step - 1) / params.Step
+		next = params.Start.Add(steps * params.Step)
+		if next.After(end) {
+			next = end
+		}
+
+		shards = append(shards, queryShard{start: start, end: next})
+		start = next
+	}
+
+	if len(shards) < 2 {
+		return nil
+	}
+
+	return shards
+}
+
+// shardBlock is the result block of a single shard along with the index of
+// each of its series in the merged result.
+type shardBlock struct {
+	start   time.Time
+	block   block.Block
+	indices []int
+}
+
+// shardResults collects the result blocks of each shard of a query so that
+// they can be streamed as a single block spanning the whole query range.
+//
+// NB: each shard evaluates the complete query, including any aggregations,
+// over a disjoint set of steps, so aggregations such as sum, count, min and
+// max are already computed within the shard that owns each step and there are
+// no partial aggregates to merge. Pushing partial aggregations down to shards
+// which split the series of a query, rather than its steps, is out of scope.
+type shardResults struct {
+	sync.Mutex
+
+	start      time.Time
+	step       time.Duration
+	numSteps   int
+	meta       block.ResultMetadata
+	tagOpts    models.TagOptions
+	index      map[string]int
+	seriesMeta []block.SeriesMeta
+	blocks     []shardBlock
+}
+
+func newShardResults(params models.RequestParams) *shardResults {
+	return &shardResults{
+		start:    params.Start,
+		step:     params.Step,
+		numSteps: int(params.ExclusiveEnd().Sub(params.Start) / params.Step),
+		meta:     block.NewResultMetadata(),
+		index:    make(map[string]int),
+	}
+}
+
+// add takes ownership of a shard result block, mapping its series onto the
+// series of the merged result. The block is closed when the merged block is
+// closed, or by add if it returns an error.
+func (r *shardResults) add(bl block.Block) error {
+	iter, err := bl.StepIter()
+	if err != nil {
+		var multiErr xerrors.MultiError
+		return multiErr.Add(err).Add(bl.Close()).FinalError()
+	}
+
+	metas := iter.SeriesMeta()
+	iter.Close()
+
+	r.Lock()
+	defer r.Unlock()
+
+	blockMeta := bl.Meta()
+	r.meta = r.meta.CombineMetadata(blockMeta.ResultMetadata)
+	if r.tagOpts == nil {
+		r.tagOpts = blockMeta.Tags.Opts
+	}
+
+	indices := make([]int, 0, len(metas))
+	for _, meta := range metas {
+		meta.Tags = meta.Tags.AddTags(blockMeta.Tags.Tags)
+		id := string(meta.Tags.ID())
+		idx, ok := r.index[id]
+		if !ok {
+			idx = len(r.seriesMeta)
+			r.index[id] = idx
+			r.seriesMeta = append(r.seriesMeta, meta)
+		}
+
+		indices = append(indices, idx)
+	}
+
+	r.blocks = append(r.blocks, shardBlock{
+		start:   blockMeta.Bounds.Start,
+		block:   bl,
+		indices: indices,
+	})
+
+	return nil
+}
+
+// close closes all shard result blocks added so far.
+func (r *shardResults) close() error {
+	r.Lock()
+	defer r.Unlock()
+
+	var multiErr xerrors.MultiError
+	for _, bl := range r.blocks {
+		multiErr = multiErr.Add(bl.block.Close())
+	}
+
+	r.blocks = nil
+	return multiErr.FinalError()
+}
+
+// block returns a block of the merged series spanning the whole query range,
+// which takes ownership of the shard result blocks.
+func (r *shardResults) block() block.Block {
+	r.Lock()
+	defer r.Unlock()
+
+	tagOpts := r.tagOpts
+	if tagOpts == nil {
+		tagOpts = models.NewTagOptions()
+	}
+
+	blocks := r.blocks
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].start.Before(blocks[j].start)
+	})
+
+	r.blocks = nil
+	return &shardedBlock{
+		meta: block.Metadata{
+			Bounds: models.Bounds{
+				Start:    r.start,
+				Duration: time.Duration(r.numSteps) * r.step,
+				StepSize: r.step,
+			},
+			Tags:           models.NewTags(0, tagOpts),
+			ResultMetadata: r.meta,
+		},
+		seriesMeta: r.seriesMeta,
+		numSteps:   r.numSteps,
+		blocks:     blocks,
+	}
+}
+
+// shardedBlock is a block which streams the result blocks of each shard of a
+// query in step order.
+type shardedBlock struct {
+	meta       block.Metadata
+	seriesMeta []block.SeriesMeta
+	numSteps   int
+	blocks     []shardBlock
+}
+
+func (b *shardedBlock) Meta() block.Metadata {
+	return b.meta
+}
+
+func (b *shardedBlock) Info() block.BlockInfo {
+	return block.NewBlockInfo(block.BlockDecompressed)
+}
+
+func (b *shardedBlock) Close() error {
+	var multiErr xerrors.MultiError
+	for _, bl := range b.blocks {
+		multiErr = multiErr.Add(bl.block.Close())
+	}
+
+	return multiErr.FinalError()
+}
+
+// SeriesIter is invalid for a sharded block.
+func (b *shardedBlock) SeriesIter() (block.SeriesIter, error) {
+	return nil, errors.New("series iterator undefined for a sharded block")
+}
+
+// MultiSeriesIter is invalid for a sharded block.
+func (b *shardedBlock) MultiSeriesIter(_ int) ([]block.SeriesIterBatch, error) {
+	return nil, errors.New("multi series iterator undefined for a sharded block")
+}
+
+func (b *shardedBlock) StepIter() (block.StepIter, error) {
+	iters := make([]block.StepIter, 0, len(b.blocks))
+	for _, bl := range b.blocks {
+		iter, err := bl.block.StepIter()
+		if err != nil {
+			for _, iter := range iters {
+				iter.Close()
+			}
+
+			return nil, err
+		}
+
+		iters = append(iters, iter)
+	}
+
+	return &shardedStepIter{
+		block: b,
+		iters: iters,
+		idx:   -1,
+	}, nil
+}
+
+// shardedStepIter iterates the steps of each shard result block in turn,
+// filling steps and series missing from a shard with NaNs.
+type shardedStepIter struct {
+	block   *shardedBlock
+	iters   []block.StepIter
+	shard   int
+	peeked  bool
+	idx     int
+	current block.Step
+	err     error
+}
+
+func (it *shardedStepIter) SeriesMeta() []block.SeriesMeta {
+	return it.block.seriesMeta
+}
+
+func (it *shardedStepIter) StepCount() int {
+	return it.block.numSteps
+}
+
+func (it *shardedStepIter) Current() block.Step {
+	return it.current
+}
+
+func (it *shardedStepIter) Err() error {
+	return it.err
+}
+
+func (it *shardedStepIter) Close() {
+	for _, iter := range it.iters {
+		iter.Close()
+	}
+}
+
+func (it *shardedStepIter) Next() bool {
+	if it.err != nil || it.idx+1 >= it.block.numSteps {
+		return false
+	}
+
+	it.idx++
+	bounds := it.block.meta.Bounds
+	values := make([]float64, len(it.block.seriesMeta))
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	for it.shard < len(it.iters) {
+		iter := it.iters[it.shard]
+		if !it.peeked {
+			if !iter.Next() {
+				if err := iter.Err(); err != nil {
+					it.err = err
+					return false
+				}
+
+				it.shard++
+				continue
+			}
+
+			it.peeked = true
+		}
+
+		step := iter.Current()
+		idx := int(step.Time().Sub(bounds.Start) / bounds.StepSize)
+		if idx > it.idx {
+			// NB: the step belongs to a later step of the query, leave it to
+			// be consumed then.
+			break
+		}
+
+		it.peeked = false
+		if idx < it.idx {
+			continue
+		}
+
+		indices := it.block.blocks[it.shard].indices
+		for j, v := range step.Values() {
+			values[indices[j]] = v
+		}
+
+		break
+	}
+
+	it.current = block.NewColStep(
+		bounds.Start.Add(time.Duration(it.idx)*bounds.StepSize), values)
+	return true
+}
+
+// executeSharded evaluates each shard of the query concurrently, streaming
+// their results as a single block spanning the whole query range.
+func (e *engine) executeSharded(
+	ctx context.Context,
+	queryCtx *models.QueryContext,
+	nodes parser.Nodes,
+	edges parser.Edges,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+	shards []queryShard,
+) (block.Block, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "execute_sharded")
+	defer sp.Finish()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := e.opts.ShardConcurrency()
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		results  = newShardResults(params)
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+		errLock  sync.Mutex
+		multiErr xerrors.MultiError
+	)
+
+	for _, shard := range shards {
+		shard := shard
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := e.executeShard(ctx, queryCtx.WithContext(ctx), nodes, edges,
+				fetchOpts, shard.params(params), results)
+			if err != nil {
+				cancel()
+				errLock.Lock()
+				multiErr = multiErr.Add(err)
+				errLock.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	if err := multiErr.FinalError(); err != nil {
+		if closeErr := results.close(); closeErr != nil {
+			multiErr = multiErr.Add(closeErr)
+		}
+
+		return nil, multiErr.FinalError()
+	}
+
+	return results.block(), nil
+}
+
+// executeShard evaluates a single shard of the query, adding its result
+// blocks to the sharded results.
+func (e *engine) executeShard(
+	ctx context.Context,
+	queryCtx *models.QueryContext,
+	nodes parser.Nodes,
+	edges parser.Edges,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+	results *shardResults,
+) error {
+	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
+	pp, err := req.plan(ctx, nodes, edges)
+	if err != nil {
+		return err
+	}
+
+	state, err := req.generateExecutionState(ctx, pp)
+	if err != nil {
+		return err
+	}
+
+	result := state.resultNode
+	go func() {
+		if err := state.Execute(queryCtx); err != nil {
+			result.abort(err)
+		} else {
+			result.done()
+		}
+	}()
+
+	// NB: always drain the result channel so that the shard execution is
+	// never blocked on sending results, even after an error.
+	var multiErr xerrors.MultiError
+	for r := range result.ResultChan() {
+		if r.Err != nil {
+			multiErr = multiErr.Add(r.Err)
+			continue
+		}
+
+		if !multiErr.Empty() {
+			if err := r.Block.Close(); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+
+			continue
+		}
+
+		// NB: the block is owned by the results from here on.
+		if err := results.add(r.Block); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
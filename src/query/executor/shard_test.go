@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package executor
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryShards(t *testing.T) {
+	start := time.Now().Truncate(24 * time.Hour).Add(-72 * time.Hour)
+	params := models.RequestParams{
+		Start: start,
+		End:   start.Add(72 * time.Hour),
+		Now:   start.Add(72 * time.Hour),
+		Step:  time.Minute,
+	}
+
+	e := NewEngine(NewEngineOptions().
+		SetInstrumentOptions(instrument.NewOptions())).(*engine)
+	assert.Nil(t, e.queryShards(params))
+
+	e = NewEngine(NewEngineOptions().
+		SetInstrumentOptions(instrument.NewOptions()).
+		SetShardInterval(72 * time.Hour)).(*engine)
+	assert.Nil(t, e.queryShards(params))
+
+	boundary := start.Add(30*time.Hour + 30*time.Second)
+	e = NewEngine(NewEngineOptions().
+		SetInstrumentOptions(instrument.NewOptions()).
+		SetShardInterval(24 * time.Hour).
+		SetShardBoundariesFn(func(now, from, to time.Time) []time.Time {
+			assert.Equal(t, params.Now, now)
+			assert.Equal(t, params.Start, from)
+			assert.Equal(t, params.End, to)
+			return []time.Time{start.Add(-time.Hour), boundary}
+		})).(*engine)
+
+	shards := e.queryShards(params)
+	expected := []queryShard{
+		{start: start, end: start.Add(24 * time.Hour)},
+		// NB: the boundary is aligned up to the next step of the query.
+		{start: start.Add(24 * time.Hour), end: start.Add(30*time.Hour + time.Minute)},
+		{start: start.Add(30*time.Hour + time.Minute), end: start.Add(54*time.Hour + time.Minute)},
+		{start: start.Add(54*time.Hour + time.Minute), end: start.Add(72 * time.Hour)},
+	}
+
+	require.Equal(t, len(expected), len(shards))
+	for i, shard := range shards {
+		assert.True(t, expected[i].start.Equal(shard.start), "shard %d start", i)
+		assert.True(t, expected[i].end.Equal(shard.end), "shard %d end", i)
+	}
+
+	shardParams := shards[1].params(params)
+	assert.Equal(t, shards[1].start, shardParams.Start)
+	assert.Equal(t, shards[1].end, shardParams.End)
+	assert.False(t, shardParams.IncludeEnd)
+}
+
+func TestShardResults(t *testing.T) {
+	start := time.Now().Truncate(time.Hour)
+	params := models.RequestParams{
+		Start: start,
+		End:   start.Add(5 * time.Minute),
+		Step:  time.Minute,
+	}
+
+	results := newShardResults(params)
+	first := test.NewBlockFromValuesWithSeriesMeta(models.Bounds{
+		Start:    start,
+		Duration: 2 * time.Minute,
+		StepSize: time.Minute,
+	}, test.NewSeriesMeta("a", 1), [][]float64{{1, 2}})
+	second := test.NewBlockFromValuesWithSeriesMeta(models.Bounds{
+		Start:    start.Add(2 * time.Minute),
+		Duration: 3 * time.Minute,
+		StepSize: time.Minute,
+	}, test.NewSeriesMeta("a", 2), [][]float64{{3, 4, 5}, {6, 7, 8}})
+
+	// NB: shards may complete in any order.
+	require.NoError(t, results.add(second))
+	require.NoError(t, results.add(first))
+
+	bl := results.block()
+	assert.Equal(t, start, bl.Meta().Bounds.Start)
+	assert.Equal(t, 5, bl.Meta().Bounds.Steps())
+
+	iter, err := bl.StepIter()
+	require.NoError(t, err)
+
+	metas := iter.SeriesMeta()
+	require.Equal(t, 2, len(metas))
+	assert.Equal(t, 5, iter.StepCount())
+
+	actual := make(map[string][]float64)
+	for i := 0; iter.Next(); i++ {
+		step := iter.Current()
+		assert.True(t, start.Add(time.Duration(i)*time.Minute).Equal(step.Time()))
+		for j, v := range step.Values() {
+			name := string(metas[j].Name)
+			actual[name] = append(actual[name], v)
+		}
+	}
+
+	require.NoError(t, iter.Err())
+	iter.Close()
+
+	require.Equal(t, 2, len(actual))
+	assert.Equal(t, []float64{1, 2, 3, 4, 5}, actual["a0"])
+	test.EqualsWithNans(t, []float64{math.NaN(), math.NaN(), 6, 7, 8}, actual["a1"])
+	assert.True(t, bl.Meta().ResultMetadata.Exhaustive)
+	require.NoError(t, bl.Close())
+}
@@ -147,6 +147,18 @@ func (s *ExecutionState) createNode(
 		return controller, nil
 	}
 
+	subqueryParams, ok := step.Transform.Op.(SubqueryParams)
+	if ok {
+		source, controller, err := s.createSubquerySource(step.ID(),
+			subqueryParams, options)
+		if err != nil {
+			return nil, err
+		}
+
+		s.sources = append(s.sources, source)
+		return controller, nil
+	}
+
 	scalarParams, ok := step.Transform.Op.(ScalarParams)
 	if ok {
 		source, controller := CreateScalarSource(step.ID(), scalarParams, options)
@@ -0,0 +1,223 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"math"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/plan"
+	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/opentracing"
+)
+
+// SubqueryParams are defined by subqueries.
+type SubqueryParams interface {
+	parser.Params
+	// DAG returns the nodes and edges for the inner expression.
+	DAG() (parser.Nodes, parser.Edges)
+	// InnerTimeSpec returns the time spec used to evaluate the inner
+	// expression, given the time spec of the enclosing query.
+	InnerTimeSpec(outer transform.TimeSpec) transform.TimeSpec
+	// Bounds returns the bounds for the subquery.
+	Bounds() transform.BoundSpec
+}
+
+// createSubquerySource plans the inner expression of a subquery at its own
+// time spec, and wraps its execution state in a source node.
+func (s *ExecutionState) createSubquerySource(
+	ID parser.NodeID,
+	params SubqueryParams,
+	options transform.Options,
+) (parser.Source, *transform.Controller, error) {
+	nodes, edges := params.DAG()
+	lp, err := plan.NewLogicalPlan(nodes, edges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		outer = options.TimeSpec()
+		inner = params.InnerTimeSpec(outer)
+	)
+
+	pp, err := plan.NewPhysicalPlan(lp, models.RequestParams{
+		Start:            inner.Start,
+		End:              inner.End,
+		Now:              inner.Now,
+		Step:             inner.Step,
+		Debug:            s.plan.Debug,
+		BlockType:        s.plan.BlockType,
+		LookbackDuration: s.plan.LookbackDuration,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := GenerateExecutionState(pp, s.storage,
+		options.FetchOptions(), options.InstrumentOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offset := params.Bounds().Offset
+	controller := &transform.Controller{ID: ID}
+	return &subqueryNode{
+		state:      state,
+		controller: controller,
+		bounds: models.Bounds{
+			Start:    outer.Start.Add(-1 * offset),
+			Duration: outer.End.Sub(outer.Start),
+			StepSize: outer.Step,
+		},
+	}, controller, nil
+}
+
+// subqueryNode is a source which evaluates an inner execution state and
+// passes its results downstream as unconsolidated series.
+type subqueryNode struct {
+	state      *ExecutionState
+	controller *transform.Controller
+	bounds     models.Bounds
+}
+
+// Execute runs the inner execution state and processes its results.
+func (n *subqueryNode) Execute(queryCtx *models.QueryContext) error {
+	sp, ctx := opentracing.StartSpanFromContext(queryCtx.Ctx, "subquery")
+	defer sp.Finish()
+
+	result := n.state.resultNode
+	go func() {
+		if err := n.state.Execute(queryCtx.WithContext(ctx)); err != nil {
+			result.abort(err)
+		} else {
+			result.done()
+		}
+	}()
+
+	var (
+		multiErr   xerrors.MultiError
+		resultMeta = block.NewResultMetadata()
+		seriesIdx  = make(map[string][]int)
+		series     []block.UnconsolidatedSeries
+	)
+
+	// NB: always drain the result channel so that the inner execution is
+	// never blocked on sending results, even after an error.
+	for r := range result.ResultChan() {
+		if r.Err != nil {
+			multiErr = multiErr.Add(r.Err)
+			continue
+		}
+
+		if multiErr.Empty() {
+			var err error
+			resultMeta = resultMeta.CombineMetadata(r.Block.Meta().ResultMetadata)
+			series, err = appendStepValues(series, seriesIdx, r.Block)
+			multiErr = multiErr.Add(err)
+		}
+
+		if err := r.Block.Close(); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	if err := multiErr.FinalError(); err != nil {
+		return err
+	}
+
+	bl := block.NewUnconsolidatedBlock(block.Metadata{
+		Bounds:         n.bounds,
+		ResultMetadata: resultMeta,
+	}, series)
+	defer bl.Close()
+	return n.controller.Process(queryCtx, bl)
+}
+
+// appendStepValues converts the consolidated values of a block into raw
+// datapoints, appending them to the matching series.
+func appendStepValues(
+	series []block.UnconsolidatedSeries,
+	seriesIdx map[string][]int,
+	bl block.Block,
+) ([]block.UnconsolidatedSeries, error) {
+	iter, err := bl.StepIter()
+	if err != nil {
+		return series, err
+	}
+
+	defer iter.Close()
+	var (
+		metas       = iter.SeriesMeta()
+		indices     = make([]int, 0, len(metas))
+		occurrences = make(map[string]int, len(metas))
+	)
+
+	for _, meta := range metas {
+		// NB: series are matched across blocks by their tags; series sharing
+		// tags within a block are matched by the order they appear in.
+		id := string(meta.Tags.ID())
+		n := occurrences[id]
+		occurrences[id] = n + 1
+		if n < len(seriesIdx[id]) {
+			indices = append(indices, seriesIdx[id][n])
+			continue
+		}
+
+		idx := len(series)
+		seriesIdx[id] = append(seriesIdx[id], idx)
+		series = append(series, block.NewUnconsolidatedSeries(nil, meta))
+		indices = append(indices, idx)
+	}
+
+	datapoints := make([]ts.Datapoints, len(series))
+	for i, s := range series {
+		datapoints[i] = s.Datapoints()
+	}
+
+	for iter.Next() {
+		step := iter.Current()
+		t := step.Time()
+		for i, v := range step.Values() {
+			if math.IsNaN(v) {
+				continue
+			}
+
+			idx := indices[i]
+			datapoints[idx] = append(datapoints[idx],
+				ts.Datapoint{Timestamp: t, Value: v})
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return series, err
+	}
+
+	for i, s := range series {
+		series[i] = block.NewUnconsolidatedSeries(datapoints[i], s.Meta)
+	}
+
+	return series, nil
+}
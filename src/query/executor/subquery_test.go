@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/plan"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubqueryState(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	values, bounds := test.GenerateValuesAndBounds([][]float64{
+		{0, 1, 2, 3, 4},
+		{5, 6, 7, 8, 9},
+	}, &models.Bounds{
+		Start:    now.Add(-5 * time.Minute),
+		Duration: 5 * time.Minute,
+		StepSize: time.Minute,
+	})
+
+	values[0][2] = math.NaN()
+	store := mock.NewMockStorage()
+	store.SetFetchBlocksResult(block.Result{
+		Blocks: []block.Block{test.NewBlockFromValues(bounds, values)},
+	}, nil)
+
+	subquery := functions.SubqueryOp{
+		Range: 5 * time.Minute,
+		Step:  time.Minute,
+		Nodes: parser.Nodes{
+			parser.NewTransformFromOperation(functions.FetchOp{}, 0),
+		},
+	}
+
+	lp, err := plan.NewLogicalPlan(parser.Nodes{
+		parser.NewTransformFromOperation(subquery, 0),
+	}, parser.Edges{})
+	require.NoError(t, err)
+
+	params := testRequestParams()
+	params.Start = now.Add(-5 * time.Minute)
+	params.End = now
+	p, err := plan.NewPhysicalPlan(lp, params)
+	require.NoError(t, err)
+
+	state, err := GenerateExecutionState(p, store, storage.NewFetchOptions(),
+		instrument.NewOptions())
+	require.NoError(t, err)
+	require.Len(t, state.sources, 1)
+	require.NoError(t, state.Execute(models.NoopQueryContext()))
+	state.resultNode.done()
+
+	var results []block.Block
+	for r := range state.resultNode.ResultChan() {
+		require.NoError(t, r.Err)
+		results = append(results, r.Block)
+	}
+
+	require.Len(t, results, 1)
+	bl := results[0]
+	assert.Equal(t, block.BlockUnconsolidated, bl.Info().Type())
+	assert.Equal(t, p.TimeSpec.Start, bl.Meta().Bounds.Start)
+	assert.Equal(t, time.Second, bl.Meta().Bounds.StepSize)
+
+	iter, err := bl.SeriesIter()
+	require.NoError(t, err)
+	require.Equal(t, 2, iter.SeriesCount())
+
+	var dps [][]float64
+	for iter.Next() {
+		series := iter.Current()
+		vals := make([]float64, 0, series.Len())
+		for i, dp := range series.Datapoints() {
+			if i > 0 {
+				prev := series.Datapoints()[i-1].Timestamp
+				assert.True(t, dp.Timestamp.After(prev))
+			}
+
+			vals = append(vals, dp.Value)
+		}
+
+		dps = append(dps, vals)
+	}
+
+	require.NoError(t, iter.Err())
+	assert.Equal(t, [][]float64{{0, 1, 3, 4}, {5, 6, 7, 8, 9}}, dps)
+}
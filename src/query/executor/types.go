@@ -87,4 +87,30 @@ type EngineOptions interface {
 	ParseOptions() promql.ParseOptions
 	// SetParseOptions sets the parse options.
 	SetParseOptions(p promql.ParseOptions) EngineOptions
+
+	// ShardInterval returns the maximum time range of each shard that range
+	// queries are split into, a zero interval disables query splitting.
+	ShardInterval() time.Duration
+	// SetShardInterval sets the maximum time range of each shard that range
+	// queries are split into, a zero interval disables query splitting.
+	SetShardInterval(time.Duration) EngineOptions
+
+	// ShardConcurrency returns the number of shards of a query that are
+	// evaluated concurrently.
+	ShardConcurrency() int
+	// SetShardConcurrency sets the number of shards of a query that are
+	// evaluated concurrently.
+	SetShardConcurrency(int) EngineOptions
+
+	// ShardBoundariesFn returns the function used to find additional
+	// boundaries that range queries are split at.
+	ShardBoundariesFn() ShardBoundariesFn
+	// SetShardBoundariesFn sets the function used to find additional
+	// boundaries that range queries are split at.
+	SetShardBoundariesFn(ShardBoundariesFn) EngineOptions
 }
+
+// ShardBoundariesFn returns the times within a query range at which the data
+// the query is served from changes, such as where the retention of a
+// namespace ends.
+type ShardBoundariesFn func(now, start, end time.Time) []time.Time
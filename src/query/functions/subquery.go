@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/parser"
+)
+
+// SubqueryType is the type for a subquery source.
+const SubqueryType = "subquery"
+
+// SubqueryOp stores required properties for a subquery, which evaluates an
+// inner expression at its own step and presents the results as unconsolidated
+// series to range functions.
+type SubqueryOp struct {
+	Range  time.Duration
+	Offset time.Duration
+	Step   time.Duration
+	Nodes  parser.Nodes
+	Edges  parser.Edges
+}
+
+// OpType for the operator.
+func (o SubqueryOp) OpType() string {
+	return SubqueryType
+}
+
+// Bounds returns the bounds for this operation.
+func (o SubqueryOp) Bounds() transform.BoundSpec {
+	return transform.BoundSpec{
+		Range:  o.Range,
+		Offset: o.Offset,
+	}
+}
+
+// String is the string representation for this operation.
+func (o SubqueryOp) String() string {
+	return fmt.Sprintf("type: %s, range: %v, offset: %v, step: %v, nodes: %v",
+		o.OpType(), o.Range, o.Offset, o.Step, o.Nodes)
+}
+
+// DAG returns the nodes and edges for the inner expression.
+func (o SubqueryOp) DAG() (parser.Nodes, parser.Edges) {
+	return o.Nodes, o.Edges
+}
+
+// InnerTimeSpec returns the time spec used to evaluate the inner expression,
+// given the time spec of the enclosing query.
+func (o SubqueryOp) InnerTimeSpec(outer transform.TimeSpec) transform.TimeSpec {
+	step := o.Step
+	if step <= 0 {
+		step = outer.Step
+	}
+
+	// NB: similar to Prometheus, subquery evaluation timestamps are aligned
+	// to absolute multiples of the subquery step rather than the query start.
+	start := outer.Start.Add(-1 * o.Offset)
+	if rem := start.UnixNano() % int64(step); rem != 0 {
+		start = start.Add(step - time.Duration(rem))
+	}
+
+	return transform.TimeSpec{
+		Start: start,
+		End:   outer.End.Add(-1 * o.Offset),
+		Now:   outer.Now,
+		Step:  step,
+	}
+}
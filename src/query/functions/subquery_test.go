@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor/transform"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubqueryInnerTimeSpec(t *testing.T) {
+	var (
+		start = time.Unix(1000, 0)
+		now   = time.Unix(2000, 0)
+		outer = transform.TimeSpec{
+			Start: start,
+			End:   start.Add(time.Hour),
+			Now:   now,
+			Step:  15 * time.Second,
+		}
+	)
+
+	op := SubqueryOp{Range: time.Hour, Step: time.Minute}
+	assert.Equal(t, transform.TimeSpec{
+		Start: time.Unix(1020, 0),
+		End:   start.Add(time.Hour),
+		Now:   now,
+		Step:  time.Minute,
+	}, op.InnerTimeSpec(outer))
+
+	op = SubqueryOp{Range: time.Hour, Offset: 10 * time.Second}
+	assert.Equal(t, transform.TimeSpec{
+		Start: time.Unix(990, 0),
+		End:   start.Add(time.Hour - 10*time.Second),
+		Now:   now,
+		Step:  15 * time.Second,
+	}, op.InnerTimeSpec(outer))
+}
+
+func TestSubqueryBounds(t *testing.T) {
+	op := SubqueryOp{Range: time.Hour, Offset: time.Minute, Step: time.Second}
+	assert.Equal(t, transform.BoundSpec{
+		Range:  time.Hour,
+		Offset: time.Minute,
+	}, op.Bounds())
+	assert.Equal(t, SubqueryType, op.OpType())
+}
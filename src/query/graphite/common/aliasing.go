@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -90,6 +91,32 @@ func AliasByNode(_ *Context, seriesList ts.SeriesList, nodes ...int) (ts.SeriesL
 	return seriesList, nil
 }
 
+// AliasByTags renames a time series result according to the values of the
+// given tags, parsed from series names in the graphite tagged format. Missing
+// tags are rendered as empty values.
+func AliasByTags(_ *Context, seriesList ts.SeriesList, tags ...string) (ts.SeriesList, error) {
+	renamed := make([]*ts.Series, 0, seriesList.Len())
+	for _, series := range seriesList.Values {
+		name := series.Name()
+		left := strings.LastIndex(name, "(") + 1
+		name = name[left:]
+		right := strings.IndexAny(name, ",)")
+		if right == -1 {
+			right = len(name)
+		}
+		seriesTags := graphite.ParseTaggedName(name[0:right])
+		newNameParts := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			newNameParts = append(newNameParts, seriesTags[tag])
+		}
+		newName := strings.Join(newNameParts, ".")
+		newSeries := series.RenamedTo(newName)
+		renamed = append(renamed, newSeries)
+	}
+	seriesList.Values = renamed
+	return seriesList, nil
+}
+
 // AliasSub runs series names through a regex search/replace.
 func AliasSub(_ *Context, input ts.SeriesList, search, replace string) (ts.SeriesList, error) {
 	regex, err := regexp.Compile(search)
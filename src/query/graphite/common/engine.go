@@ -23,6 +23,7 @@ package common
 import (
 	"github.com/m3db/m3/src/query/graphite/context"
 	"github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
 )
 
 // QueryEngine is the generic engine interface.
@@ -32,6 +33,11 @@ type QueryEngine interface {
 		query string,
 		options storage.FetchOptions,
 	) (*storage.FetchResult, error)
+	FetchByTagMatchers(
+		ctx context.Context,
+		matchers models.Matchers,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error)
 }
 
 // The Engine for running queries
@@ -54,3 +60,12 @@ func (e *Engine) FetchByQuery(
 ) (*storage.FetchResult, error) {
 	return e.storage.FetchByQuery(ctx, query, options)
 }
+
+// FetchByTagMatchers retrieves one or more time series based on tag matchers
+func (e *Engine) FetchByTagMatchers(
+	ctx context.Context,
+	matchers models.Matchers,
+	options storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return e.storage.FetchByTagMatchers(ctx, matchers, options)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/query/graphite/storage"
 	xtest "github.com/m3db/m3/src/query/graphite/testing"
 	"github.com/m3db/m3/src/query/graphite/ts"
+	"github.com/m3db/m3/src/query/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -135,6 +136,15 @@ func (s *MovingAverageStorage) FetchByQuery(
 	return s.fetchByIDs(ctx, []string{query}, opts)
 }
 
+// FetchByTagMatchers builds a new series from the input matchers
+func (s *MovingAverageStorage) FetchByTagMatchers(
+	ctx context.Context,
+	matchers models.Matchers,
+	opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return s.fetchByIDs(ctx, []string{matchers.String()}, opts)
+}
+
 // FetchByIDs builds a new series from the input query
 func (s *MovingAverageStorage) fetchByIDs(
 	ctx context.Context,
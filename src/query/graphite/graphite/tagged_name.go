@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package graphite
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// TaggedNameTag is the tag that holds the metric name of a graphite tagged
+	// series, e.g. "name" in seriesByTag('name=foo.bar').
+	TaggedNameTag = "name"
+
+	taggedNameSeparator = ";"
+	taggedNameAssign    = "="
+)
+
+// FormatTaggedName formats a metric name and a set of tags using the graphite
+// tagged series format, i.e. "name;tag1=value1;tag2=value2" with tags sorted
+// by name. Any "name" entry in tags is ignored in favor of the given name.
+func FormatTaggedName(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		if k == TaggedNameTag {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString(taggedNameSeparator)
+		b.WriteString(k)
+		b.WriteString(taggedNameAssign)
+		b.WriteString(tags[k])
+	}
+
+	return b.String()
+}
+
+// ParseTaggedName parses a series name in the graphite tagged series format
+// into its tags, including the "name" tag. Untagged series names are returned
+// as a single "name" tag holding the entire series name.
+func ParseTaggedName(seriesName string) map[string]string {
+	parts := strings.Split(seriesName, taggedNameSeparator)
+	tags := make(map[string]string, len(parts))
+	tags[TaggedNameTag] = parts[0]
+	for _, part := range parts[1:] {
+		idx := strings.Index(part, taggedNameAssign)
+		if idx <= 0 {
+			// NB: not a tag pair, treat the whole series name as untagged.
+			return map[string]string{TaggedNameTag: seriesName}
+		}
+
+		tags[part[:idx]] = part[idx+1:]
+	}
+
+	return tags
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTaggedName(t *testing.T) {
+	assert.Equal(t, "foo.bar", FormatTaggedName("foo.bar", nil))
+	assert.Equal(t, "foo.bar;a=1;dc=us-east;z=",
+		FormatTaggedName("foo.bar", map[string]string{
+			"z":           "",
+			"dc":          "us-east",
+			"a":           "1",
+			TaggedNameTag: "ignored",
+		}))
+}
+
+func TestParseTaggedName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected map[string]string
+	}{
+		{
+			name:     "foo.bar",
+			expected: map[string]string{"name": "foo.bar"},
+		},
+		{
+			name: "foo.bar;dc=us-east;host=a=b;empty=",
+			expected: map[string]string{
+				"name":  "foo.bar",
+				"dc":    "us-east",
+				"host":  "a=b",
+				"empty": "",
+			},
+		},
+		{
+			name:     "foo;bar",
+			expected: map[string]string{"name": "foo;bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, ParseTaggedName(tt.name), tt.name)
+	}
+}
+
+func TestTaggedNameRoundTrip(t *testing.T) {
+	name := FormatTaggedName("a.b", map[string]string{"x": "1", "y": "2"})
+	assert.Equal(t, map[string]string{"name": "a.b", "x": "1", "y": "2"},
+		ParseTaggedName(name))
+}
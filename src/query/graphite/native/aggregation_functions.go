@@ -23,13 +23,133 @@ package native
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
+// safeAggregationFn aggregates a set of non-NaN values into a single value.
+type safeAggregationFn func(values []float64) float64
+
+var (
+	safeAggregationFns = map[string]safeAggregationFn{
+		"average":  safeAverage,
+		"avg":      safeAverage,
+		"median":   safeMedian,
+		"sum":      safeSum,
+		"total":    safeSum,
+		"min":      safeMin,
+		"max":      safeMax,
+		"diff":     safeDiff,
+		"stddev":   safeStdDev,
+		"count":    safeCount,
+		"range":    safeRange,
+		"rangeOf":  safeRange,
+		"multiply": safeMultiply,
+		"last":     safeLast,
+		"current":  safeLast,
+	}
+)
+
+func getSafeAggregationFn(fname string) (safeAggregationFn, error) {
+	fn, ok := safeAggregationFns[fname]
+	if !ok {
+		return nil, errors.NewInvalidParamsError(fmt.Errorf("invalid func %s", fname))
+	}
+
+	return fn, nil
+}
+
+// safeAggregate applies the aggregation function to the non-NaN values, as
+// long as the ratio of non-NaN values is at least xFilesFactor.
+func safeAggregate(values []float64, fn safeAggregationFn, xFilesFactor float64) float64 {
+	nonNaN := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			nonNaN = append(nonNaN, v)
+		}
+	}
+
+	if len(nonNaN) == 0 ||
+		float64(len(nonNaN))/float64(len(values)) < xFilesFactor {
+		return math.NaN()
+	}
+
+	return fn(nonNaN)
+}
+
+func safeSum(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func safeAverage(values []float64) float64 {
+	return safeSum(values) / float64(len(values))
+}
+
+func safeMedian(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
+
+func safeMin(values []float64) float64 {
+	min := values[0]
+	for _, v := range values[1:] {
+		min = math.Min(min, v)
+	}
+	return min
+}
+
+func safeMax(values []float64) float64 {
+	max := values[0]
+	for _, v := range values[1:] {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+func safeDiff(values []float64) float64 {
+	return values[0] - safeSum(values[1:])
+}
+
+func safeStdDev(values []float64) float64 {
+	avg := safeAverage(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - avg) * (v - avg)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func safeCount(values []float64) float64 {
+	return float64(len(values))
+}
+
+func safeRange(values []float64) float64 {
+	return safeMax(values) - safeMin(values)
+}
+
+func safeMultiply(values []float64) float64 {
+	product := 1.0
+	for _, v := range values {
+		product *= v
+	}
+	return product
+}
+
+func safeLast(values []float64) float64 {
+	return values[len(values)-1]
+}
+
 func wrapPathExpr(wrapper string, series ts.SeriesList) string {
 	return fmt.Sprintf("%s(%s)", wrapper, joinPathExpr(series))
 }
@@ -89,6 +209,44 @@ func maxSeries(ctx *common.Context, series multiplePathSpecs) (ts.SeriesList, er
 	return combineSeries(ctx, series, wrapPathExpr("maxSeries", ts.SeriesList(series)), ts.Max)
 }
 
+// aggregate takes a list of series and returns a new series containing the
+// values of the given aggregation function applied across the series at each
+// datapoint, e.g. aggregate(foo.*, 'median').
+func aggregate(ctx *common.Context, series singlePathSpec, fname string, xFilesFactor float64) (ts.SeriesList, error) {
+	fn, err := getSafeAggregationFn(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	if len(series.Values) == 0 {
+		return ts.SeriesList(series), nil
+	}
+
+	normalized, start, end, millisPerStep, err := common.Normalize(ctx, ts.SeriesList(series))
+	if err != nil {
+		err := errors.NewInvalidParamsError(fmt.Errorf("aggregate series error: %v", err))
+		return ts.NewSeriesList(), err
+	}
+
+	var (
+		numSteps = ts.NumSteps(start, end, millisPerStep)
+		vals     = ts.NewValues(ctx, millisPerStep, numSteps)
+		window   = make([]float64, normalized.Len())
+	)
+	for i := 0; i < numSteps; i++ {
+		for j, s := range normalized.Values {
+			window[j] = s.ValueAt(i)
+		}
+		vals.SetValueAt(i, safeAggregate(window, fn, xFilesFactor))
+	}
+
+	name := wrapPathExpr(fname+"Series", ts.SeriesList(series))
+	return ts.SeriesList{
+		Values:   []*ts.Series{ts.NewSeries(ctx, name, start, vals)},
+		Metadata: series.Metadata,
+	}, nil
+}
+
 // divideSeries divides one series list by another series
 func divideSeries(ctx *common.Context, dividendSeriesList, divisorSeriesList singlePathSpec) (ts.SeriesList, error) {
 	if len(divisorSeriesList.Values) != 1 {
@@ -281,6 +439,149 @@ func groupByNode(ctx *common.Context, series singlePathSpec, node int, fname str
 	return r, nil
 }
 
+// applyByNode takes a series list and, for each unique prefix of the series
+// names up to and including the given node, evaluates the template function
+// with each "%" replaced by that prefix. If newName is set, the resulting
+// series are renamed to it, again with each "%" replaced by the prefix.
+func applyByNode(
+	ctx *common.Context,
+	series singlePathSpec,
+	nodeNum int,
+	templateFunction string,
+	newName string,
+) (ts.SeriesList, error) {
+	if nodeNum < 0 {
+		err := errors.NewInvalidParamsError(fmt.Errorf("invalid node %d", nodeNum))
+		return ts.NewSeriesList(), err
+	}
+
+	prefixes := make(map[string]struct{})
+	for _, s := range series.Values {
+		parts := strings.Split(s.Name(), ".")
+		if len(parts) > nodeNum+1 {
+			parts = parts[:nodeNum+1]
+		}
+
+		prefixes[strings.Join(parts, ".")] = struct{}{}
+	}
+
+	sortedPrefixes := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		sortedPrefixes = append(sortedPrefixes, prefix)
+	}
+	sort.Strings(sortedPrefixes)
+
+	var (
+		newSeries = make([]*ts.Series, 0, len(sortedPrefixes))
+		meta      = series.Metadata
+	)
+	for _, prefix := range sortedPrefixes {
+		target := strings.Replace(templateFunction, "%", prefix, -1)
+		expr, err := compile(target)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		output, err := expr.Execute(ctx)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		meta = meta.CombineMetadata(output.Metadata)
+		for _, s := range output.Values {
+			if newName != "" {
+				s = s.RenamedTo(strings.Replace(newName, "%", prefix, -1))
+			}
+			newSeries = append(newSeries, s)
+		}
+	}
+
+	r := ts.SeriesList(series)
+	r.Values = newSeries
+	r.Metadata = meta
+	r.SortApplied = false
+	return r, nil
+}
+
+// groupByTags takes a series list and groups it by the values of the given
+// tags, combining each group with the given aggregation function. Resulting
+// series are named in the graphite tagged format from the grouped tags; the
+// name is the common series name if all series share one, the per-group name
+// if grouping by the "name" tag, and the aggregation function otherwise.
+func groupByTags(ctx *common.Context, series singlePathSpec, fname string, tags ...string) (ts.SeriesList, error) {
+	if len(tags) == 0 {
+		err := errors.NewInvalidParamsError(errors.New("groupByTags requires at least one tag"))
+		return ts.NewSeriesList(), err
+	}
+
+	f, fexists := summarizeFuncs[fname]
+	if !fexists {
+		return ts.NewSeriesList(), errors.NewInvalidParamsError(fmt.Errorf("invalid func %s", fname))
+	}
+
+	groupByName := false
+	for _, tag := range tags {
+		if tag == graphite.TaggedNameTag {
+			groupByName = true
+		}
+	}
+
+	seriesTags := make([]map[string]string, 0, len(series.Values))
+	names := make(map[string]struct{})
+	for _, s := range series.Values {
+		parsed := graphite.ParseTaggedName(s.Name())
+		seriesTags = append(seriesTags, parsed)
+		names[parsed[graphite.TaggedNameTag]] = struct{}{}
+	}
+
+	commonName := fname
+	if len(names) == 1 {
+		for name := range names {
+			commonName = name
+		}
+	}
+
+	metaSeries := make(map[string][]*ts.Series)
+	for i, s := range series.Values {
+		name := commonName
+		if groupByName {
+			name = seriesTags[i][graphite.TaggedNameTag]
+		}
+
+		groupTags := make(map[string]string, len(tags))
+		for _, tag := range tags {
+			groupTags[tag] = seriesTags[i][tag]
+		}
+
+		key := graphite.FormatTaggedName(name, groupTags)
+		metaSeries[key] = append(metaSeries[key], s)
+	}
+
+	newSeries := make([]*ts.Series, 0, len(metaSeries))
+	for key, metaSeries := range metaSeries {
+		seriesList := ts.SeriesList{
+			Values:   metaSeries,
+			Metadata: series.Metadata,
+		}
+		output, err := combineSeries(ctx, multiplePathSpecs(seriesList), key, f.consolidationFunc)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+		output.Values[0].Specification = f.specificationFunc(seriesList)
+		newSeries = append(newSeries, output.Values...)
+	}
+
+	r := ts.SeriesList(series)
+
+	r.Values = newSeries
+
+	// Ranging over hash map to create results destroys
+	// any sort order on the incoming series list
+	r.SortApplied = false
+
+	return r, nil
+}
+
 // combineSeries combines multiple series into a single series using a
 // consolidation func.  If the series use different time intervals, the
 // coarsest time will apply.
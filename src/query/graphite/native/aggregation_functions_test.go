@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/query/graphite/context"
 	"github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/graphite/ts"
+	"github.com/m3db/m3/src/query/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -150,6 +151,14 @@ func (e mockEngine) FetchByQuery(
 	return e.fn(ctx, query, opts)
 }
 
+func (e mockEngine) FetchByTagMatchers(
+	ctx context.Context,
+	matchers models.Matchers,
+	opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return e.fn(ctx, matchers.String(), opts)
+}
+
 func TestVariadicSumSeries(t *testing.T) {
 	expr, err := compile("sumSeries(foo.bar.*, foo.baz.*)")
 	require.NoError(t, err)
@@ -459,3 +468,212 @@ func TestCountSeries(t *testing.T) {
 	common.CompareOutputsAndExpected(t, input[1].MillisPerStep(), input[1].StartTime(),
 		[]common.TestSeries{expected}, results.Values)
 }
+
+func TestGroupByTags(t *testing.T) {
+	var (
+		start, _ = time.Parse(time.RFC1123, "Mon, 27 Jul 2015 19:41:19 GMT")
+		end, _   = time.Parse(time.RFC1123, "Mon, 27 Jul 2015 19:43:19 GMT")
+		ctx      = common.NewContext(common.ContextOptions{Start: start, End: end})
+		inputs   = []*ts.Series{
+			ts.NewSeries(ctx, "requests;dc=us-east;host=a", start,
+				ts.NewConstantValues(ctx, 2, 12, 10000)),
+			ts.NewSeries(ctx, "requests;dc=us-east;host=b", start,
+				ts.NewConstantValues(ctx, 4, 12, 10000)),
+			ts.NewSeries(ctx, "requests;dc=us-west;host=c", start,
+				ts.NewConstantValues(ctx, 6, 12, 10000)),
+			ts.NewSeries(ctx, "errors;dc=us-west;host=d", start,
+				ts.NewConstantValues(ctx, 8, 12, 10000)),
+		}
+	)
+	defer ctx.Close()
+
+	type result struct {
+		name      string
+		sumOfVals float64
+	}
+
+	tests := []struct {
+		inputs          []*ts.Series
+		fname           string
+		tags            []string
+		expectedResults []result
+	}{
+		{inputs[:3], "sum", []string{"dc"}, []result{
+			{"requests;dc=us-east", (2 + 4) * 12},
+			{"requests;dc=us-west", 6 * 12},
+		}},
+		{inputs, "max", []string{"dc"}, []result{
+			{"max;dc=us-east", 4 * 12},
+			{"max;dc=us-west", 8 * 12},
+		}},
+		{inputs, "avg", []string{"name"}, []result{
+			{"errors", 8 * 12},
+			{"requests", ((2 + 4 + 6) / 3) * 12},
+		}},
+		{inputs[:3], "min", []string{"dc", "rack"}, []result{
+			{"requests;dc=us-east;rack=", 2 * 12},
+			{"requests;dc=us-west;rack=", 6 * 12},
+		}},
+	}
+
+	for _, test := range tests {
+		outSeries, err := groupByTags(ctx, singlePathSpec{
+			Values: test.inputs,
+		}, test.fname, test.tags...)
+		require.NoError(t, err)
+		require.Equal(t, len(test.expectedResults), len(outSeries.Values))
+
+		outSeries, _ = sortByName(ctx, singlePathSpec(outSeries))
+
+		for i, expected := range test.expectedResults {
+			series := outSeries.Values[i]
+			assert.Equal(t, expected.name, series.Name())
+			assert.Equal(t, expected.sumOfVals, series.SafeSum())
+		}
+	}
+
+	_, err := groupByTags(ctx, singlePathSpec{Values: inputs}, "sum")
+	require.Error(t, err)
+
+	_, err = groupByTags(ctx, singlePathSpec{Values: inputs}, "unknown", "dc")
+	require.Error(t, err)
+}
+
+func TestSeriesByTag(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	var fetched string
+	ctx.Engine = mockEngine{fn: func(
+		ctx context.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		fetched = query
+		return storage.NewFetchResult(ctx, []*ts.Series{
+			ts.NewSeries(ctx, "foo;dc=us-east", options.StartTime,
+				ts.NewConstantValues(ctx, 1, 3, 1000)),
+		}, block.NewResultMetadata()), nil
+	}}
+
+	expr, err := compile("aliasByTags(seriesByTag('name=foo', 'dc=~us.*'), 'dc')")
+	require.NoError(t, err)
+
+	result, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Len())
+	assert.Equal(t, "us-east", result.Values[0].Name())
+	assert.Equal(t, "seriesByTag('name=foo','dc=~us.*')",
+		result.Values[0].Specification)
+
+	matchers, err := storage.TranslateTagExpressionsToMatchers(
+		[]string{"name=foo", "dc=~us.*"})
+	require.NoError(t, err)
+	assert.Equal(t, matchers.String(), fetched)
+
+	_, err = compile("seriesByTag()")
+	require.Error(t, err)
+
+	expr, err = compile("seriesByTag('dc!=us')")
+	require.NoError(t, err)
+	_, err = expr.Execute(ctx)
+	require.Error(t, err)
+}
+
+func TestAggregate(t *testing.T) {
+	testAggregatedSeries(t, func(ctx *common.Context, series multiplePathSpecs) (ts.SeriesList, error) {
+		return aggregate(ctx, singlePathSpec(series), "sum", 0)
+	}, 15.0, 28.0, 30.0, 17.0, "invalid sum value for step %d")
+
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := ctx.StartTime
+	input := singlePathSpec{Values: []*ts.Series{
+		ts.NewSeries(ctx, "foo.a", start,
+			common.NewTestSeriesValues(ctx, 1000, []float64{1, 5, math.NaN(), math.NaN()})),
+		ts.NewSeries(ctx, "foo.b", start,
+			common.NewTestSeriesValues(ctx, 1000, []float64{2, math.NaN(), math.NaN(), 3})),
+		ts.NewSeries(ctx, "foo.c", start,
+			common.NewTestSeriesValues(ctx, 1000, []float64{9, 7, math.NaN(), math.NaN()})),
+	}}
+
+	tests := []struct {
+		fname        string
+		xFilesFactor float64
+		expected     common.TestSeries
+	}{
+		{"median", 0, common.TestSeries{
+			Name: "medianSeries(foo.a,foo.b,foo.c)",
+			Data: []float64{2, 7, math.NaN(), 3},
+		}},
+		{"range", 0, common.TestSeries{
+			Name: "rangeSeries(foo.a,foo.b,foo.c)",
+			Data: []float64{8, 2, math.NaN(), 0},
+		}},
+		{"count", 0.5, common.TestSeries{
+			Name: "countSeries(foo.a,foo.b,foo.c)",
+			Data: []float64{3, 2, math.NaN(), math.NaN()},
+		}},
+	}
+
+	for _, test := range tests {
+		r, err := aggregate(ctx, input, test.fname, test.xFilesFactor)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, 1000, start,
+			[]common.TestSeries{test.expected}, r.Values)
+	}
+
+	_, err := aggregate(ctx, input, "unknown", 0)
+	require.Error(t, err)
+}
+
+func TestApplyByNode(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	ctx.Engine = mockEngine{fn: func(
+		ctx context.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		start := options.StartTime
+		switch query {
+		case "servers.s1.disk.*":
+			return storage.NewFetchResult(ctx, []*ts.Series{
+				ts.NewSeries(ctx, "servers.s1.disk.used", start, ts.NewConstantValues(ctx, 10, 3, 1000)),
+				ts.NewSeries(ctx, "servers.s1.disk.total", start, ts.NewConstantValues(ctx, 40, 3, 1000)),
+			}, block.NewResultMetadata()), nil
+		case "servers.s2.disk.*":
+			return storage.NewFetchResult(ctx, []*ts.Series{
+				ts.NewSeries(ctx, "servers.s2.disk.used", start, ts.NewConstantValues(ctx, 5, 3, 1000)),
+				ts.NewSeries(ctx, "servers.s2.disk.total", start, ts.NewConstantValues(ctx, 10, 3, 1000)),
+			}, block.NewResultMetadata()), nil
+		}
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}}
+
+	start := ctx.StartTime
+	input := singlePathSpec{Values: []*ts.Series{
+		ts.NewSeries(ctx, "servers.s2.disk.used", start, ts.NewConstantValues(ctx, 5, 3, 1000)),
+		ts.NewSeries(ctx, "servers.s1.disk.used", start, ts.NewConstantValues(ctx, 10, 3, 1000)),
+		ts.NewSeries(ctx, "servers.s1.disk.total", start, ts.NewConstantValues(ctx, 40, 3, 1000)),
+	}}
+
+	r, err := applyByNode(ctx, input, 1, "sumSeries(%.disk.*)", "%.disk.sum")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Len())
+	assert.Equal(t, "servers.s1.disk.sum", r.Values[0].Name())
+	assert.Equal(t, []float64{50, 50, 50}, r.Values[0].SafeValues())
+	assert.Equal(t, "servers.s2.disk.sum", r.Values[1].Name())
+	assert.Equal(t, []float64{15, 15, 15}, r.Values[1].SafeValues())
+
+	r, err = applyByNode(ctx, input, 1, "sumSeries(%.disk.*)", "")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Len())
+	assert.Equal(t, "sumSeries(servers.s1.disk.*)", r.Values[0].Name())
+	assert.Equal(t, "sumSeries(servers.s2.disk.*)", r.Values[1].Name())
+
+	_, err = applyByNode(ctx, input, -1, "sumSeries(%.disk.*)", "")
+	require.Error(t, err)
+}
@@ -43,6 +43,12 @@ func aliasByNode(ctx *common.Context, seriesList singlePathSpec, nodes ...int) (
 	return common.AliasByNode(ctx, ts.SeriesList(seriesList), nodes...)
 }
 
+// aliasByTags renames a time series result according to the values of the
+// given tags of graphite tagged series.
+func aliasByTags(ctx *common.Context, seriesList singlePathSpec, tags ...string) (ts.SeriesList, error) {
+	return common.AliasByTags(ctx, ts.SeriesList(seriesList), tags...)
+}
+
 // aliasSub runs series names through a regex search/replace.
 func aliasSub(ctx *common.Context, input singlePathSpec, search, replace string) (ts.SeriesList, error) {
 	return common.AliasSub(ctx, ts.SeriesList(input), search, replace)
@@ -217,3 +217,34 @@ func TestAliasByNodeWithComposition(t *testing.T) {
 	assert.Equal(t, "~~~", results.Values[2].Name())
 	assert.Equal(t, "", results.Values[3].Name())
 }
+
+func TestAliasByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	now := time.Now()
+	values := ts.NewConstantValues(ctx, 10.0, 1000, 10)
+	series := []*ts.Series{
+		ts.NewSeries(ctx, "foo.bar;dc=us-east;host=a", now, values),
+		ts.NewSeries(ctx, "derivative(foo.baz;dc=us-west;host=b)", now, values),
+		ts.NewSeries(ctx, "foo.qux;host=c", now, values),
+	}
+
+	results, err := aliasByTags(ctx, singlePathSpec{
+		Values: series,
+	}, "name", "dc")
+	require.NoError(t, err)
+	require.Equal(t, len(series), results.Len())
+	assert.Equal(t, "foo.bar.us-east", results.Values[0].Name())
+	assert.Equal(t, "foo.baz.us-west", results.Values[1].Name())
+	assert.Equal(t, "foo.qux.", results.Values[2].Name())
+
+	results, err = aliasByTags(ctx, singlePathSpec{
+		Values: series,
+	}, "host")
+	require.NoError(t, err)
+	require.Equal(t, len(series), results.Len())
+	assert.Equal(t, "a", results.Values[0].Name())
+	assert.Equal(t, "b", results.Values[1].Name())
+	assert.Equal(t, "c", results.Values[2].Name())
+}
@@ -32,6 +32,8 @@ import (
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/graphite/ts"
 )
 
@@ -92,6 +94,22 @@ func sortByMaxima(ctx *common.Context, series singlePathSpec) (ts.SeriesList, er
 	return highestMax(ctx, series, len(series.Values))
 }
 
+// sortByMinima sorts timeseries by the minimum value across the time period
+// specified, including only series that have a maximum value greater than 0.
+func sortByMinima(_ *common.Context, series singlePathSpec) (ts.SeriesList, error) {
+	filtered := make([]*ts.Series, 0, len(series.Values))
+	for _, s := range series.Values {
+		if s.SafeMax() > 0 {
+			filtered = append(filtered, s)
+		}
+	}
+
+	input := series
+	input.Values = filtered
+	sr := ts.SeriesReducerMin.Reducer()
+	return takeByFunction(input, len(filtered), sr, ts.Ascending)
+}
+
 type valueComparator func(v, threshold float64) bool
 
 func compareByFunction(
@@ -386,6 +404,68 @@ func keepLastValue(ctx *common.Context, input singlePathSpec, limit int) (ts.Ser
 	return r, nil
 }
 
+// interpolate takes one metric or a wildcard seriesList, and optionally a
+// limit to the number of null values to skip over. Continues the line with
+// a linear interpolation between the last known and next known values across
+// null gaps of at most limit points; leading and trailing nulls are kept.
+func interpolate(ctx *common.Context, input singlePathSpec, limit float64) (ts.SeriesList, error) {
+	output := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		consecutiveNaNs := 0
+		numSteps := series.Len()
+		vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		for i := 0; i < numSteps; i++ {
+			value := series.ValueAt(i)
+			vals.SetValueAt(i, value)
+			if math.IsNaN(value) {
+				consecutiveNaNs++
+				continue
+			}
+
+			if consecutiveNaNs > 0 && float64(consecutiveNaNs) <= limit {
+				last := i - consecutiveNaNs - 1
+				if last >= 0 {
+					lastValue := series.ValueAt(last)
+					delta := (value - lastValue) / float64(consecutiveNaNs+1)
+					for index := last + 1; index < i; index++ {
+						vals.SetValueAt(index, lastValue+delta*float64(index-last))
+					}
+				}
+			}
+			consecutiveNaNs = 0
+		}
+		name := fmt.Sprintf("interpolate(%s)", series.Name())
+		newSeries := ts.NewSeries(ctx, name, series.StartTime(), vals)
+		output = append(output, newSeries)
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = output
+	return r, nil
+}
+
+// delay shifts all samples later by an integer number of steps. This can be
+// used for custom derivative calculations, among other things.
+func delay(ctx *common.Context, input singlePathSpec, steps int) (ts.SeriesList, error) {
+	output := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		numSteps := series.Len()
+		vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		for i := 0; i < numSteps; i++ {
+			if j := i - steps; j >= 0 && j < numSteps {
+				vals.SetValueAt(i, series.ValueAt(j))
+			}
+		}
+		name := fmt.Sprintf("delay(%s,%d)", series.Name(), steps)
+		newSeries := ts.NewSeries(ctx, name, series.StartTime(), vals)
+		output = append(output, newSeries)
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = output
+	return r, nil
+}
+
 type comparator func(float64, float64) bool
 
 // lessOrEqualFunc checks whether x is less than or equal to y
@@ -552,53 +632,101 @@ func lowestCurrent(_ *common.Context, input singlePathSpec, n int) (ts.SeriesLis
 	return takeByFunction(input, n, sr, ts.Ascending)
 }
 
-// windowSizeFunc calculates window size for moving average calculation
-type windowSizeFunc func(stepSize int) int
+// aggregationSeriesReducer returns a series reducer that applies the named
+// aggregation function to the non-null values of a series.
+func aggregationSeriesReducer(fname string) (ts.SeriesReducer, error) {
+	fn, err := getSafeAggregationFn(fname)
+	if err != nil {
+		return nil, err
+	}
 
-// movingAverage calculates the moving average of a metric (or metrics) over a time interval.
-func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
-	if len(input.Values) == 0 {
-		return nil, nil
+	return func(s *ts.Series) float64 {
+		values := make([]float64, s.Len())
+		for i := range values {
+			values[i] = s.ValueAt(i)
+		}
+		return safeAggregate(values, fn, 0)
+	}, nil
+}
+
+// highest takes one metric or a wildcard seriesList followed by an integer n
+// and an aggregation function. Out of all metrics passed, draws only the N
+// metrics with the highest aggregated value in the time period specified.
+func highest(_ *common.Context, input singlePathSpec, n int, fname string) (ts.SeriesList, error) {
+	sr, err := aggregationSeriesReducer(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	return takeByFunction(input, n, sr, ts.Descending)
+}
+
+// lowest takes one metric or a wildcard seriesList followed by an integer n
+// and an aggregation function. Out of all metrics passed, draws only the N
+// metrics with the lowest aggregated value in the time period specified.
+func lowest(_ *common.Context, input singlePathSpec, n int, fname string) (ts.SeriesList, error) {
+	sr, err := aggregationSeriesReducer(fname)
+	if err != nil {
+		return ts.NewSeriesList(), err
 	}
+	return takeByFunction(input, n, sr, ts.Ascending)
+}
 
-	var delta time.Duration
-	var wf windowSizeFunc
-	var ws string
+// windowSizeFunc calculates window size for moving average calculation
+type windowSizeFunc func(stepSize int) int
 
+// parseWindowSize parses a moving window size given either as an interval
+// string or as a number of points, returning the duration of the window, a
+// function to compute the number of points in the window for a step size, and
+// the window size formatted for use in series names.
+func parseWindowSize(
+	input singlePathSpec,
+	windowSizeValue genericInterface,
+) (time.Duration, windowSizeFunc, string, error) {
 	switch windowSizeValue := windowSizeValue.(type) {
 	case string:
 		interval, err := common.ParseInterval(windowSizeValue)
 		if err != nil {
-			return nil, err
+			return 0, nil, "", err
 		}
 		if interval <= 0 {
 			err := errors.NewInvalidParamsError(fmt.Errorf(
 				"windowSize must be positive but instead is %v",
 				interval))
-			return nil, err
+			return 0, nil, "", err
 		}
-		wf = func(stepSize int) int { return int(int64(delta/time.Millisecond) / int64(stepSize)) }
-		ws = fmt.Sprintf("%q", windowSizeValue)
-		delta = interval
+		wf := func(stepSize int) int { return int(int64(interval/time.Millisecond) / int64(stepSize)) }
+		return interval, wf, fmt.Sprintf("%q", windowSizeValue), nil
 	case float64:
 		windowSizeInt := int(windowSizeValue)
 		if windowSizeInt <= 0 {
 			err := errors.NewInvalidParamsError(fmt.Errorf(
 				"windowSize must be positive but instead is %d",
 				windowSizeInt))
-			return nil, err
+			return 0, nil, "", err
 		}
-		wf = func(_ int) int { return windowSizeInt }
-		ws = fmt.Sprintf("%d", windowSizeInt)
+		wf := func(_ int) int { return windowSizeInt }
 		maxStepSize := input.Values[0].MillisPerStep()
 		for i := 1; i < len(input.Values); i++ {
 			maxStepSize = int(math.Max(float64(maxStepSize), float64(input.Values[i].MillisPerStep())))
 		}
-		delta = time.Duration(maxStepSize*windowSizeInt) * time.Millisecond
+		delta := time.Duration(maxStepSize*windowSizeInt) * time.Millisecond
+		return delta, wf, fmt.Sprintf("%d", windowSizeInt), nil
 	default:
 		err := errors.NewInvalidParamsError(fmt.Errorf(
 			"windowSize must be either a string or an int but instead is a %T",
 			windowSizeValue))
+		return 0, nil, "", err
+	}
+}
+
+// movingAverage calculates the moving average of a metric (or metrics) over a time interval.
+func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
+	if len(input.Values) == 0 {
+		return nil, nil
+	}
+
+	delta, wf, ws, err := parseWindowSize(input, windowSizeValue)
+	if err != nil {
 		return nil, err
 	}
 
@@ -680,6 +808,104 @@ func movingAverage(ctx *common.Context, input singlePathSpec, windowSizeValue ge
 	}, nil
 }
 
+// movingWindow calculates the given aggregation function of a metric (or
+// metrics) over a moving window given either as a time interval or a number of
+// points. Windows with a ratio of non-null points below xFilesFactor are null.
+func movingWindow(
+	ctx *common.Context,
+	input singlePathSpec,
+	windowSizeValue genericInterface,
+	fname string,
+	xFilesFactor float64,
+) (*binaryContextShifter, error) {
+	fn, err := getSafeAggregationFn(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Values) == 0 {
+		return nil, nil
+	}
+
+	delta, wf, ws, err := parseWindowSize(input, windowSizeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	contextShiftingFn := func(c *common.Context) *common.Context {
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(0, 0, delta, 0)
+		childCtx := c.NewChildContext(opts)
+		return childCtx
+	}
+
+	fnName := "moving" + strings.ToUpper(fname[:1]) + fname[1:]
+	bootstrapStartTime, bootstrapEndTime := ctx.StartTime.Add(-delta), ctx.StartTime
+	transformerFn := func(bootstrapped, original ts.SeriesList) (ts.SeriesList, error) {
+		bootstrapList, err := combineBootstrapWithOriginal(ctx,
+			bootstrapStartTime, bootstrapEndTime,
+			bootstrapped, singlePathSpec(original))
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		results := make([]*ts.Series, 0, original.Len())
+		for i, bootstrap := range bootstrapList.Values {
+			series := original.Values[i]
+			stepSize := series.MillisPerStep()
+			windowPoints := wf(stepSize)
+			if windowPoints == 0 {
+				err := errors.NewInvalidParamsError(fmt.Errorf(
+					"windowSize should not be smaller than stepSize, windowSize=%v, stepSize=%d",
+					windowSizeValue, stepSize))
+				return ts.NewSeriesList(), err
+			}
+
+			numSteps := series.Len()
+			offset := bootstrap.Len() - numSteps
+			vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+			window := make([]float64, windowPoints)
+			for i := 0; i < numSteps; i++ {
+				// skip if the number of points received is less than the number of points
+				// in the lookback window.
+				if offset < windowPoints {
+					continue
+				}
+				for j := 0; j < windowPoints; j++ {
+					window[j] = bootstrap.ValueAt(i + offset - windowPoints + j)
+				}
+				vals.SetValueAt(i, safeAggregate(window, fn, xFilesFactor))
+			}
+			name := fmt.Sprintf("%s(%s,%s)", fnName, series.Name(), ws)
+			newSeries := ts.NewSeries(ctx, name, series.StartTime(), vals)
+			results = append(results, newSeries)
+		}
+
+		original.Values = results
+		return original, nil
+	}
+
+	return &binaryContextShifter{
+		ContextShiftFunc:  contextShiftingFn,
+		BinaryTransformer: transformerFn,
+	}, nil
+}
+
+// movingSum calculates the moving sum of a metric (or metrics) over a time interval.
+func movingSum(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSizeValue, "sum", 0)
+}
+
+// movingMin calculates the moving minimum of a metric (or metrics) over a time interval.
+func movingMin(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSizeValue, "min", 0)
+}
+
+// movingMax calculates the moving maximum of a metric (or metrics) over a time interval.
+func movingMax(ctx *common.Context, input singlePathSpec, windowSizeValue genericInterface) (*binaryContextShifter, error) {
+	return movingWindow(ctx, input, windowSizeValue, "max", 0)
+}
+
 // totalFunc takes an index and returns a total value for that index
 type totalFunc func(int) float64
 
@@ -902,6 +1128,59 @@ func integral(ctx *common.Context, input singlePathSpec) (ts.SeriesList, error)
 	return r, nil
 }
 
+// integralByInterval shows the sum over time for each series, resetting the
+// sum at each interval boundary, where intervals are aligned to the start of
+// the query, e.g. integralByInterval(foo.bar, '1d').
+func integralByInterval(ctx *common.Context, input singlePathSpec, intervalString string) (ts.SeriesList, error) {
+	interval, err := common.ParseInterval(intervalString)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+	if interval <= 0 {
+		err := errors.NewInvalidParamsError(fmt.Errorf(
+			"interval must be positive but instead is %v", interval))
+		return ts.NewSeriesList(), err
+	}
+
+	intervalIndex := func(t time.Time) int64 {
+		elapsed := t.Sub(ctx.StartTime)
+		idx := int64(elapsed / interval)
+		if elapsed < 0 && elapsed%interval != 0 {
+			// NB: floor division for points before the query start.
+			idx--
+		}
+		return idx
+	}
+
+	results := make([]*ts.Series, 0, len(input.Values))
+	for _, series := range input.Values {
+		var (
+			outvals = ts.NewValues(ctx, series.MillisPerStep(), series.Len())
+			step    = time.Duration(series.MillisPerStep()) * time.Millisecond
+			current float64
+		)
+		for i := 0; i < series.Len(); i++ {
+			t := series.StartTimeForStep(i)
+			if intervalIndex(t) != intervalIndex(t.Add(-step)) {
+				current = 0
+			}
+
+			n := series.ValueAt(i)
+			if !math.IsNaN(n) {
+				current += n
+				outvals.SetValueAt(i, current)
+			}
+		}
+
+		newName := fmt.Sprintf("integralByInterval(%s,%q)", series.Name(), intervalString)
+		results = append(results, ts.NewSeries(ctx, newName, series.StartTime(), outvals))
+	}
+
+	r := ts.SeriesList(input)
+	r.Values = results
+	return r, nil
+}
+
 // This is the opposite of the integral function.  This is useful for taking a
 // running total metric and calculating the delta between subsequent data
 // points.
@@ -1465,6 +1744,66 @@ func squareRoot(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList,
 	)
 }
 
+// pow raises each datapoint of each series to the power of the given factor.
+func pow(ctx *common.Context, seriesList singlePathSpec, factor float64) (ts.SeriesList, error) {
+	return transform(
+		ctx,
+		seriesList,
+		func(fname string) string { return fmt.Sprintf("pow(%s,%g)", fname, factor) },
+		common.MaintainNaNTransformer(func(v float64) float64 {
+			r := math.Pow(v, factor)
+			if math.IsInf(r, 0) {
+				return math.NaN()
+			}
+			return r
+		}),
+	)
+}
+
+// invert takes one metric or a wildcard seriesList, and inverts each datapoint
+// (i.e. 1/x). Datapoints equal to zero become null.
+func invert(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
+	return transform(
+		ctx,
+		seriesList,
+		func(fname string) string { return fmt.Sprintf(wrappingFmt, "invert", fname) },
+		func(v float64) float64 {
+			if v == 0 {
+				return math.NaN()
+			}
+			return 1 / v
+		},
+	)
+}
+
+// minMax scales each series to the range [0, 1] using its minimum and
+// maximum values. Series with a constant value are scaled to 0.
+func minMax(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
+	results := make([]*ts.Series, len(seriesList.Values))
+	for idx, series := range seriesList.Values {
+		minimum, maximum := series.SafeMin(), series.SafeMax()
+		numSteps := series.Len()
+		vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		for i := 0; i < numSteps; i++ {
+			v := series.ValueAt(i)
+			if math.IsNaN(v) {
+				continue
+			}
+			if maximum == minimum {
+				vals.SetValueAt(i, 0)
+			} else {
+				vals.SetValueAt(i, (v-minimum)/(maximum-minimum))
+			}
+		}
+		name := fmt.Sprintf("minMax(%s)", series.Name())
+		results[idx] = ts.NewSeries(ctx, name, series.StartTime(), vals)
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	return r, nil
+}
+
 // stdev takes one metric or a wildcard seriesList followed by an integer N. Draw the standard deviation
 // of all metrics passed for the past N datapoints. If the ratio of null points in the window is greater than
 // windowTolerance, skip the calculation.
@@ -1826,16 +2165,213 @@ func threshold(ctx *common.Context, value float64, label string, color string) (
 	return ts.NewSeriesListWithSeries(series), nil
 }
 
+// linearRegression graphs the linear regression function by the least squares
+// method, computed over the source time range given by startSourceAt and
+// endSourceAt (the query time range if unset) and projected over the query
+// time range.
+func linearRegression(
+	ctx *common.Context,
+	_ singlePathSpec,
+	startSourceAt string,
+	endSourceAt string,
+) (*unaryContextShifter, error) {
+	var (
+		now         = time.Now()
+		sourceStart = ctx.StartTime
+		sourceEnd   = ctx.EndTime
+		err         error
+	)
+	if startSourceAt != "" {
+		sourceStart, err = graphite.ParseTime(startSourceAt, now, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if endSourceAt != "" {
+		sourceEnd, err = graphite.ParseTime(endSourceAt, now, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !sourceStart.Before(sourceEnd) {
+		return nil, errors.NewInvalidParamsError(fmt.Errorf(
+			"linearRegression source start %v must be before source end %v",
+			sourceStart, sourceEnd))
+	}
+
+	shiftStart, shiftEnd := sourceStart.Sub(ctx.StartTime), sourceEnd.Sub(ctx.EndTime)
+	contextShiftingFn := func(c *common.Context) *common.Context {
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(shiftStart, shiftEnd, 0, 0)
+		childCtx := c.NewChildContext(opts)
+		return childCtx
+	}
+
+	transformerFn := func(input ts.SeriesList) (ts.SeriesList, error) {
+		output := make([]*ts.Series, 0, input.Len())
+		for _, series := range input.Values {
+			factor, offset, ok := linearRegressionAnalysis(series)
+			if !ok {
+				continue
+			}
+
+			millisPerStep := series.MillisPerStep()
+			numSteps := ts.NumSteps(ctx.StartTime, ctx.EndTime, millisPerStep)
+			vals := ts.NewValues(ctx, millisPerStep, numSteps)
+			for i := 0; i < numSteps; i++ {
+				t := ctx.StartTime.Add(time.Duration(i*millisPerStep) * time.Millisecond)
+				vals.SetValueAt(i, offset+float64(t.UnixNano())/float64(time.Second)*factor)
+			}
+
+			name := fmt.Sprintf("linearRegression(%s, %d, %d)",
+				series.Name(), sourceStart.Unix(), sourceEnd.Unix())
+			output = append(output, ts.NewSeries(ctx, name, ctx.StartTime, vals))
+		}
+
+		input.Values = output
+		return input, nil
+	}
+
+	return &unaryContextShifter{
+		ContextShiftFunc: contextShiftingFn,
+		UnaryTransformer: transformerFn,
+	}, nil
+}
+
+// linearRegressionAnalysis returns the factor (per second) and offset (at the
+// unix epoch) of the least squares linear regression of the series.
+func linearRegressionAnalysis(series *ts.Series) (float64, float64, bool) {
+	var n, sumI, sumV, sumII, sumIV float64
+	for i := 0; i < series.Len(); i++ {
+		v := series.ValueAt(i)
+		if math.IsNaN(v) {
+			continue
+		}
+
+		fi := float64(i)
+		n++
+		sumI += fi
+		sumV += v
+		sumII += fi * fi
+		sumIV += fi * v
+	}
+
+	denominator := n*sumII - sumI*sumI
+	if denominator == 0 {
+		return 0, 0, false
+	}
+
+	stepSeconds := float64(series.MillisPerStep()) / millisPerSecond
+	startSeconds := float64(series.StartTime().UnixNano()) / float64(time.Second)
+	factor := (n*sumIV - sumI*sumV) / denominator / stepSeconds
+	offset := (sumII*sumV-sumIV*sumI)/denominator - factor*startSeconds
+	return factor, offset, true
+}
+
+// useSeriesAbove compares the maximum of each series against the given value.
+// If the series maximum is greater than value, the regular expression search
+// and replace is applied against the series name to plot a related metric.
+func useSeriesAbove(
+	ctx *common.Context,
+	seriesList singlePathSpec,
+	maxAllowedValue float64,
+	search string,
+	replace string,
+) (ts.SeriesList, error) {
+	above := make([]*ts.Series, 0, len(seriesList.Values))
+	for _, series := range seriesList.Values {
+		if series.SafeMax() > maxAllowedValue {
+			above = append(above, series)
+		}
+	}
+
+	renamed, err := common.AliasSub(ctx, ts.SeriesList{Values: above}, search, replace)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	var (
+		results = make([]*ts.Series, 0, renamed.Len())
+		meta    = seriesList.Metadata
+	)
+	for _, series := range renamed.Values {
+		expr, err := compile(series.Name())
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		output, err := expr.Execute(ctx)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		meta = meta.CombineMetadata(output.Metadata)
+		if output.Len() > 0 {
+			results = append(results, output.Values[0])
+		}
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	r.Metadata = meta
+	return r, nil
+}
+
+// seriesByTag fetches the graphite tagged series matching all of the given tag
+// expressions, e.g. seriesByTag('name=foo.bar', 'dc=~us.*').
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	matchers, err := storage.TranslateTagExpressionsToMatchers(tagExpressions)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	opts := storage.FetchOptions{
+		StartTime: ctx.StartTime,
+		EndTime:   ctx.EndTime,
+		DataOptions: storage.DataOptions{
+			Timeout: ctx.Timeout,
+			Limit:   ctx.Limit,
+		},
+	}
+
+	result, err := ctx.Engine.FetchByTagMatchers(ctx, matchers, opts)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	quoted := make([]string, 0, len(tagExpressions))
+	for _, expr := range tagExpressions {
+		quoted = append(quoted, fmt.Sprintf("'%s'", expr))
+	}
+
+	spec := fmt.Sprintf("seriesByTag(%s)", strings.Join(quoted, ","))
+	for _, s := range result.SeriesList {
+		s.Specification = spec
+	}
+
+	return ts.SeriesList{
+		Values:   result.SeriesList,
+		Metadata: result.Metadata,
+	}, nil
+}
+
 func init() {
 	// functions - in alpha ordering
 	MustRegisterFunction(absolute)
+	MustRegisterFunction(aggregate).WithDefaultParams(map[uint8]interface{}{
+		3: 0.0, // xFilesFactor
+	})
 	MustRegisterFunction(aggregateLine).WithDefaultParams(map[uint8]interface{}{
 		2: "avg", // f
 	})
 	MustRegisterFunction(alias)
 	MustRegisterFunction(aliasByMetric)
 	MustRegisterFunction(aliasByNode)
+	MustRegisterFunction(aliasByTags)
 	MustRegisterFunction(aliasSub)
+	MustRegisterFunction(applyByNode).WithDefaultParams(map[uint8]interface{}{
+		4: "", // newName
+	})
 	MustRegisterFunction(asPercent).WithDefaultParams(map[uint8]interface{}{
 		2: []*ts.Series(nil), // total
 	})
@@ -1852,6 +2388,7 @@ func init() {
 	MustRegisterFunction(dashed).WithDefaultParams(map[uint8]interface{}{
 		2: 5.0, // dashLength
 	})
+	MustRegisterFunction(delay)
 	MustRegisterFunction(derivative)
 	MustRegisterFunction(diffSeries)
 	MustRegisterFunction(divideSeries)
@@ -1859,6 +2396,11 @@ func init() {
 	MustRegisterFunction(fallbackSeries)
 	MustRegisterFunction(group)
 	MustRegisterFunction(groupByNode)
+	MustRegisterFunction(groupByTags)
+	MustRegisterFunction(highest).WithDefaultParams(map[uint8]interface{}{
+		2: 1,         // n
+		3: "average", // f
+	})
 	MustRegisterFunction(highestAverage)
 	MustRegisterFunction(highestCurrent)
 	MustRegisterFunction(highestMax)
@@ -1868,6 +2410,11 @@ func init() {
 	MustRegisterFunction(holtWintersForecast)
 	MustRegisterFunction(identity)
 	MustRegisterFunction(integral)
+	MustRegisterFunction(integralByInterval)
+	MustRegisterFunction(interpolate).WithDefaultParams(map[uint8]interface{}{
+		2: math.Inf(1), // limit
+	})
+	MustRegisterFunction(invert)
 	MustRegisterFunction(isNonNull)
 	MustRegisterFunction(keepLastValue).WithDefaultParams(map[uint8]interface{}{
 		2: -1, // limit
@@ -1877,15 +2424,31 @@ func init() {
 	MustRegisterFunction(logarithm).WithDefaultParams(map[uint8]interface{}{
 		2: 10, // base
 	})
+	MustRegisterFunction(linearRegression).WithDefaultParams(map[uint8]interface{}{
+		2: "", // startSourceAt
+		3: "", // endSourceAt
+	})
+	MustRegisterFunction(lowest).WithDefaultParams(map[uint8]interface{}{
+		2: 1,         // n
+		3: "average", // f
+	})
 	MustRegisterFunction(lowestAverage)
 	MustRegisterFunction(lowestCurrent)
 	MustRegisterFunction(maxSeries)
 	MustRegisterFunction(maximumAbove)
+	MustRegisterFunction(minMax)
 	MustRegisterFunction(minSeries)
 	MustRegisterFunction(minimumAbove)
 	MustRegisterFunction(mostDeviant)
 	MustRegisterFunction(movingAverage)
+	MustRegisterFunction(movingMax)
 	MustRegisterFunction(movingMedian)
+	MustRegisterFunction(movingMin)
+	MustRegisterFunction(movingSum)
+	MustRegisterFunction(movingWindow).WithDefaultParams(map[uint8]interface{}{
+		3: "average", // func
+		4: 0.0,       // xFilesFactor
+	})
 	MustRegisterFunction(multiplySeries)
 	MustRegisterFunction(nonNegativeDerivative).WithDefaultParams(map[uint8]interface{}{
 		2: math.NaN(), // maxValue
@@ -1899,6 +2462,7 @@ func init() {
 	MustRegisterFunction(perSecond).WithDefaultParams(map[uint8]interface{}{
 		2: math.NaN(), // maxValue
 	})
+	MustRegisterFunction(pow)
 	MustRegisterFunction(rangeOfSeries)
 	MustRegisterFunction(randomWalkFunction).WithDefaultParams(map[uint8]interface{}{
 		2: 60, // step
@@ -1910,7 +2474,9 @@ func init() {
 	MustRegisterFunction(removeEmptySeries)
 	MustRegisterFunction(scale)
 	MustRegisterFunction(scaleToSeconds)
+	MustRegisterFunction(seriesByTag)
 	MustRegisterFunction(sortByMaxima)
+	MustRegisterFunction(sortByMinima)
 	MustRegisterFunction(sortByName)
 	MustRegisterFunction(sortByTotal)
 	MustRegisterFunction(squareRoot)
@@ -1942,6 +2508,7 @@ func init() {
 	MustRegisterFunction(transformNull).WithDefaultParams(map[uint8]interface{}{
 		2: 0.0, // defaultValue
 	})
+	MustRegisterFunction(useSeriesAbove)
 	MustRegisterFunction(weightedAverage)
 
 	// alias functions - in alpha ordering
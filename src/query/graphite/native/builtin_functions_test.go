@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/query/graphite/storage"
 	xtest "github.com/m3db/m3/src/query/graphite/testing"
 	"github.com/m3db/m3/src/query/graphite/ts"
+	"github.com/m3db/m3/src/query/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1961,6 +1962,12 @@ func (*mockStorage) FetchByQuery(
 	return storage.NewFetchResult(ctx, nil, block.NewResultMetadata()), nil
 }
 
+func (*mockStorage) FetchByTagMatchers(
+	ctx xctx.Context, matchers models.Matchers, opts storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return storage.NewFetchResult(ctx, nil, block.NewResultMetadata()), nil
+}
+
 func TestHoltWintersForecast(t *testing.T) {
 	ctx := common.NewTestContext()
 	ctx.Engine = NewEngine(
@@ -2779,11 +2786,14 @@ func TestFunctionsRegistered(t *testing.T) {
 	fnames := []string{
 		"abs",
 		"absolute",
+		"aggregate",
 		"aggregateLine",
 		"alias",
 		"aliasByMetric",
 		"aliasByNode",
+		"aliasByTags",
 		"aliasSub",
+		"applyByNode",
 		"asPercent",
 		"averageAbove",
 		"averageSeries",
@@ -2797,6 +2807,7 @@ func TestFunctionsRegistered(t *testing.T) {
 		"currentAbove",
 		"currentBelow",
 		"dashed",
+		"delay",
 		"derivative",
 		"diffSeries",
 		"divideSeries",
@@ -2804,6 +2815,8 @@ func TestFunctionsRegistered(t *testing.T) {
 		"fallbackSeries",
 		"group",
 		"groupByNode",
+		"groupByTags",
+		"highest",
 		"highestAverage",
 		"highestCurrent",
 		"highestMax",
@@ -2813,29 +2826,40 @@ func TestFunctionsRegistered(t *testing.T) {
 		"holtWintersForecast",
 		"identity",
 		"integral",
+		"integralByInterval",
+		"interpolate",
+		"invert",
 		"isNonNull",
 		"keepLastValue",
 		"legendValue",
 		"limit",
+		"linearRegression",
 		"log",
 		"logarithm",
+		"lowest",
 		"lowestAverage",
 		"lowestCurrent",
 		"max",
 		"maxSeries",
 		"maximumAbove",
 		"min",
+		"minMax",
 		"minSeries",
 		"minimumAbove",
 		"mostDeviant",
 		"movingAverage",
+		"movingMax",
 		"movingMedian",
+		"movingMin",
+		"movingSum",
+		"movingWindow",
 		"multiplySeries",
 		"nonNegativeDerivative",
 		"nPercentile",
 		"offset",
 		"offsetToZero",
 		"perSecond",
+		"pow",
 		"randomWalk",
 		"randomWalkFunction",
 		"rangeOfSeries",
@@ -2846,7 +2870,9 @@ func TestFunctionsRegistered(t *testing.T) {
 		"removeEmptySeries",
 		"scale",
 		"scaleToSeconds",
+		"seriesByTag",
 		"sortByMaxima",
+		"sortByMinima",
 		"sortByName",
 		"sortByTotal",
 		"squareRoot",
@@ -2860,6 +2886,7 @@ func TestFunctionsRegistered(t *testing.T) {
 		"timeFunction",
 		"timeShift",
 		"transformNull",
+		"useSeriesAbove",
 		"weightedAverage",
 	}
 
@@ -2867,3 +2894,265 @@ func TestFunctionsRegistered(t *testing.T) {
 		assert.NotNil(t, findFunction(fname), "could not find function: %s", fname)
 	}
 }
+
+func TestSortByMinima(t *testing.T) {
+	testSortingFuncs(t, sortByMinima, []int{3, 2, 4, 0})
+}
+
+func TestHighestAndLowest(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	highestWith := func(fname string) rankingFunc {
+		return func(ctx *common.Context, input singlePathSpec, n int) (ts.SeriesList, error) {
+			return highest(ctx, input, n, fname)
+		}
+	}
+	lowestWith := func(fname string) rankingFunc {
+		return func(ctx *common.Context, input singlePathSpec, n int) (ts.SeriesList, error) {
+			return lowest(ctx, input, n, fname)
+		}
+	}
+
+	testRanking(t, ctx, []nIntParamGoldenData{
+		{testInput, 2, []common.TestSeries{testInput[4], testInput[0]}},
+	}, highestWith("max"))
+	testRanking(t, ctx, []nIntParamGoldenData{
+		{testSmallInput, 1, []common.TestSeries{testInput[0]}},
+	}, highestWith("sum"))
+	testRanking(t, ctx, []nIntParamGoldenData{
+		{testSmallInput, 1, []common.TestSeries{testInput[0]}},
+	}, lowestWith("average"))
+	testRanking(t, ctx, []nIntParamGoldenData{
+		{testSmallInput, 1, []common.TestSeries{testInput[2]}},
+	}, lowestWith("min"))
+
+	_, err := highest(ctx, singlePathSpec{}, 1, "unknown")
+	require.Error(t, err)
+	_, err = lowest(ctx, singlePathSpec{}, 1, "unknown")
+	require.Error(t, err)
+}
+
+func TestMovingSumMinMax(t *testing.T) {
+	values := []float64{12.0, 19.0, -10.0, math.NaN(), 10.0}
+	bootstrap := []float64{3.0, 4.0, 5.0}
+	testMovingAverage(t, "movingSum(foo.bar.baz, '30s')", "movingSum(foo.bar.baz,\"30s\")",
+		values, bootstrap, []float64{12, 21, 36, 21, 9})
+	testMovingAverage(t, "movingSum(foo.bar.baz, 3)", "movingSum(foo.bar.baz,3)",
+		values, bootstrap, []float64{12, 21, 36, 21, 9})
+	testMovingAverage(t, "movingMin(foo.bar.baz, 3)", "movingMin(foo.bar.baz,3)",
+		values, bootstrap, []float64{3, 4, 5, -10, -10})
+	testMovingAverage(t, "movingMax(foo.bar.baz, '30s')", "movingMax(foo.bar.baz,\"30s\")",
+		values, bootstrap, []float64{5, 12, 19, 19, 19})
+	testMovingAverage(t, "movingSum(foo.bar.baz, 3)", "movingSum(foo.bar.baz,3)", nil, nil, nil)
+}
+
+func TestMovingWindow(t *testing.T) {
+	values := []float64{12.0, 19.0, -10.0, math.NaN(), 10.0}
+	bootstrap := []float64{3.0, 4.0, 5.0}
+	testMovingAverage(t, "movingWindow(foo.bar.baz, 3)", "movingAverage(foo.bar.baz,3)",
+		values, bootstrap, []float64{4.0, 7.0, 12.0, 7.0, 4.5})
+	testMovingAverage(t, "movingWindow(foo.bar.baz, '30s', 'median')", "movingMedian(foo.bar.baz,\"30s\")",
+		values, bootstrap, []float64{4, 5, 12, 12, 19})
+	testMovingAverage(t, "movingWindow(foo.bar.baz, 3, 'sum', 0.9)", "movingSum(foo.bar.baz,3)",
+		values, bootstrap, []float64{12, 21, 36, 21, math.NaN()})
+}
+
+func TestMovingWindowError(t *testing.T) {
+	testMovingAverageError(t, "movingWindow(foo.bar.baz, 3, 'unknown')")
+	testMovingAverageError(t, "movingSum(foo.bar.baz, '-30s')")
+	testMovingAverageError(t, "movingMax(foo.bar.baz, 0)")
+}
+
+func TestIntegralByInterval(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	input := ts.NewSeries(ctx, "foo", ctx.StartTime,
+		common.NewTestSeriesValues(ctx, 10000, []float64{1, 2, 3, math.NaN(), 5, 6}))
+	r, err := integralByInterval(ctx, singlePathSpec{
+		Values: []*ts.Series{input},
+	}, "20s")
+	require.NoError(t, err)
+
+	expected := []common.TestSeries{{
+		Name: "integralByInterval(foo,\"20s\")",
+		Data: []float64{1, 3, 3, math.NaN(), 5, 11},
+	}}
+	common.CompareOutputsAndExpected(t, 10000, ctx.StartTime, expected, r.Values)
+
+	_, err = integralByInterval(ctx, singlePathSpec{
+		Values: []*ts.Series{input},
+	}, "-20s")
+	require.Error(t, err)
+}
+
+func TestDelay(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := time.Now()
+	input := ts.NewSeries(ctx, "foo", start,
+		common.NewTestSeriesValues(ctx, 100, []float64{1, 2, 3, math.NaN(), 5}))
+
+	tests := []struct {
+		steps    int
+		expected common.TestSeries
+	}{
+		{2, common.TestSeries{Name: "delay(foo,2)", Data: []float64{math.NaN(), math.NaN(), 1, 2, 3}}},
+		{-1, common.TestSeries{Name: "delay(foo,-1)", Data: []float64{2, 3, math.NaN(), 5, math.NaN()}}},
+		{0, common.TestSeries{Name: "delay(foo,0)", Data: []float64{1, 2, 3, math.NaN(), 5}}},
+	}
+
+	for _, test := range tests {
+		r, err := delay(ctx, singlePathSpec{Values: []*ts.Series{input}}, test.steps)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, 100, start,
+			[]common.TestSeries{test.expected}, r.Values)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := time.Now()
+	input := ts.NewSeries(ctx, "foo", start, common.NewTestSeriesValues(ctx, 100,
+		[]float64{math.NaN(), 1, math.NaN(), math.NaN(), 4, 5, math.NaN(), 7, math.NaN()}))
+
+	tests := []struct {
+		limit    float64
+		expected []float64
+	}{
+		{math.Inf(1), []float64{math.NaN(), 1, 2, 3, 4, 5, 6, 7, math.NaN()}},
+		{1, []float64{math.NaN(), 1, math.NaN(), math.NaN(), 4, 5, 6, 7, math.NaN()}},
+	}
+
+	for _, test := range tests {
+		r, err := interpolate(ctx, singlePathSpec{Values: []*ts.Series{input}}, test.limit)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, 100, start, []common.TestSeries{
+			{Name: "interpolate(foo)", Data: test.expected},
+		}, r.Values)
+	}
+}
+
+func TestPowAndInvert(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := time.Now()
+	input := ts.NewSeries(ctx, "foo", start,
+		common.NewTestSeriesValues(ctx, 100, []float64{-2, 0, 4, math.NaN()}))
+
+	r, err := pow(ctx, singlePathSpec{Values: []*ts.Series{input}}, 2)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 100, start, []common.TestSeries{
+		{Name: "pow(foo,2)", Data: []float64{4, 0, 16, math.NaN()}},
+	}, r.Values)
+
+	r, err = pow(ctx, singlePathSpec{Values: []*ts.Series{input}}, 0.5)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 100, start, []common.TestSeries{
+		{Name: "pow(foo,0.5)", Data: []float64{math.NaN(), 0, 2, math.NaN()}},
+	}, r.Values)
+
+	r, err = invert(ctx, singlePathSpec{Values: []*ts.Series{input}})
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 100, start, []common.TestSeries{
+		{Name: "invert(foo)", Data: []float64{-0.5, math.NaN(), 0.25, math.NaN()}},
+	}, r.Values)
+}
+
+func TestMinMax(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := time.Now()
+	inputs := []*ts.Series{
+		ts.NewSeries(ctx, "foo", start,
+			common.NewTestSeriesValues(ctx, 100, []float64{1, 3, math.NaN(), 5})),
+		ts.NewSeries(ctx, "bar", start,
+			common.NewTestSeriesValues(ctx, 100, []float64{2, 2, math.NaN(), 2})),
+	}
+
+	r, err := minMax(ctx, singlePathSpec{Values: inputs})
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 100, start, []common.TestSeries{
+		{Name: "minMax(foo)", Data: []float64{0, 0.5, math.NaN(), 1}},
+		{Name: "minMax(bar)", Data: []float64{0, 0, math.NaN(), 0}},
+	}, r.Values)
+}
+
+func TestLinearRegression(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	end := start.Add(50 * time.Second)
+	ctx := common.NewContext(common.ContextOptions{Start: start, End: end})
+	defer ctx.Close()
+
+	ctx.Engine = mockEngine{fn: func(
+		ctx xctx.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		return storage.NewFetchResult(ctx, []*ts.Series{
+			ts.NewSeries(ctx, query, options.StartTime,
+				common.NewTestSeriesValues(ctx, 10000, []float64{1, math.NaN(), 3, 4, 5})),
+		}, block.NewResultMetadata()), nil
+	}}
+
+	expr, err := compile("linearRegression(foo.bar)")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+
+	expected := common.TestSeries{
+		Name: fmt.Sprintf("linearRegression(foo.bar, %d, %d)", start.Unix(), end.Unix()),
+		Data: []float64{1, 2, 3, 4, 5},
+	}
+	common.CompareOutputsAndExpected(t, 10000, start,
+		[]common.TestSeries{expected}, res.Values)
+
+	expr, err = compile("linearRegression(foo.bar, '00:00_20150101', '00:00_20140101')")
+	require.NoError(t, err)
+	_, err = expr.Execute(ctx)
+	require.Error(t, err)
+}
+
+func TestUseSeriesAbove(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer ctx.Close()
+
+	start := ctx.StartTime
+	var fetched []string
+	ctx.Engine = mockEngine{fn: func(
+		ctx xctx.Context,
+		query string,
+		options storage.FetchOptions,
+	) (*storage.FetchResult, error) {
+		fetched = append(fetched, query)
+		return storage.NewFetchResult(ctx, []*ts.Series{
+			ts.NewSeries(ctx, query, options.StartTime,
+				common.NewTestSeriesValues(ctx, 100, []float64{1, 2})),
+		}, block.NewResultMetadata()), nil
+	}}
+
+	inputs := []*ts.Series{
+		ts.NewSeries(ctx, "hosts.a.reqs", start,
+			common.NewTestSeriesValues(ctx, 100, []float64{5, 20})),
+		ts.NewSeries(ctx, "hosts.b.reqs", start,
+			common.NewTestSeriesValues(ctx, 100, []float64{5, 8})),
+		ts.NewSeries(ctx, "hosts.c.reqs", start,
+			common.NewTestSeriesValues(ctx, 100, []float64{11, math.NaN()})),
+	}
+
+	r, err := useSeriesAbove(ctx, singlePathSpec{Values: inputs}, 10, "reqs", "errors")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Len())
+	assert.Equal(t, "hosts.a.errors", r.Values[0].Name())
+	assert.Equal(t, "hosts.c.errors", r.Values[1].Name())
+	assert.Equal(t, []string{"hosts.a.errors", "hosts.c.errors"}, fetched)
+
+	_, err = useSeriesAbove(ctx, singlePathSpec{Values: inputs}, 10, "(", "errors")
+	require.Error(t, err)
+}
@@ -23,6 +23,7 @@ package native
 import (
 	"github.com/m3db/m3/src/query/graphite/context"
 	"github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
 )
 
 // The Engine for running queries.
@@ -48,6 +49,15 @@ func (e *Engine) FetchByQuery(
 	return e.storage.FetchByQuery(ctx, query, options)
 }
 
+// FetchByTagMatchers retrieves one or more time series based on tag matchers.
+func (e *Engine) FetchByTagMatchers(
+	ctx context.Context,
+	matchers models.Matchers,
+	options storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return e.storage.FetchByTagMatchers(ctx, matchers, options)
+}
+
 // Compile compiles an expression from an expression string
 func (e *Engine) Compile(s string) (Expression, error) {
 	return compile(s)
@@ -21,6 +21,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -139,10 +140,43 @@ func truncateBoundsToResolution(
 	return start, end
 }
 
+// seriesNameFn returns the graphite series name for the given series meta.
+type seriesNameFn func(meta block.SeriesMeta) string
+
+func seriesMetaName(meta block.SeriesMeta) string {
+	return string(meta.Name)
+}
+
+// taggedSeriesMetaName returns the series name in the graphite tagged format,
+// using the metric name tag as the name of the series.
+func taggedSeriesMetaName(meta block.SeriesMeta) string {
+	var (
+		name       = string(meta.Name)
+		metricName = taggedNameTagName
+		tags       = make(map[string]string, len(meta.Tags.Tags))
+	)
+
+	if meta.Tags.Opts != nil {
+		metricName = meta.Tags.Opts.MetricName()
+	}
+
+	for _, tag := range meta.Tags.Tags {
+		if bytes.Equal(tag.Name, metricName) {
+			name = string(tag.Value)
+			continue
+		}
+
+		tags[string(tag.Name)] = string(tag.Value)
+	}
+
+	return graphite.FormatTaggedName(name, tags)
+}
+
 func translateTimeseries(
 	ctx xctx.Context,
 	result block.Result,
 	start, end time.Time,
+	nameFn seriesNameFn,
 ) ([]*ts.Series, error) {
 	if len(result.Blocks) == 0 {
 		return []*ts.Series{}, nil
@@ -193,7 +227,7 @@ func translateTimeseries(
 			values.SetValueAt(index, datapoint.Value)
 		}
 
-		name := string(seriesMetas[idx].Name)
+		name := nameFn(seriesMetas[idx])
 		series = append(series, ts.NewSeries(ctx, name, start, values))
 	}
 
@@ -220,6 +254,29 @@ func (s *m3WrappedStore) FetchByQuery(
 		}, nil
 	}
 
+	return s.fetch(ctx, m3query, opts, seriesMetaName)
+}
+
+func (s *m3WrappedStore) FetchByTagMatchers(
+	ctx xctx.Context, matchers models.Matchers, opts FetchOptions,
+) (*FetchResult, error) {
+	m3query := &storage.FetchQuery{
+		Raw:         matchers.String(),
+		TagMatchers: matchers,
+		Start:       opts.StartTime,
+		End:         opts.EndTime,
+		Interval:    time.Duration(0),
+	}
+
+	return s.fetch(ctx, m3query, opts, taggedSeriesMetaName)
+}
+
+func (s *m3WrappedStore) fetch(
+	ctx xctx.Context,
+	m3query *storage.FetchQuery,
+	opts FetchOptions,
+	nameFn seriesNameFn,
+) (*FetchResult, error) {
 	m3ctx, cancel := context.WithTimeout(ctx.RequestContext(), opts.Timeout)
 	defer cancel()
 	fetchOptions := storage.NewFetchOptions()
@@ -246,7 +303,8 @@ func (s *m3WrappedStore) FetchByQuery(
 		return nil, fmt.Errorf("expected at most one block, received %d", blockCount)
 	}
 
-	series, err := translateTimeseries(ctx, res, opts.StartTime, opts.EndTime)
+	series, err := translateTimeseries(ctx, res, opts.StartTime, opts.EndTime,
+		nameFn)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +158,8 @@ func TestTranslateTimeseries(t *testing.T) {
 
 	expected := 5
 	result := buildResult(ctrl, resolution, expected, steps, start)
-	translated, err := translateTimeseries(ctx, result, start, end)
+	translated, err := translateTimeseries(ctx, result, start, end,
+		seriesMetaName)
 	require.NoError(t, err)
 
 	require.Equal(t, expected, len(translated))
@@ -246,3 +247,58 @@ func TestFetchByInvalidQuery(t *testing.T) {
 	assert.NoError(t, err)
 	require.Equal(t, 0, len(result.SeriesList))
 }
+
+func TestTaggedSeriesMetaName(t *testing.T) {
+	tags := models.NewTags(3, models.NewTagOptions()).
+		AddTag(models.Tag{Name: []byte("dc"), Value: []byte("us-east")}).
+		AddTag(models.Tag{Name: []byte("__name__"), Value: []byte("foo.bar")}).
+		AddTag(models.Tag{Name: []byte("host"), Value: []byte("a")})
+	meta := block.SeriesMeta{Name: []byte("id"), Tags: tags}
+	assert.Equal(t, "foo.bar;dc=us-east;host=a", taggedSeriesMetaName(meta))
+
+	meta = block.SeriesMeta{Name: []byte("id")}
+	assert.Equal(t, "id", taggedSeriesMetaName(meta))
+}
+
+func TestFetchByTagMatchers(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	resolution := 10 * time.Second
+	start := time.Now().Add(time.Hour * -1).Truncate(resolution).Add(time.Second)
+	steps := 3
+	res := buildResult(ctrl, resolution, 1, steps, start)
+
+	matchers, err := TranslateTagExpressionsToMatchers([]string{"name=foo"})
+	require.NoError(t, err)
+
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			assert.Equal(t, matchers, query.TagMatchers)
+			assert.Equal(t, start, query.Start)
+			return res, nil
+		})
+
+	wrapper := NewM3WrappedStorage(store, nil, instrument.NewOptions())
+	ctx := xctx.New()
+	ctx.SetRequestContext(context.TODO())
+	end := start.Add(time.Duration(steps) * resolution)
+	opts := FetchOptions{
+		StartTime: start,
+		EndTime:   end,
+		DataOptions: DataOptions{
+			Timeout: time.Minute,
+		},
+	}
+
+	result, err := wrapper.FetchByTagMatchers(ctx, matchers, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.SeriesList))
+	assert.Equal(t, "a0", result.SeriesList[0].Name())
+	assert.Equal(t, []float64{0, 0}, result.SeriesList[0].SafeValues())
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/graphite/context"
 	"github.com/m3db/m3/src/query/graphite/ts"
+	"github.com/m3db/m3/src/query/models"
 )
 
 // FetchOptions provides context to a fetch expression.
@@ -55,6 +56,12 @@ type Storage interface {
 	FetchByQuery(
 		ctx context.Context, query string, opts FetchOptions,
 	) (*FetchResult, error)
+
+	// FetchByTagMatchers fetches graphite tagged timeseries data matching the
+	// given tag matchers, naming each series in the graphite tagged format.
+	FetchByTagMatchers(
+		ctx context.Context, matchers models.Matchers, opts FetchOptions,
+	) (*FetchResult, error)
 }
 
 // FetchResult provides a fetch result and meta information.
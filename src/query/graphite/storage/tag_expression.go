@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m3db/m3/src/query/graphite/errors"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	tagExpressionEqual       = "="
+	tagExpressionNotEqual    = "!="
+	tagExpressionRegexp      = "=~"
+	tagExpressionNotRegexp   = "!=~"
+	tagExpressionInvalidChar = ";"
+)
+
+var (
+	// NB: graphite tagged series carry their metric name in the "name" tag,
+	// which is stored using the default metric name tag in M3.
+	taggedNameTagName = models.NewTagOptions().MetricName()
+
+	errNoPositiveTagExpression = errors.NewInvalidParamsError(errors.New(
+		"at least one tag expression must match a non-empty value"))
+)
+
+// TranslateTagExpressionsToMatchers converts graphite tag expressions, as used
+// by seriesByTag, to tag matchers. Supported operators are "=", "!=", "=~"
+// and "!=~"; an empty value with "=" matches series without the tag and with
+// "!=" matches series with the tag. As in graphite, regular expressions are
+// only anchored at the start of the tag value.
+func TranslateTagExpressionsToMatchers(
+	expressions []string,
+) (models.Matchers, error) {
+	var (
+		matchers = make(models.Matchers, 0, len(expressions))
+		positive = false
+	)
+
+	for _, expr := range expressions {
+		m, err := translateTagExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Type == models.MatchEqual || m.Type == models.MatchRegexp {
+			positive = true
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	if !positive {
+		return nil, errNoPositiveTagExpression
+	}
+
+	return matchers, nil
+}
+
+func translateTagExpression(expr string) (models.Matcher, error) {
+	idx := strings.IndexAny(expr, "!=")
+	if idx <= 0 || strings.Contains(expr, tagExpressionInvalidChar) {
+		return models.Matcher{}, errors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression: %s", expr))
+	}
+
+	var (
+		tag   = expr[:idx]
+		rest  = expr[idx:]
+		op    string
+		value string
+	)
+
+	// NB: check longest operators first since they share prefixes.
+	for _, candidate := range []string{
+		tagExpressionNotRegexp,
+		tagExpressionRegexp,
+		tagExpressionNotEqual,
+		tagExpressionEqual,
+	} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			value = rest[len(candidate):]
+			break
+		}
+	}
+
+	name := []byte(tag)
+	if tag == graphite.TaggedNameTag {
+		name = taggedNameTagName
+	}
+
+	switch op {
+	case tagExpressionEqual:
+		if value == "" {
+			return models.NewMatcher(models.MatchNotField, name, nil)
+		}
+		return models.NewMatcher(models.MatchEqual, name, []byte(value))
+	case tagExpressionNotEqual:
+		if value == "" {
+			return models.NewMatcher(models.MatchField, name, nil)
+		}
+		return models.NewMatcher(models.MatchNotEqual, name, []byte(value))
+	case tagExpressionRegexp:
+		return newTagExpressionRegexpMatcher(models.MatchRegexp, name, value)
+	case tagExpressionNotRegexp:
+		return newTagExpressionRegexpMatcher(models.MatchNotRegexp, name, value)
+	default:
+		return models.Matcher{}, errors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression operator: %s", expr))
+	}
+}
+
+func newTagExpressionRegexpMatcher(
+	t models.MatchType,
+	name []byte,
+	value string,
+) (models.Matcher, error) {
+	// NB: M3 regexp matchers are fully anchored whereas graphite only anchors
+	// the start of the tag value, so allow any trailing characters.
+	m, err := models.NewMatcher(t, name, []byte("(?:"+value+").*"))
+	if err != nil {
+		return models.Matcher{}, errors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression regexp %s: %v", value, err))
+	}
+
+	return m, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateTagExpressionsToMatchers(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{
+		"name=foo.bar",
+		"dc=~us.*",
+		"env!=prod",
+		"host!=~web|db",
+		"rack=",
+		"zone!=",
+	})
+	require.NoError(t, err)
+
+	expected := []struct {
+		t     models.MatchType
+		name  string
+		value string
+	}{
+		{t: models.MatchEqual, name: "__name__", value: "foo.bar"},
+		{t: models.MatchRegexp, name: "dc", value: "(?:us.*).*"},
+		{t: models.MatchNotEqual, name: "env", value: "prod"},
+		{t: models.MatchNotRegexp, name: "host", value: "(?:web|db).*"},
+		{t: models.MatchNotField, name: "rack"},
+		{t: models.MatchField, name: "zone"},
+	}
+
+	require.Equal(t, len(expected), len(matchers))
+	for i, ex := range expected {
+		assert.Equal(t, ex.t, matchers[i].Type)
+		assert.Equal(t, ex.name, string(matchers[i].Name))
+		assert.Equal(t, ex.value, string(matchers[i].Value))
+	}
+}
+
+func TestTranslateTagExpressionsRegexpAnchoredAtStart(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{"dc=~us"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(matchers))
+
+	// NB: M3 anchors regexp matchers on both ends.
+	re := regexp.MustCompile("^(?:" + string(matchers[0].Value) + ")$")
+	assert.True(t, re.MatchString("us-east"))
+	assert.False(t, re.MatchString("eu-us"))
+}
+
+func TestTranslateTagExpressionsToMatchersErrors(t *testing.T) {
+	for _, exprs := range [][]string{
+		{"name"},
+		{"=foo"},
+		{"name=foo;dc=us"},
+		{"name!foo"},
+		{"dc=~(us"},
+		{"dc!=us"},
+		{"dc="},
+		{},
+	} {
+		_, err := TranslateTagExpressionsToMatchers(exprs)
+		assert.Error(t, err, "%v", exprs)
+	}
+}
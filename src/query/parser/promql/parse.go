@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions"
 	"github.com/m3db/m3/src/query/functions/binary"
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/scalar"
@@ -172,6 +173,34 @@ func adjustOffset(offset time.Duration, step time.Duration) time.Duration {
 	return offset + step - align
 }
 
+// newSubqueryOp walks the inner expression of a subquery at the subquery step,
+// building the inner DAG separately from the enclosing query.
+func (p *parseState) newSubqueryOp(n *pql.SubqueryExpr) (parser.Params, error) {
+	step := n.Step
+	if step == 0 {
+		// NB: subqueries without an explicit step use the query step.
+		step = p.stepSize
+	}
+
+	inner := &parseState{
+		stepSize:          step,
+		tagOpts:           p.tagOpts,
+		parseFunctionExpr: p.parseFunctionExpr,
+	}
+
+	if err := inner.walk(n.Expr); err != nil {
+		return nil, err
+	}
+
+	return functions.SubqueryOp{
+		Range:  n.Range,
+		Offset: n.Offset,
+		Step:   n.Step,
+		Nodes:  inner.transforms,
+		Edges:  inner.edges,
+	}, nil
+}
+
 func (p *parseState) walk(node pql.Node) error {
 	if node == nil {
 		return nil
@@ -212,6 +241,21 @@ func (p *parseState) walk(node pql.Node) error {
 		)
 		return p.addLazyOffsetTransform(n.Offset)
 
+	case *pql.SubqueryExpr:
+		// Align offset to stepSize.
+		n.Offset = adjustOffset(n.Offset, p.stepSize)
+		operation, err := p.newSubqueryOp(n)
+		if err != nil {
+			return err
+		}
+
+		p.transforms = append(
+			p.transforms,
+			parser.NewTransformFromOperation(operation, p.transformLen()),
+		)
+
+		return p.addLazyOffsetTransform(n.Offset)
+
 	case *pql.VectorSelector:
 		// Align offset to stepSize.
 		n.Offset = adjustOffset(n.Offset, p.stepSize)
@@ -299,6 +343,10 @@ func (p *parseState) walk(node pql.Node) error {
 					argValues = append(argValues, e.Range)
 				}
 
+				if e, ok := expr.(*pql.SubqueryExpr); ok {
+					argValues = append(argValues, e.Range)
+				}
+
 				if err := p.walk(expr); err != nil {
 					return err
 				}
@@ -491,6 +491,59 @@ func TestTagParses(t *testing.T) {
 	}
 }
 
+func TestSubqueryParses(t *testing.T) {
+	q := "max_over_time(rate(up[5m])[1h:1m])"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	assert.Len(t, transforms, 2)
+	assert.Equal(t, transforms[0].Op.OpType(), functions.SubqueryType)
+	assert.Equal(t, transforms[0].ID, parser.NodeID("0"))
+	assert.Equal(t, transforms[1].Op.OpType(), temporal.MaxType)
+	assert.Equal(t, transforms[1].ID, parser.NodeID("1"))
+	assert.Len(t, edges, 1)
+	assert.Equal(t, edges[0].ParentID, parser.NodeID("0"))
+	assert.Equal(t, edges[0].ChildID, parser.NodeID("1"))
+
+	op, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, op.Range)
+	assert.Equal(t, time.Minute, op.Step)
+	assert.Equal(t, time.Duration(0), op.Offset)
+
+	innerTransforms, innerEdges := op.DAG()
+	assert.Len(t, innerTransforms, 2)
+	assert.Equal(t, innerTransforms[0].Op.OpType(), functions.FetchType)
+	assert.Equal(t, innerTransforms[1].Op.OpType(), temporal.RateType)
+	assert.Len(t, innerEdges, 1)
+	assert.Equal(t, innerEdges[0].ParentID, parser.NodeID("0"))
+	assert.Equal(t, innerEdges[0].ChildID, parser.NodeID("1"))
+}
+
+func TestSubqueryWithOffsetAndDefaultStepParses(t *testing.T) {
+	q := "min_over_time(sum(up)[30m:] offset 2m)"
+	p, err := Parse(q, time.Minute, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	assert.Len(t, transforms, 3)
+	assert.Equal(t, transforms[0].Op.OpType(), functions.SubqueryType)
+	assert.Equal(t, transforms[1].Op.OpType(), lazy.OffsetType)
+	assert.Equal(t, transforms[2].Op.OpType(), temporal.MinType)
+	assert.Len(t, edges, 2)
+
+	op, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Minute, op.Range)
+	assert.Equal(t, time.Duration(0), op.Step)
+	assert.Equal(t, 2*time.Minute, op.Offset)
+
+	innerTransforms, _ := op.DAG()
+	assert.Len(t, innerTransforms, 2)
+	assert.Equal(t, innerTransforms[1].Op.OpType(), aggregation.SumType)
+}
+
 func TestFailedTemporalParse(t *testing.T) {
 	q := "unknown_over_time(http_requests_total[5m])"
 	_, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
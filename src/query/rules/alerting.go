@@ -0,0 +1,313 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	// AlertMetricName is the name of the series recording active alerts.
+	AlertMetricName = "ALERTS"
+	// AlertNameLabel is the label holding the name of an alerting rule.
+	AlertNameLabel = "alertname"
+	// AlertStateLabel is the label holding the state of an alert.
+	AlertStateLabel = "alertstate"
+
+	// resolvedRetention is how long resolved alerts are kept so that their
+	// resolution is sent to the notifier.
+	resolvedRetention = 15 * time.Minute
+)
+
+// AlertingRule evaluates an expression and generates an alert for each
+// resulting series, which fires once it has been active for the hold duration.
+type AlertingRule struct {
+	ruleState
+
+	name         string
+	query        string
+	holdDuration time.Duration
+	labels       models.Tags
+	annotations  models.Tags
+	tagOpts      models.TagOptions
+
+	mu     sync.Mutex
+	active map[uint64]*Alert
+}
+
+// NewAlertingRule creates a new alerting rule.
+func NewAlertingRule(
+	name string,
+	query string,
+	holdDuration time.Duration,
+	labels models.Tags,
+	annotations models.Tags,
+	tagOpts models.TagOptions,
+) *AlertingRule {
+	return &AlertingRule{
+		ruleState:    newRuleState(),
+		name:         name,
+		query:        query,
+		holdDuration: holdDuration,
+		labels:       labels,
+		annotations:  annotations,
+		tagOpts:      tagOpts,
+		active:       make(map[uint64]*Alert),
+	}
+}
+
+// Name returns the name of the rule.
+func (r *AlertingRule) Name() string { return r.name }
+
+// Type returns the type of the rule.
+func (r *AlertingRule) Type() RuleType { return AlertingRuleType }
+
+// Query returns the expression of the rule.
+func (r *AlertingRule) Query() string { return r.query }
+
+// Labels returns the labels added to the alerts of the rule.
+func (r *AlertingRule) Labels() models.Tags { return r.labels }
+
+// Annotations returns the annotations added to the alerts of the rule.
+func (r *AlertingRule) Annotations() models.Tags { return r.annotations }
+
+// HoldDuration returns the duration an alert must be active before firing.
+func (r *AlertingRule) HoldDuration() time.Duration { return r.holdDuration }
+
+// Eval evaluates the rule expression and updates the state of the alerts of
+// the rule, returning samples describing the pending and firing alerts.
+func (r *AlertingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	vector, err := query(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make(map[uint64]*Alert, len(vector))
+	for _, sample := range vector {
+		alert, err := r.newAlert(sample)
+		if err != nil {
+			return nil, err
+		}
+
+		h := alert.Labels.HashedID()
+		if _, ok := alerts[h]; ok {
+			return nil, fmt.Errorf("vector contains series with the same "+
+				"labelset after applying alert labels: %s", alert.Labels)
+		}
+
+		alerts[h] = alert
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, alert := range alerts {
+		if existing, ok := r.active[h]; ok && existing.State != StateInactive {
+			existing.Value = alert.Value
+			existing.Annotations = alert.Annotations
+			continue
+		}
+
+		alert.State = StatePending
+		alert.ActiveAt = t
+		r.active[h] = alert
+	}
+
+	result := make(Vector, 0, len(r.active))
+	for h, alert := range r.active {
+		if _, ok := alerts[h]; !ok {
+			// NB: pending alerts and alerts resolved for longer than the
+			// retention period are removed, otherwise the alert is resolved.
+			if alert.State == StatePending ||
+				(!alert.ResolvedAt.IsZero() &&
+					t.Sub(alert.ResolvedAt) > resolvedRetention) {
+				delete(r.active, h)
+			}
+
+			if alert.State != StateInactive {
+				alert.State = StateInactive
+				alert.ResolvedAt = t
+			}
+
+			continue
+		}
+
+		if alert.State == StatePending && t.Sub(alert.ActiveAt) >= r.holdDuration {
+			alert.State = StateFiring
+			alert.FiredAt = t
+		}
+
+		result = append(result, r.alertSample(alert, t))
+	}
+
+	return result, nil
+}
+
+func (r *AlertingRule) newAlert(sample Sample) (*Alert, error) {
+	tags := sample.Tags.WithoutName()
+	labels := tags.Clone()
+	for _, label := range r.labels.Tags {
+		value, err := expandTemplate(string(label.Name), string(label.Value),
+			tags, sample.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = labels.AddOrUpdateTag(models.Tag{
+			Name:  label.Name,
+			Value: []byte(value),
+		})
+	}
+
+	labels = labels.AddOrUpdateTag(models.Tag{
+		Name:  []byte(AlertNameLabel),
+		Value: []byte(r.name),
+	})
+
+	annotations := models.NewTags(r.annotations.Len(), r.tagOpts)
+	for _, annotation := range r.annotations.Tags {
+		value, err := expandTemplate(string(annotation.Name),
+			string(annotation.Value), tags, sample.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations = annotations.AddTag(models.Tag{
+			Name:  annotation.Name,
+			Value: []byte(value),
+		})
+	}
+
+	return &Alert{
+		Labels:      labels,
+		Annotations: annotations,
+		Value:       sample.Value,
+	}, nil
+}
+
+func (r *AlertingRule) alertSample(alert *Alert, t time.Time) Sample {
+	tags := alert.Labels.Clone().
+		SetName([]byte(AlertMetricName)).
+		AddOrUpdateTag(models.Tag{
+			Name:  []byte(AlertStateLabel),
+			Value: []byte(alert.State.String()),
+		})
+
+	return Sample{
+		Tags:      tags,
+		Value:     1,
+		Timestamp: t,
+	}
+}
+
+// State returns the maximum state across all alerts of the rule.
+func (r *AlertingRule) State() AlertState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := StateInactive
+	for _, alert := range r.active {
+		if alert.State > state {
+			state = alert.State
+		}
+	}
+
+	return state
+}
+
+// ActiveAlerts returns copies of all pending and firing alerts of the rule,
+// sorted by their labels.
+func (r *AlertingRule) ActiveAlerts() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(r.active))
+	for _, alert := range r.active {
+		if alert.State != StateInactive {
+			alerts = append(alerts, *alert)
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Labels.String() < alerts[j].Labels.String()
+	})
+
+	return alerts
+}
+
+// sendAlerts sends all alerts which need to be sent to the notifier.
+func (r *AlertingRule) sendAlerts(
+	ctx context.Context,
+	t time.Time,
+	resendDelay time.Duration,
+	interval time.Duration,
+	notifier Notifier,
+) error {
+	r.mu.Lock()
+	alerts := make([]*Alert, 0, len(r.active))
+	for _, alert := range r.active {
+		if !alert.needsSending(t, resendDelay) {
+			continue
+		}
+
+		alert.LastSentAt = t
+		// NB: allow for a couple of missed evaluations before the alert is
+		// considered resolved by the receiver.
+		delta := resendDelay
+		if interval > resendDelay {
+			delta = interval
+		}
+
+		alert.ValidUntil = t.Add(4 * delta)
+		copied := *alert
+		alerts = append(alerts, &copied)
+	}
+
+	r.mu.Unlock()
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	return notifier.Send(ctx, alerts)
+}
+
+func (a *Alert) needsSending(t time.Time, resendDelay time.Duration) bool {
+	if a.State == StatePending {
+		return false
+	}
+
+	// NB: if an alert has been resolved since the last send, resend it.
+	if a.ResolvedAt.After(a.LastSentAt) {
+		return true
+	}
+
+	return a.LastSentAt.Add(resendDelay).Before(t)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func alertStates(t *testing.T, vector Vector) []string {
+	states := make([]string, 0, len(vector))
+	for _, sample := range vector {
+		name, ok := sample.Tags.Name()
+		require.True(t, ok)
+		assert.Equal(t, AlertMetricName, string(name))
+
+		state, ok := sample.Tags.Get([]byte(AlertStateLabel))
+		require.True(t, ok)
+		states = append(states, string(state))
+	}
+
+	return states
+}
+
+func TestAlertingRuleLifecycle(t *testing.T) {
+	rule := NewAlertingRule("InstanceDown", "up == 0", time.Minute,
+		testTags("severity", "page"),
+		testTags("summary", "{{ $labels.instance }} is down ({{ $value }})"),
+		models.NewTagOptions())
+	assert.Equal(t, AlertingRuleType, rule.Type())
+
+	var (
+		ctx    = context.Background()
+		start  = time.Now().Truncate(time.Second)
+		active = staticQueryFunc(Vector{
+			{Tags: testTags("__name__", "up", "instance", "a"), Value: 0},
+		}, nil)
+		inactive = staticQueryFunc(Vector{}, nil)
+	)
+
+	vector, err := rule.Eval(ctx, start, active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pending"}, alertStates(t, vector))
+	assert.Equal(t, StatePending, rule.State())
+
+	alerts := rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, start, alerts[0].ActiveAt)
+
+	name, ok := alerts[0].Labels.Get([]byte(AlertNameLabel))
+	require.True(t, ok)
+	assert.Equal(t, "InstanceDown", string(name))
+
+	severity, ok := alerts[0].Labels.Get([]byte("severity"))
+	require.True(t, ok)
+	assert.Equal(t, "page", string(severity))
+
+	_, ok = alerts[0].Labels.Name()
+	assert.False(t, ok)
+
+	summary, ok := alerts[0].Annotations.Get([]byte("summary"))
+	require.True(t, ok)
+	assert.Equal(t, "a is down (0)", string(summary))
+
+	vector, err = rule.Eval(ctx, start.Add(30*time.Second), active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pending"}, alertStates(t, vector))
+
+	vector, err = rule.Eval(ctx, start.Add(time.Minute), active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"firing"}, alertStates(t, vector))
+	assert.Equal(t, StateFiring, rule.State())
+
+	alerts = rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, start, alerts[0].ActiveAt)
+	assert.Equal(t, start.Add(time.Minute), alerts[0].FiredAt)
+
+	vector, err = rule.Eval(ctx, start.Add(2*time.Minute), inactive)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(vector))
+	assert.Equal(t, StateInactive, rule.State())
+	assert.Equal(t, 0, len(rule.ActiveAlerts()))
+
+	// NB: the alert becoming active again restarts the pending period.
+	vector, err = rule.Eval(ctx, start.Add(3*time.Minute), active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pending"}, alertStates(t, vector))
+
+	alerts = rule.ActiveAlerts()
+	require.Equal(t, 1, len(alerts))
+	assert.Equal(t, start.Add(3*time.Minute), alerts[0].ActiveAt)
+}
+
+func TestAlertingRulePendingAlertRemoved(t *testing.T) {
+	rule := NewAlertingRule("InstanceDown", "up == 0", time.Minute,
+		models.EmptyTags(), models.EmptyTags(), models.NewTagOptions())
+
+	var (
+		ctx    = context.Background()
+		start  = time.Now()
+		active = staticQueryFunc(Vector{
+			{Tags: testTags("instance", "a"), Value: 0},
+		}, nil)
+	)
+
+	_, err := rule.Eval(ctx, start, active)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(rule.active))
+
+	_, err = rule.Eval(ctx, start.Add(time.Second), staticQueryFunc(nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(rule.active))
+}
+
+func TestAlertingRuleDuplicateLabelset(t *testing.T) {
+	rule := NewAlertingRule("Foo", "up", 0, testTags("instance", "x"),
+		models.EmptyTags(), models.NewTagOptions())
+	query := staticQueryFunc(Vector{
+		{Tags: testTags("instance", "a"), Value: 0},
+		{Tags: testTags("instance", "b"), Value: 0},
+	}, nil)
+
+	_, err := rule.Eval(context.Background(), time.Now(), query)
+	assert.Error(t, err)
+}
+
+type recordingNotifier struct {
+	sync.Mutex
+	sent [][]*Alert
+}
+
+func (n *recordingNotifier) Send(_ context.Context, alerts []*Alert) error {
+	n.Lock()
+	n.sent = append(n.sent, alerts)
+	n.Unlock()
+	return nil
+}
+
+func TestAlertingRuleSendAlerts(t *testing.T) {
+	rule := NewAlertingRule("InstanceDown", "up == 0", 0,
+		models.EmptyTags(), models.EmptyTags(), models.NewTagOptions())
+
+	var (
+		ctx         = context.Background()
+		start       = time.Now()
+		resendDelay = time.Minute
+		interval    = 10 * time.Second
+		notifier    = &recordingNotifier{}
+		active      = staticQueryFunc(Vector{
+			{Tags: testTags("instance", "a"), Value: 0},
+		}, nil)
+	)
+
+	_, err := rule.Eval(ctx, start, active)
+	require.NoError(t, err)
+	require.NoError(t, rule.sendAlerts(ctx, start, resendDelay, interval, notifier))
+	require.Equal(t, 1, len(notifier.sent))
+	assert.Equal(t, StateFiring, notifier.sent[0][0].State)
+	assert.Equal(t, start.Add(4*resendDelay), notifier.sent[0][0].ValidUntil)
+
+	// NB: not resent within the resend delay.
+	next := start.Add(interval)
+	_, err = rule.Eval(ctx, next, active)
+	require.NoError(t, err)
+	require.NoError(t, rule.sendAlerts(ctx, next, resendDelay, interval, notifier))
+	assert.Equal(t, 1, len(notifier.sent))
+
+	// NB: resolved alerts are sent immediately.
+	next = next.Add(interval)
+	_, err = rule.Eval(ctx, next, staticQueryFunc(nil, nil))
+	require.NoError(t, err)
+	require.NoError(t, rule.sendAlerts(ctx, next, resendDelay, interval, notifier))
+	require.Equal(t, 2, len(notifier.sent))
+	assert.Equal(t, StateInactive, notifier.sent[1][0].State)
+	assert.Equal(t, next, notifier.sent[1][0].ResolvedAt)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Configuration is the configuration for the rule evaluator.
+type Configuration struct {
+	// RuleFiles is a list of Prometheus rule files to load, which may be glob
+	// patterns.
+	RuleFiles []string `yaml:"ruleFiles"`
+
+	// EvaluationInterval is the evaluation interval for rule groups which do
+	// not specify their own interval.
+	EvaluationInterval *time.Duration `yaml:"evaluationInterval"`
+
+	// ResendDelay is the minimum delay before resending a firing alert.
+	ResendDelay *time.Duration `yaml:"resendDelay"`
+
+	// Alertmanager configures where alert notifications are sent.
+	Alertmanager *AlertmanagerConfiguration `yaml:"alertmanager"`
+}
+
+// AlertmanagerConfiguration is the configuration for sending alerts to an
+// Alertmanager-compatible webhook.
+type AlertmanagerConfiguration struct {
+	// URL is the URL alerts are posted to, e.g.
+	// http://alertmanager:9093/api/v1/alerts.
+	URL string `yaml:"url" validate:"nonzero"`
+
+	// Timeout is the timeout for sending alerts.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// NewManager creates a new rule manager from the configuration.
+func (c Configuration) NewManager(
+	engine executor.Engine,
+	appender storage.Appender,
+	tagOpts models.TagOptions,
+	fetchOpts *storage.FetchOptions,
+	instrumentOpts instrument.Options,
+) (Manager, error) {
+	opts := ManagerOptions{
+		Engine:            engine,
+		Appender:          appender,
+		TagOptions:        tagOpts,
+		FetchOptions:      fetchOpts,
+		InstrumentOptions: instrumentOpts,
+	}
+
+	if c.EvaluationInterval != nil {
+		opts.EvaluationInterval = *c.EvaluationInterval
+	}
+
+	if c.ResendDelay != nil {
+		opts.ResendDelay = *c.ResendDelay
+	}
+
+	if c.Alertmanager != nil {
+		opts.Notifier = NewWebhookNotifier(c.Alertmanager.URL,
+			c.Alertmanager.Timeout)
+	}
+
+	return NewManager(c.RuleFiles, opts)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/prometheus/common/model"
+	pql "github.com/prometheus/prometheus/promql"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	errNoGroupName      = errors.New("rule group name must not be empty")
+	errNoExpr           = errors.New("rule expression must not be empty")
+	errRecordAndAlert   = errors.New("only one of 'record' and 'alert' must be set")
+	errNoRecordOrAlert  = errors.New("one of 'record' or 'alert' must be set")
+	errRecordAnnotation = errors.New("invalid field 'annotations' in recording rule")
+	errRecordFor        = errors.New("invalid field 'for' in recording rule")
+)
+
+// RuleGroups is a set of rule groups, in the Prometheus rule file format.
+type RuleGroups struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a named set of rules which are evaluated on a shared interval.
+type RuleGroup struct {
+	Name     string         `yaml:"name"`
+	Interval model.Duration `yaml:"interval,omitempty"`
+	Rules    []RuleConfig   `yaml:"rules"`
+}
+
+// RuleConfig describes either a recording or an alerting rule.
+type RuleConfig struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         model.Duration    `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// ParseFile reads and validates the rule groups in a rule file.
+func ParseFile(file string) (*RuleGroups, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse rule file %s: %v", file, err)
+	}
+
+	return groups, nil
+}
+
+// Parse parses and validates rule groups from their YAML representation.
+func Parse(content []byte) (*RuleGroups, error) {
+	var groups RuleGroups
+	if err := yaml.UnmarshalStrict(content, &groups); err != nil {
+		return nil, err
+	}
+
+	if err := groups.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &groups, nil
+}
+
+// Validate validates the rule groups.
+func (g RuleGroups) Validate() error {
+	var (
+		multiErr = xerrors.NewMultiError()
+		names    = make(map[string]struct{}, len(g.Groups))
+	)
+
+	for _, group := range g.Groups {
+		if group.Name == "" {
+			multiErr = multiErr.Add(errNoGroupName)
+			continue
+		}
+
+		if _, ok := names[group.Name]; ok {
+			multiErr = multiErr.Add(
+				fmt.Errorf("group %s: repeated in the same file", group.Name))
+		}
+
+		names[group.Name] = struct{}{}
+		for i, rule := range group.Rules {
+			if err := rule.Validate(); err != nil {
+				multiErr = multiErr.Add(
+					fmt.Errorf("group %s, rule %d: %v", group.Name, i, err))
+			}
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+// Validate validates the rule.
+func (r RuleConfig) Validate() error {
+	if r.Record != "" && r.Alert != "" {
+		return errRecordAndAlert
+	}
+
+	if r.Record == "" && r.Alert == "" {
+		return errNoRecordOrAlert
+	}
+
+	if r.Expr == "" {
+		return errNoExpr
+	}
+
+	if _, err := pql.ParseExpr(r.Expr); err != nil {
+		return fmt.Errorf("could not parse expression: %v", err)
+	}
+
+	if r.Record != "" {
+		if len(r.Annotations) > 0 {
+			return errRecordAnnotation
+		}
+
+		if r.For != 0 {
+			return errRecordFor
+		}
+
+		if !model.IsValidMetricName(model.LabelValue(r.Record)) {
+			return fmt.Errorf("invalid recording rule name: %s", r.Record)
+		}
+	}
+
+	for name, value := range r.Labels {
+		if name == model.MetricNameLabel {
+			return fmt.Errorf("invalid label name: %s", name)
+		}
+
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name: %s", name)
+		}
+
+		if err := validateTemplate(name, value); err != nil {
+			return err
+		}
+	}
+
+	for name, value := range r.Annotations {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid annotation name: %s", name)
+		}
+
+		if err := validateTemplate(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
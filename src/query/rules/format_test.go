@@ -0,0 +1,211 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validRules = `
+groups:
+  - name: example
+    interval: 30s
+    rules:
+      - record: job:up:sum
+        expr: sum(up) by (job)
+        labels:
+          source: rules
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: page
+        annotations:
+          summary: "Instance {{ $labels.instance }} down"
+`
+
+func TestParseValidRules(t *testing.T) {
+	groups, err := Parse([]byte(validRules))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(groups.Groups))
+
+	group := groups.Groups[0]
+	assert.Equal(t, "example", group.Name)
+	assert.Equal(t, model.Duration(30*time.Second), group.Interval)
+	require.Equal(t, 2, len(group.Rules))
+
+	assert.Equal(t, "job:up:sum", group.Rules[0].Record)
+	assert.Equal(t, map[string]string{"source": "rules"}, group.Rules[0].Labels)
+
+	assert.Equal(t, "InstanceDown", group.Rules[1].Alert)
+	assert.Equal(t, model.Duration(5*time.Minute), group.Rules[1].For)
+	assert.Equal(t, "Instance {{ $labels.instance }} down",
+		group.Rules[1].Annotations["summary"])
+}
+
+func TestParseFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "rules.yml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(validRules), 0644))
+
+	groups, err := ParseFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(groups.Groups))
+
+	_, err = ParseFile(filepath.Join(dir, "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestParseUnknownField(t *testing.T) {
+	_, err := Parse([]byte(`
+groups:
+  - name: example
+    rules:
+      - record: foo
+        expr: up
+        unknown: field
+`))
+	assert.Error(t, err)
+}
+
+func TestParseInvalidGroups(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{
+			name: "no group name",
+			rules: `
+groups:
+  - rules:
+      - record: foo
+        expr: up
+`,
+		},
+		{
+			name: "duplicate group name",
+			rules: `
+groups:
+  - name: example
+    rules:
+      - record: foo
+        expr: up
+  - name: example
+    rules:
+      - record: bar
+        expr: up
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.rules))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRuleConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  RuleConfig
+		valid bool
+	}{
+		{
+			name:  "valid recording rule",
+			rule:  RuleConfig{Record: "job:up:sum", Expr: "sum(up)"},
+			valid: true,
+		},
+		{
+			name:  "valid alerting rule",
+			rule:  RuleConfig{Alert: "Down", Expr: "up == 0", For: model.Duration(time.Minute)},
+			valid: true,
+		},
+		{
+			name: "record and alert",
+			rule: RuleConfig{Record: "foo", Alert: "foo", Expr: "up"},
+		},
+		{
+			name: "no record or alert",
+			rule: RuleConfig{Expr: "up"},
+		},
+		{
+			name: "no expression",
+			rule: RuleConfig{Record: "foo"},
+		},
+		{
+			name: "invalid expression",
+			rule: RuleConfig{Record: "foo", Expr: "sum(up"},
+		},
+		{
+			name: "invalid record name",
+			rule: RuleConfig{Record: "foo-bar", Expr: "up"},
+		},
+		{
+			name: "recording rule with annotations",
+			rule: RuleConfig{Record: "foo", Expr: "up",
+				Annotations: map[string]string{"summary": "foo"}},
+		},
+		{
+			name: "recording rule with for",
+			rule: RuleConfig{Record: "foo", Expr: "up",
+				For: model.Duration(time.Minute)},
+		},
+		{
+			name: "metric name label",
+			rule: RuleConfig{Record: "foo", Expr: "up",
+				Labels: map[string]string{"__name__": "bar"}},
+		},
+		{
+			name: "invalid label name",
+			rule: RuleConfig{Alert: "foo", Expr: "up",
+				Labels: map[string]string{"bad-label": "bar"}},
+		},
+		{
+			name: "invalid annotation template",
+			rule: RuleConfig{Alert: "foo", Expr: "up",
+				Annotations: map[string]string{"summary": "{{ $labels.foo "}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
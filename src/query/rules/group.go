@@ -0,0 +1,231 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+// GroupOptions are the options shared by the rule groups of a manager.
+type GroupOptions struct {
+	// QueryFunc evaluates the rule expressions of the group.
+	QueryFunc QueryFunc
+	// Appender writes the results of the rules of the group.
+	Appender storage.Appender
+	// Notifier sends the alerts of the group, if set.
+	Notifier Notifier
+	// ResendDelay is the minimum delay before resending a firing alert.
+	ResendDelay time.Duration
+	// NowFn is the function used to get the current time.
+	NowFn clock.NowFn
+	// Logger is the logger for the group.
+	Logger *zap.Logger
+	// Scope is the metrics scope for the group.
+	Scope tally.Scope
+}
+
+// Group is a set of rules which are evaluated sequentially on an interval.
+type Group struct {
+	name     string
+	file     string
+	interval time.Duration
+	rules    []Rule
+	opts     GroupOptions
+	metrics  groupMetrics
+
+	mu                 sync.RWMutex
+	lastEvaluation     time.Time
+	evaluationDuration time.Duration
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       chan struct{}
+	terminated chan struct{}
+}
+
+type groupMetrics struct {
+	evaluations       tally.Counter
+	evaluationErrors  tally.Counter
+	evaluationLatency tally.Timer
+	writeErrors       tally.Counter
+	notifyErrors      tally.Counter
+}
+
+func newGroupMetrics(scope tally.Scope) groupMetrics {
+	return groupMetrics{
+		evaluations:       scope.Counter("evaluation.success"),
+		evaluationErrors:  scope.Counter("evaluation.errors"),
+		evaluationLatency: scope.Timer("evaluation.latency"),
+		writeErrors:       scope.Counter("write.errors"),
+		notifyErrors:      scope.Counter("notify.errors"),
+	}
+}
+
+// NewGroup creates a new rule group.
+func NewGroup(
+	name string,
+	file string,
+	interval time.Duration,
+	rules []Rule,
+	opts GroupOptions,
+) *Group {
+	scope := opts.Scope.Tagged(map[string]string{"rule_group": name})
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		name:       name,
+		file:       file,
+		interval:   interval,
+		rules:      rules,
+		opts:       opts,
+		metrics:    newGroupMetrics(scope),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		terminated: make(chan struct{}),
+	}
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string { return g.name }
+
+// File returns the file the group was loaded from.
+func (g *Group) File() string { return g.file }
+
+// Interval returns the evaluation interval of the group.
+func (g *Group) Interval() time.Duration { return g.interval }
+
+// Rules returns the rules of the group.
+func (g *Group) Rules() []Rule { return g.rules }
+
+// LastEvaluation returns the time of the last evaluation of the group.
+func (g *Group) LastEvaluation() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.lastEvaluation
+}
+
+// EvaluationDuration returns the duration of the last evaluation of the group.
+func (g *Group) EvaluationDuration() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.evaluationDuration
+}
+
+// Eval evaluates all rules of the group at the given time, writing their
+// results and sending any alerts.
+func (g *Group) Eval(ctx context.Context, t time.Time) {
+	start := g.opts.NowFn()
+	for _, rule := range g.rules {
+		vector, err := rule.Eval(ctx, t, g.opts.QueryFunc)
+		if err != nil {
+			rule.SetHealth(HealthBad)
+			rule.SetLastError(err)
+			g.metrics.evaluationErrors.Inc(1)
+			g.opts.Logger.Warn("rule evaluation failed",
+				zap.String("group", g.name),
+				zap.String("rule", rule.Name()),
+				zap.Error(err))
+			continue
+		}
+
+		rule.SetHealth(HealthGood)
+		rule.SetLastError(nil)
+		g.metrics.evaluations.Inc(1)
+
+		if alerting, ok := rule.(*AlertingRule); ok && g.opts.Notifier != nil {
+			if err := alerting.sendAlerts(ctx, t, g.opts.ResendDelay,
+				g.interval, g.opts.Notifier); err != nil {
+				g.metrics.notifyErrors.Inc(1)
+				g.opts.Logger.Warn("unable to send alerts",
+					zap.String("group", g.name),
+					zap.String("rule", rule.Name()),
+					zap.Error(err))
+			}
+		}
+
+		for _, sample := range vector {
+			err := g.opts.Appender.Write(ctx, &storage.WriteQuery{
+				Tags: sample.Tags,
+				Datapoints: ts.Datapoints{
+					{
+						Timestamp: sample.Timestamp,
+						Value:     sample.Value,
+					},
+				},
+				Unit: xtime.Millisecond,
+				Attributes: storage.Attributes{
+					MetricsType: storage.UnaggregatedMetricsType,
+				},
+			})
+			if err != nil {
+				g.metrics.writeErrors.Inc(1)
+				g.opts.Logger.Warn("unable to write rule result",
+					zap.String("group", g.name),
+					zap.String("rule", rule.Name()),
+					zap.Error(err))
+			}
+		}
+	}
+
+	duration := g.opts.NowFn().Sub(start)
+	g.metrics.evaluationLatency.Record(duration)
+
+	g.mu.Lock()
+	g.lastEvaluation = t
+	g.evaluationDuration = duration
+	g.mu.Unlock()
+}
+
+// run evaluates the group on its interval until stopped.
+func (g *Group) run() {
+	defer close(g.terminated)
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	g.Eval(g.ctx, g.opts.NowFn())
+	for {
+		select {
+		case <-g.done:
+			return
+		case <-ticker.C:
+			g.Eval(g.ctx, g.opts.NowFn())
+		}
+	}
+}
+
+// stop cancels any running evaluation of the group, stops further
+// evaluations and waits for the group to terminate.
+func (g *Group) stop() {
+	g.cancel()
+	close(g.done)
+	<-g.terminated
+}
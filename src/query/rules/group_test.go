@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+func TestGroupEval(t *testing.T) {
+	var (
+		store = mock.NewMockStorage()
+		scope = tally.NewTestScope("", nil)
+		now   = time.Now().Truncate(time.Second)
+	)
+
+	query := func(_ context.Context, q string, t time.Time) (Vector, error) {
+		switch q {
+		case "up":
+			return Vector{
+				{Tags: testTags("__name__", "up", "job", "a"), Value: 1, Timestamp: t},
+			}, nil
+		case "up == 0":
+			return Vector{
+				{Tags: testTags("instance", "b"), Value: 0, Timestamp: t},
+			}, nil
+		}
+
+		return nil, errors.New("unknown query")
+	}
+
+	recording := NewRecordingRule("job:up", "up", models.EmptyTags())
+	alerting := NewAlertingRule("InstanceDown", "up == 0", time.Minute,
+		models.EmptyTags(), models.EmptyTags(), models.NewTagOptions())
+	failing := NewRecordingRule("failing", "bad", models.EmptyTags())
+	notifier := &recordingNotifier{}
+
+	group := NewGroup("example", "rules.yml", 10*time.Second,
+		[]Rule{recording, alerting, failing}, GroupOptions{
+			QueryFunc:   query,
+			Appender:    store,
+			Notifier:    notifier,
+			ResendDelay: time.Minute,
+			NowFn:       time.Now,
+			Logger:      zap.NewNop(),
+			Scope:       scope,
+		})
+
+	group.Eval(context.Background(), now)
+	assert.Equal(t, now, group.LastEvaluation())
+
+	assert.Equal(t, HealthGood, recording.Health())
+	assert.NoError(t, recording.LastError())
+	assert.Equal(t, HealthGood, alerting.Health())
+	assert.Equal(t, HealthBad, failing.Health())
+	assert.Error(t, failing.LastError())
+
+	// NB: pending alerts are not sent.
+	assert.Equal(t, 0, len(notifier.sent))
+
+	writes := store.Writes()
+	require.Equal(t, 2, len(writes))
+
+	name, ok := writes[0].Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "job:up", string(name))
+	require.Equal(t, 1, len(writes[0].Datapoints))
+	assert.Equal(t, now, writes[0].Datapoints[0].Timestamp)
+	assert.Equal(t, float64(1), writes[0].Datapoints[0].Value)
+	assert.Equal(t, storage.UnaggregatedMetricsType,
+		writes[0].Attributes.MetricsType)
+
+	name, ok = writes[1].Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, AlertMetricName, string(name))
+
+	group.Eval(context.Background(), now.Add(time.Minute))
+	require.Equal(t, 1, len(notifier.sent))
+	assert.Equal(t, StateFiring, notifier.sent[0][0].State)
+
+	tags := map[string]string{"rule_group": "example"}
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(4),
+		counters[tally.KeyForPrefixedStringMap("evaluation.success", tags)].Value())
+	assert.Equal(t, int64(2),
+		counters[tally.KeyForPrefixedStringMap("evaluation.errors", tags)].Value())
+}
+
+func TestGroupStopCancelsEvaluation(t *testing.T) {
+	started := make(chan struct{})
+	query := func(ctx context.Context, _ string, _ time.Time) (Vector, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	group := NewGroup("blocking", "rules.yml", time.Hour,
+		[]Rule{NewRecordingRule("blocked", "up", models.EmptyTags())},
+		GroupOptions{
+			QueryFunc: query,
+			Appender:  mock.NewMockStorage(),
+			NowFn:     time.Now,
+			Logger:    zap.NewNop(),
+			Scope:     tally.NoopScope,
+		})
+
+	go group.run()
+	<-started
+
+	// NB: stop only returns once the blocked evaluation sees the
+	// cancellation.
+	group.stop()
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultEvaluationInterval = time.Minute
+	defaultResendDelay        = time.Minute
+)
+
+var (
+	errEngineNotSet          = errors.New("query engine not set")
+	errAppenderNotSet        = errors.New("appender not set")
+	errInstrumentOptsNotSet  = errors.New("instrument options not set")
+	errManagerAlreadyStarted = errors.New("rule manager already started")
+	errManagerNotStarted     = errors.New("rule manager not started")
+	errManagerClosed         = errors.New("rule manager already closed")
+	errNegativeEvaluation    = errors.New("evaluation interval must not be negative")
+	errNegativeResendDelay   = errors.New("resend delay must not be negative")
+)
+
+// ManagerOptions are the options for a rule manager.
+type ManagerOptions struct {
+	// Engine is the query engine used to evaluate rules.
+	Engine executor.Engine
+	// Appender writes the results of rules.
+	Appender storage.Appender
+	// Notifier sends alerts, if set.
+	Notifier Notifier
+	// TagOptions are the tag options used for rule results.
+	TagOptions models.TagOptions
+	// FetchOptions are the fetch options used to evaluate rules.
+	FetchOptions *storage.FetchOptions
+	// EvaluationInterval is the interval for groups which do not set one.
+	EvaluationInterval time.Duration
+	// ResendDelay is the minimum delay before resending a firing alert.
+	ResendDelay time.Duration
+	// NowFn is the function used to get the current time.
+	NowFn clock.NowFn
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+// Validate validates the manager options.
+func (o *ManagerOptions) Validate() error {
+	if o.Engine == nil {
+		return errEngineNotSet
+	}
+
+	if o.Appender == nil {
+		return errAppenderNotSet
+	}
+
+	if o.InstrumentOptions == nil {
+		return errInstrumentOptsNotSet
+	}
+
+	if o.EvaluationInterval < 0 {
+		return errNegativeEvaluation
+	}
+
+	if o.ResendDelay < 0 {
+		return errNegativeResendDelay
+	}
+
+	return nil
+}
+
+type manager struct {
+	sync.RWMutex
+
+	groups  []*Group
+	started bool
+	closed  bool
+}
+
+// NewManager loads the rule groups from the given rule files, which may be
+// glob patterns, and returns a manager which evaluates them.
+func NewManager(files []string, opts ManagerOptions) (Manager, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.TagOptions == nil {
+		opts.TagOptions = models.NewTagOptions()
+	}
+
+	if opts.FetchOptions == nil {
+		opts.FetchOptions = storage.NewFetchOptions()
+	}
+
+	if opts.EvaluationInterval == 0 {
+		opts.EvaluationInterval = defaultEvaluationInterval
+	}
+
+	if opts.ResendDelay == 0 {
+		opts.ResendDelay = defaultResendDelay
+	}
+
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	var paths []string
+	for _, pattern := range files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule file pattern %s: %v",
+				pattern, err)
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	var (
+		groups []*Group
+		iOpts  = opts.InstrumentOptions
+		scope  = iOpts.MetricsScope().SubScope("rules")
+	)
+
+	for _, path := range paths {
+		ruleGroups, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rg := range ruleGroups.Groups {
+			interval := time.Duration(rg.Interval)
+			if interval == 0 {
+				interval = opts.EvaluationInterval
+			}
+
+			queryOpts := &executor.QueryOptions{
+				QueryContextOptions: models.QueryContextOptions{
+					LimitMaxTimeseries: opts.FetchOptions.Limit,
+				},
+			}
+
+			groups = append(groups, NewGroup(rg.Name, path, interval,
+				newRules(rg, opts.TagOptions), GroupOptions{
+					QueryFunc: EngineQueryFunc(opts.Engine, opts.TagOptions,
+						opts.FetchOptions, queryOpts, interval),
+					Appender:    opts.Appender,
+					Notifier:    opts.Notifier,
+					ResendDelay: opts.ResendDelay,
+					NowFn:       opts.NowFn,
+					Logger:      iOpts.Logger(),
+					Scope:       scope,
+				}))
+		}
+	}
+
+	return &manager{groups: groups}, nil
+}
+
+func newRules(rg RuleGroup, tagOpts models.TagOptions) []Rule {
+	rules := make([]Rule, 0, len(rg.Rules))
+	for _, r := range rg.Rules {
+		labels := tagsFromMap(r.Labels, tagOpts)
+		if r.Record != "" {
+			rules = append(rules, NewRecordingRule(r.Record, r.Expr, labels))
+			continue
+		}
+
+		rules = append(rules, NewAlertingRule(r.Alert, r.Expr,
+			time.Duration(r.For), labels,
+			tagsFromMap(r.Annotations, tagOpts), tagOpts))
+	}
+
+	return rules
+}
+
+func (m *manager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.started {
+		return errManagerAlreadyStarted
+	}
+
+	m.started = true
+	for _, g := range m.groups {
+		go g.run()
+	}
+
+	return nil
+}
+
+func (m *manager) Groups() []*Group {
+	m.RLock()
+	defer m.RUnlock()
+	return m.groups
+}
+
+func (m *manager) AlertingRules() []*AlertingRule {
+	m.RLock()
+	defer m.RUnlock()
+
+	var rules []*AlertingRule
+	for _, g := range m.groups {
+		for _, r := range g.Rules() {
+			if alerting, ok := r.(*AlertingRule); ok {
+				rules = append(rules, alerting)
+			}
+		}
+	}
+
+	return rules
+}
+
+func (m *manager) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.started {
+		return errManagerNotStarted
+	}
+
+	if m.closed {
+		return errManagerClosed
+	}
+
+	m.closed = true
+	var wg sync.WaitGroup
+	for _, g := range m.groups {
+		g := g
+		wg.Add(1)
+		go func() {
+			g.stop()
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
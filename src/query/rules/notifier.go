@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+const (
+	defaultNotifierTimeout = 10 * time.Second
+	contentTypeJSON        = "application/json"
+)
+
+// alertPayload is the Alertmanager-compatible representation of an alert.
+type alertPayload struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt,omitempty"`
+	EndsAt      time.Time         `json:"endsAt,omitempty"`
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier which posts alerts as JSON to an
+// Alertmanager-compatible webhook, e.g. the Alertmanager alerts API.
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	if timeout <= 0 {
+		timeout = defaultNotifierTimeout
+	}
+
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *webhookNotifier) Send(ctx context.Context, alerts []*Alert) error {
+	payload := make([]alertPayload, 0, len(alerts))
+	for _, alert := range alerts {
+		p := alertPayload{
+			Labels:      tagsToMap(alert.Labels),
+			Annotations: tagsToMap(alert.Annotations),
+			StartsAt:    alert.FiredAt,
+			EndsAt:      alert.ValidUntil,
+		}
+
+		if !alert.ResolvedAt.IsZero() {
+			p.EndsAt = alert.ResolvedAt
+		}
+
+		payload = append(payload, p)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentTypeJSON)
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	// NB: drain the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notifier %s returned status code %d",
+			n.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func tagsToMap(tags models.Tags) map[string]string {
+	m := make(map[string]string, len(tags.Tags))
+	for _, t := range tags.Tags {
+		m[string(t.Name)] = string(t.Value)
+	}
+
+	return m
+}
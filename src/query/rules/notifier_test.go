@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifierSend(t *testing.T) {
+	var received []alertPayload
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, contentTypeJSON, r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	var (
+		firedAt    = time.Unix(1000, 0).UTC()
+		validUntil = time.Unix(2000, 0).UTC()
+		resolvedAt = time.Unix(1500, 0).UTC()
+	)
+
+	notifier := NewWebhookNotifier(server.URL, time.Second)
+	err := notifier.Send(context.Background(), []*Alert{
+		{
+			State:       StateFiring,
+			Labels:      testTags("alertname", "a"),
+			Annotations: testTags("summary", "foo"),
+			FiredAt:     firedAt,
+			ValidUntil:  validUntil,
+		},
+		{
+			State:      StateInactive,
+			Labels:     testTags("alertname", "b"),
+			FiredAt:    firedAt,
+			ValidUntil: validUntil,
+			ResolvedAt: resolvedAt,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(received))
+	assert.Equal(t, map[string]string{"alertname": "a"}, received[0].Labels)
+	assert.Equal(t, map[string]string{"summary": "foo"}, received[0].Annotations)
+	assert.True(t, firedAt.Equal(received[0].StartsAt))
+	assert.True(t, validUntil.Equal(received[0].EndsAt))
+
+	assert.Equal(t, map[string]string{"alertname": "b"}, received[1].Labels)
+	assert.True(t, resolvedAt.Equal(received[1].EndsAt))
+}
+
+func TestWebhookNotifierSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second)
+	err := notifier.Send(context.Background(), []*Alert{
+		{State: StateFiring, Labels: testTags("alertname", "a")},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+// EngineQueryFunc returns a QueryFunc which evaluates instant queries through
+// the query engine, using the given step for any range evaluation required by
+// the query (e.g. subqueries without an explicit step).
+func EngineQueryFunc(
+	engine executor.Engine,
+	tagOpts models.TagOptions,
+	fetchOpts *storage.FetchOptions,
+	queryOpts *executor.QueryOptions,
+	step time.Duration,
+) QueryFunc {
+	return func(ctx context.Context, query string, t time.Time) (Vector, error) {
+		engineOpts := engine.Options()
+		parser, err := promql.Parse(query, step, tagOpts,
+			engineOpts.ParseOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		params := models.RequestParams{
+			Start:            t,
+			End:              t,
+			Now:              t,
+			Step:             step,
+			Query:            query,
+			IncludeEnd:       true,
+			BlockType:        fetchOpts.BlockType,
+			LookbackDuration: fetchOpts.LookbackDurationOrDefault(engineOpts.LookbackDuration()),
+		}
+
+		result, err := engine.ExecuteExpr(ctx, parser, queryOpts, fetchOpts, params)
+		if err != nil {
+			return nil, err
+		}
+
+		resultChan := result.ResultChan()
+		defer func() {
+			for range resultChan {
+				// NB: drain result channel in case of early termination.
+			}
+		}()
+
+		var vector Vector
+		for r := range resultChan {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+
+			vector, err = appendLastStep(vector, r.Block, t)
+			r.Block.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return vector, nil
+	}
+}
+
+// appendLastStep appends the values at the last step of a block as samples.
+func appendLastStep(vector Vector, b block.Block, t time.Time) (Vector, error) {
+	iter, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	defer iter.Close()
+	var values []float64
+	for iter.Next() {
+		values = append(values[:0], iter.Current().Values()...)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		commonTags = b.Meta().Tags.Tags
+		metas      = iter.SeriesMeta()
+	)
+
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+
+		vector = append(vector, Sample{
+			Tags:      metas[i].Tags.AddTags(commonTags),
+			Value:     v,
+			Timestamp: t,
+		})
+	}
+
+	return vector, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// RecordingRule evaluates an expression and records the results as new
+// series under the rule name.
+type RecordingRule struct {
+	ruleState
+
+	name   string
+	query  string
+	labels models.Tags
+}
+
+// NewRecordingRule creates a new recording rule.
+func NewRecordingRule(name, query string, labels models.Tags) *RecordingRule {
+	return &RecordingRule{
+		ruleState: newRuleState(),
+		name:      name,
+		query:     query,
+		labels:    labels,
+	}
+}
+
+// Name returns the name of the rule.
+func (r *RecordingRule) Name() string { return r.name }
+
+// Type returns the type of the rule.
+func (r *RecordingRule) Type() RuleType { return RecordingRuleType }
+
+// Query returns the expression of the rule.
+func (r *RecordingRule) Query() string { return r.query }
+
+// Labels returns the labels added to the results of the rule.
+func (r *RecordingRule) Labels() models.Tags { return r.labels }
+
+// Eval evaluates the rule expression, renaming the resulting series to the
+// rule name and applying the rule labels.
+func (r *RecordingRule) Eval(
+	ctx context.Context,
+	t time.Time,
+	query QueryFunc,
+) (Vector, error) {
+	vector, err := query(ctx, r.query, t)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(vector))
+	for i, sample := range vector {
+		tags := sample.Tags.Clone().SetName([]byte(r.name))
+		for _, label := range r.labels.Tags {
+			tags = tags.AddOrUpdateTag(label)
+		}
+
+		id := string(tags.ID())
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("vector contains series with the same "+
+				"labelset after applying rule labels: %s", tags)
+		}
+
+		seen[id] = struct{}{}
+		vector[i].Tags = tags
+	}
+
+	return vector, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTags(pairs ...string) models.Tags {
+	tags := models.NewTags(len(pairs)/2, models.NewTagOptions())
+	for i := 0; i < len(pairs); i += 2 {
+		tags = tags.AddTag(models.Tag{
+			Name:  []byte(pairs[i]),
+			Value: []byte(pairs[i+1]),
+		})
+	}
+
+	return tags
+}
+
+func staticQueryFunc(vector Vector, err error) QueryFunc {
+	return func(_ context.Context, _ string, t time.Time) (Vector, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(Vector, 0, len(vector))
+		for _, sample := range vector {
+			sample.Timestamp = t
+			result = append(result, sample)
+		}
+
+		return result, nil
+	}
+}
+
+func TestRecordingRuleEval(t *testing.T) {
+	rule := NewRecordingRule("job:up:sum", "sum(up) by (job)",
+		testTags("source", "rules"))
+	assert.Equal(t, RecordingRuleType, rule.Type())
+	assert.Equal(t, HealthUnknown, rule.Health())
+
+	now := time.Now()
+	query := staticQueryFunc(Vector{
+		{Tags: testTags("__name__", "up", "job", "a"), Value: 1},
+		{Tags: testTags("job", "b", "source", "other"), Value: 2},
+	}, nil)
+
+	vector, err := rule.Eval(context.Background(), now, query)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(vector))
+
+	for i, job := range []string{"a", "b"} {
+		name, ok := vector[i].Tags.Name()
+		require.True(t, ok)
+		assert.Equal(t, "job:up:sum", string(name))
+
+		value, ok := vector[i].Tags.Get([]byte("job"))
+		require.True(t, ok)
+		assert.Equal(t, job, string(value))
+
+		value, ok = vector[i].Tags.Get([]byte("source"))
+		require.True(t, ok)
+		assert.Equal(t, "rules", string(value))
+
+		assert.Equal(t, now, vector[i].Timestamp)
+	}
+
+	assert.Equal(t, float64(1), vector[0].Value)
+	assert.Equal(t, float64(2), vector[1].Value)
+}
+
+func TestRecordingRuleEvalDuplicateLabelset(t *testing.T) {
+	rule := NewRecordingRule("foo", "up", testTags("source", "rules"))
+	query := staticQueryFunc(Vector{
+		{Tags: testTags("source", "a"), Value: 1},
+		{Tags: testTags("source", "b"), Value: 2},
+	}, nil)
+
+	_, err := rule.Eval(context.Background(), time.Now(), query)
+	assert.Error(t, err)
+}
+
+func TestRecordingRuleEvalQueryError(t *testing.T) {
+	rule := NewRecordingRule("foo", "up", models.EmptyTags())
+	query := staticQueryFunc(nil, errors.New("query error"))
+
+	_, err := rule.Eval(context.Background(), time.Now(), query)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// ruleState tracks the health of a rule across evaluations.
+type ruleState struct {
+	sync.RWMutex
+	health    Health
+	lastError error
+}
+
+func newRuleState() ruleState {
+	return ruleState{health: HealthUnknown}
+}
+
+func (s *ruleState) Health() Health {
+	s.RLock()
+	defer s.RUnlock()
+	return s.health
+}
+
+func (s *ruleState) LastError() error {
+	s.RLock()
+	defer s.RUnlock()
+	return s.lastError
+}
+
+func (s *ruleState) SetHealth(health Health) {
+	s.Lock()
+	s.health = health
+	s.Unlock()
+}
+
+func (s *ruleState) SetLastError(err error) {
+	s.Lock()
+	s.lastError = err
+	s.Unlock()
+}
+
+// tagsFromMap creates tags from a map of names to values, sorted by name so
+// that the resulting tags are deterministic.
+func tagsFromMap(m map[string]string, tagOpts models.TagOptions) models.Tags {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	tags := models.NewTags(len(m), tagOpts)
+	for _, name := range names {
+		tags = tags.AddTag(models.Tag{
+			Name:  []byte(name),
+			Value: []byte(m[name]),
+		})
+	}
+
+	return tags
+}
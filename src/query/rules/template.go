@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// templateDefs provides the same variables available to Prometheus rule
+// templates, so that label and annotation templates can be shared.
+const templateDefs = "{{$labels := .Labels}}{{$value := .Value}}"
+
+type templateData struct {
+	Labels map[string]string
+	Value  float64
+}
+
+func newTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).
+		Option("missingkey=zero").
+		Parse(templateDefs + text)
+}
+
+func validateTemplate(name, text string) error {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+
+	if _, err := newTemplate(name, text); err != nil {
+		return fmt.Errorf("invalid template for %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// expandTemplate expands the template text using the tags and value of a
+// sample.
+func expandTemplate(
+	name string,
+	text string,
+	tags models.Tags,
+	value float64,
+) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := newTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData{
+		Labels: tagsToMap(tags),
+		Value:  value,
+	}); err != nil {
+		return "", fmt.Errorf("error expanding template %s: %v", name, err)
+	}
+
+	return buf.String(), nil
+}
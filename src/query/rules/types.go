@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package rules evaluates Prometheus-compatible recording and alerting rules
+// against the query engine.
+package rules
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+)
+
+// RuleType is the type of a rule.
+type RuleType string
+
+const (
+	// RecordingRuleType is a rule which writes its results back to storage.
+	RecordingRuleType RuleType = "recording"
+	// AlertingRuleType is a rule which generates alerts from its results.
+	AlertingRuleType RuleType = "alerting"
+)
+
+// Health describes the health of a rule after its last evaluation.
+type Health string
+
+const (
+	// HealthUnknown indicates the rule has not yet been evaluated.
+	HealthUnknown Health = "unknown"
+	// HealthGood indicates the last evaluation of the rule succeeded.
+	HealthGood Health = "ok"
+	// HealthBad indicates the last evaluation of the rule failed.
+	HealthBad Health = "err"
+)
+
+// AlertState is the state of an alert.
+type AlertState int
+
+const (
+	// StateInactive is the state of an alert that is neither firing nor pending.
+	StateInactive AlertState = iota
+	// StatePending is the state of an alert that has been active for less than
+	// the configured threshold duration.
+	StatePending
+	// StateFiring is the state of an alert that has been active for longer
+	// than the configured threshold duration.
+	StateFiring
+)
+
+// String returns the alert state as a string.
+func (s AlertState) String() string {
+	switch s {
+	case StateInactive:
+		return "inactive"
+	case StatePending:
+		return "pending"
+	case StateFiring:
+		return "firing"
+	}
+
+	return "unknown"
+}
+
+// Sample is a single instant value of a series.
+type Sample struct {
+	// Tags are the tags of the series.
+	Tags models.Tags
+	// Value is the value of the series.
+	Value float64
+	// Timestamp is the time the value was evaluated at.
+	Timestamp time.Time
+}
+
+// Vector is a set of samples evaluated at the same time.
+type Vector []Sample
+
+// QueryFunc evaluates an instant query at the given time.
+type QueryFunc func(ctx context.Context, query string, t time.Time) (Vector, error)
+
+// Rule is a rule which is evaluated on an interval by its group.
+type Rule interface {
+	// Name returns the name of the rule.
+	Name() string
+	// Type returns the type of the rule.
+	Type() RuleType
+	// Query returns the expression of the rule.
+	Query() string
+	// Labels returns the labels added to the results of the rule.
+	Labels() models.Tags
+	// Eval evaluates the rule at the given time.
+	Eval(ctx context.Context, t time.Time, query QueryFunc) (Vector, error)
+	// Health returns the health of the rule after its last evaluation.
+	Health() Health
+	// LastError returns the error of the last evaluation, if any.
+	LastError() error
+	// SetHealth sets the health of the rule.
+	SetHealth(health Health)
+	// SetLastError sets the error of the last evaluation.
+	SetLastError(err error)
+}
+
+// Alert is a single alert generated by an alerting rule.
+type Alert struct {
+	// State is the state of the alert.
+	State AlertState
+	// Labels are the labels identifying the alert.
+	Labels models.Tags
+	// Annotations are the expanded annotations of the alert.
+	Annotations models.Tags
+	// Value is the value of the sample that generated the alert.
+	Value float64
+	// ActiveAt is the time the alert became active.
+	ActiveAt time.Time
+	// FiredAt is the time the alert started firing.
+	FiredAt time.Time
+	// ResolvedAt is the time the alert was resolved.
+	ResolvedAt time.Time
+	// LastSentAt is the time the alert was last sent to the notifier.
+	LastSentAt time.Time
+	// ValidUntil is the time the alert is valid until, unless re-sent.
+	ValidUntil time.Time
+}
+
+// Notifier sends alerts to an external alert handler.
+type Notifier interface {
+	// Send sends the alerts.
+	Send(ctx context.Context, alerts []*Alert) error
+}
+
+// Manager loads rule groups and evaluates them on their intervals.
+type Manager interface {
+	// Start starts evaluating all rule groups.
+	Start() error
+	// Groups returns the loaded rule groups.
+	Groups() []*Group
+	// AlertingRules returns all loaded alerting rules.
+	AlertingRules() []*AlertingRule
+	// Close stops evaluation of all rule groups.
+	Close() error
+}
@@ -33,6 +33,7 @@ import (
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	ingestcarbon "github.com/m3db/m3/src/cmd/services/m3coordinator/ingest/carbon"
@@ -44,6 +45,7 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/httpd"
 	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/cache"
 	m3dbcluster "github.com/m3db/m3/src/query/cluster/m3db"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
@@ -288,6 +290,18 @@ func Run(runOpts RunOptions) {
 		SetGlobalEnforcer(perQueryEnforcer).
 		SetInstrumentOptions(instrumentOptions.
 			SetMetricsScope(instrumentOptions.MetricsScope().SubScope("engine")))
+	if shardCfg := cfg.QuerySharding; shardCfg != nil {
+		engineOpts = engineOpts.SetShardInterval(shardCfg.Interval)
+		if shardCfg.Concurrency > 0 {
+			engineOpts = engineOpts.SetShardConcurrency(shardCfg.Concurrency)
+		}
+		if m3dbClusters != nil {
+			engineOpts = engineOpts.SetShardBoundariesFn(
+				func(now, start, end time.Time) []time.Time {
+					return m3.RetentionBoundaries(now, start, end, m3dbClusters)
+				})
+		}
+	}
 	if fn := runOpts.CustomPromQLParseFunction; fn != nil {
 		engineOpts = engineOpts.
 			SetParseOptions(engineOpts.ParseOptions().SetParseFn(fn))
@@ -323,10 +337,70 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("unable to set up handler options", zap.Error(err))
 	}
 
+	if rulesCfg := cfg.Rules; rulesCfg != nil {
+		ruleManager, err := rulesCfg.NewManager(engine, backendStorage,
+			tagOptions, nil, instrumentOptions)
+		if err != nil {
+			logger.Fatal("unable to create rule manager", zap.Error(err))
+		}
+
+		if err := ruleManager.Start(); err != nil {
+			logger.Fatal("unable to start rule manager", zap.Error(err))
+		}
+
+		defer ruleManager.Close()
+		handlerOptions = handlerOptions.SetRuleManager(ruleManager)
+	}
+
+	if cacheCfg := cfg.ResultsCache; cacheCfg != nil {
+		var resolutionFn cache.ResolutionFn
+		if m3dbClusters != nil {
+			resolutionFn = newResultsCacheResolutionFn(m3dbClusters)
+		}
+
+		resultsCache, err := cacheCfg.NewResultsCache(resolutionFn, tagOptions,
+			instrumentOptions.SetMetricsScope(
+				instrumentOptions.MetricsScope().SubScope("results-cache")))
+		if err != nil {
+			logger.Fatal("unable to create results cache", zap.Error(err))
+		}
+
+		handlerOptions = handlerOptions.SetResultsCache(resultsCache)
+	}
+
+	if tenantCfg := cfg.Limits.PerTenant; tenantCfg != nil {
+		var kvStore kv.Store
+		if clusterClient != nil {
+			kvStore, err = clusterClient.KV()
+			if err != nil {
+				logger.Error("unable to get kv store for tenant limits, "+
+					"using configured limits", zap.Error(err))
+			}
+		}
+
+		tenantManager, err := tenantCfg.NewManager(kvStore, perQueryEnforcer,
+			instrumentOptions.SetMetricsScope(
+				instrumentOptions.MetricsScope().SubScope("tenant")))
+		if err != nil {
+			logger.Fatal("unable to create tenant manager", zap.Error(err))
+		}
+
+		defer tenantManager.Close()
+		handlerOptions = handlerOptions.SetTenantManager(tenantManager)
+	}
+
 	handler := httpd.NewHandler(handlerOptions, runOpts.CustomHandlers...)
 	if err := handler.RegisterRoutes(); err != nil {
 		logger.Fatal("unable to register routes", zap.Error(err))
 	}
+	if err := handler.Start(); err != nil {
+		logger.Error("unable to start handlers", zap.Error(err))
+	}
+	defer func() {
+		if err := handler.Close(); err != nil {
+			logger.Error("error closing handlers", zap.Error(err))
+		}
+	}()
 
 	listenAddress, err := cfg.ListenAddress.Resolve()
 	if err != nil {
@@ -400,6 +474,32 @@ func Run(runOpts RunOptions) {
 	})
 }
 
+// newResultsCacheResolutionFn returns a results cache resolution function
+// that describes the cluster namespaces a query range currently resolves to,
+// so that cached results are invalidated once a range is served from
+// namespaces with a different resolution or retention.
+func newResultsCacheResolutionFn(clusters m3.Clusters) cache.ResolutionFn {
+	return func(
+		now, start, end time.Time,
+		fetchOpts *storage.FetchOptions,
+	) (string, error) {
+		attrs, err := m3.ResolveClusterNamespaceAttributes(now, start, end,
+			clusters, fetchOpts)
+		if err != nil {
+			return "", err
+		}
+
+		keys := make([]string, 0, len(attrs))
+		for _, attr := range attrs {
+			keys = append(keys, fmt.Sprintf("%s:%s:%s", attr.MetricsType,
+				attr.Resolution, attr.Retention))
+		}
+
+		sort.Strings(keys)
+		return strings.Join(keys, ","), nil
+	}
+}
+
 // make connections to the m3db cluster(s) and generate sessions for those clusters along with the storage
 func newM3DBStorage(
 	cfg config.Configuration,
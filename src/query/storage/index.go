@@ -23,6 +23,7 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"regexp/syntax"
 
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/m3ninx/idx"
@@ -172,6 +173,8 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		)
 		if bytes.Equal(dotStar, matcher.Value) {
 			query = idx.NewFieldQuery(matcher.Name)
+		} else if prefix, ok := regexpPrefix(matcher.Value); ok {
+			query = idx.NewPrefixQuery(matcher.Name, prefix)
 		} else {
 			query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
 		}
@@ -212,3 +215,37 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		return idx.Query{}, fmt.Errorf("unsupported query type: %v", matcher)
 	}
 }
+
+// regexpPrefix returns the literal prefix of a regular expression of the
+// form `prefix.*`, which can be executed as a prefix query rather than
+// evaluating the regular expression against every term.
+// NB: `.` does not match newlines, this is ignored since label values with
+// newlines are not expected to be matched using these expressions.
+func regexpPrefix(re []byte) ([]byte, bool) {
+	parsed, err := syntax.Parse(string(re), syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+
+	parsed = parsed.Simplify()
+	if parsed.Op != syntax.OpConcat || len(parsed.Sub) != 2 {
+		return nil, false
+	}
+
+	literal, star := parsed.Sub[0], parsed.Sub[1]
+	if literal.Op != syntax.OpLiteral || literal.Flags&syntax.FoldCase != 0 {
+		return nil, false
+	}
+
+	if star.Op != syntax.OpStar || len(star.Sub) != 1 {
+		return nil, false
+	}
+
+	switch star.Sub[0].Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+	default:
+		return nil, false
+	}
+
+	return []byte(string(literal.Rune)), true
+}
@@ -120,6 +120,50 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "regexp match -> prefix",
+			expected: "prefix(t1, api-)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("api-.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match negated -> prefix",
+			expected: "negation(prefix(t1, api-))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("api-.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match with non-trailing wildcard",
+			expected: "regexp(t1, api-.*-v1)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("api-.*-v1"),
+				},
+			},
+		},
+		{
+			name:     "regexp match case insensitive prefix",
+			expected: "regexp(t1, (?i)api-.*)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("(?i)api-.*"),
+				},
+			},
+		},
 		{
 			name:     "regexp match -> field",
 			expected: "field(t1)",
@@ -358,3 +358,52 @@ func newCoversRangeFilter(opts coversRangeFilterOptions) func(namespace ClusterN
 		return !clusterStart.After(opts.queryStart)
 	}
 }
+
+// ResolveClusterNamespaceAttributes returns the storage attributes of the
+// cluster namespaces that a query over the given range would be fanned out to.
+func ResolveClusterNamespaceAttributes(
+	now, start, end time.Time,
+	clusters Clusters,
+	opts *storage.FetchOptions,
+) ([]storage.Attributes, error) {
+	_, namespaces, err := resolveClusterNamespacesForQuery(now, start, end,
+		clusters, opts.FanoutOptions, opts.RestrictQueryOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make([]storage.Attributes, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		attrs = append(attrs, namespace.Options().Attributes())
+	}
+
+	return attrs, nil
+}
+
+// RetentionBoundaries returns the times within the given range at which the
+// retention of a cluster namespace ends, which are the points at which the
+// namespaces a query resolves to may change.
+func RetentionBoundaries(now, start, end time.Time, clusters Clusters) []time.Time {
+	var (
+		boundaries []time.Time
+		seen       = make(map[time.Duration]struct{})
+	)
+	for _, namespace := range clusters.ClusterNamespaces() {
+		retention := namespace.Options().Attributes().Retention
+		if _, ok := seen[retention]; ok {
+			continue
+		}
+
+		seen[retention] = struct{}{}
+		boundary := now.Add(-1 * retention)
+		if boundary.After(start) && boundary.Before(end) {
+			boundaries = append(boundaries, boundary)
+		}
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	return boundaries
+}
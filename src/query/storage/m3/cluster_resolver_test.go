@@ -91,6 +91,51 @@ func TestFanoutUnaggregatedEnabledReturnsUnaggregatedNamespaces(t *testing.T) {
 	assert.Equal(t, "metrics_unaggregated", clusters[0].NamespaceID().String())
 }
 
+func TestResolveClusterNamespaceAttributes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s, _ := setup(t, ctrl)
+	store, ok := s.(*m3storage)
+	assert.True(t, ok)
+
+	now := time.Now()
+	fetchOpts := storage.NewFetchOptions()
+	attrs, err := ResolveClusterNamespaceAttributes(now,
+		now.Add(-time.Hour), now, store.clusters, fetchOpts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(attrs))
+	assert.Equal(t, storage.UnaggregatedMetricsType, attrs[0].MetricsType)
+
+	fetchOpts.FanoutOptions = &storage.FanoutOptions{
+		FanoutUnaggregated: storage.FanoutForceDisable,
+	}
+	attrs, err = ResolveClusterNamespaceAttributes(now,
+		now.Add(-time.Hour), now, store.clusters, fetchOpts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(attrs))
+	assert.Equal(t, storage.AggregatedMetricsType, attrs[0].MetricsType)
+	assert.Equal(t, time.Minute, attrs[0].Resolution)
+}
+
+func TestRetentionBoundaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s, _ := setup(t, ctrl)
+	store, ok := s.(*m3storage)
+	assert.True(t, ok)
+
+	now := time.Now()
+	boundaries := RetentionBoundaries(now, now.Add(-100*24*time.Hour), now,
+		store.clusters)
+	require.Equal(t, 2, len(boundaries))
+	assert.Equal(t, now.Add(-1*test3MonthRetention), boundaries[0])
+	assert.Equal(t, now.Add(-1*test1MonthRetention), boundaries[1])
+
+	boundaries = RetentionBoundaries(now, now.Add(-time.Hour), now,
+		store.clusters)
+	assert.Equal(t, 0, len(boundaries))
+}
+
 func TestGraphitePath(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -127,6 +127,11 @@ type FetchOptions struct {
 	// IncludeResolution if set, appends resolution information to fetch results.
 	// Currently only used for graphite queries.
 	IncludeResolution bool
+	// Tenant is the tenant that issued the fetch, if known.
+	Tenant string
+	// TenantLimited is set if Limit was lowered to the series limit of the
+	// tenant that issued the fetch.
+	TenantLimited bool
 }
 
 // FanoutOptions describes which namespaces should be fanned out to for
@@ -178,6 +178,22 @@ func (s *AsyncSession) Aggregate(namespace ident.ID, q index.Query, opts index.A
 	return s.session.Aggregate(namespace, q, opts)
 }
 
+// DeleteSeries resolves the provided query to known IDs and deletes the
+// data for them within the given time range.
+func (s *AsyncSession) DeleteSeries(
+	namespace ident.ID,
+	q index.Query,
+	startInclusive, endExclusive time.Time,
+) (int64, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	return s.session.DeleteSeries(namespace, q, startInclusive, endExclusive)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
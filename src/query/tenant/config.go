@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Configuration is the configuration for per-tenant limits.
+type Configuration struct {
+	// TenantHeader is the header the tenant of a request is taken from,
+	// defaults to M3-Tenant.
+	TenantHeader string `yaml:"tenantHeader"`
+
+	// TenantFromBasicAuth takes the tenant of a request from its basic auth
+	// username rather than the tenant header. The password is not verified,
+	// the username only identifies the tenant.
+	TenantFromBasicAuth bool `yaml:"tenantFromBasicAuth"`
+
+	// DefaultTenant is the tenant of requests that do not specify one.
+	DefaultTenant string `yaml:"defaultTenant"`
+
+	// TenantIdleTimeout is how long a tenant without running queries is
+	// tracked after its last request, defaults to 10 minutes.
+	TenantIdleTimeout *time.Duration `yaml:"tenantIdleTimeout"`
+
+	// KVKey is the KV key the tenant limits are watched at.
+	KVKey string `yaml:"kvKey"`
+
+	// Limits are the tenant limits used when none are set in KV.
+	Limits TenantLimits `yaml:"limits"`
+}
+
+// NewManager creates a new tenant manager from the configuration. If the KV
+// store is nil the configured limits are always used.
+func (c Configuration) NewManager(
+	store kv.Store,
+	globalEnforcer qcost.ChainedEnforcer,
+	instrumentOpts instrument.Options,
+) (Manager, error) {
+	opts := NewOptions().
+		SetKVStore(store).
+		SetDefaultLimits(c.Limits).
+		SetTenantFromBasicAuth(c.TenantFromBasicAuth).
+		SetGlobalEnforcer(globalEnforcer).
+		SetInstrumentOptions(instrumentOpts)
+	if c.TenantHeader != "" {
+		opts = opts.SetTenantHeader(c.TenantHeader)
+	}
+	if c.DefaultTenant != "" {
+		opts = opts.SetDefaultTenant(c.DefaultTenant)
+	}
+	if c.KVKey != "" {
+		opts = opts.SetKVKey(c.KVKey)
+	}
+	if c.TenantIdleTimeout != nil {
+		opts = opts.SetTenantIdleTimeout(*c.TenantIdleTimeout)
+	}
+
+	return NewManager(opts)
+}
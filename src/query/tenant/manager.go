@@ -0,0 +1,409 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/util"
+	"github.com/m3db/m3/src/query/block"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/cost"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/uber-go/tally"
+)
+
+type managerMetrics struct {
+	rejectedQueries tally.Counter
+	rejectedWrites  tally.Counter
+}
+
+func newManagerMetrics(scope tally.Scope) managerMetrics {
+	return managerMetrics{
+		rejectedQueries: scope.Tagged(map[string]string{
+			"limit": "max_concurrent_queries",
+		}).Counter("rejected"),
+		rejectedWrites: scope.Tagged(map[string]string{
+			"limit": "max_write_datapoints_per_second",
+		}).Counter("rejected"),
+	}
+}
+
+type manager struct {
+	sync.RWMutex
+
+	opts        Options
+	nowFn       clock.NowFn
+	limits      TenantLimits
+	tenants     map[string]*tenantState
+	lastEvictAt time.Time
+	watch       kv.ValueWatch
+	metrics     managerMetrics
+}
+
+// NewManager creates a new tenant manager, watching the tenant limits in KV
+// if a KV store is set.
+func NewManager(opts Options) (Manager, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	m := &manager{
+		opts:    opts,
+		nowFn:   opts.ClockOptions().NowFn(),
+		limits:  opts.DefaultLimits(),
+		tenants: make(map[string]*tenantState),
+		metrics: newManagerMetrics(opts.InstrumentOptions().MetricsScope()),
+	}
+
+	store := opts.KVStore()
+	if store == nil {
+		return m, nil
+	}
+
+	watchOpts := util.NewOptions().
+		SetLogger(opts.InstrumentOptions().Logger())
+	watch, err := util.WatchAndUpdateGeneric(store, opts.KVKey(),
+		tenantLimitsFromValue, m.updateLimits, m, opts.DefaultLimits(),
+		watchOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to watch key '%s': %v", opts.KVKey(), err)
+	}
+
+	m.watch = watch
+	return m, nil
+}
+
+// tenantLimitsFromValue decodes tenant limits from a KV value, which holds
+// them as a JSON string.
+func tenantLimitsFromValue(v kv.Value) (interface{}, error) {
+	var str commonpb.StringProto
+	if err := v.Unmarshal(&str); err != nil {
+		return nil, err
+	}
+
+	var limits TenantLimits
+	if err := json.Unmarshal([]byte(str.Value), &limits); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// updateLimits applies an update to the tenant limits, it is called with the
+// manager lock held.
+func (m *manager) updateLimits(value interface{}) {
+	m.limits = value.(TenantLimits)
+}
+
+func (m *manager) Tenant(r *http.Request) string {
+	if m.opts.TenantFromBasicAuth() {
+		if username, _, ok := r.BasicAuth(); ok && username != "" {
+			return username
+		}
+
+		return m.opts.DefaultTenant()
+	}
+
+	if tenant := r.Header.Get(m.opts.TenantHeader()); tenant != "" {
+		return tenant
+	}
+
+	return m.opts.DefaultTenant()
+}
+
+func (m *manager) Limits(tenant string) Limits {
+	m.RLock()
+	limits := m.limits.Limits(tenant)
+	m.RUnlock()
+	return limits
+}
+
+func (m *manager) StartQuery(
+	tenant string,
+) (qcost.ChainedEnforcer, ReleaseFn, error) {
+	limit := m.Limits(tenant).MaxConcurrentQueries
+	state := m.acquire(tenant)
+	defer state.Unlock()
+	if limit > 0 && state.queries >= limit {
+		m.metrics.rejectedQueries.Inc(1)
+		return nil, nil, newLimitError(tenant, "maxConcurrentQueries")
+	}
+
+	state.queries++
+	var once sync.Once
+	return state.enforcer, func() {
+		once.Do(func() {
+			state.Lock()
+			state.queries--
+			state.lastUsedAt = m.nowFn()
+			state.Unlock()
+		})
+	}, nil, nil
+}
+
+func (m *manager) AllowWrite(tenant string, datapoints int) error {
+	rate := m.Limits(tenant).MaxWriteDatapointsPerSecond
+	if rate <= 0 {
+		return nil
+	}
+
+	state := m.acquire(tenant)
+	allowed := state.allowWrite(state.lastUsedAt, rate, datapoints)
+	state.Unlock()
+	if !allowed {
+		m.metrics.rejectedWrites.Inc(1)
+		return newLimitError(tenant, "maxWriteDatapointsPerSecond")
+	}
+
+	return nil
+}
+
+func (m *manager) Close() {
+	if m.watch != nil {
+		m.watch.Close()
+	}
+}
+
+// acquire returns the locked state of a tenant, marking the tenant as used.
+func (m *manager) acquire(tenant string) *tenantState {
+	for {
+		state := m.state(tenant)
+		state.Lock()
+		if !state.evicted {
+			state.lastUsedAt = m.nowFn()
+			return state
+		}
+
+		// NB: the state was evicted after it was looked up, retry with the
+		// state that replaces it.
+		state.Unlock()
+	}
+}
+
+// state returns the state of a tenant, creating it on first use.
+func (m *manager) state(tenant string) *tenantState {
+	m.RLock()
+	state, ok := m.tenants[tenant]
+	m.RUnlock()
+	if ok {
+		return state
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if state, ok := m.tenants[tenant]; ok {
+		return state
+	}
+
+	now := m.nowFn()
+	m.evictIdleWithLock(now)
+	local := cost.NewEnforcer(
+		&tenantLimitManager{manager: m, tenant: tenant},
+		cost.NewTracker(),
+		cost.NewEnforcerOptions().SetCostExceededMessage(
+			fmt.Sprintf("limits.tenants[%s].maxFetchedDatapoints exceeded", tenant)),
+	)
+
+	state = &tenantState{
+		lastUsedAt: now,
+		enforcer: m.opts.GlobalEnforcer().
+			ChildWithEnforcer(qcost.TenantLevel, local),
+	}
+	m.tenants[tenant] = state
+	return state
+}
+
+// evictIdleWithLock stops tracking tenants that have no running queries and
+// have not been used for the idle timeout, so that the state of tenants that
+// have gone away does not accumulate. It runs at most once per idle timeout
+// and is called with the manager lock held when a new tenant is added.
+func (m *manager) evictIdleWithLock(now time.Time) {
+	timeout := m.opts.TenantIdleTimeout()
+	if timeout <= 0 || now.Sub(m.lastEvictAt) < timeout {
+		return
+	}
+
+	m.lastEvictAt = now
+	for tenant, state := range m.tenants {
+		state.Lock()
+		if state.queries == 0 && now.Sub(state.lastUsedAt) >= timeout {
+			state.evicted = true
+			delete(m.tenants, tenant)
+		}
+		state.Unlock()
+	}
+}
+
+// tenantState is the resource usage of a tenant.
+type tenantState struct {
+	sync.Mutex
+
+	queries     int
+	writeTokens float64
+	lastWriteAt time.Time
+	lastUsedAt  time.Time
+	evicted     bool
+	enforcer    qcost.ChainedEnforcer
+}
+
+// allowWrite refills the write token bucket of the tenant, which holds at
+// most a second of writes, and takes the given number of datapoints from it.
+// A write is allowed while any tokens remain so that writes larger than the
+// bucket can proceed, borrowing from future refills. It is called with the
+// state lock held.
+func (s *tenantState) allowWrite(now time.Time, rate float64, datapoints int) bool {
+	if s.lastWriteAt.IsZero() {
+		s.writeTokens = rate
+	} else {
+		s.writeTokens += now.Sub(s.lastWriteAt).Seconds() * rate
+		if s.writeTokens > rate {
+			s.writeTokens = rate
+		}
+	}
+
+	s.lastWriteAt = now
+	if s.writeTokens <= 0 {
+		return false
+	}
+
+	s.writeTokens -= float64(datapoints)
+	return true
+}
+
+// tenantLimitManager is the limit manager of the datapoints fetched by the
+// queries of a tenant, reflecting the current limits of the tenant.
+type tenantLimitManager struct {
+	manager *manager
+	tenant  string
+}
+
+func (l *tenantLimitManager) Limit() cost.Limit {
+	max := l.manager.Limits(l.tenant).MaxFetchedDatapoints
+	return cost.Limit{
+		Threshold: cost.Cost(max),
+		Enabled:   max > 0,
+	}
+}
+
+func (l *tenantLimitManager) Report() {}
+
+func (l *tenantLimitManager) Close() {}
+
+// limitError is the error returned when a tenant exceeds one of its limits.
+type limitError struct {
+	tenant string
+	limit  string
+}
+
+func newLimitError(tenant, limit string) error {
+	return limitError{tenant: tenant, limit: limit}
+}
+
+func (e limitError) Error() string {
+	return fmt.Sprintf("tenant %s exceeded %s limit", e.tenant, e.limit)
+}
+
+// IsLimitError returns true if the error is the result of a tenant exceeding
+// one of its limits.
+func IsLimitError(err error) bool {
+	for err != nil {
+		if _, ok := err.(limitError); ok {
+			return true
+		}
+
+		if resource, ok := qcost.ExceededLimitResource(err); ok {
+			return resource == qcost.TenantLevel
+		}
+
+		err = xerrors.InnerError(err)
+	}
+
+	return false
+}
+
+// AdmitQuery admits a query from the tenant that issued the request, applying
+// the tenant's series limit to the fetch options so that the fetch stops once
+// the limit is reached; CheckFetchedSeries must be called with the results to
+// reject a query that was truncated by the limit. It returns the enforcer the
+// query's enforcer should roll up into and a function that must be called
+// once the query completes. If the manager is nil the query is always
+// admitted with a nil enforcer.
+func AdmitQuery(
+	m Manager,
+	r *http.Request,
+	fetchOpts *storage.FetchOptions,
+) (qcost.ChainedEnforcer, ReleaseFn, error) {
+	if m == nil {
+		return nil, func() {}, nil
+	}
+
+	tenant := m.Tenant(r)
+	enforcer, release, err := m.StartQuery(tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+	fetchOpts.Tenant = tenant
+
+	limit := m.Limits(tenant).MaxFetchedSeries
+	if limit > 0 && (fetchOpts.Limit <= 0 || fetchOpts.Limit > limit) {
+		fetchOpts.Limit = limit
+		fetchOpts.TenantLimited = true
+	}
+
+	return enforcer, release, nil
+}
+
+// CheckFetchedSeries returns a limit error if the results of a query admitted
+// by AdmitQuery were truncated by the series limit of its tenant, rather than
+// returning partial results the tenant did not ask for. If the manager is nil
+// the results are always accepted.
+func CheckFetchedSeries(
+	m Manager,
+	fetchOpts *storage.FetchOptions,
+	meta block.ResultMetadata,
+) error {
+	if m == nil || meta.Exhaustive || !fetchOpts.TenantLimited {
+		return nil
+	}
+
+	return newLimitError(fetchOpts.Tenant, "maxFetchedSeries")
+}
+
+// AdmitWrite returns a limit error if writing the given number of datapoints
+// would exceed the write rate of the tenant that issued the request. If the
+// manager is nil the write is always admitted.
+func AdmitWrite(m Manager, r *http.Request, datapoints int) error {
+	if m == nil {
+		return nil
+	}
+
+	return m.AllowWrite(m.Tenant(r), datapoints)
+}
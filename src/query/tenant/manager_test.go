@@ -0,0 +1,289 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/block"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/cost"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, opts Options) *manager {
+	m, err := NewManager(opts)
+	require.NoError(t, err)
+	return m.(*manager)
+}
+
+func newTestGlobalEnforcer(t *testing.T) qcost.ChainedEnforcer {
+	newEnforcer := func() cost.Enforcer {
+		return cost.NewEnforcer(
+			cost.NewStaticLimitManager(cost.NewLimitManagerOptions()),
+			cost.NewTracker(), nil)
+	}
+
+	enforcer, err := qcost.NewChainedEnforcer(qcost.GlobalLevel,
+		[]cost.Enforcer{newEnforcer(), newEnforcer(), newEnforcer()})
+	require.NoError(t, err)
+	return enforcer
+}
+
+func TestManagerTenant(t *testing.T) {
+	m := newTestManager(t, NewOptions())
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, "default", m.Tenant(r))
+
+	r.Header.Set(DefaultTenantHeader, "foo")
+	assert.Equal(t, "foo", m.Tenant(r))
+
+	m = newTestManager(t, NewOptions().SetTenantFromBasicAuth(true))
+	assert.Equal(t, "default", m.Tenant(r))
+
+	r.SetBasicAuth("bar", "secret")
+	assert.Equal(t, "bar", m.Tenant(r))
+}
+
+func TestManagerLimits(t *testing.T) {
+	limits := TenantLimits{
+		Default: Limits{MaxConcurrentQueries: 1},
+		Tenants: map[string]Limits{
+			"foo": {MaxConcurrentQueries: 2},
+		},
+	}
+
+	m := newTestManager(t, NewOptions().SetDefaultLimits(limits))
+	assert.Equal(t, 2, m.Limits("foo").MaxConcurrentQueries)
+	assert.Equal(t, 1, m.Limits("bar").MaxConcurrentQueries)
+}
+
+func TestManagerWatchesKV(t *testing.T) {
+	store := mem.NewStore()
+	m := newTestManager(t, NewOptions().
+		SetKVStore(store).
+		SetDefaultLimits(TenantLimits{
+			Default: Limits{MaxFetchedSeries: 10},
+		}))
+	defer m.Close()
+
+	assert.Equal(t, 10, m.Limits("foo").MaxFetchedSeries)
+
+	_, err := store.Set(m.opts.KVKey(), &commonpb.StringProto{
+		Value: `{"default":{"maxFetchedSeries":20},"tenants":{"foo":{"maxFetchedSeries":30}}}`,
+	})
+	require.NoError(t, err)
+
+	require.True(t, clock.WaitUntil(func() bool {
+		return m.Limits("foo").MaxFetchedSeries == 30
+	}, 5*time.Second))
+	assert.Equal(t, 20, m.Limits("bar").MaxFetchedSeries)
+}
+
+func TestManagerStartQuery(t *testing.T) {
+	m := newTestManager(t, NewOptions().SetDefaultLimits(TenantLimits{
+		Default: Limits{MaxConcurrentQueries: 1},
+	}))
+
+	_, release, err := m.StartQuery("foo")
+	require.NoError(t, err)
+
+	_, _, err = m.StartQuery("foo")
+	require.Error(t, err)
+	assert.True(t, IsLimitError(err))
+
+	// Other tenants are not affected.
+	_, releaseOther, err := m.StartQuery("bar")
+	require.NoError(t, err)
+	releaseOther()
+
+	release()
+	release()
+
+	_, release, err = m.StartQuery("foo")
+	require.NoError(t, err)
+	release()
+}
+
+func TestManagerAllowWrite(t *testing.T) {
+	now := time.Now()
+	m := newTestManager(t, NewOptions().
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return now
+		})).
+		SetDefaultLimits(TenantLimits{
+			Default: Limits{MaxWriteDatapointsPerSecond: 10},
+		}))
+
+	require.NoError(t, m.AllowWrite("foo", 5))
+	require.NoError(t, m.AllowWrite("foo", 5))
+
+	err := m.AllowWrite("foo", 1)
+	require.Error(t, err)
+	assert.True(t, IsLimitError(err))
+
+	// Writes larger than the bucket are allowed while tokens remain.
+	now = now.Add(500 * time.Millisecond)
+	require.NoError(t, m.AllowWrite("foo", 20))
+	require.Error(t, m.AllowWrite("foo", 1))
+
+	now = now.Add(2 * time.Second)
+	require.NoError(t, m.AllowWrite("foo", 1))
+}
+
+func TestManagerEvictsIdleTenants(t *testing.T) {
+	now := time.Now()
+	m := newTestManager(t, NewOptions().
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time {
+			return now
+		})).
+		SetTenantIdleTimeout(time.Minute).
+		SetDefaultLimits(TenantLimits{
+			Default: Limits{MaxConcurrentQueries: 1},
+		}))
+
+	_, release, err := m.StartQuery("foo")
+	require.NoError(t, err)
+	_, releaseIdle, err := m.StartQuery("bar")
+	require.NoError(t, err)
+	releaseIdle()
+
+	// Tenants with running queries are never evicted.
+	now = now.Add(2 * time.Minute)
+	_, releaseNew, err := m.StartQuery("baz")
+	require.NoError(t, err)
+	releaseNew()
+	assert.Len(t, m.tenants, 2)
+	assert.Contains(t, m.tenants, "foo")
+
+	_, _, err = m.StartQuery("foo")
+	assert.True(t, IsLimitError(err))
+
+	release()
+	now = now.Add(2 * time.Minute)
+	_, releaseNew, err = m.StartQuery("qux")
+	require.NoError(t, err)
+	releaseNew()
+	assert.Len(t, m.tenants, 1)
+	assert.Contains(t, m.tenants, "qux")
+}
+
+func TestManagerEnforcer(t *testing.T) {
+	global := newTestGlobalEnforcer(t)
+	m := newTestManager(t, NewOptions().
+		SetGlobalEnforcer(global).
+		SetDefaultLimits(TenantLimits{
+			Default: Limits{MaxFetchedDatapoints: 10},
+		}))
+
+	enforcer, release, err := m.StartQuery("foo")
+	require.NoError(t, err)
+	defer release()
+
+	otherEnforcer, releaseOther, err := m.StartQuery("foo")
+	require.NoError(t, err)
+	defer releaseOther()
+	assert.Equal(t, enforcer, otherEnforcer)
+
+	query := enforcer.Child(qcost.QueryLevel)
+	require.NoError(t, query.Add(6).Error)
+
+	other := otherEnforcer.Child(qcost.QueryLevel)
+	r := other.Add(6)
+	require.Error(t, r.Error)
+	assert.True(t, IsLimitError(r.Error))
+
+	query.Close()
+	other.Close()
+	r, _ = global.State()
+	assert.Equal(t, cost.Cost(0), r.Cost)
+}
+
+func TestIsLimitError(t *testing.T) {
+	assert.False(t, IsLimitError(nil))
+	assert.False(t, IsLimitError(errors.New("foo")))
+	assert.True(t, IsLimitError(newLimitError("foo", "bar")))
+}
+
+func TestAdmitQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	fetchOpts := storage.NewFetchOptions()
+	fetchOpts.Limit = 100
+
+	enforcer, release, err := AdmitQuery(nil, r, fetchOpts)
+	require.NoError(t, err)
+	assert.Nil(t, enforcer)
+	release()
+	assert.Equal(t, 100, fetchOpts.Limit)
+	assert.False(t, fetchOpts.TenantLimited)
+
+	m := newTestManager(t, NewOptions().SetDefaultLimits(TenantLimits{
+		Default: Limits{MaxFetchedSeries: 10, MaxConcurrentQueries: 1},
+	}))
+
+	enforcer, release, err = AdmitQuery(m, r, fetchOpts)
+	require.NoError(t, err)
+	assert.NotNil(t, enforcer)
+	assert.Equal(t, 10, fetchOpts.Limit)
+	assert.True(t, fetchOpts.TenantLimited)
+	assert.Equal(t, m.Tenant(r), fetchOpts.Tenant)
+
+	_, _, err = AdmitQuery(m, r, fetchOpts)
+	assert.True(t, IsLimitError(err))
+	release()
+}
+
+func TestCheckFetchedSeries(t *testing.T) {
+	truncated := block.NewResultMetadata()
+	truncated.Exhaustive = false
+
+	fetchOpts := storage.NewFetchOptions()
+	fetchOpts.Limit = 10
+	fetchOpts.TenantLimited = true
+	require.NoError(t, CheckFetchedSeries(nil, fetchOpts, truncated))
+
+	m := newTestManager(t, NewOptions().SetDefaultLimits(TenantLimits{
+		Default: Limits{MaxFetchedSeries: 10},
+	}))
+
+	fetchOpts.Tenant = "foo"
+	require.NoError(t, CheckFetchedSeries(m, fetchOpts, block.NewResultMetadata()))
+	err := CheckFetchedSeries(m, fetchOpts, truncated)
+	require.Error(t, err)
+	assert.True(t, IsLimitError(err))
+
+	// Results truncated by a limit of the request itself that the tenant
+	// limit did not lower are partial results rather than errors, even if
+	// the limits are equal.
+	fetchOpts.TenantLimited = false
+	require.NoError(t, CheckFetchedSeries(m, fetchOpts, truncated))
+}
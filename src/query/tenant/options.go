@@ -0,0 +1,196 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	// DefaultTenantHeader is the default header the tenant of a request is
+	// taken from.
+	DefaultTenantHeader = "M3-Tenant"
+
+	defaultKVKey         = "m3query.limits.tenants"
+	defaultDefaultTenant = "default"
+	defaultIdleTimeout   = 10 * time.Minute
+)
+
+var (
+	errNoKVKey          = errors.New("no kv key set")
+	errNoTenantHeader   = errors.New("no tenant header set")
+	errNoDefaultTenant  = errors.New("no default tenant set")
+	errNoGlobalEnforcer = errors.New("no global enforcer set")
+	errIdleTimeout      = errors.New("tenant idle timeout must be zero or at least a second")
+)
+
+type options struct {
+	kvStore             kv.Store
+	kvKey               string
+	defaultLimits       TenantLimits
+	tenantHeader        string
+	tenantFromBasicAuth bool
+	defaultTenant       string
+	idleTimeout         time.Duration
+	globalEnforcer      qcost.ChainedEnforcer
+	clockOpts           clock.Options
+	instrumentOpts      instrument.Options
+}
+
+// NewOptions returns a new set of tenant manager options.
+func NewOptions() Options {
+	return &options{
+		kvKey:          defaultKVKey,
+		tenantHeader:   DefaultTenantHeader,
+		defaultTenant:  defaultDefaultTenant,
+		idleTimeout:    defaultIdleTimeout,
+		globalEnforcer: qcost.NoopChainedEnforcer(),
+		clockOpts:      clock.NewOptions(),
+		instrumentOpts: instrument.NewOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.kvKey == "" {
+		return errNoKVKey
+	}
+	if o.tenantHeader == "" {
+		return errNoTenantHeader
+	}
+	if o.defaultTenant == "" {
+		return errNoDefaultTenant
+	}
+	if o.globalEnforcer == nil {
+		return errNoGlobalEnforcer
+	}
+	// NB: tenants are only evicted once their write token bucket has fully
+	// refilled, so that evicting a tenant never resets its write rate.
+	if o.idleTimeout < 0 || (o.idleTimeout > 0 && o.idleTimeout < time.Second) {
+		return errIdleTimeout
+	}
+	return nil
+}
+
+func (o *options) SetKVStore(value kv.Store) Options {
+	opts := *o
+	opts.kvStore = value
+	return &opts
+}
+
+func (o *options) KVStore() kv.Store {
+	return o.kvStore
+}
+
+func (o *options) SetKVKey(value string) Options {
+	opts := *o
+	opts.kvKey = value
+	return &opts
+}
+
+func (o *options) KVKey() string {
+	return o.kvKey
+}
+
+func (o *options) SetDefaultLimits(value TenantLimits) Options {
+	opts := *o
+	opts.defaultLimits = value
+	return &opts
+}
+
+func (o *options) DefaultLimits() TenantLimits {
+	return o.defaultLimits
+}
+
+func (o *options) SetTenantHeader(value string) Options {
+	opts := *o
+	opts.tenantHeader = value
+	return &opts
+}
+
+func (o *options) TenantHeader() string {
+	return o.tenantHeader
+}
+
+func (o *options) SetTenantFromBasicAuth(value bool) Options {
+	opts := *o
+	opts.tenantFromBasicAuth = value
+	return &opts
+}
+
+func (o *options) TenantFromBasicAuth() bool {
+	return o.tenantFromBasicAuth
+}
+
+func (o *options) SetDefaultTenant(value string) Options {
+	opts := *o
+	opts.defaultTenant = value
+	return &opts
+}
+
+func (o *options) DefaultTenant() string {
+	return o.defaultTenant
+}
+
+func (o *options) SetTenantIdleTimeout(value time.Duration) Options {
+	opts := *o
+	opts.idleTimeout = value
+	return &opts
+}
+
+func (o *options) TenantIdleTimeout() time.Duration {
+	return o.idleTimeout
+}
+
+func (o *options) SetGlobalEnforcer(value qcost.ChainedEnforcer) Options {
+	opts := *o
+	opts.globalEnforcer = value
+	return &opts
+}
+
+func (o *options) GlobalEnforcer() qcost.ChainedEnforcer {
+	return o.globalEnforcer
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
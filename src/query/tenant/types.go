@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package tenant provides limits on the resources used by each tenant of a
+// shared coordinator.
+package tenant
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	qcost "github.com/m3db/m3/src/query/cost"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Limits are the resource limits of a tenant. Zero or negative values imply
+// no limit.
+type Limits struct {
+	// MaxWriteDatapointsPerSecond limits the rate at which the tenant writes
+	// datapoints.
+	MaxWriteDatapointsPerSecond float64 `json:"maxWriteDatapointsPerSecond" yaml:"maxWriteDatapointsPerSecond"`
+
+	// MaxFetchedSeries limits the number of series fetched by each query of
+	// the tenant.
+	MaxFetchedSeries int `json:"maxFetchedSeries" yaml:"maxFetchedSeries"`
+
+	// MaxFetchedDatapoints limits the number of datapoints fetched by all of
+	// the queries of the tenant at any given time.
+	MaxFetchedDatapoints int64 `json:"maxFetchedDatapoints" yaml:"maxFetchedDatapoints"`
+
+	// MaxConcurrentQueries limits the number of queries of the tenant that
+	// run concurrently.
+	MaxConcurrentQueries int `json:"maxConcurrentQueries" yaml:"maxConcurrentQueries"`
+}
+
+// TenantLimits are the limits of all tenants, stored in KV as JSON so that
+// they can be changed at runtime.
+type TenantLimits struct {
+	// Default are the limits of tenants without their own limits.
+	Default Limits `json:"default" yaml:"default"`
+
+	// Tenants are the limits of individual tenants.
+	Tenants map[string]Limits `json:"tenants" yaml:"tenants"`
+}
+
+// Limits returns the limits of a tenant.
+func (l TenantLimits) Limits(tenant string) Limits {
+	if limits, ok := l.Tenants[tenant]; ok {
+		return limits
+	}
+
+	return l.Default
+}
+
+// ReleaseFn releases the resources held by a query.
+type ReleaseFn func()
+
+// Manager enforces the limits of each tenant.
+type Manager interface {
+	// Tenant returns the tenant that issued a request. The tenant is only
+	// used to identify the request and is not authenticated.
+	Tenant(r *http.Request) string
+
+	// Limits returns the current limits of a tenant.
+	Limits(tenant string) Limits
+
+	// StartQuery admits a query of the tenant, returning a limit error if the
+	// tenant is already running its maximum number of concurrent queries.
+	// It returns the enforcer of the datapoints fetched by the queries of the
+	// tenant and a function that must be called once the query completes.
+	StartQuery(tenant string) (qcost.ChainedEnforcer, ReleaseFn, error)
+
+	// AllowWrite returns a limit error if writing the given number of
+	// datapoints would exceed the tenant's write rate.
+	AllowWrite(tenant string, datapoints int) error
+
+	// Close closes the manager.
+	Close()
+}
+
+// Options are the options for the tenant manager.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetKVStore sets the KV store the tenant limits are watched in, if not
+	// set the default limits are always used.
+	SetKVStore(value kv.Store) Options
+
+	// KVStore returns the KV store the tenant limits are watched in.
+	KVStore() kv.Store
+
+	// SetKVKey sets the KV key of the tenant limits.
+	SetKVKey(value string) Options
+
+	// KVKey returns the KV key of the tenant limits.
+	KVKey() string
+
+	// SetDefaultLimits sets the tenant limits used when none are set in KV.
+	SetDefaultLimits(value TenantLimits) Options
+
+	// DefaultLimits returns the tenant limits used when none are set in KV.
+	DefaultLimits() TenantLimits
+
+	// SetTenantHeader sets the header the tenant of a request is taken from.
+	SetTenantHeader(value string) Options
+
+	// TenantHeader returns the header the tenant of a request is taken from.
+	TenantHeader() string
+
+	// SetTenantFromBasicAuth sets whether the tenant of a request is taken
+	// from its basic auth username rather than the tenant header. The basic
+	// auth password is not verified, so this only identifies the tenant and
+	// must be paired with authentication in front of the coordinator.
+	SetTenantFromBasicAuth(value bool) Options
+
+	// TenantFromBasicAuth returns whether the tenant of a request is taken
+	// from its basic auth username rather than the tenant header.
+	TenantFromBasicAuth() bool
+
+	// SetDefaultTenant sets the tenant of requests that do not specify one.
+	SetDefaultTenant(value string) Options
+
+	// DefaultTenant returns the tenant of requests that do not specify one.
+	DefaultTenant() string
+
+	// SetTenantIdleTimeout sets how long a tenant without running queries
+	// is tracked after its last request, zero tracks tenants indefinitely.
+	SetTenantIdleTimeout(value time.Duration) Options
+
+	// TenantIdleTimeout returns how long a tenant without running queries
+	// is tracked after its last request.
+	TenantIdleTimeout() time.Duration
+
+	// SetGlobalEnforcer sets the global enforcer the tenant enforcers roll
+	// up into.
+	SetGlobalEnforcer(value qcost.ChainedEnforcer) Options
+
+	// GlobalEnforcer returns the global enforcer the tenant enforcers roll
+	// up into.
+	GlobalEnforcer() qcost.ChainedEnforcer
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}